@@ -4,6 +4,7 @@ WHITESPACE: [ \r\n\t]+ -> skip;
 DIGITS: '0' | [1-9] [0-9]*;
 HEX_DIGITS: ('a' .. 'f' | 'A' .. 'F' | [0-9])+;
 NET: DIGITS '.' DIGITS '.' DIGITS '.' DIGITS '/' DIGITS;
+NET6: [0-9a-fA-F]* ':' [0-9a-fA-F:.]* '/' DIGITS;
 
 ANY: 'ANY' | 'any';
 ALL: 'ALL' | 'all';
@@ -16,8 +17,12 @@ TOS: 'TOS' | 'tos';
 PROTOCOL: 'PROTOCOL' | 'protocol';
 SRCPORT: 'SRCPORT' | 'srcport';
 DSTPORT: 'DSTPORT' | 'dstport';
+TC: 'TC' | 'tc';
+FLOWLABEL: 'FLOWLABEL' | 'flowlabel';
+NEXTHDR: 'NEXTHDR' | 'nexthdr';
+ICMPTYPE: 'ICMPTYPE' | 'icmptype';
 
-STRING: [a-zA-Z]+;
+STRING: [a-zA-Z] [a-zA-Z0-9]*;
 
 matchSrc: SRC '=' NET;
 matchDst: DST '=' NET;
@@ -25,11 +30,19 @@ matchDSCP: DSCP '=0x' (HEX_DIGITS | DIGITS);
 matchTOS: TOS '=0x' (HEX_DIGITS | DIGITS);
 matchProtocol: PROTOCOL '=' STRING;
 
+matchSrcIPv6: SRC '=' NET6;
+matchDstIPv6: DST '=' NET6;
+matchTC: TC '=0x' (HEX_DIGITS | DIGITS);
+matchFlowLabel: FLOWLABEL '=0x' (HEX_DIGITS | DIGITS);
+matchNextHdr: NEXTHDR '=' STRING;
+
 matchSrcPort: SRCPORT '=' DIGITS;
 matchSrcPortRange: SRCPORT '=' DIGITS '-' DIGITS;
 matchDstPort: DSTPORT '=' DIGITS;
 matchDstPortRange: DSTPORT '=' DIGITS '-' DIGITS;
 
+matchICMPType: ICMPTYPE '=' DIGITS;
+
 condCls: 'cls=' DIGITS;
 condAny: ANY '(' cond (',' cond)* ')';
 condAll: ALL '(' cond (',' cond)* ')';
@@ -37,7 +50,9 @@ condNot: NOT '(' cond ')';
 condBool: BOOL '=' ('true' | 'false');
 
 condIPv4: matchSrc | matchDst | matchDSCP | matchTOS | matchProtocol;
+condIPv6: matchSrcIPv6 | matchDstIPv6 | matchTC | matchFlowLabel | matchNextHdr;
 condPort: matchSrcPort | matchSrcPortRange | matchDstPort | matchDstPortRange;
-cond: condAll | condAny | condNot | condIPv4 | condPort | condCls | condBool;
+condICMP: matchICMPType;
+cond: condAll | condAny | condNot | condIPv4 | condIPv6 | condPort | condICMP | condCls | condBool;
 
 trafficClass: cond EOF;
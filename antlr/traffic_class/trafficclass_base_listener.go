@@ -50,6 +50,36 @@ func (s *BaseTrafficClassListener) EnterMatchProtocol(ctx *MatchProtocolContext)
 // ExitMatchProtocol is called when production matchProtocol is exited.
 func (s *BaseTrafficClassListener) ExitMatchProtocol(ctx *MatchProtocolContext) {}
 
+// EnterMatchSrcIPv6 is called when production matchSrcIPv6 is entered.
+func (s *BaseTrafficClassListener) EnterMatchSrcIPv6(ctx *MatchSrcIPv6Context) {}
+
+// ExitMatchSrcIPv6 is called when production matchSrcIPv6 is exited.
+func (s *BaseTrafficClassListener) ExitMatchSrcIPv6(ctx *MatchSrcIPv6Context) {}
+
+// EnterMatchDstIPv6 is called when production matchDstIPv6 is entered.
+func (s *BaseTrafficClassListener) EnterMatchDstIPv6(ctx *MatchDstIPv6Context) {}
+
+// ExitMatchDstIPv6 is called when production matchDstIPv6 is exited.
+func (s *BaseTrafficClassListener) ExitMatchDstIPv6(ctx *MatchDstIPv6Context) {}
+
+// EnterMatchTC is called when production matchTC is entered.
+func (s *BaseTrafficClassListener) EnterMatchTC(ctx *MatchTCContext) {}
+
+// ExitMatchTC is called when production matchTC is exited.
+func (s *BaseTrafficClassListener) ExitMatchTC(ctx *MatchTCContext) {}
+
+// EnterMatchFlowLabel is called when production matchFlowLabel is entered.
+func (s *BaseTrafficClassListener) EnterMatchFlowLabel(ctx *MatchFlowLabelContext) {}
+
+// ExitMatchFlowLabel is called when production matchFlowLabel is exited.
+func (s *BaseTrafficClassListener) ExitMatchFlowLabel(ctx *MatchFlowLabelContext) {}
+
+// EnterMatchNextHdr is called when production matchNextHdr is entered.
+func (s *BaseTrafficClassListener) EnterMatchNextHdr(ctx *MatchNextHdrContext) {}
+
+// ExitMatchNextHdr is called when production matchNextHdr is exited.
+func (s *BaseTrafficClassListener) ExitMatchNextHdr(ctx *MatchNextHdrContext) {}
+
 // EnterMatchSrcPort is called when production matchSrcPort is entered.
 func (s *BaseTrafficClassListener) EnterMatchSrcPort(ctx *MatchSrcPortContext) {}
 
@@ -74,6 +104,12 @@ func (s *BaseTrafficClassListener) EnterMatchDstPortRange(ctx *MatchDstPortRange
 // ExitMatchDstPortRange is called when production matchDstPortRange is exited.
 func (s *BaseTrafficClassListener) ExitMatchDstPortRange(ctx *MatchDstPortRangeContext) {}
 
+// EnterMatchICMPType is called when production matchICMPType is entered.
+func (s *BaseTrafficClassListener) EnterMatchICMPType(ctx *MatchICMPTypeContext) {}
+
+// ExitMatchICMPType is called when production matchICMPType is exited.
+func (s *BaseTrafficClassListener) ExitMatchICMPType(ctx *MatchICMPTypeContext) {}
+
 // EnterCondCls is called when production condCls is entered.
 func (s *BaseTrafficClassListener) EnterCondCls(ctx *CondClsContext) {}
 
@@ -110,12 +146,24 @@ func (s *BaseTrafficClassListener) EnterCondIPv4(ctx *CondIPv4Context) {}
 // ExitCondIPv4 is called when production condIPv4 is exited.
 func (s *BaseTrafficClassListener) ExitCondIPv4(ctx *CondIPv4Context) {}
 
+// EnterCondIPv6 is called when production condIPv6 is entered.
+func (s *BaseTrafficClassListener) EnterCondIPv6(ctx *CondIPv6Context) {}
+
+// ExitCondIPv6 is called when production condIPv6 is exited.
+func (s *BaseTrafficClassListener) ExitCondIPv6(ctx *CondIPv6Context) {}
+
 // EnterCondPort is called when production condPort is entered.
 func (s *BaseTrafficClassListener) EnterCondPort(ctx *CondPortContext) {}
 
 // ExitCondPort is called when production condPort is exited.
 func (s *BaseTrafficClassListener) ExitCondPort(ctx *CondPortContext) {}
 
+// EnterCondICMP is called when production condICMP is entered.
+func (s *BaseTrafficClassListener) EnterCondICMP(ctx *CondICMPContext) {}
+
+// ExitCondICMP is called when production condICMP is exited.
+func (s *BaseTrafficClassListener) ExitCondICMP(ctx *CondICMPContext) {}
+
 // EnterCond is called when production cond is entered.
 func (s *BaseTrafficClassListener) EnterCond(ctx *CondContext) {}
 
@@ -14,113 +14,153 @@ var _ = fmt.Printf
 var _ = unicode.IsLetter
 
 var serializedLexerAtn = []uint16{
-	3, 24715, 42794, 33075, 47597, 16764, 15335, 30598, 22884, 2, 27, 238,
+	3, 24715, 42794, 33075, 47597, 16764, 15335, 30598, 22884, 2, 32, 326,
 	8, 1, 4, 2, 9, 2, 4, 3, 9, 3, 4, 4, 9, 4, 4, 5, 9, 5, 4, 6, 9, 6, 4, 7,
 	9, 7, 4, 8, 9, 8, 4, 9, 9, 9, 4, 10, 9, 10, 4, 11, 9, 11, 4, 12, 9, 12,
 	4, 13, 9, 13, 4, 14, 9, 14, 4, 15, 9, 15, 4, 16, 9, 16, 4, 17, 9, 17, 4,
 	18, 9, 18, 4, 19, 9, 19, 4, 20, 9, 20, 4, 21, 9, 21, 4, 22, 9, 22, 4, 23,
-	9, 23, 4, 24, 9, 24, 4, 25, 9, 25, 4, 26, 9, 26, 3, 2, 3, 2, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 4, 3, 4, 3, 5, 3, 5, 3, 5, 3, 5, 3, 5, 3, 6, 3, 6, 3, 7,
-	3, 7, 3, 8, 3, 8, 3, 9, 3, 9, 3, 9, 3, 9, 3, 9, 3, 10, 3, 10, 3, 10, 3,
-	10, 3, 10, 3, 10, 3, 11, 6, 11, 85, 10, 11, 13, 11, 14, 11, 86, 3, 11,
-	3, 11, 3, 12, 3, 12, 3, 12, 7, 12, 94, 10, 12, 12, 12, 14, 12, 97, 11,
-	12, 5, 12, 99, 10, 12, 3, 13, 6, 13, 102, 10, 13, 13, 13, 14, 13, 103,
-	3, 14, 3, 14, 3, 14, 3, 14, 3, 14, 3, 14, 3, 14, 3, 14, 3, 14, 3, 14, 3,
-	15, 3, 15, 3, 15, 3, 15, 3, 15, 3, 15, 5, 15, 122, 10, 15, 3, 16, 3, 16,
-	3, 16, 3, 16, 3, 16, 3, 16, 5, 16, 130, 10, 16, 3, 17, 3, 17, 3, 17, 3,
-	17, 3, 17, 3, 17, 5, 17, 138, 10, 17, 3, 18, 3, 18, 3, 18, 3, 18, 3, 18,
-	3, 18, 3, 18, 3, 18, 5, 18, 148, 10, 18, 3, 19, 3, 19, 3, 19, 3, 19, 3,
-	19, 3, 19, 5, 19, 156, 10, 19, 3, 20, 3, 20, 3, 20, 3, 20, 3, 20, 3, 20,
-	5, 20, 164, 10, 20, 3, 21, 3, 21, 3, 21, 3, 21, 3, 21, 3, 21, 3, 21, 3,
-	21, 5, 21, 174, 10, 21, 3, 22, 3, 22, 3, 22, 3, 22, 3, 22, 3, 22, 5, 22,
-	182, 10, 22, 3, 23, 3, 23, 3, 23, 3, 23, 3, 23, 3, 23, 3, 23, 3, 23, 3,
-	23, 3, 23, 3, 23, 3, 23, 3, 23, 3, 23, 3, 23, 3, 23, 5, 23, 200, 10, 23,
-	3, 24, 3, 24, 3, 24, 3, 24, 3, 24, 3, 24, 3, 24, 3, 24, 3, 24, 3, 24, 3,
-	24, 3, 24, 3, 24, 3, 24, 5, 24, 216, 10, 24, 3, 25, 3, 25, 3, 25, 3, 25,
-	3, 25, 3, 25, 3, 25, 3, 25, 3, 25, 3, 25, 3, 25, 3, 25, 3, 25, 3, 25, 5,
-	25, 232, 10, 25, 3, 26, 6, 26, 235, 10, 26, 13, 26, 14, 26, 236, 2, 2,
-	27, 3, 3, 5, 4, 7, 5, 9, 6, 11, 7, 13, 8, 15, 9, 17, 10, 19, 11, 21, 12,
-	23, 13, 25, 14, 27, 15, 29, 16, 31, 17, 33, 18, 35, 19, 37, 20, 39, 21,
-	41, 22, 43, 23, 45, 24, 47, 25, 49, 26, 51, 27, 3, 2, 7, 5, 2, 11, 12,
-	15, 15, 34, 34, 3, 2, 51, 59, 3, 2, 50, 59, 5, 2, 50, 59, 67, 72, 99, 104,
-	4, 2, 67, 92, 99, 124, 2, 253, 2, 3, 3, 2, 2, 2, 2, 5, 3, 2, 2, 2, 2, 7,
-	3, 2, 2, 2, 2, 9, 3, 2, 2, 2, 2, 11, 3, 2, 2, 2, 2, 13, 3, 2, 2, 2, 2,
-	15, 3, 2, 2, 2, 2, 17, 3, 2, 2, 2, 2, 19, 3, 2, 2, 2, 2, 21, 3, 2, 2, 2,
-	2, 23, 3, 2, 2, 2, 2, 25, 3, 2, 2, 2, 2, 27, 3, 2, 2, 2, 2, 29, 3, 2, 2,
-	2, 2, 31, 3, 2, 2, 2, 2, 33, 3, 2, 2, 2, 2, 35, 3, 2, 2, 2, 2, 37, 3, 2,
-	2, 2, 2, 39, 3, 2, 2, 2, 2, 41, 3, 2, 2, 2, 2, 43, 3, 2, 2, 2, 2, 45, 3,
-	2, 2, 2, 2, 47, 3, 2, 2, 2, 2, 49, 3, 2, 2, 2, 2, 51, 3, 2, 2, 2, 3, 53,
-	3, 2, 2, 2, 5, 55, 3, 2, 2, 2, 7, 59, 3, 2, 2, 2, 9, 61, 3, 2, 2, 2, 11,
-	66, 3, 2, 2, 2, 13, 68, 3, 2, 2, 2, 15, 70, 3, 2, 2, 2, 17, 72, 3, 2, 2,
-	2, 19, 77, 3, 2, 2, 2, 21, 84, 3, 2, 2, 2, 23, 98, 3, 2, 2, 2, 25, 101,
-	3, 2, 2, 2, 27, 105, 3, 2, 2, 2, 29, 121, 3, 2, 2, 2, 31, 129, 3, 2, 2,
-	2, 33, 137, 3, 2, 2, 2, 35, 147, 3, 2, 2, 2, 37, 155, 3, 2, 2, 2, 39, 163,
-	3, 2, 2, 2, 41, 173, 3, 2, 2, 2, 43, 181, 3, 2, 2, 2, 45, 199, 3, 2, 2,
-	2, 47, 215, 3, 2, 2, 2, 49, 231, 3, 2, 2, 2, 51, 234, 3, 2, 2, 2, 53, 54,
-	7, 63, 2, 2, 54, 4, 3, 2, 2, 2, 55, 56, 7, 63, 2, 2, 56, 57, 7, 50, 2,
-	2, 57, 58, 7, 122, 2, 2, 58, 6, 3, 2, 2, 2, 59, 60, 7, 47, 2, 2, 60, 8,
-	3, 2, 2, 2, 61, 62, 7, 101, 2, 2, 62, 63, 7, 110, 2, 2, 63, 64, 7, 117,
-	2, 2, 64, 65, 7, 63, 2, 2, 65, 10, 3, 2, 2, 2, 66, 67, 7, 42, 2, 2, 67,
-	12, 3, 2, 2, 2, 68, 69, 7, 46, 2, 2, 69, 14, 3, 2, 2, 2, 70, 71, 7, 43,
-	2, 2, 71, 16, 3, 2, 2, 2, 72, 73, 7, 118, 2, 2, 73, 74, 7, 116, 2, 2, 74,
-	75, 7, 119, 2, 2, 75, 76, 7, 103, 2, 2, 76, 18, 3, 2, 2, 2, 77, 78, 7,
-	104, 2, 2, 78, 79, 7, 99, 2, 2, 79, 80, 7, 110, 2, 2, 80, 81, 7, 117, 2,
-	2, 81, 82, 7, 103, 2, 2, 82, 20, 3, 2, 2, 2, 83, 85, 9, 2, 2, 2, 84, 83,
-	3, 2, 2, 2, 85, 86, 3, 2, 2, 2, 86, 84, 3, 2, 2, 2, 86, 87, 3, 2, 2, 2,
-	87, 88, 3, 2, 2, 2, 88, 89, 8, 11, 2, 2, 89, 22, 3, 2, 2, 2, 90, 99, 7,
-	50, 2, 2, 91, 95, 9, 3, 2, 2, 92, 94, 9, 4, 2, 2, 93, 92, 3, 2, 2, 2, 94,
-	97, 3, 2, 2, 2, 95, 93, 3, 2, 2, 2, 95, 96, 3, 2, 2, 2, 96, 99, 3, 2, 2,
-	2, 97, 95, 3, 2, 2, 2, 98, 90, 3, 2, 2, 2, 98, 91, 3, 2, 2, 2, 99, 24,
-	3, 2, 2, 2, 100, 102, 9, 5, 2, 2, 101, 100, 3, 2, 2, 2, 102, 103, 3, 2,
-	2, 2, 103, 101, 3, 2, 2, 2, 103, 104, 3, 2, 2, 2, 104, 26, 3, 2, 2, 2,
-	105, 106, 5, 23, 12, 2, 106, 107, 7, 48, 2, 2, 107, 108, 5, 23, 12, 2,
-	108, 109, 7, 48, 2, 2, 109, 110, 5, 23, 12, 2, 110, 111, 7, 48, 2, 2, 111,
-	112, 5, 23, 12, 2, 112, 113, 7, 49, 2, 2, 113, 114, 5, 23, 12, 2, 114,
-	28, 3, 2, 2, 2, 115, 116, 7, 67, 2, 2, 116, 117, 7, 80, 2, 2, 117, 122,
-	7, 91, 2, 2, 118, 119, 7, 99, 2, 2, 119, 120, 7, 112, 2, 2, 120, 122, 7,
-	123, 2, 2, 121, 115, 3, 2, 2, 2, 121, 118, 3, 2, 2, 2, 122, 30, 3, 2, 2,
-	2, 123, 124, 7, 67, 2, 2, 124, 125, 7, 78, 2, 2, 125, 130, 7, 78, 2, 2,
-	126, 127, 7, 99, 2, 2, 127, 128, 7, 110, 2, 2, 128, 130, 7, 110, 2, 2,
-	129, 123, 3, 2, 2, 2, 129, 126, 3, 2, 2, 2, 130, 32, 3, 2, 2, 2, 131, 132,
-	7, 80, 2, 2, 132, 133, 7, 81, 2, 2, 133, 138, 7, 86, 2, 2, 134, 135, 7,
-	112, 2, 2, 135, 136, 7, 113, 2, 2, 136, 138, 7, 118, 2, 2, 137, 131, 3,
-	2, 2, 2, 137, 134, 3, 2, 2, 2, 138, 34, 3, 2, 2, 2, 139, 140, 7, 68, 2,
-	2, 140, 141, 7, 81, 2, 2, 141, 142, 7, 81, 2, 2, 142, 148, 7, 78, 2, 2,
-	143, 144, 7, 100, 2, 2, 144, 145, 7, 113, 2, 2, 145, 146, 7, 113, 2, 2,
-	146, 148, 7, 110, 2, 2, 147, 139, 3, 2, 2, 2, 147, 143, 3, 2, 2, 2, 148,
-	36, 3, 2, 2, 2, 149, 150, 7, 85, 2, 2, 150, 151, 7, 84, 2, 2, 151, 156,
-	7, 69, 2, 2, 152, 153, 7, 117, 2, 2, 153, 154, 7, 116, 2, 2, 154, 156,
-	7, 101, 2, 2, 155, 149, 3, 2, 2, 2, 155, 152, 3, 2, 2, 2, 156, 38, 3, 2,
-	2, 2, 157, 158, 7, 70, 2, 2, 158, 159, 7, 85, 2, 2, 159, 164, 7, 86, 2,
-	2, 160, 161, 7, 102, 2, 2, 161, 162, 7, 117, 2, 2, 162, 164, 7, 118, 2,
-	2, 163, 157, 3, 2, 2, 2, 163, 160, 3, 2, 2, 2, 164, 40, 3, 2, 2, 2, 165,
-	166, 7, 70, 2, 2, 166, 167, 7, 85, 2, 2, 167, 168, 7, 69, 2, 2, 168, 174,
-	7, 82, 2, 2, 169, 170, 7, 102, 2, 2, 170, 171, 7, 117, 2, 2, 171, 172,
-	7, 101, 2, 2, 172, 174, 7, 114, 2, 2, 173, 165, 3, 2, 2, 2, 173, 169, 3,
-	2, 2, 2, 174, 42, 3, 2, 2, 2, 175, 176, 7, 86, 2, 2, 176, 177, 7, 81, 2,
-	2, 177, 182, 7, 85, 2, 2, 178, 179, 7, 118, 2, 2, 179, 180, 7, 113, 2,
-	2, 180, 182, 7, 117, 2, 2, 181, 175, 3, 2, 2, 2, 181, 178, 3, 2, 2, 2,
-	182, 44, 3, 2, 2, 2, 183, 184, 7, 82, 2, 2, 184, 185, 7, 84, 2, 2, 185,
-	186, 7, 81, 2, 2, 186, 187, 7, 86, 2, 2, 187, 188, 7, 81, 2, 2, 188, 189,
-	7, 69, 2, 2, 189, 190, 7, 81, 2, 2, 190, 200, 7, 78, 2, 2, 191, 192, 7,
-	114, 2, 2, 192, 193, 7, 116, 2, 2, 193, 194, 7, 113, 2, 2, 194, 195, 7,
-	118, 2, 2, 195, 196, 7, 113, 2, 2, 196, 197, 7, 101, 2, 2, 197, 198, 7,
-	113, 2, 2, 198, 200, 7, 110, 2, 2, 199, 183, 3, 2, 2, 2, 199, 191, 3, 2,
-	2, 2, 200, 46, 3, 2, 2, 2, 201, 202, 7, 85, 2, 2, 202, 203, 7, 84, 2, 2,
-	203, 204, 7, 69, 2, 2, 204, 205, 7, 82, 2, 2, 205, 206, 7, 81, 2, 2, 206,
-	207, 7, 84, 2, 2, 207, 216, 7, 86, 2, 2, 208, 209, 7, 117, 2, 2, 209, 210,
-	7, 116, 2, 2, 210, 211, 7, 101, 2, 2, 211, 212, 7, 114, 2, 2, 212, 213,
-	7, 113, 2, 2, 213, 214, 7, 116, 2, 2, 214, 216, 7, 118, 2, 2, 215, 201,
-	3, 2, 2, 2, 215, 208, 3, 2, 2, 2, 216, 48, 3, 2, 2, 2, 217, 218, 7, 70,
-	2, 2, 218, 219, 7, 85, 2, 2, 219, 220, 7, 86, 2, 2, 220, 221, 7, 82, 2,
-	2, 221, 222, 7, 81, 2, 2, 222, 223, 7, 84, 2, 2, 223, 232, 7, 86, 2, 2,
-	224, 225, 7, 102, 2, 2, 225, 226, 7, 117, 2, 2, 226, 227, 7, 118, 2, 2,
-	227, 228, 7, 114, 2, 2, 228, 229, 7, 113, 2, 2, 229, 230, 7, 116, 2, 2,
-	230, 232, 7, 118, 2, 2, 231, 217, 3, 2, 2, 2, 231, 224, 3, 2, 2, 2, 232,
-	50, 3, 2, 2, 2, 233, 235, 9, 6, 2, 2, 234, 233, 3, 2, 2, 2, 235, 236, 3,
-	2, 2, 2, 236, 234, 3, 2, 2, 2, 236, 237, 3, 2, 2, 2, 237, 52, 3, 2, 2,
-	2, 20, 2, 86, 95, 98, 101, 103, 121, 129, 137, 147, 155, 163, 173, 181,
-	199, 215, 231, 236, 3, 8, 2, 2,
+	9, 23, 4, 24, 9, 24, 4, 25, 9, 25, 4, 26, 9, 26, 4, 27, 9, 27, 4, 28, 9,
+	28, 4, 29, 9, 29, 4, 30, 9, 30, 4, 31, 9, 31, 3, 2, 3, 2, 3, 3, 3, 3, 3,
+	3, 3, 3, 3, 4, 3, 4, 3, 5, 3, 5, 3, 5, 3, 5, 3, 5, 3, 6, 3, 6, 3, 7, 3,
+	7, 3, 8, 3, 8, 3, 9, 3, 9, 3, 9, 3, 9, 3, 9, 3, 10, 3, 10, 3, 10, 3, 10,
+	3, 10, 3, 10, 3, 11, 6, 11, 95, 10, 11, 13, 11, 14, 11, 96, 3, 11, 3, 11,
+	3, 12, 3, 12, 3, 12, 7, 12, 104, 10, 12, 12, 12, 14, 12, 107, 11, 12, 5,
+	12, 109, 10, 12, 3, 13, 6, 13, 112, 10, 13, 13, 13, 14, 13, 113, 3, 14,
+	3, 14, 3, 14, 3, 14, 3, 14, 3, 14, 3, 14, 3, 14, 3, 14, 3, 14, 3, 15, 7,
+	15, 127, 10, 15, 12, 15, 14, 15, 130, 11, 15, 3, 15, 3, 15, 7, 15, 134,
+	10, 15, 12, 15, 14, 15, 137, 11, 15, 3, 15, 3, 15, 3, 15, 3, 16, 3, 16,
+	3, 16, 3, 16, 3, 16, 3, 16, 5, 16, 148, 10, 16, 3, 17, 3, 17, 3, 17, 3,
+	17, 3, 17, 3, 17, 5, 17, 156, 10, 17, 3, 18, 3, 18, 3, 18, 3, 18, 3, 18,
+	3, 18, 5, 18, 164, 10, 18, 3, 19, 3, 19, 3, 19, 3, 19, 3, 19, 3, 19, 3,
+	19, 3, 19, 5, 19, 174, 10, 19, 3, 20, 3, 20, 3, 20, 3, 20, 3, 20, 3, 20,
+	5, 20, 182, 10, 20, 3, 21, 3, 21, 3, 21, 3, 21, 3, 21, 3, 21, 5, 21, 190,
+	10, 21, 3, 22, 3, 22, 3, 22, 3, 22, 3, 22, 3, 22, 3, 22, 3, 22, 5, 22,
+	200, 10, 22, 3, 23, 3, 23, 3, 23, 3, 23, 3, 23, 3, 23, 5, 23, 208, 10,
+	23, 3, 24, 3, 24, 3, 24, 3, 24, 3, 24, 3, 24, 3, 24, 3, 24, 3, 24, 3, 24,
+	3, 24, 3, 24, 3, 24, 3, 24, 3, 24, 3, 24, 5, 24, 226, 10, 24, 3, 25, 3,
+	25, 3, 25, 3, 25, 3, 25, 3, 25, 3, 25, 3, 25, 3, 25, 3, 25, 3, 25, 3, 25,
+	3, 25, 3, 25, 5, 25, 242, 10, 25, 3, 26, 3, 26, 3, 26, 3, 26, 3, 26, 3,
+	26, 3, 26, 3, 26, 3, 26, 3, 26, 3, 26, 3, 26, 3, 26, 3, 26, 5, 26, 258,
+	10, 26, 3, 27, 3, 27, 3, 27, 3, 27, 5, 27, 264, 10, 27, 3, 28, 3, 28, 3,
+	28, 3, 28, 3, 28, 3, 28, 3, 28, 3, 28, 3, 28, 3, 28, 3, 28, 3, 28, 3, 28,
+	3, 28, 3, 28, 3, 28, 3, 28, 3, 28, 5, 28, 284, 10, 28, 3, 29, 3, 29, 3,
+	29, 3, 29, 3, 29, 3, 29, 3, 29, 3, 29, 3, 29, 3, 29, 3, 29, 3, 29, 3, 29,
+	3, 29, 5, 29, 300, 10, 29, 3, 30, 3, 30, 3, 30, 3, 30, 3, 30, 3, 30, 3,
+	30, 3, 30, 3, 30, 3, 30, 3, 30, 3, 30, 3, 30, 3, 30, 3, 30, 3, 30, 5, 30,
+	318, 10, 30, 3, 31, 3, 31, 7, 31, 322, 10, 31, 12, 31, 14, 31, 325, 11,
+	31, 2, 2, 32, 3, 3, 5, 4, 7, 5, 9, 6, 11, 7, 13, 8, 15, 9, 17, 10, 19,
+	11, 21, 12, 23, 13, 25, 14, 27, 15, 29, 16, 31, 17, 33, 18, 35, 19, 37,
+	20, 39, 21, 41, 22, 43, 23, 45, 24, 47, 25, 49, 26, 51, 27, 53, 28, 55,
+	29, 57, 30, 59, 31, 61, 32, 3, 2, 9, 5, 2, 11, 12, 15, 15, 34, 34, 3, 2,
+	51, 59, 3, 2, 50, 59, 5, 2, 50, 59, 67, 72, 99, 104, 6, 2, 48, 48, 50,
+	60, 67, 72, 99, 104, 4, 2, 67, 92, 99, 124, 5, 2, 50, 59, 67, 92, 99, 124,
+	2, 347, 2, 3, 3, 2, 2, 2, 2, 5, 3, 2, 2, 2, 2, 7, 3, 2, 2, 2, 2, 9, 3,
+	2, 2, 2, 2, 11, 3, 2, 2, 2, 2, 13, 3, 2, 2, 2, 2, 15, 3, 2, 2, 2, 2, 17,
+	3, 2, 2, 2, 2, 19, 3, 2, 2, 2, 2, 21, 3, 2, 2, 2, 2, 23, 3, 2, 2, 2, 2,
+	25, 3, 2, 2, 2, 2, 27, 3, 2, 2, 2, 2, 29, 3, 2, 2, 2, 2, 31, 3, 2, 2, 2,
+	2, 33, 3, 2, 2, 2, 2, 35, 3, 2, 2, 2, 2, 37, 3, 2, 2, 2, 2, 39, 3, 2, 2,
+	2, 2, 41, 3, 2, 2, 2, 2, 43, 3, 2, 2, 2, 2, 45, 3, 2, 2, 2, 2, 47, 3, 2,
+	2, 2, 2, 49, 3, 2, 2, 2, 2, 51, 3, 2, 2, 2, 2, 53, 3, 2, 2, 2, 2, 55, 3,
+	2, 2, 2, 2, 57, 3, 2, 2, 2, 2, 59, 3, 2, 2, 2, 2, 61, 3, 2, 2, 2, 3, 63,
+	3, 2, 2, 2, 5, 65, 3, 2, 2, 2, 7, 69, 3, 2, 2, 2, 9, 71, 3, 2, 2, 2, 11,
+	76, 3, 2, 2, 2, 13, 78, 3, 2, 2, 2, 15, 80, 3, 2, 2, 2, 17, 82, 3, 2, 2,
+	2, 19, 87, 3, 2, 2, 2, 21, 94, 3, 2, 2, 2, 23, 108, 3, 2, 2, 2, 25, 111,
+	3, 2, 2, 2, 27, 115, 3, 2, 2, 2, 29, 128, 3, 2, 2, 2, 31, 147, 3, 2, 2,
+	2, 33, 155, 3, 2, 2, 2, 35, 163, 3, 2, 2, 2, 37, 173, 3, 2, 2, 2, 39, 181,
+	3, 2, 2, 2, 41, 189, 3, 2, 2, 2, 43, 199, 3, 2, 2, 2, 45, 207, 3, 2, 2,
+	2, 47, 225, 3, 2, 2, 2, 49, 241, 3, 2, 2, 2, 51, 257, 3, 2, 2, 2, 53, 263,
+	3, 2, 2, 2, 55, 283, 3, 2, 2, 2, 57, 299, 3, 2, 2, 2, 59, 317, 3, 2, 2,
+	2, 61, 319, 3, 2, 2, 2, 63, 64, 7, 63, 2, 2, 64, 4, 3, 2, 2, 2, 65, 66,
+	7, 63, 2, 2, 66, 67, 7, 50, 2, 2, 67, 68, 7, 122, 2, 2, 68, 6, 3, 2, 2,
+	2, 69, 70, 7, 47, 2, 2, 70, 8, 3, 2, 2, 2, 71, 72, 7, 101, 2, 2, 72, 73,
+	7, 110, 2, 2, 73, 74, 7, 117, 2, 2, 74, 75, 7, 63, 2, 2, 75, 10, 3, 2,
+	2, 2, 76, 77, 7, 42, 2, 2, 77, 12, 3, 2, 2, 2, 78, 79, 7, 46, 2, 2, 79,
+	14, 3, 2, 2, 2, 80, 81, 7, 43, 2, 2, 81, 16, 3, 2, 2, 2, 82, 83, 7, 118,
+	2, 2, 83, 84, 7, 116, 2, 2, 84, 85, 7, 119, 2, 2, 85, 86, 7, 103, 2, 2,
+	86, 18, 3, 2, 2, 2, 87, 88, 7, 104, 2, 2, 88, 89, 7, 99, 2, 2, 89, 90,
+	7, 110, 2, 2, 90, 91, 7, 117, 2, 2, 91, 92, 7, 103, 2, 2, 92, 20, 3, 2,
+	2, 2, 93, 95, 9, 2, 2, 2, 94, 93, 3, 2, 2, 2, 95, 96, 3, 2, 2, 2, 96, 94,
+	3, 2, 2, 2, 96, 97, 3, 2, 2, 2, 97, 98, 3, 2, 2, 2, 98, 99, 8, 11, 2, 2,
+	99, 22, 3, 2, 2, 2, 100, 109, 7, 50, 2, 2, 101, 105, 9, 3, 2, 2, 102, 104,
+	9, 4, 2, 2, 103, 102, 3, 2, 2, 2, 104, 107, 3, 2, 2, 2, 105, 103, 3, 2,
+	2, 2, 105, 106, 3, 2, 2, 2, 106, 109, 3, 2, 2, 2, 107, 105, 3, 2, 2, 2,
+	108, 100, 3, 2, 2, 2, 108, 101, 3, 2, 2, 2, 109, 24, 3, 2, 2, 2, 110, 112,
+	9, 5, 2, 2, 111, 110, 3, 2, 2, 2, 112, 113, 3, 2, 2, 2, 113, 111, 3, 2,
+	2, 2, 113, 114, 3, 2, 2, 2, 114, 26, 3, 2, 2, 2, 115, 116, 5, 23, 12, 2,
+	116, 117, 7, 48, 2, 2, 117, 118, 5, 23, 12, 2, 118, 119, 7, 48, 2, 2, 119,
+	120, 5, 23, 12, 2, 120, 121, 7, 48, 2, 2, 121, 122, 5, 23, 12, 2, 122,
+	123, 7, 49, 2, 2, 123, 124, 5, 23, 12, 2, 124, 28, 3, 2, 2, 2, 125, 127,
+	9, 5, 2, 2, 126, 125, 3, 2, 2, 2, 127, 130, 3, 2, 2, 2, 128, 126, 3, 2,
+	2, 2, 128, 129, 3, 2, 2, 2, 129, 131, 3, 2, 2, 2, 130, 128, 3, 2, 2, 2,
+	131, 135, 7, 60, 2, 2, 132, 134, 9, 6, 2, 2, 133, 132, 3, 2, 2, 2, 134,
+	137, 3, 2, 2, 2, 135, 133, 3, 2, 2, 2, 135, 136, 3, 2, 2, 2, 136, 138,
+	3, 2, 2, 2, 137, 135, 3, 2, 2, 2, 138, 139, 7, 49, 2, 2, 139, 140, 5, 23,
+	12, 2, 140, 30, 3, 2, 2, 2, 141, 142, 7, 67, 2, 2, 142, 143, 7, 80, 2,
+	2, 143, 148, 7, 91, 2, 2, 144, 145, 7, 99, 2, 2, 145, 146, 7, 112, 2, 2,
+	146, 148, 7, 123, 2, 2, 147, 141, 3, 2, 2, 2, 147, 144, 3, 2, 2, 2, 148,
+	32, 3, 2, 2, 2, 149, 150, 7, 67, 2, 2, 150, 151, 7, 78, 2, 2, 151, 156,
+	7, 78, 2, 2, 152, 153, 7, 99, 2, 2, 153, 154, 7, 110, 2, 2, 154, 156, 7,
+	110, 2, 2, 155, 149, 3, 2, 2, 2, 155, 152, 3, 2, 2, 2, 156, 34, 3, 2, 2,
+	2, 157, 158, 7, 80, 2, 2, 158, 159, 7, 81, 2, 2, 159, 164, 7, 86, 2, 2,
+	160, 161, 7, 112, 2, 2, 161, 162, 7, 113, 2, 2, 162, 164, 7, 118, 2, 2,
+	163, 157, 3, 2, 2, 2, 163, 160, 3, 2, 2, 2, 164, 36, 3, 2, 2, 2, 165, 166,
+	7, 68, 2, 2, 166, 167, 7, 81, 2, 2, 167, 168, 7, 81, 2, 2, 168, 174, 7,
+	78, 2, 2, 169, 170, 7, 100, 2, 2, 170, 171, 7, 113, 2, 2, 171, 172, 7,
+	113, 2, 2, 172, 174, 7, 110, 2, 2, 173, 165, 3, 2, 2, 2, 173, 169, 3, 2,
+	2, 2, 174, 38, 3, 2, 2, 2, 175, 176, 7, 85, 2, 2, 176, 177, 7, 84, 2, 2,
+	177, 182, 7, 69, 2, 2, 178, 179, 7, 117, 2, 2, 179, 180, 7, 116, 2, 2,
+	180, 182, 7, 101, 2, 2, 181, 175, 3, 2, 2, 2, 181, 178, 3, 2, 2, 2, 182,
+	40, 3, 2, 2, 2, 183, 184, 7, 70, 2, 2, 184, 185, 7, 85, 2, 2, 185, 190,
+	7, 86, 2, 2, 186, 187, 7, 102, 2, 2, 187, 188, 7, 117, 2, 2, 188, 190,
+	7, 118, 2, 2, 189, 183, 3, 2, 2, 2, 189, 186, 3, 2, 2, 2, 190, 42, 3, 2,
+	2, 2, 191, 192, 7, 70, 2, 2, 192, 193, 7, 85, 2, 2, 193, 194, 7, 69, 2,
+	2, 194, 200, 7, 82, 2, 2, 195, 196, 7, 102, 2, 2, 196, 197, 7, 117, 2,
+	2, 197, 198, 7, 101, 2, 2, 198, 200, 7, 114, 2, 2, 199, 191, 3, 2, 2, 2,
+	199, 195, 3, 2, 2, 2, 200, 44, 3, 2, 2, 2, 201, 202, 7, 86, 2, 2, 202,
+	203, 7, 81, 2, 2, 203, 208, 7, 85, 2, 2, 204, 205, 7, 118, 2, 2, 205, 206,
+	7, 113, 2, 2, 206, 208, 7, 117, 2, 2, 207, 201, 3, 2, 2, 2, 207, 204, 3,
+	2, 2, 2, 208, 46, 3, 2, 2, 2, 209, 210, 7, 82, 2, 2, 210, 211, 7, 84, 2,
+	2, 211, 212, 7, 81, 2, 2, 212, 213, 7, 86, 2, 2, 213, 214, 7, 81, 2, 2,
+	214, 215, 7, 69, 2, 2, 215, 216, 7, 81, 2, 2, 216, 226, 7, 78, 2, 2, 217,
+	218, 7, 114, 2, 2, 218, 219, 7, 116, 2, 2, 219, 220, 7, 113, 2, 2, 220,
+	221, 7, 118, 2, 2, 221, 222, 7, 113, 2, 2, 222, 223, 7, 101, 2, 2, 223,
+	224, 7, 113, 2, 2, 224, 226, 7, 110, 2, 2, 225, 209, 3, 2, 2, 2, 225, 217,
+	3, 2, 2, 2, 226, 48, 3, 2, 2, 2, 227, 228, 7, 85, 2, 2, 228, 229, 7, 84,
+	2, 2, 229, 230, 7, 69, 2, 2, 230, 231, 7, 82, 2, 2, 231, 232, 7, 81, 2,
+	2, 232, 233, 7, 84, 2, 2, 233, 242, 7, 86, 2, 2, 234, 235, 7, 117, 2, 2,
+	235, 236, 7, 116, 2, 2, 236, 237, 7, 101, 2, 2, 237, 238, 7, 114, 2, 2,
+	238, 239, 7, 113, 2, 2, 239, 240, 7, 116, 2, 2, 240, 242, 7, 118, 2, 2,
+	241, 227, 3, 2, 2, 2, 241, 234, 3, 2, 2, 2, 242, 50, 3, 2, 2, 2, 243, 244,
+	7, 70, 2, 2, 244, 245, 7, 85, 2, 2, 245, 246, 7, 86, 2, 2, 246, 247, 7,
+	82, 2, 2, 247, 248, 7, 81, 2, 2, 248, 249, 7, 84, 2, 2, 249, 258, 7, 86,
+	2, 2, 250, 251, 7, 102, 2, 2, 251, 252, 7, 117, 2, 2, 252, 253, 7, 118,
+	2, 2, 253, 254, 7, 114, 2, 2, 254, 255, 7, 113, 2, 2, 255, 256, 7, 116,
+	2, 2, 256, 258, 7, 118, 2, 2, 257, 243, 3, 2, 2, 2, 257, 250, 3, 2, 2,
+	2, 258, 52, 3, 2, 2, 2, 259, 260, 7, 86, 2, 2, 260, 264, 7, 69, 2, 2, 261,
+	262, 7, 118, 2, 2, 262, 264, 7, 101, 2, 2, 263, 259, 3, 2, 2, 2, 263, 261,
+	3, 2, 2, 2, 264, 54, 3, 2, 2, 2, 265, 266, 7, 72, 2, 2, 266, 267, 7, 78,
+	2, 2, 267, 268, 7, 81, 2, 2, 268, 269, 7, 89, 2, 2, 269, 270, 7, 78, 2,
+	2, 270, 271, 7, 67, 2, 2, 271, 272, 7, 68, 2, 2, 272, 273, 7, 71, 2, 2,
+	273, 284, 7, 78, 2, 2, 274, 275, 7, 104, 2, 2, 275, 276, 7, 110, 2, 2,
+	276, 277, 7, 113, 2, 2, 277, 278, 7, 121, 2, 2, 278, 279, 7, 110, 2, 2,
+	279, 280, 7, 99, 2, 2, 280, 281, 7, 100, 2, 2, 281, 282, 7, 103, 2, 2,
+	282, 284, 7, 110, 2, 2, 283, 265, 3, 2, 2, 2, 283, 274, 3, 2, 2, 2, 284,
+	56, 3, 2, 2, 2, 285, 286, 7, 80, 2, 2, 286, 287, 7, 71, 2, 2, 287, 288,
+	7, 90, 2, 2, 288, 289, 7, 86, 2, 2, 289, 290, 7, 74, 2, 2, 290, 291, 7,
+	70, 2, 2, 291, 300, 7, 84, 2, 2, 292, 293, 7, 112, 2, 2, 293, 294, 7, 103,
+	2, 2, 294, 295, 7, 122, 2, 2, 295, 296, 7, 118, 2, 2, 296, 297, 7, 106,
+	2, 2, 297, 298, 7, 102, 2, 2, 298, 300, 7, 116, 2, 2, 299, 285, 3, 2, 2,
+	2, 299, 292, 3, 2, 2, 2, 300, 58, 3, 2, 2, 2, 301, 302, 7, 75, 2, 2, 302,
+	303, 7, 69, 2, 2, 303, 304, 7, 79, 2, 2, 304, 305, 7, 82, 2, 2, 305, 306,
+	7, 86, 2, 2, 306, 307, 7, 91, 2, 2, 307, 308, 7, 82, 2, 2, 308, 318, 7,
+	71, 2, 2, 309, 310, 7, 107, 2, 2, 310, 311, 7, 101, 2, 2, 311, 312, 7,
+	111, 2, 2, 312, 313, 7, 114, 2, 2, 313, 314, 7, 118, 2, 2, 314, 315, 7,
+	123, 2, 2, 315, 316, 7, 114, 2, 2, 316, 318, 7, 103, 2, 2, 317, 301, 3,
+	2, 2, 2, 317, 309, 3, 2, 2, 2, 318, 60, 3, 2, 2, 2, 319, 323, 9, 7, 2,
+	2, 320, 322, 9, 8, 2, 2, 321, 320, 3, 2, 2, 2, 322, 325, 3, 2, 2, 2, 323,
+	321, 3, 2, 2, 2, 323, 324, 3, 2, 2, 2, 324, 62, 3, 2, 2, 2, 325, 323, 3,
+	2, 2, 2, 26, 2, 96, 105, 108, 111, 113, 128, 135, 147, 155, 163, 173, 181,
+	189, 199, 207, 225, 241, 257, 263, 283, 299, 317, 323, 3, 8, 2, 2,
 }
 
 var lexerChannelNames = []string{
@@ -137,14 +177,16 @@ var lexerLiteralNames = []string{
 
 var lexerSymbolicNames = []string{
 	"", "", "", "", "", "", "", "", "", "", "WHITESPACE", "DIGITS", "HEX_DIGITS",
-	"NET", "ANY", "ALL", "NOT", "BOOL", "SRC", "DST", "DSCP", "TOS", "PROTOCOL",
-	"SRCPORT", "DSTPORT", "STRING",
+	"NET", "NET6", "ANY", "ALL", "NOT", "BOOL", "SRC", "DST", "DSCP", "TOS",
+	"PROTOCOL", "SRCPORT", "DSTPORT", "TC", "FLOWLABEL", "NEXTHDR", "ICMPTYPE",
+	"STRING",
 }
 
 var lexerRuleNames = []string{
 	"T__0", "T__1", "T__2", "T__3", "T__4", "T__5", "T__6", "T__7", "T__8",
-	"WHITESPACE", "DIGITS", "HEX_DIGITS", "NET", "ANY", "ALL", "NOT", "BOOL",
-	"SRC", "DST", "DSCP", "TOS", "PROTOCOL", "SRCPORT", "DSTPORT", "STRING",
+	"WHITESPACE", "DIGITS", "HEX_DIGITS", "NET", "NET6", "ANY", "ALL", "NOT",
+	"BOOL", "SRC", "DST", "DSCP", "TOS", "PROTOCOL", "SRCPORT", "DSTPORT",
+	"TC", "FLOWLABEL", "NEXTHDR", "ICMPTYPE", "STRING",
 }
 
 type TrafficClassLexer struct {
@@ -197,16 +239,21 @@ const (
 	TrafficClassLexerDIGITS     = 11
 	TrafficClassLexerHEX_DIGITS = 12
 	TrafficClassLexerNET        = 13
-	TrafficClassLexerANY        = 14
-	TrafficClassLexerALL        = 15
-	TrafficClassLexerNOT        = 16
-	TrafficClassLexerBOOL       = 17
-	TrafficClassLexerSRC        = 18
-	TrafficClassLexerDST        = 19
-	TrafficClassLexerDSCP       = 20
-	TrafficClassLexerTOS        = 21
-	TrafficClassLexerPROTOCOL   = 22
-	TrafficClassLexerSRCPORT    = 23
-	TrafficClassLexerDSTPORT    = 24
-	TrafficClassLexerSTRING     = 25
+	TrafficClassLexerNET6       = 14
+	TrafficClassLexerANY        = 15
+	TrafficClassLexerALL        = 16
+	TrafficClassLexerNOT        = 17
+	TrafficClassLexerBOOL       = 18
+	TrafficClassLexerSRC        = 19
+	TrafficClassLexerDST        = 20
+	TrafficClassLexerDSCP       = 21
+	TrafficClassLexerTOS        = 22
+	TrafficClassLexerPROTOCOL   = 23
+	TrafficClassLexerSRCPORT    = 24
+	TrafficClassLexerDSTPORT    = 25
+	TrafficClassLexerTC         = 26
+	TrafficClassLexerFLOWLABEL  = 27
+	TrafficClassLexerNEXTHDR    = 28
+	TrafficClassLexerICMPTYPE   = 29
+	TrafficClassLexerSTRING     = 30
 )
@@ -22,6 +22,21 @@ type TrafficClassListener interface {
 	// EnterMatchProtocol is called when entering the matchProtocol production.
 	EnterMatchProtocol(c *MatchProtocolContext)
 
+	// EnterMatchSrcIPv6 is called when entering the matchSrcIPv6 production.
+	EnterMatchSrcIPv6(c *MatchSrcIPv6Context)
+
+	// EnterMatchDstIPv6 is called when entering the matchDstIPv6 production.
+	EnterMatchDstIPv6(c *MatchDstIPv6Context)
+
+	// EnterMatchTC is called when entering the matchTC production.
+	EnterMatchTC(c *MatchTCContext)
+
+	// EnterMatchFlowLabel is called when entering the matchFlowLabel production.
+	EnterMatchFlowLabel(c *MatchFlowLabelContext)
+
+	// EnterMatchNextHdr is called when entering the matchNextHdr production.
+	EnterMatchNextHdr(c *MatchNextHdrContext)
+
 	// EnterMatchSrcPort is called when entering the matchSrcPort production.
 	EnterMatchSrcPort(c *MatchSrcPortContext)
 
@@ -34,6 +49,9 @@ type TrafficClassListener interface {
 	// EnterMatchDstPortRange is called when entering the matchDstPortRange production.
 	EnterMatchDstPortRange(c *MatchDstPortRangeContext)
 
+	// EnterMatchICMPType is called when entering the matchICMPType production.
+	EnterMatchICMPType(c *MatchICMPTypeContext)
+
 	// EnterCondCls is called when entering the condCls production.
 	EnterCondCls(c *CondClsContext)
 
@@ -52,9 +70,15 @@ type TrafficClassListener interface {
 	// EnterCondIPv4 is called when entering the condIPv4 production.
 	EnterCondIPv4(c *CondIPv4Context)
 
+	// EnterCondIPv6 is called when entering the condIPv6 production.
+	EnterCondIPv6(c *CondIPv6Context)
+
 	// EnterCondPort is called when entering the condPort production.
 	EnterCondPort(c *CondPortContext)
 
+	// EnterCondICMP is called when entering the condICMP production.
+	EnterCondICMP(c *CondICMPContext)
+
 	// EnterCond is called when entering the cond production.
 	EnterCond(c *CondContext)
 
@@ -76,6 +100,21 @@ type TrafficClassListener interface {
 	// ExitMatchProtocol is called when exiting the matchProtocol production.
 	ExitMatchProtocol(c *MatchProtocolContext)
 
+	// ExitMatchSrcIPv6 is called when exiting the matchSrcIPv6 production.
+	ExitMatchSrcIPv6(c *MatchSrcIPv6Context)
+
+	// ExitMatchDstIPv6 is called when exiting the matchDstIPv6 production.
+	ExitMatchDstIPv6(c *MatchDstIPv6Context)
+
+	// ExitMatchTC is called when exiting the matchTC production.
+	ExitMatchTC(c *MatchTCContext)
+
+	// ExitMatchFlowLabel is called when exiting the matchFlowLabel production.
+	ExitMatchFlowLabel(c *MatchFlowLabelContext)
+
+	// ExitMatchNextHdr is called when exiting the matchNextHdr production.
+	ExitMatchNextHdr(c *MatchNextHdrContext)
+
 	// ExitMatchSrcPort is called when exiting the matchSrcPort production.
 	ExitMatchSrcPort(c *MatchSrcPortContext)
 
@@ -88,6 +127,9 @@ type TrafficClassListener interface {
 	// ExitMatchDstPortRange is called when exiting the matchDstPortRange production.
 	ExitMatchDstPortRange(c *MatchDstPortRangeContext)
 
+	// ExitMatchICMPType is called when exiting the matchICMPType production.
+	ExitMatchICMPType(c *MatchICMPTypeContext)
+
 	// ExitCondCls is called when exiting the condCls production.
 	ExitCondCls(c *CondClsContext)
 
@@ -106,9 +148,15 @@ type TrafficClassListener interface {
 	// ExitCondIPv4 is called when exiting the condIPv4 production.
 	ExitCondIPv4(c *CondIPv4Context)
 
+	// ExitCondIPv6 is called when exiting the condIPv6 production.
+	ExitCondIPv6(c *CondIPv6Context)
+
 	// ExitCondPort is called when exiting the condPort production.
 	ExitCondPort(c *CondPortContext)
 
+	// ExitCondICMP is called when exiting the condICMP production.
+	ExitCondICMP(c *CondICMPContext)
+
 	// ExitCond is called when exiting the cond production.
 	ExitCond(c *CondContext)
 
@@ -15,76 +15,100 @@ var _ = reflect.Copy
 var _ = strconv.Itoa
 
 var parserATN = []uint16{
-	3, 24715, 42794, 33075, 47597, 16764, 15335, 30598, 22884, 3, 27, 140,
+	3, 24715, 42794, 33075, 47597, 16764, 15335, 30598, 22884, 3, 32, 191,
 	4, 2, 9, 2, 4, 3, 9, 3, 4, 4, 9, 4, 4, 5, 9, 5, 4, 6, 9, 6, 4, 7, 9, 7,
 	4, 8, 9, 8, 4, 9, 9, 9, 4, 10, 9, 10, 4, 11, 9, 11, 4, 12, 9, 12, 4, 13,
 	9, 13, 4, 14, 9, 14, 4, 15, 9, 15, 4, 16, 9, 16, 4, 17, 9, 17, 4, 18, 9,
-	18, 4, 19, 9, 19, 3, 2, 3, 2, 3, 2, 3, 2, 3, 3, 3, 3, 3, 3, 3, 3, 3, 4,
-	3, 4, 3, 4, 3, 4, 3, 5, 3, 5, 3, 5, 3, 5, 3, 6, 3, 6, 3, 6, 3, 6, 3, 7,
-	3, 7, 3, 7, 3, 7, 3, 8, 3, 8, 3, 8, 3, 8, 3, 8, 3, 8, 3, 9, 3, 9, 3, 9,
-	3, 9, 3, 10, 3, 10, 3, 10, 3, 10, 3, 10, 3, 10, 3, 11, 3, 11, 3, 11, 3,
-	12, 3, 12, 3, 12, 3, 12, 3, 12, 7, 12, 87, 10, 12, 12, 12, 14, 12, 90,
-	11, 12, 3, 12, 3, 12, 3, 13, 3, 13, 3, 13, 3, 13, 3, 13, 7, 13, 99, 10,
-	13, 12, 13, 14, 13, 102, 11, 13, 3, 13, 3, 13, 3, 14, 3, 14, 3, 14, 3,
-	14, 3, 14, 3, 15, 3, 15, 3, 15, 3, 15, 3, 16, 3, 16, 3, 16, 3, 16, 3, 16,
-	5, 16, 120, 10, 16, 3, 17, 3, 17, 3, 17, 3, 17, 5, 17, 126, 10, 17, 3,
-	18, 3, 18, 3, 18, 3, 18, 3, 18, 3, 18, 3, 18, 5, 18, 135, 10, 18, 3, 19,
-	3, 19, 3, 19, 3, 19, 2, 2, 20, 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22,
-	24, 26, 28, 30, 32, 34, 36, 2, 4, 3, 2, 13, 14, 3, 2, 10, 11, 2, 136, 2,
-	38, 3, 2, 2, 2, 4, 42, 3, 2, 2, 2, 6, 46, 3, 2, 2, 2, 8, 50, 3, 2, 2, 2,
-	10, 54, 3, 2, 2, 2, 12, 58, 3, 2, 2, 2, 14, 62, 3, 2, 2, 2, 16, 68, 3,
-	2, 2, 2, 18, 72, 3, 2, 2, 2, 20, 78, 3, 2, 2, 2, 22, 81, 3, 2, 2, 2, 24,
-	93, 3, 2, 2, 2, 26, 105, 3, 2, 2, 2, 28, 110, 3, 2, 2, 2, 30, 119, 3, 2,
-	2, 2, 32, 125, 3, 2, 2, 2, 34, 134, 3, 2, 2, 2, 36, 136, 3, 2, 2, 2, 38,
-	39, 7, 20, 2, 2, 39, 40, 7, 3, 2, 2, 40, 41, 7, 15, 2, 2, 41, 3, 3, 2,
-	2, 2, 42, 43, 7, 21, 2, 2, 43, 44, 7, 3, 2, 2, 44, 45, 7, 15, 2, 2, 45,
-	5, 3, 2, 2, 2, 46, 47, 7, 22, 2, 2, 47, 48, 7, 4, 2, 2, 48, 49, 9, 2, 2,
-	2, 49, 7, 3, 2, 2, 2, 50, 51, 7, 23, 2, 2, 51, 52, 7, 4, 2, 2, 52, 53,
-	9, 2, 2, 2, 53, 9, 3, 2, 2, 2, 54, 55, 7, 24, 2, 2, 55, 56, 7, 3, 2, 2,
-	56, 57, 7, 27, 2, 2, 57, 11, 3, 2, 2, 2, 58, 59, 7, 25, 2, 2, 59, 60, 7,
-	3, 2, 2, 60, 61, 7, 13, 2, 2, 61, 13, 3, 2, 2, 2, 62, 63, 7, 25, 2, 2,
-	63, 64, 7, 3, 2, 2, 64, 65, 7, 13, 2, 2, 65, 66, 7, 5, 2, 2, 66, 67, 7,
-	13, 2, 2, 67, 15, 3, 2, 2, 2, 68, 69, 7, 26, 2, 2, 69, 70, 7, 3, 2, 2,
-	70, 71, 7, 13, 2, 2, 71, 17, 3, 2, 2, 2, 72, 73, 7, 26, 2, 2, 73, 74, 7,
-	3, 2, 2, 74, 75, 7, 13, 2, 2, 75, 76, 7, 5, 2, 2, 76, 77, 7, 13, 2, 2,
-	77, 19, 3, 2, 2, 2, 78, 79, 7, 6, 2, 2, 79, 80, 7, 13, 2, 2, 80, 21, 3,
-	2, 2, 2, 81, 82, 7, 16, 2, 2, 82, 83, 7, 7, 2, 2, 83, 88, 5, 34, 18, 2,
-	84, 85, 7, 8, 2, 2, 85, 87, 5, 34, 18, 2, 86, 84, 3, 2, 2, 2, 87, 90, 3,
-	2, 2, 2, 88, 86, 3, 2, 2, 2, 88, 89, 3, 2, 2, 2, 89, 91, 3, 2, 2, 2, 90,
-	88, 3, 2, 2, 2, 91, 92, 7, 9, 2, 2, 92, 23, 3, 2, 2, 2, 93, 94, 7, 17,
-	2, 2, 94, 95, 7, 7, 2, 2, 95, 100, 5, 34, 18, 2, 96, 97, 7, 8, 2, 2, 97,
-	99, 5, 34, 18, 2, 98, 96, 3, 2, 2, 2, 99, 102, 3, 2, 2, 2, 100, 98, 3,
-	2, 2, 2, 100, 101, 3, 2, 2, 2, 101, 103, 3, 2, 2, 2, 102, 100, 3, 2, 2,
-	2, 103, 104, 7, 9, 2, 2, 104, 25, 3, 2, 2, 2, 105, 106, 7, 18, 2, 2, 106,
-	107, 7, 7, 2, 2, 107, 108, 5, 34, 18, 2, 108, 109, 7, 9, 2, 2, 109, 27,
-	3, 2, 2, 2, 110, 111, 7, 19, 2, 2, 111, 112, 7, 3, 2, 2, 112, 113, 9, 3,
-	2, 2, 113, 29, 3, 2, 2, 2, 114, 120, 5, 2, 2, 2, 115, 120, 5, 4, 3, 2,
-	116, 120, 5, 6, 4, 2, 117, 120, 5, 8, 5, 2, 118, 120, 5, 10, 6, 2, 119,
-	114, 3, 2, 2, 2, 119, 115, 3, 2, 2, 2, 119, 116, 3, 2, 2, 2, 119, 117,
-	3, 2, 2, 2, 119, 118, 3, 2, 2, 2, 120, 31, 3, 2, 2, 2, 121, 126, 5, 12,
-	7, 2, 122, 126, 5, 14, 8, 2, 123, 126, 5, 16, 9, 2, 124, 126, 5, 18, 10,
-	2, 125, 121, 3, 2, 2, 2, 125, 122, 3, 2, 2, 2, 125, 123, 3, 2, 2, 2, 125,
-	124, 3, 2, 2, 2, 126, 33, 3, 2, 2, 2, 127, 135, 5, 24, 13, 2, 128, 135,
-	5, 22, 12, 2, 129, 135, 5, 26, 14, 2, 130, 135, 5, 30, 16, 2, 131, 135,
-	5, 32, 17, 2, 132, 135, 5, 20, 11, 2, 133, 135, 5, 28, 15, 2, 134, 127,
-	3, 2, 2, 2, 134, 128, 3, 2, 2, 2, 134, 129, 3, 2, 2, 2, 134, 130, 3, 2,
-	2, 2, 134, 131, 3, 2, 2, 2, 134, 132, 3, 2, 2, 2, 134, 133, 3, 2, 2, 2,
-	135, 35, 3, 2, 2, 2, 136, 137, 5, 34, 18, 2, 137, 138, 7, 2, 2, 3, 138,
-	37, 3, 2, 2, 2, 7, 88, 100, 119, 125, 134,
+	18, 4, 19, 9, 19, 4, 20, 9, 20, 4, 21, 9, 21, 4, 22, 9, 22, 4, 23, 9, 23,
+	4, 24, 9, 24, 4, 25, 9, 25, 4, 26, 9, 26, 4, 27, 9, 27, 3, 2, 3, 2, 3,
+	2, 3, 2, 3, 3, 3, 3, 3, 3, 3, 3, 3, 4, 3, 4, 3, 4, 3, 4, 3, 5, 3, 5, 3,
+	5, 3, 5, 3, 6, 3, 6, 3, 6, 3, 6, 3, 7, 3, 7, 3, 7, 3, 7, 3, 8, 3, 8, 3,
+	8, 3, 8, 3, 9, 3, 9, 3, 9, 3, 9, 3, 10, 3, 10, 3, 10, 3, 10, 3, 11, 3,
+	11, 3, 11, 3, 11, 3, 12, 3, 12, 3, 12, 3, 12, 3, 13, 3, 13, 3, 13, 3, 13,
+	3, 13, 3, 13, 3, 14, 3, 14, 3, 14, 3, 14, 3, 15, 3, 15, 3, 15, 3, 15, 3,
+	15, 3, 15, 3, 16, 3, 16, 3, 16, 3, 16, 3, 17, 3, 17, 3, 17, 3, 18, 3, 18,
+	3, 18, 3, 18, 3, 18, 7, 18, 127, 10, 18, 12, 18, 14, 18, 130, 11, 18, 3,
+	18, 3, 18, 3, 19, 3, 19, 3, 19, 3, 19, 3, 19, 7, 19, 139, 10, 19, 12, 19,
+	14, 19, 142, 11, 19, 3, 19, 3, 19, 3, 20, 3, 20, 3, 20, 3, 20, 3, 20, 3,
+	21, 3, 21, 3, 21, 3, 21, 3, 22, 3, 22, 3, 22, 3, 22, 3, 22, 5, 22, 160,
+	10, 22, 3, 23, 3, 23, 3, 23, 3, 23, 3, 23, 5, 23, 167, 10, 23, 3, 24, 3,
+	24, 3, 24, 3, 24, 5, 24, 173, 10, 24, 3, 25, 3, 25, 3, 26, 3, 26, 3, 26,
+	3, 26, 3, 26, 3, 26, 3, 26, 3, 26, 3, 26, 5, 26, 186, 10, 26, 3, 27, 3,
+	27, 3, 27, 3, 27, 2, 2, 28, 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24,
+	26, 28, 30, 32, 34, 36, 38, 40, 42, 44, 46, 48, 50, 52, 2, 4, 3, 2, 13,
+	14, 3, 2, 10, 11, 2, 185, 2, 54, 3, 2, 2, 2, 4, 58, 3, 2, 2, 2, 6, 62,
+	3, 2, 2, 2, 8, 66, 3, 2, 2, 2, 10, 70, 3, 2, 2, 2, 12, 74, 3, 2, 2, 2,
+	14, 78, 3, 2, 2, 2, 16, 82, 3, 2, 2, 2, 18, 86, 3, 2, 2, 2, 20, 90, 3,
+	2, 2, 2, 22, 94, 3, 2, 2, 2, 24, 98, 3, 2, 2, 2, 26, 104, 3, 2, 2, 2, 28,
+	108, 3, 2, 2, 2, 30, 114, 3, 2, 2, 2, 32, 118, 3, 2, 2, 2, 34, 121, 3,
+	2, 2, 2, 36, 133, 3, 2, 2, 2, 38, 145, 3, 2, 2, 2, 40, 150, 3, 2, 2, 2,
+	42, 159, 3, 2, 2, 2, 44, 166, 3, 2, 2, 2, 46, 172, 3, 2, 2, 2, 48, 174,
+	3, 2, 2, 2, 50, 185, 3, 2, 2, 2, 52, 187, 3, 2, 2, 2, 54, 55, 7, 21, 2,
+	2, 55, 56, 7, 3, 2, 2, 56, 57, 7, 15, 2, 2, 57, 3, 3, 2, 2, 2, 58, 59,
+	7, 22, 2, 2, 59, 60, 7, 3, 2, 2, 60, 61, 7, 15, 2, 2, 61, 5, 3, 2, 2, 2,
+	62, 63, 7, 23, 2, 2, 63, 64, 7, 4, 2, 2, 64, 65, 9, 2, 2, 2, 65, 7, 3,
+	2, 2, 2, 66, 67, 7, 24, 2, 2, 67, 68, 7, 4, 2, 2, 68, 69, 9, 2, 2, 2, 69,
+	9, 3, 2, 2, 2, 70, 71, 7, 25, 2, 2, 71, 72, 7, 3, 2, 2, 72, 73, 7, 32,
+	2, 2, 73, 11, 3, 2, 2, 2, 74, 75, 7, 21, 2, 2, 75, 76, 7, 3, 2, 2, 76,
+	77, 7, 16, 2, 2, 77, 13, 3, 2, 2, 2, 78, 79, 7, 22, 2, 2, 79, 80, 7, 3,
+	2, 2, 80, 81, 7, 16, 2, 2, 81, 15, 3, 2, 2, 2, 82, 83, 7, 28, 2, 2, 83,
+	84, 7, 4, 2, 2, 84, 85, 9, 2, 2, 2, 85, 17, 3, 2, 2, 2, 86, 87, 7, 29,
+	2, 2, 87, 88, 7, 4, 2, 2, 88, 89, 9, 2, 2, 2, 89, 19, 3, 2, 2, 2, 90, 91,
+	7, 30, 2, 2, 91, 92, 7, 3, 2, 2, 92, 93, 7, 32, 2, 2, 93, 21, 3, 2, 2,
+	2, 94, 95, 7, 26, 2, 2, 95, 96, 7, 3, 2, 2, 96, 97, 7, 13, 2, 2, 97, 23,
+	3, 2, 2, 2, 98, 99, 7, 26, 2, 2, 99, 100, 7, 3, 2, 2, 100, 101, 7, 13,
+	2, 2, 101, 102, 7, 5, 2, 2, 102, 103, 7, 13, 2, 2, 103, 25, 3, 2, 2, 2,
+	104, 105, 7, 27, 2, 2, 105, 106, 7, 3, 2, 2, 106, 107, 7, 13, 2, 2, 107,
+	27, 3, 2, 2, 2, 108, 109, 7, 27, 2, 2, 109, 110, 7, 3, 2, 2, 110, 111,
+	7, 13, 2, 2, 111, 112, 7, 5, 2, 2, 112, 113, 7, 13, 2, 2, 113, 29, 3, 2,
+	2, 2, 114, 115, 7, 31, 2, 2, 115, 116, 7, 3, 2, 2, 116, 117, 7, 13, 2,
+	2, 117, 31, 3, 2, 2, 2, 118, 119, 7, 6, 2, 2, 119, 120, 7, 13, 2, 2, 120,
+	33, 3, 2, 2, 2, 121, 122, 7, 17, 2, 2, 122, 123, 7, 7, 2, 2, 123, 128,
+	5, 50, 26, 2, 124, 125, 7, 8, 2, 2, 125, 127, 5, 50, 26, 2, 126, 124, 3,
+	2, 2, 2, 127, 130, 3, 2, 2, 2, 128, 126, 3, 2, 2, 2, 128, 129, 3, 2, 2,
+	2, 129, 131, 3, 2, 2, 2, 130, 128, 3, 2, 2, 2, 131, 132, 7, 9, 2, 2, 132,
+	35, 3, 2, 2, 2, 133, 134, 7, 18, 2, 2, 134, 135, 7, 7, 2, 2, 135, 140,
+	5, 50, 26, 2, 136, 137, 7, 8, 2, 2, 137, 139, 5, 50, 26, 2, 138, 136, 3,
+	2, 2, 2, 139, 142, 3, 2, 2, 2, 140, 138, 3, 2, 2, 2, 140, 141, 3, 2, 2,
+	2, 141, 143, 3, 2, 2, 2, 142, 140, 3, 2, 2, 2, 143, 144, 7, 9, 2, 2, 144,
+	37, 3, 2, 2, 2, 145, 146, 7, 19, 2, 2, 146, 147, 7, 7, 2, 2, 147, 148,
+	5, 50, 26, 2, 148, 149, 7, 9, 2, 2, 149, 39, 3, 2, 2, 2, 150, 151, 7, 20,
+	2, 2, 151, 152, 7, 3, 2, 2, 152, 153, 9, 3, 2, 2, 153, 41, 3, 2, 2, 2,
+	154, 160, 5, 2, 2, 2, 155, 160, 5, 4, 3, 2, 156, 160, 5, 6, 4, 2, 157,
+	160, 5, 8, 5, 2, 158, 160, 5, 10, 6, 2, 159, 154, 3, 2, 2, 2, 159, 155,
+	3, 2, 2, 2, 159, 156, 3, 2, 2, 2, 159, 157, 3, 2, 2, 2, 159, 158, 3, 2,
+	2, 2, 160, 43, 3, 2, 2, 2, 161, 167, 5, 12, 7, 2, 162, 167, 5, 14, 8, 2,
+	163, 167, 5, 16, 9, 2, 164, 167, 5, 18, 10, 2, 165, 167, 5, 20, 11, 2,
+	166, 161, 3, 2, 2, 2, 166, 162, 3, 2, 2, 2, 166, 163, 3, 2, 2, 2, 166,
+	164, 3, 2, 2, 2, 166, 165, 3, 2, 2, 2, 167, 45, 3, 2, 2, 2, 168, 173, 5,
+	22, 12, 2, 169, 173, 5, 24, 13, 2, 170, 173, 5, 26, 14, 2, 171, 173, 5,
+	28, 15, 2, 172, 168, 3, 2, 2, 2, 172, 169, 3, 2, 2, 2, 172, 170, 3, 2,
+	2, 2, 172, 171, 3, 2, 2, 2, 173, 47, 3, 2, 2, 2, 174, 175, 5, 30, 16, 2,
+	175, 49, 3, 2, 2, 2, 176, 186, 5, 36, 19, 2, 177, 186, 5, 34, 18, 2, 178,
+	186, 5, 38, 20, 2, 179, 186, 5, 42, 22, 2, 180, 186, 5, 44, 23, 2, 181,
+	186, 5, 46, 24, 2, 182, 186, 5, 48, 25, 2, 183, 186, 5, 32, 17, 2, 184,
+	186, 5, 40, 21, 2, 185, 176, 3, 2, 2, 2, 185, 177, 3, 2, 2, 2, 185, 178,
+	3, 2, 2, 2, 185, 179, 3, 2, 2, 2, 185, 180, 3, 2, 2, 2, 185, 181, 3, 2,
+	2, 2, 185, 182, 3, 2, 2, 2, 185, 183, 3, 2, 2, 2, 185, 184, 3, 2, 2, 2,
+	186, 51, 3, 2, 2, 2, 187, 188, 5, 50, 26, 2, 188, 189, 7, 2, 2, 3, 189,
+	53, 3, 2, 2, 2, 8, 128, 140, 159, 166, 172, 185,
 }
 var literalNames = []string{
 	"", "'='", "'=0x'", "'-'", "'cls='", "'('", "','", "')'", "'true'", "'false'",
 }
 var symbolicNames = []string{
 	"", "", "", "", "", "", "", "", "", "", "WHITESPACE", "DIGITS", "HEX_DIGITS",
-	"NET", "ANY", "ALL", "NOT", "BOOL", "SRC", "DST", "DSCP", "TOS", "PROTOCOL",
-	"SRCPORT", "DSTPORT", "STRING",
+	"NET", "NET6", "ANY", "ALL", "NOT", "BOOL", "SRC", "DST", "DSCP", "TOS",
+	"PROTOCOL", "SRCPORT", "DSTPORT", "TC", "FLOWLABEL", "NEXTHDR", "ICMPTYPE",
+	"STRING",
 }
 
 var ruleNames = []string{
-	"matchSrc", "matchDst", "matchDSCP", "matchTOS", "matchProtocol", "matchSrcPort",
-	"matchSrcPortRange", "matchDstPort", "matchDstPortRange", "condCls", "condAny",
-	"condAll", "condNot", "condBool", "condIPv4", "condPort", "cond", "trafficClass",
+	"matchSrc", "matchDst", "matchDSCP", "matchTOS", "matchProtocol", "matchSrcIPv6",
+	"matchDstIPv6", "matchTC", "matchFlowLabel", "matchNextHdr", "matchSrcPort",
+	"matchSrcPortRange", "matchDstPort", "matchDstPortRange", "matchICMPType",
+	"condCls", "condAny", "condAll", "condNot", "condBool", "condIPv4", "condIPv6",
+	"condPort", "condICMP", "cond", "trafficClass",
 }
 
 type TrafficClassParser struct {
@@ -132,18 +156,23 @@ const (
 	TrafficClassParserDIGITS     = 11
 	TrafficClassParserHEX_DIGITS = 12
 	TrafficClassParserNET        = 13
-	TrafficClassParserANY        = 14
-	TrafficClassParserALL        = 15
-	TrafficClassParserNOT        = 16
-	TrafficClassParserBOOL       = 17
-	TrafficClassParserSRC        = 18
-	TrafficClassParserDST        = 19
-	TrafficClassParserDSCP       = 20
-	TrafficClassParserTOS        = 21
-	TrafficClassParserPROTOCOL   = 22
-	TrafficClassParserSRCPORT    = 23
-	TrafficClassParserDSTPORT    = 24
-	TrafficClassParserSTRING     = 25
+	TrafficClassParserNET6       = 14
+	TrafficClassParserANY        = 15
+	TrafficClassParserALL        = 16
+	TrafficClassParserNOT        = 17
+	TrafficClassParserBOOL       = 18
+	TrafficClassParserSRC        = 19
+	TrafficClassParserDST        = 20
+	TrafficClassParserDSCP       = 21
+	TrafficClassParserTOS        = 22
+	TrafficClassParserPROTOCOL   = 23
+	TrafficClassParserSRCPORT    = 24
+	TrafficClassParserDSTPORT    = 25
+	TrafficClassParserTC         = 26
+	TrafficClassParserFLOWLABEL  = 27
+	TrafficClassParserNEXTHDR    = 28
+	TrafficClassParserICMPTYPE   = 29
+	TrafficClassParserSTRING     = 30
 )
 
 // TrafficClassParser rules.
@@ -153,19 +182,27 @@ const (
 	TrafficClassParserRULE_matchDSCP         = 2
 	TrafficClassParserRULE_matchTOS          = 3
 	TrafficClassParserRULE_matchProtocol     = 4
-	TrafficClassParserRULE_matchSrcPort      = 5
-	TrafficClassParserRULE_matchSrcPortRange = 6
-	TrafficClassParserRULE_matchDstPort      = 7
-	TrafficClassParserRULE_matchDstPortRange = 8
-	TrafficClassParserRULE_condCls           = 9
-	TrafficClassParserRULE_condAny           = 10
-	TrafficClassParserRULE_condAll           = 11
-	TrafficClassParserRULE_condNot           = 12
-	TrafficClassParserRULE_condBool          = 13
-	TrafficClassParserRULE_condIPv4          = 14
-	TrafficClassParserRULE_condPort          = 15
-	TrafficClassParserRULE_cond              = 16
-	TrafficClassParserRULE_trafficClass      = 17
+	TrafficClassParserRULE_matchSrcIPv6      = 5
+	TrafficClassParserRULE_matchDstIPv6      = 6
+	TrafficClassParserRULE_matchTC           = 7
+	TrafficClassParserRULE_matchFlowLabel    = 8
+	TrafficClassParserRULE_matchNextHdr      = 9
+	TrafficClassParserRULE_matchSrcPort      = 10
+	TrafficClassParserRULE_matchSrcPortRange = 11
+	TrafficClassParserRULE_matchDstPort      = 12
+	TrafficClassParserRULE_matchDstPortRange = 13
+	TrafficClassParserRULE_matchICMPType     = 14
+	TrafficClassParserRULE_condCls           = 15
+	TrafficClassParserRULE_condAny           = 16
+	TrafficClassParserRULE_condAll           = 17
+	TrafficClassParserRULE_condNot           = 18
+	TrafficClassParserRULE_condBool          = 19
+	TrafficClassParserRULE_condIPv4          = 20
+	TrafficClassParserRULE_condIPv6          = 21
+	TrafficClassParserRULE_condPort          = 22
+	TrafficClassParserRULE_condICMP          = 23
+	TrafficClassParserRULE_cond              = 24
+	TrafficClassParserRULE_trafficClass      = 25
 )
 
 // IMatchSrcContext is an interface to support dynamic dispatch.
@@ -259,93 +296,647 @@ func (p *TrafficClassParser) MatchSrc() (localctx IMatchSrcContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(36)
+		p.SetState(52)
+		p.Match(TrafficClassParserSRC)
+	}
+	{
+		p.SetState(53)
+		p.Match(TrafficClassParserT__0)
+	}
+	{
+		p.SetState(54)
+		p.Match(TrafficClassParserNET)
+	}
+
+	return localctx
+}
+
+// IMatchDstContext is an interface to support dynamic dispatch.
+type IMatchDstContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// IsMatchDstContext differentiates from other interfaces.
+	IsMatchDstContext()
+}
+
+type MatchDstContext struct {
+	*antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyMatchDstContext() *MatchDstContext {
+	var p = new(MatchDstContext)
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(nil, -1)
+	p.RuleIndex = TrafficClassParserRULE_matchDst
+	return p
+}
+
+func (*MatchDstContext) IsMatchDstContext() {}
+
+func NewMatchDstContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MatchDstContext {
+	var p = new(MatchDstContext)
+
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = TrafficClassParserRULE_matchDst
+
+	return p
+}
+
+func (s *MatchDstContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *MatchDstContext) DST() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserDST, 0)
+}
+
+func (s *MatchDstContext) NET() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserNET, 0)
+}
+
+func (s *MatchDstContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *MatchDstContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *MatchDstContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(TrafficClassListener); ok {
+		listenerT.EnterMatchDst(s)
+	}
+}
+
+func (s *MatchDstContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(TrafficClassListener); ok {
+		listenerT.ExitMatchDst(s)
+	}
+}
+
+func (p *TrafficClassParser) MatchDst() (localctx IMatchDstContext) {
+	this := p
+	_ = this
+
+	localctx = NewMatchDstContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 2, TrafficClassParserRULE_matchDst)
+
+	defer func() {
+		p.ExitRule()
+	}()
+
+	defer func() {
+		if err := recover(); err != nil {
+			if v, ok := err.(antlr.RecognitionException); ok {
+				localctx.SetException(v)
+				p.GetErrorHandler().ReportError(p, v)
+				p.GetErrorHandler().Recover(p, v)
+			} else {
+				panic(err)
+			}
+		}
+	}()
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(56)
+		p.Match(TrafficClassParserDST)
+	}
+	{
+		p.SetState(57)
+		p.Match(TrafficClassParserT__0)
+	}
+	{
+		p.SetState(58)
+		p.Match(TrafficClassParserNET)
+	}
+
+	return localctx
+}
+
+// IMatchDSCPContext is an interface to support dynamic dispatch.
+type IMatchDSCPContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// IsMatchDSCPContext differentiates from other interfaces.
+	IsMatchDSCPContext()
+}
+
+type MatchDSCPContext struct {
+	*antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyMatchDSCPContext() *MatchDSCPContext {
+	var p = new(MatchDSCPContext)
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(nil, -1)
+	p.RuleIndex = TrafficClassParserRULE_matchDSCP
+	return p
+}
+
+func (*MatchDSCPContext) IsMatchDSCPContext() {}
+
+func NewMatchDSCPContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MatchDSCPContext {
+	var p = new(MatchDSCPContext)
+
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = TrafficClassParserRULE_matchDSCP
+
+	return p
+}
+
+func (s *MatchDSCPContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *MatchDSCPContext) DSCP() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserDSCP, 0)
+}
+
+func (s *MatchDSCPContext) HEX_DIGITS() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserHEX_DIGITS, 0)
+}
+
+func (s *MatchDSCPContext) DIGITS() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserDIGITS, 0)
+}
+
+func (s *MatchDSCPContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *MatchDSCPContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *MatchDSCPContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(TrafficClassListener); ok {
+		listenerT.EnterMatchDSCP(s)
+	}
+}
+
+func (s *MatchDSCPContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(TrafficClassListener); ok {
+		listenerT.ExitMatchDSCP(s)
+	}
+}
+
+func (p *TrafficClassParser) MatchDSCP() (localctx IMatchDSCPContext) {
+	this := p
+	_ = this
+
+	localctx = NewMatchDSCPContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 4, TrafficClassParserRULE_matchDSCP)
+	var _la int
+
+	defer func() {
+		p.ExitRule()
+	}()
+
+	defer func() {
+		if err := recover(); err != nil {
+			if v, ok := err.(antlr.RecognitionException); ok {
+				localctx.SetException(v)
+				p.GetErrorHandler().ReportError(p, v)
+				p.GetErrorHandler().Recover(p, v)
+			} else {
+				panic(err)
+			}
+		}
+	}()
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(60)
+		p.Match(TrafficClassParserDSCP)
+	}
+	{
+		p.SetState(61)
+		p.Match(TrafficClassParserT__1)
+	}
+	{
+		p.SetState(62)
+		_la = p.GetTokenStream().LA(1)
+
+		if !(_la == TrafficClassParserDIGITS || _la == TrafficClassParserHEX_DIGITS) {
+			p.GetErrorHandler().RecoverInline(p)
+		} else {
+			p.GetErrorHandler().ReportMatch(p)
+			p.Consume()
+		}
+	}
+
+	return localctx
+}
+
+// IMatchTOSContext is an interface to support dynamic dispatch.
+type IMatchTOSContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// IsMatchTOSContext differentiates from other interfaces.
+	IsMatchTOSContext()
+}
+
+type MatchTOSContext struct {
+	*antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyMatchTOSContext() *MatchTOSContext {
+	var p = new(MatchTOSContext)
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(nil, -1)
+	p.RuleIndex = TrafficClassParserRULE_matchTOS
+	return p
+}
+
+func (*MatchTOSContext) IsMatchTOSContext() {}
+
+func NewMatchTOSContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MatchTOSContext {
+	var p = new(MatchTOSContext)
+
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = TrafficClassParserRULE_matchTOS
+
+	return p
+}
+
+func (s *MatchTOSContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *MatchTOSContext) TOS() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserTOS, 0)
+}
+
+func (s *MatchTOSContext) HEX_DIGITS() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserHEX_DIGITS, 0)
+}
+
+func (s *MatchTOSContext) DIGITS() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserDIGITS, 0)
+}
+
+func (s *MatchTOSContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *MatchTOSContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *MatchTOSContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(TrafficClassListener); ok {
+		listenerT.EnterMatchTOS(s)
+	}
+}
+
+func (s *MatchTOSContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(TrafficClassListener); ok {
+		listenerT.ExitMatchTOS(s)
+	}
+}
+
+func (p *TrafficClassParser) MatchTOS() (localctx IMatchTOSContext) {
+	this := p
+	_ = this
+
+	localctx = NewMatchTOSContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 6, TrafficClassParserRULE_matchTOS)
+	var _la int
+
+	defer func() {
+		p.ExitRule()
+	}()
+
+	defer func() {
+		if err := recover(); err != nil {
+			if v, ok := err.(antlr.RecognitionException); ok {
+				localctx.SetException(v)
+				p.GetErrorHandler().ReportError(p, v)
+				p.GetErrorHandler().Recover(p, v)
+			} else {
+				panic(err)
+			}
+		}
+	}()
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(64)
+		p.Match(TrafficClassParserTOS)
+	}
+	{
+		p.SetState(65)
+		p.Match(TrafficClassParserT__1)
+	}
+	{
+		p.SetState(66)
+		_la = p.GetTokenStream().LA(1)
+
+		if !(_la == TrafficClassParserDIGITS || _la == TrafficClassParserHEX_DIGITS) {
+			p.GetErrorHandler().RecoverInline(p)
+		} else {
+			p.GetErrorHandler().ReportMatch(p)
+			p.Consume()
+		}
+	}
+
+	return localctx
+}
+
+// IMatchProtocolContext is an interface to support dynamic dispatch.
+type IMatchProtocolContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// IsMatchProtocolContext differentiates from other interfaces.
+	IsMatchProtocolContext()
+}
+
+type MatchProtocolContext struct {
+	*antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyMatchProtocolContext() *MatchProtocolContext {
+	var p = new(MatchProtocolContext)
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(nil, -1)
+	p.RuleIndex = TrafficClassParserRULE_matchProtocol
+	return p
+}
+
+func (*MatchProtocolContext) IsMatchProtocolContext() {}
+
+func NewMatchProtocolContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MatchProtocolContext {
+	var p = new(MatchProtocolContext)
+
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = TrafficClassParserRULE_matchProtocol
+
+	return p
+}
+
+func (s *MatchProtocolContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *MatchProtocolContext) PROTOCOL() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserPROTOCOL, 0)
+}
+
+func (s *MatchProtocolContext) STRING() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserSTRING, 0)
+}
+
+func (s *MatchProtocolContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *MatchProtocolContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *MatchProtocolContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(TrafficClassListener); ok {
+		listenerT.EnterMatchProtocol(s)
+	}
+}
+
+func (s *MatchProtocolContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(TrafficClassListener); ok {
+		listenerT.ExitMatchProtocol(s)
+	}
+}
+
+func (p *TrafficClassParser) MatchProtocol() (localctx IMatchProtocolContext) {
+	this := p
+	_ = this
+
+	localctx = NewMatchProtocolContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 8, TrafficClassParserRULE_matchProtocol)
+
+	defer func() {
+		p.ExitRule()
+	}()
+
+	defer func() {
+		if err := recover(); err != nil {
+			if v, ok := err.(antlr.RecognitionException); ok {
+				localctx.SetException(v)
+				p.GetErrorHandler().ReportError(p, v)
+				p.GetErrorHandler().Recover(p, v)
+			} else {
+				panic(err)
+			}
+		}
+	}()
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(68)
+		p.Match(TrafficClassParserPROTOCOL)
+	}
+	{
+		p.SetState(69)
+		p.Match(TrafficClassParserT__0)
+	}
+	{
+		p.SetState(70)
+		p.Match(TrafficClassParserSTRING)
+	}
+
+	return localctx
+}
+
+// IMatchSrcIPv6Context is an interface to support dynamic dispatch.
+type IMatchSrcIPv6Context interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// IsMatchSrcIPv6Context differentiates from other interfaces.
+	IsMatchSrcIPv6Context()
+}
+
+type MatchSrcIPv6Context struct {
+	*antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyMatchSrcIPv6Context() *MatchSrcIPv6Context {
+	var p = new(MatchSrcIPv6Context)
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(nil, -1)
+	p.RuleIndex = TrafficClassParserRULE_matchSrcIPv6
+	return p
+}
+
+func (*MatchSrcIPv6Context) IsMatchSrcIPv6Context() {}
+
+func NewMatchSrcIPv6Context(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MatchSrcIPv6Context {
+	var p = new(MatchSrcIPv6Context)
+
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = TrafficClassParserRULE_matchSrcIPv6
+
+	return p
+}
+
+func (s *MatchSrcIPv6Context) GetParser() antlr.Parser { return s.parser }
+
+func (s *MatchSrcIPv6Context) SRC() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserSRC, 0)
+}
+
+func (s *MatchSrcIPv6Context) NET6() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserNET6, 0)
+}
+
+func (s *MatchSrcIPv6Context) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *MatchSrcIPv6Context) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *MatchSrcIPv6Context) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(TrafficClassListener); ok {
+		listenerT.EnterMatchSrcIPv6(s)
+	}
+}
+
+func (s *MatchSrcIPv6Context) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(TrafficClassListener); ok {
+		listenerT.ExitMatchSrcIPv6(s)
+	}
+}
+
+func (p *TrafficClassParser) MatchSrcIPv6() (localctx IMatchSrcIPv6Context) {
+	this := p
+	_ = this
+
+	localctx = NewMatchSrcIPv6Context(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 10, TrafficClassParserRULE_matchSrcIPv6)
+
+	defer func() {
+		p.ExitRule()
+	}()
+
+	defer func() {
+		if err := recover(); err != nil {
+			if v, ok := err.(antlr.RecognitionException); ok {
+				localctx.SetException(v)
+				p.GetErrorHandler().ReportError(p, v)
+				p.GetErrorHandler().Recover(p, v)
+			} else {
+				panic(err)
+			}
+		}
+	}()
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(72)
 		p.Match(TrafficClassParserSRC)
 	}
 	{
-		p.SetState(37)
+		p.SetState(73)
 		p.Match(TrafficClassParserT__0)
 	}
 	{
-		p.SetState(38)
-		p.Match(TrafficClassParserNET)
+		p.SetState(74)
+		p.Match(TrafficClassParserNET6)
 	}
 
 	return localctx
 }
 
-// IMatchDstContext is an interface to support dynamic dispatch.
-type IMatchDstContext interface {
+// IMatchDstIPv6Context is an interface to support dynamic dispatch.
+type IMatchDstIPv6Context interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
-	// IsMatchDstContext differentiates from other interfaces.
-	IsMatchDstContext()
+	// IsMatchDstIPv6Context differentiates from other interfaces.
+	IsMatchDstIPv6Context()
 }
 
-type MatchDstContext struct {
+type MatchDstIPv6Context struct {
 	*antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyMatchDstContext() *MatchDstContext {
-	var p = new(MatchDstContext)
+func NewEmptyMatchDstIPv6Context() *MatchDstIPv6Context {
+	var p = new(MatchDstIPv6Context)
 	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(nil, -1)
-	p.RuleIndex = TrafficClassParserRULE_matchDst
+	p.RuleIndex = TrafficClassParserRULE_matchDstIPv6
 	return p
 }
 
-func (*MatchDstContext) IsMatchDstContext() {}
+func (*MatchDstIPv6Context) IsMatchDstIPv6Context() {}
 
-func NewMatchDstContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MatchDstContext {
-	var p = new(MatchDstContext)
+func NewMatchDstIPv6Context(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MatchDstIPv6Context {
+	var p = new(MatchDstIPv6Context)
 
 	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = TrafficClassParserRULE_matchDst
+	p.RuleIndex = TrafficClassParserRULE_matchDstIPv6
 
 	return p
 }
 
-func (s *MatchDstContext) GetParser() antlr.Parser { return s.parser }
+func (s *MatchDstIPv6Context) GetParser() antlr.Parser { return s.parser }
 
-func (s *MatchDstContext) DST() antlr.TerminalNode {
+func (s *MatchDstIPv6Context) DST() antlr.TerminalNode {
 	return s.GetToken(TrafficClassParserDST, 0)
 }
 
-func (s *MatchDstContext) NET() antlr.TerminalNode {
-	return s.GetToken(TrafficClassParserNET, 0)
+func (s *MatchDstIPv6Context) NET6() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserNET6, 0)
 }
 
-func (s *MatchDstContext) GetRuleContext() antlr.RuleContext {
+func (s *MatchDstIPv6Context) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *MatchDstContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *MatchDstIPv6Context) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *MatchDstContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *MatchDstIPv6Context) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(TrafficClassListener); ok {
-		listenerT.EnterMatchDst(s)
+		listenerT.EnterMatchDstIPv6(s)
 	}
 }
 
-func (s *MatchDstContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *MatchDstIPv6Context) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(TrafficClassListener); ok {
-		listenerT.ExitMatchDst(s)
+		listenerT.ExitMatchDstIPv6(s)
 	}
 }
 
-func (p *TrafficClassParser) MatchDst() (localctx IMatchDstContext) {
+func (p *TrafficClassParser) MatchDstIPv6() (localctx IMatchDstIPv6Context) {
 	this := p
 	_ = this
 
-	localctx = NewMatchDstContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 2, TrafficClassParserRULE_matchDst)
+	localctx = NewMatchDstIPv6Context(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 12, TrafficClassParserRULE_matchDstIPv6)
 
 	defer func() {
 		p.ExitRule()
@@ -365,97 +956,97 @@ func (p *TrafficClassParser) MatchDst() (localctx IMatchDstContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(40)
+		p.SetState(76)
 		p.Match(TrafficClassParserDST)
 	}
 	{
-		p.SetState(41)
+		p.SetState(77)
 		p.Match(TrafficClassParserT__0)
 	}
 	{
-		p.SetState(42)
-		p.Match(TrafficClassParserNET)
+		p.SetState(78)
+		p.Match(TrafficClassParserNET6)
 	}
 
 	return localctx
 }
 
-// IMatchDSCPContext is an interface to support dynamic dispatch.
-type IMatchDSCPContext interface {
+// IMatchTCContext is an interface to support dynamic dispatch.
+type IMatchTCContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
-	// IsMatchDSCPContext differentiates from other interfaces.
-	IsMatchDSCPContext()
+	// IsMatchTCContext differentiates from other interfaces.
+	IsMatchTCContext()
 }
 
-type MatchDSCPContext struct {
+type MatchTCContext struct {
 	*antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyMatchDSCPContext() *MatchDSCPContext {
-	var p = new(MatchDSCPContext)
+func NewEmptyMatchTCContext() *MatchTCContext {
+	var p = new(MatchTCContext)
 	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(nil, -1)
-	p.RuleIndex = TrafficClassParserRULE_matchDSCP
+	p.RuleIndex = TrafficClassParserRULE_matchTC
 	return p
 }
 
-func (*MatchDSCPContext) IsMatchDSCPContext() {}
+func (*MatchTCContext) IsMatchTCContext() {}
 
-func NewMatchDSCPContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MatchDSCPContext {
-	var p = new(MatchDSCPContext)
+func NewMatchTCContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MatchTCContext {
+	var p = new(MatchTCContext)
 
 	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = TrafficClassParserRULE_matchDSCP
+	p.RuleIndex = TrafficClassParserRULE_matchTC
 
 	return p
 }
 
-func (s *MatchDSCPContext) GetParser() antlr.Parser { return s.parser }
+func (s *MatchTCContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *MatchDSCPContext) DSCP() antlr.TerminalNode {
-	return s.GetToken(TrafficClassParserDSCP, 0)
+func (s *MatchTCContext) TC() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserTC, 0)
 }
 
-func (s *MatchDSCPContext) HEX_DIGITS() antlr.TerminalNode {
+func (s *MatchTCContext) HEX_DIGITS() antlr.TerminalNode {
 	return s.GetToken(TrafficClassParserHEX_DIGITS, 0)
 }
 
-func (s *MatchDSCPContext) DIGITS() antlr.TerminalNode {
+func (s *MatchTCContext) DIGITS() antlr.TerminalNode {
 	return s.GetToken(TrafficClassParserDIGITS, 0)
 }
 
-func (s *MatchDSCPContext) GetRuleContext() antlr.RuleContext {
+func (s *MatchTCContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *MatchDSCPContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *MatchTCContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *MatchDSCPContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *MatchTCContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(TrafficClassListener); ok {
-		listenerT.EnterMatchDSCP(s)
+		listenerT.EnterMatchTC(s)
 	}
 }
 
-func (s *MatchDSCPContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *MatchTCContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(TrafficClassListener); ok {
-		listenerT.ExitMatchDSCP(s)
+		listenerT.ExitMatchTC(s)
 	}
 }
 
-func (p *TrafficClassParser) MatchDSCP() (localctx IMatchDSCPContext) {
+func (p *TrafficClassParser) MatchTC() (localctx IMatchTCContext) {
 	this := p
 	_ = this
 
-	localctx = NewMatchDSCPContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 4, TrafficClassParserRULE_matchDSCP)
+	localctx = NewMatchTCContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 14, TrafficClassParserRULE_matchTC)
 	var _la int
 
 	defer func() {
@@ -476,15 +1067,15 @@ func (p *TrafficClassParser) MatchDSCP() (localctx IMatchDSCPContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(44)
-		p.Match(TrafficClassParserDSCP)
+		p.SetState(80)
+		p.Match(TrafficClassParserTC)
 	}
 	{
-		p.SetState(45)
+		p.SetState(81)
 		p.Match(TrafficClassParserT__1)
 	}
 	{
-		p.SetState(46)
+		p.SetState(82)
 		_la = p.GetTokenStream().LA(1)
 
 		if !(_la == TrafficClassParserDIGITS || _la == TrafficClassParserHEX_DIGITS) {
@@ -498,82 +1089,82 @@ func (p *TrafficClassParser) MatchDSCP() (localctx IMatchDSCPContext) {
 	return localctx
 }
 
-// IMatchTOSContext is an interface to support dynamic dispatch.
-type IMatchTOSContext interface {
+// IMatchFlowLabelContext is an interface to support dynamic dispatch.
+type IMatchFlowLabelContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
-	// IsMatchTOSContext differentiates from other interfaces.
-	IsMatchTOSContext()
+	// IsMatchFlowLabelContext differentiates from other interfaces.
+	IsMatchFlowLabelContext()
 }
 
-type MatchTOSContext struct {
+type MatchFlowLabelContext struct {
 	*antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyMatchTOSContext() *MatchTOSContext {
-	var p = new(MatchTOSContext)
+func NewEmptyMatchFlowLabelContext() *MatchFlowLabelContext {
+	var p = new(MatchFlowLabelContext)
 	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(nil, -1)
-	p.RuleIndex = TrafficClassParserRULE_matchTOS
+	p.RuleIndex = TrafficClassParserRULE_matchFlowLabel
 	return p
 }
 
-func (*MatchTOSContext) IsMatchTOSContext() {}
+func (*MatchFlowLabelContext) IsMatchFlowLabelContext() {}
 
-func NewMatchTOSContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MatchTOSContext {
-	var p = new(MatchTOSContext)
+func NewMatchFlowLabelContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MatchFlowLabelContext {
+	var p = new(MatchFlowLabelContext)
 
 	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = TrafficClassParserRULE_matchTOS
+	p.RuleIndex = TrafficClassParserRULE_matchFlowLabel
 
 	return p
 }
 
-func (s *MatchTOSContext) GetParser() antlr.Parser { return s.parser }
+func (s *MatchFlowLabelContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *MatchTOSContext) TOS() antlr.TerminalNode {
-	return s.GetToken(TrafficClassParserTOS, 0)
+func (s *MatchFlowLabelContext) FLOWLABEL() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserFLOWLABEL, 0)
 }
 
-func (s *MatchTOSContext) HEX_DIGITS() antlr.TerminalNode {
+func (s *MatchFlowLabelContext) HEX_DIGITS() antlr.TerminalNode {
 	return s.GetToken(TrafficClassParserHEX_DIGITS, 0)
 }
 
-func (s *MatchTOSContext) DIGITS() antlr.TerminalNode {
+func (s *MatchFlowLabelContext) DIGITS() antlr.TerminalNode {
 	return s.GetToken(TrafficClassParserDIGITS, 0)
 }
 
-func (s *MatchTOSContext) GetRuleContext() antlr.RuleContext {
+func (s *MatchFlowLabelContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *MatchTOSContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *MatchFlowLabelContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *MatchTOSContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *MatchFlowLabelContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(TrafficClassListener); ok {
-		listenerT.EnterMatchTOS(s)
+		listenerT.EnterMatchFlowLabel(s)
 	}
 }
 
-func (s *MatchTOSContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *MatchFlowLabelContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(TrafficClassListener); ok {
-		listenerT.ExitMatchTOS(s)
+		listenerT.ExitMatchFlowLabel(s)
 	}
 }
 
-func (p *TrafficClassParser) MatchTOS() (localctx IMatchTOSContext) {
+func (p *TrafficClassParser) MatchFlowLabel() (localctx IMatchFlowLabelContext) {
 	this := p
 	_ = this
 
-	localctx = NewMatchTOSContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 6, TrafficClassParserRULE_matchTOS)
+	localctx = NewMatchFlowLabelContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 16, TrafficClassParserRULE_matchFlowLabel)
 	var _la int
 
 	defer func() {
@@ -594,15 +1185,15 @@ func (p *TrafficClassParser) MatchTOS() (localctx IMatchTOSContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(48)
-		p.Match(TrafficClassParserTOS)
+		p.SetState(84)
+		p.Match(TrafficClassParserFLOWLABEL)
 	}
 	{
-		p.SetState(49)
+		p.SetState(85)
 		p.Match(TrafficClassParserT__1)
 	}
 	{
-		p.SetState(50)
+		p.SetState(86)
 		_la = p.GetTokenStream().LA(1)
 
 		if !(_la == TrafficClassParserDIGITS || _la == TrafficClassParserHEX_DIGITS) {
@@ -616,78 +1207,78 @@ func (p *TrafficClassParser) MatchTOS() (localctx IMatchTOSContext) {
 	return localctx
 }
 
-// IMatchProtocolContext is an interface to support dynamic dispatch.
-type IMatchProtocolContext interface {
+// IMatchNextHdrContext is an interface to support dynamic dispatch.
+type IMatchNextHdrContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
-	// IsMatchProtocolContext differentiates from other interfaces.
-	IsMatchProtocolContext()
+	// IsMatchNextHdrContext differentiates from other interfaces.
+	IsMatchNextHdrContext()
 }
 
-type MatchProtocolContext struct {
+type MatchNextHdrContext struct {
 	*antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyMatchProtocolContext() *MatchProtocolContext {
-	var p = new(MatchProtocolContext)
+func NewEmptyMatchNextHdrContext() *MatchNextHdrContext {
+	var p = new(MatchNextHdrContext)
 	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(nil, -1)
-	p.RuleIndex = TrafficClassParserRULE_matchProtocol
+	p.RuleIndex = TrafficClassParserRULE_matchNextHdr
 	return p
 }
 
-func (*MatchProtocolContext) IsMatchProtocolContext() {}
+func (*MatchNextHdrContext) IsMatchNextHdrContext() {}
 
-func NewMatchProtocolContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MatchProtocolContext {
-	var p = new(MatchProtocolContext)
+func NewMatchNextHdrContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MatchNextHdrContext {
+	var p = new(MatchNextHdrContext)
 
 	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = TrafficClassParserRULE_matchProtocol
+	p.RuleIndex = TrafficClassParserRULE_matchNextHdr
 
 	return p
 }
 
-func (s *MatchProtocolContext) GetParser() antlr.Parser { return s.parser }
+func (s *MatchNextHdrContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *MatchProtocolContext) PROTOCOL() antlr.TerminalNode {
-	return s.GetToken(TrafficClassParserPROTOCOL, 0)
+func (s *MatchNextHdrContext) NEXTHDR() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserNEXTHDR, 0)
 }
 
-func (s *MatchProtocolContext) STRING() antlr.TerminalNode {
+func (s *MatchNextHdrContext) STRING() antlr.TerminalNode {
 	return s.GetToken(TrafficClassParserSTRING, 0)
 }
 
-func (s *MatchProtocolContext) GetRuleContext() antlr.RuleContext {
+func (s *MatchNextHdrContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *MatchProtocolContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *MatchNextHdrContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *MatchProtocolContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *MatchNextHdrContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(TrafficClassListener); ok {
-		listenerT.EnterMatchProtocol(s)
+		listenerT.EnterMatchNextHdr(s)
 	}
 }
 
-func (s *MatchProtocolContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *MatchNextHdrContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(TrafficClassListener); ok {
-		listenerT.ExitMatchProtocol(s)
+		listenerT.ExitMatchNextHdr(s)
 	}
 }
 
-func (p *TrafficClassParser) MatchProtocol() (localctx IMatchProtocolContext) {
+func (p *TrafficClassParser) MatchNextHdr() (localctx IMatchNextHdrContext) {
 	this := p
 	_ = this
 
-	localctx = NewMatchProtocolContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 8, TrafficClassParserRULE_matchProtocol)
+	localctx = NewMatchNextHdrContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 18, TrafficClassParserRULE_matchNextHdr)
 
 	defer func() {
 		p.ExitRule()
@@ -707,15 +1298,15 @@ func (p *TrafficClassParser) MatchProtocol() (localctx IMatchProtocolContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(52)
-		p.Match(TrafficClassParserPROTOCOL)
+		p.SetState(88)
+		p.Match(TrafficClassParserNEXTHDR)
 	}
 	{
-		p.SetState(53)
+		p.SetState(89)
 		p.Match(TrafficClassParserT__0)
 	}
 	{
-		p.SetState(54)
+		p.SetState(90)
 		p.Match(TrafficClassParserSTRING)
 	}
 
@@ -793,7 +1384,7 @@ func (p *TrafficClassParser) MatchSrcPort() (localctx IMatchSrcPortContext) {
 	_ = this
 
 	localctx = NewMatchSrcPortContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 10, TrafficClassParserRULE_matchSrcPort)
+	p.EnterRule(localctx, 20, TrafficClassParserRULE_matchSrcPort)
 
 	defer func() {
 		p.ExitRule()
@@ -813,15 +1404,15 @@ func (p *TrafficClassParser) MatchSrcPort() (localctx IMatchSrcPortContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(56)
+		p.SetState(92)
 		p.Match(TrafficClassParserSRCPORT)
 	}
 	{
-		p.SetState(57)
+		p.SetState(93)
 		p.Match(TrafficClassParserT__0)
 	}
 	{
-		p.SetState(58)
+		p.SetState(94)
 		p.Match(TrafficClassParserDIGITS)
 	}
 
@@ -903,7 +1494,7 @@ func (p *TrafficClassParser) MatchSrcPortRange() (localctx IMatchSrcPortRangeCon
 	_ = this
 
 	localctx = NewMatchSrcPortRangeContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 12, TrafficClassParserRULE_matchSrcPortRange)
+	p.EnterRule(localctx, 22, TrafficClassParserRULE_matchSrcPortRange)
 
 	defer func() {
 		p.ExitRule()
@@ -923,23 +1514,23 @@ func (p *TrafficClassParser) MatchSrcPortRange() (localctx IMatchSrcPortRangeCon
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(60)
+		p.SetState(96)
 		p.Match(TrafficClassParserSRCPORT)
 	}
 	{
-		p.SetState(61)
+		p.SetState(97)
 		p.Match(TrafficClassParserT__0)
 	}
 	{
-		p.SetState(62)
+		p.SetState(98)
 		p.Match(TrafficClassParserDIGITS)
 	}
 	{
-		p.SetState(63)
+		p.SetState(99)
 		p.Match(TrafficClassParserT__2)
 	}
 	{
-		p.SetState(64)
+		p.SetState(100)
 		p.Match(TrafficClassParserDIGITS)
 	}
 
@@ -1017,7 +1608,7 @@ func (p *TrafficClassParser) MatchDstPort() (localctx IMatchDstPortContext) {
 	_ = this
 
 	localctx = NewMatchDstPortContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 14, TrafficClassParserRULE_matchDstPort)
+	p.EnterRule(localctx, 24, TrafficClassParserRULE_matchDstPort)
 
 	defer func() {
 		p.ExitRule()
@@ -1037,15 +1628,15 @@ func (p *TrafficClassParser) MatchDstPort() (localctx IMatchDstPortContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(66)
+		p.SetState(102)
 		p.Match(TrafficClassParserDSTPORT)
 	}
 	{
-		p.SetState(67)
+		p.SetState(103)
 		p.Match(TrafficClassParserT__0)
 	}
 	{
-		p.SetState(68)
+		p.SetState(104)
 		p.Match(TrafficClassParserDIGITS)
 	}
 
@@ -1127,7 +1718,7 @@ func (p *TrafficClassParser) MatchDstPortRange() (localctx IMatchDstPortRangeCon
 	_ = this
 
 	localctx = NewMatchDstPortRangeContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 16, TrafficClassParserRULE_matchDstPortRange)
+	p.EnterRule(localctx, 26, TrafficClassParserRULE_matchDstPortRange)
 
 	defer func() {
 		p.ExitRule()
@@ -1147,23 +1738,129 @@ func (p *TrafficClassParser) MatchDstPortRange() (localctx IMatchDstPortRangeCon
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(70)
+		p.SetState(106)
 		p.Match(TrafficClassParserDSTPORT)
 	}
 	{
-		p.SetState(71)
+		p.SetState(107)
 		p.Match(TrafficClassParserT__0)
 	}
 	{
-		p.SetState(72)
-		p.Match(TrafficClassParserDIGITS)
+		p.SetState(108)
+		p.Match(TrafficClassParserDIGITS)
+	}
+	{
+		p.SetState(109)
+		p.Match(TrafficClassParserT__2)
+	}
+	{
+		p.SetState(110)
+		p.Match(TrafficClassParserDIGITS)
+	}
+
+	return localctx
+}
+
+// IMatchICMPTypeContext is an interface to support dynamic dispatch.
+type IMatchICMPTypeContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// IsMatchICMPTypeContext differentiates from other interfaces.
+	IsMatchICMPTypeContext()
+}
+
+type MatchICMPTypeContext struct {
+	*antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyMatchICMPTypeContext() *MatchICMPTypeContext {
+	var p = new(MatchICMPTypeContext)
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(nil, -1)
+	p.RuleIndex = TrafficClassParserRULE_matchICMPType
+	return p
+}
+
+func (*MatchICMPTypeContext) IsMatchICMPTypeContext() {}
+
+func NewMatchICMPTypeContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MatchICMPTypeContext {
+	var p = new(MatchICMPTypeContext)
+
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = TrafficClassParserRULE_matchICMPType
+
+	return p
+}
+
+func (s *MatchICMPTypeContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *MatchICMPTypeContext) ICMPTYPE() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserICMPTYPE, 0)
+}
+
+func (s *MatchICMPTypeContext) DIGITS() antlr.TerminalNode {
+	return s.GetToken(TrafficClassParserDIGITS, 0)
+}
+
+func (s *MatchICMPTypeContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *MatchICMPTypeContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *MatchICMPTypeContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(TrafficClassListener); ok {
+		listenerT.EnterMatchICMPType(s)
+	}
+}
+
+func (s *MatchICMPTypeContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(TrafficClassListener); ok {
+		listenerT.ExitMatchICMPType(s)
+	}
+}
+
+func (p *TrafficClassParser) MatchICMPType() (localctx IMatchICMPTypeContext) {
+	this := p
+	_ = this
+
+	localctx = NewMatchICMPTypeContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 28, TrafficClassParserRULE_matchICMPType)
+
+	defer func() {
+		p.ExitRule()
+	}()
+
+	defer func() {
+		if err := recover(); err != nil {
+			if v, ok := err.(antlr.RecognitionException); ok {
+				localctx.SetException(v)
+				p.GetErrorHandler().ReportError(p, v)
+				p.GetErrorHandler().Recover(p, v)
+			} else {
+				panic(err)
+			}
+		}
+	}()
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(112)
+		p.Match(TrafficClassParserICMPTYPE)
 	}
 	{
-		p.SetState(73)
-		p.Match(TrafficClassParserT__2)
+		p.SetState(113)
+		p.Match(TrafficClassParserT__0)
 	}
 	{
-		p.SetState(74)
+		p.SetState(114)
 		p.Match(TrafficClassParserDIGITS)
 	}
 
@@ -1237,7 +1934,7 @@ func (p *TrafficClassParser) CondCls() (localctx ICondClsContext) {
 	_ = this
 
 	localctx = NewCondClsContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 18, TrafficClassParserRULE_condCls)
+	p.EnterRule(localctx, 30, TrafficClassParserRULE_condCls)
 
 	defer func() {
 		p.ExitRule()
@@ -1257,11 +1954,11 @@ func (p *TrafficClassParser) CondCls() (localctx ICondClsContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(76)
+		p.SetState(116)
 		p.Match(TrafficClassParserT__3)
 	}
 	{
-		p.SetState(77)
+		p.SetState(117)
 		p.Match(TrafficClassParserDIGITS)
 	}
 
@@ -1358,7 +2055,7 @@ func (p *TrafficClassParser) CondAny() (localctx ICondAnyContext) {
 	_ = this
 
 	localctx = NewCondAnyContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 20, TrafficClassParserRULE_condAny)
+	p.EnterRule(localctx, 32, TrafficClassParserRULE_condAny)
 	var _la int
 
 	defer func() {
@@ -1379,37 +2076,37 @@ func (p *TrafficClassParser) CondAny() (localctx ICondAnyContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(79)
+		p.SetState(119)
 		p.Match(TrafficClassParserANY)
 	}
 	{
-		p.SetState(80)
+		p.SetState(120)
 		p.Match(TrafficClassParserT__4)
 	}
 	{
-		p.SetState(81)
+		p.SetState(121)
 		p.Cond()
 	}
-	p.SetState(86)
+	p.SetState(126)
 	p.GetErrorHandler().Sync(p)
 	_la = p.GetTokenStream().LA(1)
 
 	for _la == TrafficClassParserT__5 {
 		{
-			p.SetState(82)
+			p.SetState(122)
 			p.Match(TrafficClassParserT__5)
 		}
 		{
-			p.SetState(83)
+			p.SetState(123)
 			p.Cond()
 		}
 
-		p.SetState(88)
+		p.SetState(128)
 		p.GetErrorHandler().Sync(p)
 		_la = p.GetTokenStream().LA(1)
 	}
 	{
-		p.SetState(89)
+		p.SetState(129)
 		p.Match(TrafficClassParserT__6)
 	}
 
@@ -1506,7 +2203,7 @@ func (p *TrafficClassParser) CondAll() (localctx ICondAllContext) {
 	_ = this
 
 	localctx = NewCondAllContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 22, TrafficClassParserRULE_condAll)
+	p.EnterRule(localctx, 34, TrafficClassParserRULE_condAll)
 	var _la int
 
 	defer func() {
@@ -1527,37 +2224,37 @@ func (p *TrafficClassParser) CondAll() (localctx ICondAllContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(91)
+		p.SetState(131)
 		p.Match(TrafficClassParserALL)
 	}
 	{
-		p.SetState(92)
+		p.SetState(132)
 		p.Match(TrafficClassParserT__4)
 	}
 	{
-		p.SetState(93)
+		p.SetState(133)
 		p.Cond()
 	}
-	p.SetState(98)
+	p.SetState(138)
 	p.GetErrorHandler().Sync(p)
 	_la = p.GetTokenStream().LA(1)
 
 	for _la == TrafficClassParserT__5 {
 		{
-			p.SetState(94)
+			p.SetState(134)
 			p.Match(TrafficClassParserT__5)
 		}
 		{
-			p.SetState(95)
+			p.SetState(135)
 			p.Cond()
 		}
 
-		p.SetState(100)
+		p.SetState(140)
 		p.GetErrorHandler().Sync(p)
 		_la = p.GetTokenStream().LA(1)
 	}
 	{
-		p.SetState(101)
+		p.SetState(141)
 		p.Match(TrafficClassParserT__6)
 	}
 
@@ -1641,7 +2338,7 @@ func (p *TrafficClassParser) CondNot() (localctx ICondNotContext) {
 	_ = this
 
 	localctx = NewCondNotContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 24, TrafficClassParserRULE_condNot)
+	p.EnterRule(localctx, 36, TrafficClassParserRULE_condNot)
 
 	defer func() {
 		p.ExitRule()
@@ -1661,19 +2358,19 @@ func (p *TrafficClassParser) CondNot() (localctx ICondNotContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(103)
+		p.SetState(143)
 		p.Match(TrafficClassParserNOT)
 	}
 	{
-		p.SetState(104)
+		p.SetState(144)
 		p.Match(TrafficClassParserT__4)
 	}
 	{
-		p.SetState(105)
+		p.SetState(145)
 		p.Cond()
 	}
 	{
-		p.SetState(106)
+		p.SetState(146)
 		p.Match(TrafficClassParserT__6)
 	}
 
@@ -1747,7 +2444,7 @@ func (p *TrafficClassParser) CondBool() (localctx ICondBoolContext) {
 	_ = this
 
 	localctx = NewCondBoolContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 26, TrafficClassParserRULE_condBool)
+	p.EnterRule(localctx, 38, TrafficClassParserRULE_condBool)
 	var _la int
 
 	defer func() {
@@ -1768,15 +2465,15 @@ func (p *TrafficClassParser) CondBool() (localctx ICondBoolContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(108)
+		p.SetState(148)
 		p.Match(TrafficClassParserBOOL)
 	}
 	{
-		p.SetState(109)
+		p.SetState(149)
 		p.Match(TrafficClassParserT__0)
 	}
 	{
-		p.SetState(110)
+		p.SetState(150)
 		_la = p.GetTokenStream().LA(1)
 
 		if !(_la == TrafficClassParserT__7 || _la == TrafficClassParserT__8) {
@@ -1903,7 +2600,7 @@ func (p *TrafficClassParser) CondIPv4() (localctx ICondIPv4Context) {
 	_ = this
 
 	localctx = NewCondIPv4Context(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 28, TrafficClassParserRULE_condIPv4)
+	p.EnterRule(localctx, 40, TrafficClassParserRULE_condIPv4)
 
 	defer func() {
 		p.ExitRule()
@@ -1921,42 +2618,42 @@ func (p *TrafficClassParser) CondIPv4() (localctx ICondIPv4Context) {
 		}
 	}()
 
-	p.SetState(117)
+	p.SetState(157)
 	p.GetErrorHandler().Sync(p)
 
 	switch p.GetTokenStream().LA(1) {
 	case TrafficClassParserSRC:
 		p.EnterOuterAlt(localctx, 1)
 		{
-			p.SetState(112)
+			p.SetState(152)
 			p.MatchSrc()
 		}
 
 	case TrafficClassParserDST:
 		p.EnterOuterAlt(localctx, 2)
 		{
-			p.SetState(113)
+			p.SetState(153)
 			p.MatchDst()
 		}
 
 	case TrafficClassParserDSCP:
 		p.EnterOuterAlt(localctx, 3)
 		{
-			p.SetState(114)
+			p.SetState(154)
 			p.MatchDSCP()
 		}
 
 	case TrafficClassParserTOS:
 		p.EnterOuterAlt(localctx, 4)
 		{
-			p.SetState(115)
+			p.SetState(155)
 			p.MatchTOS()
 		}
 
 	case TrafficClassParserPROTOCOL:
 		p.EnterOuterAlt(localctx, 5)
 		{
-			p.SetState(116)
+			p.SetState(156)
 			p.MatchProtocol()
 		}
 
@@ -1967,6 +2664,183 @@ func (p *TrafficClassParser) CondIPv4() (localctx ICondIPv4Context) {
 	return localctx
 }
 
+// ICondIPv6Context is an interface to support dynamic dispatch.
+type ICondIPv6Context interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// IsCondIPv6Context differentiates from other interfaces.
+	IsCondIPv6Context()
+}
+
+type CondIPv6Context struct {
+	*antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyCondIPv6Context() *CondIPv6Context {
+	var p = new(CondIPv6Context)
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(nil, -1)
+	p.RuleIndex = TrafficClassParserRULE_condIPv6
+	return p
+}
+
+func (*CondIPv6Context) IsCondIPv6Context() {}
+
+func NewCondIPv6Context(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *CondIPv6Context {
+	var p = new(CondIPv6Context)
+
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = TrafficClassParserRULE_condIPv6
+
+	return p
+}
+
+func (s *CondIPv6Context) GetParser() antlr.Parser { return s.parser }
+
+func (s *CondIPv6Context) MatchSrcIPv6() IMatchSrcIPv6Context {
+	var t = s.GetTypedRuleContext(reflect.TypeOf((*IMatchSrcIPv6Context)(nil)).Elem(), 0)
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IMatchSrcIPv6Context)
+}
+
+func (s *CondIPv6Context) MatchDstIPv6() IMatchDstIPv6Context {
+	var t = s.GetTypedRuleContext(reflect.TypeOf((*IMatchDstIPv6Context)(nil)).Elem(), 0)
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IMatchDstIPv6Context)
+}
+
+func (s *CondIPv6Context) MatchTC() IMatchTCContext {
+	var t = s.GetTypedRuleContext(reflect.TypeOf((*IMatchTCContext)(nil)).Elem(), 0)
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IMatchTCContext)
+}
+
+func (s *CondIPv6Context) MatchFlowLabel() IMatchFlowLabelContext {
+	var t = s.GetTypedRuleContext(reflect.TypeOf((*IMatchFlowLabelContext)(nil)).Elem(), 0)
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IMatchFlowLabelContext)
+}
+
+func (s *CondIPv6Context) MatchNextHdr() IMatchNextHdrContext {
+	var t = s.GetTypedRuleContext(reflect.TypeOf((*IMatchNextHdrContext)(nil)).Elem(), 0)
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IMatchNextHdrContext)
+}
+
+func (s *CondIPv6Context) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *CondIPv6Context) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *CondIPv6Context) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(TrafficClassListener); ok {
+		listenerT.EnterCondIPv6(s)
+	}
+}
+
+func (s *CondIPv6Context) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(TrafficClassListener); ok {
+		listenerT.ExitCondIPv6(s)
+	}
+}
+
+func (p *TrafficClassParser) CondIPv6() (localctx ICondIPv6Context) {
+	this := p
+	_ = this
+
+	localctx = NewCondIPv6Context(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 42, TrafficClassParserRULE_condIPv6)
+
+	defer func() {
+		p.ExitRule()
+	}()
+
+	defer func() {
+		if err := recover(); err != nil {
+			if v, ok := err.(antlr.RecognitionException); ok {
+				localctx.SetException(v)
+				p.GetErrorHandler().ReportError(p, v)
+				p.GetErrorHandler().Recover(p, v)
+			} else {
+				panic(err)
+			}
+		}
+	}()
+
+	p.SetState(164)
+	p.GetErrorHandler().Sync(p)
+
+	switch p.GetTokenStream().LA(1) {
+	case TrafficClassParserSRC:
+		p.EnterOuterAlt(localctx, 1)
+		{
+			p.SetState(159)
+			p.MatchSrcIPv6()
+		}
+
+	case TrafficClassParserDST:
+		p.EnterOuterAlt(localctx, 2)
+		{
+			p.SetState(160)
+			p.MatchDstIPv6()
+		}
+
+	case TrafficClassParserTC:
+		p.EnterOuterAlt(localctx, 3)
+		{
+			p.SetState(161)
+			p.MatchTC()
+		}
+
+	case TrafficClassParserFLOWLABEL:
+		p.EnterOuterAlt(localctx, 4)
+		{
+			p.SetState(162)
+			p.MatchFlowLabel()
+		}
+
+	case TrafficClassParserNEXTHDR:
+		p.EnterOuterAlt(localctx, 5)
+		{
+			p.SetState(163)
+			p.MatchNextHdr()
+		}
+
+	default:
+		panic(antlr.NewNoViableAltException(p, nil, nil, nil, nil, nil))
+	}
+
+	return localctx
+}
+
 // ICondPortContext is an interface to support dynamic dispatch.
 type ICondPortContext interface {
 	antlr.ParserRuleContext
@@ -2070,7 +2944,7 @@ func (p *TrafficClassParser) CondPort() (localctx ICondPortContext) {
 	_ = this
 
 	localctx = NewCondPortContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 30, TrafficClassParserRULE_condPort)
+	p.EnterRule(localctx, 44, TrafficClassParserRULE_condPort)
 
 	defer func() {
 		p.ExitRule()
@@ -2088,34 +2962,34 @@ func (p *TrafficClassParser) CondPort() (localctx ICondPortContext) {
 		}
 	}()
 
-	p.SetState(123)
+	p.SetState(170)
 	p.GetErrorHandler().Sync(p)
-	switch p.GetInterpreter().AdaptivePredict(p.GetTokenStream(), 3, p.GetParserRuleContext()) {
+	switch p.GetInterpreter().AdaptivePredict(p.GetTokenStream(), 4, p.GetParserRuleContext()) {
 	case 1:
 		p.EnterOuterAlt(localctx, 1)
 		{
-			p.SetState(119)
+			p.SetState(166)
 			p.MatchSrcPort()
 		}
 
 	case 2:
 		p.EnterOuterAlt(localctx, 2)
 		{
-			p.SetState(120)
+			p.SetState(167)
 			p.MatchSrcPortRange()
 		}
 
 	case 3:
 		p.EnterOuterAlt(localctx, 3)
 		{
-			p.SetState(121)
+			p.SetState(168)
 			p.MatchDstPort()
 		}
 
 	case 4:
 		p.EnterOuterAlt(localctx, 4)
 		{
-			p.SetState(122)
+			p.SetState(169)
 			p.MatchDstPortRange()
 		}
 
@@ -2124,6 +2998,106 @@ func (p *TrafficClassParser) CondPort() (localctx ICondPortContext) {
 	return localctx
 }
 
+// ICondICMPContext is an interface to support dynamic dispatch.
+type ICondICMPContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// IsCondICMPContext differentiates from other interfaces.
+	IsCondICMPContext()
+}
+
+type CondICMPContext struct {
+	*antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyCondICMPContext() *CondICMPContext {
+	var p = new(CondICMPContext)
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(nil, -1)
+	p.RuleIndex = TrafficClassParserRULE_condICMP
+	return p
+}
+
+func (*CondICMPContext) IsCondICMPContext() {}
+
+func NewCondICMPContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *CondICMPContext {
+	var p = new(CondICMPContext)
+
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = TrafficClassParserRULE_condICMP
+
+	return p
+}
+
+func (s *CondICMPContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *CondICMPContext) MatchICMPType() IMatchICMPTypeContext {
+	var t = s.GetTypedRuleContext(reflect.TypeOf((*IMatchICMPTypeContext)(nil)).Elem(), 0)
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IMatchICMPTypeContext)
+}
+
+func (s *CondICMPContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *CondICMPContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *CondICMPContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(TrafficClassListener); ok {
+		listenerT.EnterCondICMP(s)
+	}
+}
+
+func (s *CondICMPContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(TrafficClassListener); ok {
+		listenerT.ExitCondICMP(s)
+	}
+}
+
+func (p *TrafficClassParser) CondICMP() (localctx ICondICMPContext) {
+	this := p
+	_ = this
+
+	localctx = NewCondICMPContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 46, TrafficClassParserRULE_condICMP)
+
+	defer func() {
+		p.ExitRule()
+	}()
+
+	defer func() {
+		if err := recover(); err != nil {
+			if v, ok := err.(antlr.RecognitionException); ok {
+				localctx.SetException(v)
+				p.GetErrorHandler().ReportError(p, v)
+				p.GetErrorHandler().Recover(p, v)
+			} else {
+				panic(err)
+			}
+		}
+	}()
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(172)
+		p.MatchICMPType()
+	}
+
+	return localctx
+}
+
 // ICondContext is an interface to support dynamic dispatch.
 type ICondContext interface {
 	antlr.ParserRuleContext
@@ -2202,6 +3176,16 @@ func (s *CondContext) CondIPv4() ICondIPv4Context {
 	return t.(ICondIPv4Context)
 }
 
+func (s *CondContext) CondIPv6() ICondIPv6Context {
+	var t = s.GetTypedRuleContext(reflect.TypeOf((*ICondIPv6Context)(nil)).Elem(), 0)
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(ICondIPv6Context)
+}
+
 func (s *CondContext) CondPort() ICondPortContext {
 	var t = s.GetTypedRuleContext(reflect.TypeOf((*ICondPortContext)(nil)).Elem(), 0)
 
@@ -2212,6 +3196,16 @@ func (s *CondContext) CondPort() ICondPortContext {
 	return t.(ICondPortContext)
 }
 
+func (s *CondContext) CondICMP() ICondICMPContext {
+	var t = s.GetTypedRuleContext(reflect.TypeOf((*ICondICMPContext)(nil)).Elem(), 0)
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(ICondICMPContext)
+}
+
 func (s *CondContext) CondCls() ICondClsContext {
 	var t = s.GetTypedRuleContext(reflect.TypeOf((*ICondClsContext)(nil)).Elem(), 0)
 
@@ -2257,7 +3251,7 @@ func (p *TrafficClassParser) Cond() (localctx ICondContext) {
 	_ = this
 
 	localctx = NewCondContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 32, TrafficClassParserRULE_cond)
+	p.EnterRule(localctx, 48, TrafficClassParserRULE_cond)
 
 	defer func() {
 		p.ExitRule()
@@ -2275,61 +3269,72 @@ func (p *TrafficClassParser) Cond() (localctx ICondContext) {
 		}
 	}()
 
-	p.SetState(132)
+	p.SetState(183)
 	p.GetErrorHandler().Sync(p)
-
-	switch p.GetTokenStream().LA(1) {
-	case TrafficClassParserALL:
+	switch p.GetInterpreter().AdaptivePredict(p.GetTokenStream(), 5, p.GetParserRuleContext()) {
+	case 1:
 		p.EnterOuterAlt(localctx, 1)
 		{
-			p.SetState(125)
+			p.SetState(174)
 			p.CondAll()
 		}
 
-	case TrafficClassParserANY:
+	case 2:
 		p.EnterOuterAlt(localctx, 2)
 		{
-			p.SetState(126)
+			p.SetState(175)
 			p.CondAny()
 		}
 
-	case TrafficClassParserNOT:
+	case 3:
 		p.EnterOuterAlt(localctx, 3)
 		{
-			p.SetState(127)
+			p.SetState(176)
 			p.CondNot()
 		}
 
-	case TrafficClassParserSRC, TrafficClassParserDST, TrafficClassParserDSCP, TrafficClassParserTOS, TrafficClassParserPROTOCOL:
+	case 4:
 		p.EnterOuterAlt(localctx, 4)
 		{
-			p.SetState(128)
+			p.SetState(177)
 			p.CondIPv4()
 		}
 
-	case TrafficClassParserSRCPORT, TrafficClassParserDSTPORT:
+	case 5:
 		p.EnterOuterAlt(localctx, 5)
 		{
-			p.SetState(129)
-			p.CondPort()
+			p.SetState(178)
+			p.CondIPv6()
 		}
 
-	case TrafficClassParserT__3:
+	case 6:
 		p.EnterOuterAlt(localctx, 6)
 		{
-			p.SetState(130)
-			p.CondCls()
+			p.SetState(179)
+			p.CondPort()
 		}
 
-	case TrafficClassParserBOOL:
+	case 7:
 		p.EnterOuterAlt(localctx, 7)
 		{
-			p.SetState(131)
+			p.SetState(180)
+			p.CondICMP()
+		}
+
+	case 8:
+		p.EnterOuterAlt(localctx, 8)
+		{
+			p.SetState(181)
+			p.CondCls()
+		}
+
+	case 9:
+		p.EnterOuterAlt(localctx, 9)
+		{
+			p.SetState(182)
 			p.CondBool()
 		}
 
-	default:
-		panic(antlr.NewNoViableAltException(p, nil, nil, nil, nil, nil))
 	}
 
 	return localctx
@@ -2412,7 +3417,7 @@ func (p *TrafficClassParser) TrafficClass() (localctx ITrafficClassContext) {
 	_ = this
 
 	localctx = NewTrafficClassContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 34, TrafficClassParserRULE_trafficClass)
+	p.EnterRule(localctx, 50, TrafficClassParserRULE_trafficClass)
 
 	defer func() {
 		p.ExitRule()
@@ -2432,11 +3437,11 @@ func (p *TrafficClassParser) TrafficClass() (localctx ITrafficClassContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(134)
+		p.SetState(185)
 		p.Cond()
 	}
 	{
-		p.SetState(135)
+		p.SetState(186)
 		p.Match(TrafficClassParserEOF)
 	}
 
@@ -0,0 +1,269 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/scionproto/scion/dispatcher/internal/metrics"
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/private/common"
+)
+
+// DropReason describes why a packet of an application was dropped.
+type DropReason string
+
+const (
+	// DropRingFull is used for packets from the network that were dropped
+	// because the application does not read fast enough.
+	DropRingFull DropReason = "ring_full"
+	// DropRateLimited is used for packets that exceeded the rate limit of the
+	// application.
+	DropRateLimited DropReason = "rate_limited"
+	// DropSendError is used for packets from the application that could not
+	// be sent on the underlay socket.
+	DropSendError DropReason = "send_error"
+)
+
+var dropReasons = [...]DropReason{DropRingFull, DropRateLimited, DropSendError}
+
+// DropReasons contains all drop reasons.
+var DropReasons = dropReasons[:]
+
+// AppStats contains the traffic counters of a single registration. Incoming
+// traffic is traffic from the network to the application, outgoing traffic
+// is traffic from the application to the network.
+type AppStats struct {
+	PktsIn   uint64
+	BytesIn  uint64
+	PktsOut  uint64
+	BytesOut uint64
+	Drops    map[DropReason]uint64
+}
+
+// AppInfo describes a registered application and its traffic.
+type AppInfo struct {
+	IA     addr.IA
+	Public *net.UDPAddr
+	SVC    addr.HostSVC
+	// RateLimit is the rate limit in bytes per second that applies to each
+	// direction. 0 means no limit.
+	RateLimit int
+	Stats     AppStats
+}
+
+// appAccounting keeps track of the traffic of a single registration, and
+// enforces the rate limit of the registration.
+type appAccounting struct {
+	ia        addr.IA
+	svc       addr.HostSVC
+	rateLimit int
+	// address holds the *appAddress of the registration. It is only known
+	// once the port is allocated, which happens after the accounting is
+	// reachable by the packet processing goroutines.
+	address atomic.Value
+
+	pktsIn, bytesIn   uint64
+	pktsOut, bytesOut uint64
+	drops             [len(dropReasons)]uint64
+
+	ingressLimiter *tokenBucket
+	egressLimiter  *tokenBucket
+}
+
+// appAddress is the address of a registration, together with the metric
+// labels derived from it.
+type appAddress struct {
+	public *net.UDPAddr
+	labels metrics.App
+}
+
+// newAppAccounting creates the accounting of a registration. If public is nil,
+// it must be set with setPublic once the port is allocated. Until then, the
+// traffic is counted, but not reported in the metrics.
+func newAppAccounting(ia addr.IA, public *net.UDPAddr, svc addr.HostSVC,
+	rateLimit int) *appAccounting {
+
+	a := &appAccounting{
+		ia:        ia,
+		svc:       svc,
+		rateLimit: rateLimit,
+	}
+	if rateLimit > 0 {
+		a.ingressLimiter = newTokenBucket(rateLimit, common.SupportedMTU)
+		a.egressLimiter = newTokenBucket(rateLimit, common.SupportedMTU)
+	}
+	if public != nil {
+		a.setPublic(public)
+	}
+	return a
+}
+
+// setPublic sets the public address of the registration. It is safe to call
+// concurrently with the other methods.
+func (a *appAccounting) setPublic(public *net.UDPAddr) {
+	a.address.Store(&appAddress{
+		public: public,
+		labels: metrics.App{
+			IA:   a.ia.String(),
+			Port: strconv.Itoa(public.Port),
+			SVC:  a.svc.BaseString(),
+		},
+	})
+}
+
+// labels returns the metric labels of the registration, and false if the
+// address is not known yet.
+func (a *appAccounting) labels() (metrics.App, bool) {
+	address, ok := a.address.Load().(*appAddress)
+	if !ok {
+		return metrics.App{}, false
+	}
+	return address.labels, true
+}
+
+// allowIn checks the rate limit for an incoming packet of size n. It returns
+// false if the packet must be dropped.
+func (a *appAccounting) allowIn(n int) bool {
+	if a.ingressLimiter != nil && !a.ingressLimiter.take(n) {
+		a.drop(DropRateLimited)
+		return false
+	}
+	return true
+}
+
+// allowOut checks the rate limit for an outgoing packet of size n. It returns
+// false if the packet must be dropped.
+func (a *appAccounting) allowOut(n int) bool {
+	if a.egressLimiter != nil && !a.egressLimiter.take(n) {
+		a.drop(DropRateLimited)
+		return false
+	}
+	return true
+}
+
+func (a *appAccounting) countIn(n int) {
+	atomic.AddUint64(&a.pktsIn, 1)
+	atomic.AddUint64(&a.bytesIn, uint64(n))
+	if labels, ok := a.labels(); ok {
+		metrics.M.AppPkts(labels.WithDirection(metrics.DirectionIn)).Inc()
+		metrics.M.AppBytes(labels.WithDirection(metrics.DirectionIn)).Add(float64(n))
+	}
+}
+
+func (a *appAccounting) countOut(n int) {
+	atomic.AddUint64(&a.pktsOut, 1)
+	atomic.AddUint64(&a.bytesOut, uint64(n))
+	if labels, ok := a.labels(); ok {
+		metrics.M.AppPkts(labels.WithDirection(metrics.DirectionOut)).Inc()
+		metrics.M.AppBytes(labels.WithDirection(metrics.DirectionOut)).Add(float64(n))
+	}
+}
+
+func (a *appAccounting) drop(reason DropReason) {
+	for i, r := range DropReasons {
+		if r == reason {
+			atomic.AddUint64(&a.drops[i], 1)
+		}
+	}
+	if labels, ok := a.labels(); ok {
+		metrics.M.AppDrops(labels.WithReason(string(reason))).Inc()
+	}
+}
+
+func (a *appAccounting) info() AppInfo {
+	drops := make(map[DropReason]uint64, len(DropReasons))
+	for i, r := range DropReasons {
+		drops[r] = atomic.LoadUint64(&a.drops[i])
+	}
+	var public *net.UDPAddr
+	if address, ok := a.address.Load().(*appAddress); ok {
+		public = address.public
+	}
+	return AppInfo{
+		IA:        a.ia,
+		Public:    public,
+		SVC:       a.svc,
+		RateLimit: a.rateLimit,
+		Stats: AppStats{
+			PktsIn:   atomic.LoadUint64(&a.pktsIn),
+			BytesIn:  atomic.LoadUint64(&a.bytesIn),
+			PktsOut:  atomic.LoadUint64(&a.pktsOut),
+			BytesOut: atomic.LoadUint64(&a.bytesOut),
+			Drops:    drops,
+		},
+	}
+}
+
+// close removes the Prometheus series of the registration.
+func (a *appAccounting) close() {
+	labels, ok := a.labels()
+	if !ok {
+		return
+	}
+	reasons := make([]string, 0, len(DropReasons))
+	for _, r := range DropReasons {
+		reasons = append(reasons, string(r))
+	}
+	metrics.M.DeleteApp(labels, reasons...)
+}
+
+// tokenBucket is a token bucket rate limiter. The bucket holds at most one
+// second worth of tokens, but never less than the burst size. This ensures that
+// a packet of the burst size can pass even if the rate is lower.
+type tokenBucket struct {
+	mtx      sync.Mutex
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+}
+
+func newTokenBucket(rate, burst int) *tokenBucket {
+	capacity := float64(rate)
+	if burst > rate {
+		capacity = float64(burst)
+	}
+	return &tokenBucket{
+		rate:     float64(rate),
+		capacity: capacity,
+		tokens:   capacity,
+		last:     time.Now(),
+		now:      time.Now,
+	}
+}
+
+// take removes n tokens from the bucket. It returns false if not enough
+// tokens are available, in which case no tokens are removed.
+func (b *tokenBucket) take(n int) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	now := b.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+	if b.tokens < float64(n) {
+		return false
+	}
+	b.tokens -= float64(n)
+	return true
+}
@@ -0,0 +1,157 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/dispatcher/internal/respool"
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/private/ringbuf"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newTokenBucket(1000, 0)
+	b.last, b.now = now, func() time.Time { return now }
+
+	assert.True(t, b.take(600))
+	assert.False(t, b.take(600))
+	assert.True(t, b.take(400))
+	assert.False(t, b.take(1))
+
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, b.take(500))
+	assert.False(t, b.take(1))
+
+	// The bucket never holds more than one second worth of tokens.
+	now = now.Add(10 * time.Second)
+	assert.False(t, b.take(1001))
+	assert.True(t, b.take(1000))
+}
+
+func TestTokenBucketBurst(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newTokenBucket(100, 1500)
+	b.last, b.now = now, func() time.Time { return now }
+
+	// A packet larger than the rate passes once enough tokens accumulated.
+	assert.True(t, b.take(1500))
+	assert.False(t, b.take(1500))
+	now = now.Add(10 * time.Second)
+	assert.False(t, b.take(1500))
+	now = now.Add(5 * time.Second)
+	assert.True(t, b.take(1500))
+
+	// The bucket never holds more than the burst size.
+	now = now.Add(time.Minute)
+	assert.False(t, b.take(1501))
+	assert.True(t, b.take(1500))
+}
+
+func TestSendPacketAccounting(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	public := &net.UDPAddr{IP: net.IP{127, 0, 0, 1}, Port: 31000}
+
+	t.Run("delivered packets are counted", func(t *testing.T) {
+		entry := newTableEntry(false)
+		entry.accounting = newAppAccounting(ia, public, addr.SvcNone, 0)
+		defer entry.accounting.close()
+
+		sendPacket(entry, respool.GetPacket())
+		info := entry.accounting.info()
+		assert.Equal(t, uint64(1), info.Stats.PktsIn)
+		assert.Equal(t, uint64(respool.GetPacket().Len()), info.Stats.BytesIn)
+		assert.Zero(t, info.Stats.Drops[DropRingFull])
+	})
+	t.Run("full ring drops are counted", func(t *testing.T) {
+		entry := &TableEntry{
+			appIngressRing: ringbuf.New(1, nil, "test"),
+			accounting:     newAppAccounting(ia, public, addr.SvcNone, 0),
+		}
+		defer entry.accounting.close()
+
+		sendPacket(entry, respool.GetPacket())
+		sendPacket(entry, respool.GetPacket())
+		info := entry.accounting.info()
+		assert.Equal(t, uint64(1), info.Stats.PktsIn)
+		assert.Equal(t, uint64(1), info.Stats.Drops[DropRingFull])
+	})
+	t.Run("rate limited packets are dropped", func(t *testing.T) {
+		pkt := respool.GetPacket()
+		entry := newTableEntry(false)
+		entry.accounting = newAppAccounting(ia, public, addr.SvcNone, pkt.Len())
+		defer entry.accounting.close()
+
+		sendPacket(entry, pkt)
+		sendPacket(entry, respool.GetPacket())
+		info := entry.accounting.info()
+		assert.Equal(t, uint64(1), info.Stats.PktsIn)
+		assert.Equal(t, uint64(1), info.Stats.Drops[DropRateLimited])
+	})
+	t.Run("packets before the address is known are counted", func(t *testing.T) {
+		entry := newTableEntry(false)
+		entry.accounting = newAppAccounting(ia, nil, addr.SvcNone, 0)
+
+		sendPacket(entry, respool.GetPacket())
+		assert.Nil(t, entry.accounting.info().Public)
+		entry.accounting.setPublic(public)
+		defer entry.accounting.close()
+
+		info := entry.accounting.info()
+		assert.Equal(t, public, info.Public)
+		assert.Equal(t, uint64(1), info.Stats.PktsIn)
+	})
+}
+
+func TestServerApplications(t *testing.T) {
+	ipv4Conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	ipv6Conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	server, err := NewServer("", ipv4Conn, ipv6Conn)
+	require.NoError(t, err)
+	defer server.Close()
+	server.AppRateLimit = 1000
+
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	conn1, port1, err := server.Register(context.Background(), ia,
+		&net.UDPAddr{IP: net.IP{127, 0, 0, 1}, Port: 31001}, addr.SvcNone)
+	require.NoError(t, err)
+	conn2, _, err := server.Register(context.Background(), ia,
+		&net.UDPAddr{IP: net.IP{127, 0, 0, 1}, Port: 31000}, addr.SvcCS)
+	require.NoError(t, err)
+
+	apps := server.Applications()
+	require.Len(t, apps, 2)
+	assert.Equal(t, 31000, apps[0].Public.Port)
+	assert.Equal(t, addr.SvcCS, apps[0].SVC)
+	assert.Equal(t, int(port1), apps[1].Public.Port)
+	assert.Equal(t, 1000, apps[1].RateLimit)
+
+	require.NoError(t, conn2.Close())
+	apps = server.Applications()
+	require.Len(t, apps, 1)
+	assert.Equal(t, 31001, apps[0].Public.Port)
+	require.NoError(t, conn1.Close())
+	assert.Empty(t, server.Applications())
+}
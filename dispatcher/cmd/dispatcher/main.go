@@ -59,16 +59,18 @@ func realMain(ctx context.Context) error {
 
 	path.StrictDecoding(false)
 
+	dispatcher := &network.Dispatcher{
+		UnderlaySocket:    fmt.Sprintf(":%d", globalCfg.Dispatcher.UnderlayPort),
+		ApplicationSocket: globalCfg.Dispatcher.ApplicationSocket,
+		SocketFileMode:    os.FileMode(globalCfg.Dispatcher.SocketFileMode),
+		AppRateLimit:      globalCfg.Dispatcher.AppRateLimit,
+	}
+
 	var cleanup app.Cleanup
 	g, errCtx := errgroup.WithContext(ctx)
 	g.Go(func() error {
 		defer log.HandlePanic()
-		return RunDispatcher(
-			globalCfg.Dispatcher.DeleteSocket,
-			globalCfg.Dispatcher.ApplicationSocket,
-			os.FileMode(globalCfg.Dispatcher.SocketFileMode),
-			globalCfg.Dispatcher.UnderlayPort,
-		)
+		return RunDispatcher(globalCfg.Dispatcher.DeleteSocket, dispatcher)
 	})
 
 	// Initialise and start service management API endpoints.
@@ -80,9 +82,10 @@ func realMain(ctx context.Context) error {
 		r.Get("/", api.ServeSpecInteractive)
 		r.Get("/openapi.json", api.ServeSpecJSON)
 		server := api.Server{
-			Config:   service.NewConfigStatusPage(globalCfg).Handler,
-			Info:     service.NewInfoStatusPage().Handler,
-			LogLevel: service.NewLogLevelStatusPage().Handler,
+			Config:     service.NewConfigStatusPage(globalCfg).Handler,
+			Info:       service.NewInfoStatusPage().Handler,
+			LogLevel:   service.NewLogLevelStatusPage().Handler,
+			Dispatcher: dispatcher,
 		}
 		log.Info("Exposing API", "addr", globalCfg.API.Addr)
 		h := api.HandlerFromMuxWithBaseURL(&server, r, "/api/v1")
@@ -134,20 +137,14 @@ func realMain(ctx context.Context) error {
 	}
 }
 
-func RunDispatcher(deleteSocketFlag bool, applicationSocket string, socketFileMode os.FileMode,
-	underlayPort int) error {
-
+func RunDispatcher(deleteSocketFlag bool, dispatcher *network.Dispatcher) error {
 	if deleteSocketFlag {
 		if err := deleteSocket(globalCfg.Dispatcher.ApplicationSocket); err != nil {
 			return err
 		}
 	}
-	dispatcher := &network.Dispatcher{
-		UnderlaySocket:    fmt.Sprintf(":%d", underlayPort),
-		ApplicationSocket: applicationSocket,
-		SocketFileMode:    socketFileMode,
-	}
-	log.Debug("Dispatcher starting", "appSocket", applicationSocket, "underlayPort", underlayPort)
+	log.Debug("Dispatcher starting", "appSocket", dispatcher.ApplicationSocket,
+		"underlaySocket", dispatcher.UnderlaySocket)
 	return dispatcher.ListenAndServe()
 }
 
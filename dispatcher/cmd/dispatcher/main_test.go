@@ -27,6 +27,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/scionproto/scion/dispatcher/network"
 	"github.com/scionproto/scion/pkg/addr"
 	"github.com/scionproto/scion/pkg/private/xtest"
 	"github.com/scionproto/scion/pkg/snet"
@@ -422,8 +423,11 @@ func TestDataplaneIntegration(t *testing.T) {
 	settings := InitTestSettings(t, dispatcherTestPort)
 
 	go func() {
-		err := RunDispatcher(false, settings.ApplicationSocket, reliable.DefaultDispSocketFileMode,
-			settings.UnderlayPort)
+		err := RunDispatcher(false, &network.Dispatcher{
+			UnderlaySocket:    fmt.Sprintf(":%d", settings.UnderlayPort),
+			ApplicationSocket: settings.ApplicationSocket,
+			SocketFileMode:    reliable.DefaultDispSocketFileMode,
+		})
 		require.NoError(t, err, "dispatcher error")
 	}()
 	time.Sleep(defaultWaitDuration)
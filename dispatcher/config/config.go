@@ -54,6 +54,12 @@ type Dispatcher struct {
 	// DeleteSocket specifies whether the dispatcher should delete the
 	// socket file prior to attempting to create a new one.
 	DeleteSocket bool `toml:"delete_socket,omitempty"`
+	// AppRateLimit is the maximum rate in bytes per second at which each
+	// registered application can send and receive packets. The limit applies
+	// to each direction separately. Bursts of one second worth of traffic, but
+	// at least one maximum size packet, are allowed. If 0, the rate is not
+	// limited. (default 0)
+	AppRateLimit int `toml:"app_rate_limit,omitempty"`
 }
 
 func (cfg *Dispatcher) Validate() error {
@@ -69,6 +75,9 @@ func (cfg *Dispatcher) Validate() error {
 	if cfg.ID == "" {
 		return serrors.New("id must be set")
 	}
+	if cfg.AppRateLimit < 0 {
+		return serrors.New("app_rate_limit must not be negative", "value", cfg.AppRateLimit)
+	}
 	return nil
 }
 
@@ -45,6 +45,7 @@ func InitTestConfig(cfg *Config) {
 	envtest.InitTest(nil, &cfg.Metrics, nil, nil)
 	logtest.InitTestLogging(&cfg.Logging)
 	cfg.Dispatcher.DeleteSocket = true
+	cfg.Dispatcher.AppRateLimit = 1
 }
 
 func CheckTestConfig(t *testing.T, cfg *Config, id string) {
@@ -56,4 +57,5 @@ func CheckTestConfig(t *testing.T, cfg *Config, id string) {
 	assert.Equal(t, reliable.DefaultDispSocketFileMode, int(cfg.Dispatcher.SocketFileMode))
 	assert.Equal(t, topology.EndhostPort, cfg.Dispatcher.UnderlayPort)
 	assert.False(t, cfg.Dispatcher.DeleteSocket)
+	assert.Zero(t, cfg.Dispatcher.AppRateLimit)
 }
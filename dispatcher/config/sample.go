@@ -32,4 +32,10 @@ underlay_port = 30041
 
 # Remove the socket file (if it exists) on start. (default false)
 delete_socket = false
+
+# The maximum rate in bytes per second at which each registered application can
+# send and receive packets. The limit applies to each direction separately.
+# Bursts of one second worth of traffic, but at least one maximum size packet,
+# are allowed. If 0, the rate is not limited. (default 0)
+app_rate_limit = 0
 `
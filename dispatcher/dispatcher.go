@@ -15,16 +15,21 @@
 package dispatcher
 
 import (
+	"bytes"
 	"context"
 	"net"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/scionproto/scion/dispatcher/internal/registration"
 	"github.com/scionproto/scion/dispatcher/internal/respool"
 	"github.com/scionproto/scion/pkg/addr"
 	"github.com/scionproto/scion/pkg/log"
+	"github.com/scionproto/scion/pkg/private/common"
 	"github.com/scionproto/scion/pkg/private/serrors"
 	"github.com/scionproto/scion/pkg/slayers"
+	"github.com/scionproto/scion/pkg/sock/reliable"
 	"github.com/scionproto/scion/private/ringbuf"
 	"github.com/scionproto/scion/private/underlay/conn"
 )
@@ -36,12 +41,27 @@ const (
 	SendBufferSize = 1 << 20
 )
 
+// ErrRateLimited is returned when a packet is dropped because it exceeds the
+// rate limit of the application.
+const ErrRateLimited common.ErrMsg = "application rate limit exceeded"
+
 // Server is the main object allowing to create new SCION connections.
 type Server struct {
+	// AppRateLimit is the maximum rate in bytes per second at which each
+	// registered application can send and receive packets. The limit applies
+	// to each direction separately. If 0, the rate is not limited. It must
+	// be set before the first registration.
+	AppRateLimit int
+
 	// routingTable is used to register new connections.
 	routingTable *IATable
 	ipv4Conn     net.PacketConn
 	ipv6Conn     net.PacketConn
+
+	// appsMtx protects apps.
+	appsMtx sync.Mutex
+	// apps contains the accounting information of all registrations.
+	apps map[*appAccounting]struct{}
 }
 
 // NewServer creates new instance of Server. Internally, it opens the dispatcher ports
@@ -66,6 +86,7 @@ func NewServer(address string, ipv4Conn, ipv6Conn net.PacketConn) (*Server, erro
 		routingTable: NewIATable(32768, 65535),
 		ipv4Conn:     ipv4Conn,
 		ipv6Conn:     ipv6Conn,
+		apps:         make(map[*appAccounting]struct{}),
 	}, nil
 }
 
@@ -96,25 +117,76 @@ func (as *Server) Serve() error {
 func (as *Server) Register(ctx context.Context, ia addr.IA, address *net.UDPAddr,
 	svc addr.HostSVC) (net.PacketConn, uint16, error) {
 
-	tableEntry := newTableEntry()
+	return as.RegisterSCMPErrors(ctx, ia, address, svc, 0)
+}
+
+// RegisterSCMPErrors creates a new connection that additionally receives
+// notifications for SCMP errors of the given types. The notifications can be
+// read with ReadSCMPError on the returned connection.
+func (as *Server) RegisterSCMPErrors(ctx context.Context, ia addr.IA, address *net.UDPAddr,
+	svc addr.HostSVC, types reliable.SCMPErrorTypes) (net.PacketConn, uint16, error) {
+
+	scmpTypes := types.Types()
+	tableEntry := newTableEntry(len(scmpTypes) > 0)
+	// The accounting must exist before the entry is registered, because the
+	// entry is used by the packet processing goroutines right away. The port
+	// is only known after registering, and is set separately.
+	tableEntry.accounting = newAppAccounting(ia, nil, svc, as.AppRateLimit)
 	ref, err := as.routingTable.Register(ia, address, nil, svc, tableEntry)
 	if err != nil {
 		return nil, 0, err
 	}
+	tableEntry.accounting.setPublic(ref.UDPAddr())
+	if err := ref.RegisterSCMPErrors(scmpTypes); err != nil {
+		ref.Free()
+		tableEntry.accounting.close()
+		return nil, 0, err
+	}
 	var ovConn net.PacketConn
 	if address.IP.To4() == nil {
 		ovConn = as.ipv6Conn
 	} else {
 		ovConn = as.ipv4Conn
 	}
+	as.appsMtx.Lock()
+	as.apps[tableEntry.accounting] = struct{}{}
+	as.appsMtx.Unlock()
 	conn := &Conn{
-		conn:         ovConn,
-		ring:         tableEntry.appIngressRing,
-		regReference: ref,
+		conn:          ovConn,
+		ring:          tableEntry.appIngressRing,
+		scmpErrorRing: tableEntry.scmpErrorRing,
+		regReference:  ref,
+		accounting:    tableEntry.accounting,
+		unregister: func() {
+			as.appsMtx.Lock()
+			defer as.appsMtx.Unlock()
+			delete(as.apps, tableEntry.accounting)
+		},
 	}
 	return conn, uint16(ref.UDPAddr().Port), nil
 }
 
+// Applications returns information about the traffic of all registered
+// applications, sorted by ISD-AS, address and port.
+func (as *Server) Applications() []AppInfo {
+	as.appsMtx.Lock()
+	defer as.appsMtx.Unlock()
+	infos := make([]AppInfo, 0, len(as.apps))
+	for a := range as.apps {
+		infos = append(infos, a.info())
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].IA != infos[j].IA {
+			return infos[i].IA < infos[j].IA
+		}
+		if c := bytes.Compare(infos[i].Public.IP, infos[j].Public.IP); c != 0 {
+			return c < 0
+		}
+		return infos[i].Public.Port < infos[j].Public.Port
+	})
+	return infos
+}
+
 func (as *Server) Close() {
 	as.ipv4Conn.Close()
 	as.ipv6Conn.Close()
@@ -126,8 +198,15 @@ type Conn struct {
 	conn net.PacketConn
 	// ring is used to retrieve incoming packets.
 	ring *ringbuf.Ring
+	// scmpErrorRing is used to retrieve SCMP error notifications. It is nil if
+	// the connection does not subscribe to SCMP errors.
+	scmpErrorRing *ringbuf.Ring
 	// regReference is the reference to the registration in the routing table.
 	regReference registration.RegReference
+	// accounting tracks the traffic of the registration.
+	accounting *appAccounting
+	// unregister removes the registration from the server.
+	unregister func()
 }
 
 func (ac *Conn) WriteTo(p []byte, addr net.Addr) (int, error) {
@@ -142,7 +221,16 @@ func (ac *Conn) Write(pkt *respool.Packet) (int, error) {
 	// If this becomes ever a problem, we can namespace the ID per registered
 	// application.
 	registerIfSCMPInfo(ac.regReference, pkt)
-	return pkt.SendOnConn(ac.conn, pkt.UnderlayRemote)
+	if !ac.accounting.allowOut(pkt.Len()) {
+		return 0, ErrRateLimited
+	}
+	n, err := pkt.SendOnConn(ac.conn, pkt.UnderlayRemote)
+	if err != nil {
+		ac.accounting.drop(DropSendError)
+		return n, err
+	}
+	ac.accounting.countOut(n)
+	return n, nil
 }
 
 func (ac *Conn) ReadFrom(p []byte) (n int, addr net.Addr, err error) {
@@ -168,9 +256,35 @@ func (ac *Conn) Read() *respool.Packet {
 	return pkt
 }
 
+// SubscribesSCMPErrors returns whether the connection receives SCMP error
+// notifications.
+func (ac *Conn) SubscribesSCMPErrors() bool {
+	return ac.scmpErrorRing != nil
+}
+
+// ReadSCMPError blocks until it reads the next SCMP error notification. It
+// returns nil if the connection was closed or does not subscribe to SCMP
+// errors.
+func (ac *Conn) ReadSCMPError() *reliable.SCMPErrorNotification {
+	if ac.scmpErrorRing == nil {
+		return nil
+	}
+	entries := make(ringbuf.EntryList, 1)
+	n, _ := ac.scmpErrorRing.Read(entries, true)
+	if n < 0 {
+		return nil
+	}
+	return entries[0].(*reliable.SCMPErrorNotification)
+}
+
 func (ac *Conn) Close() error {
+	ac.unregister()
+	ac.accounting.close()
 	ac.regReference.Free()
 	ac.ring.Close()
+	if ac.scmpErrorRing != nil {
+		ac.scmpErrorRing.Close()
+	}
 	return nil
 }
 
@@ -81,6 +81,71 @@ func (l SCMP) Values() []string {
 	return []string{"class", "type"}
 }
 
+// Traffic direction labels
+const (
+	DirectionIn  = "in"
+	DirectionOut = "out"
+)
+
+// App contains the labels that identify a registered application.
+type App struct {
+	IA   string
+	Port string
+	SVC  string
+}
+
+// Labels returns the list of labels.
+func (l App) Labels() []string {
+	return []string{"isd_as", "port", "svc"}
+}
+
+// Values returns the label values in the order defined by Labels.
+func (l App) Values() []string {
+	return []string{l.IA, l.Port, l.SVC}
+}
+
+// WithDirection returns the labels extended with the traffic direction.
+func (l App) WithDirection(direction string) AppDirection {
+	return AppDirection{App: l, Direction: direction}
+}
+
+// WithReason returns the labels extended with the drop reason.
+func (l App) WithReason(reason string) AppDrop {
+	return AppDrop{App: l, Reason: reason}
+}
+
+// AppDirection contains the labels for per-application traffic metrics.
+type AppDirection struct {
+	App
+	Direction string
+}
+
+// Labels returns the list of labels.
+func (l AppDirection) Labels() []string {
+	return append(l.App.Labels(), "direction")
+}
+
+// Values returns the label values in the order defined by Labels.
+func (l AppDirection) Values() []string {
+	return append(l.App.Values(), l.Direction)
+}
+
+// AppDrop contains the labels for per-application drop metrics.
+type AppDrop struct {
+	App
+	Reason string
+}
+
+// Labels returns the list of labels.
+func (l AppDrop) Labels() []string {
+	return append(l.App.Labels(), "reason")
+}
+
+// Values returns the label values in the order defined by Labels.
+func (l AppDrop) Values() []string {
+	return append(l.App.Values(), l.Reason)
+}
+
 type metrics struct {
 	netWriteBytes      prometheus.Counter
 	netWritePkts       prometheus.Counter
@@ -101,6 +166,9 @@ type metrics struct {
 	appNotFoundErrors  prometheus.Counter
 	appWriteSVCPkts    *prometheus.CounterVec
 	netReadOverflows   prometheus.Counter
+	appPkts            *prometheus.CounterVec
+	appBytes           *prometheus.CounterVec
+	appDrops           *prometheus.CounterVec
 }
 
 func newMetrics() metrics {
@@ -143,6 +211,12 @@ func newMetrics() metrics {
 			"Total SVC packets delivered to applications", SVC{}),
 		netReadOverflows: prom.NewCounter(Namespace, "", "net_read_overflow_pkts_total",
 			"Total ingress packets that were dropped on the OS socket"),
+		appPkts: prom.NewCounterVecWithLabels(Namespace, "", "app_pkts_total",
+			"Total packets per registered application and direction.", AppDirection{}),
+		appBytes: prom.NewCounterVecWithLabels(Namespace, "", "app_bytes_total",
+			"Total bytes per registered application and direction.", AppDirection{}),
+		appDrops: prom.NewCounterVecWithLabels(Namespace, "", "app_dropped_pkts_total",
+			"Total dropped packets per registered application and reason.", AppDrop{}),
 	}
 }
 
@@ -223,3 +297,30 @@ func (m metrics) AppWriteSVCPkts(labels SVC) prometheus.Counter {
 func (m metrics) NetReadOverflows() prometheus.Counter {
 	return m.netReadOverflows
 }
+
+// AppPkts returns the packet counter of a registered application.
+func (m metrics) AppPkts(labels AppDirection) prometheus.Counter {
+	return m.appPkts.WithLabelValues(labels.Values()...)
+}
+
+// AppBytes returns the byte counter of a registered application.
+func (m metrics) AppBytes(labels AppDirection) prometheus.Counter {
+	return m.appBytes.WithLabelValues(labels.Values()...)
+}
+
+// AppDrops returns the drop counter of a registered application.
+func (m metrics) AppDrops(labels AppDrop) prometheus.Counter {
+	return m.appDrops.WithLabelValues(labels.Values()...)
+}
+
+// DeleteApp removes the per-application series of the application. The
+// reasons are the possible drop reasons.
+func (m metrics) DeleteApp(labels App, reasons ...string) {
+	for _, direction := range []string{DirectionIn, DirectionOut} {
+		m.appPkts.DeleteLabelValues(labels.WithDirection(direction).Values()...)
+		m.appBytes.DeleteLabelValues(labels.WithDirection(direction).Values()...)
+	}
+	for _, reason := range reasons {
+		m.appDrops.DeleteLabelValues(labels.WithReason(reason).Values()...)
+	}
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/scionproto/scion/pkg/addr"
 	"github.com/scionproto/scion/pkg/private/common"
+	"github.com/scionproto/scion/pkg/slayers"
 )
 
 const (
@@ -46,6 +47,12 @@ type RegReference interface {
 	// SCMP messages targeted at the ID will get sent to the socket associated
 	// with the reference. The IA of the id is set to the IA of the reference.
 	RegisterID(id uint64) error
+	// RegisterSCMPErrors subscribes this reference to the SCMP error types
+	// types. The subscriptions are released when the reference is freed. SCMP
+	// errors of a subscribed type that cannot be attributed to a single
+	// registration get sent to the socket associated with the reference, if
+	// the offending packet was sent from the IP address of the reference.
+	RegisterSCMPErrors(types []slayers.SCMPType) error
 }
 
 // IATable manages the UDP/IP port registrations for a SCION Dispatcher.
@@ -93,6 +100,10 @@ type IATable interface {
 	// If an entry is found, the returned boolean is set to true. Otherwise, it
 	// is set to false.
 	LookupID(ia addr.IA, id uint64) (interface{}, bool)
+	// LookupSCMPErrors returns the entries in AS ia that subscribed to SCMP
+	// errors of type typ for the local host address host. Entries registered
+	// on wildcard addresses match all hosts of the same address family.
+	LookupSCMPErrors(ia addr.IA, host net.IP, typ slayers.SCMPType) []interface{}
 }
 
 // NewIATable creates a new UDP/IP port registration table.
@@ -178,6 +189,17 @@ func (t *iaTable) LookupID(ia addr.IA, id uint64) (interface{}, bool) {
 	return nil, false
 }
 
+func (t *iaTable) LookupSCMPErrors(ia addr.IA, host net.IP,
+	typ slayers.SCMPType) []interface{} {
+
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+	if table, ok := t.ia[ia]; ok {
+		return table.LookupSCMPErrors(typ, host)
+	}
+	return nil
+}
+
 var _ RegReference = (*iaTableReference)(nil)
 
 type iaTableReference struct {
@@ -211,3 +233,9 @@ func (r *iaTableReference) RegisterID(id uint64) error {
 	defer r.table.mtx.Unlock()
 	return r.entryRef.RegisterID(id, r.value)
 }
+
+func (r *iaTableReference) RegisterSCMPErrors(types []slayers.SCMPType) error {
+	r.table.mtx.Lock()
+	defer r.table.mtx.Unlock()
+	return r.entryRef.RegisterSCMPErrors(types, r.value)
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/scionproto/scion/pkg/addr"
 	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/pkg/slayers"
 )
 
 var (
@@ -33,6 +34,22 @@ var (
 
 func TestIATable(t *testing.T) {
 
+	t.Run("SCMP error subscriptions are released on free", func(t *testing.T) {
+		table := NewIATable(minPort, maxPort)
+		ref, err := table.Register(ia, public, nil, addr.SvcNone, value)
+		require.NoError(t, err)
+		err = ref.RegisterSCMPErrors([]slayers.SCMPType{slayers.SCMPTypePacketTooBig})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{value},
+			table.LookupSCMPErrors(ia, public.IP, slayers.SCMPTypePacketTooBig))
+		assert.Empty(t, table.LookupSCMPErrors(ia, public.IP,
+			slayers.SCMPTypeDestinationUnreachable))
+		assert.Empty(t, table.LookupSCMPErrors(xtest.MustParseIA("1-ff00:0:2"), public.IP,
+			slayers.SCMPTypePacketTooBig))
+		ref.Free()
+		assert.Empty(t, table.LookupSCMPErrors(ia, public.IP, slayers.SCMPTypePacketTooBig))
+	})
+
 	t.Run("Given a table with one entry that is only public and no svc", func(t *testing.T) {
 		table := NewIATable(minPort, maxPort)
 		ref, err := table.Register(ia, public, nil, addr.SvcNone, value)
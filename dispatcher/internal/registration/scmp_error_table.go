@@ -0,0 +1,108 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registration
+
+import (
+	"net"
+
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/slayers"
+)
+
+// SCMPErrorTable contains subscriptions to SCMP error types. Subscriptions are
+// scoped to the local host address of the subscriber, such that SCMP errors
+// that cannot be attributed to a single registration (e.g., because the quoted
+// packet is truncated) are only delivered to the subscribers on the host that
+// sent the offending packet.
+type SCMPErrorTable struct {
+	m map[slayers.SCMPType][]*scmpErrorSubscription
+}
+
+type scmpErrorSubscription struct {
+	host  net.IP
+	value interface{}
+}
+
+func NewSCMPErrorTable() *SCMPErrorTable {
+	return &SCMPErrorTable{m: make(map[slayers.SCMPType][]*scmpErrorSubscription)}
+}
+
+// Lookup returns the values subscribed to typ on host. Subscriptions on
+// unspecified addresses match all hosts of the same address family.
+func (t *SCMPErrorTable) Lookup(typ slayers.SCMPType, host net.IP) []interface{} {
+	var values []interface{}
+	for _, s := range t.m[typ] {
+		if s.matches(host) {
+			values = append(values, s.value)
+		}
+	}
+	return values
+}
+
+// Register subscribes value to SCMP errors of type typ on host. To remove the
+// subscription, free the returned reference.
+func (t *SCMPErrorTable) Register(typ slayers.SCMPType, host net.IP,
+	value interface{}) (Reference, error) {
+
+	if value == nil {
+		return nil, serrors.New("cannot register nil value")
+	}
+	if host == nil {
+		return nil, serrors.New("cannot register nil host")
+	}
+	if slayers.CreateSCMPTypeCode(typ, 0).InfoMsg() {
+		return nil, serrors.New("not an SCMP error type", "type", typ)
+	}
+	s := &scmpErrorSubscription{host: host, value: value}
+	t.m[typ] = append(t.m[typ], s)
+	return &scmpErrorReference{table: t, typ: typ, subscription: s}, nil
+}
+
+func (t *SCMPErrorTable) remove(typ slayers.SCMPType, s *scmpErrorSubscription) {
+	subscriptions := t.m[typ]
+	for i, other := range subscriptions {
+		if other == s {
+			subscriptions = append(subscriptions[:i], subscriptions[i+1:]...)
+			break
+		}
+	}
+	if len(subscriptions) == 0 {
+		delete(t.m, typ)
+		return
+	}
+	t.m[typ] = subscriptions
+}
+
+func (s *scmpErrorSubscription) matches(host net.IP) bool {
+	if s.host.IsUnspecified() {
+		return (s.host.To4() != nil) == (host.To4() != nil)
+	}
+	return s.host.Equal(host)
+}
+
+type scmpErrorReference struct {
+	table        *SCMPErrorTable
+	typ          slayers.SCMPType
+	subscription *scmpErrorSubscription
+	freed        bool
+}
+
+func (r *scmpErrorReference) Free() {
+	if r.freed {
+		panic("double free")
+	}
+	r.freed = true
+	r.table.remove(r.typ, r.subscription)
+}
@@ -0,0 +1,70 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registration
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/pkg/slayers"
+)
+
+func TestSCMPErrorTable(t *testing.T) {
+	host := net.IP{192, 0, 2, 1}
+	otherHost := net.IP{192, 0, 2, 2}
+	ptb := slayers.SCMPTypePacketTooBig
+
+	t.Run("invalid registrations fail", func(t *testing.T) {
+		table := NewSCMPErrorTable()
+		_, err := table.Register(ptb, host, nil)
+		assert.Error(t, err)
+		_, err = table.Register(ptb, nil, value)
+		assert.Error(t, err)
+		_, err = table.Register(slayers.SCMPTypeEchoReply, host, value)
+		assert.Error(t, err)
+	})
+	t.Run("lookup matches type and host", func(t *testing.T) {
+		table := NewSCMPErrorTable()
+		ref, err := table.Register(ptb, host, value)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{value}, table.Lookup(ptb, host))
+		assert.Empty(t, table.Lookup(ptb, otherHost))
+		assert.Empty(t, table.Lookup(slayers.SCMPTypeExternalInterfaceDown, host))
+
+		ref.Free()
+		assert.Empty(t, table.Lookup(ptb, host))
+		assert.Panics(t, ref.Free)
+	})
+	t.Run("wildcard host matches same address family", func(t *testing.T) {
+		table := NewSCMPErrorTable()
+		_, err := table.Register(ptb, net.IPv4zero, value)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{value}, table.Lookup(ptb, otherHost))
+		assert.Empty(t, table.Lookup(ptb, net.ParseIP("2001:db8::1")))
+	})
+	t.Run("multiple subscribers", func(t *testing.T) {
+		table := NewSCMPErrorTable()
+		ref, err := table.Register(ptb, host, "a")
+		require.NoError(t, err)
+		_, err = table.Register(ptb, host, "b")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []interface{}{"a", "b"}, table.Lookup(ptb, host))
+		ref.Free()
+		assert.Equal(t, []interface{}{"b"}, table.Lookup(ptb, host))
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"net"
 
 	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/slayers"
 )
 
 // Table manages the UDP/IP port registrations for a single AS.
@@ -34,13 +35,16 @@ type Table struct {
 	// e.g., if apps start with an ID of 1 and increment from there). We should
 	// revisit if SCMP General IDs should be scoped to IPs.
 	scmpTable *SCMPTable
+	// scmpErrorTable contains the subscriptions to SCMP error types.
+	scmpErrorTable *SCMPErrorTable
 }
 
 func NewTable(minPort, maxPort int) *Table {
 	return &Table{
-		udpPortTable: NewUDPPortTable(minPort, maxPort),
-		svcTable:     NewSVCTable(),
-		scmpTable:    NewSCMPTable(),
+		udpPortTable:   NewUDPPortTable(minPort, maxPort),
+		svcTable:       NewSVCTable(),
+		scmpTable:      NewSCMPTable(),
+		scmpErrorTable: NewSCMPErrorTable(),
 	}
 }
 
@@ -106,12 +110,18 @@ func (t *Table) removeID(id uint64) {
 	t.scmpTable.Remove(id)
 }
 
+func (t *Table) LookupSCMPErrors(typ slayers.SCMPType, host net.IP) []interface{} {
+	return t.scmpErrorTable.Lookup(typ, host)
+}
+
 type TableReference struct {
 	table   *Table
 	freed   bool
 	address *net.UDPAddr
 	svcRef  Reference
 	ids     []uint64
+	// scmpErrorRefs are the references to the SCMP error subscriptions.
+	scmpErrorRefs []Reference
 }
 
 func (r *TableReference) Free() {
@@ -127,6 +137,9 @@ func (r *TableReference) Free() {
 	for _, id := range r.ids {
 		r.table.removeID(id)
 	}
+	for _, ref := range r.scmpErrorRefs {
+		ref.Free()
+	}
 }
 
 func (r *TableReference) UDPAddr() *net.UDPAddr {
@@ -140,3 +153,24 @@ func (r *TableReference) RegisterID(id uint64, value interface{}) error {
 	r.ids = append(r.ids, id)
 	return nil
 }
+
+// RegisterSCMPErrors subscribes the value to the SCMP error types types. The
+// subscriptions are scoped to the IP address of the public address of the
+// reference, and are released when the reference is freed.
+func (r *TableReference) RegisterSCMPErrors(types []slayers.SCMPType,
+	value interface{}) error {
+
+	var refs []Reference
+	for _, typ := range types {
+		ref, err := r.table.scmpErrorTable.Register(typ, r.address.IP, value)
+		if err != nil {
+			for _, ref := range refs {
+				ref.Free()
+			}
+			return err
+		}
+		refs = append(refs, ref)
+	}
+	r.scmpErrorRefs = append(r.scmpErrorRefs, refs...)
+	return nil
+}
@@ -15,14 +15,26 @@
 package mgmtapi
 
 import (
+	"encoding/json"
 	"net/http"
+
+	"github.com/scionproto/scion/dispatcher"
+	"github.com/scionproto/scion/pkg/addr"
+	api "github.com/scionproto/scion/private/mgmtapi"
 )
 
+// Dispatcher provides information about the applications registered with the
+// dispatcher.
+type Dispatcher interface {
+	Applications() []dispatcher.AppInfo
+}
+
 // Server implements the Dispatcher Service API.
 type Server struct {
-	Config   http.HandlerFunc
-	Info     http.HandlerFunc
-	LogLevel http.HandlerFunc
+	Config     http.HandlerFunc
+	Info       http.HandlerFunc
+	LogLevel   http.HandlerFunc
+	Dispatcher Dispatcher
 }
 
 // GetConfig is an indirection to the http handler.
@@ -44,3 +56,52 @@ func (s *Server) GetLogLevel(w http.ResponseWriter, r *http.Request) {
 func (s *Server) SetLogLevel(w http.ResponseWriter, r *http.Request) {
 	s.LogLevel(w, r)
 }
+
+// GetApplications lists the registered applications and their traffic.
+func (s *Server) GetApplications(w http.ResponseWriter, r *http.Request) {
+	infos := s.Dispatcher.Applications()
+	apps := make([]Application, 0, len(infos))
+	for _, info := range infos {
+		drops := make(map[string]int64, len(info.Stats.Drops))
+		for reason, count := range info.Stats.Drops {
+			drops[string(reason)] = int64(count)
+		}
+		app := Application{
+			Address:   info.Public.String(),
+			IsdAs:     IsdAs(info.IA.String()),
+			RateLimit: info.RateLimit,
+			Traffic: ApplicationTraffic{
+				BytesIn:    int64(info.Stats.BytesIn),
+				BytesOut:   int64(info.Stats.BytesOut),
+				PacketsIn:  int64(info.Stats.PktsIn),
+				PacketsOut: int64(info.Stats.PktsOut),
+				Drops:      ApplicationTraffic_Drops{AdditionalProperties: drops},
+			},
+		}
+		if info.SVC != addr.SvcNone {
+			app.Svc = api.StringRef(info.SVC.BaseString())
+		}
+		apps = append(apps, app)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(ApplicationsResponse{Applications: apps}); err != nil {
+		Error(w, Problem{
+			Detail: api.StringRef(err.Error()),
+			Status: http.StatusInternalServerError,
+			Title:  "unable to marshal response",
+			Type:   api.StringRef(api.InternalError),
+		})
+		return
+	}
+}
+
+// Error creates an detailed error response.
+func Error(w http.ResponseWriter, p Problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	// no point in catching error here, there is nothing we can do about it anymore.
+	enc.Encode(p)
+}
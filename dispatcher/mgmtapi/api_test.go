@@ -0,0 +1,111 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgmtapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/dispatcher"
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/private/xtest"
+)
+
+var update = xtest.UpdateGoldenFiles()
+
+type fakeDispatcher []dispatcher.AppInfo
+
+func (d fakeDispatcher) Applications() []dispatcher.AppInfo {
+	return d
+}
+
+func TestAPI(t *testing.T) {
+	testCases := map[string]struct {
+		Handler      http.Handler
+		RequestURL   string
+		ResponseFile string
+		Status       int
+	}{
+		"applications": {
+			Handler: Handler(&Server{
+				Dispatcher: fakeDispatcher{
+					{
+						IA:     xtest.MustParseIA("1-ff00:0:110"),
+						Public: xtest.MustParseUDPAddr(t, "127.0.0.1:31000"),
+						SVC:    addr.SvcNone,
+						Stats: dispatcher.AppStats{
+							PktsIn:   10,
+							BytesIn:  1000,
+							PktsOut:  5,
+							BytesOut: 500,
+							Drops: map[dispatcher.DropReason]uint64{
+								dispatcher.DropRingFull:    1,
+								dispatcher.DropRateLimited: 0,
+								dispatcher.DropSendError:   0,
+							},
+						},
+					},
+					{
+						IA:        xtest.MustParseIA("1-ff00:0:110"),
+						Public:    xtest.MustParseUDPAddr(t, "127.0.0.1:31001"),
+						SVC:       addr.SvcCS,
+						RateLimit: 1000000,
+						Stats: dispatcher.AppStats{
+							Drops: map[dispatcher.DropReason]uint64{
+								dispatcher.DropRingFull:    0,
+								dispatcher.DropRateLimited: 7,
+								dispatcher.DropSendError:   0,
+							},
+						},
+					},
+				},
+			}),
+			RequestURL:   "/applications",
+			ResponseFile: "testdata/applications.json",
+			Status:       200,
+		},
+		"no applications": {
+			Handler:      Handler(&Server{Dispatcher: fakeDispatcher{}}),
+			RequestURL:   "/applications",
+			ResponseFile: "testdata/applications-empty.json",
+			Status:       200,
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("GET", tc.RequestURL, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			tc.Handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Result().StatusCode)
+			if *update {
+				require.NoError(t, os.WriteFile(tc.ResponseFile, rr.Body.Bytes(), 0666))
+			}
+			golden, err := os.ReadFile(tc.ResponseFile)
+			require.NoError(t, err)
+			assert.Equal(t, string(golden), rr.Body.String())
+		})
+	}
+}
@@ -18,17 +18,37 @@ import (
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/9xVTW8bIRD9K2ja48rrfJz21qRVZMlporq3yAfMjtdELNCBtWJZ+9+rAduJ14lS9RCp",
-	"vcEAb948HsMWlGu9s2hjgGoLhME7GzBNrmT9A391GCLPlLMRbRpK741WMmpny8fgLMeCWmErefSZcAkV",
-	"fCqfocu8GspZlLaWVH8jcgR93xdQY1CkPYNBxTkF7ZLy6u4g405dM8U1Gh57ch4p6kzU7MPHWFPXNNo2",
-	"Ii8XgLZroXqAGhddAwVou3QcTlzmBeCTbL1BqPYrceN5FiJp2yQ6TE0T1gyTYeeHbW7xiCpCX8BxlSd0",
-	"cR8+ppt2ixZDkA2+m/3AepCd9yX6J/gzpLVWKG6llQ22aKP4cj8RS0cirlDMrid338VXHbyMaoXEDHRM",
-	"cjwHB4ehgDVSyPjj0dlozOU7j1Z6DRVcjMajcyjAy7hKpZfK2aVueNhg8hILk5w0qaGCG4zXeUdx7MXz",
-	"8XhgwohPsfRG6oH9hrKdWGzWKYUhLDsj7vbJmfZlTvGafQ9UyhdvItmza1tJG6jgnrSNISn58+52KnKh",
-	"XYYXS21wxIrKJvDtKde2zsKcMcr9db2lyCS78d/S40oGrQSXRm3WwMsGhVy4LiaVmDo5I8LOlp4c03hT",
-	"JeOa8vDQ35Lq0CPelevve9ghx4dpeYNRmEEzO9GoAN+9IspsIErCv3L15kP02Lfgl/lzB4vUYf9f3dLs",
-	"T24pHUHirgnVwxY6MlDBKkZfleV25ULsq613FPtSel2uz7jFStJyYbJGvCX39qXsTIQKjFPSpDB7wNFg",
-	"+WJ8eXnGKswPdIY/w3Vil34DfPIuYC0Wm92HsHue6V1a2XIr2RXTz/vfAQAA//9Se+9IPwgAAA==",
+	"H4sIAAAAAAAC/9xYW3PjthX+K2eQPDQTSqJs57J6c7xpqhlv12N524fE9UDEIYmYBBgA1Fp19d87ByBp",
+	"kqLX3k6bTvtGEsC5fOc7F/CRJbqstELlLFs9MoO20sqif/mBi2v8rUbr6C3RyqHyj7yqCplwJ7Va/Gq1",
+	"om82ybHk9PSlwZSt2BeLJ9GLsGoXG8eV4Eb8aIw27HA4REygTYysSBhbkU4wjVJabQ6S3PMnrfRaGV2h",
+	"cTLYyoUwaP3jUOBVvS1kApuL9fs/Lz68vYJmJ+gUXI5gMJPWGS92ziKGD7ysCmQrtnxzMl9++/38ZH6y",
+	"Ol3Gccwi5vYVLVlnpMrYIWLSijtuX3J7bcW5pe2GO7wrZCndsaXX3CH4NZAKtnuHFio0YDHRSoDLuQOP",
+	"PFpwGpAnOQhpMPGmQwwlcmXDPu8ZyZMWlHZBLIqBg3HnjlQOMzRkoN0lx5Zt/nLxKdgikClwtR/Cd7GZ",
+	"wssZnqYyeQmwXqxvmhPEBmKGNCjY6ucW+agL/QDdJ023EXPSeZN6UhsH0KCAj9Ll3ikhbcVdkqOZP9mu",
+	"t79i4sj2CaOOoFqrRJdSZdCopwC0j6nRpdej0H3U5p6iSK+9dIpA1y7TLwnonWiFNDLJ8GFmeCLdSZ8z",
+	"XXTOTuI4Yqk2JXeBAN+esSk+hNO6dv/acWF01eanJHN5cTWw7jUyBgC/NbqqUEDFk3t0FrZ7ICVgkNtR",
+	"Bj/2CEGcOY0Y0fAurYvC09+iEnfoS9EqPkxEvFFyjN6rnG9PH6P3iuMjtvcsiZ5COtTRj1YLfY/9DWch",
+	"0bVyaHwqc9Xn0kust9dNe5iov71d9C4dlvYzspw9wc+N4fsjAAYKbifsDDW2jzNbztI0jlfxarmMPVbO",
+	"oVFsxf72yy/i69kffuazNJ69uX1cRmeH1VePJ4fhp6/+Qfu+ZE8YrjdvZ+cbWAtUTqYSzVSNu9TZJe6w",
+	"OEapaD8POX2ps4xSPixHDFVdks8Ct3XGIiZVqumzZ+ptv8g2KyMTRtAFsVOYXRm9LbA8NlSg43LC0nPI",
+	"65IryjbBtwUCPlQFV6ES2QoTSQzzJUla0ElSG4MqwbZtVEFhaFLSQo5FldYFnSh0Qg2rv4srAZncIXCx",
+	"kyREQa4/0ubK6ARRzOGvRjqHCqSCH1VWSJv7U519qTaAKpMK0dgIalvzotj7nmhrKgt+h9IKHCa5kgkv",
+	"wDp+j7kuBCUJSaPdZF4h/z7qoexCKxU6MJkluONbbhGcLFFASMTjmUFZx1WCU/B+uF6DwRQDagGmlmzW",
+	"g9Oh/Cy6EeA8m1NlpKqrMuCQGp6VqHrCDGgDtt7OKu7yELFeePYVzuEd38MWobYoRgEyWrugVNrukFTB",
+	"Pl2bBCHRAodQLZqNi6TDbOYp/YXT96hmxOUZBW7m0ZsF9LpCWRs565CZgtU67uqJCfAmR/jTzc0VhA3e",
+	"MshQIXUHQTCR2drITCqwaHZoPCk+TeGBb9/EpxEr+YMsKXG/efMmYqVU4W0ZTw5aTUU5ZoDNtSFyliU3",
+	"+6O88YH5b5N+g8bn4wfFd1wWpHNy1ttXjYcprwuKId/q2q22BVf3LHoN92slf6ux2I+ToI8HaFXsW/b5",
+	"W8qD6+G2kwIFnF+t5/C+qnRD5n4mheolFVz/8WL23ffxdxFIX50USpejAYOJLktUIpzdIghsDfWAE16V",
+	"lsrRMg81ctaFQ+ikpuQLepQ2kBV660MS/GvoNgrz65LnM1Jk1BaafGmpONUfhte1oy6B7edhJP1uKNFa",
+	"nr1sRtfVRtoPh6bxHV9HGvq944pn6Mva+dW6A9Ff9eBtN8v32vfTx9FhFrEdGhvkx/PlPCb3dYWKV5Kt",
+	"2Ok8np+EESL3ri/GA0+GE1e6S2ndeGBvbmjc4At3kAicztATsF2VBtxoiiOaUEy87LVgK/YTuv7MxqLh",
+	"rf4kjv9t1/nJ2XDiVu9x0Gnf4T4icwL77JN2NfT/+vPsa+ebCZPWascL2f1smIe/DaHq9iP3jMlEKp7Z",
+	"0WTKbkkK5Wgqsx4rjgJ0EXa8GBqqZYuq4HLk9Diljtzb1EmC1tJs9b5V3kN5CrTOlEXvx88QlSsjlQsV",
+	"+Ob9u0sIjtZBPKSywHkPGCqaHSZtKj+HyDpMsv9bePzArUxAqlCBCYOKZwi+zXXtyOgCbFOy/Nxq7bMo",
+	"FTpbdJeE56Dq7hf/wczudPxuWP6EDorRRegIo4hV9QQomxEoXv4PWux/Fzza61tff+huztR4+L+K0uY1",
+	"UfJH/CBN3x9ZbQq2Yrlz1WqxeMy1dYfVY6WNOyx4JRe7JbVfbiRNSx4j2jKcHP0k6j8TB7QZLZ/GZ2dL",
+	"QuG2M+doKtmh2bucDDdY+NHf6ec7UsQUL/3viMFPirHUC++znz/wIUyX230zgjRJ3xfWQHS4PfxzAHVN",
+	"E8N6FwAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
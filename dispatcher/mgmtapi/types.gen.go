@@ -3,6 +3,11 @@
 // Code generated by unknown module path version unknown version DO NOT EDIT.
 package mgmtapi
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 // Defines values for LogLevelLevel.
 const (
 	LogLevelLevelDebug LogLevelLevel = "debug"
@@ -12,6 +17,46 @@ const (
 	LogLevelLevelInfo LogLevelLevel = "info"
 )
 
+// Application defines model for Application.
+type Application struct {
+	// Public SCION/UDP address of the registration.
+	Address string `json:"address"`
+	IsdAs   IsdAs  `json:"isd_as"`
+
+	// Rate limit in bytes per second that applies to each direction. 0 means that the rate is not limited.
+	RateLimit int `json:"rate_limit"`
+
+	// SVC address of the registration, if any.
+	Svc *string `json:"svc,omitempty"`
+
+	// Incoming traffic is traffic from the network to the application, outgoing traffic is traffic from the application to the network.
+	Traffic ApplicationTraffic `json:"traffic"`
+}
+
+// Incoming traffic is traffic from the network to the application, outgoing traffic is traffic from the application to the network.
+type ApplicationTraffic struct {
+	BytesIn  int64 `json:"bytes_in"`
+	BytesOut int64 `json:"bytes_out"`
+
+	// Dropped packets by drop reason.
+	Drops      ApplicationTraffic_Drops `json:"drops"`
+	PacketsIn  int64                    `json:"packets_in"`
+	PacketsOut int64                    `json:"packets_out"`
+}
+
+// Dropped packets by drop reason.
+type ApplicationTraffic_Drops struct {
+	AdditionalProperties map[string]int64 `json:"-"`
+}
+
+// ApplicationsResponse defines model for ApplicationsResponse.
+type ApplicationsResponse struct {
+	Applications []Application `json:"applications"`
+}
+
+// IsdAs defines model for IsdAs.
+type IsdAs string
+
 // LogLevel defines model for LogLevel.
 type LogLevel struct {
 	// Logging level
@@ -21,6 +66,24 @@ type LogLevel struct {
 // Logging level
 type LogLevelLevel string
 
+// Problem defines model for Problem.
+type Problem struct {
+	// A human readable explanation specific to this occurrence of the problem that is helpful to locate the problem and give advice on how to proceed. Written in English and readable for engineers, usually not suited for non technical stakeholders and not localized.
+	Detail *string `json:"detail,omitempty"`
+
+	// A URI reference that identifies the specific occurrence of the problem, e.g. by adding a fragment identifier or sub-path to the problem type. May be used to locate the root of this problem in the source code.
+	Instance *string `json:"instance,omitempty"`
+
+	// The HTTP status code generated by the origin server for this occurrence of the problem.
+	Status int `json:"status"`
+
+	// A short summary of the problem type. Written in English and readable for engineers, usually not suited for non technical stakeholders and not localized.
+	Title string `json:"title"`
+
+	// A URI reference that uniquely identifies the problem type only in the context of the provided API. Opposed to the specification in RFC-7807, it is neither recommended to be dereferencable and point to a human-readable documentation nor globally unique for the problem type.
+	Type *string `json:"type,omitempty"`
+}
+
 // StandardError defines model for StandardError.
 type StandardError struct {
 	// Error message
@@ -35,3 +98,56 @@ type SetLogLevelJSONBody LogLevel
 
 // SetLogLevelJSONRequestBody defines body for SetLogLevel for application/json ContentType.
 type SetLogLevelJSONRequestBody SetLogLevelJSONBody
+
+// Getter for additional properties for ApplicationTraffic_Drops. Returns the specified
+// element and whether it was found
+func (a ApplicationTraffic_Drops) Get(fieldName string) (value int64, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for ApplicationTraffic_Drops
+func (a *ApplicationTraffic_Drops) Set(fieldName string, value int64) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]int64)
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for ApplicationTraffic_Drops to handle AdditionalProperties
+func (a *ApplicationTraffic_Drops) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]int64)
+		for fieldName, fieldBuf := range object {
+			var fieldVal int64
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for ApplicationTraffic_Drops to handle AdditionalProperties
+func (a ApplicationTraffic_Drops) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
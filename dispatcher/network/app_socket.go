@@ -15,6 +15,7 @@
 package network
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -87,6 +88,12 @@ func (h *AppConnHandler) Handle(appServer *dispatcher.Server) {
 		defer log.HandlePanic()
 		h.RunRingToAppDataplane()
 	}()
+	if h.DispConn.SubscribesSCMPErrors() {
+		go func() {
+			defer log.HandlePanic()
+			h.RunSCMPErrorsToAppDataplane()
+		}()
+	}
 
 	h.RunAppToNetDataplane()
 }
@@ -103,8 +110,8 @@ func (h *AppConnHandler) doRegExchange(appServer *dispatcher.Server) (net.Packet
 	if err != nil {
 		return nil, serrors.WrapStr("receiving registration message", err)
 	}
-	appConn, _, err := appServer.Register(nil,
-		regInfo.IA, regInfo.PublicAddress, regInfo.SVCAddress)
+	appConn, _, err := appServer.RegisterSCMPErrors(nil,
+		regInfo.IA, regInfo.PublicAddress, regInfo.SVCAddress, regInfo.SCMPErrors)
 	if err != nil {
 		return nil, serrors.WrapStr("add registration", err, "registration", regInfo)
 	}
@@ -182,7 +189,9 @@ func (h *AppConnHandler) RunAppToNetDataplane() {
 		metrics.M.AppReadPkts().Inc()
 
 		n, err := h.DispConn.Write(pkt)
-		if err != nil {
+		if errors.Is(err, dispatcher.ErrRateLimited) {
+			h.Logger.Debug("[app->network] Dropped packet", "err", err)
+		} else if err != nil {
 			metrics.M.NetWriteErrors().Inc()
 			h.Logger.Error("[app->network] Underlay socket error", "err", err)
 		} else {
@@ -215,6 +224,32 @@ func (h *AppConnHandler) RunRingToAppDataplane() {
 	}
 }
 
+// RunSCMPErrorsToAppDataplane moves SCMP error notifications from the
+// application's notification ring to the application's socket.
+func (h *AppConnHandler) RunSCMPErrorsToAppDataplane() {
+	conn, ok := h.Conn.(scmpErrorWriter)
+	if !ok {
+		return
+	}
+	for {
+		n := h.DispConn.ReadSCMPError()
+		if n == nil {
+			// Ring was closed because app shut down its data socket
+			return
+		}
+		if err := conn.WriteSCMPErrorNotification(n); err != nil {
+			metrics.M.AppWriteErrors().Inc()
+			h.Logger.Error("[network->app] App connection error.", "err", err)
+			h.Conn.Close()
+			return
+		}
+	}
+}
+
+type scmpErrorWriter interface {
+	WriteSCMPErrorNotification(n *reliable.SCMPErrorNotification) error
+}
+
 func getBindIP(address *net.UDPAddr) net.IP {
 	if address == nil {
 		return nil
@@ -16,6 +16,7 @@ package network
 
 import (
 	"os"
+	"sync"
 
 	"github.com/scionproto/scion/dispatcher"
 	"github.com/scionproto/scion/pkg/log"
@@ -27,6 +28,12 @@ type Dispatcher struct {
 	UnderlaySocket    string
 	ApplicationSocket string
 	SocketFileMode    os.FileMode
+	// AppRateLimit is the per-application rate limit in bytes per second. If
+	// 0, the rate is not limited.
+	AppRateLimit int
+
+	mtx    sync.Mutex
+	server *dispatcher.Server
 }
 
 func (d *Dispatcher) ListenAndServe() error {
@@ -35,6 +42,10 @@ func (d *Dispatcher) ListenAndServe() error {
 		return err
 	}
 	defer dispServer.Close()
+	dispServer.AppRateLimit = d.AppRateLimit
+	d.mtx.Lock()
+	d.server = dispServer
+	d.mtx.Unlock()
 
 	dispServerConn, err := reliable.Listen(d.ApplicationSocket)
 	if err != nil {
@@ -62,3 +73,14 @@ func (d *Dispatcher) ListenAndServe() error {
 
 	return <-errChan
 }
+
+// Applications returns information about the traffic of all registered
+// applications. It returns nil if the dispatcher is not serving.
+func (d *Dispatcher) Applications() []dispatcher.AppInfo {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if d.server == nil {
+		return nil
+	}
+	return d.server.Applications()
+}
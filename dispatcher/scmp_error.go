@@ -0,0 +1,144 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"net"
+
+	"github.com/google/gopacket"
+
+	"github.com/scionproto/scion/dispatcher/internal/respool"
+	"github.com/scionproto/scion/pkg/slayers"
+	"github.com/scionproto/scion/pkg/sock/reliable"
+	"github.com/scionproto/scion/private/ringbuf"
+)
+
+// notifySCMPError sends a notification for the SCMP error in pkt to the
+// applications that subscribed to the SCMP error type. If dst identifies the
+// application that sent the offending packet, only that application is
+// notified. Otherwise, e.g., if the quoted packet is truncated, all
+// subscribers on the destination host of pkt are notified.
+//
+// The packet is not modified, and ownership is not taken.
+func (dp *NetToRingDataplane) notifySCMPError(pkt *respool.Packet, dst Destination) {
+	host, err := pkt.SCION.DstAddr()
+	if err != nil {
+		return
+	}
+	ipAddr, ok := host.(*net.IPAddr)
+	if !ok {
+		return
+	}
+	subscribers := dp.RoutingTable.LookupSCMPErrors(pkt.SCION.DstIA, ipAddr.IP,
+		pkt.SCMP.TypeCode.Type())
+	if len(subscribers) == 0 {
+		return
+	}
+	var owner *TableEntry
+	switch d := dst.(type) {
+	case UDPDestination:
+		owner, _ = dp.RoutingTable.LookupPublic(d.IA, d.Public)
+	case SCMPDestination:
+		owner, _ = dp.RoutingTable.LookupID(d.IA, uint64(d.ID))
+	}
+	n := newSCMPErrorNotification(pkt)
+	if owner == nil {
+		for _, entry := range subscribers {
+			sendSCMPError(entry, n)
+		}
+		return
+	}
+	for _, entry := range subscribers {
+		if entry == owner {
+			sendSCMPError(entry, n)
+			return
+		}
+	}
+}
+
+// newSCMPErrorNotification parses the SCMP error in pkt. Parsing is best
+// effort; fields that cannot be extracted are left empty.
+func newSCMPErrorNotification(pkt *respool.Packet) *reliable.SCMPErrorNotification {
+	n := &reliable.SCMPErrorNotification{
+		TypeCode:  pkt.SCMP.TypeCode,
+		Source:    pkt.SCION.SrcIA,
+		Truncated: true,
+	}
+	if pkt.SCMP.NextLayerType() == gopacket.LayerTypePayload {
+		return n
+	}
+	l, err := decodeSCMP(&pkt.SCMP)
+	if err != nil {
+		return n
+	}
+	switch info := l[0].(type) {
+	case *slayers.SCMPPacketTooBig:
+		n.MTU = info.MTU
+	case *slayers.SCMPParameterProblem:
+		n.Pointer = info.Pointer
+	case *slayers.SCMPExternalInterfaceDown:
+		n.IA = info.IA
+		n.Egress = info.IfID
+	case *slayers.SCMPInternalConnectivityDown:
+		n.IA = info.IA
+		n.Ingress = info.Ingress
+		n.Egress = info.Egress
+	}
+	if len(l) != 2 {
+		return n
+	}
+	quote := *l[1].(*gopacket.Payload)
+	n.Truncated = quoteTruncated(quote)
+	gpkt := gopacket.NewPacket(quote, slayers.LayerTypeSCION,
+		gopacket.DecodeOptions{NoCopy: true},
+	)
+	scionL := gpkt.Layer(slayers.LayerTypeSCION)
+	if scionL == nil {
+		return n
+	}
+	quoted := scionL.(*slayers.SCION)
+	n.DestinationIA = quoted.DstIA
+	n.Destination = &net.UDPAddr{}
+	if dst, err := quoted.DstAddr(); err == nil {
+		if ipAddr, ok := dst.(*net.IPAddr); ok {
+			n.Destination.IP = append(net.IP(nil), ipAddr.IP...)
+		}
+	}
+	if udp := gpkt.Layer(slayers.LayerTypeSCIONUDP); udp != nil {
+		n.Destination.Port = int(udp.(*slayers.UDP).DstPort)
+	}
+	return n
+}
+
+// quoteTruncated returns whether the quoted packet is too short to contain the
+// SCION header and, for UDP and SCMP, the first 8 bytes of the L4 header,
+// which identify the sender.
+func quoteTruncated(quote []byte) bool {
+	if len(quote) < slayers.CmnHdrLen {
+		return true
+	}
+	hdrLen := int(quote[5]) * slayers.LineLen
+	switch slayers.L4ProtocolType(quote[4]) {
+	case slayers.L4UDP, slayers.L4SCMP:
+		return len(quote) < hdrLen+8
+	default:
+		return len(quote) < hdrLen
+	}
+}
+
+func sendSCMPError(routingEntry *TableEntry, n *reliable.SCMPErrorNotification) {
+	// Notifications are dropped if the application does not keep up.
+	routingEntry.scmpErrorRing.Write(ringbuf.EntryList{n}, false)
+}
@@ -0,0 +1,171 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/dispatcher/internal/respool"
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/pkg/slayers"
+	"github.com/scionproto/scion/pkg/sock/reliable"
+	"github.com/scionproto/scion/private/ringbuf"
+)
+
+func TestNotifySCMPError(t *testing.T) {
+	localIA := xtest.MustParseIA("1-ff00:0:110")
+	host := net.IP{192, 168, 0, 1}
+	ptb := reliable.NewSCMPErrorTypes(slayers.SCMPTypePacketTooBig)
+
+	// newPkt constructs an SCMP Packet Too Big error quoting a UDP/SCION
+	// packet sent from port 1337. If truncate is set, the quote ends within
+	// the UDP header.
+	newPkt := func(t *testing.T, truncate bool) *respool.Packet {
+		scionL := newSCIONHdr(t, slayers.L4UDP)
+		udp := &slayers.UDP{SrcPort: 1337, DstPort: 42}
+		udp.SetNetworkLayerForChecksum(scionL)
+		buf := gopacket.NewSerializeBuffer()
+		err := gopacket.SerializeLayers(buf,
+			gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true},
+			scionL, udp, gopacket.Payload(bytes.Repeat([]byte{0xff}, 20)),
+		)
+		require.NoError(t, err)
+		quote := buf.Bytes()
+		if truncate {
+			quote = quote[:len(quote)-24]
+		}
+		scmpPld := gopacket.NewSerializeBuffer()
+		err = gopacket.SerializeLayers(scmpPld, gopacket.SerializeOptions{},
+			&slayers.SCMPPacketTooBig{MTU: 1280},
+			gopacket.Payload(quote),
+		)
+		require.NoError(t, err)
+		pkt := &respool.Packet{
+			SCION: slayers.SCION{
+				SrcIA: xtest.MustParseIA("1-ff00:0:111"),
+				DstIA: localIA,
+			},
+			SCMP: slayers.SCMP{
+				TypeCode: slayers.CreateSCMPTypeCode(slayers.SCMPTypePacketTooBig, 0),
+				BaseLayer: slayers.BaseLayer{
+					Payload: scmpPld.Bytes(),
+				},
+			},
+			L4: slayers.LayerTypeSCMP,
+		}
+		require.NoError(t, pkt.SCION.SetDstAddr(&net.IPAddr{IP: host}))
+		return pkt
+	}
+	register := func(t *testing.T, table *IATable, port int,
+		types reliable.SCMPErrorTypes) *TableEntry {
+
+		entry := newTableEntry(true)
+		ref, err := table.Register(localIA, &net.UDPAddr{IP: host, Port: port}, nil,
+			addr.SvcNone, entry)
+		require.NoError(t, err)
+		require.NoError(t, ref.RegisterSCMPErrors(types.Types()))
+		return entry
+	}
+	readNotification := func(entry *TableEntry) *reliable.SCMPErrorNotification {
+		entries := make(ringbuf.EntryList, 1)
+		n, _ := entry.scmpErrorRing.Read(entries, false)
+		if n <= 0 {
+			return nil
+		}
+		return entries[0].(*reliable.SCMPErrorNotification)
+	}
+
+	t.Run("only the owner is notified", func(t *testing.T) {
+		table := NewIATable(1024, 65535)
+		owner := register(t, table, 1337, ptb)
+		other := register(t, table, 1338, ptb)
+		dp := &NetToRingDataplane{RoutingTable: table}
+
+		pkt := newPkt(t, false)
+		dst, err := getDst(pkt)
+		require.NoError(t, err)
+		dp.notifySCMPError(pkt, dst)
+
+		n := readNotification(owner)
+		require.NotNil(t, n)
+		assert.Equal(t, &reliable.SCMPErrorNotification{
+			TypeCode:      slayers.CreateSCMPTypeCode(slayers.SCMPTypePacketTooBig, 0),
+			Source:        xtest.MustParseIA("1-ff00:0:111"),
+			DestinationIA: xtest.MustParseIA("1-ff00:0:112"),
+			Destination:   &net.UDPAddr{IP: net.IP{192, 168, 0, 2}.To4(), Port: 42},
+			MTU:           1280,
+		}, n)
+		assert.Nil(t, readNotification(other))
+	})
+	t.Run("owner without subscription is not notified", func(t *testing.T) {
+		table := NewIATable(1024, 65535)
+		owner := register(t, table, 1337,
+			reliable.NewSCMPErrorTypes(slayers.SCMPTypeExternalInterfaceDown))
+		other := register(t, table, 1338, ptb)
+		dp := &NetToRingDataplane{RoutingTable: table}
+
+		pkt := newPkt(t, false)
+		dst, err := getDst(pkt)
+		require.NoError(t, err)
+		dp.notifySCMPError(pkt, dst)
+
+		assert.Nil(t, readNotification(owner))
+		assert.Nil(t, readNotification(other))
+	})
+	t.Run("truncated quote notifies all subscribers on host", func(t *testing.T) {
+		table := NewIATable(1024, 65535)
+		a := register(t, table, 1337, ptb)
+		b := register(t, table, 1338, ptb)
+		unsubscribed := register(t, table, 1339, 0)
+		dp := &NetToRingDataplane{RoutingTable: table}
+
+		pkt := newPkt(t, true)
+		dst, err := getDst(pkt)
+		require.Error(t, err)
+		dp.notifySCMPError(pkt, dst)
+
+		for _, entry := range []*TableEntry{a, b} {
+			n := readNotification(entry)
+			require.NotNil(t, n)
+			assert.True(t, n.Truncated)
+			assert.Equal(t, uint16(1280), n.MTU)
+			assert.Equal(t, xtest.MustParseIA("1-ff00:0:112"), n.DestinationIA)
+		}
+		assert.Nil(t, readNotification(unsubscribed))
+	})
+	t.Run("complete quote without owner is not truncated", func(t *testing.T) {
+		table := NewIATable(1024, 65535)
+		other := register(t, table, 1338, ptb)
+		dp := &NetToRingDataplane{RoutingTable: table}
+
+		pkt := newPkt(t, false)
+		dst, err := getDst(pkt)
+		require.NoError(t, err)
+		dp.notifySCMPError(pkt, dst)
+
+		n := readNotification(other)
+		require.NotNil(t, n)
+		assert.False(t, n.Truncated)
+		assert.Equal(t, &net.UDPAddr{IP: net.IP{192, 168, 0, 2}.To4(), Port: 42},
+			n.Destination)
+	})
+}
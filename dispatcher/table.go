@@ -19,19 +19,30 @@ import (
 
 	"github.com/scionproto/scion/dispatcher/internal/registration"
 	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/slayers"
 	"github.com/scionproto/scion/private/ringbuf"
 )
 
 type TableEntry struct {
 	appIngressRing *ringbuf.Ring
+	// scmpErrorRing contains the SCMP error notifications for the application.
+	// It is nil if the application does not subscribe to SCMP errors.
+	scmpErrorRing *ringbuf.Ring
+	// accounting tracks the traffic of the application. It is set before the
+	// entry is registered.
+	accounting *appAccounting
 }
 
-func newTableEntry() *TableEntry {
+func newTableEntry(scmpErrors bool) *TableEntry {
 	// Construct application ingress ring buffer
 	appIngressRing := ringbuf.New(128, nil, "net_to_app_ring")
-	return &TableEntry{
+	entry := &TableEntry{
 		appIngressRing: appIngressRing,
 	}
+	if scmpErrors {
+		entry.scmpErrorRing = ringbuf.New(16, nil, "scmp_error_ring")
+	}
+	return entry
 }
 
 // IATable is a type-safe convenience wrapper around a generic routing table.
@@ -69,3 +80,14 @@ func (t *IATable) LookupID(ia addr.IA, id uint64) (*TableEntry, bool) {
 	}
 	return e.(*TableEntry), true
 }
+
+func (t *IATable) LookupSCMPErrors(ia addr.IA, host net.IP,
+	typ slayers.SCMPType) []*TableEntry {
+
+	ifaces := t.IATable.LookupSCMPErrors(ia, host, typ)
+	entries := make([]*TableEntry, len(ifaces))
+	for i := range ifaces {
+		entries[i] = ifaces[i].(*TableEntry)
+	}
+	return entries
+}
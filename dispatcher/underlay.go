@@ -62,6 +62,9 @@ func (dp *NetToRingDataplane) Run() error {
 			continue
 		}
 		dst, err := getDst(pkt)
+		if pkt.L4 == slayers.LayerTypeSCMP && !pkt.SCMP.TypeCode.InfoMsg() {
+			dp.notifySCMPError(pkt, dst)
+		}
 		if err != nil {
 			log.Debug("unable to route packet", "err", err)
 			metrics.M.NetReadPkts(
@@ -401,10 +404,22 @@ type Destination interface {
 // sendPacket puts pkt on the routing entry's ring buffer, and releases the
 // reference to pkt.
 func sendPacket(routingEntry *TableEntry, pkt *respool.Packet) {
+	accounting, n := routingEntry.accounting, pkt.Len()
+	if accounting != nil && !accounting.allowIn(n) {
+		pkt.Free()
+		return
+	}
 	// Move packet reference to other goroutine.
 	count, _ := routingEntry.appIngressRing.Write(ringbuf.EntryList{pkt}, false)
 	if count <= 0 {
+		if accounting != nil {
+			accounting.drop(DropRingFull)
+		}
 		// Release buffer if we couldn't transmit it to the other goroutine.
 		pkt.Free()
+		return
+	}
+	if accounting != nil {
+		accounting.countIn(n)
 	}
 }
@@ -0,0 +1,203 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package attestation implements signed prefix attestations. A prefix
+// attestation authorizes an ISD-AS to announce IP prefixes to remote gateways.
+// It is encapsulated in a CMS SignedData structure that is signed with the AS
+// certificate of the attested ISD-AS and includes its certificate chain. Thus,
+// its signature can be verified with the TRC of the ISD alone. Since an ISD-AS
+// can attest any prefix, the attestations are only trusted for the prefixes
+// that the ISD-AS holds according to the locally configured prefix holders.
+package attestation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"time"
+
+	"inet.af/netaddr"
+
+	"github.com/scionproto/scion/gateway/control"
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/scrypto/cms/protocol"
+	"github.com/scionproto/scion/pkg/scrypto/cppki"
+	"github.com/scionproto/scion/private/ca/renewal"
+	"github.com/scionproto/scion/private/trust"
+)
+
+// PEMType is the type of the PEM blocks that contain prefix attestations.
+const PEMType = "SCION PREFIX ATTESTATION"
+
+type body struct {
+	IA       addr.IA      `json:"isd_as"`
+	Prefixes []bodyPrefix `json:"prefixes"`
+	NotAfter time.Time    `json:"not_after"`
+}
+
+type bodyPrefix struct {
+	Prefix    netaddr.IPPrefix `json:"prefix"`
+	MaxLength uint8            `json:"max_length,omitempty"`
+}
+
+// Encode encodes the attestation as the payload of the signed attestation.
+func Encode(a control.PrefixAttestation) ([]byte, error) {
+	b := body{
+		IA:       a.IA,
+		Prefixes: make([]bodyPrefix, 0, len(a.Prefixes)),
+		NotAfter: a.NotAfter.UTC(),
+	}
+	for _, p := range a.Prefixes {
+		b.Prefixes = append(b.Prefixes, bodyPrefix{Prefix: p.Prefix, MaxLength: p.MaxLength})
+	}
+	return json.Marshal(b)
+}
+
+// Decode decodes the payload of a signed attestation.
+func Decode(raw []byte) (control.PrefixAttestation, error) {
+	var b body
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&b); err != nil {
+		return control.PrefixAttestation{}, serrors.WrapStr("decoding attestation", err)
+	}
+	a := control.PrefixAttestation{
+		IA:       b.IA,
+		Prefixes: make([]control.AttestedPrefix, 0, len(b.Prefixes)),
+		NotAfter: b.NotAfter,
+	}
+	for _, p := range b.Prefixes {
+		a.Prefixes = append(a.Prefixes, control.AttestedPrefix{
+			Prefix:    p.Prefix,
+			MaxLength: p.MaxLength,
+		})
+	}
+	if err := a.Validate(); err != nil {
+		return control.PrefixAttestation{}, serrors.WrapStr("validating attestation", err)
+	}
+	return a, nil
+}
+
+// Sign signs the attestation with the signer. The signer must hold the AS
+// certificate chain of the attested ISD-AS, and the attestation must not
+// outlive the AS certificate.
+func Sign(ctx context.Context, a control.PrefixAttestation,
+	signer trust.Signer) ([]byte, error) {
+
+	if err := a.Validate(); err != nil {
+		return nil, serrors.WrapStr("validating attestation", err)
+	}
+	if len(signer.Chain) == 0 {
+		return nil, serrors.New("signer without certificate chain")
+	}
+	if !signer.IA.Equal(a.IA) {
+		return nil, serrors.New("signer does not match attested ISD-AS",
+			"signer", signer.IA, "isd_as", a.IA)
+	}
+	if notAfter := signer.Chain[0].NotAfter; a.NotAfter.After(notAfter) {
+		return nil, serrors.New("attestation outlives the AS certificate",
+			"not_after", a.NotAfter, "certificate_not_after", notAfter)
+	}
+	raw, err := Encode(a)
+	if err != nil {
+		return nil, serrors.WrapStr("encoding attestation", err)
+	}
+	return signer.SignCMS(ctx, raw)
+}
+
+// Verifier verifies signed prefix attestations. It implements the
+// control.PrefixAttestationVerifier interface.
+type Verifier struct {
+	// TRCs provides the TRCs that are used to verify the certificate chains.
+	TRCs renewal.TRCFetcher
+}
+
+// Verify verifies the signed attestation. The certificate chain included in
+// the attestation must be verifiable with the active TRC of its ISD, and it
+// must belong to the attested ISD-AS. The expiration time of the returned
+// attestation is capped at the expiration time of the AS certificate. The
+// signature only proves that the attested ISD-AS issued the attestation, not
+// that it holds the prefixes; the caller must restrict the attestation to the
+// prefixes held by the ISD-AS.
+func (v Verifier) Verify(ctx context.Context, raw []byte) (control.PrefixAttestation, error) {
+	ci, err := protocol.ParseContentInfo(raw)
+	if err != nil {
+		return control.PrefixAttestation{}, serrors.WrapStr("parsing ContentInfo", err)
+	}
+	sd, err := ci.SignedDataContent()
+	if err != nil {
+		return control.PrefixAttestation{}, serrors.WrapStr("parsing SignedData", err)
+	}
+	chain, err := renewal.ExtractChain(sd)
+	if err != nil {
+		return control.PrefixAttestation{}, serrors.WrapStr("extracting certificate chain", err)
+	}
+	verifier := renewal.RequestVerifier{TRCFetcher: v.TRCs}
+	if err := verifier.VerifySignature(ctx, sd, chain); err != nil {
+		return control.PrefixAttestation{}, serrors.WrapStr("verifying signature", err)
+	}
+	pld, err := sd.EncapContentInfo.EContentValue()
+	if err != nil {
+		return control.PrefixAttestation{}, serrors.WrapStr("reading payload", err)
+	}
+	a, err := Decode(pld)
+	if err != nil {
+		return control.PrefixAttestation{}, err
+	}
+	ia, err := cppki.ExtractIA(chain[0].Subject)
+	if err != nil {
+		return control.PrefixAttestation{}, serrors.WrapStr("extracting ISD-AS", err)
+	}
+	if !ia.Equal(a.IA) {
+		return control.PrefixAttestation{}, serrors.New(
+			"attestation not signed by attested ISD-AS", "signer", ia, "isd_as", a.IA)
+	}
+	if notAfter := chain[0].NotAfter; notAfter.Before(a.NotAfter) {
+		a.NotAfter = notAfter
+	}
+	if now := time.Now(); now.After(a.NotAfter) {
+		return control.PrefixAttestation{}, serrors.New("attestation expired",
+			"isd_as", a.IA, "not_after", a.NotAfter)
+	}
+	return a, nil
+}
+
+// EncodePEM encodes the signed attestations as PEM blocks.
+func EncodePEM(attestations [][]byte) []byte {
+	var buf bytes.Buffer
+	for _, raw := range attestations {
+		// Writing to a bytes.Buffer does not fail.
+		_ = pem.Encode(&buf, &pem.Block{Type: PEMType, Bytes: raw})
+	}
+	return buf.Bytes()
+}
+
+// DecodePEM decodes the signed attestations from PEM blocks. Blocks of other
+// types are ignored.
+func DecodePEM(raw []byte) ([][]byte, error) {
+	var attestations [][]byte
+	for len(bytes.TrimSpace(raw)) > 0 {
+		var block *pem.Block
+		block, raw = pem.Decode(raw)
+		if block == nil {
+			return nil, serrors.New("invalid PEM")
+		}
+		if block.Type == PEMType {
+			attestations = append(attestations, block.Bytes)
+		}
+	}
+	return attestations, nil
+}
@@ -0,0 +1,261 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"inet.af/netaddr"
+
+	"github.com/scionproto/scion/gateway/attestation"
+	"github.com/scionproto/scion/gateway/control"
+	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/private/app/command"
+	"github.com/scionproto/scion/private/trust"
+	"github.com/scionproto/scion/scion-pki/testcrypto"
+)
+
+func TestSignVerify(t *testing.T) {
+	dir := genCrypto(t)
+	store := &attestation.TRCStore{}
+	_, err := trust.LoadTRCs(context.Background(), filepath.Join(dir, "trcs"), store)
+	require.NoError(t, err)
+	verifier := attestation.Verifier{TRCs: store}
+
+	signer110 := loadSigner(t, dir, "110")
+	signer112 := loadSigner(t, dir, "112")
+
+	attestation110 := control.PrefixAttestation{
+		IA: xtest.MustParseIA("1-ff00:0:110"),
+		Prefixes: []control.AttestedPrefix{
+			{Prefix: netaddr.MustParseIPPrefix("10.1.0.0/16"), MaxLength: 24},
+			{Prefix: netaddr.MustParseIPPrefix("2001:db8::/32")},
+		},
+		NotAfter: time.Now().Add(time.Hour).Truncate(time.Second),
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		raw, err := attestation.Sign(context.Background(), attestation110, signer110)
+		require.NoError(t, err)
+		a, err := verifier.Verify(context.Background(), raw)
+		require.NoError(t, err)
+		assert.Equal(t, attestation110.IA, a.IA)
+		assert.Equal(t, attestation110.Prefixes, a.Prefixes)
+		assert.True(t, attestation110.NotAfter.Equal(a.NotAfter))
+	})
+	t.Run("capped at certificate expiry", func(t *testing.T) {
+		// Bypass the check in Sign to verify that the verifier caps the
+		// expiration time.
+		a := attestation110
+		a.NotAfter = signer110.Chain[0].NotAfter.Add(time.Hour)
+		pld, err := attestation.Encode(a)
+		require.NoError(t, err)
+		raw, err := signer110.SignCMS(context.Background(), pld)
+		require.NoError(t, err)
+		verified, err := verifier.Verify(context.Background(), raw)
+		require.NoError(t, err)
+		assert.Equal(t, signer110.Chain[0].NotAfter, verified.NotAfter)
+	})
+	t.Run("outlives certificate", func(t *testing.T) {
+		a := attestation110
+		a.NotAfter = signer110.Chain[0].NotAfter.Add(time.Hour)
+		_, err := attestation.Sign(context.Background(), a, signer110)
+		assert.Error(t, err)
+	})
+	t.Run("wrong signer", func(t *testing.T) {
+		_, err := attestation.Sign(context.Background(), attestation110, signer112)
+		assert.Error(t, err)
+
+		// Bypass the check in Sign to verify that the verifier rejects
+		// attestations of other ISD-ASes.
+		pld, err := attestation.Encode(attestation110)
+		require.NoError(t, err)
+		raw, err := signer112.SignCMS(context.Background(), pld)
+		require.NoError(t, err)
+		_, err = verifier.Verify(context.Background(), raw)
+		assert.Error(t, err)
+	})
+	t.Run("expired", func(t *testing.T) {
+		a := attestation110
+		a.NotAfter = time.Now().Add(-time.Minute)
+		raw, err := attestation.Sign(context.Background(), a, signer110)
+		require.NoError(t, err)
+		_, err = verifier.Verify(context.Background(), raw)
+		assert.Error(t, err)
+	})
+	t.Run("unknown TRC", func(t *testing.T) {
+		raw, err := attestation.Sign(context.Background(), attestation110, signer110)
+		require.NoError(t, err)
+		_, err = attestation.Verifier{TRCs: &attestation.TRCStore{}}.Verify(
+			context.Background(), raw)
+		assert.Error(t, err)
+	})
+	t.Run("tampered", func(t *testing.T) {
+		raw, err := attestation.Sign(context.Background(), attestation110, signer110)
+		require.NoError(t, err)
+		tampered := bytes.Replace(raw, []byte("10.1.0.0/16"), []byte("10.2.0.0/16"), 1)
+		require.NotEqual(t, raw, tampered)
+		_, err = verifier.Verify(context.Background(), tampered)
+		assert.Error(t, err)
+	})
+}
+
+func TestDecode(t *testing.T) {
+	testCases := map[string]struct {
+		Input        string
+		ErrAssertion assert.ErrorAssertionFunc
+	}{
+		"valid": {
+			Input: `{"isd_as":"1-ff00:0:110","prefixes":[{"prefix":"10.0.0.0/8",` +
+				`"max_length":16}],"not_after":"2030-01-01T00:00:00Z"}`,
+			ErrAssertion: assert.NoError,
+		},
+		"no prefixes": {
+			Input:        `{"isd_as":"1-ff00:0:110","not_after":"2030-01-01T00:00:00Z"}`,
+			ErrAssertion: assert.Error,
+		},
+		"wildcard": {
+			Input: `{"isd_as":"1-0","prefixes":[{"prefix":"10.0.0.0/8"}],` +
+				`"not_after":"2030-01-01T00:00:00Z"}`,
+			ErrAssertion: assert.Error,
+		},
+		"not masked": {
+			Input: `{"isd_as":"1-ff00:0:110","prefixes":[{"prefix":"10.1.0.0/8"}],` +
+				`"not_after":"2030-01-01T00:00:00Z"}`,
+			ErrAssertion: assert.Error,
+		},
+		"max length too short": {
+			Input: `{"isd_as":"1-ff00:0:110","prefixes":[{"prefix":"10.0.0.0/16",` +
+				`"max_length":8}],"not_after":"2030-01-01T00:00:00Z"}`,
+			ErrAssertion: assert.Error,
+		},
+		"max length too long": {
+			Input: `{"isd_as":"1-ff00:0:110","prefixes":[{"prefix":"10.0.0.0/16",` +
+				`"max_length":33}],"not_after":"2030-01-01T00:00:00Z"}`,
+			ErrAssertion: assert.Error,
+		},
+		"no expiration": {
+			Input:        `{"isd_as":"1-ff00:0:110","prefixes":[{"prefix":"10.0.0.0/8"}]}`,
+			ErrAssertion: assert.Error,
+		},
+		"unknown field": {
+			Input: `{"isd_as":"1-ff00:0:110","prefixes":[{"prefix":"10.0.0.0/8"}],` +
+				`"not_after":"2030-01-01T00:00:00Z","foo":1}`,
+			ErrAssertion: assert.Error,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := attestation.Decode([]byte(tc.Input))
+			tc.ErrAssertion(t, err)
+		})
+	}
+}
+
+func TestPEM(t *testing.T) {
+	attestations := [][]byte{{1, 2, 3}, {4, 5, 6}}
+	raw := attestation.EncodePEM(attestations)
+	decoded, err := attestation.DecodePEM(raw)
+	require.NoError(t, err)
+	assert.Equal(t, attestations, decoded)
+
+	_, err = attestation.DecodePEM([]byte("garbage"))
+	assert.Error(t, err)
+}
+
+func TestDecodeHolders(t *testing.T) {
+	holders, err := attestation.DecodeHolders([]byte(
+		`{"1-ff00:0:110": ["10.1.0.0/16", "2001:db8::/32"], "1-ff00:0:111": []}`))
+	require.NoError(t, err)
+	assert.Equal(t, control.PrefixHolders{
+		xtest.MustParseIA("1-ff00:0:110"): {
+			netaddr.MustParseIPPrefix("10.1.0.0/16"),
+			netaddr.MustParseIPPrefix("2001:db8::/32"),
+		},
+		xtest.MustParseIA("1-ff00:0:111"): {},
+	}, holders)
+
+	for name, input := range map[string]string{
+		"invalid ISD-AS": `{"1-ff00": ["10.1.0.0/16"]}`,
+		"wildcard":       `{"1-0": ["10.1.0.0/16"]}`,
+		"invalid prefix": `{"1-ff00:0:110": ["10.1.0.0"]}`,
+		"not masked":     `{"1-ff00:0:110": ["10.1.0.0/8"]}`,
+	} {
+		_, err := attestation.DecodeHolders([]byte(input))
+		assert.Error(t, err, name)
+	}
+
+	_, err = attestation.LoadHolders(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attestations.pem")
+	f := &attestation.File{Path: path}
+	assert.Error(t, f.Load())
+
+	require.NoError(t, os.WriteFile(path, attestation.EncodePEM([][]byte{{1}}), 0644))
+	require.NoError(t, f.Load())
+	assert.Equal(t, [][]byte{{1}}, f.PrefixAttestations())
+
+	// Modified files are read again.
+	require.NoError(t, os.WriteFile(path, attestation.EncodePEM([][]byte{{2}}), 0644))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+	assert.Equal(t, [][]byte{{2}}, f.PrefixAttestations())
+
+	// Invalid files are ignored.
+	require.NoError(t, os.WriteFile(path, []byte("garbage"), 0644))
+	later = later.Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+	assert.Equal(t, [][]byte{{2}}, f.PrefixAttestations())
+}
+
+func genCrypto(t *testing.T) string {
+	dir := t.TempDir()
+
+	var buf bytes.Buffer
+	cmd := testcrypto.Cmd(command.StringPather(""))
+	cmd.SetArgs([]string{
+		"-t", "testdata/golden.topo",
+		"-o", dir,
+	})
+	cmd.SetOutput(&buf)
+	require.NoError(t, cmd.Execute(), buf.String())
+	return dir
+}
+
+func loadSigner(t *testing.T, dir, as string) trust.Signer {
+	t.Helper()
+	chain := xtest.LoadChain(t,
+		filepath.Join(dir, "certs", "ISD1-ASff00_0_"+as+".pem"))
+	return trust.Signer{
+		PrivateKey: xtest.LoadSigner(t,
+			filepath.Join(dir, "ASff00_0_"+as, "crypto/as/cp-as.key")),
+		IA:           xtest.MustParseIA("1-ff00:0:" + as),
+		Chain:        chain,
+		SubjectKeyID: chain[0].SubjectKeyId,
+		Expiration:   chain[0].NotAfter,
+	}
+}
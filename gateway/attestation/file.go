@@ -0,0 +1,83 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/pkg/log"
+	"github.com/scionproto/scion/pkg/private/serrors"
+)
+
+// File provides the signed attestations stored in a PEM file. The file is read
+// again whenever it is modified, such that the attestations can be renewed
+// without restarting the gateway.
+type File struct {
+	// Path is the path of the file.
+	Path string
+
+	mtx          sync.Mutex
+	modTime      time.Time
+	attestations [][]byte
+}
+
+// Load reads the file. It returns an error if the file cannot be read or
+// contains no attestation.
+func (f *File) Load() error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.load()
+}
+
+// PrefixAttestations returns the attestations. If the file was modified, it is
+// read again. If this fails, the previously read attestations are returned.
+func (f *File) PrefixAttestations() [][]byte {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	info, err := os.Stat(f.Path)
+	if err != nil || info.ModTime().Equal(f.modTime) {
+		return f.attestations
+	}
+	if err := f.load(); err != nil {
+		log.Info("Failed to reload prefix attestations", "file", f.Path, "err", err)
+		return f.attestations
+	}
+	log.Info("Reloaded prefix attestations", "file", f.Path, "count", len(f.attestations))
+	return f.attestations
+}
+
+func (f *File) load() error {
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return serrors.WrapStr("reading prefix attestations", err, "file", f.Path)
+	}
+	raw, err := os.ReadFile(f.Path)
+	if err != nil {
+		return serrors.WrapStr("reading prefix attestations", err, "file", f.Path)
+	}
+	attestations, err := DecodePEM(raw)
+	if err != nil {
+		return serrors.WrapStr("decoding prefix attestations", err, "file", f.Path)
+	}
+	if len(attestations) == 0 {
+		return serrors.New("no prefix attestations found", "file", f.Path)
+	}
+	f.attestations = attestations
+	f.modTime = info.ModTime()
+	return nil
+}
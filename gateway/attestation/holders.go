@@ -0,0 +1,64 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+
+	"inet.af/netaddr"
+
+	"github.com/scionproto/scion/gateway/control"
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/private/serrors"
+)
+
+// LoadHolders reads the prefix holders from a JSON file that maps each ISD-AS
+// to the IP prefixes it holds, e.g.,
+//
+//	{"1-ff00:0:110": ["10.1.0.0/16", "2001:db8::/32"]}
+func LoadHolders(path string) (control.PrefixHolders, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, serrors.WrapStr("reading prefix holders", err, "file", path)
+	}
+	holders, err := DecodeHolders(raw)
+	if err != nil {
+		return nil, serrors.WrapStr("decoding prefix holders", err, "file", path)
+	}
+	return holders, nil
+}
+
+// DecodeHolders decodes the prefix holders from JSON. See LoadHolders for the
+// format.
+func DecodeHolders(raw []byte) (control.PrefixHolders, error) {
+	var holders map[addr.IA][]netaddr.IPPrefix
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if err := dec.Decode(&holders); err != nil {
+		return nil, err
+	}
+	for ia, prefixes := range holders {
+		if ia.IsWildcard() {
+			return nil, serrors.New("ISD-AS must not contain wildcard", "isd_as", ia)
+		}
+		for _, p := range prefixes {
+			if !p.IsValid() || p.Masked() != p {
+				return nil, serrors.New("invalid prefix", "isd_as", ia, "prefix", p)
+			}
+		}
+	}
+	return holders, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation
+
+import (
+	"context"
+	"crypto/x509"
+	"sync"
+
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/scrypto"
+	"github.com/scionproto/scion/pkg/scrypto/cppki"
+	"github.com/scionproto/scion/private/trust"
+)
+
+// TRCStore is an in-memory store of TRCs. It implements the trust.DB
+// interface, such that TRCs can be loaded with trust.LoadTRCs, but it does not
+// store certificate chains; the chains are included in the attestations.
+type TRCStore struct {
+	mtx  sync.RWMutex
+	trcs map[cppki.TRCID]cppki.SignedTRC
+}
+
+// SignedTRC returns the TRC identified by the ID. If the base and serial
+// number are scrypto.LatestVer, the latest TRC of the ISD is returned. If only
+// the serial number is scrypto.LatestVer, the latest TRC with the base number
+// is returned. If no TRC matches, an empty TRC is returned.
+func (s *TRCStore) SignedTRC(_ context.Context, id cppki.TRCID) (cppki.SignedTRC, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if id.Serial != scrypto.LatestVer {
+		if id.Base == scrypto.LatestVer {
+			return cppki.SignedTRC{}, serrors.New("latest base with fixed serial not supported",
+				"id", id)
+		}
+		return s.trcs[id], nil
+	}
+	var latest cppki.SignedTRC
+	for trcID, trc := range s.trcs {
+		if trcID.ISD != id.ISD || (id.Base != scrypto.LatestVer && trcID.Base != id.Base) {
+			continue
+		}
+		if latest.IsZero() || trcID.Serial > latest.TRC.ID.Serial {
+			latest = trc
+		}
+	}
+	return latest, nil
+}
+
+// InsertTRC inserts the TRC. It returns true if the TRC was not yet in the
+// store.
+func (s *TRCStore) InsertTRC(_ context.Context, trc cppki.SignedTRC) (bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.trcs == nil {
+		s.trcs = make(map[cppki.TRCID]cppki.SignedTRC)
+	}
+	if _, ok := s.trcs[trc.TRC.ID]; ok {
+		return false, nil
+	}
+	s.trcs[trc.TRC.ID] = trc
+	return true, nil
+}
+
+// Chains returns no chains, chains are not stored.
+func (s *TRCStore) Chains(context.Context, trust.ChainQuery) ([][]*x509.Certificate, error) {
+	return nil, nil
+}
+
+// InsertChain does not store the chain and returns false.
+func (s *TRCStore) InsertChain(context.Context, []*x509.Certificate) (bool, error) {
+	return false, nil
+}
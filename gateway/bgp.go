@@ -0,0 +1,68 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"context"
+
+	"inet.af/netaddr"
+
+	"github.com/scionproto/scion/gateway/bgp"
+	"github.com/scionproto/scion/gateway/routemgr"
+	"github.com/scionproto/scion/pkg/log"
+)
+
+// BGP configures the exchange of prefixes with a router in the local network.
+type BGP struct {
+	// Speaker is the BGP speaker used for the session with the router.
+	Speaker *bgp.Speaker
+	// Peer is the address of the router.
+	Peer string
+	// Export enables announcing the prefixes learned from remote gateways to
+	// the router. Only the prefixes accepted by the routing policy are
+	// announced.
+	Export bool
+	// Import enables advertising the prefixes learned from the router to
+	// remote gateways. The advertise rules of the routing policy select which
+	// of the prefixes are advertised.
+	Import bool
+}
+
+// PrefixImporter provides the prefixes learned dynamically from the local
+// network.
+type PrefixImporter interface {
+	Received() []netaddr.IPPrefix
+}
+
+// start runs the BGP session and, if enabled, exports the routes published to
+// Linux to the router.
+func (b *BGP) start(ctx context.Context, linux *routemgr.Linux) {
+	logger := log.FromCtx(ctx)
+	if b.Export {
+		exporter := &routemgr.BGPExporter{Announcer: b.Speaker}
+		consumer := linux.NewConsumer()
+		go func() {
+			defer log.HandlePanic()
+			exporter.Run(ctx, consumer)
+		}()
+	}
+	go func() {
+		defer log.HandlePanic()
+		if err := b.Speaker.Dial(ctx, b.Peer); err != nil {
+			logger.Error("BGP speaker failed", "err", err)
+		}
+	}()
+	logger.Info("BGP speaker started", "peer", b.Peer, "export", b.Export, "import", b.Import)
+}
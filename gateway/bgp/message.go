@@ -0,0 +1,486 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgp
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+
+	"inet.af/netaddr"
+
+	"github.com/scionproto/scion/pkg/private/serrors"
+)
+
+const (
+	headerLen = 19
+	maxMsgLen = 4096
+
+	bgpVersion = 4
+	// asTrans is the 2-octet AS number announced in place of a 4-octet AS
+	// number (RFC 6793).
+	asTrans = 23456
+)
+
+type msgType uint8
+
+const (
+	msgOpen         msgType = 1
+	msgUpdate       msgType = 2
+	msgNotification msgType = 3
+	msgKeepalive    msgType = 4
+)
+
+// Optional parameters and capabilities (RFC 5492).
+const (
+	paramCapabilities = 2
+
+	capMultiprotocol = 1
+	capFourOctetAS   = 65
+)
+
+// Address families (RFC 4760).
+const (
+	afiIPv4     = 1
+	afiIPv6     = 2
+	safiUnicast = 1
+)
+
+// Path attributes.
+const (
+	attrFlagOptional   = 0x80
+	attrFlagTransitive = 0x40
+	attrFlagExtended   = 0x10
+
+	attrOrigin        = 1
+	attrASPath        = 2
+	attrNextHop       = 3
+	attrLocalPref     = 5
+	attrMPReachNLRI   = 14
+	attrMPUnreachNLRI = 15
+
+	originIncomplete = 2
+
+	asSequence = 2
+)
+
+// Notification error codes and subcodes (RFC 4271).
+const (
+	errCodeHeader    = 1
+	errCodeOpen      = 2
+	errCodeUpdate    = 3
+	errCodeHoldTimer = 4
+	errCodeFSM       = 5
+	errCodeCease     = 6
+
+	errSubBadMarker           = 1
+	errSubBadLength           = 2
+	errSubBadPeerAS           = 2
+	errSubBadHoldTime         = 6
+	errSubMalformedAttributes = 1
+	errSubAdminShutdown       = 2
+	errSubCollision           = 7
+)
+
+// openMsg is a BGP OPEN message.
+type openMsg struct {
+	AS       uint32
+	HoldTime uint16
+	ID       [4]byte
+	// FourOctetAS indicates support for 4-octet AS numbers. If set, AS
+	// contains the 4-octet AS number from the capability.
+	FourOctetAS bool
+	// IPv6 indicates support for the IPv6 unicast address family.
+	IPv6 bool
+}
+
+func (m *openMsg) encode() []byte {
+	as := uint16(asTrans)
+	if m.AS <= 0xffff {
+		as = uint16(m.AS)
+	}
+	caps := []byte{
+		capMultiprotocol, 4, 0, afiIPv4, 0, safiUnicast,
+	}
+	if m.IPv6 {
+		caps = append(caps, capMultiprotocol, 4, 0, afiIPv6, 0, safiUnicast)
+	}
+	if m.FourOctetAS {
+		caps = append(caps, capFourOctetAS, 4)
+		caps = binary.BigEndian.AppendUint32(caps, m.AS)
+	}
+	b := []byte{bgpVersion}
+	b = binary.BigEndian.AppendUint16(b, as)
+	b = binary.BigEndian.AppendUint16(b, m.HoldTime)
+	b = append(b, m.ID[:]...)
+	b = append(b, byte(len(caps)+2), paramCapabilities, byte(len(caps)))
+	return append(b, caps...)
+}
+
+func decodeOpen(b []byte) (*openMsg, error) {
+	if len(b) < 10 {
+		return nil, serrors.New("OPEN message too short", "len", len(b))
+	}
+	if b[0] != bgpVersion {
+		return nil, serrors.New("unsupported BGP version", "version", b[0])
+	}
+	m := &openMsg{
+		AS:       uint32(binary.BigEndian.Uint16(b[1:3])),
+		HoldTime: binary.BigEndian.Uint16(b[3:5]),
+	}
+	copy(m.ID[:], b[5:9])
+	params := b[10:]
+	if len(params) != int(b[9]) {
+		return nil, serrors.New("invalid optional parameters length")
+	}
+	for len(params) > 0 {
+		if len(params) < 2 || len(params) < 2+int(params[1]) {
+			return nil, serrors.New("truncated optional parameter")
+		}
+		typ, val := params[0], params[2:2+int(params[1])]
+		params = params[2+int(params[1]):]
+		if typ != paramCapabilities {
+			continue
+		}
+		for len(val) > 0 {
+			if len(val) < 2 || len(val) < 2+int(val[1]) {
+				return nil, serrors.New("truncated capability")
+			}
+			code, cap := val[0], val[2:2+int(val[1])]
+			val = val[2+int(val[1]):]
+			switch {
+			case code == capFourOctetAS && len(cap) == 4:
+				m.FourOctetAS = true
+				m.AS = binary.BigEndian.Uint32(cap)
+			case code == capMultiprotocol && len(cap) == 4:
+				if binary.BigEndian.Uint16(cap) == afiIPv6 && cap[3] == safiUnicast {
+					m.IPv6 = true
+				}
+			}
+		}
+	}
+	return m, nil
+}
+
+// updateMsg is a BGP UPDATE message. IPv4 prefixes are carried in the
+// withdrawn routes and NLRI fields, IPv6 prefixes in the multiprotocol
+// attributes.
+type updateMsg struct {
+	Withdrawn []netaddr.IPPrefix
+	NLRI      []netaddr.IPPrefix
+	// ASPath contains the AS numbers of all the segments of the AS path.
+	ASPath    []uint32
+	NextHop   net.IP
+	LocalPref uint32
+	// HasLocalPref indicates whether the LOCAL_PREF attribute is present.
+	HasLocalPref bool
+}
+
+func (m *updateMsg) encode(fourOctetAS bool) []byte {
+	var withdrawn4, withdrawn6, nlri4, nlri6 []netaddr.IPPrefix
+	for _, p := range m.Withdrawn {
+		if p.IP().Is4() {
+			withdrawn4 = append(withdrawn4, p)
+		} else {
+			withdrawn6 = append(withdrawn6, p)
+		}
+	}
+	for _, p := range m.NLRI {
+		if p.IP().Is4() {
+			nlri4 = append(nlri4, p)
+		} else {
+			nlri6 = append(nlri6, p)
+		}
+	}
+
+	var attrs []byte
+	if len(m.NLRI) > 0 {
+		attrs = appendAttr(attrs, attrFlagTransitive, attrOrigin, []byte{originIncomplete})
+		var path []byte
+		if len(m.ASPath) > 0 {
+			path = []byte{asSequence, byte(len(m.ASPath))}
+			for _, as := range m.ASPath {
+				if fourOctetAS {
+					path = binary.BigEndian.AppendUint32(path, as)
+				} else if as > 0xffff {
+					path = binary.BigEndian.AppendUint16(path, asTrans)
+				} else {
+					path = binary.BigEndian.AppendUint16(path, uint16(as))
+				}
+			}
+		}
+		attrs = appendAttr(attrs, attrFlagTransitive, attrASPath, path)
+		if len(nlri4) > 0 {
+			attrs = appendAttr(attrs, attrFlagTransitive, attrNextHop, m.NextHop.To4())
+		}
+		if m.HasLocalPref {
+			attrs = appendAttr(attrs, attrFlagTransitive, attrLocalPref,
+				binary.BigEndian.AppendUint32(nil, m.LocalPref))
+		}
+		if len(nlri6) > 0 {
+			reach := []byte{0, afiIPv6, safiUnicast, net.IPv6len}
+			reach = append(reach, m.NextHop.To16()...)
+			reach = appendPrefixes(append(reach, 0), nlri6)
+			attrs = appendAttr(attrs, attrFlagOptional, attrMPReachNLRI, reach)
+		}
+	}
+	if len(withdrawn6) > 0 {
+		unreach := appendPrefixes([]byte{0, afiIPv6, safiUnicast}, withdrawn6)
+		attrs = appendAttr(attrs, attrFlagOptional, attrMPUnreachNLRI, unreach)
+	}
+
+	w := appendPrefixes(nil, withdrawn4)
+	b := binary.BigEndian.AppendUint16(nil, uint16(len(w)))
+	b = append(b, w...)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(attrs)))
+	b = append(b, attrs...)
+	return appendPrefixes(b, nlri4)
+}
+
+func appendAttr(b []byte, flags, typ byte, val []byte) []byte {
+	if len(val) > 0xff {
+		b = append(b, flags|attrFlagExtended, typ)
+		b = binary.BigEndian.AppendUint16(b, uint16(len(val)))
+	} else {
+		b = append(b, flags, typ, byte(len(val)))
+	}
+	return append(b, val...)
+}
+
+func appendPrefixes(b []byte, prefixes []netaddr.IPPrefix) []byte {
+	for _, p := range prefixes {
+		b = append(b, p.Bits())
+		ip := p.IP().As16()
+		start := 0
+		if p.IP().Is4() {
+			start = 12
+		}
+		b = append(b, ip[start:start+prefixBytes(p.Bits())]...)
+	}
+	return b
+}
+
+// prefixEncodedLen returns the number of bytes the prefix takes in the NLRI
+// encoding.
+func prefixEncodedLen(p netaddr.IPPrefix) int {
+	return 1 + prefixBytes(p.Bits())
+}
+
+func prefixBytes(bits uint8) int {
+	return (int(bits) + 7) / 8
+}
+
+func decodeUpdate(b []byte, fourOctetAS bool) (*updateMsg, error) {
+	if len(b) < 4 {
+		return nil, serrors.New("UPDATE message too short", "len", len(b))
+	}
+	m := &updateMsg{}
+	wLen := int(binary.BigEndian.Uint16(b))
+	if len(b) < 4+wLen {
+		return nil, serrors.New("invalid withdrawn routes length")
+	}
+	var err error
+	if m.Withdrawn, err = decodePrefixes(b[2:2+wLen], false); err != nil {
+		return nil, serrors.WrapStr("decoding withdrawn routes", err)
+	}
+	b = b[2+wLen:]
+	aLen := int(binary.BigEndian.Uint16(b))
+	if len(b) < 2+aLen {
+		return nil, serrors.New("invalid path attributes length")
+	}
+	attrs, nlri := b[2:2+aLen], b[2+aLen:]
+	if m.NLRI, err = decodePrefixes(nlri, false); err != nil {
+		return nil, serrors.WrapStr("decoding NLRI", err)
+	}
+	for len(attrs) > 0 {
+		if len(attrs) < 3 {
+			return nil, serrors.New("truncated path attribute")
+		}
+		flags, typ := attrs[0], attrs[1]
+		var l, off int
+		if flags&attrFlagExtended != 0 {
+			if len(attrs) < 4 {
+				return nil, serrors.New("truncated path attribute")
+			}
+			l, off = int(binary.BigEndian.Uint16(attrs[2:])), 4
+		} else {
+			l, off = int(attrs[2]), 3
+		}
+		if len(attrs) < off+l {
+			return nil, serrors.New("truncated path attribute", "type", typ)
+		}
+		val := attrs[off : off+l]
+		attrs = attrs[off+l:]
+		if err := m.decodeAttr(typ, val, fourOctetAS); err != nil {
+			return nil, serrors.WrapStr("decoding path attribute", err, "type", typ)
+		}
+	}
+	return m, nil
+}
+
+func (m *updateMsg) decodeAttr(typ byte, val []byte, fourOctetAS bool) error {
+	switch typ {
+	case attrASPath:
+		asLen := 2
+		if fourOctetAS {
+			asLen = 4
+		}
+		for len(val) > 0 {
+			if len(val) < 2 || len(val) < 2+asLen*int(val[1]) {
+				return serrors.New("truncated AS path segment")
+			}
+			n := int(val[1])
+			for i := 0; i < n; i++ {
+				as := val[2+i*asLen:]
+				if fourOctetAS {
+					m.ASPath = append(m.ASPath, binary.BigEndian.Uint32(as))
+				} else {
+					m.ASPath = append(m.ASPath, uint32(binary.BigEndian.Uint16(as)))
+				}
+			}
+			val = val[2+asLen*n:]
+		}
+	case attrNextHop:
+		if len(val) != net.IPv4len {
+			return serrors.New("invalid next hop length", "len", len(val))
+		}
+		m.NextHop = net.IP(append([]byte(nil), val...))
+	case attrLocalPref:
+		if len(val) != 4 {
+			return serrors.New("invalid local preference length", "len", len(val))
+		}
+		m.LocalPref, m.HasLocalPref = binary.BigEndian.Uint32(val), true
+	case attrMPReachNLRI:
+		if len(val) < 5 || len(val) < 5+int(val[3]) {
+			return serrors.New("truncated MP_REACH_NLRI")
+		}
+		if binary.BigEndian.Uint16(val) != afiIPv6 || val[2] != safiUnicast {
+			// Other address families are not negotiated and ignored.
+			return nil
+		}
+		nhLen := int(val[3])
+		if nhLen == net.IPv6len || nhLen == 2*net.IPv6len {
+			// The link-local next hop, if present, is ignored.
+			m.NextHop = net.IP(append([]byte(nil), val[4:4+net.IPv6len]...))
+		}
+		prefixes, err := decodePrefixes(val[5+nhLen:], true)
+		if err != nil {
+			return err
+		}
+		m.NLRI = append(m.NLRI, prefixes...)
+	case attrMPUnreachNLRI:
+		if len(val) < 3 {
+			return serrors.New("truncated MP_UNREACH_NLRI")
+		}
+		if binary.BigEndian.Uint16(val) != afiIPv6 || val[2] != safiUnicast {
+			return nil
+		}
+		prefixes, err := decodePrefixes(val[3:], true)
+		if err != nil {
+			return err
+		}
+		m.Withdrawn = append(m.Withdrawn, prefixes...)
+	}
+	return nil
+}
+
+func decodePrefixes(b []byte, ipv6 bool) ([]netaddr.IPPrefix, error) {
+	var prefixes []netaddr.IPPrefix
+	for len(b) > 0 {
+		bits := b[0]
+		maxBits := uint8(32)
+		if ipv6 {
+			maxBits = 128
+		}
+		if bits > maxBits || len(b) < 1+prefixBytes(bits) {
+			return nil, serrors.New("invalid prefix", "bits", bits)
+		}
+		var ip netaddr.IP
+		if ipv6 {
+			var a [16]byte
+			copy(a[:], b[1:1+prefixBytes(bits)])
+			ip = netaddr.IPFrom16(a)
+		} else {
+			var a [4]byte
+			copy(a[:], b[1:1+prefixBytes(bits)])
+			ip = netaddr.IPFrom4(a)
+		}
+		prefix, err := ip.Prefix(bits)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix)
+		b = b[1+prefixBytes(bits):]
+	}
+	return prefixes, nil
+}
+
+// notificationMsg is a BGP NOTIFICATION message.
+type notificationMsg struct {
+	Code    uint8
+	Subcode uint8
+}
+
+func (m *notificationMsg) encode() []byte {
+	return []byte{m.Code, m.Subcode}
+}
+
+func (m *notificationMsg) Error() string {
+	return serrors.New("BGP notification", "code", m.Code, "subcode", m.Subcode).Error()
+}
+
+func decodeNotification(b []byte) (*notificationMsg, error) {
+	if len(b) < 2 {
+		return nil, serrors.New("NOTIFICATION message too short", "len", len(b))
+	}
+	return &notificationMsg{Code: b[0], Subcode: b[1]}, nil
+}
+
+// writeMsg writes a BGP message with the given type and body.
+func writeMsg(w io.Writer, typ msgType, body []byte) error {
+	if headerLen+len(body) > maxMsgLen {
+		return serrors.New("message too long", "len", headerLen+len(body))
+	}
+	b := make([]byte, headerLen, headerLen+len(body))
+	for i := 0; i < 16; i++ {
+		b[i] = 0xff
+	}
+	binary.BigEndian.PutUint16(b[16:], uint16(headerLen+len(body)))
+	b[18] = byte(typ)
+	_, err := w.Write(append(b, body...))
+	return err
+}
+
+// readMsg reads a BGP message and returns its type and body.
+func readMsg(r io.Reader) (msgType, []byte, error) {
+	var hdr [headerLen]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+	for _, b := range hdr[:16] {
+		if b != 0xff {
+			return 0, nil, &notificationMsg{Code: errCodeHeader, Subcode: errSubBadMarker}
+		}
+	}
+	l := int(binary.BigEndian.Uint16(hdr[16:]))
+	if l < headerLen || l > maxMsgLen {
+		return 0, nil, &notificationMsg{Code: errCodeHeader, Subcode: errSubBadLength}
+	}
+	body := make([]byte, l-headerLen)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return msgType(hdr[18]), body, nil
+}
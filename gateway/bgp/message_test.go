@@ -0,0 +1,148 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgp
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"inet.af/netaddr"
+)
+
+func TestOpenRoundTrip(t *testing.T) {
+	testCases := map[string]openMsg{
+		"2-octet AS": {
+			AS:          64512,
+			HoldTime:    90,
+			ID:          [4]byte{192, 0, 2, 1},
+			FourOctetAS: true,
+			IPv6:        true,
+		},
+		"4-octet AS": {
+			AS:          4200000000,
+			HoldTime:    30,
+			ID:          [4]byte{192, 0, 2, 1},
+			FourOctetAS: true,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			decoded, err := decodeOpen(tc.encode())
+			require.NoError(t, err)
+			assert.Equal(t, tc, *decoded)
+		})
+	}
+	t.Run("4-octet AS without capability", func(t *testing.T) {
+		m := openMsg{AS: 4200000000, ID: [4]byte{192, 0, 2, 1}}
+		decoded, err := decodeOpen(m.encode())
+		require.NoError(t, err)
+		assert.Equal(t, uint32(asTrans), decoded.AS)
+	})
+}
+
+func TestUpdateRoundTrip(t *testing.T) {
+	testCases := map[string]struct {
+		Update      updateMsg
+		FourOctetAS bool
+	}{
+		"IPv4 announcement": {
+			Update: updateMsg{
+				NLRI: []netaddr.IPPrefix{
+					netaddr.MustParseIPPrefix("10.1.0.0/16"),
+					netaddr.MustParseIPPrefix("192.0.2.0/25"),
+					netaddr.MustParseIPPrefix("0.0.0.0/0"),
+				},
+				ASPath:  []uint32{4200000000},
+				NextHop: net.IP{192, 0, 2, 1},
+			},
+			FourOctetAS: true,
+		},
+		"IPv6 announcement": {
+			Update: updateMsg{
+				NLRI: []netaddr.IPPrefix{
+					netaddr.MustParseIPPrefix("2001:db8::/32"),
+					netaddr.MustParseIPPrefix("2001:db8:1:2::/64"),
+				},
+				ASPath:       []uint32{64512},
+				NextHop:      net.ParseIP("2001:db8::1"),
+				LocalPref:    100,
+				HasLocalPref: true,
+			},
+		},
+		"withdrawal": {
+			Update: updateMsg{
+				Withdrawn: []netaddr.IPPrefix{
+					netaddr.MustParseIPPrefix("10.1.0.0/16"),
+					netaddr.MustParseIPPrefix("2001:db8::/32"),
+				},
+			},
+			FourOctetAS: true,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			decoded, err := decodeUpdate(tc.Update.encode(tc.FourOctetAS), tc.FourOctetAS)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Update, *decoded)
+		})
+	}
+}
+
+func TestDecodeUpdateErrors(t *testing.T) {
+	testCases := map[string][]byte{
+		"too short":                {0, 0},
+		"invalid withdrawn length": {0, 10, 0, 0},
+		"invalid attribute length": {0, 0, 0, 10},
+		"truncated attribute":      {0, 0, 0, 3, 0x40, attrOrigin, 1},
+		"invalid prefix length":    {0, 0, 0, 0, 33, 10, 0, 0, 0, 0},
+		"truncated prefix":         {0, 0, 0, 0, 24, 10},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeUpdate(tc, true)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestReadWriteMsg(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeMsg(&buf, msgKeepalive, nil))
+	assert.Equal(t, headerLen, buf.Len())
+	require.NoError(t, writeMsg(&buf, msgNotification, []byte{6, 2}))
+
+	typ, body, err := readMsg(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, msgKeepalive, typ)
+	assert.Empty(t, body)
+	typ, body, err = readMsg(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, msgNotification, typ)
+	assert.Equal(t, []byte{6, 2}, body)
+
+	assert.Error(t, writeMsg(&buf, msgUpdate, make([]byte, maxMsgLen)))
+
+	// Invalid marker.
+	_, _, err = readMsg(bytes.NewReader(make([]byte, headerLen)))
+	var n *notificationMsg
+	require.ErrorAs(t, err, &n)
+	assert.Equal(t, uint8(errCodeHeader), n.Code)
+}
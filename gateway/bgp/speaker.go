@@ -0,0 +1,589 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bgp implements a minimal BGP-4 speaker (RFC 4271) that lets the
+// gateway exchange IP prefixes with a router in the local network.
+//
+// The speaker maintains a single session with one peer. It announces the
+// prefixes set with Announce, using itself as the next hop, and keeps track of
+// the unicast prefixes the peer announces. IPv6 prefixes are exchanged with the
+// multiprotocol extensions (RFC 4760) and 4-octet AS numbers are supported
+// (RFC 6793). The speaker does not implement a decision process, route
+// refresh, or graceful restart.
+package bgp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"inet.af/netaddr"
+
+	"github.com/scionproto/scion/pkg/log"
+	"github.com/scionproto/scion/pkg/private/serrors"
+)
+
+const (
+	// DefaultHoldTime is the hold time proposed to the peer if none is
+	// configured.
+	DefaultHoldTime = 90 * time.Second
+	// DefaultConnectRetry is the time between connection attempts if none is
+	// configured.
+	DefaultConnectRetry = 5 * time.Second
+	// DefaultPort is the BGP port.
+	DefaultPort = 179
+
+	// openHoldTime is the hold time while waiting for the OPEN message of the
+	// peer (RFC 4271, Section 8).
+	openHoldTime = 4 * time.Minute
+	writeTimeout = 5 * time.Second
+	// localPref is the local preference attached to routes announced to
+	// internal peers.
+	localPref = 100
+)
+
+// Speaker is a BGP speaker with a single peer.
+type Speaker struct {
+	// LocalAS is the AS number of the speaker.
+	LocalAS uint32
+	// PeerAS is the expected AS number of the peer. If it is equal to LocalAS,
+	// the session is an internal BGP session.
+	PeerAS uint32
+	// RouterID is the BGP identifier of the speaker. It must be an IPv4
+	// address.
+	RouterID net.IP
+	// HoldTime is the hold time proposed to the peer. If zero,
+	// DefaultHoldTime is used.
+	HoldTime time.Duration
+	// ConnectRetry is the time between connection attempts. If zero,
+	// DefaultConnectRetry is used.
+	ConnectRetry time.Duration
+	// NextHopIPv4 is the next hop announced for IPv4 prefixes. If nil, the
+	// local address of the session is used if it is an IPv4 address.
+	// Otherwise, IPv4 prefixes are not announced.
+	NextHopIPv4 net.IP
+	// NextHopIPv6 is the next hop announced for IPv6 prefixes. If nil, the
+	// local address of the session is used if it is an IPv6 address.
+	// Otherwise, IPv6 prefixes are not announced.
+	NextHopIPv6 net.IP
+
+	mtx       sync.Mutex
+	announced map[netaddr.IPPrefix]struct{}
+	received  map[netaddr.IPPrefix]struct{}
+	// running indicates whether a session is running, established indicates
+	// whether it completed the handshake.
+	running     bool
+	established bool
+	// changed is signaled when the announced prefixes change.
+	changed chan struct{}
+}
+
+func (s *Speaker) initLocked() {
+	if s.changed != nil {
+		return
+	}
+	s.announced = make(map[netaddr.IPPrefix]struct{})
+	s.received = make(map[netaddr.IPPrefix]struct{})
+	s.changed = make(chan struct{}, 1)
+}
+
+// Announce adds the prefix to the prefixes announced to the peer. Duplicates
+// are a no-op.
+func (s *Speaker) Announce(prefix netaddr.IPPrefix) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.initLocked()
+
+	s.announced[prefix.Masked()] = struct{}{}
+	s.signalLocked()
+}
+
+// Withdraw removes the prefix from the prefixes announced to the peer. If the
+// prefix is not announced, the call is a no-op.
+func (s *Speaker) Withdraw(prefix netaddr.IPPrefix) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.initLocked()
+
+	delete(s.announced, prefix.Masked())
+	s.signalLocked()
+}
+
+func (s *Speaker) signalLocked() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Received returns the sorted list of prefixes currently announced by the
+// peer. If no session is established, the list is empty.
+func (s *Speaker) Received() []netaddr.IPPrefix {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	prefixes := make([]netaddr.IPPrefix, 0, len(s.received))
+	for p := range s.received {
+		prefixes = append(prefixes, p)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		if c := prefixes[i].IP().Compare(prefixes[j].IP()); c != 0 {
+			return c < 0
+		}
+		return prefixes[i].Bits() < prefixes[j].Bits()
+	})
+	return prefixes
+}
+
+// Established returns whether a session with the peer is established.
+func (s *Speaker) Established() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.established
+}
+
+// Dial connects to the peer at the given address and runs the session. If the
+// connection fails or the session terminates, the speaker reconnects after
+// the connect retry time. Dial returns when the context is canceled.
+func (s *Speaker) Dial(ctx context.Context, address string) error {
+	logger := log.FromCtx(ctx)
+	retry := s.ConnectRetry
+	if retry == 0 {
+		retry = DefaultConnectRetry
+	}
+	var dialer net.Dialer
+	for {
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			logger.Debug("Connecting to BGP peer failed", "peer", address, "err", err)
+		} else if err := s.runSession(ctx, conn); err != nil {
+			logger.Info("BGP session terminated", "peer", address, "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(retry):
+		}
+	}
+}
+
+// Serve accepts connections from the peer on the listener and runs the
+// session. Connections arriving while a session is running are rejected.
+// Serve returns when the context is canceled or the listener fails.
+func (s *Speaker) Serve(ctx context.Context, listener net.Listener) error {
+	logger := log.FromCtx(ctx)
+	go func() {
+		defer log.HandlePanic()
+		<-ctx.Done()
+		listener.Close()
+	}()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return serrors.WrapStr("accepting BGP connection", err)
+		}
+		go func() {
+			defer log.HandlePanic()
+			if err := s.runSession(ctx, conn); err != nil {
+				logger.Info("BGP session terminated", "peer", conn.RemoteAddr(), "err", err)
+			}
+		}()
+	}
+}
+
+// runSession runs the BGP session on the connection until it terminates. The
+// connection is closed when the function returns.
+func (s *Speaker) runSession(ctx context.Context, conn net.Conn) error {
+	defer conn.Close()
+
+	s.mtx.Lock()
+	s.initLocked()
+	if s.running {
+		s.mtx.Unlock()
+		sendNotification(conn, &notificationMsg{Code: errCodeCease, Subcode: errSubCollision})
+		return serrors.New("session already running")
+	}
+	s.running = true
+	s.mtx.Unlock()
+
+	defer func() {
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+		s.running = false
+		s.established = false
+		s.received = make(map[netaddr.IPPrefix]struct{})
+	}()
+
+	sess := &session{
+		speaker: s,
+		conn:    conn,
+		sent:    make(map[netaddr.IPPrefix]struct{}),
+	}
+	err := sess.run(ctx)
+	var n *notificationMsg
+	if errors.As(err, &n) {
+		sendNotification(conn, n)
+	}
+	return err
+}
+
+func sendNotification(conn net.Conn, n *notificationMsg) {
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	// The connection is closed in any case, errors are thus ignored.
+	_ = writeMsg(conn, msgNotification, n.encode())
+}
+
+// session is a single BGP session.
+type session struct {
+	speaker *Speaker
+	conn    net.Conn
+
+	fourOctetAS bool
+	ipv6        bool
+	holdTime    time.Duration
+	nextHop4    net.IP
+	nextHop6    net.IP
+	// sent contains the prefixes that are currently announced to the peer.
+	sent map[netaddr.IPPrefix]struct{}
+}
+
+type message struct {
+	typ  msgType
+	body []byte
+}
+
+func (s *session) run(ctx context.Context) error {
+	if err := s.handshake(); err != nil {
+		return err
+	}
+	s.speaker.mtx.Lock()
+	s.speaker.established = true
+	s.speaker.mtx.Unlock()
+	log.FromCtx(ctx).Info("BGP session established", "peer", s.conn.RemoteAddr())
+
+	msgs := make(chan message)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer log.HandlePanic()
+		for {
+			typ, body, err := readMsg(s.conn)
+			if err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case msgs <- message{typ: typ, body: body}:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	// If the hold time is zero, neither keepalives nor the hold timer are
+	// used.
+	var keepalive, holdExpired <-chan time.Time
+	var holdTimer *time.Timer
+	if s.holdTime != 0 {
+		holdTimer = time.NewTimer(s.holdTime)
+		defer holdTimer.Stop()
+		holdExpired = holdTimer.C
+		ticker := time.NewTicker(s.holdTime / 3)
+		defer ticker.Stop()
+		keepalive = ticker.C
+	}
+	// Announce the initial set of prefixes.
+	if err := s.sync(ctx); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return &notificationMsg{Code: errCodeCease, Subcode: errSubAdminShutdown}
+		case err := <-readErr:
+			return serrors.WrapStr("reading message", err)
+		case <-holdExpired:
+			return &notificationMsg{Code: errCodeHoldTimer}
+		case <-keepalive:
+			if err := s.write(msgKeepalive, nil); err != nil {
+				return err
+			}
+		case <-s.speaker.changed:
+			if err := s.sync(ctx); err != nil {
+				return err
+			}
+		case msg := <-msgs:
+			if holdTimer != nil {
+				if !holdTimer.Stop() {
+					<-holdTimer.C
+				}
+				holdTimer.Reset(s.holdTime)
+			}
+			if err := s.handle(ctx, msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handshake exchanges the OPEN and the initial KEEPALIVE messages.
+func (s *session) handshake() error {
+	sp := s.speaker
+	holdTime := sp.HoldTime
+	if holdTime == 0 {
+		holdTime = DefaultHoldTime
+	}
+	routerID := sp.RouterID.To4()
+	if routerID == nil {
+		return serrors.New("router ID must be an IPv4 address", "router_id", sp.RouterID)
+	}
+	open := &openMsg{
+		AS:          sp.LocalAS,
+		HoldTime:    uint16(holdTime / time.Second),
+		FourOctetAS: true,
+		IPv6:        true,
+	}
+	copy(open.ID[:], routerID)
+	if err := s.write(msgOpen, open.encode()); err != nil {
+		return err
+	}
+
+	s.conn.SetReadDeadline(time.Now().Add(openHoldTime))
+	typ, body, err := readMsg(s.conn)
+	if err != nil {
+		return serrors.WrapStr("reading OPEN message", err)
+	}
+	if err := checkType(typ, body, msgOpen); err != nil {
+		return err
+	}
+	peer, err := decodeOpen(body)
+	if err != nil {
+		return serrors.WithCtx(&notificationMsg{Code: errCodeOpen}, "err", err)
+	}
+	if peer.AS != sp.PeerAS {
+		return serrors.WithCtx(&notificationMsg{Code: errCodeOpen, Subcode: errSubBadPeerAS},
+			"expected", sp.PeerAS, "actual", peer.AS)
+	}
+	if peer.HoldTime == 1 || peer.HoldTime == 2 {
+		return serrors.WithCtx(&notificationMsg{Code: errCodeOpen,
+			Subcode: errSubBadHoldTime}, "hold_time", peer.HoldTime)
+	}
+	s.fourOctetAS = peer.FourOctetAS
+	s.ipv6 = peer.IPv6
+	s.holdTime = holdTime
+	if peerHoldTime := time.Duration(peer.HoldTime) * time.Second; peerHoldTime < holdTime {
+		s.holdTime = peerHoldTime
+	}
+	s.nextHop4, s.nextHop6 = sp.NextHopIPv4.To4(), sp.NextHopIPv6
+	if local, ok := s.conn.LocalAddr().(*net.TCPAddr); ok {
+		if s.nextHop4 == nil && local.IP.To4() != nil {
+			s.nextHop4 = local.IP.To4()
+		}
+		if s.nextHop6 == nil && local.IP.To4() == nil {
+			s.nextHop6 = local.IP
+		}
+	}
+	if err := s.write(msgKeepalive, nil); err != nil {
+		return err
+	}
+
+	typ, body, err = readMsg(s.conn)
+	if err != nil {
+		return serrors.WrapStr("reading KEEPALIVE message", err)
+	}
+	if err := checkType(typ, body, msgKeepalive); err != nil {
+		return err
+	}
+	s.conn.SetReadDeadline(time.Time{})
+	return nil
+}
+
+// checkType checks that the message received during the handshake has the
+// expected type.
+func checkType(actual msgType, body []byte, expected msgType) error {
+	switch actual {
+	case expected:
+		return nil
+	case msgNotification:
+		n, err := decodeNotification(body)
+		if err != nil {
+			return err
+		}
+		return serrors.New("received notification", "code", n.Code, "subcode", n.Subcode)
+	default:
+		return serrors.WithCtx(&notificationMsg{Code: errCodeFSM}, "type", actual)
+	}
+}
+
+// handle processes a message received on the established session.
+func (s *session) handle(ctx context.Context, msg message) error {
+	switch msg.typ {
+	case msgKeepalive:
+		return nil
+	case msgNotification:
+		n, err := decodeNotification(msg.body)
+		if err != nil {
+			return err
+		}
+		return serrors.New("received notification", "code", n.Code, "subcode", n.Subcode)
+	case msgUpdate:
+		update, err := decodeUpdate(msg.body, s.fourOctetAS)
+		if err != nil {
+			return serrors.WithCtx(&notificationMsg{Code: errCodeUpdate,
+				Subcode: errSubMalformedAttributes}, "err", err)
+		}
+		s.receive(ctx, update)
+		return nil
+	default:
+		return serrors.WithCtx(&notificationMsg{Code: errCodeFSM}, "type", msg.typ)
+	}
+}
+
+// receive updates the received prefixes. Prefixes whose AS path contains the
+// local AS are discarded to avoid routing loops.
+func (s *session) receive(ctx context.Context, update *updateMsg) {
+	sp := s.speaker
+	loop := false
+	for _, as := range update.ASPath {
+		if as == sp.LocalAS && sp.LocalAS != sp.PeerAS {
+			loop = true
+		}
+	}
+
+	sp.mtx.Lock()
+	defer sp.mtx.Unlock()
+	for _, p := range update.Withdrawn {
+		delete(sp.received, p)
+	}
+	for _, p := range update.NLRI {
+		if loop {
+			// The announcement replaces the previous one, which is thus
+			// implicitly withdrawn.
+			delete(sp.received, p)
+			continue
+		}
+		sp.received[p] = struct{}{}
+	}
+	log.FromCtx(ctx).Debug("Received BGP update", "withdrawn", len(update.Withdrawn),
+		"announced", len(update.NLRI), "loop", loop)
+}
+
+// sync sends the updates required to bring the prefixes announced to the peer
+// in line with the prefixes announced by the speaker.
+func (s *session) sync(ctx context.Context) error {
+	sp := s.speaker
+	var announce, withdraw []netaddr.IPPrefix
+	sp.mtx.Lock()
+	for p := range sp.announced {
+		if _, ok := s.sent[p]; !ok && s.nextHop(p) != nil {
+			announce = append(announce, p)
+		}
+	}
+	for p := range s.sent {
+		if _, ok := sp.announced[p]; !ok {
+			withdraw = append(withdraw, p)
+		}
+	}
+	sp.mtx.Unlock()
+
+	var asPath []uint32
+	if sp.LocalAS != sp.PeerAS {
+		asPath = []uint32{sp.LocalAS}
+	}
+	for _, batch := range batches(withdraw) {
+		update := &updateMsg{Withdrawn: batch}
+		if err := s.write(msgUpdate, update.encode(s.fourOctetAS)); err != nil {
+			return err
+		}
+	}
+	for _, batch := range batches(announce) {
+		update := &updateMsg{
+			NLRI:         batch,
+			ASPath:       asPath,
+			NextHop:      s.nextHop(batch[0]),
+			LocalPref:    localPref,
+			HasLocalPref: sp.LocalAS == sp.PeerAS,
+		}
+		if err := s.write(msgUpdate, update.encode(s.fourOctetAS)); err != nil {
+			return err
+		}
+	}
+	for _, p := range withdraw {
+		delete(s.sent, p)
+	}
+	for _, p := range announce {
+		s.sent[p] = struct{}{}
+	}
+	if len(withdraw) > 0 || len(announce) > 0 {
+		log.FromCtx(ctx).Debug("Sent BGP update", "withdrawn", len(withdraw),
+			"announced", len(announce))
+	}
+	return nil
+}
+
+// nextHop returns the next hop for the prefix, or nil if the prefix cannot be
+// announced.
+func (s *session) nextHop(p netaddr.IPPrefix) net.IP {
+	if p.IP().Is4() {
+		return s.nextHop4
+	}
+	if !s.ipv6 {
+		return nil
+	}
+	return s.nextHop6
+}
+
+// batchBudget is the number of bytes available for prefixes in an UPDATE
+// message. It leaves room for the header and the path attributes.
+const batchBudget = maxMsgLen - headerLen - 128
+
+// batches splits the prefixes into batches of a single address family that
+// fit into one UPDATE message.
+func batches(prefixes []netaddr.IPPrefix) [][]netaddr.IPPrefix {
+	var result [][]netaddr.IPPrefix
+	for _, is4 := range []bool{true, false} {
+		var batch []netaddr.IPPrefix
+		size := 0
+		for _, p := range prefixes {
+			if p.IP().Is4() != is4 {
+				continue
+			}
+			if size+prefixEncodedLen(p) > batchBudget {
+				result = append(result, batch)
+				batch, size = nil, 0
+			}
+			batch = append(batch, p)
+			size += prefixEncodedLen(p)
+		}
+		if len(batch) > 0 {
+			result = append(result, batch)
+		}
+	}
+	return result
+}
+
+func (s *session) write(typ msgType, body []byte) error {
+	s.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := writeMsg(s.conn, typ, body); err != nil {
+		return serrors.WrapStr("writing message", err, "type", typ)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgp_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"inet.af/netaddr"
+
+	"github.com/scionproto/scion/gateway/bgp"
+)
+
+// startPeer starts a speaker that accepts a connection on the loopback
+// interface and returns the address it listens on.
+func startPeer(ctx context.Context, t *testing.T, peer *bgp.Speaker) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		peer.Serve(ctx, listener)
+	}()
+	return listener.Addr().String()
+}
+
+func prefixes(ps ...string) []netaddr.IPPrefix {
+	result := []netaddr.IPPrefix{}
+	for _, p := range ps {
+		result = append(result, netaddr.MustParseIPPrefix(p))
+	}
+	return result
+}
+
+func TestSpeaker(t *testing.T) {
+	testCases := map[string]struct {
+		LocalAS uint32
+		PeerAS  uint32
+	}{
+		"external": {
+			LocalAS: 4200000001,
+			PeerAS:  64512,
+		},
+		"internal": {
+			LocalAS: 64512,
+			PeerAS:  64512,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			router := &bgp.Speaker{
+				LocalAS:  tc.PeerAS,
+				PeerAS:   tc.LocalAS,
+				RouterID: net.IP{192, 0, 2, 2},
+			}
+			router.Announce(netaddr.MustParseIPPrefix("172.16.0.0/12"))
+			addr := startPeer(ctx, t, router)
+
+			gateway := &bgp.Speaker{
+				LocalAS:      tc.LocalAS,
+				PeerAS:       tc.PeerAS,
+				RouterID:     net.IP{192, 0, 2, 1},
+				ConnectRetry: 10 * time.Millisecond,
+				NextHopIPv6:  net.ParseIP("2001:db8::1"),
+			}
+			gateway.Announce(netaddr.MustParseIPPrefix("10.1.0.0/16"))
+			gateway.Announce(netaddr.MustParseIPPrefix("2001:db8:1::/48"))
+			gatewayCtx, gatewayCancel := context.WithCancel(ctx)
+			gatewayDone := make(chan struct{})
+			go func() {
+				defer close(gatewayDone)
+				gateway.Dial(gatewayCtx, addr)
+			}()
+
+			assert.Eventually(t, func() bool {
+				return assert.ObjectsAreEqual(prefixes("10.1.0.0/16", "2001:db8:1::/48"),
+					router.Received())
+			}, 5*time.Second, 10*time.Millisecond)
+			assert.Eventually(t, func() bool {
+				return assert.ObjectsAreEqual(prefixes("172.16.0.0/12"), gateway.Received())
+			}, 5*time.Second, 10*time.Millisecond)
+			assert.True(t, gateway.Established())
+
+			gateway.Withdraw(netaddr.MustParseIPPrefix("2001:db8:1::/48"))
+			gateway.Announce(netaddr.MustParseIPPrefix("192.168.0.0/24"))
+			router.Withdraw(netaddr.MustParseIPPrefix("172.16.0.0/12"))
+			assert.Eventually(t, func() bool {
+				return assert.ObjectsAreEqual(prefixes("10.1.0.0/16", "192.168.0.0/24"),
+					router.Received())
+			}, 5*time.Second, 10*time.Millisecond)
+			assert.Eventually(t, func() bool {
+				return len(gateway.Received()) == 0
+			}, 5*time.Second, 10*time.Millisecond)
+
+			// The routes are removed once the session terminates.
+			gatewayCancel()
+			<-gatewayDone
+			assert.Eventually(t, func() bool {
+				return len(router.Received()) == 0 && !router.Established()
+			}, 5*time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestSpeakerPeerASMismatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	router := &bgp.Speaker{
+		LocalAS:  64513,
+		PeerAS:   64512,
+		RouterID: net.IP{192, 0, 2, 2},
+	}
+	router.Announce(netaddr.MustParseIPPrefix("172.16.0.0/12"))
+	addr := startPeer(ctx, t, router)
+
+	gateway := &bgp.Speaker{
+		LocalAS:      64512,
+		PeerAS:       64514,
+		RouterID:     net.IP{192, 0, 2, 1},
+		ConnectRetry: 10 * time.Millisecond,
+	}
+	go func() {
+		gateway.Dial(ctx, addr)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, gateway.Established())
+	assert.Empty(t, gateway.Received())
+}
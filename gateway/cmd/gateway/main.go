@@ -26,6 +26,8 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/scionproto/scion/gateway"
+	"github.com/scionproto/scion/gateway/attestation"
+	"github.com/scionproto/scion/gateway/bgp"
 	"github.com/scionproto/scion/gateway/config"
 	"github.com/scionproto/scion/gateway/dataplane"
 	api "github.com/scionproto/scion/gateway/mgmtapi"
@@ -37,6 +39,7 @@ import (
 	"github.com/scionproto/scion/private/app"
 	"github.com/scionproto/scion/private/app/launcher"
 	"github.com/scionproto/scion/private/service"
+	"github.com/scionproto/scion/private/trust"
 )
 
 var globalCfg config.Config
@@ -92,42 +95,24 @@ func realMain(ctx context.Context) error {
 	}
 	var cleanup app.Cleanup
 	g, errCtx := errgroup.WithContext(ctx)
-	if globalCfg.API.Addr != "" {
-		r := chi.NewRouter()
-		r.Use(cors.Handler(cors.Options{
-			AllowedOrigins: []string{"*"},
-		}))
-		r.Get("/", api.ServeSpecInteractive)
-		r.Get("/openapi.json", api.ServeSpecJSON)
-		server := api.Server{
-			Config:   service.NewConfigStatusPage(globalCfg).Handler,
-			Info:     service.NewInfoStatusPage().Handler,
-			LogLevel: service.NewLogLevelStatusPage().Handler,
-		}
-		log.Info("Exposing API", "addr", globalCfg.API.Addr)
-		h := api.HandlerFromMuxWithBaseURL(&server, r, "/api/v1")
-		mgmtServer := &http.Server{
-			Addr:    globalCfg.API.Addr,
-			Handler: h,
-		}
-		defer mgmtServer.Close()
-		g.Go(func() error {
-			defer log.HandlePanic()
-			err := mgmtServer.ListenAndServe()
-			if err != nil && !errors.Is(err, http.ErrServerClosed) {
-				return serrors.WrapStr("serving service management API", err)
-			}
-			return nil
-		})
-		cleanup.Add(mgmtServer.Close)
-	}
-
 	httpPages := service.StatusPages{
 		"info":      service.NewInfoStatusPage(),
 		"config":    service.NewConfigStatusPage(globalCfg),
 		"log/level": service.NewLogLevelStatusPage(),
 	}
 	routingTable := &dataplane.AtomicRoutingTable{}
+	var tunnelSocket, tunnelSocketRemote net.Addr
+	if globalCfg.Tunnel.Socket != "" {
+		if tunnelSocket, err = config.ParseSocketAddr(globalCfg.Tunnel.Socket); err != nil {
+			return serrors.WrapStr("parsing tunnel socket", err)
+		}
+	}
+	if globalCfg.Tunnel.SocketRemote != "" {
+		tunnelSocketRemote, err = config.ParseSocketAddr(globalCfg.Tunnel.SocketRemote)
+		if err != nil {
+			return serrors.WrapStr("parsing remote tunnel socket", err)
+		}
+	}
 	gw := &gateway.Gateway{
 		ID:                       globalCfg.Gateway.ID,
 		TrafficPolicyFile:        globalCfg.Gateway.TrafficPolicy,
@@ -142,9 +127,15 @@ func realMain(ctx context.Context) error {
 		DataClientIP:             dataAddress.IP,
 		Dispatcher:               reliable.NewDispatcher(""),
 		Daemon:                   daemon,
+		FrameProtection:          globalCfg.Gateway.FrameProtection != config.FrameProtectionDisabled,
+		RequireFrameProtection:   globalCfg.Gateway.FrameProtection == config.FrameProtectionRequired,
 		RouteSourceIPv4:          globalCfg.Tunnel.SrcIPv4,
 		RouteSourceIPv6:          globalCfg.Tunnel.SrcIPv6,
 		TunnelName:               globalCfg.Tunnel.Name,
+		TunnelSocket:             tunnelSocket,
+		TunnelSocketRemote:       tunnelSocketRemote,
+		SendPacketTooBig:         globalCfg.Tunnel.PacketTooBig,
+		ClampTCPMSS:              globalCfg.Tunnel.ClampTCPMSS,
 		RoutingTableReader:       routingTable,
 		RoutingTableSwapper:      routingTable,
 		ConfigReloadTrigger:      app.SIGHUPChannel(ctx),
@@ -152,6 +143,78 @@ func realMain(ctx context.Context) error {
 		HTTPServeMux:             http.DefaultServeMux,
 		Metrics:                  gateway.NewMetrics(localIA),
 	}
+	if globalCfg.BGP.Peer != "" {
+		gw.BGP = &gateway.BGP{
+			Speaker: &bgp.Speaker{
+				LocalAS:     globalCfg.BGP.LocalAS,
+				PeerAS:      globalCfg.BGP.PeerAS,
+				RouterID:    globalCfg.BGP.RouterID,
+				HoldTime:    globalCfg.BGP.HoldTime.Duration,
+				NextHopIPv4: globalCfg.BGP.NextHopIPv4,
+				NextHopIPv6: globalCfg.BGP.NextHopIPv6,
+			},
+			Peer:   globalCfg.BGP.Peer,
+			Export: globalCfg.BGP.Export,
+			Import: globalCfg.BGP.Import,
+		}
+	}
+	if path := globalCfg.Gateway.PrefixAttestations; path != "" {
+		file := &attestation.File{Path: path}
+		if err := file.Load(); err != nil {
+			return err
+		}
+		gw.PrefixAttestations = file
+	}
+	if globalCfg.Gateway.PrefixOriginValidation != config.PrefixOriginValidationDisabled {
+		trcs := &attestation.TRCStore{}
+		loaded, err := trust.LoadTRCs(ctx, globalCfg.Gateway.TRCDir, trcs)
+		if err != nil {
+			return serrors.WrapStr("loading TRCs", err)
+		}
+		log.Info("TRCs loaded", "files", loaded.Loaded)
+		for f, r := range loaded.Ignored {
+			log.Info("Ignoring non-TRC", "file", f, "reason", r)
+		}
+		holders, err := attestation.LoadHolders(globalCfg.Gateway.PrefixHolders)
+		if err != nil {
+			return err
+		}
+		gw.PrefixOriginVerifier = attestation.Verifier{TRCs: trcs}
+		gw.PrefixHolders = holders
+		gw.RejectInvalidPrefixOrigins = globalCfg.Gateway.PrefixOriginValidation ==
+			config.PrefixOriginValidationReject
+	}
+
+	if globalCfg.API.Addr != "" {
+		r := chi.NewRouter()
+		r.Use(cors.Handler(cors.Options{
+			AllowedOrigins: []string{"*"},
+		}))
+		r.Get("/", api.ServeSpecInteractive)
+		r.Get("/openapi.json", api.ServeSpecJSON)
+		server := api.Server{
+			Config:   service.NewConfigStatusPage(globalCfg).Handler,
+			Info:     service.NewInfoStatusPage().Handler,
+			LogLevel: service.NewLogLevelStatusPage().Handler,
+			State:    gw,
+		}
+		log.Info("Exposing API", "addr", globalCfg.API.Addr)
+		h := api.HandlerFromMuxWithBaseURL(&server, r, "/api/v1")
+		mgmtServer := &http.Server{
+			Addr:    globalCfg.API.Addr,
+			Handler: h,
+		}
+		defer mgmtServer.Close()
+		g.Go(func() error {
+			defer log.HandlePanic()
+			err := mgmtServer.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return serrors.WrapStr("serving service management API", err)
+			}
+			return nil
+		})
+		cleanup.Add(mgmtServer.Close)
+	}
 
 	g.Go(func() error {
 		defer log.HandlePanic()
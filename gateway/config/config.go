@@ -18,8 +18,12 @@ import (
 	"io"
 	"net"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/scionproto/scion/pkg/log"
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/private/util"
 	"github.com/scionproto/scion/private/config"
 	"github.com/scionproto/scion/private/env"
 	api "github.com/scionproto/scion/private/mgmtapi"
@@ -43,6 +47,34 @@ const (
 
 	DefaultTunnelName           = "sig"
 	DefaultTunnelRoutingTableID = 11
+
+	DefaultBGPHoldTime = 90 * time.Second
+	defaultBGPPort     = 179
+)
+
+// Frame protection modes.
+const (
+	// FrameProtectionDisabled disables frame protection.
+	FrameProtectionDisabled = "disabled"
+	// FrameProtectionEnabled protects the frames exchanged with remote gateways
+	// that support frame protection. The support is advertised in the
+	// unauthenticated prefix exchange, and unprotected frames are still
+	// accepted. Thus, this mode does not protect against active attackers.
+	FrameProtectionEnabled = "enabled"
+	// FrameProtectionRequired protects all frames and discards unprotected
+	// frames.
+	FrameProtectionRequired = "required"
+)
+
+// Prefix origin validation modes.
+const (
+	// PrefixOriginValidationDisabled disables the prefix origin validation.
+	PrefixOriginValidationDisabled = "disabled"
+	// PrefixOriginValidationDeprioritize deprioritizes prefixes with an invalid
+	// origin.
+	PrefixOriginValidationDeprioritize = "deprioritize"
+	// PrefixOriginValidationReject discards prefixes with an invalid origin.
+	PrefixOriginValidationReject = "reject"
 )
 
 type Config struct {
@@ -53,6 +85,7 @@ type Config struct {
 	Daemon   env.Daemon   `toml:"sciond_connection,omitempty"`
 	Gateway  Gateway      `toml:"gateway,omitempty"`
 	Tunnel   Tunnel       `toml:"tunnel,omitempty"`
+	BGP      BGP          `toml:"bgp,omitempty"`
 }
 
 func (cfg *Config) InitDefaults() {
@@ -64,6 +97,7 @@ func (cfg *Config) InitDefaults() {
 		&cfg.Daemon,
 		&cfg.Gateway,
 		&cfg.Tunnel,
+		&cfg.BGP,
 	)
 }
 
@@ -76,6 +110,7 @@ func (cfg *Config) Validate() error {
 		&cfg.Daemon,
 		&cfg.Gateway,
 		&cfg.Tunnel,
+		&cfg.BGP,
 	)
 }
 
@@ -88,6 +123,7 @@ func (cfg *Config) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
 		&cfg.Daemon,
 		&cfg.Gateway,
 		&cfg.Tunnel,
+		&cfg.BGP,
 	)
 }
 
@@ -107,6 +143,20 @@ type Gateway struct {
 	DataAddr string `toml:"data_addr,omitempty"`
 	// Probe address, for probing paths.
 	ProbeAddr string `toml:"probe_addr,omitempty"`
+	// FrameProtection is the frame protection mode.
+	FrameProtection string `toml:"frame_protection,omitempty"`
+	// PrefixOriginValidation is the prefix origin validation mode.
+	PrefixOriginValidation string `toml:"prefix_origin_validation,omitempty"`
+	// PrefixAttestations is the file path of the signed prefix attestations
+	// advertised to remote gateways.
+	PrefixAttestations string `toml:"prefix_attestations_file,omitempty"`
+	// TRCDir is the directory with the TRCs used to verify prefix
+	// attestations.
+	TRCDir string `toml:"trc_dir,omitempty"`
+	// PrefixHolders is the file path of the IP prefixes held by each ISD-AS.
+	// Prefix attestations are only trusted for the prefixes held by the
+	// attested ISD-AS.
+	PrefixHolders string `toml:"prefix_holders_file,omitempty"`
 }
 
 func (cfg *Gateway) Validate() error {
@@ -119,6 +169,30 @@ func (cfg *Gateway) Validate() error {
 	cfg.CtrlAddr = DefaultAddress(cfg.CtrlAddr, defaultCtrlPort)
 	cfg.DataAddr = DefaultAddress(cfg.DataAddr, defaultDataPort)
 	cfg.ProbeAddr = DefaultAddress(cfg.ProbeAddr, defaultProbePort)
+	switch cfg.FrameProtection {
+	case "":
+		cfg.FrameProtection = FrameProtectionDisabled
+	case FrameProtectionDisabled, FrameProtectionEnabled, FrameProtectionRequired:
+	default:
+		return serrors.New("unknown frame protection mode", "mode", cfg.FrameProtection)
+	}
+	switch cfg.PrefixOriginValidation {
+	case "":
+		cfg.PrefixOriginValidation = PrefixOriginValidationDisabled
+	case PrefixOriginValidationDisabled:
+	case PrefixOriginValidationDeprioritize, PrefixOriginValidationReject:
+		if cfg.TRCDir == "" {
+			return serrors.New("trc_dir must be set for prefix origin validation",
+				"mode", cfg.PrefixOriginValidation)
+		}
+		if cfg.PrefixHolders == "" {
+			return serrors.New("prefix_holders_file must be set for prefix origin validation",
+				"mode", cfg.PrefixOriginValidation)
+		}
+	default:
+		return serrors.New("unknown prefix origin validation mode",
+			"mode", cfg.PrefixOriginValidation)
+	}
 	return nil
 }
 
@@ -140,15 +214,77 @@ type Tunnel struct {
 	SrcIPv4 net.IP `toml:"src_ipv4,omitempty"`
 	// SrcIPv6 is the source address to put into the routing table.
 	SrcIPv6 net.IP `toml:"src_ipv6,omitempty"`
+	// PacketTooBig enables ICMP Packet Too Big messages for packets that do
+	// not fit into a single frame and must not be fragmented.
+	PacketTooBig bool `toml:"packet_too_big,omitempty"`
+	// ClampTCPMSS enables clamping of the TCP maximum segment size on SYN
+	// packets to the tunnel MTU.
+	ClampTCPMSS bool `toml:"clamp_tcp_mss,omitempty"`
+	// Socket is the local address of a datagram socket that is used instead of
+	// the TUN device, either udp://host:port or unix:///path. If set, no TUN
+	// device is created and no routes are installed.
+	Socket string `toml:"socket,omitempty"`
+	// SocketRemote is the address to which the IP packets are sent if Socket
+	// is set. If empty, the packets are sent to the address of the last
+	// received packet; this is only allowed for Unix sockets and UDP sockets
+	// bound to a loopback address.
+	SocketRemote string `toml:"socket_remote,omitempty"`
 }
 
 func (cfg *Tunnel) Validate() error {
 	if cfg.Name == "" {
 		cfg.Name = DefaultTunnelName
 	}
+	if cfg.Socket == "" {
+		if cfg.SocketRemote != "" {
+			return serrors.New("socket_remote requires socket")
+		}
+		return nil
+	}
+	local, err := ParseSocketAddr(cfg.Socket)
+	if err != nil {
+		return serrors.WrapStr("parsing socket", err)
+	}
+	if cfg.SocketRemote == "" {
+		if udp, ok := local.(*net.UDPAddr); ok && !udp.IP.IsLoopback() {
+			return serrors.New("socket_remote required for UDP socket not bound to "+
+				"loopback address", "socket", cfg.Socket)
+		}
+		return nil
+	}
+	remote, err := ParseSocketAddr(cfg.SocketRemote)
+	if err != nil {
+		return serrors.WrapStr("parsing socket_remote", err)
+	}
+	if local.Network() != remote.Network() {
+		return serrors.New("socket and socket_remote must use the same network",
+			"socket", cfg.Socket, "socket_remote", cfg.SocketRemote)
+	}
 	return nil
 }
 
+// ParseSocketAddr parses a socket address of the form udp://host:port or
+// unix:///path. It returns a *net.UDPAddr or a *net.UnixAddr, respectively.
+func ParseSocketAddr(s string) (net.Addr, error) {
+	switch {
+	case strings.HasPrefix(s, "udp://"):
+		a, err := net.ResolveUDPAddr("udp", strings.TrimPrefix(s, "udp://"))
+		if err != nil {
+			return nil, err
+		}
+		return a, nil
+	case strings.HasPrefix(s, "unix://"):
+		path := strings.TrimPrefix(s, "unix://")
+		if path == "" {
+			return nil, serrors.New("empty Unix socket path", "addr", s)
+		}
+		return &net.UnixAddr{Name: path, Net: "unixgram"}, nil
+	default:
+		return nil, serrors.New("unsupported socket address, "+
+			"expected udp://host:port or unix:///path", "addr", s)
+	}
+}
+
 func (cfg *Tunnel) Sample(dst io.Writer, path config.Path, ctx config.CtxMap) {
 	config.WriteString(dst, tunnelSample)
 }
@@ -157,6 +293,72 @@ func (cfg *Tunnel) ConfigName() string {
 	return "tunnel"
 }
 
+// BGP holds the configuration of the BGP session with a router in the local
+// network.
+type BGP struct {
+	config.NoDefaulter
+
+	// Peer is the address of the router. If empty, BGP is disabled.
+	Peer string `toml:"peer,omitempty"`
+	// PeerAS is the AS number of the router.
+	PeerAS uint32 `toml:"peer_as,omitempty"`
+	// LocalAS is the AS number of the gateway.
+	LocalAS uint32 `toml:"local_as,omitempty"`
+	// RouterID is the BGP identifier of the gateway.
+	RouterID net.IP `toml:"router_id,omitempty"`
+	// HoldTime is the hold time proposed to the router.
+	HoldTime util.DurWrap `toml:"hold_time,omitempty"`
+	// NextHopIPv4 is the next hop announced for IPv4 prefixes.
+	NextHopIPv4 net.IP `toml:"next_hop_ipv4,omitempty"`
+	// NextHopIPv6 is the next hop announced for IPv6 prefixes.
+	NextHopIPv6 net.IP `toml:"next_hop_ipv6,omitempty"`
+	// Export enables announcing the prefixes learned from remote gateways to
+	// the router.
+	Export bool `toml:"export,omitempty"`
+	// Import enables advertising the prefixes learned from the router to
+	// remote gateways.
+	Import bool `toml:"import,omitempty"`
+}
+
+func (cfg *BGP) Validate() error {
+	if cfg.HoldTime.Duration == 0 {
+		cfg.HoldTime.Duration = DefaultBGPHoldTime
+	}
+	if cfg.Peer == "" {
+		return nil
+	}
+	cfg.Peer = DefaultAddress(cfg.Peer, defaultBGPPort)
+	if cfg.PeerAS == 0 {
+		return serrors.New("peer_as must be set")
+	}
+	if cfg.LocalAS == 0 {
+		return serrors.New("local_as must be set")
+	}
+	if cfg.RouterID.To4() == nil {
+		return serrors.New("router_id must be an IPv4 address", "router_id", cfg.RouterID)
+	}
+	if cfg.HoldTime.Duration < 3*time.Second {
+		return serrors.New("hold_time must be at least 3s", "hold_time", cfg.HoldTime)
+	}
+	if cfg.NextHopIPv4 != nil && cfg.NextHopIPv4.To4() == nil {
+		return serrors.New("next_hop_ipv4 must be an IPv4 address",
+			"next_hop_ipv4", cfg.NextHopIPv4)
+	}
+	if cfg.NextHopIPv6 != nil && cfg.NextHopIPv6.To4() != nil {
+		return serrors.New("next_hop_ipv6 must be an IPv6 address",
+			"next_hop_ipv6", cfg.NextHopIPv6)
+	}
+	return nil
+}
+
+func (cfg *BGP) Sample(dst io.Writer, path config.Path, ctx config.CtxMap) {
+	config.WriteString(dst, bgpSample)
+}
+
+func (cfg *BGP) ConfigName() string {
+	return "bgp"
+}
+
 // DefaultAddress determines the default address. If port is not specified, or
 // is zero, it is set to the default port. If the input is garbage, the output
 // is garbage as well.
@@ -39,12 +39,97 @@ func TestConfigSample(t *testing.T) {
 	CheckConfig(t, &cfg)
 }
 
+func TestTunnelValidateSocket(t *testing.T) {
+	testCases := map[string]struct {
+		Socket       string
+		SocketRemote string
+		Valid        bool
+	}{
+		"no socket": {Valid: true},
+		"udp":       {Socket: "udp://127.0.0.1:30100", Valid: true},
+		"unix":      {Socket: "unix:///run/gateway.sock", Valid: true},
+		"udp with remote": {
+			Socket:       "udp://127.0.0.1:30100",
+			SocketRemote: "udp://127.0.0.1:30101",
+			Valid:        true,
+		},
+		"udp without remote on non-loopback address": {Socket: "udp://192.0.2.1:30100"},
+		"udp with remote on non-loopback address": {
+			Socket:       "udp://192.0.2.1:30100",
+			SocketRemote: "udp://192.0.2.2:30101",
+			Valid:        true,
+		},
+		"remote without socket": {SocketRemote: "udp://127.0.0.1:30101"},
+		"unsupported scheme":    {Socket: "tcp://127.0.0.1:30100"},
+		"empty unix path":       {Socket: "unix://"},
+		"mixed networks": {
+			Socket:       "udp://127.0.0.1:30100",
+			SocketRemote: "unix:///run/stack.sock",
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			cfg := config.Tunnel{Socket: tc.Socket, SocketRemote: tc.SocketRemote}
+			err := cfg.Validate()
+			if tc.Valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestGatewayValidatePrefixOriginValidation(t *testing.T) {
+	const (
+		trcDir  = "/etc/scion/certs"
+		holders = "/etc/scion/prefix_holders.json"
+	)
+	testCases := map[string]struct {
+		Mode    string
+		TRCDir  string
+		Holders string
+		Valid   bool
+	}{
+		"default":  {Valid: true},
+		"disabled": {Mode: "disabled", Valid: true},
+		"deprioritize": {
+			Mode:    "deprioritize",
+			TRCDir:  trcDir,
+			Holders: holders,
+			Valid:   true,
+		},
+		"reject":                 {Mode: "reject", TRCDir: trcDir, Holders: holders, Valid: true},
+		"reject without TRCs":    {Mode: "reject", Holders: holders},
+		"reject without holders": {Mode: "reject", TRCDir: trcDir},
+		"unknown mode":           {Mode: "strict", TRCDir: trcDir, Holders: holders},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			cfg := config.Gateway{
+				PrefixOriginValidation: tc.Mode,
+				TRCDir:                 tc.TRCDir,
+				PrefixHolders:          tc.Holders,
+			}
+			err := cfg.Validate()
+			if tc.Valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
 func InitConfig(cfg *config.Config) {
 	envtest.InitTest(nil, &cfg.Metrics, nil, &cfg.Daemon)
 	logtest.InitTestLogging(&cfg.Logging)
 	apitest.InitConfig(&cfg.API)
 	configtest.InitGateway(&cfg.Gateway)
 	configtest.InitTunnel(&cfg.Tunnel)
+	configtest.InitBGP(&cfg.BGP)
 }
 
 func CheckConfig(t *testing.T, cfg *config.Config) {
@@ -53,4 +138,5 @@ func CheckConfig(t *testing.T, cfg *config.Config) {
 	configtest.CheckGateway(t, &cfg.Gateway)
 	apitest.CheckConfig(t, &cfg.API)
 	configtest.CheckTunnel(t, &cfg.Tunnel)
+	configtest.CheckBGP(t, &cfg.BGP)
 }
@@ -31,10 +31,30 @@ func CheckGateway(t *testing.T, cfg *config.Gateway) {
 	assert.Equal(t, config.DefaultCtrlAddr, cfg.CtrlAddr)
 	assert.Equal(t, config.DefaultDataAddr, cfg.DataAddr)
 	assert.Equal(t, config.DefaultProbeAddr, cfg.ProbeAddr)
+	assert.Equal(t, config.FrameProtectionDisabled, cfg.FrameProtection)
+	assert.Equal(t, config.PrefixOriginValidationDisabled, cfg.PrefixOriginValidation)
+	assert.Empty(t, cfg.PrefixAttestations)
+	assert.Empty(t, cfg.TRCDir)
+	assert.Empty(t, cfg.PrefixHolders)
 }
 
 func InitTunnel(cfg *config.Tunnel) {}
 
 func CheckTunnel(t *testing.T, cfg *config.Tunnel) {
 	assert.Equal(t, config.DefaultTunnelName, cfg.Name)
+	assert.False(t, cfg.PacketTooBig)
+	assert.False(t, cfg.ClampTCPMSS)
+	assert.Empty(t, cfg.Socket)
+	assert.Empty(t, cfg.SocketRemote)
+}
+
+func InitBGP(cfg *config.BGP) {}
+
+func CheckBGP(t *testing.T, cfg *config.BGP) {
+	assert.Empty(t, cfg.Peer)
+	assert.Equal(t, uint32(64512), cfg.PeerAS)
+	assert.Equal(t, uint32(64513), cfg.LocalAS)
+	assert.Equal(t, config.DefaultBGPHoldTime, cfg.HoldTime.Duration)
+	assert.False(t, cfg.Export)
+	assert.False(t, cfg.Import)
 }
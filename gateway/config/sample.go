@@ -57,6 +57,58 @@ data_addr = ":30056"
 #
 # (default ":30856")
 probe_addr = ":30856"
+
+# The protection of the encapsulated traffic exchanged with remote gateways.
+# Protected frames are authenticated and encrypted with keys derived from DRKey
+# Host-Host keys between the data-plane addresses of the gateways. Thus, DRKey
+# must be enabled in the local and the remote ASes. Support for frame
+# protection is advertised to remote gateways when fetching prefixes.
+#
+#  "disabled" -> frames are not protected.
+#  "enabled"  -> frames are protected if the remote gateway supports it.
+#                Unprotected frames are still accepted. The support is learned
+#                from the unauthenticated prefix exchange, thus an attacker on
+#                the path can downgrade the traffic to unprotected frames.
+#  "required" -> all frames are protected and unprotected frames are discarded.
+#
+# (default "disabled")
+frame_protection = "disabled"
+
+# The validation of the origin of the IP prefixes announced by remote gateways.
+# The origin is validated with the signed prefix attestations advertised by the
+# remote gateways. An attestation is only trusted for the prefixes held by the
+# attested ISD-AS according to prefix_holders_file. A prefix with an invalid
+# origin is covered by a trusted attestation, but none authorizes the
+# announcing ISD-AS to announce it. Prefixes that are not covered by any
+# trusted attestation are accepted.
+#
+#  "disabled"     -> the origin is not validated.
+#  "deprioritize" -> prefixes with an invalid origin are only used for the
+#                    address space that is not validly announced by another
+#                    ISD-AS.
+#  "reject"       -> prefixes with an invalid origin are discarded.
+#
+# (default "disabled")
+prefix_origin_validation = "disabled"
+
+# The file with the signed prefix attestations of the local ISD-AS in PEM
+# format. The attestations are advertised to remote gateways when they fetch
+# prefixes. The file is read again when it is modified. If not set, no
+# attestations are advertised.
+# (default "")
+prefix_attestations_file = ""
+
+# The directory with the TRCs that are used to verify the prefix attestations.
+# Must be set if the prefix origin validation is enabled.
+# (default "")
+trc_dir = ""
+
+# The JSON file that maps each ISD-AS to the IP prefixes it holds, e.g.,
+# {"1-ff00:0:110": ["10.1.0.0/16", "2001:db8::/32"]}. The attestations of an
+# ISD-AS are only trusted for the prefixes it holds. Must be set if the prefix
+# origin validation is enabled.
+# (default "")
+prefix_holders_file = ""
 `
 
 const tunnelSample = `
@@ -68,4 +120,56 @@ src_ipv4 = "192.0.2.100"
 # Source hint to put to put into the routing table for IPv6 routes.
 # (default "")
 src_ipv6 = "2001:db8::2:1"
+# Send ICMP Packet Too Big (ICMPv6) or Fragmentation Needed (ICMP) messages to
+# the local senders of packets that exceed the tunnel MTU, i.e., that do not fit
+# into a single frame on the path to the remote gateway. This only applies to
+# IPv4 packets with the Don't Fragment flag and IPv6 packets. The packets are
+# dropped. If disabled, such packets are split across multiple frames.
+# (default false)
+packet_too_big = false
+# Clamp the maximum segment size option of outgoing TCP SYN packets to the
+# tunnel MTU. (default false)
+clamp_tcp_mss = false
+# Local address of a datagram socket that is used instead of the TUN device,
+# either "udp://host:port" or "unix:///path". Each datagram carries one IP
+# packet. This allows to run the gateway without privileges, e.g., with a
+# userspace TCP/IP stack on the other end of the socket. No routes are
+# installed in this mode. (default "")
+socket = ""
+# Address to which the IP packets are sent if socket is set, using the same
+# scheme as socket. If empty, the packets are sent to the address from which
+# the last packet was received. It may only be empty for Unix sockets and for
+# UDP sockets bound to a loopback address. (default "")
+socket_remote = ""
+`
+
+const bgpSample = `
+# The address of the router the gateway establishes a BGP session with. If the
+# port is empty, or zero, the default port 179 is used. If empty, BGP is
+# disabled. (default "")
+peer = ""
+# The AS number of the router.
+peer_as = 64512
+# The AS number of the gateway. If it is equal to peer_as, the session is an
+# internal BGP session.
+local_as = 64513
+# The BGP identifier of the gateway. It must be an IPv4 address.
+router_id = "192.0.2.100"
+# The hold time proposed to the router. (default "90s")
+hold_time = "90s"
+# The next hop announced for IPv4 prefixes. If not set, the local address of
+# the BGP session is used if it is an IPv4 address. (default "")
+next_hop_ipv4 = "192.0.2.100"
+# The next hop announced for IPv6 prefixes. If not set, the local address of
+# the BGP session is used if it is an IPv6 address. (default "")
+next_hop_ipv6 = "2001:db8::2:1"
+# Announce the prefixes learned from remote gateways, after filtering with
+# the accept and reject rules of the routing policy, to the router.
+# (default false)
+export = false
+# Advertise the prefixes learned from the router to remote gateways. If set,
+# the advertise rules of the routing policy select which of the learned
+# prefixes are advertised, instead of advertising their networks directly.
+# (default false)
+import = false
 `
@@ -154,6 +154,73 @@ func (e *Engine) DiagnosticsWrite(w io.Writer) {
 	}
 }
 
+// Sessions returns the sessions of the engine, sorted by ID.
+func (e *Engine) Sessions() []SessionInfo {
+	e.stateMtx.RLock()
+	defer e.stateMtx.RUnlock()
+
+	infos := make([]SessionInfo, 0, len(e.SessionConfigs))
+	for i, config := range e.SessionConfigs {
+		info := SessionInfo{
+			ID:             config.ID,
+			PolicyID:       config.PolicyID,
+			RemoteIA:       config.IA,
+			Gateway:        config.Gateway,
+			TrafficMatcher: config.TrafficMatcher,
+			LoadSharing:    config.LoadSharing,
+		}
+		// The workers are only available once the engine is set up.
+		if i < len(e.sessions) {
+			info.Healthy = e.sessionMonitors[i].sessionState().Healthy
+			info.Paths = e.sessions[i].pathStatus()
+		}
+		if reader, ok := e.dataplaneSessions[config.ID].(SessionCounterReader); ok {
+			info.Counters = reader.Counters()
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
+// RoutingEntries returns the routing table entries for the routing chains the
+// engine was created with, together with the sessions currently in use.
+func (e *Engine) RoutingEntries(chains []*RoutingChain) []RoutingEntry {
+	e.stateMtx.RLock()
+	defer e.stateMtx.RUnlock()
+
+	var entries []RoutingEntry
+	for _, chain := range chains {
+		for _, tm := range chain.TrafficMatchers {
+			entry := RoutingEntry{
+				RemoteIA:       chain.RemoteIA,
+				Prefixes:       chain.Prefixes,
+				TrafficMatcher: tm.Matcher,
+			}
+			if e.router != nil {
+				entry.Sessions = e.router.activeSessions(tm.ID)
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// SwitchPath forces the session to switch away from the paths it currently
+// uses for the given duration. It returns ErrSessionNotFound if the engine
+// has no session with the ID.
+func (e *Engine) SwitchPath(sessionID uint8, duration time.Duration) error {
+	e.stateMtx.RLock()
+	defer e.stateMtx.RUnlock()
+
+	for _, s := range e.sessions {
+		if s.ID == sessionID {
+			return s.SwitchPath(duration)
+		}
+	}
+	return serrors.WithCtx(ErrSessionNotFound, "session_id", sessionID)
+}
+
 // Status prints the status page to the writer.
 func (e *Engine) Status(w io.Writer) {
 	e.stateMtx.RLock()
@@ -323,6 +390,8 @@ func (e *Engine) initWorkers(ctx context.Context) error {
 			config.PolicyID,
 			config.IA,
 			config.Gateway.Data,
+			config.Gateway.FrameProtection,
+			config.QoS,
 		)
 		remoteIA := config.IA
 		pathMonitorRegistration := e.PathMonitor.Register(
@@ -388,6 +457,7 @@ func (e *Engine) initWorkers(ctx context.Context) error {
 			PathMonitorRegistration: pathMonitorRegistration,
 			PathMonitorPollInterval: 250 * time.Millisecond,
 			DataplaneSession:        dataplaneSession,
+			LoadSharing:             config.LoadSharing,
 			Metrics: SessionMetrics{
 				metrics.CounterWith(e.Metrics.SessionMetrics.PathChanges, labels...),
 			},
@@ -413,12 +483,18 @@ func (e *Engine) initWorkers(ctx context.Context) error {
 	for k, v := range e.dataplaneSessions {
 		writers[k] = v
 	}
+	weights := make(map[uint8]SessionWeigher, len(e.sessions))
+	for _, s := range e.sessions {
+		weights[s.ID] = s
+	}
 	e.router = &Router{
 		RoutingTable:        e.RoutingTable,
 		RoutingTableIndices: e.RoutingTableIndices,
 		DataplaneSessions:   writers,
 		Events:              e.eventNotifications,
 		Metrics:             e.Metrics.RouterMetrics,
+		LoadSharing:         e.loadSharingIndices(),
+		SessionWeights:      weights,
 	}
 	e.workerBase.WG.Add(1)
 	go func() {
@@ -432,6 +508,26 @@ func (e *Engine) initWorkers(ctx context.Context) error {
 	return nil
 }
 
+// loadSharingIndices returns the routing table indices for which all sessions
+// are configured for load sharing.
+func (e *Engine) loadSharingIndices() map[int]bool {
+	loadSharing := make(map[uint8]bool, len(e.SessionConfigs))
+	for _, config := range e.SessionConfigs {
+		loadSharing[config.ID] = config.LoadSharing
+	}
+	indices := make(map[int]bool)
+	for rtID, sessIDs := range e.RoutingTableIndices {
+		shared := len(sessIDs) > 0
+		for _, sessID := range sessIDs {
+			shared = shared && loadSharing[sessID]
+		}
+		if shared {
+			indices[rtID] = true
+		}
+	}
+	return indices
+}
+
 // Close stops all internal goroutines and waits for them to finish.
 func (e *Engine) Close(ctx context.Context) error {
 	return e.workerBase.CloseWrapper(ctx, e.close)
@@ -493,10 +589,30 @@ type PktWriter interface {
 	Write(packet gopacket.Packet)
 }
 
+// PktMTUWriter is implemented by packet writers that know the tunnel MTU, i.e.,
+// the size of the largest IP packet that fits into a single frame on the path
+// the packet is sent on.
+type PktMTUWriter interface {
+	PktWriter
+	// MinMTU returns the smallest tunnel MTU of the paths the packets are sent
+	// on. Packets that do not exceed it fit into a single frame on any path. It
+	// returns 0 if the MTU is not known.
+	MinMTU() int
+	// WriteWithMTU selects the path for the packet, calls check with the
+	// tunnel MTU of that path, and writes the packet on the same path if check
+	// returns true. The MTU passed to check is 0 if it is not known. The
+	// writer may hold locks while calling check, so check must not block.
+	WriteWithMTU(packet gopacket.Packet, check func(mtu int) bool)
+}
+
 // DataplaneSessionFactory is used to construct a data-plane session with a specific ID towards a
 // remote.
 type DataplaneSessionFactory interface {
-	New(sessID uint8, policyID int, remoteIA addr.IA, remoteAddr net.Addr) DataplaneSession
+	// New creates a data-plane session. The frameProtection flag indicates
+	// whether the remote gateway supports protected frames. The QoS policy
+	// classifies the packets into egress queues.
+	New(sessID uint8, policyID int, remoteIA addr.IA, remoteAddr net.Addr,
+		frameProtection bool, qos QoSPolicy) DataplaneSession
 }
 
 // PathMonitor is used to construct registrations for path discovery.
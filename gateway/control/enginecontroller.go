@@ -106,6 +106,9 @@ type EngineController struct {
 	// startup before the first configuration update arrives), it means no forwarding is currently
 	// in effect.
 	engine Worker
+	// routingChains are the routing chains of the routing table used by the
+	// current engine.
+	routingChains []*RoutingChain
 
 	workerBase worker.Base
 }
@@ -134,6 +137,45 @@ func (c *EngineController) Status(w io.Writer) {
 	}
 }
 
+// engineState is implemented by engines that expose their state.
+type engineState interface {
+	Sessions() []SessionInfo
+	RoutingEntries([]*RoutingChain) []RoutingEntry
+	SwitchPath(sessionID uint8, duration time.Duration) error
+}
+
+// Sessions returns the sessions of the engine currently in use.
+func (c *EngineController) Sessions() []SessionInfo {
+	c.stateMtx.RLock()
+	defer c.stateMtx.RUnlock()
+	if es, ok := c.engine.(engineState); ok {
+		return es.Sessions()
+	}
+	return nil
+}
+
+// RoutingTable returns the entries of the routing table currently in use.
+func (c *EngineController) RoutingTable() []RoutingEntry {
+	c.stateMtx.RLock()
+	defer c.stateMtx.RUnlock()
+	if es, ok := c.engine.(engineState); ok {
+		return es.RoutingEntries(c.routingChains)
+	}
+	return nil
+}
+
+// SwitchPath forces the session to switch away from the paths it currently
+// uses for the given duration. It returns ErrSessionNotFound if the session
+// does not exist.
+func (c *EngineController) SwitchPath(sessionID uint8, duration time.Duration) error {
+	c.stateMtx.RLock()
+	defer c.stateMtx.RUnlock()
+	if es, ok := c.engine.(engineState); ok {
+		return es.SwitchPath(sessionID, duration)
+	}
+	return serrors.WithCtx(ErrSessionNotFound, "session_id", sessionID)
+}
+
 func (c *EngineController) validate(ctx context.Context) error {
 	if c.ConfigurationUpdates == nil {
 		return serrors.New("configuration update channel must not be nil")
@@ -194,6 +236,7 @@ func (c *EngineController) run(ctx context.Context) error {
 
 		c.stateMtx.Lock()
 		c.engine = newEngine
+		c.routingChains = rcs
 		c.stateMtx.Unlock()
 	}
 	return nil
@@ -33,10 +33,12 @@ type PrefixFetcher struct {
 	Dialer grpc.Dialer
 }
 
-func (f PrefixFetcher) Prefixes(ctx context.Context, gateway *net.UDPAddr) ([]*net.IPNet, error) {
+func (f PrefixFetcher) Prefixes(ctx context.Context,
+	gateway *net.UDPAddr) (control.Advertisement, error) {
+
 	paths := f.Pather.Get().Paths
 	if len(paths) == 0 {
-		return nil, serrors.New("no path available")
+		return control.Advertisement{}, serrors.New("no path available")
 	}
 	conn, err := f.Dialer.Dial(ctx, &snet.UDPAddr{
 		IA:      f.Remote,
@@ -45,13 +47,13 @@ func (f PrefixFetcher) Prefixes(ctx context.Context, gateway *net.UDPAddr) ([]*n
 		Host:    gateway,
 	})
 	if err != nil {
-		return nil, err
+		return control.Advertisement{}, err
 	}
 	defer conn.Close()
 	client := gpb.NewIPPrefixesServiceClient(conn)
 	rep, err := client.Prefixes(ctx, &gpb.PrefixesRequest{}, grpc.RetryProfile...)
 	if err != nil {
-		return nil, serrors.WrapStr("receiving IP prefixes", err)
+		return control.Advertisement{}, serrors.WrapStr("receiving IP prefixes", err)
 	}
 	prefixes := make([]*net.IPNet, 0, len(rep.Prefixes))
 	for _, pb := range rep.Prefixes {
@@ -64,5 +66,9 @@ func (f PrefixFetcher) Prefixes(ctx context.Context, gateway *net.UDPAddr) ([]*n
 			Mask: mask,
 		})
 	}
-	return prefixes, nil
+	return control.Advertisement{
+		Prefixes:           prefixes,
+		FrameProtection:    rep.FrameProtection,
+		PrefixAttestations: rep.PrefixAttestations,
+	}, nil
 }
@@ -33,6 +33,12 @@ type Advertiser interface {
 	AdvertiseList(from, to addr.IA) ([]netaddr.IPPrefix, error)
 }
 
+// AttestationProvider provides the prefix attestations to advertise.
+type AttestationProvider interface {
+	// PrefixAttestations returns the raw signed prefix attestations.
+	PrefixAttestations() [][]byte
+}
+
 // IPPrefixServer serves IP prefix requests.
 type IPPrefixServer struct {
 	// LocalIA is the IA of the local AS.
@@ -42,6 +48,12 @@ type IPPrefixServer struct {
 	// PrefixesAdvertised reports the number of IP prefixes advertised. If nil, no  metrics are
 	// reported.
 	PrefixesAdvertised metrics.Gauge
+	// FrameProtection indicates that the local gateway supports protected
+	// data-plane frames.
+	FrameProtection bool
+	// Attestations provides the prefix attestations that are advertised
+	// together with the prefixes. If nil, no attestations are advertised.
+	Attestations AttestationProvider
 }
 
 func (s IPPrefixServer) Prefixes(ctx context.Context,
@@ -72,8 +84,14 @@ func (s IPPrefixServer) Prefixes(ctx context.Context,
 			Mask:   uint32(prefix.Bits()),
 		})
 	}
+	var attestations [][]byte
+	if s.Attestations != nil {
+		attestations = s.Attestations.PrefixAttestations()
+	}
 	return &gpb.PrefixesResponse{
-		Prefixes: pb,
+		Prefixes:           pb,
+		FrameProtection:    s.FrameProtection,
+		PrefixAttestations: attestations,
 	}, nil
 }
 
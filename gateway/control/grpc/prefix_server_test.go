@@ -37,10 +37,12 @@ func TestIPPrefixServerPrefixes(t *testing.T) {
 	remote := xtest.MustParseIA("1-ff00:0:111")
 
 	testCases := map[string]struct {
-		Advertiser   func(t *testing.T, ctrl *gomock.Controller) grpc.Advertiser
-		Request      func() (context.Context, *gpb.PrefixesRequest)
-		ErrAssertion assert.ErrorAssertionFunc
-		Expected     []*net.IPNet
+		Advertiser      func(t *testing.T, ctrl *gomock.Controller) grpc.Advertiser
+		Request         func() (context.Context, *gpb.PrefixesRequest)
+		FrameProtection bool
+		Attestations    [][]byte
+		ErrAssertion    assert.ErrorAssertionFunc
+		Expected        []*net.IPNet
 	}{
 		"valid": {
 			Advertiser: func(t *testing.T, ctrl *gomock.Controller) grpc.Advertiser {
@@ -58,6 +60,40 @@ func TestIPPrefixServerPrefixes(t *testing.T) {
 			Expected:     networksList(t, "127.0.0.0/24,127.0.1.0/24,::/64"),
 			ErrAssertion: assert.NoError,
 		},
+		"frame protection": {
+			Advertiser: func(t *testing.T, ctrl *gomock.Controller) grpc.Advertiser {
+				a := mock_grpc.NewMockAdvertiser(ctrl)
+				a.EXPECT().AdvertiseList(local, remote).Return(
+					xtest.MustParseIPPrefixes(t, "127.0.0.0/24"), nil)
+				return a
+			},
+			Request: func() (context.Context, *gpb.PrefixesRequest) {
+				ctx := peer.NewContext(context.Background(),
+					&peer.Peer{Addr: &snet.UDPAddr{IA: remote}},
+				)
+				return ctx, &gpb.PrefixesRequest{}
+			},
+			FrameProtection: true,
+			Expected:        networksList(t, "127.0.0.0/24"),
+			ErrAssertion:    assert.NoError,
+		},
+		"attestations": {
+			Advertiser: func(t *testing.T, ctrl *gomock.Controller) grpc.Advertiser {
+				a := mock_grpc.NewMockAdvertiser(ctrl)
+				a.EXPECT().AdvertiseList(local, remote).Return(
+					xtest.MustParseIPPrefixes(t, "127.0.0.0/24"), nil)
+				return a
+			},
+			Request: func() (context.Context, *gpb.PrefixesRequest) {
+				ctx := peer.NewContext(context.Background(),
+					&peer.Peer{Addr: &snet.UDPAddr{IA: remote}},
+				)
+				return ctx, &gpb.PrefixesRequest{}
+			},
+			Attestations: [][]byte{[]byte("attestation1"), []byte("attestation2")},
+			Expected:     networksList(t, "127.0.0.0/24"),
+			ErrAssertion: assert.NoError,
+		},
 		"unknown": {
 			Advertiser: func(t *testing.T, ctrl *gomock.Controller) grpc.Advertiser {
 				a := mock_grpc.NewMockAdvertiser(ctrl)
@@ -100,14 +136,18 @@ func TestIPPrefixServerPrefixes(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			s := grpc.IPPrefixServer{
-				LocalIA:    local,
-				Advertiser: tc.Advertiser(t, ctrl),
+				LocalIA:         local,
+				Advertiser:      tc.Advertiser(t, ctrl),
+				FrameProtection: tc.FrameProtection,
+				Attestations:    staticAttestations(tc.Attestations),
 			}
 			rep, err := s.Prefixes(tc.Request())
 			tc.ErrAssertion(t, err)
 			if err != nil {
 				return
 			}
+			assert.Equal(t, tc.FrameProtection, rep.FrameProtection)
+			assert.Equal(t, tc.Attestations, rep.PrefixAttestations)
 			var got []*net.IPNet
 			for _, pb := range rep.Prefixes {
 				prefix := &net.IPNet{
@@ -121,6 +161,12 @@ func TestIPPrefixServerPrefixes(t *testing.T) {
 	}
 }
 
+type staticAttestations [][]byte
+
+func (a staticAttestations) PrefixAttestations() [][]byte {
+	return a
+}
+
 func networksList(t *testing.T, networks string) []*net.IPNet {
 	var prefixes []*net.IPNet
 	for _, network := range strings.Split(networks, ",") {
@@ -0,0 +1,245 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package control
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc64"
+	"math"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+// weightResolution is the number of levels the largest weight is quantized
+// to.
+const weightResolution = 1000
+
+var (
+	crcTable = crc64.MakeTable(crc64.ECMA)
+)
+
+// FlowShares maps packet flows to one of several weighted targets. The flow of
+// a packet is identified by its quintuple, so all packets of a flow are mapped
+// to the same target. The share of flows mapped to a target is proportional to
+// its weight.
+//
+// The flows are mapped with weighted rendezvous hashing: every target scores
+// the flow based on the hash of the flow and the target key, and the target
+// with the highest score is selected. Thus, if the weight of a target changes
+// or a target is added or removed, only the flows that must move to restore the
+// proportions change their target.
+type FlowShares struct {
+	// keys contains the hashed keys of the targets.
+	keys []uint64
+	// weights contains the quantized weights of the targets.
+	weights []float64
+}
+
+// NewFlowShares creates the flow shares for targets with the given weights.
+// The targets are identified by their index. Targets with a weight <= 0 are
+// never selected, unless no target has a positive weight in which case all
+// targets are selected with the same probability.
+func NewFlowShares(weights []float64) FlowShares {
+	return NewKeyedFlowShares(nil, weights)
+}
+
+// NewKeyedFlowShares creates the flow shares like NewFlowShares, but the
+// targets are identified by the keys. A flow keeps its target as long as the
+// key and the weight of the target do not change, independent of the position
+// of the target. If keys is nil, the targets are identified by their index.
+// Otherwise, keys must have the same length as weights.
+func NewKeyedFlowShares(keys []string, weights []float64) FlowShares {
+	var max float64
+	for _, w := range weights {
+		if w > max && !math.IsInf(w, 1) {
+			max = w
+		}
+	}
+	shares := FlowShares{
+		keys:    make([]uint64, len(weights)),
+		weights: make([]float64, len(weights)),
+	}
+	seen := make(map[uint64]bool, len(weights))
+	for i, w := range weights {
+		var key uint64
+		if keys == nil {
+			key = mix(uint64(i))
+		} else {
+			key = crc64.Checksum([]byte(keys[i]), crcTable)
+		}
+		// Targets with the same key would score all flows equally, so the
+		// duplicates are told apart by their index.
+		for seen[key] {
+			key = mix(key ^ uint64(i))
+		}
+		seen[key] = true
+		shares.keys[i] = key
+
+		switch {
+		case max == 0:
+			shares.weights[i] = 1
+		case w > 0:
+			shares.weights[i] = math.Max(math.Round(math.Min(w, max)/max*weightResolution), 1)
+		}
+	}
+	return shares
+}
+
+// Select returns the index of the target for the packet. It returns -1 if
+// there are no targets.
+func (s FlowShares) Select(packet gopacket.Packet) int {
+	switch len(s.keys) {
+	case 0:
+		return -1
+	case 1:
+		return 0
+	}
+	flow := crc64.Checksum(extractQuintuple(packet), crcTable)
+	selected, best := -1, 0.0
+	for i, key := range s.keys {
+		if s.weights[i] == 0 {
+			continue
+		}
+		// The hash is mapped to the open interval (0, 1). The score -w/ln(u)
+		// makes the probability to select a target proportional to its
+		// weight.
+		u := (float64(mix(flow^key)>>11) + 0.5) / (1 << 53)
+		score := -s.weights[i] / math.Log(u)
+		if selected < 0 || score > best {
+			selected, best = i, score
+		}
+	}
+	return selected
+}
+
+// mix scrambles the bits of x with the finalizer of splitmix64.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// WeightedPktWriter shares the packets among multiple packet writers with
+// ECMP-style flow hashing. The share of flows written to a packet writer is
+// proportional to its weight.
+type WeightedPktWriter struct {
+	writers []PktWriter
+	shares  FlowShares
+}
+
+// NewWeightedPktWriter creates a packet writer that shares the packets among
+// the writers according to the weights. The keys identify the writers, see
+// NewKeyedFlowShares. The keys, if not nil, and the weights must have the same
+// length as the writers.
+func NewWeightedPktWriter(
+	writers []PktWriter,
+	keys []string,
+	weights []float64,
+) *WeightedPktWriter {
+
+	return &WeightedPktWriter{
+		writers: writers,
+		shares:  NewKeyedFlowShares(keys, weights),
+	}
+}
+
+// Write writes the packet to the packet writer selected for its flow.
+func (w *WeightedPktWriter) Write(packet gopacket.Packet) {
+	if i := w.shares.Select(packet); i >= 0 {
+		w.writers[i].Write(packet)
+	}
+}
+
+// MinMTU returns the smallest tunnel MTU of the packet writers that know
+// their MTU. It returns 0 if none of them does.
+func (w *WeightedPktWriter) MinMTU() int {
+	min := 0
+	for _, writer := range w.writers {
+		mtuWriter, ok := writer.(PktMTUWriter)
+		if !ok {
+			continue
+		}
+		if mtu := mtuWriter.MinMTU(); mtu > 0 && (min == 0 || mtu < min) {
+			min = mtu
+		}
+	}
+	return min
+}
+
+// WriteWithMTU writes the packet to the packet writer selected for its flow if
+// check accepts the tunnel MTU of that packet writer. The MTU passed to check
+// is 0 if the packet writer does not know it.
+func (w *WeightedPktWriter) WriteWithMTU(packet gopacket.Packet, check func(mtu int) bool) {
+	i := w.shares.Select(packet)
+	if i < 0 {
+		return
+	}
+	if mtuWriter, ok := w.writers[i].(PktMTUWriter); ok {
+		mtuWriter.WriteWithMTU(packet, check)
+		return
+	}
+	if check(0) {
+		w.writers[i].Write(packet)
+	}
+}
+
+func extractQuintuple(packet gopacket.Packet) []byte {
+	// Protocol number and addresses.
+	var proto layers.IPProtocol
+	var q []byte
+	switch ip := packet.NetworkLayer().(type) {
+	case *layers.IPv4:
+		q = []byte{byte(ip.Protocol)}
+		q = append(q, ip.SrcIP...)
+		q = append(q, ip.DstIP...)
+		proto = ip.Protocol
+	case *layers.IPv6:
+		q = []byte{byte(ip.NextHeader)}
+		q = append(q, ip.SrcIP...)
+		q = append(q, ip.DstIP...)
+		proto = ip.NextHeader
+	default:
+		panic(fmt.Sprintf("unexpected network layer %T", packet.NetworkLayer()))
+	}
+	// Ports. The transport layer is missing in IP fragments other than the
+	// first one, in which case only the protocol and the addresses are used.
+	var srcPort, dstPort uint16
+	switch proto {
+	case layers.IPProtocolTCP:
+		tcp, ok := packet.Layer(layers.LayerTypeTCP).(*layers.TCP)
+		if !ok {
+			return q
+		}
+		srcPort, dstPort = uint16(tcp.SrcPort), uint16(tcp.DstPort)
+	case layers.IPProtocolUDP:
+		udp, ok := packet.Layer(layers.LayerTypeUDP).(*layers.UDP)
+		if !ok {
+			return q
+		}
+		srcPort, dstPort = uint16(udp.SrcPort), uint16(udp.DstPort)
+	default:
+		return q
+	}
+	pos := len(q)
+	q = append(q, 0, 0, 0, 0)
+	binary.BigEndian.PutUint16(q[pos:pos+2], srcPort)
+	binary.BigEndian.PutUint16(q[pos+2:pos+4], dstPort)
+	return q
+}
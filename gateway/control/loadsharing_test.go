@@ -0,0 +1,204 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package control_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/gateway/control"
+)
+
+// udpFlow creates a UDP packet of the flow identified by the source port.
+func udpFlow(t *testing.T, srcPort uint16) gopacket.Packet {
+	ip := &layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolUDP,
+		SrcIP:    net.IP{192, 168, 0, 1},
+		DstIP:    net.IP{10, 0, 0, 1},
+	}
+	udp := &layers.UDP{SrcPort: layers.UDPPort(srcPort), DstPort: 53}
+	require.NoError(t, udp.SetNetworkLayerForChecksum(ip))
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	require.NoError(t, gopacket.SerializeLayers(buf, opts, ip, udp, gopacket.Payload{1}))
+	return gopacket.NewPacket(buf.Bytes(), layers.LayerTypeIPv4, gopacket.Default)
+}
+
+func TestFlowShares(t *testing.T) {
+	const flows = 2000
+	share := func(weights []float64) []int {
+		shares := control.NewFlowShares(weights)
+		counts := make([]int, len(weights))
+		for port := 0; port < flows; port++ {
+			pkt := udpFlow(t, uint16(10000+port))
+			index := shares.Select(pkt)
+			require.GreaterOrEqual(t, index, 0)
+			// All packets of the same flow are mapped to the same target.
+			require.Equal(t, index, shares.Select(pkt))
+			counts[index]++
+		}
+		return counts
+	}
+
+	t.Run("no targets", func(t *testing.T) {
+		assert.Equal(t, -1, control.NewFlowShares(nil).Select(udpFlow(t, 10000)))
+	})
+	t.Run("weighted", func(t *testing.T) {
+		counts := share([]float64{1, 3})
+		assert.InDelta(t, 0.25, float64(counts[0])/flows, 0.05)
+		assert.InDelta(t, 0.75, float64(counts[1])/flows, 0.05)
+	})
+	t.Run("zero weight", func(t *testing.T) {
+		counts := share([]float64{2, 0, 2})
+		assert.Zero(t, counts[1])
+		assert.InDelta(t, 0.5, float64(counts[0])/flows, 0.05)
+	})
+	t.Run("all zero weights", func(t *testing.T) {
+		counts := share([]float64{0, 0})
+		assert.InDelta(t, 0.5, float64(counts[0])/flows, 0.05)
+	})
+}
+
+func TestFlowSharesConsistency(t *testing.T) {
+	const flows = 2000
+	// moved returns the share of the flows that are mapped to different
+	// targets, which are identified by the keys.
+	moved := func(keysA []string, weightsA []float64, keysB []string, weightsB []float64) float64 {
+		a := control.NewKeyedFlowShares(keysA, weightsA)
+		b := control.NewKeyedFlowShares(keysB, weightsB)
+		count := 0
+		for port := 0; port < flows; port++ {
+			pkt := udpFlow(t, uint16(10000+port))
+			if keysA[a.Select(pkt)] != keysB[b.Select(pkt)] {
+				count++
+			}
+		}
+		return float64(count) / flows
+	}
+
+	t.Run("weight change", func(t *testing.T) {
+		// The share of c grows from 1/4 to 2/5, so 3/20 of the flows move.
+		keys := []string{"a", "b", "c"}
+		share := moved(keys, []float64{1, 2, 1}, keys, []float64{1, 2, 2})
+		assert.InDelta(t, 0.15, share, 0.05)
+	})
+	t.Run("target removed", func(t *testing.T) {
+		// Only the flows of b move.
+		share := moved([]string{"a", "b", "c"}, []float64{1, 1, 1},
+			[]string{"a", "c"}, []float64{1, 1})
+		assert.InDelta(t, 1.0/3, share, 0.05)
+	})
+	t.Run("targets reordered", func(t *testing.T) {
+		share := moved([]string{"a", "b", "c"}, []float64{1, 2, 3},
+			[]string{"c", "a", "b"}, []float64{3, 1, 2})
+		assert.Zero(t, share)
+	})
+}
+
+func TestFlowSharesFragments(t *testing.T) {
+	// fragment creates a non-first fragment of a TCP packet of the given
+	// source, which does not contain the TCP header.
+	fragment := func(t *testing.T, src net.IP) gopacket.Packet {
+		ip := &layers.IPv4{
+			Version:    4,
+			TTL:        64,
+			Protocol:   layers.IPProtocolTCP,
+			FragOffset: 185,
+			SrcIP:      src,
+			DstIP:      net.IP{10, 0, 0, 1},
+		}
+		buf := gopacket.NewSerializeBuffer()
+		opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+		require.NoError(t, gopacket.SerializeLayers(buf, opts, ip, gopacket.Payload{1, 2, 3}))
+		pkt := gopacket.NewPacket(buf.Bytes(), layers.LayerTypeIPv4, gopacket.Default)
+		require.Nil(t, pkt.Layer(layers.LayerTypeTCP))
+		return pkt
+	}
+
+	shares := control.NewFlowShares([]float64{1, 1})
+	counts := make([]int, 2)
+	for i := 0; i < 200; i++ {
+		pkt := fragment(t, net.IP{192, 168, byte(i), 1})
+		index := shares.Select(pkt)
+		require.GreaterOrEqual(t, index, 0)
+		// The fragments are shared based on the addresses.
+		require.Equal(t, index, shares.Select(fragment(t, net.IP{192, 168, byte(i), 1})))
+		counts[index]++
+	}
+	assert.Positive(t, counts[0])
+	assert.Positive(t, counts[1])
+}
+
+type countingPktWriter struct {
+	count int
+}
+
+func (w *countingPktWriter) Write(gopacket.Packet) {
+	w.count++
+}
+
+func TestWeightedPktWriter(t *testing.T) {
+	a, b := &countingPktWriter{}, &countingPktWriter{}
+	w := control.NewWeightedPktWriter([]control.PktWriter{a, b}, nil, []float64{1, 0})
+	for port := 0; port < 100; port++ {
+		w.Write(udpFlow(t, uint16(10000+port)))
+	}
+	assert.Equal(t, 100, a.count)
+	assert.Equal(t, 0, b.count)
+}
+
+type mtuPktWriter struct {
+	countingPktWriter
+	mtu int
+}
+
+func (w *mtuPktWriter) MinMTU() int {
+	return w.mtu
+}
+
+func (w *mtuPktWriter) WriteWithMTU(packet gopacket.Packet, check func(mtu int) bool) {
+	if check(w.mtu) {
+		w.Write(packet)
+	}
+}
+
+func TestWeightedPktWriterMTU(t *testing.T) {
+	a, b := &mtuPktWriter{mtu: 1400}, &mtuPktWriter{mtu: 1200}
+	c := &countingPktWriter{}
+	w := control.NewWeightedPktWriter([]control.PktWriter{a, b, c}, nil, []float64{1, 0, 0})
+	assert.Equal(t, 1200, w.MinMTU())
+
+	var mtus []int
+	check := func(mtu int) bool {
+		mtus = append(mtus, mtu)
+		return mtu >= 1300
+	}
+	w.WriteWithMTU(udpFlow(t, 10000), check)
+	assert.Equal(t, []int{1400}, mtus)
+	assert.Equal(t, 1, a.count)
+
+	w = control.NewWeightedPktWriter([]control.PktWriter{c}, nil, []float64{1})
+	assert.Equal(t, 0, w.MinMTU())
+	w.WriteWithMTU(udpFlow(t, 10000), check)
+	assert.Equal(t, []int{1400, 0}, mtus)
+	assert.Equal(t, 0, c.count)
+}
@@ -9,6 +9,7 @@ import (
 	io "io"
 	net "net"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	gopacket "github.com/google/gopacket"
@@ -385,6 +386,18 @@ func (m *MockPathMonitorRegistration) EXPECT() *MockPathMonitorRegistrationMockR
 	return m.recorder
 }
 
+// Avoid mocks base method.
+func (m *MockPathMonitorRegistration) Avoid(arg0 snet.PathFingerprint, arg1 time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Avoid", arg0, arg1)
+}
+
+// Avoid indicates an expected call of Avoid.
+func (mr *MockPathMonitorRegistrationMockRecorder) Avoid(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Avoid", reflect.TypeOf((*MockPathMonitorRegistration)(nil).Avoid), arg0, arg1)
+}
+
 // Close mocks base method.
 func (m *MockPathMonitorRegistration) Close() {
 	m.ctrl.T.Helper()
@@ -524,10 +537,10 @@ func (mr *MockPrefixFetcherMockRecorder) Close() *gomock.Call {
 }
 
 // Prefixes mocks base method.
-func (m *MockPrefixFetcher) Prefixes(arg0 context.Context, arg1 *net.UDPAddr) ([]*net.IPNet, error) {
+func (m *MockPrefixFetcher) Prefixes(arg0 context.Context, arg1 *net.UDPAddr) (control.Advertisement, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Prefixes", arg0, arg1)
-	ret0, _ := ret[0].([]*net.IPNet)
+	ret0, _ := ret[0].(control.Advertisement)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -599,17 +612,17 @@ func (m *MockDataplaneSessionFactory) EXPECT() *MockDataplaneSessionFactoryMockR
 }
 
 // New mocks base method.
-func (m *MockDataplaneSessionFactory) New(arg0 byte, arg1 int, arg2 addr.IA, arg3 net.Addr) control.DataplaneSession {
+func (m *MockDataplaneSessionFactory) New(arg0 byte, arg1 int, arg2 addr.IA, arg3 net.Addr, arg4 bool, arg5 control.QoSPolicy) control.DataplaneSession {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "New", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "New", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(control.DataplaneSession)
 	return ret0
 }
 
 // New indicates an expected call of New.
-func (mr *MockDataplaneSessionFactoryMockRecorder) New(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockDataplaneSessionFactoryMockRecorder) New(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "New", reflect.TypeOf((*MockDataplaneSessionFactory)(nil).New), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "New", reflect.TypeOf((*MockDataplaneSessionFactory)(nil).New), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // MockPktWriter is a mock of PktWriter interface.
@@ -647,6 +660,67 @@ func (mr *MockPktWriterMockRecorder) Write(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockPktWriter)(nil).Write), arg0)
 }
 
+// MockPktMTUWriter is a mock of PktMTUWriter interface.
+type MockPktMTUWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockPktMTUWriterMockRecorder
+}
+
+// MockPktMTUWriterMockRecorder is the mock recorder for MockPktMTUWriter.
+type MockPktMTUWriterMockRecorder struct {
+	mock *MockPktMTUWriter
+}
+
+// NewMockPktMTUWriter creates a new mock instance.
+func NewMockPktMTUWriter(ctrl *gomock.Controller) *MockPktMTUWriter {
+	mock := &MockPktMTUWriter{ctrl: ctrl}
+	mock.recorder = &MockPktMTUWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPktMTUWriter) EXPECT() *MockPktMTUWriterMockRecorder {
+	return m.recorder
+}
+
+// MinMTU mocks base method.
+func (m *MockPktMTUWriter) MinMTU() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MinMTU")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// MinMTU indicates an expected call of MinMTU.
+func (mr *MockPktMTUWriterMockRecorder) MinMTU() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MinMTU", reflect.TypeOf((*MockPktMTUWriter)(nil).MinMTU))
+}
+
+// Write mocks base method.
+func (m *MockPktMTUWriter) Write(arg0 gopacket.Packet) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Write", arg0)
+}
+
+// Write indicates an expected call of Write.
+func (mr *MockPktMTUWriterMockRecorder) Write(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockPktMTUWriter)(nil).Write), arg0)
+}
+
+// WriteWithMTU mocks base method.
+func (m *MockPktMTUWriter) WriteWithMTU(arg0 gopacket.Packet, arg1 func(int) bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "WriteWithMTU", arg0, arg1)
+}
+
+// WriteWithMTU indicates an expected call of WriteWithMTU.
+func (mr *MockPktMTUWriterMockRecorder) WriteWithMTU(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteWithMTU", reflect.TypeOf((*MockPktMTUWriter)(nil).WriteWithMTU), arg0, arg1)
+}
+
 // MockWorker is a mock of Worker interface.
 type MockWorker struct {
 	ctrl     *gomock.Controller
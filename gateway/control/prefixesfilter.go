@@ -33,10 +33,14 @@ type RoutingPolicyProvider interface {
 type PrefixesFilterMetrics struct {
 	PrefixesAccepted metrics.Gauge
 	PrefixesRejected metrics.Gauge
+	// PrefixesOriginInvalid reports the number of prefixes with an invalid
+	// origin.
+	PrefixesOriginInvalid metrics.Gauge
 }
 
 // PrefixesFilter is a prefix consumer that only forwards calls that are
-// accepted by the current routing policy.
+// accepted by the current routing policy and, if configured, pass the origin
+// validation.
 type PrefixesFilter struct {
 	// LocalIA is that IA this filter is running in. It is used as from value in
 	// the routing policy check.
@@ -46,6 +50,10 @@ type PrefixesFilter struct {
 	Consumer PrefixConsumer
 	// PolicyProvider is the provider of routing policies, must not be nil.
 	PolicyProvider RoutingPolicyProvider
+	// OriginValidator validates the origin of the prefixes with the prefix
+	// attestations advertised by the remote gateways. If nil, the origin is
+	// not validated.
+	OriginValidator *PrefixOriginValidator
 	// Metrics can be used to report information about accepted and rejected IP prefixes. If not
 	// initialized, no metrics will be reported.
 	Metrics PrefixesFilterMetrics
@@ -62,6 +70,18 @@ func (f PrefixesFilter) Prefixes(
 	if rp == nil {
 		return nil
 	}
+	if f.OriginValidator != nil {
+		var invalid int
+		var err error
+		prefixes, invalid, err = f.OriginValidator.Filter(remote, gateway, prefixes)
+		if err != nil {
+			return serrors.WrapStr("validating prefix origins", err)
+		}
+		metrics.GaugeSet(metrics.GaugeWith(f.Metrics.PrefixesOriginInvalid,
+			"remote_isd_as", remote.String()), float64(invalid))
+	}
+	// The attestations are only relevant for the origin validation.
+	gateway.PrefixAttestations = nil
 	var sb netaddr.IPSetBuilder
 	allowedCount := 0
 	rejectedCount := 0
@@ -0,0 +1,411 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package control
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"inet.af/netaddr"
+
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/log"
+	"github.com/scionproto/scion/pkg/private/serrors"
+)
+
+const (
+	// maxAttestationsPerGateway is the maximum number of attestations that are
+	// considered per remote gateway. Further attestations are ignored.
+	maxAttestationsPerGateway = 16
+	// defaultOriginExpiryInterval is the default time after which the state of
+	// a remote gateway that did not announce its prefixes anymore is dropped.
+	defaultOriginExpiryInterval = 10 * time.Minute
+)
+
+// PrefixAttestation authorizes an ISD-AS to announce IP prefixes. It is
+// signed by the ISD-AS itself, similar to a route origin authorization in the
+// RPKI. Since the ISD-AS can attest any prefix, the attestation is only taken
+// into account for the prefixes that the ISD-AS holds according to the local
+// PrefixHolders.
+type PrefixAttestation struct {
+	// IA is the ISD-AS that is authorized to announce the prefixes.
+	IA addr.IA
+	// Prefixes are the authorized prefixes.
+	Prefixes []AttestedPrefix
+	// NotAfter is the time after which the attestation is no longer valid.
+	NotAfter time.Time
+}
+
+// AttestedPrefix is an IP prefix in a prefix attestation.
+type AttestedPrefix struct {
+	// Prefix is the authorized IP prefix.
+	Prefix netaddr.IPPrefix
+	// MaxLength is the maximum length of the announced prefixes that are
+	// covered by Prefix. If 0, only Prefix itself may be announced.
+	MaxLength uint8
+}
+
+// Validate checks that the attestation is well-formed.
+func (a PrefixAttestation) Validate() error {
+	if a.IA.IsWildcard() {
+		return serrors.New("ISD-AS must not contain wildcard", "isd_as", a.IA)
+	}
+	if len(a.Prefixes) == 0 {
+		return serrors.New("no prefixes")
+	}
+	for _, p := range a.Prefixes {
+		if !p.Prefix.IsValid() || p.Prefix.Masked() != p.Prefix {
+			return serrors.New("invalid prefix", "prefix", p.Prefix)
+		}
+		if p.MaxLength != 0 && (p.MaxLength < p.Prefix.Bits() ||
+			p.MaxLength > p.Prefix.IP().BitLen()) {
+
+			return serrors.New("invalid max length", "prefix", p.Prefix,
+				"max_length", p.MaxLength)
+		}
+	}
+	if a.NotAfter.IsZero() {
+		return serrors.New("expiration time not set")
+	}
+	return nil
+}
+
+// PrefixHolders maps each ISD-AS to the IP prefixes it holds, i.e., the
+// prefixes for which its attestations are trusted. It is configured locally and
+// thus anchors the origin validation in the knowledge of the operator rather
+// than in the claims of the announcing ISD-ASes.
+type PrefixHolders map[addr.IA][]netaddr.IPPrefix
+
+// restrict returns the attestation restricted to the prefixes held by the
+// attested ISD-AS. Attested prefixes that are only partially held are
+// restricted to the held prefixes they cover.
+func (h PrefixHolders) restrict(a PrefixAttestation) PrefixAttestation {
+	var prefixes []AttestedPrefix
+	for _, p := range a.Prefixes {
+		maxLength := p.MaxLength
+		if maxLength == 0 {
+			maxLength = p.Prefix.Bits()
+		}
+		for _, held := range h[a.IA] {
+			if held.Bits() <= p.Prefix.Bits() && held.Contains(p.Prefix.IP()) {
+				// The attested prefix is held entirely.
+				prefixes = append(prefixes, p)
+				break
+			}
+			if p.Prefix.Bits() < held.Bits() && p.Prefix.Contains(held.IP()) &&
+				held.Bits() <= maxLength {
+
+				prefixes = append(prefixes, AttestedPrefix{Prefix: held, MaxLength: maxLength})
+			}
+		}
+	}
+	a.Prefixes = prefixes
+	return a
+}
+
+// PrefixAttestationVerifier verifies signed prefix attestations.
+type PrefixAttestationVerifier interface {
+	// Verify verifies the signature of the raw attestation and returns the
+	// attestation if it is valid.
+	Verify(ctx context.Context, raw []byte) (PrefixAttestation, error)
+}
+
+// PrefixOriginState is the result of the origin validation of an announced
+// prefix.
+type PrefixOriginState int
+
+const (
+	// PrefixOriginNotFound indicates that the prefix is not covered by any
+	// attestation.
+	PrefixOriginNotFound PrefixOriginState = iota
+	// PrefixOriginValid indicates that an attestation authorizes the announcing
+	// ISD-AS to announce the prefix.
+	PrefixOriginValid
+	// PrefixOriginInvalid indicates that the prefix is covered by an
+	// attestation, but none authorizes the announcing ISD-AS to announce it.
+	PrefixOriginInvalid
+)
+
+func (s PrefixOriginState) String() string {
+	switch s {
+	case PrefixOriginNotFound:
+		return "not_found"
+	case PrefixOriginValid:
+		return "valid"
+	case PrefixOriginInvalid:
+		return "invalid"
+	default:
+		return "unknown"
+	}
+}
+
+// PrefixOriginValidator validates the origin of announced prefixes against the
+// prefix attestations it has learned from the remote gateways. The
+// attestations are collected from all remote gateways, i.e., a remote gateway
+// can also provide attestations of other ISD-ASes. An attestation is only
+// taken into account for the prefixes that the attested ISD-AS holds according
+// to Holders, thus an ISD-AS can not invalidate the announcements of address
+// space it does not hold.
+//
+// Prefixes with an invalid origin are either discarded or deprioritized. A
+// deprioritized prefix is only used for the address space that is not validly
+// announced by another ISD-AS. Prefixes that are not covered by any
+// attestation are accepted.
+type PrefixOriginValidator struct {
+	// Verifier verifies the attestations, must not be nil.
+	Verifier PrefixAttestationVerifier
+	// Holders are the prefixes held by each ISD-AS. Attestations are ignored
+	// for the prefixes that the attested ISD-AS does not hold. If empty, all
+	// prefixes are not found.
+	Holders PrefixHolders
+	// Reject indicates that prefixes with an invalid origin are discarded
+	// instead of deprioritized.
+	Reject bool
+	// ExpiryInterval is the time after which the state of a remote gateway
+	// that did not announce its prefixes anymore is dropped. If zero, this
+	// defaults to 10 minutes.
+	ExpiryInterval time.Duration
+
+	mtx sync.Mutex
+	// gateways is the state of each remote gateway, keyed by the ISD-AS and
+	// the control address of the gateway.
+	gateways map[string]*originGateway
+}
+
+// originGateway is the origin validation state of a remote gateway.
+type originGateway struct {
+	ia addr.IA
+	// attestations are the attestations advertised by the gateway, keyed by
+	// the hash of the raw attestation. The value is nil if the attestation is
+	// invalid or covers no held prefix.
+	attestations map[[sha256.Size]byte]*PrefixAttestation
+	// valid is the validly announced address space of the gateway.
+	valid *netaddr.IPSet
+	// lastUpdated is the time the gateway announced its prefixes the last
+	// time.
+	lastUpdated time.Time
+}
+
+// Filter adds the attestations advertised by the gateway and returns the
+// prefixes that pass the origin validation, together with the number of
+// prefixes with an invalid origin.
+func (v *PrefixOriginValidator) Filter(
+	remote addr.IA,
+	gateway Gateway,
+	prefixes []*net.IPNet,
+) ([]*net.IPNet, int, error) {
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	now := time.Now()
+	v.expire(now)
+	if v.gateways == nil {
+		v.gateways = make(map[string]*originGateway)
+	}
+	key := fmt.Sprintf("%s %s", remote, gateway.Control)
+	g, ok := v.gateways[key]
+	if !ok {
+		g = &originGateway{ia: remote}
+		v.gateways[key] = g
+	}
+	g.attestations = v.addAttestations(g.attestations, gateway.PrefixAttestations)
+	g.lastUpdated = now
+
+	var valid netaddr.IPSetBuilder
+	var result, invalid []*net.IPNet
+	for _, prefix := range prefixes {
+		p, ok := netaddr.FromStdIPNet(prefix)
+		if !ok {
+			return nil, 0, serrors.New("can not convert prefix", "prefix", prefix)
+		}
+		switch v.validate(remote, p, now) {
+		case PrefixOriginValid:
+			valid.AddPrefix(p)
+			result = append(result, prefix)
+		case PrefixOriginInvalid:
+			invalid = append(invalid, prefix)
+		default:
+			result = append(result, prefix)
+		}
+	}
+	validSet, err := valid.IPSet()
+	if err != nil {
+		return nil, 0, serrors.WrapStr("building valid prefixes", err)
+	}
+	g.valid = validSet
+
+	if v.Reject || len(invalid) == 0 {
+		return result, len(invalid), nil
+	}
+	deprioritized, err := v.deprioritize(remote, invalid)
+	if err != nil {
+		return nil, 0, err
+	}
+	return append(result, deprioritized...), len(invalid), nil
+}
+
+// Validate returns the origin state of the prefix announced by the remote
+// ISD-AS.
+func (v *PrefixOriginValidator) Validate(remote addr.IA,
+	prefix netaddr.IPPrefix) PrefixOriginState {
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	now := time.Now()
+	v.expire(now)
+	return v.validate(remote, prefix, now)
+}
+
+func (v *PrefixOriginValidator) validate(remote addr.IA,
+	prefix netaddr.IPPrefix, now time.Time) PrefixOriginState {
+
+	state := PrefixOriginNotFound
+	for _, g := range v.gateways {
+		for _, a := range g.attestations {
+			if a == nil || now.After(a.NotAfter) {
+				continue
+			}
+			for _, p := range a.Prefixes {
+				if p.Prefix.Bits() > prefix.Bits() || !p.Prefix.Contains(prefix.IP()) {
+					continue
+				}
+				maxLength := p.MaxLength
+				if maxLength == 0 {
+					maxLength = p.Prefix.Bits()
+				}
+				if a.IA.Equal(remote) && prefix.Bits() <= maxLength {
+					return PrefixOriginValid
+				}
+				state = PrefixOriginInvalid
+			}
+		}
+	}
+	return state
+}
+
+// deprioritize removes the address space that is validly announced by other
+// ISD-ASes from the prefixes.
+func (v *PrefixOriginValidator) deprioritize(remote addr.IA,
+	prefixes []*net.IPNet) ([]*net.IPNet, error) {
+
+	var sb netaddr.IPSetBuilder
+	for _, prefix := range prefixes {
+		p, _ := netaddr.FromStdIPNet(prefix)
+		sb.AddPrefix(p)
+	}
+	for _, g := range v.gateways {
+		if !g.ia.Equal(remote) && g.valid != nil {
+			sb.RemoveSet(g.valid)
+		}
+	}
+	set, err := sb.IPSet()
+	if err != nil {
+		return nil, serrors.WrapStr("deprioritizing prefixes", err)
+	}
+	var result []*net.IPNet
+	for _, prefix := range set.Prefixes() {
+		result = append(result, prefix.IPNet())
+	}
+	return result, nil
+}
+
+// addAttestations returns the verified attestations of a gateway that
+// advertises the raw attestations. Only the first attestations up to the
+// maximum per gateway are considered. Attestations that have already been
+// verified are not verified again.
+func (v *PrefixOriginValidator) addAttestations(
+	previous map[[sha256.Size]byte]*PrefixAttestation,
+	raws [][]byte,
+) map[[sha256.Size]byte]*PrefixAttestation {
+
+	logger := log.FromCtx(context.Background())
+	if len(raws) > maxAttestationsPerGateway {
+		logger.Debug("Ignoring excess prefix attestations", "count", len(raws),
+			"max", maxAttestationsPerGateway)
+		raws = raws[:maxAttestationsPerGateway]
+	}
+	attestations := make(map[[sha256.Size]byte]*PrefixAttestation, len(raws))
+	for _, raw := range raws {
+		key := sha256.Sum256(raw)
+		if a, ok := previous[key]; ok {
+			attestations[key] = a
+			continue
+		}
+		if a, ok := v.lookup(key); ok {
+			attestations[key] = a
+			continue
+		}
+		attestations[key] = v.verify(raw)
+	}
+	return attestations
+}
+
+// lookup returns the attestation if it has already been verified for any
+// gateway.
+func (v *PrefixOriginValidator) lookup(key [sha256.Size]byte) (*PrefixAttestation, bool) {
+	for _, g := range v.gateways {
+		if a, ok := g.attestations[key]; ok {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+// verify verifies the raw attestation and restricts it to the held prefixes.
+// It returns nil if the attestation is invalid or covers no held prefix.
+func (v *PrefixOriginValidator) verify(raw []byte) *PrefixAttestation {
+	logger := log.FromCtx(context.Background())
+	a, err := v.Verifier.Verify(context.Background(), raw)
+	if err != nil {
+		logger.Debug("Ignoring prefix attestation", "err", err)
+		return nil
+	}
+	restricted := v.Holders.restrict(a)
+	if len(restricted.Prefixes) == 0 {
+		logger.Info("Ignoring prefix attestation without held prefixes", "isd_as", a.IA,
+			"prefixes", fmtAttestedPrefixes(a.Prefixes))
+		return nil
+	}
+	logger.Info("Learned prefix attestation", "isd_as", a.IA,
+		"prefixes", fmtAttestedPrefixes(restricted.Prefixes), "not_after", a.NotAfter)
+	return &restricted
+}
+
+// expire drops the state of the gateways that did not announce their prefixes
+// within the expiry interval.
+func (v *PrefixOriginValidator) expire(now time.Time) {
+	interval := v.ExpiryInterval
+	if interval == 0 {
+		interval = defaultOriginExpiryInterval
+	}
+	for key, g := range v.gateways {
+		if now.Sub(g.lastUpdated) > interval {
+			delete(v.gateways, key)
+		}
+	}
+}
+
+func fmtAttestedPrefixes(prefixes []AttestedPrefix) []string {
+	result := make([]string, 0, len(prefixes))
+	for _, p := range prefixes {
+		result = append(result, p.Prefix.String())
+	}
+	return result
+}
@@ -0,0 +1,310 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package control_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"inet.af/netaddr"
+
+	"github.com/scionproto/scion/gateway/control"
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/private/xtest"
+)
+
+func TestPrefixOriginValidatorValidate(t *testing.T) {
+	v := &control.PrefixOriginValidator{Verifier: testVerifier(t), Holders: testHolders(t)}
+	_, _, err := v.Filter(xtest.MustParseIA("1-ff00:0:111"), testGateway(t, 1,
+		"111", "112", "expired", "garbage", "hijack", "unheld"), nil)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		Remote   string
+		Prefix   string
+		Expected control.PrefixOriginState
+	}{
+		"exact match": {
+			Remote:   "1-ff00:0:111",
+			Prefix:   "10.1.0.0/16",
+			Expected: control.PrefixOriginValid,
+		},
+		"within max length": {
+			Remote:   "1-ff00:0:111",
+			Prefix:   "10.1.2.0/24",
+			Expected: control.PrefixOriginValid,
+		},
+		"longer than max length": {
+			Remote:   "1-ff00:0:111",
+			Prefix:   "10.1.2.0/25",
+			Expected: control.PrefixOriginInvalid,
+		},
+		"shorter than attested": {
+			Remote:   "1-ff00:0:111",
+			Prefix:   "10.0.0.0/8",
+			Expected: control.PrefixOriginNotFound,
+		},
+		"other ISD-AS": {
+			Remote:   "1-ff00:0:112",
+			Prefix:   "10.1.0.0/16",
+			Expected: control.PrefixOriginInvalid,
+		},
+		"no max length": {
+			Remote:   "1-ff00:0:112",
+			Prefix:   "10.2.0.0/24",
+			Expected: control.PrefixOriginInvalid,
+		},
+		"expired attestation": {
+			Remote:   "1-ff00:0:113",
+			Prefix:   "10.3.0.0/16",
+			Expected: control.PrefixOriginNotFound,
+		},
+		"not covered": {
+			Remote:   "1-ff00:0:113",
+			Prefix:   "192.168.0.0/16",
+			Expected: control.PrefixOriginNotFound,
+		},
+		"attestation of unheld prefix": {
+			Remote:   "1-ff00:0:114",
+			Prefix:   "10.4.0.0/16",
+			Expected: control.PrefixOriginNotFound,
+		},
+		"attestation of prefix held by other ISD-AS": {
+			Remote:   "1-ff00:0:666",
+			Prefix:   "10.1.0.0/16",
+			Expected: control.PrefixOriginInvalid,
+		},
+		"attestation restricted to held part": {
+			Remote:   "1-ff00:0:666",
+			Prefix:   "10.66.1.0/24",
+			Expected: control.PrefixOriginValid,
+		},
+		"outside of held part": {
+			Remote:   "1-ff00:0:111",
+			Prefix:   "10.67.0.0/16",
+			Expected: control.PrefixOriginNotFound,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			state := v.Validate(xtest.MustParseIA(tc.Remote),
+				netaddr.MustParseIPPrefix(tc.Prefix))
+			assert.Equal(t, tc.Expected, state)
+		})
+	}
+}
+
+func TestPrefixOriginValidatorFilter(t *testing.T) {
+	ia111 := xtest.MustParseIA("1-ff00:0:111")
+	ia112 := xtest.MustParseIA("1-ff00:0:112")
+	ia666 := xtest.MustParseIA("1-ff00:0:666")
+
+	t.Run("reject", func(t *testing.T) {
+		v := &control.PrefixOriginValidator{
+			Verifier: testVerifier(t),
+			Holders:  testHolders(t),
+			Reject:   true,
+		}
+		prefixes, invalid, err := v.Filter(ia112, testGateway(t, 1, "111", "112"),
+			xtest.MustParseCIDRs(t, "10.2.0.0/16", "10.1.0.0/24", "192.168.0.0/16"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, invalid)
+		assert.Equal(t, xtest.MustParseCIDRs(t, "10.2.0.0/16", "192.168.0.0/16"), prefixes)
+	})
+	t.Run("deprioritize", func(t *testing.T) {
+		v := &control.PrefixOriginValidator{Verifier: testVerifier(t), Holders: testHolders(t)}
+		prefixes, invalid, err := v.Filter(ia111, testGateway(t, 1, "111", "112"),
+			xtest.MustParseCIDRs(t, "10.1.0.0/17"))
+		require.NoError(t, err)
+		assert.Equal(t, 0, invalid)
+		assert.Equal(t, xtest.MustParseCIDRs(t, "10.1.0.0/17"), prefixes)
+
+		// The invalid prefix is only kept for the address space that is not
+		// validly announced by 1-ff00:0:111.
+		prefixes, invalid, err = v.Filter(ia112, testGateway(t, 2),
+			xtest.MustParseCIDRs(t, "10.2.0.0/16", "10.1.0.0/16"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, invalid)
+		assert.Equal(t, xtest.MustParseCIDRs(t, "10.2.0.0/16", "10.1.128.0/17"), prefixes)
+	})
+	t.Run("hijack", func(t *testing.T) {
+		v := &control.PrefixOriginValidator{
+			Verifier: testVerifier(t),
+			Holders:  testHolders(t),
+			Reject:   true,
+		}
+		// The hijacker attests the prefix of 1-ff00:0:111, which it does not
+		// hold. This neither validates its announcement nor invalidates the
+		// announcement of 1-ff00:0:111.
+		prefixes, invalid, err := v.Filter(ia666, testGateway(t, 1, "hijack"),
+			xtest.MustParseCIDRs(t, "10.1.0.0/16"))
+		require.NoError(t, err)
+		assert.Equal(t, 0, invalid)
+		assert.Equal(t, xtest.MustParseCIDRs(t, "10.1.0.0/16"), prefixes)
+		assert.Equal(t, control.PrefixOriginNotFound,
+			v.Validate(ia666, netaddr.MustParseIPPrefix("10.1.0.0/16")))
+
+		prefixes, invalid, err = v.Filter(ia111, testGateway(t, 2),
+			xtest.MustParseCIDRs(t, "10.1.0.0/16"))
+		require.NoError(t, err)
+		assert.Equal(t, 0, invalid)
+		assert.Equal(t, xtest.MustParseCIDRs(t, "10.1.0.0/16"), prefixes)
+	})
+	t.Run("gateways of same ISD-AS", func(t *testing.T) {
+		v := &control.PrefixOriginValidator{Verifier: testVerifier(t), Holders: testHolders(t)}
+		_, _, err := v.Filter(ia111, testGateway(t, 1, "111"),
+			xtest.MustParseCIDRs(t, "10.1.0.0/16"))
+		require.NoError(t, err)
+		// A second gateway of the same ISD-AS does not replace the validly
+		// announced address space of the first one.
+		_, _, err = v.Filter(ia111, testGateway(t, 2), nil)
+		require.NoError(t, err)
+
+		prefixes, invalid, err := v.Filter(ia112, testGateway(t, 3),
+			xtest.MustParseCIDRs(t, "10.1.0.0/16"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, invalid)
+		assert.Empty(t, prefixes)
+	})
+	t.Run("expiry", func(t *testing.T) {
+		v := &control.PrefixOriginValidator{
+			Verifier:       testVerifier(t),
+			Holders:        testHolders(t),
+			ExpiryInterval: 10 * time.Millisecond,
+		}
+		_, _, err := v.Filter(ia111, testGateway(t, 1, "111"),
+			xtest.MustParseCIDRs(t, "10.1.0.0/16"))
+		require.NoError(t, err)
+		assert.Equal(t, control.PrefixOriginInvalid,
+			v.Validate(ia112, netaddr.MustParseIPPrefix("10.1.0.0/16")))
+
+		// The attestations of gateways that went away are dropped.
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, control.PrefixOriginNotFound,
+			v.Validate(ia112, netaddr.MustParseIPPrefix("10.1.0.0/16")))
+	})
+	t.Run("attestations per gateway are capped", func(t *testing.T) {
+		verifier := countingVerifier{fakeVerifier: testVerifier(t)}
+		v := &control.PrefixOriginValidator{Verifier: &verifier, Holders: testHolders(t)}
+		raws := make([]string, 0, 100)
+		for i := 0; i < 100; i++ {
+			raws = append(raws, fmt.Sprint("garbage", i))
+		}
+		gateway := testGateway(t, 1, append(raws, "111")...)
+		_, _, err := v.Filter(ia111, gateway, nil)
+		require.NoError(t, err)
+		assert.Less(t, verifier.count, 100)
+		assert.Equal(t, control.PrefixOriginNotFound,
+			v.Validate(ia112, netaddr.MustParseIPPrefix("10.1.0.0/16")))
+
+		// Attestations that have been verified are not verified again.
+		count := verifier.count
+		_, _, err = v.Filter(ia111, gateway, nil)
+		require.NoError(t, err)
+		assert.Equal(t, count, verifier.count)
+	})
+}
+
+func testGateway(t *testing.T, id int, attestations ...string) control.Gateway {
+	raws := make([][]byte, 0, len(attestations))
+	for _, a := range attestations {
+		raws = append(raws, []byte(a))
+	}
+	return control.Gateway{
+		Control:            &net.UDPAddr{IP: net.IP{192, 0, 2, byte(id)}, Port: 30256},
+		PrefixAttestations: raws,
+	}
+}
+
+func testHolders(t *testing.T) control.PrefixHolders {
+	return control.PrefixHolders{
+		xtest.MustParseIA("1-ff00:0:111"): {netaddr.MustParseIPPrefix("10.1.0.0/16")},
+		xtest.MustParseIA("1-ff00:0:112"): {netaddr.MustParseIPPrefix("10.2.0.0/16")},
+		xtest.MustParseIA("1-ff00:0:113"): {netaddr.MustParseIPPrefix("10.3.0.0/16")},
+		xtest.MustParseIA("1-ff00:0:666"): {netaddr.MustParseIPPrefix("10.66.0.0/16")},
+	}
+}
+
+func testVerifier(t *testing.T) fakeVerifier {
+	return fakeVerifier{
+		"111": {
+			IA: xtest.MustParseIA("1-ff00:0:111"),
+			Prefixes: []control.AttestedPrefix{
+				{Prefix: netaddr.MustParseIPPrefix("10.1.0.0/16"), MaxLength: 24},
+			},
+			NotAfter: time.Now().Add(time.Hour),
+		},
+		"112": {
+			IA: xtest.MustParseIA("1-ff00:0:112"),
+			Prefixes: []control.AttestedPrefix{
+				{Prefix: netaddr.MustParseIPPrefix("10.2.0.0/16")},
+			},
+			NotAfter: time.Now().Add(time.Hour),
+		},
+		"hijack": {
+			IA: xtest.MustParseIA("1-ff00:0:666"),
+			Prefixes: []control.AttestedPrefix{
+				{Prefix: netaddr.MustParseIPPrefix("10.1.0.0/16")},
+				{Prefix: netaddr.MustParseIPPrefix("10.0.0.0/8"), MaxLength: 24},
+			},
+			NotAfter: time.Now().Add(time.Hour),
+		},
+		"unheld": {
+			IA: xtest.MustParseIA("1-ff00:0:114"),
+			Prefixes: []control.AttestedPrefix{
+				{Prefix: netaddr.MustParseIPPrefix("10.4.0.0/16")},
+			},
+			NotAfter: time.Now().Add(time.Hour),
+		},
+		"expired": {
+			IA: xtest.MustParseIA("1-ff00:0:113"),
+			Prefixes: []control.AttestedPrefix{
+				{Prefix: netaddr.MustParseIPPrefix("10.3.0.0/16")},
+			},
+			NotAfter: time.Now().Add(-time.Hour),
+		},
+	}
+}
+
+// fakeVerifier maps raw attestations to the verified attestations.
+type fakeVerifier map[string]control.PrefixAttestation
+
+func (v fakeVerifier) Verify(_ context.Context, raw []byte) (control.PrefixAttestation, error) {
+	a, ok := v[string(raw)]
+	if !ok {
+		return control.PrefixAttestation{}, serrors.New("invalid attestation")
+	}
+	return a, nil
+}
+
+// countingVerifier counts the verified attestations.
+type countingVerifier struct {
+	fakeVerifier
+	count int
+}
+
+func (v *countingVerifier) Verify(ctx context.Context,
+	raw []byte) (control.PrefixAttestation, error) {
+
+	v.count++
+	return v.fakeVerifier.Verify(ctx, raw)
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package control
+
+import (
+	"github.com/scionproto/scion/gateway/pktcls"
+	"github.com/scionproto/scion/pkg/private/serrors"
+)
+
+// DefaultQoSClassName is the name of the implicit QoS class of the packets
+// that do not match any of the configured QoS classes. The default class has
+// priority 0, weight 1 and no shaping rate.
+const DefaultQoSClassName = "default"
+
+// QoSClass is a traffic class with its own egress queue in a session.
+type QoSClass struct {
+	// Name identifies the class, e.g., in the metrics.
+	Name string
+	// Matcher contains the conditions the IP packets must satisfy to belong to
+	// the class.
+	Matcher pktcls.Cond
+	// Priority is the priority of the class. Packets of classes with a higher
+	// priority are always sent before packets of classes with a lower
+	// priority.
+	Priority int
+	// Weight is the share of the bandwidth of the class relative to the other
+	// classes with the same priority. If 0, a weight of 1 is used.
+	Weight int
+	// Rate limits the sending rate of the class, in bits per second. The rate
+	// applies to all paths of the session together. Packets in excess of the
+	// rate are queued, and dropped if the queue is full. If 0, the rate is not
+	// limited.
+	Rate uint64
+}
+
+// QoSPolicy is an ordered list of QoS classes. A packet belongs to the first
+// class it matches, or to the default class if it matches none of them. An
+// empty policy puts all packets into a single FIFO queue.
+type QoSPolicy []QoSClass
+
+// Validate checks that the classes have unique names and a matcher.
+func (p QoSPolicy) Validate() error {
+	names := make(map[string]struct{}, len(p))
+	for i, class := range p {
+		if class.Name == "" {
+			return serrors.New("QoS class without name", "index", i)
+		}
+		if class.Name == DefaultQoSClassName {
+			return serrors.New("QoS class name is reserved", "name", class.Name)
+		}
+		if _, ok := names[class.Name]; ok {
+			return serrors.New("duplicate QoS class", "name", class.Name)
+		}
+		names[class.Name] = struct{}{}
+		if class.Matcher == nil {
+			return serrors.New("QoS class without matcher", "name", class.Name)
+		}
+		if class.Weight < 0 {
+			return serrors.New("negative QoS class weight", "name", class.Name,
+				"weight", class.Weight)
+		}
+	}
+	return nil
+}
+
+// Copy creates a deep copy of the QoS policy.
+func (p QoSPolicy) Copy() QoSPolicy {
+	if p == nil {
+		return nil
+	}
+	copy := make(QoSPolicy, 0, len(p))
+	for _, class := range p {
+		class.Matcher = copyTrafficMatcher(class.Matcher)
+		copy = append(copy, class)
+	}
+	return copy
+}
+
+// Equal returns true if both QoS policies contain the same classes in the same
+// order.
+func (p QoSPolicy) Equal(other QoSPolicy) bool {
+	if len(p) != len(other) {
+		return false
+	}
+	for i := range p {
+		a, b := p[i], other[i]
+		if a.Name != b.Name || a.Priority != b.Priority || a.Weight != b.Weight ||
+			a.Rate != b.Rate || a.Matcher.String() != b.Matcher.String() {
+			return false
+		}
+	}
+	return true
+}
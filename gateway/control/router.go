@@ -19,7 +19,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/google/gopacket/layers"
 
@@ -50,6 +53,23 @@ type RouterMetrics struct {
 	StateChanges func(routingChain int) metrics.Counter
 }
 
+const (
+	// defaultWeightUpdateInterval is the default interval in which the
+	// session weights of load shared routing table indices are updated.
+	defaultWeightUpdateInterval = time.Second
+	// weightChangeThreshold is the relative change of a weight that triggers
+	// an update of the flow shares. Smaller changes are ignored, since every
+	// update moves flows to other sessions or paths.
+	weightChangeThreshold = 0.2
+)
+
+// SessionWeigher provides the weight of a session for load sharing.
+type SessionWeigher interface {
+	// Weight returns the current weight of the session. A higher weight
+	// results in a larger share of the traffic.
+	Weight() float64
+}
+
 // RoutingTable is the dataplane routing table as seen from the control plane.
 type RoutingTable interface {
 	io.Closer
@@ -92,6 +112,17 @@ type Router struct {
 	Events <-chan SessionEvent
 	// Metrics are the metrics of the router.
 	Metrics RouterMetrics
+	// LoadSharing contains the routing table indices for which the traffic is
+	// shared among all alive sessions instead of being sent over the first
+	// alive session only. The flows are shared according to the session
+	// weights.
+	LoadSharing map[int]bool
+	// SessionWeights provides the weights of the sessions used for load
+	// sharing. Sessions without an entry have the same weight.
+	SessionWeights map[uint8]SessionWeigher
+	// WeightUpdateInterval is the interval in which the weights of the load
+	// shared routing table indices are updated. If zero, a default is used.
+	WeightUpdateInterval time.Duration
 
 	// stateMtx protects mutable state.
 	stateMtx sync.RWMutex
@@ -99,6 +130,9 @@ type Router struct {
 	sessionStates map[uint8]Event
 	// currentSessions maps routing table indices to the session in use.
 	currentSessions map[int]uint8
+	// sharedSessions maps load shared routing table indices to the sessions in
+	// use.
+	sharedSessions map[int]sharedSessions
 
 	workerBase worker.Base
 }
@@ -113,6 +147,18 @@ func (r *Router) Run(ctx context.Context) error {
 
 func (r *Router) run(ctx context.Context) error {
 	logger := log.FromCtx(ctx)
+	// The weights only need to be updated if there are load shared routing
+	// table indices, otherwise the nil channel blocks forever.
+	var weightUpdates <-chan time.Time
+	if len(r.LoadSharing) > 0 {
+		interval := r.WeightUpdateInterval
+		if interval == 0 {
+			interval = defaultWeightUpdateInterval
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		weightUpdates = ticker.C
+	}
 	for {
 		select {
 		case <-r.workerBase.GetDoneChan():
@@ -123,6 +169,8 @@ func (r *Router) run(ctx context.Context) error {
 			if err != nil {
 				logger.Error("Handling event", "err", err)
 			}
+		case <-weightUpdates:
+			r.updateWeights(ctx)
 		}
 	}
 }
@@ -135,6 +183,7 @@ func (r *Router) Close(ctx context.Context) error {
 func (r *Router) initData(ctx context.Context) error {
 	r.currentSessions = make(map[int]uint8, len(r.RoutingTableIndices))
 	r.sessionStates = make(map[uint8]Event, len(r.DataplaneSessions))
+	r.sharedSessions = make(map[int]sharedSessions, len(r.LoadSharing))
 	return nil
 }
 
@@ -154,20 +203,18 @@ func (r *Router) handleEvent(ctx context.Context, event SessionEvent) error {
 	for rtID := range r.RoutingTableIndices {
 		metrics.GaugeSet(r.Metrics.SessionsAlive(rtID), float64(r.aliveSessions(rtID)))
 	}
+	for rtID, sessIDs := range r.RoutingTableIndices {
+		if r.LoadSharing[rtID] && containsSession(sessIDs, event.SessionID) {
+			r.updateShared(ctx, rtID)
+		}
+	}
 	switch event.Event {
 	case EventUp:
-		getIdx := func(ids []uint8, search uint8) int {
-			for i, id := range ids {
-				if id == search {
-					return i
-				}
-			}
-			return -1
-		}
 		for rtID, sessIDs := range r.RoutingTableIndices {
 			// Skip routing table indices that do not contain the session this
-			// event is for.
-			if getIdx(sessIDs, event.SessionID) == -1 {
+			// event is for, and the load shared ones, which are already
+			// updated.
+			if !containsSession(sessIDs, event.SessionID) || r.LoadSharing[rtID] {
 				continue
 			}
 			// check if there is already a session for this index.
@@ -249,6 +296,158 @@ func (r *Router) findSession(rtID int) (uint8, int) {
 	return 0, -1
 }
 
+// sharedSessions are the sessions in use for a load shared routing table
+// index.
+type sharedSessions struct {
+	IDs     []uint8
+	Weights []float64
+}
+
+// sameSessions returns true if both use the same sessions.
+func (s sharedSessions) sameSessions(o sharedSessions) bool {
+	if len(s.IDs) != len(o.IDs) {
+		return false
+	}
+	for i := range s.IDs {
+		if s.IDs[i] != o.IDs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// weight returns the weight of the session in use, and false if the session
+// is not in use.
+func (s sharedSessions) weight(id uint8) (float64, bool) {
+	for i := range s.IDs {
+		if s.IDs[i] == id {
+			return s.Weights[i], true
+		}
+	}
+	return 0, false
+}
+
+// equal returns true if both use the same sessions with the same weights.
+func (s sharedSessions) equal(o sharedSessions) bool {
+	if !s.sameSessions(o) {
+		return false
+	}
+	for i := range s.Weights {
+		if s.Weights[i] != o.Weights[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// weightChanged returns true if the weight changed by more than
+// weightChangeThreshold relative to the larger of both weights.
+func weightChanged(old, new float64) bool {
+	return math.Abs(old-new) > weightChangeThreshold*math.Max(old, new)
+}
+
+// updateWeights updates all load shared routing table indices with the current
+// session weights.
+func (r *Router) updateWeights(ctx context.Context) {
+	r.stateMtx.Lock()
+	defer r.stateMtx.Unlock()
+
+	for rtID := range r.RoutingTableIndices {
+		if r.LoadSharing[rtID] {
+			r.updateShared(ctx, rtID)
+		}
+	}
+}
+
+// updateShared sets a routing table entry that shares the traffic among all
+// alive sessions of the load shared routing table index. If no session is
+// alive, the routing table entry is cleared. The caller must hold the state
+// lock.
+func (r *Router) updateShared(ctx context.Context, rtID int) {
+	logger := log.FromCtx(ctx)
+	current, ok := r.sharedSessions[rtID]
+	var next sharedSessions
+	for _, sessID := range r.RoutingTableIndices[rtID] {
+		if r.sessionStates[sessID] != EventUp {
+			continue
+		}
+		weight := 1.0
+		if weigher, ok := r.SessionWeights[sessID]; ok {
+			weight = weigher.Weight()
+		}
+		// Keep the weight in use unless it changed significantly, such that
+		// the flows of the other sessions are not moved.
+		if inUse, used := current.weight(sessID); used && !weightChanged(inUse, weight) {
+			weight = inUse
+		}
+		next.IDs = append(next.IDs, sessID)
+		next.Weights = append(next.Weights, weight)
+	}
+	if len(next.IDs) == 0 {
+		if !ok {
+			return
+		}
+		logger.Debug("No alive session found", "routing_chain", rtID)
+		metrics.CounterInc(r.Metrics.StateChanges(rtID))
+		metrics.GaugeSet(r.Metrics.RoutingChainHealthy(rtID), 0)
+		if err := r.RoutingTable.ClearSession(rtID); err != nil {
+			// if the routing table doesn't know the index it means
+			// something was wrongly programmed.
+			panic(serrors.WrapStr("deleting from routing table", err, "id", rtID))
+		}
+		delete(r.sharedSessions, rtID)
+		return
+	}
+	if ok && current.equal(next) {
+		return
+	}
+	writers := make([]PktWriter, 0, len(next.IDs))
+	keys := make([]string, 0, len(next.IDs))
+	for _, sessID := range next.IDs {
+		writers = append(writers, r.DataplaneSessions[sessID])
+		keys = append(keys, strconv.Itoa(int(sessID)))
+	}
+	logger.Debug("Setting shared sessions", "routing_chain", rtID,
+		"session_ids", next.IDs, "weights", next.Weights)
+	if !ok {
+		metrics.CounterInc(r.Metrics.StateChanges(rtID))
+		metrics.GaugeSet(r.Metrics.RoutingChainHealthy(rtID), 1)
+	} else if !current.sameSessions(next) {
+		metrics.CounterInc(r.Metrics.SessionChanges(rtID))
+	}
+	err := r.RoutingTable.SetSession(rtID, NewWeightedPktWriter(writers, keys, next.Weights))
+	if err != nil {
+		// if the routing table doesn't know the index it means
+		// something was wrongly programmed.
+		panic(serrors.WrapStr("adding to routing table", err, "id", rtID))
+	}
+	r.sharedSessions[rtID] = next
+}
+
+// activeSessions returns the sessions that are currently used for the routing
+// table index.
+func (r *Router) activeSessions(rtID int) []uint8 {
+	r.stateMtx.RLock()
+	defer r.stateMtx.RUnlock()
+
+	if shared, ok := r.sharedSessions[rtID]; ok {
+		return append([]uint8(nil), shared.IDs...)
+	}
+	if sessID, ok := r.currentSessions[rtID]; ok {
+		return []uint8{sessID}
+	}
+	return nil
+}
+
+func containsSession(ids []uint8, search uint8) bool {
+	for _, id := range ids {
+		if id == search {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *Router) aliveSessions(rtID int) int {
 	alive := 0
 	for _, sessID := range r.RoutingTableIndices[rtID] {
@@ -267,11 +466,13 @@ func (r *Router) DiagnosticsWrite(w io.Writer) {
 	type Diagnostics struct {
 		RoutingTableIndices map[int][]uint8
 		CurrentSessions     map[int]uint8
+		SharedSessions      map[int]sharedSessions `json:",omitempty"`
 		SessionStates       map[uint8]Event
 	}
 	d := Diagnostics{
 		RoutingTableIndices: r.RoutingTableIndices,
 		CurrentSessions:     r.currentSessions,
+		SharedSessions:      r.sharedSessions,
 		SessionStates:       r.sessionStates,
 	}
 	raw, err := json.MarshalIndent(d, "", "    ")
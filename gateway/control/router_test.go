@@ -16,6 +16,7 @@ package control_test
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -123,3 +124,98 @@ func TestRouterRun(t *testing.T) {
 		t.Fatalf("Timeout waiting on run to complete")
 	}
 }
+
+type testWeigher struct {
+	mtx    sync.Mutex
+	weight float64
+}
+
+func (w *testWeigher) Weight() float64 {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.weight
+}
+
+func (w *testWeigher) Set(weight float64) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.weight = weight
+}
+
+func TestRouterLoadSharing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	rt := mock_control.NewMockRoutingTable(ctrl)
+
+	events := make(chan control.SessionEvent)
+	weigher := &testWeigher{weight: 1}
+	router := control.Router{
+		RoutingTable: rt,
+		RoutingTableIndices: map[int][]uint8{
+			1: {100, 101},
+		},
+		DataplaneSessions: map[uint8]control.PktWriter{
+			100: testPktWriter{ID: 100},
+			101: testPktWriter{ID: 101},
+		},
+		Events: events,
+		Metrics: control.RouterMetrics{
+			RoutingChainHealthy: func(routingChain int) metrics.Gauge { return nil },
+			SessionsAlive:       func(routingChain int) metrics.Gauge { return nil },
+			SessionChanges:      func(routingChain int) metrics.Counter { return nil },
+			StateChanges:        func(routingChain int) metrics.Counter { return nil },
+		},
+		LoadSharing: map[int]bool{1: true},
+		SessionWeights: map[uint8]control.SessionWeigher{
+			100: &testWeigher{weight: 1},
+			101: weigher,
+		},
+		WeightUpdateInterval: 10 * time.Millisecond,
+	}
+	errChan := make(chan error)
+	go func() { errChan <- router.Run(context.Background()) }()
+
+	callChan := make(chan struct{})
+	setShared := func(_ int, session control.PktWriter) error {
+		assert.IsType(t, &control.WeightedPktWriter{}, session)
+		callChan <- struct{}{}
+		return nil
+	}
+
+	// Every session that goes up is added to the shared routing table entry.
+	rt.EXPECT().SetSession(1, gomock.Any()).DoAndReturn(setShared)
+	events <- control.SessionEvent{SessionID: 100, Event: control.EventUp}
+	xtest.AssertReadReturnsBefore(t, callChan, time.Second)
+
+	rt.EXPECT().SetSession(1, gomock.Any()).DoAndReturn(setShared)
+	events <- control.SessionEvent{SessionID: 101, Event: control.EventUp}
+	xtest.AssertReadReturnsBefore(t, callChan, time.Second)
+
+	// A significant weight change updates the routing table entry.
+	rt.EXPECT().SetSession(1, gomock.Any()).DoAndReturn(setShared)
+	weigher.Set(3)
+	xtest.AssertReadReturnsBefore(t, callChan, time.Second)
+
+	// Small weight changes are ignored.
+	weigher.Set(3.3)
+	time.Sleep(50 * time.Millisecond)
+
+	rt.EXPECT().SetSession(1, gomock.Any()).DoAndReturn(setShared)
+	events <- control.SessionEvent{SessionID: 100, Event: control.EventDown}
+	xtest.AssertReadReturnsBefore(t, callChan, time.Second)
+
+	rt.EXPECT().ClearSession(1).DoAndReturn(func(int) error {
+		callChan <- struct{}{}
+		return nil
+	})
+	events <- control.SessionEvent{SessionID: 101, Event: control.EventDown}
+	xtest.AssertReadReturnsBefore(t, callChan, time.Second)
+
+	assert.NoError(t, router.Close(context.Background()))
+	select {
+	case err := <-errChan:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatalf("Timeout waiting on run to complete")
+	}
+}
@@ -43,6 +43,15 @@ type DataplaneSession interface {
 	Close()
 }
 
+// WeightedPathSetter is implemented by dataplane sessions that can share the
+// traffic among their paths according to weights.
+type WeightedPathSetter interface {
+	// SetWeightedPaths changes the paths on which packets are sent, like
+	// SetPaths. The share of the flows sent on a path is proportional to its
+	// weight.
+	SetWeightedPaths(paths []snet.Path, weights []float64) error
+}
+
 // Session represents a point-to-point association with a remote gateway that is subject to
 // a path policy.
 //
@@ -84,12 +93,21 @@ type Session struct {
 	// Run will return an error if DataplaneSession is nil.
 	DataplaneSession DataplaneSession
 
+	// LoadSharing indicates that the traffic is shared among the paths
+	// according to their health, i.e., latency and loss of the path probes.
+	// This requires that the DataplaneSession implements WeightedPathSetter,
+	// otherwise the traffic is shared equally.
+	LoadSharing bool
+
 	Metrics SessionMetrics
 
 	// pathResultMtx protects access to pathResult.
 	pathResultMtx sync.RWMutex
 	// pathResult is the last result from pathhealth monitoring.
 	pathResult pathhealth.Selection
+	// pathWeights are the load sharing weights last set on the data-plane
+	// session, indexed by path fingerprint. Protected by pathResultMtx.
+	pathWeights map[snet.PathFingerprint]float64
 
 	runCalledMutex sync.Mutex
 	// runCalled is incremented on the first execution of Run. Future calls will return an error.
@@ -134,12 +152,81 @@ func (s *Session) Run(ctx context.Context) error {
 				diff.log(logger)
 			}
 			s.pathResult = newPathResult
-			s.DataplaneSession.SetPaths(s.pathResult.Paths)
+			s.setPaths()
 			s.pathResultMtx.Unlock()
 		}
 	}
 }
 
+func (s *Session) setPaths() {
+	setter, ok := s.DataplaneSession.(WeightedPathSetter)
+	if !s.LoadSharing || !ok || len(s.pathResult.States) != len(s.pathResult.Paths) {
+		s.DataplaneSession.SetPaths(s.pathResult.Paths)
+		return
+	}
+	// The weights follow the path health, which changes on every poll. Keep
+	// the weights set previously unless they changed significantly, such that
+	// the flows are not moved among the paths for small fluctuations.
+	weights := make([]float64, 0, len(s.pathResult.States))
+	pathWeights := make(map[snet.PathFingerprint]float64, len(s.pathResult.States))
+	for i, state := range s.pathResult.States {
+		weight := state.Weight()
+		fingerprint := snet.Fingerprint(s.pathResult.Paths[i])
+		if prev, ok := s.pathWeights[fingerprint]; ok && !weightChanged(prev, weight) {
+			weight = prev
+		}
+		weights = append(weights, weight)
+		pathWeights[fingerprint] = weight
+	}
+	s.pathWeights = pathWeights
+	setter.SetWeightedPaths(s.pathResult.Paths, weights)
+}
+
+// Weight returns the weight of the session for load sharing among sessions.
+// It is the sum of the weights of the paths currently in use.
+func (s *Session) Weight() float64 {
+	s.pathResultMtx.RLock()
+	defer s.pathResultMtx.RUnlock()
+
+	var weight float64
+	for _, state := range s.pathResult.States {
+		weight += state.Weight()
+	}
+	return weight
+}
+
+// SwitchPath forces the session to switch away from the paths it currently
+// uses. The paths are avoided for the given duration, unless there is no
+// alternative path.
+func (s *Session) SwitchPath(duration time.Duration) error {
+	s.pathResultMtx.RLock()
+	defer s.pathResultMtx.RUnlock()
+
+	if len(s.pathResult.Paths) == 0 {
+		return serrors.New("session has no path", "session_id", s.ID)
+	}
+	for _, path := range s.pathResult.Paths {
+		s.PathMonitorRegistration.Avoid(snet.Fingerprint(path), duration)
+	}
+	return nil
+}
+
+// pathStatus returns the paths currently in use with their health.
+func (s *Session) pathStatus() []PathStatus {
+	s.pathResultMtx.RLock()
+	defer s.pathResultMtx.RUnlock()
+
+	paths := make([]PathStatus, 0, len(s.pathResult.Paths))
+	for i, path := range s.pathResult.Paths {
+		status := PathStatus{Path: path}
+		if i < len(s.pathResult.States) {
+			status.State = s.pathResult.States[i]
+		}
+		paths = append(paths, status)
+	}
+	return paths
+}
+
 func (s *Session) runCalledCheck() error {
 	s.runCalledMutex.Lock()
 	defer s.runCalledMutex.Unlock()
@@ -28,6 +28,7 @@ import (
 	"github.com/scionproto/scion/pkg/private/xtest"
 	"github.com/scionproto/scion/pkg/snet"
 	"github.com/scionproto/scion/pkg/snet/mock_snet"
+	snetpath "github.com/scionproto/scion/pkg/snet/path"
 )
 
 func TestSessionRun(t *testing.T) {
@@ -69,3 +70,47 @@ func TestSessionRun(t *testing.T) {
 		xtest.AssertReadReturnsBefore(t, done, time.Second)
 	})
 }
+
+func TestSessionSwitchPath(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	path := snetpath.Path{
+		Meta: snet.PathMetadata{
+			Interfaces: []snet.PathInterface{
+				{IA: xtest.MustParseIA("1-ff00:0:110"), ID: 1},
+				{IA: xtest.MustParseIA("1-ff00:0:111"), ID: 2},
+			},
+		},
+	}
+	pathMonitorRegistration := mock_control.NewMockPathMonitorRegistration(ctrl)
+	pathMonitorRegistration.EXPECT().Get().Return(pathhealth.Selection{
+		Paths: []snet.Path{path}}).AnyTimes()
+	dataplaneSession := mock_control.NewMockDataplaneSession(ctrl)
+	dataplaneSession.EXPECT().SetPaths([]snet.Path{path}).AnyTimes()
+
+	sessionMonitorEvents := make(chan control.SessionEvent)
+	session := &control.Session{
+		Events:                  make(chan control.SessionEvent),
+		SessionMonitorEvents:    sessionMonitorEvents,
+		PathMonitorRegistration: pathMonitorRegistration,
+		PathMonitorPollInterval: 10 * time.Millisecond,
+		DataplaneSession:        dataplaneSession,
+	}
+	// Without a path there is nothing to switch away from.
+	assert.Error(t, session.SwitchPath(time.Minute))
+
+	done := make(chan struct{})
+	go func() {
+		err := session.Run(context.Background())
+		assert.NoError(t, err)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	pathMonitorRegistration.EXPECT().Avoid(snet.Fingerprint(path), time.Minute)
+	assert.NoError(t, session.SwitchPath(time.Minute))
+
+	close(sessionMonitorEvents)
+	xtest.AssertReadReturnsBefore(t, done, time.Second)
+}
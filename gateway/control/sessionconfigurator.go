@@ -56,6 +56,12 @@ type SessionConfig struct {
 	PathPolicy policies.PathPolicy
 	// PathCount is the max number of paths to use.
 	PathCount int
+	// LoadSharing defines whether the traffic is shared among the paths and
+	// the sessions of the same policy, weighted by the path health.
+	LoadSharing bool
+	// QoS classifies the traffic of the session into classes with separate
+	// egress queues.
+	QoS QoSPolicy
 	// Gateway describes a discovered remote gateway instance.
 	Gateway Gateway
 	// Prefixes contains the network prefixes that are reachable through this
@@ -95,6 +101,14 @@ func (sc *SessionConfigurator) Close(ctx context.Context) error {
 	return sc.workerBase.CloseWrapper(ctx, nil)
 }
 
+// RemoteGateways returns the remote gateways that are currently known, together
+// with the prefixes they advertise.
+func (sc *SessionConfigurator) RemoteGateways() RemoteGateways {
+	sc.stateMtx.RLock()
+	defer sc.stateMtx.RUnlock()
+	return sc.currentRemotes
+}
+
 // DiagnosticsWrite writes diagnostics to the writer.
 func (sc *SessionConfigurator) DiagnosticsWrite(w io.Writer) {
 	type sessionConfigDiagnostics struct {
@@ -214,8 +228,10 @@ func diffSessionPolicies(a, b SessionPolicies) bool {
 func diffSessionPolicy(a, b SessionPolicy) bool {
 	if a.TrafficMatcher.String() != b.TrafficMatcher.String() ||
 		a.PathCount != b.PathCount ||
+		a.LoadSharing != b.LoadSharing ||
 		// no better way than comparing pointers here:
 		a.PerfPolicy != b.PerfPolicy ||
+		!a.QoS.Equal(b.QoS) ||
 		prefixesKey(a.Prefixes) != prefixesKey(b.Prefixes) {
 		return true
 	}
@@ -317,6 +333,8 @@ func buildSessionConfigs(sessionPolicies SessionPolicies,
 				PerfPolicy:     sessionPolicy.PerfPolicy,
 				PathPolicy:     pathPol,
 				PathCount:      sessionPolicy.PathCount,
+				LoadSharing:    sessionPolicy.LoadSharing,
+				QoS:            sessionPolicy.QoS,
 				Gateway:        entry.Gateway,
 				Prefixes:       mergePrefixes(sessionPolicy.Prefixes, entry.Prefixes),
 			})
@@ -371,6 +389,19 @@ func (p conjuctionPathPol) Filter(s []snet.Path) []snet.Path {
 	return p.Pol2.Filter(p.Pol1.Filter(s))
 }
 
+// Cost returns the cost of the path according to the first policy, which is
+// the configured session policy. The dynamically created second policy does
+// not rank paths.
+func (p conjuctionPathPol) Cost(path snet.Path) (float64, bool) {
+	coster, ok := p.Pol1.(interface {
+		Cost(snet.Path) (float64, bool)
+	})
+	if !ok {
+		return 0, false
+	}
+	return coster.Cost(path)
+}
+
 func newPathPolForEnteringAS(ia addr.IA, allowedInterfaces []uint64) policies.PathPolicy {
 	if len(allowedInterfaces) == 0 {
 		return DefaultPathPolicy
@@ -0,0 +1,92 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package control
+
+import (
+	"net"
+
+	"github.com/scionproto/scion/gateway/pathhealth"
+	"github.com/scionproto/scion/gateway/pktcls"
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/snet"
+)
+
+// ErrSessionNotFound indicates that there is no session with the requested ID.
+var ErrSessionNotFound = serrors.New("session not found")
+
+// SessionCounters are the traffic counters of a dataplane session.
+type SessionCounters struct {
+	// IPPktsSent is the number of IP packets sent.
+	IPPktsSent uint64
+	// IPPktBytesSent is the number of IP packet bytes sent.
+	IPPktBytesSent uint64
+	// IPPktsDropped is the number of IP packets dropped because the session
+	// had no path.
+	IPPktsDropped uint64
+}
+
+// SessionCounterReader is implemented by dataplane sessions that keep traffic
+// counters.
+type SessionCounterReader interface {
+	// Counters returns the current traffic counters.
+	Counters() SessionCounters
+}
+
+// PathStatus is a path used by a session together with its health.
+type PathStatus struct {
+	// Path is the path.
+	Path snet.Path
+	// State is the health of the path as determined by the path probes.
+	State pathhealth.State
+}
+
+// SessionInfo describes a session of the running engine and its current
+// state.
+type SessionInfo struct {
+	// ID is the ID of the session.
+	ID uint8
+	// PolicyID is the ID of the session policy the session was created for.
+	PolicyID int
+	// RemoteIA is the ISD-AS of the remote gateway.
+	RemoteIA addr.IA
+	// Gateway is the remote gateway.
+	Gateway Gateway
+	// TrafficMatcher is the traffic class of the session.
+	TrafficMatcher pktcls.Cond
+	// LoadSharing indicates that the traffic is shared among the sessions.
+	LoadSharing bool
+	// Healthy indicates that the remote gateway answers the session probes.
+	Healthy bool
+	// Paths are the paths currently used by the session.
+	Paths []PathStatus
+	// Counters are the traffic counters of the session. They are zero if the
+	// dataplane session does not keep counters.
+	Counters SessionCounters
+}
+
+// RoutingEntry is an entry of the routing table installed by the running
+// engine.
+type RoutingEntry struct {
+	// RemoteIA is the ISD-AS the entry routes to.
+	RemoteIA addr.IA
+	// Prefixes are the destination prefixes of the entry.
+	Prefixes []*net.IPNet
+	// TrafficMatcher is the traffic class the entry applies to.
+	TrafficMatcher pktcls.Cond
+	// Sessions are the sessions the matching traffic is currently sent on.
+	// It is empty if no session is alive.
+	Sessions []uint8
+}
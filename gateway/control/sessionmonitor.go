@@ -50,6 +50,9 @@ const (
 // PathMonitorRegistration provides access to the paths.
 type PathMonitorRegistration interface {
 	Get() pathhealth.Selection
+	// Avoid excludes the path from the selection for the given duration,
+	// unless there is no alternative path.
+	Avoid(fingerprint snet.PathFingerprint, duration time.Duration)
 	Close()
 }
 
@@ -34,8 +34,10 @@ var (
 			Entries: []*pathpol.ACLEntry{{Action: pathpol.Allow}},
 		},
 	}
-	DefaultPerfPolicy = fingerPrintOrder{}
-	DefaultPathCount  = 1
+	// DefaultPerfPolicy does not rank the paths by performance, shorter paths
+	// are preferred instead.
+	DefaultPerfPolicy policies.PerfPolicy = nil
+	DefaultPathCount                      = 1
 )
 
 // LegacySessionPolicyAdapter parses the legacy gateway JSON configuration and
@@ -46,8 +48,11 @@ type LegacySessionPolicyAdapter struct{}
 func (LegacySessionPolicyAdapter) Parse(ctx context.Context, raw []byte) (SessionPolicies, error) {
 	type JSONFormat struct {
 		ASes map[addr.IA]struct {
-			Nets      []string
-			PathCount int
+			Nets        []string
+			PathCount   int
+			PerfPolicy  string
+			LoadSharing bool
+			QoS         []legacyQoSClass
 		}
 		ConfigVersion uint64
 	}
@@ -65,13 +70,23 @@ func (LegacySessionPolicyAdapter) Parse(ctx context.Context, raw []byte) (Sessio
 		if asEntry.PathCount != 0 {
 			pathCount = asEntry.PathCount
 		}
+		perfPolicy, err := parsePerfPolicy(asEntry.PerfPolicy)
+		if err != nil {
+			return nil, serrors.WithCtx(err, "isd_as", ia)
+		}
+		qos, err := parseQoSPolicy(asEntry.QoS)
+		if err != nil {
+			return nil, serrors.WithCtx(err, "isd_as", ia)
+		}
 		policies = append(policies, SessionPolicy{
 			ID:             0,
 			IA:             ia,
 			TrafficMatcher: pktcls.CondTrue,
-			PerfPolicy:     DefaultPerfPolicy,
+			PerfPolicy:     perfPolicy,
 			PathPolicy:     DefaultPathPolicy,
 			PathCount:      pathCount,
+			LoadSharing:    asEntry.LoadSharing,
+			QoS:            qos,
 			Prefixes:       prefixes,
 		})
 	}
@@ -93,6 +108,51 @@ func parsePrefixes(rawNets []string) ([]*net.IPNet, error) {
 	return nets, nil
 }
 
+// parsePerfPolicy resolves the name of a built-in performance policy. An empty
+// name resolves to the default performance policy.
+func parsePerfPolicy(name string) (policies.PerfPolicy, error) {
+	if name == "" {
+		return DefaultPerfPolicy, nil
+	}
+	return policies.PerfPolicyByName(name)
+}
+
+// legacyQoSClass is the JSON representation of a QoS class. The class is a
+// traffic class in the format parsed by pktcls.BuildClassTree.
+type legacyQoSClass struct {
+	Name     string
+	Class    string
+	Priority int
+	Weight   int
+	Rate     uint64
+}
+
+// parseQoSPolicy converts the JSON representation of the QoS classes. No
+// classes result in an empty QoS policy.
+func parseQoSPolicy(classes []legacyQoSClass) (QoSPolicy, error) {
+	if len(classes) == 0 {
+		return nil, nil
+	}
+	qos := make(QoSPolicy, 0, len(classes))
+	for _, class := range classes {
+		matcher, err := pktcls.BuildClassTree(class.Class)
+		if err != nil {
+			return nil, serrors.WrapStr("parsing QoS class", err, "name", class.Name)
+		}
+		qos = append(qos, QoSClass{
+			Name:     class.Name,
+			Matcher:  matcher,
+			Priority: class.Priority,
+			Weight:   class.Weight,
+			Rate:     class.Rate,
+		})
+	}
+	if err := qos.Validate(); err != nil {
+		return nil, err
+	}
+	return qos, nil
+}
+
 // SessionPolicyParser parses a raw session policy.
 type SessionPolicyParser interface {
 	Parse(context.Context, []byte) (SessionPolicies, error)
@@ -147,6 +207,8 @@ func (p SessionPolicies) Copy() SessionPolicies {
 // - a path class defined by a path policy,
 // - a performance policy,
 // - a path count,
+// - a load sharing mode,
+// - a QoS policy,
 // - a remote IA,
 // - a set of prefixes.
 type SessionPolicy struct {
@@ -158,8 +220,7 @@ type SessionPolicy struct {
 	// this session.
 	TrafficMatcher pktcls.Cond
 	// PerfPolicy specifies which paths should be preferred (e.g., the path with
-	// the lowest latency). If unset, paths with the lowest latency are
-	// preferred.
+	// the lowest latency). If unset, shorter paths are preferred.
 	PerfPolicy policies.PerfPolicy
 	// PathPolicy specifies the path properties that paths used for this session
 	// must satisfy.
@@ -167,6 +228,13 @@ type SessionPolicy struct {
 	// PathCount  defines the number of paths that can be simultaneously used
 	// within a session.
 	PathCount int
+	// LoadSharing defines whether the traffic is shared among the paths and
+	// the remote gateways, weighted by the path health. Otherwise, a single
+	// remote gateway is used at a time.
+	LoadSharing bool
+	// QoS classifies the traffic of the session into classes with separate
+	// egress queues. If empty, all packets share a single FIFO queue.
+	QoS QoSPolicy
 	// Prefixes contains the network prefixes that are reachable through this
 	// session.
 	Prefixes []*net.IPNet
@@ -179,10 +247,12 @@ func (sp SessionPolicy) Copy() SessionPolicy {
 		IA:             sp.IA,
 		TrafficMatcher: copyTrafficMatcher(sp.TrafficMatcher),
 		// TODO(lukedirtwalker): find a way to properly copy perf policies.
-		PerfPolicy: sp.PerfPolicy,
-		PathPolicy: copyPathPolicy(sp.PathPolicy),
-		PathCount:  sp.PathCount,
-		Prefixes:   copyPrefixes(sp.Prefixes),
+		PerfPolicy:  sp.PerfPolicy,
+		PathPolicy:  copyPathPolicy(sp.PathPolicy),
+		PathCount:   sp.PathCount,
+		LoadSharing: sp.LoadSharing,
+		QoS:         sp.QoS.Copy(),
+		Prefixes:    copyPrefixes(sp.Prefixes),
 	}
 }
 
@@ -221,7 +291,3 @@ func copyPrefixes(prefixes []*net.IPNet) []*net.IPNet {
 	}
 	return copy
 }
-
-type fingerPrintOrder struct{}
-
-func (fingerPrintOrder) Better(x, y *policies.Stats) bool { return x.Fingerprint < y.Fingerprint }
@@ -26,6 +26,7 @@ import (
 
 	"github.com/scionproto/scion/gateway/control"
 	"github.com/scionproto/scion/gateway/control/mock_control"
+	"github.com/scionproto/scion/gateway/pathhealth/policies"
 	"github.com/scionproto/scion/gateway/pktcls"
 	"github.com/scionproto/scion/pkg/addr"
 	"github.com/scionproto/scion/pkg/private/serrors"
@@ -34,6 +35,11 @@ import (
 )
 
 func TestLegacySessionPolicyAdapterParse(t *testing.T) {
+	buildClass := func(class string) pktcls.Cond {
+		cond, err := pktcls.BuildClassTree(class)
+		require.NoError(t, err)
+		return cond
+	}
 	testCases := map[string]struct {
 		Input     []byte
 		Expected  control.SessionPolicies
@@ -107,6 +113,177 @@ func TestLegacySessionPolicyAdapterParse(t *testing.T) {
 			},
 			AssertErr: assert.NoError,
 		},
+		"load sharing": {
+			Input: []byte(`
+			{
+				"ASes": {
+				  "1-ff00:0:110": {
+					"Nets": [
+					  "172.20.4.0/24"
+					],
+					"PathCount": 2,
+					"LoadSharing": true
+				  }
+				},
+				"ConfigVersion": 300
+			}
+			`),
+			Expected: control.SessionPolicies{
+				control.SessionPolicy{
+					ID:             0,
+					IA:             xtest.MustParseIA("1-ff00:0:110"),
+					TrafficMatcher: pktcls.CondTrue,
+					PerfPolicy:     control.DefaultPerfPolicy,
+					PathPolicy:     control.DefaultPathPolicy,
+					PathCount:      2,
+					LoadSharing:    true,
+					Prefixes:       []*net.IPNet{xtest.MustParseCIDR(t, "172.20.4.0/24")},
+				},
+			},
+			AssertErr: assert.NoError,
+		},
+		"perf policy": {
+			Input: []byte(`
+			{
+				"ASes": {
+				  "1-ff00:0:110": {
+					"Nets": [
+					  "172.20.4.0/24"
+					],
+					"PerfPolicy": "lowest_latency"
+				  }
+				},
+				"ConfigVersion": 300
+			}
+			`),
+			Expected: control.SessionPolicies{
+				control.SessionPolicy{
+					ID:             0,
+					IA:             xtest.MustParseIA("1-ff00:0:110"),
+					TrafficMatcher: pktcls.CondTrue,
+					PerfPolicy:     policies.LowestLatency{},
+					PathPolicy:     control.DefaultPathPolicy,
+					PathCount:      1,
+					Prefixes:       []*net.IPNet{xtest.MustParseCIDR(t, "172.20.4.0/24")},
+				},
+			},
+			AssertErr: assert.NoError,
+		},
+		"unknown perf policy": {
+			Input: []byte(`
+			{
+				"ASes": {
+				  "1-ff00:0:110": {
+					"Nets": [
+					  "172.20.4.0/24"
+					],
+					"PerfPolicy": "fastest"
+				  }
+				},
+				"ConfigVersion": 300
+			}
+			`),
+			AssertErr: assert.Error,
+		},
+		"qos": {
+			Input: []byte(`
+			{
+				"ASes": {
+				  "1-ff00:0:110": {
+					"Nets": [
+					  "172.20.4.0/24"
+					],
+					"QoS": [
+					  {"Name": "voice", "Class": "dscp=0x2e", "Priority": 2},
+					  {"Name": "bulk", "Class": "dst=172.20.4.0/26", "Weight": 2,
+					   "Rate": 1000000}
+					]
+				  }
+				},
+				"ConfigVersion": 300
+			}
+			`),
+			Expected: control.SessionPolicies{
+				control.SessionPolicy{
+					ID:             0,
+					IA:             xtest.MustParseIA("1-ff00:0:110"),
+					TrafficMatcher: pktcls.CondTrue,
+					PerfPolicy:     control.DefaultPerfPolicy,
+					PathPolicy:     control.DefaultPathPolicy,
+					PathCount:      1,
+					QoS: control.QoSPolicy{
+						{
+							Name:     "voice",
+							Matcher:  buildClass("dscp=0x2e"),
+							Priority: 2,
+						},
+						{
+							Name:    "bulk",
+							Matcher: buildClass("dst=172.20.4.0/26"),
+							Weight:  2,
+							Rate:    1000000,
+						},
+					},
+					Prefixes: []*net.IPNet{xtest.MustParseCIDR(t, "172.20.4.0/24")},
+				},
+			},
+			AssertErr: assert.NoError,
+		},
+		"qos invalid class": {
+			Input: []byte(`
+			{
+				"ASes": {
+				  "1-ff00:0:110": {
+					"Nets": [
+					  "172.20.4.0/24"
+					],
+					"QoS": [
+					  {"Name": "voice", "Class": "dscp=46"}
+					]
+				  }
+				},
+				"ConfigVersion": 300
+			}
+			`),
+			AssertErr: assert.Error,
+		},
+		"qos duplicate class": {
+			Input: []byte(`
+			{
+				"ASes": {
+				  "1-ff00:0:110": {
+					"Nets": [
+					  "172.20.4.0/24"
+					],
+					"QoS": [
+					  {"Name": "voice", "Class": "dscp=0x2e"},
+					  {"Name": "voice", "Class": "dscp=0x2c"}
+					]
+				  }
+				},
+				"ConfigVersion": 300
+			}
+			`),
+			AssertErr: assert.Error,
+		},
+		"qos default class": {
+			Input: []byte(`
+			{
+				"ASes": {
+				  "1-ff00:0:110": {
+					"Nets": [
+					  "172.20.4.0/24"
+					],
+					"QoS": [
+					  {"Name": "default", "Class": "dscp=0x2e"}
+					]
+				  }
+				},
+				"ConfigVersion": 300
+			}
+			`),
+			AssertErr: assert.Error,
+		},
 	}
 	for name, tc := range testCases {
 		name, tc := name, tc
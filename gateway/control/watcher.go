@@ -61,13 +61,21 @@ type Gateway struct {
 	Data *net.UDPAddr
 	// Interfaces are the last-hop SCION interfaces that should be preferred.
 	Interfaces []uint64
+	// FrameProtection indicates that the remote gateway supports protected
+	// data-plane frames. It is learned through the prefix exchange.
+	FrameProtection bool
+	// PrefixAttestations are the raw prefix attestations advertised by the
+	// remote gateway. They are learned through the prefix exchange and
+	// consumed by the PrefixesFilter.
+	PrefixAttestations [][]byte
 }
 
 func (g Gateway) Equal(other Gateway) bool {
 	return g.Control.String() == other.Control.String() &&
 		g.Probe.String() == other.Probe.String() &&
 		g.Data.String() == other.Data.String() &&
-		interfacesKey(g.Interfaces) == interfacesKey(other.Interfaces)
+		interfacesKey(g.Interfaces) == interfacesKey(other.Interfaces) &&
+		g.FrameProtection == other.FrameProtection
 }
 
 func interfacesKey(interfaces []uint64) string {
@@ -150,11 +158,12 @@ func (w *watcherItem) Close() error {
 
 // gatewayDiagnostics represents the gathered diagnostics from the prefixes.
 type gatewayDiagnostics struct {
-	DataAddr   string    `json:"data_address"`
-	ProbeAddr  string    `json:"probe_address"`
-	Interfaces []uint64  `json:"interfaces"`
-	Prefixes   []string  `json:"prefixes"`
-	Timestamp  time.Time `json:"timestamp"`
+	DataAddr        string    `json:"data_address"`
+	ProbeAddr       string    `json:"probe_address"`
+	Interfaces      []uint64  `json:"interfaces"`
+	Prefixes        []string  `json:"prefixes"`
+	FrameProtection bool      `json:"frame_protection"`
+	Timestamp       time.Time `json:"timestamp"`
 }
 
 // Run watches the remote for gateways. This method blocks until the context
@@ -274,11 +283,12 @@ func (w *GatewayWatcher) diagnostics() (remoteDiagnostics, error) {
 			interfaces = []uint64{}
 		}
 		diagnostics.Gateways[watcher.gateway.Control.String()] = gatewayDiagnostics{
-			DataAddr:   watcher.gateway.Data.String(),
-			ProbeAddr:  watcher.gateway.Probe.String(),
-			Interfaces: interfaces,
-			Prefixes:   watcher.prefixes,
-			Timestamp:  watcher.timestamp,
+			DataAddr:        watcher.gateway.Data.String(),
+			ProbeAddr:       watcher.gateway.Probe.String(),
+			Interfaces:      interfaces,
+			Prefixes:        watcher.prefixes,
+			FrameProtection: watcher.frameProtection,
+			Timestamp:       watcher.timestamp,
 		}
 	}
 	return diagnostics, nil
@@ -305,9 +315,21 @@ type PrefixConsumer interface {
 	Prefixes(remote addr.IA, gateway Gateway, prefixes []*net.IPNet) error
 }
 
+// Advertisement is the information a remote gateway advertises in the prefix
+// exchange.
+type Advertisement struct {
+	// Prefixes are the IP prefixes reachable via the remote gateway.
+	Prefixes []*net.IPNet
+	// FrameProtection indicates that the remote gateway supports protected
+	// data-plane frames.
+	FrameProtection bool
+	// PrefixAttestations are the raw signed prefix attestations.
+	PrefixAttestations [][]byte
+}
+
 // PrefixFetcher fetches the IP prefixes from a remote gateway.
 type PrefixFetcher interface {
-	Prefixes(ctx context.Context, gateway *net.UDPAddr) ([]*net.IPNet, error)
+	Prefixes(ctx context.Context, gateway *net.UDPAddr) (Advertisement, error)
 	Close() error
 }
 
@@ -369,6 +391,8 @@ type prefixWatcher struct {
 	prefixes []string
 	// timestamp of last fetched prefixes
 	timestamp time.Time
+	// frameProtection is the last advertised frame protection support.
+	frameProtection bool
 	// fetchErrors counts the amount of errors while fetching prefixes.
 	fetchErrors metrics.Counter
 }
@@ -425,22 +449,28 @@ func (w *prefixWatcher) run(ctx context.Context) {
 
 	logger := log.FromCtx(ctx)
 	logger.Debug("Fetching IP prefixes from remote gateway")
-	prefixes, err := w.fetcher.Prefixes(ctx, w.gateway.Control)
+	adv, err := w.fetcher.Prefixes(ctx, w.gateway.Control)
 	if err != nil {
 		metrics.CounterInc(w.fetchErrors)
 		logger.Debug("Failed to fetch IP prefixes from remote gateway", "err", err)
 		return
 	}
-	logger.Debug("Fetched prefixes successfully", "prefixes", fmtPrefixes(prefixes))
+	prefixes := adv.Prefixes
+	logger.Debug("Fetched prefixes successfully", "prefixes", fmtPrefixes(prefixes),
+		"frame_protection", adv.FrameProtection)
 
 	snapshot := fmtPrefixes(prefixes)
-	if err := w.Consumer.Prefixes(w.remote, w.gateway, prefixes); err != nil {
+	gateway := w.gateway
+	gateway.FrameProtection = adv.FrameProtection
+	gateway.PrefixAttestations = adv.PrefixAttestations
+	if err := w.Consumer.Prefixes(w.remote, gateway, prefixes); err != nil {
 		logger.Error("Failed to process prefixes", "prefixes", fmtPrefixes(prefixes), "err", err)
 	}
 
 	w.stateMtx.Lock()
 	defer w.stateMtx.Unlock()
 	w.prefixes = snapshot
+	w.frameProtection = adv.FrameProtection
 	w.timestamp = time.Now()
 }
 
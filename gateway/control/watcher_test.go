@@ -55,9 +55,9 @@ func TestGatewayWatcherRun(t *testing.T) {
 	)
 
 	fetcher.EXPECT().Prefixes(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
-		func(_ interface{}, g *net.UDPAddr) ([]*net.IPNet, error) {
+		func(_ interface{}, g *net.UDPAddr) (control.Advertisement, error) {
 			fetcherCounts.With("gateway", g.String()).Add(1)
-			return nil, serrors.New("error")
+			return control.Advertisement{}, serrors.New("error")
 		},
 	)
 
@@ -130,15 +130,16 @@ func TestPrefixWatcherRun(t *testing.T) {
 	fetcher.EXPECT().Close().AnyTimes().Return(nil)
 
 	// Initial error to check consumer is not called on error.
-	fetcher.EXPECT().Prefixes(gomock.Any(), gateway.Control).Return(nil, serrors.New("internal"))
+	fetcher.EXPECT().Prefixes(gomock.Any(), gateway.Control).Return(
+		control.Advertisement{}, serrors.New("internal"))
 
 	// First successful result has one more subnet, to check that consumer is
 	// called with the up to date list.
 	first := []*net.IPNet{cidr(t, "127.0.0.0/24"), cidr(t, "127.0.1.0/24"), cidr(t, "::/64")}
 	fetcher.EXPECT().Prefixes(gomock.Any(), gateway.Control).DoAndReturn(
-		func(_, _ interface{}) ([]*net.IPNet, error) {
+		func(_, _ interface{}) (control.Advertisement, error) {
 			fetcherCounts.Add(1)
-			return first, nil
+			return control.Advertisement{Prefixes: first}, nil
 		},
 	)
 	consumer.EXPECT().Prefixes(gomock.Any(), gateway, first).Do(
@@ -149,12 +150,14 @@ func TestPrefixWatcherRun(t *testing.T) {
 
 	afterwards := []*net.IPNet{cidr(t, "127.0.0.0/24"), cidr(t, "::/64")}
 	fetcher.EXPECT().Prefixes(gomock.Any(), gateway.Control).AnyTimes().DoAndReturn(
-		func(_, _ interface{}) ([]*net.IPNet, error) {
+		func(_, _ interface{}) (control.Advertisement, error) {
 			fetcherCounts.Add(1)
-			return afterwards, nil
+			return control.Advertisement{Prefixes: afterwards, FrameProtection: true}, nil
 		},
 	)
-	consumer.EXPECT().Prefixes(gomock.Any(), gateway, afterwards).AnyTimes().Do(
+	protectedGateway := gateway
+	protectedGateway.FrameProtection = true
+	consumer.EXPECT().Prefixes(gomock.Any(), protectedGateway, afterwards).AnyTimes().Do(
 		func(_, _, _ interface{}) {
 			consumerCounts.Add(1)
 		},
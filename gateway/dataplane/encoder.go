@@ -54,8 +54,8 @@ type encoder struct {
 	// streamID is identifies a flow within the session. Only the frames from
 	// the same streams are, on the remote side, put into the same reassembly queue.
 	streamID uint32
-	// ring is used to pass packets from the writer goroutine to the sending goroutine.
-	ring *pktRing
+	// queue is used to pass packets from the writer goroutine to the sending goroutine.
+	queue pktQueue
 	// seq is the next frame sequence number to use.
 	seq uint64
 	// pkt is the unprocessed part of the currently processed packet.
@@ -67,12 +67,12 @@ type encoder struct {
 
 // newEncoder creates a new encoder instance.
 // mtu is max size of the frame, excluding SCION header, but including SIG header.
-func newEncoder(sessionID uint8, streamID uint32, mtu uint16) *encoder {
+func newEncoder(sessionID uint8, streamID uint32, mtu uint16, queue pktQueue) *encoder {
 	return &encoder{
 		sessionID: sessionID,
 		streamID:  streamID,
 		seq:       0,
-		ring:      newPktRing(),
+		queue:     queue,
 		frame:     make([]byte, 0, mtu),
 	}
 }
@@ -80,12 +80,12 @@ func newEncoder(sessionID uint8, streamID uint32, mtu uint16) *encoder {
 // Close initiates the close procedure. Frames can still be read.
 // Once there are no more frames available, Read will return nil.
 func (e *encoder) Close() {
-	e.ring.Close()
+	e.queue.Close()
 }
 
-// Write sends a packet to the encoder.
-func (e *encoder) Write(pkt []byte) {
-	e.ring.Write(pkt, false)
+// Write sends a packet of the given QoS class to the encoder.
+func (e *encoder) Write(pkt []byte, class int) {
+	e.queue.Write(pkt, class)
 }
 
 // Read reads a frame from the encoder.
@@ -123,7 +123,7 @@ func (e *encoder) Read() []byte {
 		// we'll send what we have immediately.
 		block := (pos == hdrLen)
 		var n int
-		e.pkt, n = e.ring.Read(block)
+		e.pkt, n = e.queue.Read(block)
 		if n == 0 {
 			// No more packets to stuff into the frame. Go on with sending.
 			return e.frame[:pos]
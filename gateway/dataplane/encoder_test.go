@@ -22,20 +22,20 @@ import (
 
 func TestEncoder(t *testing.T) {
 	t.Run("closed ringbuf", func(t *testing.T) {
-		e := newEncoder(1, 2, 1500)
+		e := newEncoder(1, 2, 1500, newFIFOQueue())
 		e.Close()
 		f := e.Read()
 		assert.Nil(t, f)
 	})
 
 	t.Run("simple IPv4 packet", func(t *testing.T) {
-		e := newEncoder(1, 2, 1500)
+		e := newEncoder(1, 2, 1500, newFIFOQueue())
 		e.Write([]byte{
 			// IPv4 header.
 			0x40, 0, 0, 23, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 			// Payload.
 			1, 2, 3,
-		})
+		}, 0)
 		e.Close()
 		f := e.Read()
 		assert.EqualValues(t, []byte{
@@ -51,14 +51,14 @@ func TestEncoder(t *testing.T) {
 	})
 
 	t.Run("simple IPv6 packet", func(t *testing.T) {
-		e := newEncoder(1, 2, 1500)
+		e := newEncoder(1, 2, 1500, newFIFOQueue())
 		e.Write([]byte{
 			// IPv6 header.
 			0x60, 0, 0, 0, 0, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 			0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 			// Payload.
 			1, 2, 3,
-		})
+		}, 0)
 		e.Close()
 		f := e.Read()
 		assert.EqualValues(t, []byte{
@@ -75,19 +75,19 @@ func TestEncoder(t *testing.T) {
 	})
 
 	t.Run("two packets in a single frame", func(t *testing.T) {
-		e := newEncoder(1, 2, 1500)
+		e := newEncoder(1, 2, 1500, newFIFOQueue())
 		e.Write([]byte{
 			// IPv4 header.
 			0x40, 0, 0, 23, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 			// Payload.
 			4, 5, 6,
-		})
+		}, 0)
 		e.Write([]byte{
 			// IPv4 header.
 			0x40, 0, 0, 22, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 			// Payload.
 			7, 8,
-		})
+		}, 0)
 		e.Close()
 		f := e.Read()
 		assert.EqualValues(t, []byte{
@@ -107,13 +107,13 @@ func TestEncoder(t *testing.T) {
 	})
 
 	t.Run("single packet split into two frames", func(t *testing.T) {
-		e := newEncoder(1, 2, 56)
+		e := newEncoder(1, 2, 56, newFIFOQueue())
 		e.Write([]byte{
 			// IPv4 header.
 			0x40, 0, 0, 42, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 			// Payload.
 			1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22,
-		})
+		}, 0)
 		e.Close()
 		f := e.Read()
 		assert.EqualValues(t, []byte{
@@ -136,19 +136,19 @@ func TestEncoder(t *testing.T) {
 	})
 
 	t.Run("second packet starting at non-zero position in the second frame", func(t *testing.T) {
-		e := newEncoder(1, 2, 58)
+		e := newEncoder(1, 2, 58, newFIFOQueue())
 		e.Write([]byte{
 			// IPv4 header.
 			0x40, 0, 0, 44, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 			// Payload.
 			1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24,
-		})
+		}, 0)
 		e.Write([]byte{
 			// IPv4 header.
 			0x40, 0, 0, 22, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 			// Payload.
 			25, 26,
-		})
+		}, 0)
 		e.Close()
 		f := e.Read()
 		assert.EqualValues(t, []byte{
@@ -68,6 +68,11 @@ type IngressServer struct {
 	Conn          ReadConn
 	DeviceManager control.DeviceManager
 	Metrics       IngressMetrics
+	// FrameKeys, if set, is used to verify and decrypt protected frames. If
+	// nil, protected frames are discarded.
+	FrameKeys FrameKeyProvider
+	// RequireProtection indicates that unprotected frames are discarded.
+	RequireProtection bool
 
 	workers map[string]*worker
 }
@@ -109,11 +114,11 @@ func (d *IngressServer) read(ctx context.Context) error {
 				frame.Release()
 				continue
 			}
-			if frame.raw[0] != 0 {
+			if !d.versionSupported(frame.raw[versionPos]) {
 				metrics.CounterInc(metrics.CounterWith(d.Metrics.FramesDiscarded,
 					"remote_isd_as", v.IA.String(), "reason", "invalid"))
 				logger.Info("IngressServer: Unsupported SIG protocol version",
-					"supported", 0, "actual", frame.raw[0])
+					"actual", frame.raw[versionPos])
 				frame.Release()
 				continue
 			}
@@ -133,6 +138,19 @@ func (d *IngressServer) read(ctx context.Context) error {
 	}
 }
 
+// versionSupported returns whether frames with the given SIG frame version are
+// accepted.
+func (d *IngressServer) versionSupported(version uint8) bool {
+	switch version {
+	case frameVersionPlain:
+		return !d.RequireProtection
+	case frameVersionProtected:
+		return d.FrameKeys != nil
+	default:
+		return false
+	}
+}
+
 // dispatch dispatches a frame to the corresponding worker, spawning one if none
 // exist yet. Dispatching is done based on source ISD-AS -> source host Addr -> Sess Id.
 func (d *IngressServer) dispatch(ctx context.Context, frame *frameBuf, src *snet.UDPAddr) {
@@ -152,6 +170,9 @@ func (d *IngressServer) dispatch(ctx context.Context, frame *frameBuf, src *snet
 		// Handle will be cleaned up when worker goroutine finishes.
 
 		worker = newWorker(src, frame.sessId, handle, metrics)
+		if d.FrameKeys != nil {
+			worker.opener = newFrameOpener(d.FrameKeys, src)
+		}
 		d.workers[dispatchStr] = worker
 		go func() {
 			defer log.HandlePanic()
@@ -17,6 +17,8 @@ package dataplane
 import (
 	"context"
 	"io"
+	"net"
+	"time"
 
 	"github.com/google/gopacket"
 	"github.com/google/gopacket/layers"
@@ -49,6 +51,10 @@ type IPForwarderMetrics struct {
 	IPPktsInvalid metrics.Counter
 	//  IPPktsFragmented the number of fragmented packet. If nil, the metric is not reported.
 	IPPktsFragmented metrics.Counter
+	// IPPktsTooBig counts the number of IP packets that were discarded because
+	// they exceed the tunnel MTU and must not be fragmented. If nil, the metric
+	// is not reported.
+	IPPktsTooBig metrics.Counter
 	// ReceiveLocalErrors counts the number of read errors encountered on the raw packets source.
 	// If nil, the metric is not reported.
 	ReceiveLocalErrors metrics.Counter
@@ -66,6 +72,20 @@ type IPForwarder struct {
 	// Metrics is used by the forwarder to report information about internal operation.
 	// If a metric is not initialized, it is not reported.
 	Metrics IPForwarderMetrics
+	// Writer is used to send ICMP Packet Too Big messages to the local senders
+	// of packets that exceed the tunnel MTU and must not be fragmented. If nil,
+	// no messages are sent and such packets are split across frames.
+	Writer io.Writer
+	// ICMPSourceIPv4 and ICMPSourceIPv6 are the source addresses of the ICMP
+	// messages. If nil, the destination address of the packet is used.
+	ICMPSourceIPv4 net.IP
+	ICMPSourceIPv6 net.IP
+	// ClampMSS enables clamping the maximum segment size of TCP SYN packets to
+	// the tunnel MTU.
+	ClampMSS bool
+
+	// icmpLimiter limits the rate of the ICMP Packet Too Big messages.
+	icmpLimiter icmpRateLimiter
 }
 
 // Run forwards packets from the reader based on the routing table.
@@ -129,11 +149,74 @@ func (f *IPForwarder) Run(ctx context.Context) error {
 			metrics.CounterInc(f.Metrics.IPPktsNoRoute)
 			continue
 		}
+		if mtuWriter, ok := session.(control.PktMTUWriter); ok && f.needsMTU(mtuWriter, packet) {
+			// The check runs while the session is locked, so the ICMP message
+			// is only sent after the session returned.
+			tooBigMTU := 0
+			mtuWriter.WriteWithMTU(packet, func(mtu int) bool {
+				if !f.checkMTU(packet, mtu) {
+					tooBigMTU = mtu
+					return false
+				}
+				return true
+			})
+			if tooBigMTU > 0 {
+				f.sendPacketTooBig(ctx, packet, tooBigMTU)
+			}
+			continue
+		}
 
 		session.Write(packet)
 	}
 }
 
+// needsMTU returns true if the packet must be checked against the tunnel MTU of
+// the path it is sent on, i.e., if it is a TCP SYN and the maximum segment size
+// is clamped, or if it might exceed the MTU.
+func (f *IPForwarder) needsMTU(session control.PktMTUWriter, packet gopacket.Packet) bool {
+	if f.ClampMSS && isTCPSYN(packet) {
+		return true
+	}
+	if f.Writer == nil {
+		return false
+	}
+	minMTU := session.MinMTU()
+	return minMTU > 0 && len(packet.Data()) > minMTU
+}
+
+// checkMTU clamps the TCP maximum segment size to the tunnel MTU. It returns
+// false if the packet exceeds the tunnel MTU and must not be forwarded, in which
+// case its sender must be notified with sendPacketTooBig.
+func (f *IPForwarder) checkMTU(packet gopacket.Packet, mtu int) bool {
+	if mtu <= 0 {
+		return true
+	}
+	if f.Writer != nil && exceedsMTU(packet, mtu) {
+		return false
+	}
+	if f.ClampMSS {
+		clampMSS(packet, mtu)
+	}
+	return true
+}
+
+// sendPacketTooBig sends an ICMP Packet Too Big message for the packet to its
+// local sender. The messages are rate limited as required by RFC 4443,
+// Section 2.4 (f).
+func (f *IPForwarder) sendPacketTooBig(ctx context.Context, packet gopacket.Packet, mtu int) {
+	metrics.CounterInc(f.Metrics.IPPktsTooBig)
+	if !f.icmpLimiter.allow(time.Now()) {
+		return
+	}
+	reply, ok := packetTooBig(packet, mtu, f.ICMPSourceIPv4, f.ICMPSourceIPv6)
+	if !ok {
+		return
+	}
+	if _, err := f.Writer.Write(reply); err != nil {
+		log.FromCtx(ctx).Debug("forwarder: failed to send packet too big", "err", err)
+	}
+}
+
 func (f *IPForwarder) validate() error {
 	if f.Reader == nil {
 		return serrors.New("packet reader must not be nil")
@@ -152,3 +235,35 @@ func (f *IPForwarder) initMetrics() {
 		f.Metrics.ReceiveLocalErrors.Add(0)
 	}
 }
+
+// packetTooBigRate is the maximum number of ICMP Packet Too Big messages that
+// the forwarder sends per second.
+const packetTooBigRate = 100
+
+// icmpRateLimiter limits the rate at which ICMP messages are generated. It is a
+// token bucket that holds at most one second worth of tokens. The zero value
+// allows packetTooBigRate messages per second. It is not safe for concurrent
+// use.
+type icmpRateLimiter struct {
+	tokens float64
+	last   time.Time
+}
+
+// allow takes a token from the bucket. It returns false if no token is
+// available.
+func (l *icmpRateLimiter) allow(now time.Time) bool {
+	if l.last.IsZero() {
+		l.tokens = packetTooBigRate
+	} else {
+		l.tokens += now.Sub(l.last).Seconds() * packetTooBigRate
+		if l.tokens > packetTooBigRate {
+			l.tokens = packetTooBigRate
+		}
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
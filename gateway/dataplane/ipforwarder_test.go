@@ -216,6 +216,92 @@ func TestIPForwarderRun(t *testing.T) {
 
 		xtest.AssertReadReturnsBefore(t, done, time.Second)
 	})
+
+	t.Run("packet too big", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		reader := mock_io.NewMockReader(ctrl)
+		rt := dataplane.NewRoutingTable([]*control.RoutingChain{
+			{
+				Prefixes:        []*net.IPNet{xtest.MustParseCIDR(t, "10.0.0.0/8")},
+				TrafficMatchers: []control.TrafficMatcher{{ID: 1, Matcher: pktcls.CondTrue}},
+			},
+		})
+		art := &dataplane.AtomicRoutingTable{}
+		art.SetRoutingTable(rt)
+
+		session := mock_control.NewMockPktMTUWriter(ctrl)
+		rt.SetSession(1, session)
+
+		bigPacket := newDFIPv4Packet(t, net.IP{10, 0, 0, 1}, 1500)
+		reader.EXPECT().Read(gomock.Any()).DoAndReturn(
+			func(b []byte) (int, error) { return copy(b, bigPacket.Data()), nil },
+		)
+		session.EXPECT().MinMTU().Return(1400).Times(2)
+		session.EXPECT().WriteWithMTU(Packet(bigPacket), gomock.Any()).Do(
+			func(_ gopacket.Packet, check func(int) bool) { assert.False(t, check(1400)) },
+		)
+
+		smallPacket := newDFIPv4Packet(t, net.IP{10, 0, 0, 1}, 1000)
+		reader.EXPECT().Read(gomock.Any()).DoAndReturn(
+			func(b []byte) (int, error) { return copy(b, smallPacket.Data()), nil },
+		)
+		// Packets that fit into the smallest MTU are written without looking
+		// up the MTU of their path.
+		session.EXPECT().Write(Packet(smallPacket))
+
+		// Force IP forwarder to shut down.
+		errDone := serrors.New("done")
+		reader.EXPECT().Read(gomock.Any()).Return(0, errDone)
+
+		writer := &bytes.Buffer{}
+		ipForwarder := &dataplane.IPForwarder{
+			Reader:       reader,
+			RoutingTable: art,
+			Writer:       writer,
+		}
+
+		done := make(chan struct{})
+		go func() {
+			err := ipForwarder.Run(context.Background())
+			require.True(t, errors.Is(err, errDone), err)
+			close(done)
+		}()
+
+		xtest.AssertReadReturnsBefore(t, done, time.Second)
+
+		reply := gopacket.NewPacket(writer.Bytes(), layers.LayerTypeIPv4, gopacket.Default)
+		icmp, ok := reply.Layer(layers.LayerTypeICMPv4).(*layers.ICMPv4)
+		require.True(t, ok)
+		assert.Equal(t, layers.CreateICMPv4TypeCode(layers.ICMPv4TypeDestinationUnreachable,
+			layers.ICMPv4CodeFragmentationNeeded), icmp.TypeCode)
+		assert.Equal(t, uint16(1400), icmp.Seq)
+	})
+}
+
+func newDFIPv4Packet(t *testing.T, destination net.IP, length int) gopacket.Packet {
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	err := gopacket.SerializeLayers(buf, opts,
+		&layers.IPv4{
+			Version:  4,
+			TTL:      64,
+			Flags:    layers.IPv4DontFragment,
+			Protocol: layers.IPProtocolUDP,
+			SrcIP:    net.IP{192, 168, 0, 1},
+			DstIP:    destination,
+		},
+		gopacket.Payload(make([]byte, length-20)),
+	)
+	require.NoError(t, err)
+
+	decodeOptions := gopacket.DecodeOptions{
+		NoCopy: true,
+		Lazy:   true,
+	}
+	return gopacket.NewPacket(buf.Bytes(), layers.LayerTypeIPv4, decodeOptions)
 }
 
 func newIPv4Packet(t *testing.T, destination net.IP) gopacket.Packet {
@@ -0,0 +1,245 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+const (
+	// minIPv4MTU is the minimum MTU of an IPv4 link, see RFC 791.
+	minIPv4MTU = 68
+	// minIPv6MTU is the minimum MTU of an IPv6 link, see RFC 8200.
+	minIPv6MTU = 1280
+	// maxIPv4ICMPLen is the maximum length of an ICMP error message, see
+	// RFC 1812.
+	maxIPv4ICMPLen = 576
+	// icmpHdrLen is the length of the ICMP and ICMPv6 error message header,
+	// including the MTU field.
+	icmpHdrLen = 8
+	// icmpTTL is the TTL (hop limit) of the generated ICMP messages.
+	icmpTTL = 64
+)
+
+// exceedsMTU returns true if the packet exceeds the MTU, must not be
+// fragmented, and its sender can be notified with an ICMP Fragmentation Needed
+// (IPv4) or ICMPv6 Packet Too Big message. Such packets must not be forwarded.
+func exceedsMTU(packet gopacket.Packet, mtu int) bool {
+	if len(packet.Data()) <= mtu {
+		return false
+	}
+	switch ip := packet.NetworkLayer().(type) {
+	case *layers.IPv4:
+		return ip.Flags&layers.IPv4DontFragment != 0 && mtu >= minIPv4MTU &&
+			isValidICMPDestination(ip.SrcIP) && !isICMPv4Error(ip)
+	case *layers.IPv6:
+		// The tunnel MTU must not be announced if it is below the minimum
+		// IPv6 MTU. Such packets are split across frames instead.
+		return mtu >= minIPv6MTU && isValidICMPDestination(ip.SrcIP) && !isICMPv6Error(ip)
+	default:
+		return false
+	}
+}
+
+// packetTooBig returns an ICMP Fragmentation Needed (IPv4) or ICMPv6 Packet Too
+// Big message for the packet if it exceeds the MTU and must not be fragmented.
+// The message is sent from srcIPv4 or srcIPv6, respectively. If they are nil,
+// the destination address of the packet is used instead. The second return
+// value is false if no message must be sent, in which case the packet can be
+// forwarded.
+func packetTooBig(packet gopacket.Packet, mtu int, srcIPv4, srcIPv6 net.IP) ([]byte, bool) {
+	if !exceedsMTU(packet, mtu) {
+		return nil, false
+	}
+	data := packet.Data()
+	switch ip := packet.NetworkLayer().(type) {
+	case *layers.IPv4:
+		src := srcIPv4
+		if src == nil {
+			src = ip.DstIP
+		}
+		quoteLen := maxIPv4ICMPLen - 20 - icmpHdrLen
+		return serializeICMP(
+			&layers.IPv4{
+				Version:  4,
+				TTL:      icmpTTL,
+				Protocol: layers.IPProtocolICMPv4,
+				SrcIP:    src,
+				DstIP:    ip.SrcIP,
+			},
+			&layers.ICMPv4{
+				TypeCode: layers.CreateICMPv4TypeCode(layers.ICMPv4TypeDestinationUnreachable,
+					layers.ICMPv4CodeFragmentationNeeded),
+				Seq: uint16(mtu),
+			},
+			truncate(data, quoteLen),
+		)
+	case *layers.IPv6:
+		src := srcIPv6
+		if src == nil {
+			src = ip.DstIP
+		}
+		ip6 := &layers.IPv6{
+			Version:    6,
+			HopLimit:   icmpTTL,
+			NextHeader: layers.IPProtocolICMPv6,
+			SrcIP:      src,
+			DstIP:      ip.SrcIP,
+		}
+		icmp := &layers.ICMPv6{
+			TypeCode: layers.CreateICMPv6TypeCode(layers.ICMPv6TypePacketTooBig, 0),
+		}
+		if err := icmp.SetNetworkLayerForChecksum(ip6); err != nil {
+			return nil, false
+		}
+		quoteLen := minIPv6MTU - 40 - icmpHdrLen
+		payload := make([]byte, 4, 4+quoteLen)
+		binary.BigEndian.PutUint32(payload, uint32(mtu))
+		payload = append(payload, truncate(data, quoteLen)...)
+		return serializeICMP(ip6, icmp, payload)
+	default:
+		return nil, false
+	}
+}
+
+func serializeICMP(ip, icmp gopacket.SerializableLayer, payload []byte) ([]byte, bool) {
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	err := gopacket.SerializeLayers(buf, opts, ip, icmp, gopacket.Payload(payload))
+	if err != nil {
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+func truncate(data []byte, length int) []byte {
+	if len(data) > length {
+		return data[:length]
+	}
+	return data
+}
+
+// isValidICMPDestination returns false for addresses ICMP error messages must
+// not be sent to.
+func isValidICMPDestination(ip net.IP) bool {
+	return !ip.IsUnspecified() && !ip.IsMulticast() && !ip.Equal(net.IPv4bcast)
+}
+
+// isICMPv4Error returns true if the packet is an ICMP error message. No ICMP
+// error messages are sent in response to them.
+func isICMPv4Error(ip *layers.IPv4) bool {
+	if ip.Protocol != layers.IPProtocolICMPv4 || len(ip.Payload) == 0 {
+		return false
+	}
+	switch ip.Payload[0] {
+	case layers.ICMPv4TypeDestinationUnreachable, layers.ICMPv4TypeSourceQuench,
+		layers.ICMPv4TypeRedirect, layers.ICMPv4TypeTimeExceeded,
+		layers.ICMPv4TypeParameterProblem:
+		return true
+	}
+	return false
+}
+
+// isICMPv6Error returns true if the packet is an ICMPv6 error message, i.e.,
+// its type is below 128.
+func isICMPv6Error(ip *layers.IPv6) bool {
+	return ip.NextHeader == layers.IPProtocolICMPv6 && len(ip.Payload) > 0 &&
+		ip.Payload[0] < 128
+}
+
+// isTCPSYN returns true if the packet is a TCP SYN packet.
+func isTCPSYN(packet gopacket.Packet) bool {
+	tcp, ok := packet.Layer(layers.LayerTypeTCP).(*layers.TCP)
+	return ok && tcp.SYN
+}
+
+// clampMSS lowers the maximum segment size option of a TCP SYN packet such
+// that the segments fit into the MTU. The packet is modified in place. It
+// returns true if the option was modified.
+func clampMSS(packet gopacket.Packet, mtu int) bool {
+	tcp, ok := packet.Layer(layers.LayerTypeTCP).(*layers.TCP)
+	if !ok || !tcp.SYN {
+		return false
+	}
+	var mss int
+	switch packet.NetworkLayer().(type) {
+	case *layers.IPv4:
+		mss = mtu - 20 - 20
+	case *layers.IPv6:
+		mss = mtu - 40 - 20
+	default:
+		return false
+	}
+	if mss <= 0 {
+		return false
+	}
+	hdr := tcp.Contents
+	for i := 20; i < len(hdr); {
+		switch layers.TCPOptionKind(hdr[i]) {
+		case layers.TCPOptionKindEndList:
+			return false
+		case layers.TCPOptionKindNop:
+			i++
+			continue
+		}
+		if i+1 >= len(hdr) {
+			return false
+		}
+		length := int(hdr[i+1])
+		if length < 2 || i+length > len(hdr) {
+			return false
+		}
+		if layers.TCPOptionKind(hdr[i]) == layers.TCPOptionKindMSS && length == 4 {
+			if int(binary.BigEndian.Uint16(hdr[i+2:i+4])) <= mss {
+				return false
+			}
+			setTCPUint16(hdr, i+2, uint16(mss))
+			return true
+		}
+		i += length
+	}
+	return false
+}
+
+// setTCPUint16 sets the 16-bit value at the position in the TCP header and
+// updates the checksum incrementally, see RFC 1624.
+func setTCPUint16(hdr []byte, pos int, value uint16) {
+	// The value may not be aligned to the 16-bit words of the checksum.
+	start, end := pos&^1, (pos+3)&^1
+	before := onesComplementSum(hdr[start:end])
+	binary.BigEndian.PutUint16(hdr[pos:pos+2], value)
+	after := onesComplementSum(hdr[start:end])
+	sum := uint32(^binary.BigEndian.Uint16(hdr[16:18])) + uint32(^before) + uint32(after)
+	binary.BigEndian.PutUint16(hdr[16:18], ^fold(sum))
+}
+
+func onesComplementSum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(b[i : i+2]))
+	}
+	return fold(sum)
+}
+
+func fold(sum uint32) uint16 {
+	for sum > 0xffff {
+		sum = (sum >> 16) + (sum & 0xffff)
+	}
+	return uint16(sum)
+}
@@ -0,0 +1,314 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacketTooBig(t *testing.T) {
+	srcIPv4, dstIPv4 := net.IP{10, 0, 0, 1}, net.IP{10, 1, 0, 1}
+	srcIPv6, dstIPv6 := net.ParseIP("fd00::1"), net.ParseIP("fd01::1")
+	icmpSrcIPv4, icmpSrcIPv6 := net.IP{192, 168, 0, 1}, net.ParseIP("fd02::1")
+
+	testCases := map[string]struct {
+		Packet gopacket.Packet
+		MTU    int
+		Reply  bool
+		Source net.IP
+		Check  func(t *testing.T, reply gopacket.Packet)
+	}{
+		"ipv4 fits": {
+			Packet: ipv4Packet(t, srcIPv4, dstIPv4, layers.IPv4DontFragment, 1000),
+			MTU:    1400,
+		},
+		"ipv4 may fragment": {
+			Packet: ipv4Packet(t, srcIPv4, dstIPv4, 0, 1500),
+			MTU:    1400,
+		},
+		"ipv4 too big": {
+			Packet: ipv4Packet(t, srcIPv4, dstIPv4, layers.IPv4DontFragment, 1500),
+			MTU:    1400,
+			Reply:  true,
+			Source: icmpSrcIPv4,
+			Check: func(t *testing.T, reply gopacket.Packet) {
+				ip := reply.Layer(layers.LayerTypeIPv4).(*layers.IPv4)
+				assert.Equal(t, icmpSrcIPv4, ip.SrcIP.To4())
+				assert.Equal(t, srcIPv4, ip.DstIP.To4())
+				icmp := reply.Layer(layers.LayerTypeICMPv4).(*layers.ICMPv4)
+				assert.Equal(t, layers.CreateICMPv4TypeCode(
+					layers.ICMPv4TypeDestinationUnreachable,
+					layers.ICMPv4CodeFragmentationNeeded), icmp.TypeCode)
+				assert.Equal(t, uint16(1400), icmp.Seq)
+				assert.Len(t, reply.Data(), maxIPv4ICMPLen)
+			},
+		},
+		"ipv4 too big default source": {
+			Packet: ipv4Packet(t, srcIPv4, dstIPv4, layers.IPv4DontFragment, 1500),
+			MTU:    1400,
+			Reply:  true,
+			Check: func(t *testing.T, reply gopacket.Packet) {
+				ip := reply.Layer(layers.LayerTypeIPv4).(*layers.IPv4)
+				assert.Equal(t, dstIPv4, ip.SrcIP.To4())
+			},
+		},
+		"ipv4 multicast source": {
+			Packet: ipv4Packet(t, net.IP{224, 0, 0, 1}, dstIPv4, layers.IPv4DontFragment,
+				1500),
+			MTU: 1400,
+		},
+		"ipv6 fits": {
+			Packet: ipv6Packet(t, srcIPv6, dstIPv6, 1300),
+			MTU:    1400,
+		},
+		"ipv6 too big": {
+			Packet: ipv6Packet(t, srcIPv6, dstIPv6, 1500),
+			MTU:    1400,
+			Reply:  true,
+			Source: icmpSrcIPv6,
+			Check: func(t *testing.T, reply gopacket.Packet) {
+				ip := reply.Layer(layers.LayerTypeIPv6).(*layers.IPv6)
+				assert.Equal(t, icmpSrcIPv6, ip.SrcIP)
+				assert.Equal(t, srcIPv6, ip.DstIP)
+				icmp := reply.Layer(layers.LayerTypeICMPv6).(*layers.ICMPv6)
+				assert.Equal(t, layers.CreateICMPv6TypeCode(layers.ICMPv6TypePacketTooBig, 0),
+					icmp.TypeCode)
+				assert.Equal(t, uint32(1400), binary.BigEndian.Uint32(icmp.Payload[:4]))
+				assert.Len(t, reply.Data(), minIPv6MTU)
+			},
+		},
+		"ipv6 below minimum mtu": {
+			Packet: ipv6Packet(t, srcIPv6, dstIPv6, 1500),
+			MTU:    1200,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var src4, src6 net.IP
+			if tc.Source != nil {
+				src4, src6 = icmpSrcIPv4, icmpSrcIPv6
+			}
+			reply, ok := packetTooBig(tc.Packet, tc.MTU, src4, src6)
+			require.Equal(t, tc.Reply, ok)
+			if !tc.Reply {
+				return
+			}
+			decoded := gopacket.NewPacket(reply, firstLayer(reply), gopacket.Default)
+			require.Nil(t, decoded.ErrorLayer())
+			tc.Check(t, decoded)
+		})
+	}
+}
+
+func TestPacketTooBigNoICMPError(t *testing.T) {
+	// An ICMP error that exceeds the MTU must not trigger another ICMP error.
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	require.NoError(t, gopacket.SerializeLayers(buf, opts,
+		&layers.IPv4{
+			Version:  4,
+			TTL:      64,
+			Flags:    layers.IPv4DontFragment,
+			Protocol: layers.IPProtocolICMPv4,
+			SrcIP:    net.IP{10, 0, 0, 1},
+			DstIP:    net.IP{10, 1, 0, 1},
+		},
+		&layers.ICMPv4{
+			TypeCode: layers.CreateICMPv4TypeCode(layers.ICMPv4TypeTimeExceeded, 0),
+		},
+		gopacket.Payload(make([]byte, 1500)),
+	))
+	packet := gopacket.NewPacket(buf.Bytes(), layers.LayerTypeIPv4, decodeOptions)
+	_, ok := packetTooBig(packet, 1400, nil, nil)
+	assert.False(t, ok)
+}
+
+func TestICMPRateLimiter(t *testing.T) {
+	var l icmpRateLimiter
+	now := time.Now()
+	for i := 0; i < packetTooBigRate; i++ {
+		require.True(t, l.allow(now))
+	}
+	assert.False(t, l.allow(now))
+	// Tokens are refilled with the rate.
+	now = now.Add(20 * time.Millisecond)
+	assert.True(t, l.allow(now))
+	assert.True(t, l.allow(now))
+	assert.False(t, l.allow(now))
+	// The bucket holds at most one second worth of tokens.
+	now = now.Add(time.Minute)
+	for i := 0; i < packetTooBigRate; i++ {
+		require.True(t, l.allow(now))
+	}
+	assert.False(t, l.allow(now))
+}
+
+func TestClampMSS(t *testing.T) {
+	testCases := map[string]struct {
+		IPv6        bool
+		SYN         bool
+		MSS         uint16
+		MTU         int
+		Clamped     bool
+		ExpectedMSS uint16
+	}{
+		"ipv4 syn": {
+			SYN:         true,
+			MSS:         1460,
+			MTU:         1400,
+			Clamped:     true,
+			ExpectedMSS: 1360,
+		},
+		"ipv6 syn": {
+			IPv6:        true,
+			SYN:         true,
+			MSS:         1440,
+			MTU:         1400,
+			Clamped:     true,
+			ExpectedMSS: 1340,
+		},
+		"ipv4 syn small mss": {
+			SYN:         true,
+			MSS:         1200,
+			MTU:         1400,
+			ExpectedMSS: 1200,
+		},
+		"ipv4 no syn": {
+			MSS:         1460,
+			MTU:         1400,
+			ExpectedMSS: 1460,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			data := tcpPacket(t, tc.IPv6, tc.SYN, tc.MSS)
+			packet := gopacket.NewPacket(data, firstLayer(data), decodeOptions)
+			assert.Equal(t, tc.Clamped, clampMSS(packet, tc.MTU))
+
+			// Decode the modified packet and check that the checksum is valid
+			// by comparing it with a freshly computed one.
+			decoded := gopacket.NewPacket(data, firstLayer(data), decodeOptions)
+			tcp := decoded.Layer(layers.LayerTypeTCP).(*layers.TCP)
+			var mss uint16
+			for _, opt := range tcp.Options {
+				if opt.OptionType == layers.TCPOptionKindMSS {
+					mss = binary.BigEndian.Uint16(opt.OptionData)
+				}
+			}
+			assert.Equal(t, tc.ExpectedMSS, mss)
+			expected := tcpPacket(t, tc.IPv6, tc.SYN, tc.ExpectedMSS)
+			assert.Equal(t, expected, data)
+		})
+	}
+}
+
+func firstLayer(data []byte) gopacket.LayerType {
+	if data[0]>>4 == 6 {
+		return layers.LayerTypeIPv6
+	}
+	return layers.LayerTypeIPv4
+}
+
+func ipv4Packet(t *testing.T, src, dst net.IP, flags layers.IPv4Flag,
+	length int) gopacket.Packet {
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	require.NoError(t, gopacket.SerializeLayers(buf, opts,
+		&layers.IPv4{
+			Version:  4,
+			TTL:      64,
+			Flags:    flags,
+			Protocol: layers.IPProtocolUDP,
+			SrcIP:    src,
+			DstIP:    dst,
+		},
+		gopacket.Payload(make([]byte, length-20)),
+	))
+	return gopacket.NewPacket(buf.Bytes(), layers.LayerTypeIPv4, decodeOptions)
+}
+
+func ipv6Packet(t *testing.T, src, dst net.IP, length int) gopacket.Packet {
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	require.NoError(t, gopacket.SerializeLayers(buf, opts,
+		&layers.IPv6{
+			Version:    6,
+			HopLimit:   64,
+			NextHeader: layers.IPProtocolUDP,
+			SrcIP:      src,
+			DstIP:      dst,
+		},
+		gopacket.Payload(make([]byte, length-40)),
+	))
+	return gopacket.NewPacket(buf.Bytes(), layers.LayerTypeIPv6, decodeOptions)
+}
+
+func tcpPacket(t *testing.T, ipv6, syn bool, mss uint16) []byte {
+	var ip gopacket.NetworkLayer
+	if ipv6 {
+		ip = &layers.IPv6{
+			Version:    6,
+			HopLimit:   64,
+			NextHeader: layers.IPProtocolTCP,
+			SrcIP:      net.ParseIP("fd00::1"),
+			DstIP:      net.ParseIP("fd01::1"),
+		}
+	} else {
+		ip = &layers.IPv4{
+			Version:  4,
+			TTL:      64,
+			Protocol: layers.IPProtocolTCP,
+			SrcIP:    net.IP{10, 0, 0, 1},
+			DstIP:    net.IP{10, 1, 0, 1},
+		}
+	}
+	mssData := make([]byte, 2)
+	binary.BigEndian.PutUint16(mssData, mss)
+	tcp := &layers.TCP{
+		SrcPort: 40000,
+		DstPort: 80,
+		Seq:     1,
+		SYN:     syn,
+		ACK:     !syn,
+		Window:  65535,
+		// The NOP shifts the MSS option to an odd offset.
+		Options: []layers.TCPOption{
+			{OptionType: layers.TCPOptionKindNop},
+			{OptionType: layers.TCPOptionKindMSS, OptionLength: 4, OptionData: mssData},
+			{OptionType: layers.TCPOptionKindNop},
+			{OptionType: layers.TCPOptionKindNop},
+			{OptionType: layers.TCPOptionKindNop},
+		},
+	}
+	require.NoError(t, tcp.SetNetworkLayerForChecksum(ip))
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	require.NoError(t, gopacket.SerializeLayers(buf, opts,
+		ip.(gopacket.SerializableLayer), tcp, gopacket.Payload([]byte("hello"))))
+	return buf.Bytes()
+}
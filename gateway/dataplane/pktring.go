@@ -21,6 +21,42 @@ const (
 	ringSize  = 64
 )
 
+// pktQueue passes packets from the writer goroutine to the sending goroutine.
+type pktQueue interface {
+	// Write writes one packet of the given QoS class to the queue without
+	// blocking. Returns 1 if successful, 0 if the packet was dropped or -1 if
+	// the queue was closed.
+	Write(pkt []byte, class int) int
+	// Read returns next packet from the queue.
+	// Returns 1 if successful, 0 if the call would block or -1 if the queue
+	// was closed.
+	Read(block bool) ([]byte, int)
+	// Close closes the queue. The queued packets can still be read.
+	Close()
+}
+
+// fifoQueue is a packet queue that ignores the QoS classes and sends the
+// packets in FIFO order.
+type fifoQueue struct {
+	ring *pktRing
+}
+
+func newFIFOQueue() fifoQueue {
+	return fifoQueue{ring: newPktRing()}
+}
+
+func (q fifoQueue) Write(pkt []byte, _ int) int {
+	return q.ring.Write(pkt, false)
+}
+
+func (q fifoQueue) Read(block bool) ([]byte, int) {
+	return q.ring.Read(block)
+}
+
+func (q fifoQueue) Close() {
+	q.ring.Close()
+}
+
 // pktRing reads entries from a ringbuffer in batches but hands them to
 // the client one by one.
 type pktRing struct {
@@ -0,0 +1,435 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/pkg/log"
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/snet"
+)
+
+// Protected SIG frames carry version 1 in the SIG frame header and have the
+// following format:
+//
+//  +----------------------+----------+------------------------+------------+
+//  | SIG frame header     | Salt     | Encrypted payload      | Tag        |
+//  | (16 bytes)           | (8 bytes)| (variable)             | (16 bytes) |
+//  +----------------------+----------+------------------------+------------+
+//
+// The payload is encrypted with AES-GCM. The 16-byte nonce is the
+// concatenation of the salt, which is chosen randomly by every sender, and the
+// sequence number from the frame header. The frame header and the salt are
+// authenticated as additional data.
+//
+// All senders to the same remote gateway share the key, and the sequence
+// numbers of a sender restart at zero. Thus, the nonces of two senders only
+// differ in the salt. The salt is 64 bits long, such that a collision is not
+// to be expected within the lifetime of a key.
+
+const (
+	// frameVersionPlain is the SIG frame version of unprotected frames.
+	frameVersionPlain = 0
+	// frameVersionProtected is the SIG frame version of protected frames.
+	frameVersionProtected = 1
+	// saltLen is the length of the nonce salt following the frame header.
+	saltLen = 8
+	// nonceLen is the length of the AES-GCM nonce.
+	nonceLen = saltLen + 8
+	// tagLen is the length of the AES-GCM authentication tag.
+	tagLen = 16
+	// protectionOverhead is the number of bytes protection adds to a frame.
+	protectionOverhead = saltLen + tagLen
+	// frameKeyGracePeriod is the time around key epoch boundaries during which
+	// the keys of the adjacent epochs are also accepted. It accounts for clock
+	// skew between the gateways and for frames in flight.
+	frameKeyGracePeriod = 10 * time.Second
+	// keyRetryInterval is the time after which a failed key fetch is retried.
+	keyRetryInterval = time.Second
+	// replayWindowSize is the number of sequence numbers tracked per stream
+	// and sender to detect replayed frames.
+	replayWindowSize = 1024
+)
+
+// FrameKey is a key used to protect the frames sent in one direction between
+// two gateways.
+type FrameKey struct {
+	// Key is the AES-128 key.
+	Key [16]byte
+	// NotBefore is the start of the validity period of the key.
+	NotBefore time.Time
+	// NotAfter is the end of the validity period of the key.
+	NotAfter time.Time
+}
+
+// FrameKeyProvider provides the keys that protect the frames exchanged with
+// remote gateways. The provider is bound to the local gateway. The egress key
+// of one gateway must be the ingress key of its peer.
+type FrameKeyProvider interface {
+	// EgressKey returns the key protecting the frames sent to the remote
+	// gateway at time t.
+	EgressKey(ctx context.Context, remote *snet.UDPAddr, t time.Time) (FrameKey, error)
+	// IngressKey returns the key protecting the frames received from the
+	// remote gateway at time t.
+	IngressKey(ctx context.Context, remote *snet.UDPAddr, t time.Time) (FrameKey, error)
+}
+
+// frameSealer protects the frames of a single sender.
+type frameSealer struct {
+	keys   FrameKeyProvider
+	remote *snet.UDPAddr
+	salt   [saltLen]byte
+	key    FrameKey
+	aead   cipher.AEAD
+	// aad is the additional data, i.e., the frame header and the salt. It is
+	// kept separate from buf, because the additional data must not overlap
+	// with the output of the cipher.
+	aad [hdrLen + saltLen]byte
+	buf []byte
+	// retryAfter is the earliest time at which a failed key fetch is retried.
+	retryAfter time.Time
+}
+
+func newFrameSealer(keys FrameKeyProvider, remote *snet.UDPAddr, mtu int) (*frameSealer, error) {
+	s := &frameSealer{
+		keys:   keys,
+		remote: remote,
+		buf:    make([]byte, 0, mtu),
+	}
+	if _, err := rand.Read(s.salt[:]); err != nil {
+		return nil, serrors.WrapStr("generating nonce salt", err)
+	}
+	return s, nil
+}
+
+// Seal returns the protected version of the frame. The returned buffer is
+// only valid until the next call to Seal.
+func (s *frameSealer) Seal(ctx context.Context, frame []byte, now time.Time) ([]byte, error) {
+	if s.aead == nil || now.After(s.key.NotAfter) || now.Before(s.key.NotBefore) {
+		// Do not hammer the key provider if it fails, drop the frames instead.
+		if now.Before(s.retryAfter) {
+			return nil, errNoKey
+		}
+		key, err := s.keys.EgressKey(ctx, s.remote, now)
+		if err != nil {
+			s.aead = nil
+			s.retryAfter = now.Add(keyRetryInterval)
+			return nil, serrors.WrapStr("fetching egress key", err)
+		}
+		aead, err := newFrameAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		s.key, s.aead = key, aead
+	}
+	copy(s.aad[:], frame[:hdrLen])
+	s.aad[versionPos] = frameVersionProtected
+	copy(s.aad[hdrLen:], s.salt[:])
+	s.buf = append(s.buf[:0], s.aad[:]...)
+	nonce := frameNonce(s.aad[:])
+	return s.aead.Seal(s.buf, nonce[:], frame[hdrLen:], s.aad[:]), nil
+}
+
+// frameOpener verifies and decrypts the protected frames received from a
+// single remote gateway.
+//
+// The ingress keys are fetched in the background, such that frames do not
+// block the worker while a key is fetched. Frames that need a key that is not
+// available yet are discarded. At most one key is fetched at a time, and a
+// failed fetch is only retried after keyRetryInterval.
+type frameOpener struct {
+	keys   FrameKeyProvider
+	remote *snet.UDPAddr
+	// epochs caches the ciphers per key epoch, indexed by the start of the
+	// epoch. It is only accessed by the worker.
+	epochs map[time.Time]*frameEpoch
+	// buf is the buffer frames are decrypted into.
+	buf []byte
+
+	mtx sync.Mutex
+	// fetching is set while a key is fetched.
+	fetching bool
+	// fetched contains the keys that were fetched, but not yet added to
+	// epochs.
+	fetched []FrameKey
+	// retryAfter contains the earliest time at which a failed fetch is
+	// retried, indexed by the time slot of the fetch. See keySlot.
+	retryAfter map[time.Time]time.Time
+	// fetches tracks the running fetches; tests can wait for them.
+	fetches sync.WaitGroup
+}
+
+// frameEpoch is the state of a single key epoch.
+type frameEpoch struct {
+	key  FrameKey
+	aead cipher.AEAD
+	// windows tracks the received sequence numbers per stream and sender. A
+	// window is only created once a frame was authenticated with the key of
+	// the epoch, and it is kept as long as the key is accepted. Thus, frames
+	// cannot be replayed while they can still be authenticated.
+	windows map[windowKey]*replayWindow
+}
+
+// windowKey identifies the frames of a single stream of a single sender.
+type windowKey struct {
+	stream uint32
+	salt   [saltLen]byte
+}
+
+func newFrameOpener(keys FrameKeyProvider, remote *snet.UDPAddr) *frameOpener {
+	return &frameOpener{
+		keys:       keys,
+		remote:     remote,
+		epochs:     make(map[time.Time]*frameEpoch),
+		buf:        make([]byte, 0, frameBufCap),
+		retryAfter: make(map[time.Time]time.Time),
+	}
+}
+
+var (
+	errNoKey           = serrors.New("no key available")
+	errFrameTooShort   = serrors.New("protected frame too short")
+	errReplayed        = serrors.New("replayed frame")
+	errUnauthenticated = serrors.New("frame authentication failed")
+)
+
+// Open verifies and decrypts the protected frame in place. It returns the
+// length of the resulting unprotected frame. If a key that could authenticate
+// the frame is not available, the fetch of the key is started, and an error
+// that wraps errNoKey is returned.
+func (o *frameOpener) Open(ctx context.Context, frame []byte, now time.Time) (int, error) {
+	if len(frame) < hdrLen+protectionOverhead {
+		return 0, errFrameTooShort
+	}
+	o.addFetched()
+	aad := frame[:hdrLen+saltLen]
+	seq := binary.BigEndian.Uint64(frame[seqPos : seqPos+8])
+	wk := windowKey{stream: binary.BigEndian.Uint32(frame[streamPos:streamPos+4]) & 0xfffff}
+	copy(wk.salt[:], frame[hdrLen:])
+	nonce := frameNonce(aad)
+	ciphertext := frame[hdrLen+saltLen:]
+	var tried []*frameEpoch
+	var keyErr error
+	replayed := false
+	for _, t := range []time.Time{now, now.Add(-frameKeyGracePeriod),
+		now.Add(frameKeyGracePeriod)} {
+
+		epoch, err := o.epoch(ctx, t, now)
+		if err != nil {
+			keyErr = err
+			continue
+		}
+		if containsEpoch(tried, epoch) {
+			continue
+		}
+		tried = append(tried, epoch)
+		// Check for replays before decrypting, such that replayed frames are
+		// discarded cheaply.
+		window := epoch.windows[wk]
+		if window != nil && !window.Check(seq) {
+			replayed = true
+			continue
+		}
+		// Decrypt into a separate buffer, the output is cleared if the
+		// authentication fails.
+		plaintext, err := epoch.aead.Open(o.buf[:0], nonce[:], ciphertext, aad)
+		if err != nil {
+			continue
+		}
+		if window == nil {
+			window = &replayWindow{}
+			epoch.windows[wk] = window
+		}
+		window.Update(seq)
+		frame[versionPos] = frameVersionPlain
+		copy(frame[hdrLen:], plaintext)
+		return hdrLen + len(plaintext), nil
+	}
+	switch {
+	case replayed:
+		return 0, errReplayed
+	case keyErr != nil:
+		// The frame might be authenticated by the missing key.
+		return 0, keyErr
+	default:
+		return 0, errUnauthenticated
+	}
+}
+
+func containsEpoch(epochs []*frameEpoch, epoch *frameEpoch) bool {
+	for _, e := range epochs {
+		if e == epoch {
+			return true
+		}
+	}
+	return false
+}
+
+// epoch returns the state of the key epoch containing t. If the key is not
+// available, its fetch is started and an error is returned.
+func (o *frameOpener) epoch(ctx context.Context, t, now time.Time) (*frameEpoch, error) {
+	for _, e := range o.epochs {
+		if !t.Before(e.key.NotBefore) && !t.After(e.key.NotAfter) {
+			return e, nil
+		}
+	}
+	o.fetch(ctx, t, now)
+	return nil, serrors.WithCtx(errNoKey, "time", t)
+}
+
+// fetch starts fetching the key for time t in the background, unless a fetch
+// is already running or a fetch for the same time slot recently failed.
+func (o *frameOpener) fetch(ctx context.Context, t, now time.Time) {
+	slot := keySlot(t)
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	if o.fetching || now.Before(o.retryAfter[slot]) {
+		return
+	}
+	o.fetching = true
+	o.fetches.Add(1)
+	go func() {
+		defer log.HandlePanic()
+		defer o.fetches.Done()
+		key, err := o.keys.IngressKey(ctx, o.remote, t)
+		if err == nil {
+			// Check the key before it is handed to the worker.
+			_, err = newFrameAEAD(key)
+		}
+		o.mtx.Lock()
+		defer o.mtx.Unlock()
+		o.fetching = false
+		if err != nil {
+			log.FromCtx(ctx).Debug("Fetching ingress key failed", "remote", o.remote,
+				"time", t, "err", err)
+			o.retryAfter[slot] = now.Add(keyRetryInterval)
+			return
+		}
+		delete(o.retryAfter, slot)
+		o.fetched = append(o.fetched, key)
+	}()
+}
+
+// addFetched adds the keys that were fetched in the background to the
+// epochs.
+func (o *frameOpener) addFetched() {
+	o.mtx.Lock()
+	fetched := o.fetched
+	o.fetched = nil
+	o.mtx.Unlock()
+	for _, key := range fetched {
+		if _, ok := o.epochs[key.NotBefore]; ok {
+			continue
+		}
+		// The key was already checked when it was fetched.
+		aead, _ := newFrameAEAD(key)
+		o.epochs[key.NotBefore] = &frameEpoch{
+			key:     key,
+			aead:    aead,
+			windows: make(map[windowKey]*replayWindow),
+		}
+	}
+}
+
+// keySlot returns the time slot that identifies fetches for the key at time t.
+// The slots are as long as the grace period, such that the fetches of the keys
+// that are tried for a frame are backed off independently.
+func keySlot(t time.Time) time.Time {
+	return t.Truncate(frameKeyGracePeriod)
+}
+
+// cleanup removes the expired keys together with their replay windows. Frames
+// protected with an expired key are no longer accepted, hence the windows are
+// not needed anymore.
+func (o *frameOpener) cleanup(now time.Time) {
+	for notBefore, e := range o.epochs {
+		if now.Add(-frameKeyGracePeriod).After(e.key.NotAfter) {
+			delete(o.epochs, notBefore)
+		}
+	}
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	for slot, retryAfter := range o.retryAfter {
+		if now.After(retryAfter) {
+			delete(o.retryAfter, slot)
+		}
+	}
+}
+
+func newFrameAEAD(key FrameKey) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key.Key[:])
+	if err != nil {
+		return nil, serrors.WrapStr("creating block cipher", err)
+	}
+	aead, err := cipher.NewGCMWithNonceSize(block, nonceLen)
+	if err != nil {
+		return nil, serrors.WrapStr("creating AEAD", err)
+	}
+	return aead, nil
+}
+
+// frameNonce returns the nonce for a frame, given its header and salt.
+func frameNonce(hdr []byte) [nonceLen]byte {
+	var nonce [nonceLen]byte
+	copy(nonce[:saltLen], hdr[hdrLen:hdrLen+saltLen])
+	copy(nonce[saltLen:], hdr[seqPos:seqPos+8])
+	return nonce
+}
+
+// replayWindow keeps track of the sequence numbers received on a stream. It
+// rejects sequence numbers that were already seen or that are too old to be
+// tracked.
+type replayWindow struct {
+	initialized bool
+	top         uint64
+	bitmap      [replayWindowSize / 64]uint64
+}
+
+// Check returns whether the sequence number is acceptable. It does not record
+// the sequence number; Update must be called once the frame is authenticated.
+func (w *replayWindow) Check(seq uint64) bool {
+	if !w.initialized || seq > w.top {
+		return true
+	}
+	if w.top-seq >= replayWindowSize-64 {
+		return false
+	}
+	block := (seq / 64) % uint64(len(w.bitmap))
+	return w.bitmap[block]&(1<<(seq%64)) == 0
+}
+
+// Update records the sequence number.
+func (w *replayWindow) Update(seq uint64) {
+	if !w.initialized || seq > w.top {
+		if w.initialized {
+			// Clear the blocks that are shifted into the window.
+			current, next := w.top/64, seq/64
+			for i := current + 1; i <= next && i <= current+uint64(len(w.bitmap)); i++ {
+				w.bitmap[i%uint64(len(w.bitmap))] = 0
+			}
+		}
+		w.initialized = true
+		w.top = seq
+	}
+	block := (seq / 64) % uint64(len(w.bitmap))
+	w.bitmap[block] |= 1 << (seq % 64)
+}
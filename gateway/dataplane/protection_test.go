@@ -0,0 +1,364 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/pkg/snet"
+)
+
+// epochKeys hands out a different key for every epoch of one hour. The same
+// key is used in both directions.
+type epochKeys struct {
+	fail bool
+}
+
+func (k epochKeys) EgressKey(_ context.Context, _ *snet.UDPAddr,
+	t time.Time) (FrameKey, error) {
+
+	return k.key(t)
+}
+
+func (k epochKeys) IngressKey(_ context.Context, _ *snet.UDPAddr,
+	t time.Time) (FrameKey, error) {
+
+	return k.key(t)
+}
+
+func (k epochKeys) key(t time.Time) (FrameKey, error) {
+	if k.fail {
+		return FrameKey{}, serrors.New("no key")
+	}
+	notBefore := t.Truncate(time.Hour)
+	key := FrameKey{
+		NotBefore: notBefore,
+		NotAfter:  notBefore.Add(time.Hour - time.Nanosecond),
+	}
+	key.Key[0] = byte(notBefore.Hour())
+	return key, nil
+}
+
+func testFrame(seq byte) []byte {
+	return []byte{
+		// SIG frame header.
+		0, 1, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, seq,
+		// IPv4 header.
+		0x40, 0, 0, 23, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		// Payload.
+		101, 102, 103,
+	}
+}
+
+func testRemote() *snet.UDPAddr {
+	return &snet.UDPAddr{
+		IA:   xtest.MustParseIA("1-ff00:0:300"),
+		Host: &net.UDPAddr{IP: net.IP{192, 168, 1, 1}, Port: 80},
+	}
+}
+
+func seal(t *testing.T, s *frameSealer, frame []byte, now time.Time) []byte {
+	sealed, err := s.Seal(context.Background(), frame, now)
+	require.NoError(t, err)
+	return append([]byte(nil), sealed...)
+}
+
+// open opens the frame, and waits for the keys to be fetched if necessary.
+func open(o *frameOpener, frame []byte, now time.Time) (int, error) {
+	for i := 0; ; i++ {
+		n, err := o.Open(context.Background(), frame, now)
+		if !errors.Is(err, errNoKey) || i == 3 {
+			return n, err
+		}
+		o.fetches.Wait()
+	}
+}
+
+// countingKeys counts the fetched keys. The fetches block until release is
+// closed, and fail if fail is set.
+type countingKeys struct {
+	epochKeys
+	mtx     sync.Mutex
+	count   int
+	release chan struct{}
+}
+
+func (k *countingKeys) IngressKey(ctx context.Context, remote *snet.UDPAddr,
+	t time.Time) (FrameKey, error) {
+
+	k.mtx.Lock()
+	k.count++
+	k.mtx.Unlock()
+	<-k.release
+	return k.epochKeys.IngressKey(ctx, remote, t)
+}
+
+func (k *countingKeys) fetched() int {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+	return k.count
+}
+
+func TestFrameProtection(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 30, 0, 0, time.UTC)
+
+	t.Run("round trip", func(t *testing.T) {
+		sealer, err := newFrameSealer(epochKeys{}, testRemote(), 1000)
+		require.NoError(t, err)
+		opener := newFrameOpener(epochKeys{}, testRemote())
+
+		sealed := seal(t, sealer, testFrame(1), now)
+		assert.Equal(t, uint8(frameVersionProtected), sealed[versionPos])
+		assert.Len(t, sealed, len(testFrame(1))+protectionOverhead)
+		assert.NotContains(t, string(sealed), string([]byte{101, 102, 103}))
+
+		n, err := open(opener, sealed, now)
+		require.NoError(t, err)
+		assert.Equal(t, testFrame(1), sealed[:n])
+	})
+	t.Run("replay", func(t *testing.T) {
+		sealer, err := newFrameSealer(epochKeys{}, testRemote(), 1000)
+		require.NoError(t, err)
+		opener := newFrameOpener(epochKeys{}, testRemote())
+
+		sealed := seal(t, sealer, testFrame(1), now)
+		replayed := append([]byte(nil), sealed...)
+		_, err = open(opener, sealed, now)
+		require.NoError(t, err)
+		_, err = open(opener, replayed, now)
+		assert.ErrorIs(t, err, errReplayed)
+	})
+	t.Run("replay after cleanup", func(t *testing.T) {
+		sealer, err := newFrameSealer(epochKeys{}, testRemote(), 1000)
+		require.NoError(t, err)
+		opener := newFrameOpener(epochKeys{}, testRemote())
+
+		sealed := seal(t, sealer, testFrame(1), now)
+		replayed := append([]byte(nil), sealed...)
+		_, err = open(opener, sealed, now)
+		require.NoError(t, err)
+		// The replay windows are kept as long as the key is accepted, no
+		// matter how long the stream is idle.
+		for i := 1; i <= 3; i++ {
+			opener.cleanup(now.Add(time.Duration(i) * time.Minute))
+		}
+		_, err = open(opener, replayed, now.Add(3*time.Minute))
+		assert.ErrorIs(t, err, errReplayed)
+
+		// Once the key expired, the windows are removed, but the frame is not
+		// accepted anymore either.
+		later := now.Add(time.Hour)
+		opener.cleanup(later)
+		assert.Len(t, opener.epochs, 0)
+		_, err = open(opener, replayed, later)
+		assert.ErrorIs(t, err, errUnauthenticated)
+	})
+	t.Run("unauthenticated frames create no state", func(t *testing.T) {
+		opener := newFrameOpener(epochKeys{}, testRemote())
+		forged := append(testFrame(1), make([]byte, protectionOverhead)...)
+		forged[versionPos] = frameVersionProtected
+		for salt := byte(0); salt < 10; salt++ {
+			forged[hdrLen] = salt
+			_, err := open(opener, forged, now)
+			assert.ErrorIs(t, err, errUnauthenticated)
+		}
+		for _, epoch := range opener.epochs {
+			assert.Empty(t, epoch.windows)
+		}
+	})
+	t.Run("tampered header", func(t *testing.T) {
+		sealer, err := newFrameSealer(epochKeys{}, testRemote(), 1000)
+		require.NoError(t, err)
+		opener := newFrameOpener(epochKeys{}, testRemote())
+
+		sealed := seal(t, sealer, testFrame(1), now)
+		// Change the session ID.
+		sealed[sessPos] = 2
+		_, err = open(opener, sealed, now)
+		assert.ErrorIs(t, err, errUnauthenticated)
+	})
+	t.Run("tampered payload", func(t *testing.T) {
+		sealer, err := newFrameSealer(epochKeys{}, testRemote(), 1000)
+		require.NoError(t, err)
+		opener := newFrameOpener(epochKeys{}, testRemote())
+
+		sealed := seal(t, sealer, testFrame(1), now)
+		sealed[hdrLen+saltLen] ^= 1
+		_, err = open(opener, sealed, now)
+		assert.ErrorIs(t, err, errUnauthenticated)
+
+		// The failed attempt must not have been recorded as seen.
+		sealed[hdrLen+saltLen] ^= 1
+		_, err = open(opener, sealed, now)
+		assert.NoError(t, err)
+	})
+	t.Run("too short", func(t *testing.T) {
+		opener := newFrameOpener(epochKeys{}, testRemote())
+		_, err := open(opener, make([]byte, hdrLen), now)
+		assert.ErrorIs(t, err, errFrameTooShort)
+	})
+	t.Run("previous epoch within grace period", func(t *testing.T) {
+		sealer, err := newFrameSealer(epochKeys{}, testRemote(), 1000)
+		require.NoError(t, err)
+		opener := newFrameOpener(epochKeys{}, testRemote())
+
+		epochEnd := now.Truncate(time.Hour).Add(time.Hour)
+		sealed := seal(t, sealer, testFrame(1), epochEnd.Add(-time.Second))
+		_, err = open(opener, sealed, epochEnd.Add(time.Second))
+		assert.NoError(t, err)
+
+		sealed = seal(t, sealer, testFrame(2), epochEnd.Add(-time.Minute))
+		_, err = open(opener, sealed, epochEnd.Add(time.Minute))
+		assert.ErrorIs(t, err, errUnauthenticated)
+	})
+	t.Run("keys are fetched in the background", func(t *testing.T) {
+		sealer, err := newFrameSealer(epochKeys{}, testRemote(), 1000)
+		require.NoError(t, err)
+		keys := &countingKeys{release: make(chan struct{})}
+		opener := newFrameOpener(keys, testRemote())
+
+		sealed := seal(t, sealer, testFrame(1), now)
+		for i := 0; i < 10; i++ {
+			_, err = opener.Open(context.Background(), sealed, now)
+			assert.ErrorIs(t, err, errNoKey)
+		}
+		close(keys.release)
+		opener.fetches.Wait()
+		assert.Equal(t, 1, keys.fetched())
+		_, err = open(opener, sealed, now)
+		assert.NoError(t, err)
+	})
+	t.Run("failed fetches are retried after an interval", func(t *testing.T) {
+		keys := &countingKeys{epochKeys: epochKeys{fail: true}, release: make(chan struct{})}
+		close(keys.release)
+		opener := newFrameOpener(keys, testRemote())
+
+		frame := append(testFrame(1), make([]byte, protectionOverhead)...)
+		frame[versionPos] = frameVersionProtected
+		for i := 0; i < 10; i++ {
+			_, err := open(opener, frame, now)
+			assert.ErrorIs(t, err, errNoKey)
+		}
+		// One fetch for each of the tried epochs.
+		assert.Equal(t, 3, keys.fetched())
+
+		later := now.Add(keyRetryInterval + time.Millisecond)
+		opener.cleanup(later)
+		_, err := open(opener, frame, later)
+		assert.ErrorIs(t, err, errNoKey)
+		assert.Equal(t, 6, keys.fetched())
+	})
+	t.Run("no key", func(t *testing.T) {
+		sealer, err := newFrameSealer(epochKeys{fail: true}, testRemote(), 1000)
+		require.NoError(t, err)
+		_, err = sealer.Seal(context.Background(), testFrame(1), now)
+		assert.Error(t, err)
+		_, err = sealer.Seal(context.Background(), testFrame(2), now)
+		assert.ErrorIs(t, err, errNoKey)
+	})
+}
+
+func TestReplayWindow(t *testing.T) {
+	var w replayWindow
+	accept := func(seq uint64) bool {
+		if !w.Check(seq) {
+			return false
+		}
+		w.Update(seq)
+		return true
+	}
+	assert.True(t, accept(0))
+	assert.False(t, accept(0))
+	assert.True(t, accept(10))
+	// Reordered frames within the window are accepted once.
+	assert.True(t, accept(5))
+	assert.False(t, accept(5))
+	assert.True(t, accept(2000))
+	// Frames that fall out of the window are rejected.
+	assert.False(t, accept(10))
+	assert.True(t, accept(1990))
+	assert.False(t, accept(1990))
+	// Skipping far ahead clears the window.
+	assert.True(t, accept(100000))
+	assert.True(t, accept(99999))
+}
+
+func TestWorkerProtectedFrames(t *testing.T) {
+	now := time.Now()
+	mt := &MockTun{}
+	w := newWorker(testRemote(), 1, mt, IngressMetrics{})
+	w.opener = newFrameOpener(epochKeys{}, testRemote())
+	sealer, err := newFrameSealer(epochKeys{}, testRemote(), 1000)
+	require.NoError(t, err)
+
+	// The first frame triggers the fetch of the key, but is discarded.
+	SendFrame(t, w, seal(t, sealer, testFrame(0), now))
+	mt.AssertDone(t)
+	w.opener.fetches.Wait()
+
+	sealed := seal(t, sealer, testFrame(1), now)
+	SendFrame(t, w, sealed)
+	mt.AssertPacket(t, testFrame(1)[hdrLen:])
+	mt.AssertDone(t)
+
+	// The replayed frame is discarded.
+	SendFrame(t, w, sealed)
+	mt.AssertDone(t)
+
+	// Protected frames are discarded if the worker cannot verify them.
+	w = newWorker(testRemote(), 1, mt, IngressMetrics{})
+	SendFrame(t, w, seal(t, sealer, testFrame(2), now))
+	mt.AssertDone(t)
+}
+
+func TestIngressServerVersionSupported(t *testing.T) {
+	testCases := map[string]struct {
+		Server    IngressServer
+		Plain     bool
+		Protected bool
+	}{
+		"disabled": {
+			Server: IngressServer{},
+			Plain:  true,
+		},
+		"enabled": {
+			Server:    IngressServer{FrameKeys: epochKeys{}},
+			Plain:     true,
+			Protected: true,
+		},
+		"required": {
+			Server:    IngressServer{FrameKeys: epochKeys{}, RequireProtection: true},
+			Protected: true,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.Plain, tc.Server.versionSupported(frameVersionPlain))
+			assert.Equal(t, tc.Protected, tc.Server.versionSupported(frameVersionProtected))
+			assert.False(t, tc.Server.versionSupported(2))
+		})
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/pkg/metrics"
+)
+
+const (
+	// qosQuantum is the number of bytes a QoS class with weight 1 may send
+	// per round of the deficit round robin scheduler.
+	qosQuantum = 1500
+	// qosBurst is the duration for which a shaped QoS class may accumulate
+	// tokens.
+	qosBurst = 10 * time.Millisecond
+	// qosMinBurst is the minimum token bucket size of a shaped QoS class, in
+	// bytes.
+	qosMinBurst = 1500
+)
+
+// qosClass is the configuration of the egress queue of a QoS class.
+type qosClass struct {
+	// priority of the class. Classes with a higher priority are served first.
+	priority int
+	// weight of the class among the classes with the same priority.
+	weight int
+	// shaper limits the sending rate of the class. It is shared by the queues
+	// of all paths of a session, so that the rate applies to the session as a
+	// whole. If nil, the class is not shaped.
+	shaper *qosShaper
+	// dropped counts the packets that are dropped because the queue is full.
+	// If nil, the metric is not reported.
+	dropped metrics.Counter
+}
+
+// qosShaper is a token bucket that limits the sending rate of a QoS class. It
+// is safe for concurrent use by multiple queues.
+type qosShaper struct {
+	mtx sync.Mutex
+	// rate is the shaping rate in bytes per second.
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// newQoSShaper creates a shaper for the rate in bytes per second.
+func newQoSShaper(rate float64) *qosShaper {
+	return &qosShaper{rate: rate}
+}
+
+// conforms refills the token bucket and returns whether the class may send.
+// The tokens may become negative when a packet is sent, so that packets larger
+// than the bucket are sent eventually.
+func (s *qosShaper) conforms(now time.Time) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.last.IsZero() {
+		s.last = now
+	}
+	burst := math.Max(s.rate*qosBurst.Seconds(), qosMinBurst)
+	if now.After(s.last) {
+		s.tokens = math.Min(burst, s.tokens+s.rate*now.Sub(s.last).Seconds())
+		s.last = now
+	}
+	return s.tokens >= 0
+}
+
+// consume takes the tokens for a packet of the given length.
+func (s *qosShaper) consume(length int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.tokens -= float64(length)
+}
+
+// wait returns the duration until the class may send again.
+func (s *qosShaper) wait() time.Duration {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return time.Duration(-s.tokens / s.rate * float64(time.Second))
+}
+
+// classQueue is the egress queue of a QoS class.
+type classQueue struct {
+	qosClass
+	// pkts is the ring of queued packets. start is the index of the oldest
+	// packet and n the number of queued packets.
+	pkts  [ringSize][]byte
+	start int
+	n     int
+	// deficit is the number of bytes the class may send in the current round.
+	deficit int
+}
+
+func (c *classQueue) push(pkt []byte) bool {
+	if c.n == len(c.pkts) {
+		return false
+	}
+	c.pkts[(c.start+c.n)%len(c.pkts)] = pkt
+	c.n++
+	return true
+}
+
+func (c *classQueue) peek() []byte {
+	return c.pkts[c.start]
+}
+
+func (c *classQueue) pop() []byte {
+	pkt := c.pkts[c.start]
+	c.pkts[c.start] = nil
+	c.start = (c.start + 1) % len(c.pkts)
+	c.n--
+	return pkt
+}
+
+// conforms returns whether the class may send.
+func (c *classQueue) conforms(now time.Time) bool {
+	return c.shaper == nil || c.shaper.conforms(now)
+}
+
+// qosQueue passes packets from the writer goroutine to the sending goroutine.
+// Each QoS class has its own queue. The classes are served in strict priority
+// order, the classes with the same priority are served by deficit round
+// robin according to their weights. Shaped classes are only served as long as
+// they do not exceed their rate.
+type qosQueue struct {
+	mtx sync.Mutex
+	// classes are the queues indexed by the class.
+	classes []*classQueue
+	// groups contains the classes with the same priority, ordered by
+	// descending priority.
+	groups [][]*classQueue
+	// next is the index of the class in each group that is served next.
+	next []int
+	// notify wakes up a blocked reader.
+	notify chan struct{}
+	closed bool
+	// now returns the current time. It can be overridden in tests.
+	now func() time.Time
+}
+
+// newQoSQueue creates a queue for the classes.
+func newQoSQueue(classes []qosClass) *qosQueue {
+	q := &qosQueue{
+		notify: make(chan struct{}, 1),
+		now:    time.Now,
+	}
+	byPriority := make(map[int][]*classQueue)
+	for _, class := range classes {
+		if class.weight <= 0 {
+			class.weight = 1
+		}
+		c := &classQueue{qosClass: class}
+		q.classes = append(q.classes, c)
+		byPriority[class.priority] = append(byPriority[class.priority], c)
+	}
+	priorities := make([]int, 0, len(byPriority))
+	for priority := range byPriority {
+		priorities = append(priorities, priority)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(priorities)))
+	for _, priority := range priorities {
+		q.groups = append(q.groups, byPriority[priority])
+	}
+	q.next = make([]int, len(q.groups))
+	return q
+}
+
+// Write adds the packet to the queue of the class. The packet is dropped if
+// the queue is full. Returns 1 if successful, 0 if the packet was dropped or
+// -1 if the queue was closed.
+func (q *qosQueue) Write(pkt []byte, class int) int {
+	q.mtx.Lock()
+	if q.closed {
+		q.mtx.Unlock()
+		return -1
+	}
+	if class < 0 || class >= len(q.classes) {
+		class = len(q.classes) - 1
+	}
+	c := q.classes[class]
+	if !c.push(pkt) {
+		q.mtx.Unlock()
+		metrics.CounterInc(c.dropped)
+		return 0
+	}
+	q.mtx.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return 1
+}
+
+// Read returns the next packet to send.
+// Returns 1 if successful, 0 if the call would block or -1 if the queue was
+// closed and all packets were read. After the queue is closed, the remaining
+// packets are returned without shaping.
+func (q *qosQueue) Read(block bool) ([]byte, int) {
+	for {
+		q.mtx.Lock()
+		pkt, wait := q.dequeue(q.now())
+		closed := q.closed
+		q.mtx.Unlock()
+
+		switch {
+		case pkt != nil:
+			return pkt, 1
+		case closed && wait == 0:
+			return nil, -1
+		case !block:
+			return nil, 0
+		}
+		if wait <= 0 {
+			<-q.notify
+			continue
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-q.notify:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// Close closes the queue. The queued packets can still be read.
+func (q *qosQueue) Close() {
+	q.mtx.Lock()
+	q.closed = true
+	q.mtx.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// dequeue returns the next packet. If there is none, it returns the duration
+// until a shaped class may send again, or 0 if all queues are empty.
+func (q *qosQueue) dequeue(now time.Time) ([]byte, time.Duration) {
+	var wait time.Duration
+	for i, group := range q.groups {
+		pkt, groupWait := q.dequeueGroup(i, group, now)
+		if pkt != nil {
+			return pkt, 0
+		}
+		if groupWait > 0 && (wait == 0 || groupWait < wait) {
+			wait = groupWait
+		}
+	}
+	return nil, wait
+}
+
+// dequeueGroup serves the classes of the group by deficit round robin. The
+// class that is served keeps its turn as long as its deficit allows it to
+// send. Each visit of a class that cannot send the next packet increases its
+// deficit, thus the loop terminates.
+func (q *qosQueue) dequeueGroup(index int, group []*classQueue,
+	now time.Time) ([]byte, time.Duration) {
+
+	for {
+		var eligible bool
+		var wait time.Duration
+		for range group {
+			c := group[q.next[index]]
+			if c.n == 0 {
+				c.deficit = 0
+				q.next[index] = (q.next[index] + 1) % len(group)
+				continue
+			}
+			if !q.closed && !c.conforms(now) {
+				if w := c.shaper.wait(); wait == 0 || w < wait {
+					wait = w
+				}
+				q.next[index] = (q.next[index] + 1) % len(group)
+				continue
+			}
+			eligible = true
+			if pkt := c.peek(); len(pkt) <= c.deficit {
+				c.pop()
+				c.deficit -= len(pkt)
+				if c.shaper != nil {
+					c.shaper.consume(len(pkt))
+				}
+				if c.n == 0 {
+					c.deficit = 0
+				}
+				return pkt, 0
+			}
+			c.deficit += c.weight * qosQuantum
+			q.next[index] = (q.next[index] + 1) % len(group)
+		}
+		if !eligible {
+			// Make sure that the waiting time is positive, the reader would
+			// otherwise not wake up.
+			if wait == 0 && hasPackets(group) {
+				wait = time.Millisecond
+			}
+			return nil, wait
+		}
+	}
+}
+
+func hasPackets(group []*classQueue) bool {
+	for _, c := range group {
+		if c.n > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/pkg/metrics"
+	"github.com/scionproto/scion/pkg/private/xtest"
+)
+
+func TestQoSQueuePriority(t *testing.T) {
+	q := newQoSQueue([]qosClass{{priority: 1}, {priority: 0}})
+	low, high := make([]byte, 100), make([]byte, 200)
+	require.Equal(t, 1, q.Write(low, 1))
+	require.Equal(t, 1, q.Write(high, 0))
+
+	pkt, n := q.Read(false)
+	require.Equal(t, 1, n)
+	assert.Len(t, pkt, len(high))
+	pkt, n = q.Read(false)
+	require.Equal(t, 1, n)
+	assert.Len(t, pkt, len(low))
+	_, n = q.Read(false)
+	assert.Equal(t, 0, n)
+}
+
+func TestQoSQueueWeights(t *testing.T) {
+	q := newQoSQueue([]qosClass{{weight: 3}, {weight: 1}})
+	for i := 0; i < ringSize; i++ {
+		for class := 0; class < 2; class++ {
+			pkt := make([]byte, 1000)
+			pkt[0] = byte(class)
+			require.Equal(t, 1, q.Write(pkt, class))
+		}
+	}
+	// The classes are served by deficit round robin. Thus, the shares are
+	// only exact over multiple rounds.
+	counts := make([]int, 2)
+	for i := 0; i < 40; i++ {
+		pkt, n := q.Read(false)
+		require.Equal(t, 1, n)
+		counts[pkt[0]]++
+	}
+	assert.InDelta(t, 30, counts[0], 2)
+	assert.InDelta(t, 10, counts[1], 2)
+}
+
+func TestQoSQueueWeightsBytes(t *testing.T) {
+	// The weights apply to bytes, not packets.
+	q := newQoSQueue([]qosClass{{weight: 1}, {weight: 1}})
+	for i := 0; i < ringSize; i++ {
+		large := make([]byte, 1000)
+		large[0] = 0
+		require.Equal(t, 1, q.Write(large, 0))
+		small := make([]byte, 250)
+		small[0] = 1
+		require.Equal(t, 1, q.Write(small, 1))
+	}
+	bytes := make([]int, 2)
+	for i := 0; i < 50; i++ {
+		pkt, n := q.Read(false)
+		require.Equal(t, 1, n)
+		bytes[pkt[0]] += len(pkt)
+	}
+	assert.InEpsilon(t, bytes[0], bytes[1], 0.2)
+}
+
+func TestQoSQueueShaping(t *testing.T) {
+	now := time.Unix(1000, 0)
+	// 8000 bit/s, i.e., 1000 bytes per second.
+	q := newQoSQueue([]qosClass{{priority: 1, shaper: newQoSShaper(1000)}, {priority: 0}})
+	q.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		require.Equal(t, 1, q.Write(make([]byte, 500), 0))
+	}
+	pkt, n := q.Read(false)
+	require.Equal(t, 1, n)
+	assert.Len(t, pkt, 500)
+
+	// The shaped class exceeds its rate and has to wait.
+	_, n = q.Read(false)
+	assert.Equal(t, 0, n)
+	_, wait := q.dequeue(now)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	// Classes with a lower priority are served in the meantime.
+	require.Equal(t, 1, q.Write(make([]byte, 100), 1))
+	pkt, n = q.Read(false)
+	require.Equal(t, 1, n)
+	assert.Len(t, pkt, 100)
+
+	now = now.Add(500 * time.Millisecond)
+	pkt, n = q.Read(false)
+	require.Equal(t, 1, n)
+	assert.Len(t, pkt, 500)
+
+	// After closing, the remaining packets are sent without shaping.
+	q.Close()
+	pkt, n = q.Read(false)
+	require.Equal(t, 1, n)
+	assert.Len(t, pkt, 500)
+	_, n = q.Read(true)
+	assert.Equal(t, -1, n)
+}
+
+func TestQoSQueueSharedShaper(t *testing.T) {
+	now := time.Unix(1000, 0)
+	// The queues of two paths share the rate of 1000 bytes per second.
+	shaper := newQoSShaper(1000)
+	a := newQoSQueue([]qosClass{{shaper: shaper}})
+	a.now = func() time.Time { return now }
+	b := newQoSQueue([]qosClass{{shaper: shaper}})
+	b.now = func() time.Time { return now }
+
+	require.Equal(t, 1, a.Write(make([]byte, 500), 0))
+	require.Equal(t, 1, b.Write(make([]byte, 500), 0))
+	_, n := a.Read(false)
+	require.Equal(t, 1, n)
+
+	// The other queue has to wait for the tokens used by the first one.
+	_, n = b.Read(false)
+	assert.Equal(t, 0, n)
+	_, wait := b.dequeue(now)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	now = now.Add(500 * time.Millisecond)
+	_, n = b.Read(false)
+	assert.Equal(t, 1, n)
+}
+
+func TestQoSQueueShapingBlocks(t *testing.T) {
+	// 800 kbit/s, i.e., 100 kB per second.
+	q := newQoSQueue([]qosClass{{shaper: newQoSShaper(100000)}})
+	require.Equal(t, 1, q.Write(make([]byte, 1000), 0))
+	require.Equal(t, 1, q.Write(make([]byte, 1000), 0))
+
+	_, n := q.Read(true)
+	require.Equal(t, 1, n)
+	start := time.Now()
+	_, n = q.Read(true)
+	require.Equal(t, 1, n)
+	assert.GreaterOrEqual(t, time.Since(start), 5*time.Millisecond)
+}
+
+func TestQoSQueueDrops(t *testing.T) {
+	dropped := metrics.NewTestCounter()
+	q := newQoSQueue([]qosClass{{dropped: dropped}, {}})
+	for i := 0; i < ringSize; i++ {
+		require.Equal(t, 1, q.Write([]byte{1}, 0))
+	}
+	assert.Equal(t, 0, q.Write([]byte{1}, 0))
+	assert.Equal(t, float64(1), metrics.CounterValue(dropped))
+	// Other classes are not affected.
+	assert.Equal(t, 1, q.Write([]byte{1}, 1))
+}
+
+func TestQoSQueueBlockingRead(t *testing.T) {
+	q := newQoSQueue([]qosClass{{}})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pkt, n := q.Read(true)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, []byte{1}, pkt)
+		_, n = q.Read(true)
+		assert.Equal(t, -1, n)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	require.Equal(t, 1, q.Write([]byte{1}, 0))
+	q.Close()
+	assert.Equal(t, -1, q.Write([]byte{2}, 0))
+	xtest.AssertReadReturnsBefore(t, done, time.Second)
+}
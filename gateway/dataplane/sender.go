@@ -15,7 +15,9 @@
 package dataplane
 
 import (
+	"context"
 	"net"
+	"time"
 
 	"github.com/scionproto/scion/pkg/addr"
 	"github.com/scionproto/scion/pkg/log"
@@ -42,11 +44,17 @@ type sender struct {
 	path               snet.Path
 	pathFingerprint    snet.PathFingerprint
 	metrics            SessionMetrics
+	// mtu is the size of the largest IP packet that fits into a single frame.
+	mtu int
+	// sealer protects the frames. If nil, the frames are sent unprotected.
+	sealer *frameSealer
 }
 
+// newSender creates a sender for the path. If QoS classes are given, the
+// packets are queued per class, otherwise in a single FIFO queue.
 func newSender(sessID uint8, conn net.PacketConn, path snet.Path,
 	gatewayAddr net.UDPAddr, pathStatsPublisher PathStatsPublisher,
-	metrics SessionMetrics) (*sender, error) {
+	metrics SessionMetrics, frameKeys FrameKeyProvider, classes []qosClass) (*sender, error) {
 
 	// MTU must account for the size of the SCION header.
 	localAddr := conn.LocalAddr().(*snet.UDPAddr)
@@ -57,12 +65,21 @@ func newSender(sessID uint8, conn net.PacketConn, path snet.Path,
 	}
 	pathLen := len(scionPath.Raw)
 	mtu := int(path.Metadata().MTU) - slayers.CmnHdrLen - addrLen - pathLen - udpHdrLen
+	frameMTU := mtu
+	if frameKeys != nil {
+		// The encoder must leave room for the salt and the authentication tag.
+		mtu -= protectionOverhead
+	}
 	if mtu < minMTU {
 		return nil, serrors.New("insufficient MTU", "mtu", mtu, "minMTU", minMTU)
 	}
 
+	var queue pktQueue = newFIFOQueue()
+	if len(classes) > 0 {
+		queue = newQoSQueue(classes)
+	}
 	c := &sender{
-		encoder: newEncoder(sessID, NewStreamID(), uint16(mtu)),
+		encoder: newEncoder(sessID, NewStreamID(), uint16(mtu), queue),
 		conn:    conn,
 		address: &snet.UDPAddr{
 			IA:      path.Destination(),
@@ -74,6 +91,15 @@ func newSender(sessID uint8, conn net.PacketConn, path snet.Path,
 		path:               path,
 		pathFingerprint:    snet.Fingerprint(path),
 		metrics:            metrics,
+		mtu:                mtu - hdrLen,
+	}
+	if frameKeys != nil {
+		remote := &snet.UDPAddr{IA: path.Destination(), Host: &gatewayAddr}
+		sealer, err := newFrameSealer(frameKeys, remote, frameMTU)
+		if err != nil {
+			return nil, err
+		}
+		c.sealer = sealer
 	}
 	go func() {
 		defer log.HandlePanic()
@@ -88,12 +114,13 @@ func (c *sender) Close() {
 	c.encoder.Close()
 }
 
-// Write sends the packet to the remote gateway in asynchronous manner.
-func (c *sender) Write(pkt []byte) {
+// Write sends the packet of the given QoS class to the remote gateway in
+// asynchronous manner.
+func (c *sender) Write(pkt []byte, class int) {
 	increaseCounterMetric(c.metrics.IPPktsSent, 1)
 	increaseCounterMetric(c.metrics.IPPktBytesSent, float64(len(pkt)))
 
-	c.encoder.Write(pkt)
+	c.encoder.Write(pkt, class)
 }
 
 func (c *sender) run() {
@@ -103,6 +130,14 @@ func (c *sender) run() {
 			// Sender was closed and all the buffered frames were sent.
 			break
 		}
+		if c.sealer != nil {
+			var err error
+			frame, err = c.sealer.Seal(context.Background(), frame, time.Now())
+			if err != nil {
+				increaseCounterMetric(c.metrics.SendExternalErrors, 1)
+				continue
+			}
+		}
 		_, err := c.conn.WriteTo(frame, c.address)
 		if err != nil {
 			increaseCounterMetric(c.metrics.SendExternalErrors, 1)
@@ -15,25 +15,21 @@
 package dataplane
 
 import (
-	"encoding/binary"
 	"fmt"
-	"hash/crc64"
 	"net"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/google/gopacket"
-	"github.com/google/gopacket/layers"
 
+	"github.com/scionproto/scion/gateway/control"
 	"github.com/scionproto/scion/pkg/metrics"
+	"github.com/scionproto/scion/pkg/private/serrors"
 	"github.com/scionproto/scion/pkg/snet"
 )
 
-var (
-	crcTable = crc64.MakeTable(crc64.ECMA)
-)
-
 type PathStatsPublisher interface {
 	PublishEgressStats(fingerprint string, frames int64, bytes int64)
 }
@@ -51,6 +47,9 @@ type SessionMetrics struct {
 	FrameBytesSent metrics.Counter
 	// SendExternalError is the error count when sending frames to the external network.
 	SendExternalErrors metrics.Counter
+	// IPPktsQueueDropped is the count of IP packets dropped because the egress
+	// queue of their QoS class was full. The session adds the label "class".
+	IPPktsQueueDropped metrics.Counter
 }
 
 type Session struct {
@@ -59,10 +58,29 @@ type Session struct {
 	DataPlaneConn      net.PacketConn
 	PathStatsPublisher PathStatsPublisher
 	Metrics            SessionMetrics
+	// FrameKeys, if set, is used to protect the frames sent to the remote
+	// gateway.
+	FrameKeys FrameKeyProvider
+	// QoS classifies the packets into QoS classes with separate egress queues.
+	// If empty, the packets are sent in FIFO order.
+	QoS control.QoSPolicy
 
 	mutex sync.Mutex
+	// classes are the egress queue configurations of the QoS classes,
+	// including the default class as the last one.
+	classes []qosClass
 	// senders is a list of currently used senders.
 	senders []*sender
+	// shares maps the flows to the senders.
+	shares control.FlowShares
+	// minMTU is the smallest tunnel MTU of the senders.
+	minMTU int
+
+	// pktsSent, pktBytesSent and pktsDropped count the IP packets written to
+	// the session.
+	pktsSent     atomic.Uint64
+	pktBytesSent atomic.Uint64
+	pktsDropped  atomic.Uint64
 }
 
 // Close signals that the session should close up its internal Connections. Close returns as
@@ -79,17 +97,95 @@ func (s *Session) Write(packet gopacket.Packet) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	if len(s.senders) == 0 {
+	// Choose the path based on the packet's quintuple.
+	s.write(s.shares.Select(packet), packet)
+}
+
+// WriteWithMTU selects the path for the packet like Write and sends the packet
+// on that path if check accepts the tunnel MTU of the path. The packet is
+// dropped if the session has no path.
+func (s *Session) WriteWithMTU(packet gopacket.Packet, check func(mtu int) bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	index := s.shares.Select(packet)
+	if index >= 0 && !check(s.senders[index].mtu) {
 		return
 	}
-	if len(s.senders) == 1 {
-		s.senders[0].Write(packet.Data())
+	s.write(index, packet)
+}
+
+// write sends the packet with the sender at index. The packet is dropped if
+// the index is negative.
+func (s *Session) write(index int, packet gopacket.Packet) {
+	if index < 0 {
+		s.pktsDropped.Add(1)
 		return
 	}
-	// Choose the path based on the packet's quintuple.
-	hash := crc64.Checksum(extractQuintuple(packet), crcTable)
-	index := hash % uint64(len(s.senders))
-	s.senders[index].Write(packet.Data())
+	s.pktsSent.Add(1)
+	s.pktBytesSent.Add(uint64(len(packet.Data())))
+	s.senders[index].Write(packet.Data(), s.classify(packet))
+}
+
+// classify returns the index of the QoS class of the packet. Packets that do
+// not match any class belong to the default class, which comes last.
+func (s *Session) classify(packet gopacket.Packet) int {
+	if len(s.QoS) == 0 {
+		return 0
+	}
+	network := packet.NetworkLayer()
+	for i, class := range s.QoS {
+		if class.Matcher.Eval(network) {
+			return i
+		}
+	}
+	return len(s.QoS)
+}
+
+// qosClasses returns the egress queue configurations of the QoS classes. The
+// configurations are shared by all senders of the session, thus the senders
+// share the shapers of the classes.
+func (s *Session) qosClasses() []qosClass {
+	if len(s.QoS) == 0 || s.classes != nil {
+		return s.classes
+	}
+	s.classes = make([]qosClass, 0, len(s.QoS)+1)
+	for _, class := range s.QoS {
+		c := qosClass{
+			priority: class.Priority,
+			weight:   class.Weight,
+			dropped:  metrics.CounterWith(s.Metrics.IPPktsQueueDropped, "class", class.Name),
+		}
+		if class.Rate > 0 {
+			c.shaper = newQoSShaper(float64(class.Rate) / 8)
+		}
+		s.classes = append(s.classes, c)
+	}
+	s.classes = append(s.classes, qosClass{
+		weight: 1,
+		dropped: metrics.CounterWith(s.Metrics.IPPktsQueueDropped,
+			"class", control.DefaultQoSClassName),
+	})
+	return s.classes
+}
+
+// MinMTU returns the smallest tunnel MTU of the paths of the session, i.e.,
+// the size of the largest IP packet that fits into a single frame on any path.
+// It returns 0 if the session has no path.
+func (s *Session) MinMTU() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.minMTU
+}
+
+// Counters returns the traffic counters of the session.
+func (s *Session) Counters() control.SessionCounters {
+	return control.SessionCounters{
+		IPPktsSent:     s.pktsSent.Load(),
+		IPPktBytesSent: s.pktBytesSent.Load(),
+		IPPktsDropped:  s.pktsDropped.Load(),
+	}
 }
 
 func (s *Session) String() string {
@@ -113,21 +209,40 @@ func (s *Session) String() string {
 // could cause packets to be delivered out of order. Using new sender with new stream
 // ID causes creation of new reassemby queue on the remote side, thus avoiding the
 // reordering issues.
+//
+// The flows are shared equally among the paths.
 func (s *Session) SetPaths(paths []snet.Path) error {
+	return s.SetWeightedPaths(paths, nil)
+}
+
+// SetWeightedPaths sets the paths like SetPaths, but shares the flows among
+// the paths according to the weights. The weights must either be nil or have
+// the same length as the paths.
+func (s *Session) SetWeightedPaths(paths []snet.Path, weights []float64) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	if weights != nil && len(weights) != len(paths) {
+		return serrors.New("number of weights does not match number of paths",
+			"paths", len(paths), "weights", len(weights))
+	}
+	weightOf := make(map[*sender]float64, len(paths))
 	created := make([]*sender, 0, len(paths))
 	reused := make(map[*sender]bool, len(s.senders))
 	for _, existingSender := range s.senders {
 		reused[existingSender] = false
 	}
 
-	for _, path := range paths {
+	for i, path := range paths {
+		weight := 1.0
+		if weights != nil {
+			weight = weights[i]
+		}
 		// Find out whether we already have a sender for this path.
 		// Keep using old senders whenever possible.
 		if existingSender, ok := findSenderWithPath(s.senders, path); ok {
 			reused[existingSender] = true
+			weightOf[existingSender] = weight
 			continue
 		}
 
@@ -138,6 +253,8 @@ func (s *Session) SetPaths(paths []snet.Path) error {
 			s.GatewayAddr,
 			s.PathStatsPublisher,
 			s.Metrics,
+			s.FrameKeys,
+			s.qosClasses(),
 		)
 		if err != nil {
 			// Collect newly created senders to avoid go routine leak.
@@ -147,6 +264,7 @@ func (s *Session) SetPaths(paths []snet.Path) error {
 			return err
 		}
 		created = append(created, newSender)
+		weightOf[newSender] = weight
 	}
 
 	newSenders := created
@@ -165,6 +283,17 @@ func (s *Session) SetPaths(paths []snet.Path) error {
 			string(newSenders[y].pathFingerprint)) == -1
 	})
 	s.senders = newSenders
+	senderKeys := make([]string, 0, len(newSenders))
+	senderWeights := make([]float64, 0, len(newSenders))
+	s.minMTU = 0
+	for _, snd := range newSenders {
+		senderKeys = append(senderKeys, string(snd.pathFingerprint))
+		senderWeights = append(senderWeights, weightOf[snd])
+		if s.minMTU == 0 || snd.mtu < s.minMTU {
+			s.minMTU = snd.mtu
+		}
+	}
+	s.shares = control.NewKeyedFlowShares(senderKeys, senderWeights)
 	return nil
 }
 
@@ -189,39 +318,3 @@ func pathsEqual(x, y snet.Path) bool {
 		x.Metadata().MTU == y.Metadata().MTU &&
 		x.Metadata().Expiry.Equal(y.Metadata().Expiry)
 }
-
-func extractQuintuple(packet gopacket.Packet) []byte {
-	// Protocol number and addresses.
-	var proto layers.IPProtocol
-	var q []byte
-	switch ip := packet.NetworkLayer().(type) {
-	case *layers.IPv4:
-		q = []byte{byte(ip.Protocol)}
-		q = append(q, ip.SrcIP...)
-		q = append(q, ip.DstIP...)
-		proto = ip.Protocol
-	case *layers.IPv6:
-		q = []byte{byte(ip.NextHeader)}
-		q = append(q, ip.SrcIP...)
-		q = append(q, ip.DstIP...)
-		proto = ip.NextHeader
-	default:
-		panic(fmt.Sprintf("unexpected network layer %T", packet.NetworkLayer()))
-	}
-	// Ports.
-	switch proto {
-	case layers.IPProtocolTCP:
-		pos := len(q)
-		q = append(q, 0, 0, 0, 0)
-		tcp := packet.Layer(layers.LayerTypeTCP).(*layers.TCP)
-		binary.BigEndian.PutUint16(q[pos:pos+2], uint16(tcp.SrcPort))
-		binary.BigEndian.PutUint16(q[pos+2:pos+4], uint16(tcp.DstPort))
-	case layers.IPProtocolUDP:
-		pos := len(q)
-		q = append(q, 0, 0, 0, 0)
-		udp := packet.Layer(layers.LayerTypeUDP).(*layers.UDP)
-		binary.BigEndian.PutUint16(q[pos:pos+2], uint16(udp.SrcPort))
-		binary.BigEndian.PutUint16(q[pos+2:pos+4], uint16(udp.DstPort))
-	}
-	return q
-}
@@ -23,8 +23,11 @@ import (
 	"github.com/google/gopacket"
 	"github.com/google/gopacket/layers"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 
+	"github.com/scionproto/scion/gateway/control"
+	"github.com/scionproto/scion/gateway/pktcls"
 	"github.com/scionproto/scion/pkg/private/mocks/net/mock_net"
 	"github.com/scionproto/scion/pkg/private/xtest"
 	"github.com/scionproto/scion/pkg/snet"
@@ -83,6 +86,65 @@ func TestTwoPaths(t *testing.T) {
 	sess.Close()
 }
 
+func TestWeightedPaths(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	frameChan := make(chan ([]byte))
+	sess := createSession(t, ctrl, frameChan)
+
+	err := sess.SetWeightedPaths([]snet.Path{createMockPath(ctrl, 200)}, []float64{1, 2})
+	assert.Error(t, err)
+
+	err = sess.SetWeightedPaths([]snet.Path{
+		createMockPath(ctrl, 200),
+		createMockPath(ctrl, 201),
+	}, []float64{0, 1})
+	assert.NoError(t, err)
+	sendPackets(t, sess, 22, 10)
+	waitFrames(t, frameChan, 22, 10)
+	sess.Close()
+}
+
+func TestQoS(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	frameChan := make(chan ([]byte))
+	sess := createSession(t, ctrl, frameChan)
+	sess.QoS = control.QoSPolicy{
+		{Name: "voice", Matcher: mustBuildClass(t, "dscp=0x2e"), Priority: 1},
+	}
+	sess.SetPaths([]snet.Path{createMockPath(ctrl, 200)})
+	sendPackets(t, sess, 22, 10)
+	waitFrames(t, frameChan, 22, 10)
+	sess.Close()
+}
+
+func TestSessionClassify(t *testing.T) {
+	sess := &Session{
+		QoS: control.QoSPolicy{
+			{Name: "voice", Matcher: mustBuildClass(t, "dscp=0x2e")},
+			{Name: "web", Matcher: mustBuildClass(t, "dst=10.0.0.0/8")},
+		},
+	}
+	packet := func(tos uint8, dst net.IP) gopacket.Packet {
+		buf := gopacket.NewSerializeBuffer()
+		opts := gopacket.SerializeOptions{FixLengths: true}
+		require.NoError(t, gopacket.SerializeLayers(buf, opts, &layers.IPv4{
+			Version: 4,
+			TTL:     64,
+			TOS:     tos,
+			SrcIP:   net.IP{192, 168, 0, 1},
+			DstIP:   dst,
+		}))
+		return gopacket.NewPacket(buf.Bytes(), layers.LayerTypeIPv4, gopacket.Default)
+	}
+	assert.Equal(t, 0, sess.classify(packet(0x2e<<2, net.IP{10, 0, 0, 1})))
+	assert.Equal(t, 1, sess.classify(packet(0, net.IP{10, 0, 0, 1})))
+	assert.Equal(t, 2, sess.classify(packet(0, net.IP{172, 16, 0, 1})))
+}
+
 func TestNoLeak(t *testing.T) {
 	defer goleak.VerifyNone(t)
 
@@ -121,6 +183,12 @@ func TestNoLeak(t *testing.T) {
 	sess.Close()
 }
 
+func mustBuildClass(t *testing.T, class string) pktcls.Cond {
+	cond, err := pktcls.BuildClassTree(class)
+	require.NoError(t, err)
+	return cond
+}
+
 func createSession(t *testing.T, ctrl *gomock.Controller, frameChan chan []byte) *Session {
 	conn := mock_net.NewMockPacketConn(ctrl)
 	conn.EXPECT().LocalAddr().Return(
@@ -138,6 +206,13 @@ func createSession(t *testing.T, ctrl *gomock.Controller, frameChan chan []byte)
 }
 
 func sendPackets(t *testing.T, sess *Session, payloadSize int, pktCount int) {
+	pkt := newPacket(t, payloadSize)
+	for i := 0; i < pktCount; i++ {
+		sess.Write(pkt)
+	}
+}
+
+func newPacket(t *testing.T, payloadSize int) gopacket.Packet {
 	bytes := append([]byte{
 		// IPv4 header.
 		0x40, 0, 0, 42, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
@@ -146,10 +221,7 @@ func sendPackets(t *testing.T, sess *Session, payloadSize int, pktCount int) {
 		NoCopy: true,
 		Lazy:   true,
 	}
-	pkt := gopacket.NewPacket(bytes, layers.LayerTypeIPv4, decodeOptions)
-	for i := 0; i < pktCount; i++ {
-		sess.Write(pkt)
-	}
+	return gopacket.NewPacket(bytes, layers.LayerTypeIPv4, decodeOptions)
 }
 
 func waitFrames(t *testing.T, frameChan chan []byte, payloadSize int, pktCount int) {
@@ -182,3 +254,34 @@ func createMockPath(ctrl *gomock.Controller, mtu uint16) snet.Path {
 	path.EXPECT().UnderlayNextHop().Return(nil).AnyTimes()
 	return path
 }
+
+func TestWriteWithMTU(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	frameChan := make(chan ([]byte), 10)
+	sess := createSession(t, ctrl, frameChan)
+	defer sess.Close()
+	assert.Equal(t, 0, sess.MinMTU())
+
+	err := sess.SetPaths([]snet.Path{createMockPath(ctrl, 1400), createMockPath(ctrl, 1300)})
+	require.NoError(t, err)
+	minMTU := sess.MinMTU()
+	assert.Positive(t, minMTU)
+	assert.Less(t, minMTU, 1300)
+
+	pkt := newPacket(t, 22)
+	var mtu int
+	sess.WriteWithMTU(pkt, func(m int) bool {
+		mtu = m
+		return false
+	})
+	assert.GreaterOrEqual(t, mtu, minMTU)
+	assert.Equal(t, uint64(0), sess.Counters().IPPktsSent)
+
+	sess.WriteWithMTU(pkt, func(m int) bool {
+		assert.Equal(t, mtu, m)
+		return true
+	})
+	assert.Equal(t, uint64(1), sess.Counters().IPPktsSent)
+}
@@ -18,6 +18,7 @@ package dataplane
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"time"
@@ -50,6 +51,9 @@ type worker struct {
 	rlists           map[int]*reassemblyList
 	markedForCleanup bool
 	tunIO            io.WriteCloser
+	// opener verifies and decrypts protected frames. If nil, protected frames
+	// are discarded.
+	opener *frameOpener
 }
 
 func newWorker(remote *snet.UDPAddr, sessID uint8,
@@ -101,6 +105,10 @@ func (w *worker) Run(ctx context.Context) {
 // packets to the wire and then adding the frame to the corresponding reassembly
 // list if needed.
 func (w *worker) processFrame(ctx context.Context, frame *frameBuf) {
+	if frame.raw[versionPos] == frameVersionProtected && !w.unprotect(ctx, frame) {
+		frame.Release()
+		return
+	}
 	index := int(binary.BigEndian.Uint16(frame.raw[2:4]))
 	epoch := int(binary.BigEndian.Uint32(frame.raw[4:8]) & 0xfffff)
 	seqNr := binary.BigEndian.Uint64(frame.raw[8:16])
@@ -118,6 +126,33 @@ func (w *worker) processFrame(ctx context.Context, frame *frameBuf) {
 	rlist.Insert(ctx, frame)
 }
 
+// unprotect verifies and decrypts a protected frame in place. It returns false
+// if the frame must be discarded.
+func (w *worker) unprotect(ctx context.Context, frame *frameBuf) bool {
+	reason := "invalid"
+	if w.opener != nil {
+		n, err := w.opener.Open(ctx, frame.raw[:frame.frameLen], time.Now())
+		if err == nil {
+			frame.frameLen = n
+			return true
+		}
+		switch {
+		case errors.Is(err, errFrameTooShort):
+		case errors.Is(err, errReplayed):
+			reason = "replayed"
+		case errors.Is(err, errUnauthenticated):
+			reason = "unauthenticated"
+		default:
+			log.FromCtx(ctx).Debug("Unable to verify protected frame", "err", err)
+			reason = "no_key"
+		}
+	}
+	if w.Metrics.FramesDiscarded != nil {
+		w.Metrics.FramesDiscarded.With("reason", reason).Add(1)
+	}
+	return false
+}
+
 func (w *worker) getRlist(epoch int) *reassemblyList {
 	rlist, ok := w.rlists[epoch]
 	if !ok {
@@ -129,6 +164,9 @@ func (w *worker) getRlist(epoch int) *reassemblyList {
 }
 
 func (w *worker) cleanup() {
+	if w.opener != nil {
+		w.opener.cleanup(time.Now())
+	}
 	for epoch := range w.rlists {
 		rlist := w.rlists[epoch]
 		if rlist.markedForDeletion {
@@ -0,0 +1,108 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/scionproto/scion/gateway/dataplane"
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/daemon"
+	"github.com/scionproto/scion/pkg/drkey"
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/snet"
+)
+
+const (
+	// FrameProtectionProtocol is the DRKey protocol identifier under which the
+	// frame protection keys are derived. As it is not a predefined protocol,
+	// the keys are derived with the generic derivation, which binds them to the
+	// identifier.
+	FrameProtectionProtocol drkey.Protocol = 0x4757
+
+	defaultFrameKeyTimeout = 2 * time.Second
+)
+
+// DRKeyFrameKeys provides the keys protecting the data-plane frames based on
+// DRKey Host-Host keys. The frames sent from gateway A to gateway B are
+// protected with the key K_{A->B:H_A,H_B}, where H_A is the address from which
+// A sends frames, and H_B is the address on which B receives them. Thus, the
+// sending gateway is always on the fast side of the derivation.
+type DRKeyFrameKeys struct {
+	// Daemon is used to fetch the DRKey keys.
+	Daemon daemon.Connector
+	// LocalIA is the ISD-AS of the local gateway.
+	LocalIA addr.IA
+	// DataClientIP is the IP from which the local gateway sends frames.
+	DataClientIP net.IP
+	// DataServerIP is the IP on which the local gateway receives frames.
+	DataServerIP net.IP
+	// Timeout is the timeout for fetching a key. If zero, a default of two
+	// seconds is used.
+	Timeout time.Duration
+}
+
+// EgressKey returns the key protecting the frames sent to the remote gateway.
+func (k DRKeyFrameKeys) EgressKey(ctx context.Context, remote *snet.UDPAddr,
+	t time.Time) (dataplane.FrameKey, error) {
+
+	return k.key(ctx, drkey.HostHostMeta{
+		ProtoId:  FrameProtectionProtocol,
+		Validity: t,
+		SrcIA:    k.LocalIA,
+		DstIA:    remote.IA,
+		SrcHost:  k.DataClientIP.String(),
+		DstHost:  remote.Host.IP.String(),
+	})
+}
+
+// IngressKey returns the key protecting the frames received from the remote
+// gateway.
+func (k DRKeyFrameKeys) IngressKey(ctx context.Context, remote *snet.UDPAddr,
+	t time.Time) (dataplane.FrameKey, error) {
+
+	return k.key(ctx, drkey.HostHostMeta{
+		ProtoId:  FrameProtectionProtocol,
+		Validity: t,
+		SrcIA:    remote.IA,
+		DstIA:    k.LocalIA,
+		SrcHost:  remote.Host.IP.String(),
+		DstHost:  k.DataServerIP.String(),
+	})
+}
+
+func (k DRKeyFrameKeys) key(ctx context.Context,
+	meta drkey.HostHostMeta) (dataplane.FrameKey, error) {
+
+	timeout := k.Timeout
+	if timeout == 0 {
+		timeout = defaultFrameKeyTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	key, err := k.Daemon.DRKeyGetHostHostKey(ctx, meta)
+	if err != nil {
+		return dataplane.FrameKey{}, serrors.WrapStr("fetching DRKey Host-Host key", err,
+			"src_ia", meta.SrcIA, "dst_ia", meta.DstIA,
+			"src_host", meta.SrcHost, "dst_host", meta.DstHost)
+	}
+	return dataplane.FrameKey{
+		Key:       key.Key,
+		NotBefore: key.Epoch.NotBefore,
+		NotAfter:  key.Epoch.NotAfter,
+	}, nil
+}
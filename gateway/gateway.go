@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	quic "github.com/lucas-clemente/quic-go"
@@ -66,10 +67,16 @@ type DataplaneSessionFactory struct {
 	PacketConnFactory  PacketConnFactory
 	PathStatsPublisher dataplane.PathStatsPublisher
 	Metrics            dataplane.SessionMetrics
+	// FrameKeys is used to protect the frames sent to remote gateways that
+	// support frame protection. If nil, frames are never protected.
+	FrameKeys dataplane.FrameKeyProvider
+	// RequireFrameProtection indicates that frames are protected even if the
+	// remote gateway does not advertise support for it.
+	RequireFrameProtection bool
 }
 
-func (dpf DataplaneSessionFactory) New(id uint8, policyID int,
-	remoteIA addr.IA, remoteAddr net.Addr) control.DataplaneSession {
+func (dpf DataplaneSessionFactory) New(id uint8, policyID int, remoteIA addr.IA,
+	remoteAddr net.Addr, frameProtection bool, qos control.QoSPolicy) control.DataplaneSession {
 
 	conn, err := dpf.PacketConnFactory.New()
 	if err != nil {
@@ -82,6 +89,7 @@ func (dpf DataplaneSessionFactory) New(id uint8, policyID int,
 		FrameBytesSent:     metrics.CounterWith(dpf.Metrics.FrameBytesSent, labels...),
 		FramesSent:         metrics.CounterWith(dpf.Metrics.FramesSent, labels...),
 		SendExternalErrors: dpf.Metrics.SendExternalErrors,
+		IPPktsQueueDropped: metrics.CounterWith(dpf.Metrics.IPPktsQueueDropped, labels...),
 	}
 	sess := &dataplane.Session{
 		SessionID:          id,
@@ -89,6 +97,10 @@ func (dpf DataplaneSessionFactory) New(id uint8, policyID int,
 		DataPlaneConn:      conn,
 		PathStatsPublisher: dpf.PathStatsPublisher,
 		Metrics:            metrics,
+		QoS:                qos,
+	}
+	if frameProtection || dpf.RequireFrameProtection {
+		sess.FrameKeys = dpf.FrameKeys
 	}
 	return sess
 }
@@ -154,9 +166,17 @@ func (ignoreSCMP) Handle(pkt *snet.Packet) error {
 // depending on the state of the last published routing policy file.
 type SelectAdvertisedRoutes struct {
 	ConfigPublisher *control.ConfigPublisher
+	// Importer, if set, provides the prefixes learned from the local network.
+	// In this case, the advertise rules of the routing policy select which of
+	// these prefixes are advertised.
+	Importer PrefixImporter
 }
 
 func (a *SelectAdvertisedRoutes) AdvertiseList(from, to addr.IA) ([]netaddr.IPPrefix, error) {
+	if a.Importer != nil {
+		return routing.FilterAdvertised(a.ConfigPublisher.RoutingPolicy(), from, to,
+			a.Importer.Received())
+	}
 	return routing.AdvertiseList(a.ConfigPublisher.RoutingPolicy(), from, to)
 }
 
@@ -207,12 +227,52 @@ type Gateway struct {
 	// Daemon is the API of the SCION Daemon.
 	Daemon daemon.Connector
 
+	// FrameProtection enables authenticated and encrypted data-plane frames.
+	// Support is advertised to remote gateways in the prefix exchange, and the
+	// frames sent to remote gateways that advertise support are protected.
+	FrameProtection bool
+	// RequireFrameProtection enables frame protection and, in addition, causes
+	// all frames to be protected and unprotected frames to be discarded.
+	RequireFrameProtection bool
+
+	// PrefixAttestations provides the signed prefix attestations that are
+	// advertised to remote gateways. If nil, no attestations are advertised.
+	PrefixAttestations controlgrpc.AttestationProvider
+	// PrefixOriginVerifier verifies the prefix attestations advertised by
+	// remote gateways. If nil, the origin of remote prefixes is not validated.
+	PrefixOriginVerifier control.PrefixAttestationVerifier
+	// PrefixHolders are the IP prefixes held by each ISD-AS. The prefix
+	// attestations are only trusted for the prefixes held by the attested
+	// ISD-AS.
+	PrefixHolders control.PrefixHolders
+	// RejectInvalidPrefixOrigins causes prefixes with an invalid origin to be
+	// discarded instead of deprioritized.
+	RejectInvalidPrefixOrigins bool
+
 	// RouteSourceIPv4 is the source hint for IPv4 routes added to the Linux routing table.
 	RouteSourceIPv4 net.IP
 	// RouteSourceIPv6 is the source hint for IPv6 routes added to the Linux routing table.
 	RouteSourceIPv6 net.IP
 	// TunnelName is the device name for the Linux global tunnel device.
 	TunnelName string
+	// TunnelSocket is the local address of the datagram socket that is used
+	// instead of the Linux tunnel device. If nil, the Linux tunnel device is
+	// used.
+	TunnelSocket net.Addr
+	// TunnelSocketRemote is the address to which the IP packets are sent if
+	// TunnelSocket is set. If nil, they are sent to the address of the last
+	// received packet.
+	TunnelSocketRemote net.Addr
+	// SendPacketTooBig enables ICMP Packet Too Big messages to the local senders
+	// of packets that exceed the tunnel MTU and must not be fragmented.
+	SendPacketTooBig bool
+	// ClampTCPMSS enables clamping the maximum segment size of outgoing TCP
+	// SYN packets to the tunnel MTU.
+	ClampTCPMSS bool
+
+	// BGP, if set, enables the exchange of prefixes with a router in the local
+	// network.
+	BGP *BGP
 
 	// RoutingTableReader is used for routing the packets.
 	RoutingTableReader control.RoutingTableReader
@@ -229,6 +289,11 @@ type Gateway struct {
 
 	// Metrics are the metrics exported by the gateway.
 	Metrics *Metrics
+
+	// stateMtx protects the references to the running components below.
+	stateMtx            sync.RWMutex
+	sessionConfigurator *control.SessionConfigurator
+	engineController    *control.EngineController
 }
 
 func (g *Gateway) Run(ctx context.Context) error {
@@ -247,23 +312,21 @@ func (g *Gateway) Run(ctx context.Context) error {
 			metrics.NewPromCounter(g.Metrics.IPPktsDiscardedTotal), "reason", "invalid")
 		fwMetrics.IPPktsFragmented = metrics.CounterWith(
 			metrics.NewPromCounter(g.Metrics.IPPktsDiscardedTotal), "reason", "fragmented")
+		fwMetrics.IPPktsTooBig = metrics.CounterWith(
+			metrics.NewPromCounter(g.Metrics.IPPktsDiscardedTotal), "reason", "too_big")
 		fwMetrics.ReceiveLocalErrors = metrics.NewPromCounter(g.Metrics.ReceiveLocalErrorsTotal)
 		fwMetrics.IPPktsNoRoute = metrics.CounterWith(
 			metrics.NewPromCounter(g.Metrics.IPPktsDiscardedTotal), "reason", "no_route")
 	}
 
-	tunnelName := g.TunnelName
-	if tunnelName == "" {
-		tunnelName = "tun0"
-	}
-
 	tunnelReader := TunnelReader{
-		DeviceOpener: xnet.UseNameResolver(
-			routemgr.FixedTunnelName(tunnelName),
-			xnet.OpenerWithOptions(ctx),
-		),
-		Router:  g.RoutingTableReader,
-		Metrics: fwMetrics,
+		DeviceOpener:     g.tunnelDeviceOpener(ctx),
+		Router:           g.RoutingTableReader,
+		Metrics:          fwMetrics,
+		SendPacketTooBig: g.SendPacketTooBig,
+		ICMPSourceIPv4:   g.RouteSourceIPv4,
+		ICMPSourceIPv6:   g.RouteSourceIPv6,
+		ClampTCPMSS:      g.ClampTCPMSS,
 	}
 	deviceManager := &routemgr.SingleDeviceManager{
 		DeviceOpener: tunnelReader.GetDeviceOpenerWithAsyncReader(ctx),
@@ -271,7 +334,7 @@ func (g *Gateway) Run(ctx context.Context) error {
 
 	logger.Debug("Egress started")
 
-	routePublisherFactory := createRouteManager(ctx, deviceManager)
+	routePublisherFactory := createRouteManager(ctx, deviceManager, g.BGP)
 
 	// *************************************************************************
 	// Initialize base SCION network information: IA + Dispatcher connectivity
@@ -284,6 +347,19 @@ func (g *Gateway) Run(ctx context.Context) error {
 
 	reconnectingDispatcher := reconnect.NewDispatcherService(g.Dispatcher)
 
+	// Frame protection keys are derived from DRKey Host-Host keys between the
+	// data-plane addresses of the gateways.
+	var frameKeys dataplane.FrameKeyProvider
+	if g.FrameProtection || g.RequireFrameProtection {
+		frameKeys = DRKeyFrameKeys{
+			Daemon:       g.Daemon,
+			LocalIA:      localIA,
+			DataClientIP: g.DataClientIP,
+			DataServerIP: g.DataServerAddr.IP,
+		}
+		logger.Info("Frame protection enabled", "required", g.RequireFrameProtection)
+	}
+
 	// *************************************************************************
 	// Set up path monitoring. The path monitor runs an the SCION/UDP stack
 	// using the control address and uses traceroute packets to check if paths
@@ -418,12 +494,23 @@ func (g *Gateway) Run(ctx context.Context) error {
 	if g.Metrics != nil {
 		pfMetrics.PrefixesAccepted = metrics.NewPromGauge(g.Metrics.PrefixesAccepted)
 		pfMetrics.PrefixesRejected = metrics.NewPromGauge(g.Metrics.PrefixesRejected)
+		pfMetrics.PrefixesOriginInvalid = metrics.NewPromGauge(g.Metrics.PrefixesOriginInvalid)
+	}
+	var originValidator *control.PrefixOriginValidator
+	if g.PrefixOriginVerifier != nil {
+		logger.Info("Prefix origin validation enabled", "reject", g.RejectInvalidPrefixOrigins)
+		originValidator = &control.PrefixOriginValidator{
+			Verifier: g.PrefixOriginVerifier,
+			Holders:  g.PrefixHolders,
+			Reject:   g.RejectInvalidPrefixOrigins,
+		}
 	}
 	filteredPrefixAggregator := &control.PrefixesFilter{
-		LocalIA:        localIA,
-		PolicyProvider: configPublisher,
-		Consumer:       prefixAggregator,
-		Metrics:        pfMetrics,
+		LocalIA:         localIA,
+		PolicyProvider:  configPublisher,
+		Consumer:        prefixAggregator,
+		OriginValidator: originValidator,
+		Metrics:         pfMetrics,
 	}
 
 	go func() {
@@ -584,6 +671,10 @@ func (g *Gateway) Run(ctx context.Context) error {
 	if g.Metrics != nil {
 		paMetric = metrics.NewPromGauge(g.Metrics.PrefixesAdvertised)
 	}
+	var importer PrefixImporter
+	if g.BGP != nil && g.BGP.Import {
+		importer = g.BGP.Speaker
+	}
 	discoveryServer := grpc.NewServer(libgrpc.UnaryServerInterceptor())
 	gatewaypb.RegisterIPPrefixesServiceServer(
 		discoveryServer,
@@ -591,8 +682,11 @@ func (g *Gateway) Run(ctx context.Context) error {
 			LocalIA: localIA,
 			Advertiser: &SelectAdvertisedRoutes{
 				ConfigPublisher: configPublisher,
+				Importer:        importer,
 			},
 			PrefixesAdvertised: paMetric,
+			FrameProtection:    frameKeys != nil,
+			Attestations:       g.PrefixAttestations,
 		},
 	)
 
@@ -626,7 +720,7 @@ func (g *Gateway) Run(ctx context.Context) error {
 
 	// Start dataplane ingress
 	if err := StartIngress(ctx, scionNetwork, g.DataServerAddr, deviceManager,
-		g.Metrics); err != nil {
+		g.Metrics, frameKeys, g.RequireFrameProtection); err != nil {
 
 		return err
 	}
@@ -676,7 +770,9 @@ func (g *Gateway) Run(ctx context.Context) error {
 					Network: scionNetwork,
 					Addr:    &net.UDPAddr{IP: g.DataClientIP},
 				},
-				Metrics: CreateSessionMetrics(g.Metrics),
+				Metrics:                CreateSessionMetrics(g.Metrics),
+				FrameKeys:              frameKeys,
+				RequireFrameProtection: g.RequireFrameProtection,
 			},
 			Metrics: CreateEngineMetrics(g.Metrics),
 		},
@@ -693,6 +789,11 @@ func (g *Gateway) Run(ctx context.Context) error {
 	}()
 	logger.Debug("Engine controller started")
 
+	g.stateMtx.Lock()
+	g.sessionConfigurator = sessionConfigurator
+	g.engineController = engineController
+	g.stateMtx.Unlock()
+
 	g.HTTPEndpoints["engine"] = service.StatusPage{
 		Info: "gateway diagnostics",
 		Handler: func(w http.ResponseWriter, _ *http.Request) {
@@ -713,7 +814,7 @@ func (g *Gateway) Run(ctx context.Context) error {
 	}
 	g.HTTPEndpoints["diagnostics/sgrp"] = service.StatusPage{
 		Info:    "SGRP diagnostics",
-		Handler: g.diagnosticsSGRP(routePublisherFactory, configPublisher),
+		Handler: g.diagnosticsSGRP(routePublisherFactory, configPublisher, importer),
 	}
 
 	// XXX(scrye): Use an empty file here because the server often doesn't have
@@ -734,12 +835,14 @@ func (g *Gateway) Run(ctx context.Context) error {
 func (g *Gateway) diagnosticsSGRP(
 	routePublisherFactory control.PublisherFactory,
 	pub *control.ConfigPublisher,
+	importer PrefixImporter,
 ) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, _ *http.Request) {
 		var d struct {
 			Advertise struct {
-				Static []string `json:"static"`
+				Static   []string `json:"static"`
+				Imported []string `json:"imported,omitempty"`
 			} `json:"advertise"`
 			Learned struct {
 				Dynamic []string `json:"dynamic"`
@@ -752,6 +855,11 @@ func (g *Gateway) diagnosticsSGRP(
 		for _, s := range routing.StaticAdvertised(pub.RoutingPolicy()) {
 			d.Advertise.Static = append(d.Advertise.Static, s.String())
 		}
+		if importer != nil {
+			for _, p := range importer.Received() {
+				d.Advertise.Imported = append(d.Advertise.Imported, p.String())
+			}
+		}
 		if p, ok := routePublisherFactory.(interface{ Diagnostics() control.Diagnostics }); ok {
 			for _, r := range p.Diagnostics().Routes {
 				d.Learned.Dynamic = append(d.Learned.Dynamic, r.Prefix.String())
@@ -797,7 +905,8 @@ func CreateIngressMetrics(m *Metrics) dataplane.IngressMetrics {
 }
 
 func StartIngress(ctx context.Context, scionNetwork *snet.SCIONNetwork, dataAddr *net.UDPAddr,
-	deviceManager control.DeviceManager, metrics *Metrics) error {
+	deviceManager control.DeviceManager, metrics *Metrics,
+	frameKeys dataplane.FrameKeyProvider, requireFrameProtection bool) error {
 
 	logger := log.FromCtx(ctx)
 	dataplaneServerConn, err := scionNetwork.Listen(
@@ -811,9 +920,11 @@ func StartIngress(ctx context.Context, scionNetwork *snet.SCIONNetwork, dataAddr
 	}
 	ingressMetrics := CreateIngressMetrics(metrics)
 	ingressServer := &dataplane.IngressServer{
-		Conn:          dataplaneServerConn,
-		DeviceManager: deviceManager,
-		Metrics:       ingressMetrics,
+		Conn:              dataplaneServerConn,
+		DeviceManager:     deviceManager,
+		Metrics:           ingressMetrics,
+		FrameKeys:         frameKeys,
+		RequireProtection: requireFrameProtection,
 	}
 	go func() {
 		defer log.HandlePanic()
@@ -835,6 +946,7 @@ func CreateSessionMetrics(m *Metrics) dataplane.SessionMetrics {
 		FrameBytesSent:     metrics.NewPromCounter(m.FrameBytesSentTotal),
 		FramesSent:         metrics.NewPromCounter(m.FramesSentTotal),
 		SendExternalErrors: metrics.NewPromCounter(m.SendExternalErrorsTotal),
+		IPPktsQueueDropped: metrics.NewPromCounter(m.IPPktsQueueDroppedTotal),
 	}
 }
 
@@ -887,14 +999,36 @@ func createRouterMetrics(m *Metrics) control.RouterMetrics {
 	}
 }
 
-func createRouteManager(ctx context.Context,
-	deviceManager control.DeviceManager) control.PublisherFactory {
+// tunnelDeviceOpener returns the opener of the device that exchanges the IP
+// packets with the local network, either a Linux tunnel device or a datagram
+// socket if TunnelSocket is set.
+func (g *Gateway) tunnelDeviceOpener(ctx context.Context) control.DeviceOpener {
+	if g.TunnelSocket != nil {
+		log.FromCtx(ctx).Info("Using socket instead of tunnel device",
+			"local", g.TunnelSocket, "remote", g.TunnelSocketRemote)
+		return xnet.SocketOpener(g.TunnelSocket, g.TunnelSocketRemote)
+	}
+	tunnelName := g.TunnelName
+	if tunnelName == "" {
+		tunnelName = "tun0"
+	}
+	return xnet.UseNameResolver(
+		routemgr.FixedTunnelName(tunnelName),
+		xnet.OpenerWithOptions(ctx),
+	)
+}
+
+func createRouteManager(ctx context.Context, deviceManager control.DeviceManager,
+	bgp *BGP) control.PublisherFactory {
 
 	linux := &routemgr.Linux{DeviceManager: deviceManager}
 	go func() {
 		defer log.HandlePanic()
 		linux.Run(ctx)
 	}()
+	if bgp != nil {
+		bgp.start(ctx, linux)
+	}
 	return linux
 }
 
@@ -902,6 +1036,15 @@ type TunnelReader struct {
 	DeviceOpener control.DeviceOpener
 	Router       control.RoutingTableReader
 	Metrics      dataplane.IPForwarderMetrics
+	// SendPacketTooBig enables ICMP Packet Too Big messages for packets that
+	// exceed the tunnel MTU. They are written to the tunnel device.
+	SendPacketTooBig bool
+	// ICMPSourceIPv4 and ICMPSourceIPv6 are the source addresses of the ICMP
+	// messages.
+	ICMPSourceIPv4 net.IP
+	ICMPSourceIPv6 net.IP
+	// ClampTCPMSS enables clamping of the TCP maximum segment size.
+	ClampTCPMSS bool
 }
 
 func (r *TunnelReader) GetDeviceOpenerWithAsyncReader(ctx context.Context) control.DeviceOpener {
@@ -913,9 +1056,15 @@ func (r *TunnelReader) GetDeviceOpenerWithAsyncReader(ctx context.Context) contr
 		}
 
 		forwarder := &dataplane.IPForwarder{
-			Reader:       handle,
-			RoutingTable: r.Router,
-			Metrics:      r.Metrics,
+			Reader:         handle,
+			RoutingTable:   r.Router,
+			Metrics:        r.Metrics,
+			ICMPSourceIPv4: r.ICMPSourceIPv4,
+			ICMPSourceIPv6: r.ICMPSourceIPv6,
+			ClampMSS:       r.ClampTCPMSS,
+		}
+		if r.SendPacketTooBig {
+			forwarder.Writer = handle
 		}
 
 		go func() {
@@ -930,3 +1079,47 @@ func (r *TunnelReader) GetDeviceOpenerWithAsyncReader(ctx context.Context) contr
 	}
 	return control.DeviceOpenerFunc(f)
 }
+
+// RemoteGateways returns the remote gateways that are currently known, together
+// with the prefixes they advertise. It is empty if the gateway is not running.
+func (g *Gateway) RemoteGateways() control.RemoteGateways {
+	g.stateMtx.RLock()
+	defer g.stateMtx.RUnlock()
+	if g.sessionConfigurator == nil {
+		return control.RemoteGateways{}
+	}
+	return g.sessionConfigurator.RemoteGateways()
+}
+
+// Sessions returns the sessions to the remote gateways. It is empty if the
+// gateway is not running.
+func (g *Gateway) Sessions() []control.SessionInfo {
+	g.stateMtx.RLock()
+	defer g.stateMtx.RUnlock()
+	if g.engineController == nil {
+		return nil
+	}
+	return g.engineController.Sessions()
+}
+
+// RoutingTable returns the entries of the installed routing table. It is empty
+// if the gateway is not running.
+func (g *Gateway) RoutingTable() []control.RoutingEntry {
+	g.stateMtx.RLock()
+	defer g.stateMtx.RUnlock()
+	if g.engineController == nil {
+		return nil
+	}
+	return g.engineController.RoutingTable()
+}
+
+// SwitchPath forces the session to switch away from the paths it currently
+// uses for the given duration.
+func (g *Gateway) SwitchPath(sessionID uint8, duration time.Duration) error {
+	g.stateMtx.RLock()
+	defer g.stateMtx.RUnlock()
+	if g.engineController == nil {
+		return serrors.WithCtx(control.ErrSessionNotFound, "session_id", sessionID)
+	}
+	return g.engineController.SwitchPath(sessionID, duration)
+}
@@ -96,6 +96,11 @@ var (
 		Help:   "Total number of discarded IP packets received from the local network.",
 		Labels: []string{"reason"},
 	}
+	IPPktsQueueDroppedTotalMeta = MetricMeta{
+		Name:   "gateway_ippkts_queue_dropped_total",
+		Help:   "Total number of IP packets dropped because the egress queue was full.",
+		Labels: []string{"isd_as", "remote_isd_as", "policy_id", "class"},
+	}
 	SendExternalErrorsTotalMeta = MetricMeta{
 		Name:   "gateway_send_external_errors_total",
 		Help:   "Total number of errors when sending frames to the network (WAN).",
@@ -221,6 +226,11 @@ var (
 		Help:   "Total number of rejected IP prefixes (incoming).",
 		Labels: []string{"isd_as", "remote_isd_as"},
 	}
+	PrefixesOriginInvalidMeta = MetricMeta{
+		Name:   "gateway_prefixes_origin_invalid",
+		Help:   "Total number of IP prefixes with an invalid origin (incoming).",
+		Labels: []string{"isd_as", "remote_isd_as"},
+	}
 )
 
 type MetricMeta struct {
@@ -268,6 +278,7 @@ type Metrics struct {
 	// Error Metrics
 	FramesDiscardedTotal       *prometheus.CounterVec
 	IPPktsDiscardedTotal       *prometheus.CounterVec
+	IPPktsQueueDroppedTotal    *prometheus.CounterVec
 	SendExternalErrorsTotal    *prometheus.CounterVec
 	SendLocalErrorsTotal       *prometheus.CounterVec
 	ReceiveExternalErrorsTotal *prometheus.CounterVec
@@ -288,6 +299,7 @@ type Metrics struct {
 	PrefixesAdvertised    *prometheus.GaugeVec
 	PrefixesAccepted      *prometheus.GaugeVec
 	PrefixesRejected      *prometheus.GaugeVec
+	PrefixesOriginInvalid *prometheus.GaugeVec
 
 	// SessionMonitor Metrics
 	SessionProbes       *prometheus.CounterVec
@@ -343,6 +355,8 @@ func NewMetrics(ia addr.IA) *Metrics {
 			NewCounterVec().MustCurryWith(labels),
 		IPPktsDiscardedTotal: IPPktsDiscardedTotalMeta.
 			NewCounterVec(),
+		IPPktsQueueDroppedTotal: IPPktsQueueDroppedTotalMeta.
+			NewCounterVec().MustCurryWith(labels),
 		SendExternalErrorsTotal: SendExternalErrorsTotalMeta.
 			NewCounterVec().MustCurryWith(labels),
 		SendLocalErrorsTotal: SendLocalErrorsTotalMeta.
@@ -393,6 +407,8 @@ func NewMetrics(ia addr.IA) *Metrics {
 			NewGaugeVec().MustCurryWith(labels),
 		PrefixesRejected: PrefixesRejectedMeta.
 			NewGaugeVec().MustCurryWith(labels),
+		PrefixesOriginInvalid: PrefixesOriginInvalidMeta.
+			NewGaugeVec().MustCurryWith(labels),
 		SCIONNetworkMetrics:    snetmetrics.NewSCIONNetworkMetrics(),
 		SCMPErrors:             scionPacketConnMetrics.SCMPErrors,
 		SCIONPacketConnMetrics: scionPacketConnMetrics,
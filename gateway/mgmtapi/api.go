@@ -15,14 +15,43 @@
 package mgmtapi
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"sort"
+	"time"
+
+	"github.com/scionproto/scion/gateway/control"
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/snet"
+	api "github.com/scionproto/scion/private/mgmtapi"
 )
 
+// defaultSwitchPathDuration is the duration for which the current paths of a
+// session are avoided if the path switch request does not specify one.
+const defaultSwitchPathDuration = time.Minute
+
+// State provides access to the state of the running gateway.
+type State interface {
+	// RemoteGateways returns the discovered remote gateways.
+	RemoteGateways() control.RemoteGateways
+	// Sessions returns the sessions to the remote gateways.
+	Sessions() []control.SessionInfo
+	// RoutingTable returns the entries of the installed routing table.
+	RoutingTable() []control.RoutingEntry
+	// SwitchPath forces the session to switch away from its current paths for
+	// the given duration.
+	SwitchPath(sessionID uint8, duration time.Duration) error
+}
+
 // Server implements the Posix Gateway Service API.
 type Server struct {
 	Config   http.HandlerFunc
 	Info     http.HandlerFunc
 	LogLevel http.HandlerFunc
+	State    State
 }
 
 // GetConfig is an indirection to the http handler.
@@ -44,3 +73,252 @@ func (s *Server) GetLogLevel(w http.ResponseWriter, r *http.Request) {
 func (s *Server) SetLogLevel(w http.ResponseWriter, r *http.Request) {
 	s.LogLevel(w, r)
 }
+
+// GetRemoteGateways lists the remote gateways and the prefixes they advertise.
+func (s *Server) GetRemoteGateways(w http.ResponseWriter, r *http.Request) {
+	gateways := []RemoteGateway{}
+	remotes := s.State.RemoteGateways()
+	for _, ia := range sortedIAs(remotes) {
+		for _, gw := range remotes.Gateways[ia] {
+			interfaces := make([]int, 0, len(gw.Gateway.Interfaces))
+			for _, intf := range gw.Gateway.Interfaces {
+				interfaces = append(interfaces, int(intf))
+			}
+			gateways = append(gateways, RemoteGateway{
+				ControlAddress:  addrString(gw.Gateway.Control),
+				DataAddress:     addrString(gw.Gateway.Data),
+				FrameProtection: gw.Gateway.FrameProtection,
+				Interfaces:      interfaces,
+				IsdAs:           IsdAs(ia.String()),
+				Prefixes:        prefixes(gw.Prefixes),
+				ProbeAddress:    addrString(gw.Gateway.Probe),
+			})
+		}
+	}
+	respond(w, RemoteGatewaysResponse{RemoteGateways: gateways})
+}
+
+// GetPrefixes lists the prefixes advertised by the remote gateways, grouped by
+// the remote ISD-AS.
+func (s *Server) GetPrefixes(w http.ResponseWriter, r *http.Request) {
+	rep := PrefixesResponse{Prefixes: []RemotePrefixes{}}
+	remotes := s.State.RemoteGateways()
+	for _, ia := range sortedIAs(remotes) {
+		seen := make(map[string]bool)
+		remote := RemotePrefixes{IsdAs: IsdAs(ia.String()), Prefixes: []Prefix{}}
+		for _, gw := range remotes.Gateways[ia] {
+			for _, prefix := range gw.Prefixes {
+				if seen[prefix.String()] {
+					continue
+				}
+				seen[prefix.String()] = true
+				remote.Prefixes = append(remote.Prefixes, Prefix(prefix.String()))
+			}
+		}
+		rep.Prefixes = append(rep.Prefixes, remote)
+	}
+	respond(w, rep)
+}
+
+// GetSessions lists the sessions to the remote gateways.
+func (s *Server) GetSessions(w http.ResponseWriter, r *http.Request) {
+	sessions := []Session{}
+	for _, info := range s.State.Sessions() {
+		sessions = append(sessions, session(info))
+	}
+	respond(w, SessionsResponse{Sessions: sessions})
+}
+
+// GetSession gets the session with the given ID.
+func (s *Server) GetSession(w http.ResponseWriter, r *http.Request, sessionID SessionID) {
+	id, ok := parseSessionID(w, sessionID)
+	if !ok {
+		return
+	}
+	for _, info := range s.State.Sessions() {
+		if info.ID == id {
+			respond(w, session(info))
+			return
+		}
+	}
+	Error(w, Problem{
+		Detail: api.StringRef(fmt.Sprintf("no session with ID %d", id)),
+		Status: http.StatusNotFound,
+		Title:  "session not found",
+		Type:   api.StringRef(api.NotFound),
+	})
+}
+
+// PostSessionSwitchPath forces the session to switch away from the paths it
+// currently uses.
+func (s *Server) PostSessionSwitchPath(w http.ResponseWriter, r *http.Request,
+	sessionID SessionID, params PostSessionSwitchPathParams) {
+
+	id, ok := parseSessionID(w, sessionID)
+	if !ok {
+		return
+	}
+	duration := defaultSwitchPathDuration
+	if params.Duration != nil {
+		d, err := time.ParseDuration(*params.Duration)
+		if err != nil || d <= 0 {
+			Error(w, Problem{
+				Detail: api.StringRef(fmt.Sprintf("invalid duration: %q", *params.Duration)),
+				Status: http.StatusBadRequest,
+				Title:  "malformed query parameters",
+				Type:   api.StringRef(api.BadRequest),
+			})
+			return
+		}
+		duration = d
+	}
+	if err := s.State.SwitchPath(id, duration); err != nil {
+		status, title := http.StatusBadRequest, "unable to switch path"
+		if errors.Is(err, control.ErrSessionNotFound) {
+			status, title = http.StatusNotFound, "session not found"
+		}
+		Error(w, Problem{
+			Detail: api.StringRef(err.Error()),
+			Status: status,
+			Title:  title,
+			Type:   api.StringRef(problemType(status)),
+		})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetRoutingTable gets the entries of the installed routing table.
+func (s *Server) GetRoutingTable(w http.ResponseWriter, r *http.Request) {
+	entries := []RoutingEntry{}
+	for _, entry := range s.State.RoutingTable() {
+		sessions := make([]SessionID, 0, len(entry.Sessions))
+		for _, id := range entry.Sessions {
+			sessions = append(sessions, SessionID(id))
+		}
+		entries = append(entries, RoutingEntry{
+			IsdAs:        IsdAs(entry.RemoteIA.String()),
+			Prefixes:     prefixes(entry.Prefixes),
+			Sessions:     sessions,
+			TrafficClass: condString(entry.TrafficMatcher),
+		})
+	}
+	respond(w, RoutingTableResponse{Entries: entries})
+}
+
+// Error creates an detailed error response.
+func Error(w http.ResponseWriter, p Problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	// no point in catching error here, there is nothing we can do about it anymore.
+	enc.Encode(p)
+}
+
+func respond(w http.ResponseWriter, rep interface{}) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(rep); err != nil {
+		Error(w, Problem{
+			Detail: api.StringRef(err.Error()),
+			Status: http.StatusInternalServerError,
+			Title:  "unable to marshal response",
+			Type:   api.StringRef(api.InternalError),
+		})
+	}
+}
+
+func parseSessionID(w http.ResponseWriter, sessionID SessionID) (uint8, bool) {
+	if sessionID < 0 || sessionID > 255 {
+		Error(w, Problem{
+			Detail: api.StringRef(fmt.Sprintf("session ID out of range: %d", sessionID)),
+			Status: http.StatusBadRequest,
+			Title:  "malformed path parameters",
+			Type:   api.StringRef(api.BadRequest),
+		})
+		return 0, false
+	}
+	return uint8(sessionID), true
+}
+
+func problemType(status int) string {
+	if status == http.StatusNotFound {
+		return api.NotFound
+	}
+	return api.BadRequest
+}
+
+func session(info control.SessionInfo) Session {
+	paths := make([]SessionPath, 0, len(info.Paths))
+	for _, p := range info.Paths {
+		paths = append(paths, sessionPath(p))
+	}
+	return Session{
+		Counters: SessionCounters{
+			IpBytesSent:      int64(info.Counters.IPPktBytesSent),
+			IpPacketsDropped: int64(info.Counters.IPPktsDropped),
+			IpPacketsSent:    int64(info.Counters.IPPktsSent),
+		},
+		Healthy:       info.Healthy,
+		Id:            SessionID(info.ID),
+		IsdAs:         IsdAs(info.RemoteIA.String()),
+		LoadSharing:   info.LoadSharing,
+		Paths:         paths,
+		PolicyId:      info.PolicyID,
+		RemoteGateway: addrString(info.Gateway.Data),
+		TrafficClass:  condString(info.TrafficMatcher),
+	}
+}
+
+func sessionPath(p control.PathStatus) SessionPath {
+	sp := SessionPath{
+		Alive:       p.State.IsAlive,
+		DropRate:    float32(p.State.DropRate),
+		Fingerprint: snet.Fingerprint(p.Path).String(),
+		Hops:        []Hop{},
+		Rtt:         p.State.RTT.String(),
+	}
+	if meta := p.Path.Metadata(); meta != nil {
+		for _, intf := range meta.Interfaces {
+			sp.Hops = append(sp.Hops, Hop{
+				Interface: int(intf.ID),
+				IsdAs:     IsdAs(intf.IA.String()),
+			})
+		}
+		sp.Expiry = meta.Expiry
+		sp.Mtu = int(meta.MTU)
+	}
+	return sp
+}
+
+func sortedIAs(remotes control.RemoteGateways) []addr.IA {
+	ias := make([]addr.IA, 0, len(remotes.Gateways))
+	for ia := range remotes.Gateways {
+		ias = append(ias, ia)
+	}
+	sort.Slice(ias, func(i, j int) bool { return ias[i] < ias[j] })
+	return ias
+}
+
+func prefixes(nets []*net.IPNet) []Prefix {
+	result := make([]Prefix, 0, len(nets))
+	for _, n := range nets {
+		result = append(result, Prefix(n.String()))
+	}
+	return result
+}
+
+func addrString(a *net.UDPAddr) string {
+	if a == nil {
+		return ""
+	}
+	return a.String()
+}
+
+func condString(c fmt.Stringer) string {
+	if c == nil {
+		return ""
+	}
+	return c.String()
+}
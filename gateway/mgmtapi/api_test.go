@@ -0,0 +1,267 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgmtapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/gateway/control"
+	"github.com/scionproto/scion/gateway/pathhealth"
+	"github.com/scionproto/scion/gateway/pktcls"
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/pkg/snet"
+	snetpath "github.com/scionproto/scion/pkg/snet/path"
+)
+
+var update = xtest.UpdateGoldenFiles()
+
+type fakeState struct {
+	remotes  control.RemoteGateways
+	sessions []control.SessionInfo
+	routes   []control.RoutingEntry
+
+	switched map[uint8]time.Duration
+}
+
+func (s *fakeState) RemoteGateways() control.RemoteGateways { return s.remotes }
+func (s *fakeState) Sessions() []control.SessionInfo        { return s.sessions }
+func (s *fakeState) RoutingTable() []control.RoutingEntry   { return s.routes }
+
+func (s *fakeState) SwitchPath(sessionID uint8, duration time.Duration) error {
+	for _, info := range s.sessions {
+		if info.ID == sessionID {
+			s.switched[sessionID] = duration
+			return nil
+		}
+	}
+	return serrors.WithCtx(control.ErrSessionNotFound, "session_id", sessionID)
+}
+
+func TestAPI(t *testing.T) {
+	testCases := map[string]struct {
+		Method       string
+		RequestURL   string
+		ResponseFile string
+		Status       int
+		Switched     map[uint8]time.Duration
+	}{
+		"remote gateways": {
+			RequestURL:   "/remote-gateways",
+			ResponseFile: "testdata/remote-gateways.json",
+			Status:       200,
+		},
+		"prefixes": {
+			RequestURL:   "/prefixes",
+			ResponseFile: "testdata/prefixes.json",
+			Status:       200,
+		},
+		"sessions": {
+			RequestURL:   "/sessions",
+			ResponseFile: "testdata/sessions.json",
+			Status:       200,
+		},
+		"session": {
+			RequestURL:   "/sessions/2",
+			ResponseFile: "testdata/session.json",
+			Status:       200,
+		},
+		"session not found": {
+			RequestURL:   "/sessions/7",
+			ResponseFile: "testdata/session-not-found.json",
+			Status:       404,
+		},
+		"session invalid id": {
+			RequestURL:   "/sessions/300",
+			ResponseFile: "testdata/session-invalid-id.json",
+			Status:       400,
+		},
+		"switch path": {
+			Method:     "POST",
+			RequestURL: "/sessions/1/switch-path",
+			Status:     204,
+			Switched:   map[uint8]time.Duration{1: defaultSwitchPathDuration},
+		},
+		"switch path with duration": {
+			Method:     "POST",
+			RequestURL: "/sessions/2/switch-path?duration=30s",
+			Status:     204,
+			Switched:   map[uint8]time.Duration{2: 30 * time.Second},
+		},
+		"switch path invalid duration": {
+			Method:       "POST",
+			RequestURL:   "/sessions/2/switch-path?duration=-1s",
+			ResponseFile: "testdata/switch-path-invalid-duration.json",
+			Status:       400,
+		},
+		"switch path not found": {
+			Method:       "POST",
+			RequestURL:   "/sessions/7/switch-path",
+			ResponseFile: "testdata/switch-path-not-found.json",
+			Status:       404,
+		},
+		"routing table": {
+			RequestURL:   "/routing-table",
+			ResponseFile: "testdata/routing-table.json",
+			Status:       200,
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			state := createState(t)
+			method := tc.Method
+			if method == "" {
+				method = "GET"
+			}
+			req, err := http.NewRequest(method, tc.RequestURL, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			Handler(&Server{State: state}).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Result().StatusCode)
+			if tc.Switched == nil {
+				tc.Switched = map[uint8]time.Duration{}
+			}
+			assert.Equal(t, tc.Switched, state.switched)
+
+			if tc.ResponseFile == "" {
+				assert.Empty(t, rr.Body.String())
+				return
+			}
+			if *update {
+				require.NoError(t, os.WriteFile(tc.ResponseFile, rr.Body.Bytes(), 0666))
+			}
+			golden, err := os.ReadFile(tc.ResponseFile)
+			require.NoError(t, err)
+			assert.Equal(t, string(golden), rr.Body.String())
+		})
+	}
+}
+
+func createState(t *testing.T) *fakeState {
+	ia110 := xtest.MustParseIA("1-ff00:0:110")
+	ia111 := xtest.MustParseIA("1-ff00:0:111")
+	gw110 := control.Gateway{
+		Control:    xtest.MustParseUDPAddr(t, "172.20.0.2:30256"),
+		Probe:      xtest.MustParseUDPAddr(t, "172.20.0.2:30856"),
+		Data:       xtest.MustParseUDPAddr(t, "172.20.0.2:30056"),
+		Interfaces: []uint64{1, 2},
+	}
+	gw111 := control.Gateway{
+		Control:         xtest.MustParseUDPAddr(t, "172.20.0.3:30256"),
+		Probe:           xtest.MustParseUDPAddr(t, "172.20.0.3:30856"),
+		Data:            xtest.MustParseUDPAddr(t, "172.20.0.3:30056"),
+		FrameProtection: true,
+	}
+	gw111b := control.Gateway{
+		Control: xtest.MustParseUDPAddr(t, "172.20.0.4:30256"),
+		Probe:   xtest.MustParseUDPAddr(t, "172.20.0.4:30856"),
+		Data:    xtest.MustParseUDPAddr(t, "172.20.0.4:30056"),
+	}
+	path := snetpath.Path{
+		Src: ia110,
+		Dst: ia111,
+		Meta: snet.PathMetadata{
+			Interfaces: []snet.PathInterface{
+				{IA: ia110, ID: 1},
+				{IA: ia111, ID: 3},
+			},
+			MTU:    1472,
+			Expiry: time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	return &fakeState{
+		remotes: control.RemoteGateways{
+			Gateways: map[addr.IA][]control.RemoteGateway{
+				ia111: {
+					{
+						Gateway:  gw111,
+						Prefixes: xtest.MustParseCIDRs(t, "10.1.0.0/16", "10.2.0.0/16"),
+					},
+					{
+						Gateway:  gw111b,
+						Prefixes: xtest.MustParseCIDRs(t, "10.2.0.0/16", "10.3.0.0/16"),
+					},
+				},
+				ia110: {
+					{
+						Gateway:  gw110,
+						Prefixes: xtest.MustParseCIDRs(t, "192.168.0.0/24"),
+					},
+				},
+			},
+		},
+		sessions: []control.SessionInfo{
+			{
+				ID:             1,
+				PolicyID:       0,
+				RemoteIA:       ia110,
+				Gateway:        gw110,
+				TrafficMatcher: pktcls.CondTrue,
+				Healthy:        false,
+			},
+			{
+				ID:             2,
+				PolicyID:       1,
+				RemoteIA:       ia111,
+				Gateway:        gw111,
+				TrafficMatcher: pktcls.CondTrue,
+				LoadSharing:    true,
+				Healthy:        true,
+				Paths: []control.PathStatus{
+					{
+						Path: path,
+						State: pathhealth.State{
+							IsAlive:  true,
+							RTT:      12 * time.Millisecond,
+							DropRate: 0.25,
+						},
+					},
+				},
+				Counters: control.SessionCounters{
+					IPPktsSent:     10,
+					IPPktBytesSent: 1500,
+					IPPktsDropped:  2,
+				},
+			},
+		},
+		routes: []control.RoutingEntry{
+			{
+				RemoteIA:       ia111,
+				Prefixes:       xtest.MustParseCIDRs(t, "10.1.0.0/16", "10.2.0.0/16"),
+				TrafficMatcher: pktcls.CondTrue,
+				Sessions:       []uint8{2},
+			},
+			{
+				RemoteIA:       ia110,
+				Prefixes:       xtest.MustParseCIDRs(t, "192.168.0.0/24"),
+				TrafficMatcher: pktcls.CondTrue,
+			},
+		},
+		switched: map[uint8]time.Duration{},
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/deepmap/oapi-codegen/pkg/runtime"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -24,6 +25,24 @@ type ServerInterface interface {
 	// Set logging level
 	// (PUT /log/level)
 	SetLogLevel(w http.ResponseWriter, r *http.Request)
+	// List the prefixes advertised by remote ASes
+	// (GET /prefixes)
+	GetPrefixes(w http.ResponseWriter, r *http.Request)
+	// List the remote gateways
+	// (GET /remote-gateways)
+	GetRemoteGateways(w http.ResponseWriter, r *http.Request)
+	// Get the routing table
+	// (GET /routing-table)
+	GetRoutingTable(w http.ResponseWriter, r *http.Request)
+	// List the sessions
+	// (GET /sessions)
+	GetSessions(w http.ResponseWriter, r *http.Request)
+	// Get the session
+	// (GET /sessions/{session-id})
+	GetSession(w http.ResponseWriter, r *http.Request, sessionId SessionID)
+	// Force a path switch
+	// (POST /sessions/{session-id}/switch-path)
+	PostSessionSwitchPath(w http.ResponseWriter, r *http.Request, sessionId SessionID, params PostSessionSwitchPathParams)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -95,6 +114,132 @@ func (siw *ServerInterfaceWrapper) SetLogLevel(w http.ResponseWriter, r *http.Re
 	handler(w, r.WithContext(ctx))
 }
 
+// GetPrefixes operation middleware
+func (siw *ServerInterfaceWrapper) GetPrefixes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPrefixes(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetRemoteGateways operation middleware
+func (siw *ServerInterfaceWrapper) GetRemoteGateways(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRemoteGateways(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetRoutingTable operation middleware
+func (siw *ServerInterfaceWrapper) GetRoutingTable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRoutingTable(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetSessions operation middleware
+func (siw *ServerInterfaceWrapper) GetSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSessions(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetSession operation middleware
+func (siw *ServerInterfaceWrapper) GetSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "session-id" -------------
+	var sessionId SessionID
+
+	err = runtime.BindStyledParameter("simple", false, "session-id", chi.URLParam(r, "session-id"), &sessionId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "session-id", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSession(w, r, sessionId)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// PostSessionSwitchPath operation middleware
+func (siw *ServerInterfaceWrapper) PostSessionSwitchPath(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "session-id" -------------
+	var sessionId SessionID
+
+	err = runtime.BindStyledParameter("simple", false, "session-id", chi.URLParam(r, "session-id"), &sessionId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "session-id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostSessionSwitchPathParams
+
+	// ------------- Optional query parameter "duration" -------------
+	if paramValue := r.URL.Query().Get("duration"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "duration", r.URL.Query(), &params.Duration)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "duration", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostSessionSwitchPath(w, r, sessionId, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
 type UnescapedCookieParamError struct {
 	ParamName string
 	Err       error
@@ -220,6 +365,24 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Put(options.BaseURL+"/log/level", wrapper.SetLogLevel)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/prefixes", wrapper.GetPrefixes)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/remote-gateways", wrapper.GetRemoteGateways)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/routing-table", wrapper.GetRoutingTable)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sessions", wrapper.GetSessions)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sessions/{session-id}", wrapper.GetSession)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/sessions/{session-id}/switch-path", wrapper.PostSessionSwitchPath)
+	})
 
 	return r
 }
@@ -18,17 +18,56 @@ import (
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/9xVTW/bOBD9K8TsHgXL+dg96NYERWDAbYK6t8AHWhrLDCgOS1KuDUP/vRjSUmM5QYoe",
-	"ArQ3ajh88+bxcXSAkhpLBk3wUBzAobdkPMaPG1l9wW8t+sBfJZmAJi6ltVqVMigy+ZMnwzFfbrCRvPrX",
-	"4RoK+Cf/CZ2nXZ8vgjSVdNVH58hB13UZVOhLpyyDQcE1hTsW5d3jQcadUz3HLWpeW0cWXVCJqO7Dp1hz",
-	"qmtlapG2M0DTNlA8QoWrtoYMlFkThyOXZQa4k43VCEW/E/aWv3xwytSRDlNTDiuGSbDLIY1WT1gG6DI4",
-	"7fKMLvbhU7oxWzTovazxzeoD61F1zov0z/AX6LaqRPHhYSbW5ETYoHggr3biTgb8LvdcU4UowEmcT0AG",
-	"W3Q+IU0n08kFN0oWjbQKCriaTCeXkIGVYRObzEsya1XzssboGpYgemZWQQF3GG5TRnbqusvpdGS3gLuQ",
-	"Wy3VyGhjgc7MtGjLEr1ft1rc98WZ9nUq8ZJRByr5M/dHI7ZNI92etXHKBB/l+3r/aS5So22CF2ulccJK",
-	"ytrzPZXUNGRgyRh5fzGvKTJLvvuz9LiRXpWCW3NN0sDKGoVcURuiSkzdkRb+aEDriGm8qpKmOh+e9GtS",
-	"DdPgTbl+f1oNNd5NyzsMQo/G1plGGdj2BVEWI1Ei/g1V+3fRox+2z+unWRVci91fdUuLX7mleAQdT00o",
-	"Hg/QOg0FbEKwRZ4fNuRDVxwsudDl0qp8e8EjVjolVzppxClpiq9lqwMUoKmUOobZA+RG21fT6//+ZxWW",
-	"A53xP+A2sou/ANxZ8liJ1V4sbmf3n/vnGd+lkQ2PkmMz3bL7EQAA//93UAL/KQgAAA==",
+	"H4sIAAAAAAAC/+xaX3PbNhL/Khi2D+2UkijFdhK9pU6aaiZtNLE795D6PBCxJNGQAAuAsnU+ffcb/OF/",
+	"yJZyk17u5p5skSCw+9vfLnYXeAhiXpScAVMyWD4EAmTJmQTz40dMPsCfFUilf8WcKWDmX1yWOY2xopzN",
+	"/pCc6WcyzqDA+r9vBSTBMvhm1k49s2/l7EphRrAgb4TgItjv92FAQMaClnqyYKnXRMItqt+6D/W8P/NS",
+	"/ykFL0EoamWkTIFIcAz6B9zjoswhWM7DQO1KCJbmfQoi2IcBleQWy6ckXEnySpqltRhUAAmWH+tvw856",
+	"N80afPMHxEovYT/uShLMJ0kSRctoOZ9HQRiUWCkQWtO///47+WHy3Uc8SaLJy5uHeXi2X37/sNj3H33/",
+	"Tz3u2yAMFFVmxtXV68mrK7QiwBRNKIigkUQqQVmqJXnH03ewhXyMWF4/7gP/jqcpZSmyr8MAWFVozQls",
+	"qtQonnD92BjuJuxo6N4MRBgAaKf1YbYWkND7AWgvF9P5xYtpNI1mi7Ou7mtU2vEene1MID84Eo91L90I",
+	"/T9VUDzJhg9QcAX1xMG+WRULgXcjLZv5/YryTQ7FWCoCClOPSV6hrCowQwIwwZscENyXOWbG75AsIaYJ",
+	"jZHiSGVUIh7HlRDAYkA8QSoDVNoFkcqwQlSiDPIyqXL9Rc5jrKA3CjOCUroFhMmW6kkYyvidHlwKHgOQ",
+	"KfqboEoBQ5ShNyzNqczMV418CRcIWEoZgJAhqmSF83yHGFdIVlQBMSMYZ0hBnDEa4xxJhT9BxnMCQprZ",
+	"9GgtXk7/AWQadJl2yRmD2KivOCJY4Q2WgBQtgCBeKR8pKJMKsxh88P72YYUEJGBRszDVXiUNOA3KB9EN",
+	"EUzTKdrsECZEOxBGicBpAawzmUBcIFltJiVWmbVYxzy7EqboF7xDG0CVBDIwkOBc2UWpbD6izMrHKxED",
+	"ijmBPlQzN3AWN5hNjO9+o/gnYBPttBNtuIlBb2LRS7gosAqWQSXopEHGB6tUWFVyDOp1Bujn6+s1sgOM",
+	"ZCgFBgJr+292RmwuaEoZkiC2IAwpHqdwT7fz6FkYFPieFjpCnb98GQYFZfbXPIp8od+FjzEDZMaFJmdR",
+	"YLEb+Y0xzH+a9FcgjD/+xvAW01yv6TOIfaA1THCVaxviDa/UcpNj9ikIj+F+xeifFeS7oRN08UCc5bua",
+	"fSYluFcd3LaUAEGv1qspel+W3JG560k2elGGPvx0OXn+InoeImqiEwOqMhBIQMyLAhix324AEagFNYBr",
+	"vEpOmdKvsY2Rk8YchMeVdj67DuMCpTnfGJNY/RzdBmY+znlOcJHBzuD8paaib3+we81brOAO78a7hAZb",
+	"8PwWEyJAelzvt9fr2dXl6v2vyA2p7eK+RHrzaDwrtev0FZ8/X0wX0TSaLpbPosX5hY9oOux+hhD6s1Ml",
+	"iPwSJAIXcFsKrqxtxlKsGKmZxhN0l4FhVmdRJKuy5EJJ5KbRbqqnlT1xEpxLaCTYcJ4DZsG+kwZ6IHiH",
+	"pZpkvEQWh3aoSV1AiDYO+iD4OA8XN2GbnniiWS8FOTGxDXtJ0AC2OrsCqbMAzT3pFfao3Mkldh6BtXvB",
+	"KRzqWg4zeQfC7oQgEWePEOjF+cWTntmk9kMHG8o5oH6PBB5Shv1s0CWw1sk7yhAXixzsVLXIy2kr/IEw",
+	"8UiuK8y4W7fSqSmvm//JjHe4yuHAtu7QblDDfT5//y0iHiKCz25rv19gZAFAtijzG4xXirL0DVNi9/Uo",
+	"HwYSpKSceRzwyr0x1CywijOd2CqBE50KU4lsmqbyHZLAlPPBo8RxU69e+yRyK9zGOfbFhWsngHltZAON",
+	"KTLtCJBI8X4owGz33ffH+38D7VCQDlQdRhh71nuZsDZGSucgj7HgWg847LRaIXpKfdrl1lMEryf3+aiz",
+	"iy/tqJgCcaxhL+vh+zDIAOcq252yPYtheLSxvk7XBtuzEpV/dyYn0fBEF8w5Jrcyw4ZNx+rW+o7+Egi6",
+	"oypD3Lys2XVc6qFLyOMJ4hRdY5V5d2Ke03h3S4lHkU7xaknu5ET2o96jDeScpSMX9BZj/S3jse2/kzMO",
+	"ssk+T05PIU+KNCYCtnmQ0/mzYg0JupiHbfAZgDIOQbUzDfhX8yFsHbUTo5z1baE0xuxQFLjs+Pxgtypv",
+	"NzsF8la6VnAft1+rYmP5olNJHH8Chcz4ep84CODF+fmzi051RZm6OAu8bdzy1s4sb4ngZQnkKDkkcqPR",
+	"BmJcSejxN8O6BkcayyGBTxPpaFyehmQeLc6OWH5IsYEs4cBmXgA7lGmo7zighcZd+Q6RZvV62IJvejSL",
+	"8/NOjyYa85N6etkdfLtBbERJnNMtnLLHmB5cb2fRD07YXjRqtwIrz6o/CRzXa9o4FWsrd1awrZY7EGCa",
+	"PlaOQc8nmkbnzcLMcEevC/clFZ6A+UY/t6oqWrS9M0fmhkEEKzDtPm9RTVkKohTUx9+f2pfD2Ruhg2eb",
+	"C3iBz5IFmccvIXruWyXj5fF7lz7w8exZharGEv5iuaY3WiYLamlVMdqTN9R9J+MLfUc7e77wblTKA8VV",
+	"wfWuTXTKp4s3QcsR6j4yBfNFIZ/cILpWcGiFjuFWni77GkZYTLrVihaiamqUI7z3kUKyWyecknM8mY/2",
+	"suqRXL2TwpFQUD8euIJ+jAqQEqdPN+Waw6zB6vu9O+8aW981Y1+tV00fcc0lvUdv2527NkL3uf4iCIMt",
+	"CJtmBzpBmWtFeQkMl1Q7kM5Zgk6Gp/uPCTVJZgqGixoC4+krEiyDt6Au7Yiwf3K7iKLBka3u087KHNPB",
+	"Ye0QoNGB7FUVxyClPjd6Xy+uxT6LokM8aESZdU6Q9cyuz27KacqUjb7X7395h6yilYtiCXVFFE6ltpNu",
+	"CHMW3Og5ZrVhDiGysseR/114/Iilrg6YjdUmycYpINPC7/VwpSOgOZOT8iBKOU9nzUnvIaiaQ+In4fr8",
+	"E/9mjb8My7egz1L6p9kjjMKgrDygXA1AMfP/yMnuL8GjPoPvrm9jlU5F9v9TVro6xkqayd2OlyPyADUq",
+	"rY9029cCYqBbIPVRlX3Rdu8KLae/mpQhSgWvyrbiG/X4Rp60bntHX8xGo+sNPgZpLHjS6klaSEoQQ01a",
+	"Kx6Q0B2E/XCqpOYrn4ArtsU5be74TAesaGx54BzCyf/qCmSHL1JhBY4udsSk2/Z+nDUD49v8PMNbQBsA",
+	"hgiVMd+CABIixVNbRpjmzZByKoNdK66XJv3e/Zcky4FTgkcoM8DhaybHQNRDTLC90YnpyR7kwVuwc7rm",
+	"qLeh29ygMZdJ8rwNK22Hym/vTtP3i1rb11z2APyhp5VT+Su0dG2UnhUOmLlbnjzu6bI51uBez6+9mrb9",
+	"WtN9C+vzDlPcyRDZXpw9vBu0S7w0qGusL0mBUR33iKu3Deev18dli9ljVp89uP8mlOyfdPLPNqvrgtW3",
+	"wToF9SFjmzpO4AJsI/Wjbo2VOSdta1+XHqbWC8KA4cKUHY0uo+wvPI0H9mRDqp0pRSU1TYj9zZcnoDeD",
+	"rNt8bX3zdVBPi3D2V4pQI8G4QoluIB2KfLJh0dHsn8k7quLM3DHUIpZc+lp5XMT93rfiyH6JsG5UJIIX",
+	"TSfLXEhoT3srCXKKrpuXWADCW24ufdXtEH2HlCHiavkQVSwHKXV33Z526S/1XtrcZht70ZrL2o2ujGRr",
+	"6yVfn0OFQ3hfNz0MLtBdRmObJ/YiTRe2KXptL+yZXWleDFqqkb1nEiyDPysQu1axGt8gfKR5MXb3M/+N",
+	"TWMTR4I7LJESNE1tW/r/bupzU+tFuAucz1X1N+aOqyVsJfJgGWRKlcvZ7CHjUu2XDyUXaj/DJZ1t57pD",
+	"iAXVXmEMljUuXF/qNJdEzWNzeisGr59FZ+cXWqObRppRC1MLNzjSlWHNTEaapGt8Ta92KqPemPuXpmw3",
+	"vVG4t5c/Nzt3C871rWRnGlfl72/2/xoAq6cYiIYyAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -3,6 +3,10 @@
 // Code generated by unknown module path version unknown version DO NOT EDIT.
 package mgmtapi
 
+import (
+	"time"
+)
+
 // Defines values for LogLevelLevel.
 const (
 	LogLevelLevelDebug LogLevelLevel = "debug"
@@ -12,6 +16,15 @@ const (
 	LogLevelLevelInfo LogLevelLevel = "info"
 )
 
+// Hop defines model for Hop.
+type Hop struct {
+	Interface int   `json:"interface"`
+	IsdAs     IsdAs `json:"isd_as"`
+}
+
+// IsdAs defines model for IsdAs.
+type IsdAs string
+
 // LogLevel defines model for LogLevel.
 type LogLevel struct {
 	// Logging level
@@ -21,6 +34,147 @@ type LogLevel struct {
 // Logging level
 type LogLevelLevel string
 
+// Prefix defines model for Prefix.
+type Prefix string
+
+// PrefixesResponse defines model for PrefixesResponse.
+type PrefixesResponse struct {
+	Prefixes []RemotePrefixes `json:"prefixes"`
+}
+
+// Problem defines model for Problem.
+type Problem struct {
+	// A human readable explanation specific to this occurrence of the problem that is helpful to locate the problem and give advice on how to proceed. Written in English and readable for engineers, usually not suited for non technical stakeholders and not localized.
+	Detail *string `json:"detail,omitempty"`
+
+	// A URI reference that identifies the specific occurrence of the problem, e.g. by adding a fragment identifier or sub-path to the problem type. May be used to locate the root of this problem in the source code.
+	Instance *string `json:"instance,omitempty"`
+
+	// The HTTP status code generated by the origin server for this occurrence of the problem.
+	Status int `json:"status"`
+
+	// A short summary of the problem type. Written in English and readable for engineers, usually not suited for non technical stakeholders and not localized.
+	Title string `json:"title"`
+
+	// A URI reference that uniquely identifies the problem type only in the context of the provided API. Opposed to the specification in RFC-7807, it is neither recommended to be dereferencable and point to a human-readable documentation nor globally unique for the problem type.
+	Type *string `json:"type,omitempty"`
+}
+
+// RemoteGateway defines model for RemoteGateway.
+type RemoteGateway struct {
+	// UDP/SCION address of the control plane of the gateway.
+	ControlAddress string `json:"control_address"`
+
+	// UDP/SCION address of the data plane of the gateway.
+	DataAddress string `json:"data_address"`
+
+	// Indication of whether the gateway supports protected frames.
+	FrameProtection bool `json:"frame_protection"`
+
+	// Last-hop SCION interfaces preferred by the gateway.
+	Interfaces []int `json:"interfaces"`
+	IsdAs      IsdAs `json:"isd_as"`
+
+	// IP prefixes advertised by the gateway.
+	Prefixes []Prefix `json:"prefixes"`
+
+	// UDP/SCION address the gateway answers probes on.
+	ProbeAddress string `json:"probe_address"`
+}
+
+// RemoteGatewaysResponse defines model for RemoteGatewaysResponse.
+type RemoteGatewaysResponse struct {
+	RemoteGateways []RemoteGateway `json:"remote_gateways"`
+}
+
+// RemotePrefixes defines model for RemotePrefixes.
+type RemotePrefixes struct {
+	IsdAs    IsdAs    `json:"isd_as"`
+	Prefixes []Prefix `json:"prefixes"`
+}
+
+// RoutingEntry defines model for RoutingEntry.
+type RoutingEntry struct {
+	IsdAs    IsdAs    `json:"isd_as"`
+	Prefixes []Prefix `json:"prefixes"`
+
+	// Sessions the matching traffic is currently sent on.
+	Sessions []SessionID `json:"sessions"`
+
+	// Traffic class the entry applies to.
+	TrafficClass string `json:"traffic_class"`
+}
+
+// RoutingTableResponse defines model for RoutingTableResponse.
+type RoutingTableResponse struct {
+	Entries []RoutingEntry `json:"entries"`
+}
+
+// Session defines model for Session.
+type Session struct {
+	Counters SessionCounters `json:"counters"`
+
+	// Indication of whether the remote gateway answers the probes.
+	Healthy bool      `json:"healthy"`
+	Id      SessionID `json:"id"`
+	IsdAs   IsdAs     `json:"isd_as"`
+
+	// Indication of whether traffic is shared with other sessions.
+	LoadSharing bool          `json:"load_sharing"`
+	Paths       []SessionPath `json:"paths"`
+
+	// Identifier of the session policy the session belongs to.
+	PolicyId int `json:"policy_id"`
+
+	// UDP/SCION data plane address of the remote gateway.
+	RemoteGateway string `json:"remote_gateway"`
+
+	// Traffic class matched by the session.
+	TrafficClass string `json:"traffic_class"`
+}
+
+// SessionCounters defines model for SessionCounters.
+type SessionCounters struct {
+	// Number of IP packet bytes sent on the session.
+	IpBytesSent int64 `json:"ip_bytes_sent"`
+
+	// Number of IP packets dropped because the session had no path.
+	IpPacketsDropped int64 `json:"ip_packets_dropped"`
+
+	// Number of IP packets sent on the session.
+	IpPacketsSent int64 `json:"ip_packets_sent"`
+}
+
+// SessionID defines model for SessionID.
+type SessionID int
+
+// SessionPath defines model for SessionPath.
+type SessionPath struct {
+	// Indication of whether the path answers the path probes.
+	Alive bool `json:"alive"`
+
+	// Fraction of the recent path probes that were not answered.
+	DropRate float32 `json:"drop_rate"`
+
+	// Expiration time of the path.
+	Expiry time.Time `json:"expiry"`
+
+	// Fingerprint of the path.
+	Fingerprint string `json:"fingerprint"`
+	Hops        []Hop  `json:"hops"`
+
+	// Maximum transmission unit of the path, in bytes.
+	Mtu int `json:"mtu"`
+
+	// Smoothed round trip time of the path probes.
+	Rtt string `json:"rtt"`
+}
+
+// SessionsResponse defines model for SessionsResponse.
+type SessionsResponse struct {
+	Sessions []Session `json:"sessions"`
+}
+
 // StandardError defines model for StandardError.
 type StandardError struct {
 	// Error message
@@ -33,5 +187,11 @@ type BadRequest StandardError
 // SetLogLevelJSONBody defines parameters for SetLogLevel.
 type SetLogLevelJSONBody LogLevel
 
+// PostSessionSwitchPathParams defines parameters for PostSessionSwitchPath.
+type PostSessionSwitchPathParams struct {
+	// Duration for which the current paths are avoided. Defaults to 1m.
+	Duration *string `json:"duration,omitempty"`
+}
+
 // SetLogLevelJSONRequestBody defines body for SetLogLevel for application/json ContentType.
 type SetLogLevelJSONRequestBody SetLogLevelJSONBody
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"sort"
 	"sync"
 	"time"
 
@@ -175,9 +176,9 @@ func (w *pathWatcher) Run(ctx context.Context) {
 	defer probeTicker.Stop()
 	for {
 		select {
-		case <-w.pktChan:
+		case pkt := <-w.pktChan:
 			metrics.CounterInc(w.probesReceived)
-			w.pathState.receiveProbe(time.Now())
+			w.pathState.receiveProbe(pkt.Sequence, time.Now())
 		case <-probeTicker.C:
 			w.sendProbe(ctx)
 		case <-ctx.Done():
@@ -224,8 +225,13 @@ func (w *pathWatcher) State() State {
 			IsExpired: true,
 		}
 	}
+	stats := w.pathState.stats(now)
 	return State{
-		IsAlive: w.pathState.active(),
+		IsAlive:  w.pathState.active(),
+		RTT:      stats.rtt,
+		Latency:  stats.latency,
+		Jitter:   stats.jitter,
+		DropRate: stats.dropRate,
 	}
 }
 
@@ -264,8 +270,8 @@ func (w *pathWatcher) sendProbe(ctx context.Context) {
 	w.pathMtx.RLock()
 	defer w.pathMtx.RUnlock()
 
-	w.pathState.sendProbe(time.Now())
 	w.nextSeq++
+	w.pathState.sendProbe(w.nextSeq, time.Now())
 	metrics.CounterInc(w.probesSent)
 	logger := log.FromCtx(ctx)
 	if err := w.prepareProbePacket(); err != nil {
@@ -302,15 +308,51 @@ func (w *pathWatcher) prepareProbePacket() error {
 	return nil
 }
 
+// probeWindow is the number of most recent probes that are considered to
+// compute the latency, jitter and drop rate of a path.
+const probeWindow = 20
+
+// rttSmoothing is the weight of a new RTT sample in the smoothed RTT.
+const rttSmoothing = 0.125
+
+type probe struct {
+	seq      uint16
+	sent     time.Time
+	received bool
+	rtt      time.Duration
+}
+
+// probeStats are the path metrics computed from the probes in the window.
+type probeStats struct {
+	// rtt is the smoothed round trip time.
+	rtt time.Duration
+	// latency is the median one-way latency, estimated as half the round trip
+	// time.
+	latency time.Duration
+	// jitter is the average difference between the latencies of consecutive
+	// answered probes.
+	jitter time.Duration
+	// dropRate is the share of the timed out probes that were not answered.
+	dropRate float64
+}
+
 type pathState struct {
 	mu                sync.Mutex
 	consecutiveProbes int
 	lastReceived      time.Time
+	// probes is a ring buffer of the most recently sent probes.
+	probes [probeWindow]probe
+	// next is the index in probes for the next probe sent.
+	next int
+	// rtt is the smoothed round trip time of the probes.
+	rtt time.Duration
 }
 
-func (s *pathState) sendProbe(now time.Time) {
+func (s *pathState) sendProbe(seq uint16, now time.Time) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.probes[s.next] = probe{seq: seq, sent: now}
+	s.next = (s.next + 1) % probeWindow
 	// Probe timed out.
 	if s.lastReceived.Add(defaultProbeInterval * 2).Before(now) {
 		s.consecutiveProbes = 0
@@ -318,13 +360,27 @@ func (s *pathState) sendProbe(now time.Time) {
 	}
 }
 
-func (s *pathState) receiveProbe(now time.Time) {
+func (s *pathState) receiveProbe(seq uint16, now time.Time) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.lastReceived = now
 	if s.consecutiveProbes < 3 {
 		s.consecutiveProbes++
 	}
+	for i := range s.probes {
+		p := &s.probes[i]
+		if p.sent.IsZero() || p.seq != seq || p.received {
+			continue
+		}
+		p.received = true
+		p.rtt = now.Sub(p.sent)
+		if s.rtt == 0 {
+			s.rtt = p.rtt
+		} else {
+			s.rtt += time.Duration(rttSmoothing * float64(p.rtt-s.rtt))
+		}
+		return
+	}
 }
 
 func (s *pathState) active() bool {
@@ -333,6 +389,53 @@ func (s *pathState) active() bool {
 	return s.consecutiveProbes == 3
 }
 
+// stats computes the path metrics from the probes in the window. Probes that
+// are not yet answered nor timed out are not considered.
+func (s *pathState) stats(now time.Time) probeStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := probeStats{rtt: s.rtt}
+	var sent, dropped int
+	var rtts []time.Duration
+	var jitterSum time.Duration
+	// Iterate from the oldest to the most recent probe.
+	for i := 0; i < probeWindow; i++ {
+		p := s.probes[(s.next+i)%probeWindow]
+		if p.sent.IsZero() || (!p.received && p.sent.Add(defaultProbeInterval*2).After(now)) {
+			continue
+		}
+		sent++
+		if !p.received {
+			dropped++
+			continue
+		}
+		if len(rtts) > 0 {
+			diff := p.rtt - rtts[len(rtts)-1]
+			if diff < 0 {
+				diff = -diff
+			}
+			jitterSum += diff
+		}
+		rtts = append(rtts, p.rtt)
+	}
+	if sent == 0 {
+		return stats
+	}
+	stats.dropRate = float64(dropped) / float64(sent)
+	if len(rtts) > 1 {
+		stats.jitter = jitterSum / time.Duration(len(rtts)-1) / 2
+	}
+	if len(rtts) > 0 {
+		sort.Slice(rtts, func(i, j int) bool { return rtts[i] < rtts[j] })
+		median := rtts[len(rtts)/2]
+		if len(rtts)%2 == 0 {
+			median = (rtts[len(rtts)/2-1] + median) / 2
+		}
+		stats.latency = median / 2
+	}
+	return stats
+}
+
 // pathWrap is the monitored pathWrap it already contains a few precalculated values to
 // prevent too much repeated work.
 type pathWrap struct {
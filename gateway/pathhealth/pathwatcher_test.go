@@ -0,0 +1,73 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathhealth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathStateStats(t *testing.T) {
+	var s pathState
+	now := time.Now()
+	assert.Equal(t, probeStats{}, s.stats(now))
+
+	// Answer every other probe, with a RTT of 10ms and 30ms.
+	for seq := uint16(1); seq <= 4; seq++ {
+		s.sendProbe(seq, now)
+		if seq%2 == 1 {
+			s.receiveProbe(seq, now.Add(time.Duration(seq)*10*time.Millisecond))
+		}
+		now = now.Add(defaultProbeInterval)
+	}
+	stats := s.stats(now)
+	assert.Equal(t, 12500*time.Microsecond, stats.rtt)
+	assert.Equal(t, 10*time.Millisecond, stats.latency)
+	assert.Equal(t, 10*time.Millisecond, stats.jitter)
+	// The last probe is not yet timed out.
+	assert.InDelta(t, 1.0/3, stats.dropRate, 0.001)
+
+	stats = s.stats(now.Add(defaultProbeInterval * 2))
+	assert.InDelta(t, 0.5, stats.dropRate, 0.001)
+
+	// Duplicate replies and replies to unknown probes are ignored.
+	s.receiveProbe(1, now)
+	s.receiveProbe(100, now)
+	assert.Equal(t, 12500*time.Microsecond, s.stats(now).rtt)
+
+	// Probes that fall out of the window are no longer considered.
+	for seq := uint16(5); seq < 5+probeWindow; seq++ {
+		s.sendProbe(seq, now)
+		s.receiveProbe(seq, now.Add(4*time.Millisecond))
+		now = now.Add(defaultProbeInterval)
+	}
+	stats = s.stats(now)
+	assert.Equal(t, 2*time.Millisecond, stats.latency)
+	assert.Zero(t, stats.jitter)
+	assert.Zero(t, stats.dropRate)
+}
+
+func TestStateWeight(t *testing.T) {
+	assert.Zero(t, State{RTT: 10 * time.Millisecond}.Weight())
+	fast := State{IsAlive: true, RTT: 10 * time.Millisecond}
+	slow := State{IsAlive: true, RTT: 40 * time.Millisecond}
+	lossy := State{IsAlive: true, RTT: 10 * time.Millisecond, DropRate: 0.5}
+	assert.InDelta(t, 4*slow.Weight(), fast.Weight(), 0.001)
+	assert.InDelta(t, 2*lossy.Weight(), fast.Weight(), 0.001)
+	assert.Equal(t, State{IsAlive: true}.Weight(),
+		State{IsAlive: true, RTT: time.Microsecond}.Weight())
+}
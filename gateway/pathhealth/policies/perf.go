@@ -0,0 +1,116 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policies
+
+import (
+	"math"
+	"sort"
+
+	"github.com/scionproto/scion/pkg/private/serrors"
+)
+
+// Names of the built-in performance policies.
+const (
+	LowestLatencyName = "lowest_latency"
+	LowestLossName    = "lowest_loss"
+	StableName        = "stable"
+)
+
+// DefaultHysteresis is the relative improvement a path must offer over the
+// current path to be preferred by the Stable policy.
+const DefaultHysteresis = 0.2
+
+var builtinPerfPolicies = map[string]PerfPolicy{
+	LowestLatencyName: LowestLatency{},
+	LowestLossName:    LowestLoss{},
+	StableName:        Stable{Hysteresis: DefaultHysteresis},
+}
+
+// PerfPolicyByName returns the built-in performance policy with the given
+// name.
+func PerfPolicyByName(name string) (PerfPolicy, error) {
+	policy, ok := builtinPerfPolicies[name]
+	if !ok {
+		return nil, serrors.New("unknown performance policy", "name", name,
+			"known", PerfPolicyNames())
+	}
+	return policy, nil
+}
+
+// PerfPolicyNames returns the sorted names of the built-in performance
+// policies.
+func PerfPolicyNames() []string {
+	names := make([]string, 0, len(builtinPerfPolicies))
+	for name := range builtinPerfPolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LowestLatency prefers the path with the lowest latency.
+type LowestLatency struct{}
+
+// Better returns true if x has a lower latency than y.
+func (LowestLatency) Better(x, y *Stats) bool {
+	return x.Latency < y.Latency
+}
+
+// LowestLoss prefers the path with the lowest drop rate. Paths with the same
+// drop rate are ordered by latency.
+type LowestLoss struct{}
+
+// Better returns true if x has a lower drop rate than y, or the same drop rate
+// and a lower latency.
+func (LowestLoss) Better(x, y *Stats) bool {
+	if x.DropRate != y.DropRate {
+		return x.DropRate < y.DropRate
+	}
+	return x.Latency < y.Latency
+}
+
+// Stable prefers the path with the lowest expected latency, i.e., the latency
+// scaled by the share of delivered probes. To avoid flapping between paths
+// with similar performance, another path is only preferred over the current
+// path if its expected latency is lower by more than the hysteresis.
+type Stable struct {
+	// Hysteresis is the relative improvement in the interval [0,1) that is
+	// required to prefer another path over the current one.
+	Hysteresis float64
+}
+
+// Better returns true if x has a lower expected latency than y. If one of the
+// paths is the current path, the other must be better by more than the
+// hysteresis.
+func (p Stable) Better(x, y *Stats) bool {
+	costX, costY := expectedLatency(x), expectedLatency(y)
+	switch {
+	case x.IsCurrent && !y.IsCurrent:
+		return costY >= costX*(1-p.Hysteresis)
+	case !x.IsCurrent && y.IsCurrent:
+		return costX < costY*(1-p.Hysteresis)
+	default:
+		return costX < costY
+	}
+}
+
+// expectedLatency returns the latency scaled by the inverse of the share of
+// delivered probes. It is infinite if no probe is delivered.
+func expectedLatency(s *Stats) float64 {
+	if s.DropRate >= 1 {
+		return math.Inf(1)
+	}
+	return float64(s.Latency) / (1 - s.DropRate)
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policies_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/gateway/pathhealth/policies"
+)
+
+func TestPerfPolicyByName(t *testing.T) {
+	for _, name := range policies.PerfPolicyNames() {
+		policy, err := policies.PerfPolicyByName(name)
+		require.NoError(t, err)
+		assert.NotNil(t, policy)
+	}
+	_, err := policies.PerfPolicyByName("fastest")
+	assert.Error(t, err)
+}
+
+func TestLowestLatency(t *testing.T) {
+	fast := &policies.Stats{Latency: 10 * time.Millisecond, DropRate: 0.5}
+	slow := &policies.Stats{Latency: 20 * time.Millisecond}
+	assert.True(t, policies.LowestLatency{}.Better(fast, slow))
+	assert.False(t, policies.LowestLatency{}.Better(slow, fast))
+	assert.False(t, policies.LowestLatency{}.Better(fast, fast))
+}
+
+func TestLowestLoss(t *testing.T) {
+	lossy := &policies.Stats{Latency: 10 * time.Millisecond, DropRate: 0.5}
+	slow := &policies.Stats{Latency: 20 * time.Millisecond}
+	fast := &policies.Stats{Latency: 10 * time.Millisecond}
+	assert.True(t, policies.LowestLoss{}.Better(slow, lossy))
+	assert.False(t, policies.LowestLoss{}.Better(lossy, slow))
+	assert.True(t, policies.LowestLoss{}.Better(fast, slow))
+}
+
+func TestStable(t *testing.T) {
+	policy := policies.Stable{Hysteresis: 0.2}
+	current := &policies.Stats{Latency: 10 * time.Millisecond, IsCurrent: true}
+	slightlyFaster := &policies.Stats{Latency: 9 * time.Millisecond}
+	muchFaster := &policies.Stats{Latency: 5 * time.Millisecond}
+	lossy := &policies.Stats{Latency: 5 * time.Millisecond, DropRate: 0.5}
+	dead := &policies.Stats{Latency: 5 * time.Millisecond, DropRate: 1}
+
+	// The current path is kept unless the other path is considerably better.
+	assert.True(t, policy.Better(current, slightlyFaster))
+	assert.False(t, policy.Better(slightlyFaster, current))
+	assert.True(t, policy.Better(muchFaster, current))
+	assert.False(t, policy.Better(current, muchFaster))
+	// The loss increases the expected latency.
+	assert.True(t, policy.Better(current, lossy))
+	assert.True(t, policy.Better(current, dead))
+	// Without the current path, the expected latency is compared.
+	assert.True(t, policy.Better(muchFaster, slightlyFaster))
+	assert.True(t, policy.Better(slightlyFaster, lossy))
+}
@@ -16,6 +16,7 @@ package pathhealth
 
 import (
 	"sync"
+	"time"
 
 	"github.com/scionproto/scion/pkg/snet"
 )
@@ -27,6 +28,34 @@ type State struct {
 	// IsExpired indicates that the path is expired. IsExpired == true implies IsAlive == false but
 	// not vice versa.
 	IsExpired bool
+	// RTT is the smoothed round trip time of the path probes.
+	RTT time.Duration
+	// Latency is the median one-way latency of the recent path probes.
+	Latency time.Duration
+	// Jitter is the average difference between the one-way latencies of
+	// consecutive path probes.
+	Jitter time.Duration
+	// DropRate is the share of the recent path probes that were not answered,
+	// in the interval [0,1].
+	DropRate float64
+}
+
+// minWeightRTT is the RTT below which all paths have the same weight. It
+// prevents very short or unmeasured RTTs from dominating the weights.
+const minWeightRTT = time.Millisecond
+
+// Weight returns the relative weight of the path for load sharing. The weight
+// is inversely proportional to the RTT and proportional to the share of
+// answered probes. Paths that are not alive have weight 0.
+func (s State) Weight() float64 {
+	if !s.IsAlive {
+		return 0
+	}
+	rtt := s.RTT
+	if rtt < minWeightRTT {
+		rtt = minWeightRTT
+	}
+	return (1 - s.DropRate) * float64(time.Second) / float64(rtt)
 }
 
 // Selectable is a subset of the PathWatcher that is used for path selection.
@@ -59,6 +88,9 @@ type Selection struct {
 	// Path is the list of selected paths. The list is sorted from best to worst
 	// according to the scoring function used by the selector.
 	Paths []snet.Path
+	// States contains the state of each of the selected paths, in the same
+	// order as Paths.
+	States []State
 	// PathInfo provides more info about why the path was selected.
 	PathInfo PathInfo
 	// PathsAlive is the number of active paths available.
@@ -80,6 +112,9 @@ type Registration struct {
 	currentFingerprints FingerprintSet
 	// pathSelector selects the paths.
 	pathSelector PathSelector
+	// avoided contains the paths that are not selected, unless there is no
+	// alternative, until the given time.
+	avoided map[snet.PathFingerprint]time.Time
 }
 
 // Get returns your own copy of the best available path.
@@ -92,12 +127,28 @@ func (r *Registration) Get() Selection {
 	}
 
 	watchers := r.remoteWatcher.PathWatchers()
-	selectables := make([]Selectable, len(watchers))
-	for i := range watchers {
-		selectables[i] = watchers[i]
+	selectables := make([]Selectable, 0, len(watchers))
+	now := time.Now()
+	for _, watcher := range watchers {
+		fingerprint := snet.Fingerprint(watcher.Path())
+		if until, ok := r.avoided[fingerprint]; ok {
+			if now.Before(until) {
+				continue
+			}
+			delete(r.avoided, fingerprint)
+		}
+		selectables = append(selectables, watcher)
 	}
 
 	selection := r.pathSelector.Select(selectables, r.currentFingerprints)
+	if len(selection.Paths) == 0 && len(selectables) != len(watchers) {
+		// Fall back to the avoided paths if there is no alternative.
+		selectables = selectables[:0]
+		for _, watcher := range watchers {
+			selectables = append(selectables, watcher)
+		}
+		selection = r.pathSelector.Select(selectables, r.currentFingerprints)
+	}
 	r.currentFingerprints = make(FingerprintSet)
 	for _, path := range selection.Paths {
 		r.currentFingerprints[snet.Fingerprint(path)] = struct{}{}
@@ -105,6 +156,19 @@ func (r *Registration) Get() Selection {
 	return selection
 }
 
+// Avoid excludes the path from the selection for the given duration, unless
+// there is no alternative path. This can be used to force a switch away from
+// the currently selected path.
+func (r *Registration) Avoid(fingerprint snet.PathFingerprint, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.avoided == nil {
+		r.avoided = make(map[snet.PathFingerprint]time.Time)
+	}
+	r.avoided[fingerprint] = time.Now().Add(duration)
+}
+
 // Close cancels the registration.
 func (r *Registration) Close() {
 	r.mu.Lock()
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/scionproto/scion/gateway/pathhealth/policies"
 	"github.com/scionproto/scion/pkg/snet"
 )
 
@@ -33,10 +34,19 @@ type PathPolicy interface {
 	Filter(paths []snet.Path) []snet.Path
 }
 
+// PathCoster is implemented by path policies that rank the paths by cost. The
+// second return value is false if the policy does not define a cost.
+type PathCoster interface {
+	Cost(path snet.Path) (float64, bool)
+}
+
 // FilteringPathSelector selects the best paths from a filtered set of paths.
 type FilteringPathSelector struct {
 	// PathPolicy is used to determine which paths are eligible and which are not.
 	PathPolicy PathPolicy
+	// PerfPolicy determines which of the eligible paths are preferred based on
+	// the measured path performance. If it is nil, shorter paths are preferred.
+	PerfPolicy policies.PerfPolicy
 	// RevocationStore keeps track of the revocations.
 	RevocationStore
 	// PathCount is the max number of paths to return to the user. Defaults to 1.
@@ -48,9 +58,12 @@ func (f *FilteringPathSelector) Select(selectables []Selectable, current Fingerp
 	type Allowed struct {
 		Fingerprint snet.PathFingerprint
 		Path        snet.Path
-		Selectable  Selectable
+		State       State
 		IsCurrent   bool
 		IsRevoked   bool
+		Cost        float64
+		HasCost     bool
+		Stats       policies.Stats
 	}
 
 	// Sort out the paths allowed by the path policy.
@@ -71,11 +84,25 @@ func (f *FilteringPathSelector) Select(selectables []Selectable, current Fingerp
 		}
 		fingerprint := snet.Fingerprint(path)
 		_, isCurrent := current[fingerprint]
+		isRevoked := f.RevocationStore.IsRevoked(path)
+		cost, hasCost := pathCost(f.PathPolicy, path)
 		allowed = append(allowed, Allowed{
 			Path:        path,
 			Fingerprint: fingerprint,
+			State:       state,
 			IsCurrent:   isCurrent,
-			IsRevoked:   f.RevocationStore.IsRevoked(path),
+			IsRevoked:   isRevoked,
+			Cost:        cost,
+			HasCost:     hasCost,
+			Stats: policies.Stats{
+				Fingerprint: fingerprint,
+				Latency:     state.Latency,
+				Jitter:      state.Jitter,
+				DropRate:    state.DropRate,
+				IsAlive:     state.IsAlive,
+				IsCurrent:   isCurrent,
+				IsRevoked:   isRevoked,
+			},
 		})
 	}
 	// Sort the allowed paths according the the perf policy.
@@ -88,6 +115,20 @@ func (f *FilteringPathSelector) Select(selectables []Selectable, current Fingerp
 		case !allowed[i].IsRevoked && allowed[j].IsRevoked:
 			return true
 		}
+		// Prefer the cheaper path if the path policy ranks the paths by cost.
+		if allowed[i].HasCost && allowed[j].HasCost && allowed[i].Cost != allowed[j].Cost {
+			return allowed[i].Cost < allowed[j].Cost
+		}
+		// Prefer the path with the better performance if the perf policy
+		// distinguishes the paths.
+		if f.PerfPolicy != nil {
+			if f.PerfPolicy.Better(&allowed[i].Stats, &allowed[j].Stats) {
+				return true
+			}
+			if f.PerfPolicy.Better(&allowed[j].Stats, &allowed[i].Stats) {
+				return false
+			}
+		}
 		if shorter, ok := isShorter(allowed[i].Path, allowed[j].Path); ok {
 			return shorter
 		}
@@ -126,11 +167,14 @@ func (f *FilteringPathSelector) Select(selectables []Selectable, current Fingerp
 	}
 
 	paths := make([]snet.Path, 0, pathCount)
+	states := make([]State, 0, pathCount)
 	for i := 0; i < pathCount; i++ {
 		paths = append(paths, allowed[i].Path)
+		states = append(states, allowed[i].State)
 	}
 	return Selection{
 		Paths:         paths,
+		States:        states,
 		PathInfo:      pathInfo,
 		PathsAlive:    len(allowed),
 		PathsDead:     len(dead),
@@ -146,6 +190,15 @@ func isPathAllowed(policy PathPolicy, path snet.Path) bool {
 	return len(policy.Filter([]snet.Path{path})) > 0
 }
 
+// pathCost returns the cost of the path if the policy ranks the paths by cost.
+func pathCost(policy PathPolicy, path snet.Path) (float64, bool) {
+	coster, ok := policy.(PathCoster)
+	if !ok {
+		return 0, false
+	}
+	return coster.Cost(path)
+}
+
 func isShorter(a, b snet.Path) (bool, bool) {
 	mA, mB := a.Metadata(), b.Metadata()
 	if mA == nil || mB == nil {
@@ -0,0 +1,97 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathhealth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/gateway/pathhealth"
+	"github.com/scionproto/scion/gateway/pathhealth/policies"
+	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/pkg/snet"
+	snetpath "github.com/scionproto/scion/pkg/snet/path"
+)
+
+type selectable struct {
+	path  snet.Path
+	state pathhealth.State
+}
+
+func (s selectable) Path() snet.Path         { return s.path }
+func (s selectable) State() pathhealth.State { return s.state }
+
+func TestFilteringPathSelectorPerfPolicy(t *testing.T) {
+	// The long path has the lower latency.
+	short := selectable{
+		path: createPath(t, "1-ff00:0:110", "1-ff00:0:111"),
+		state: pathhealth.State{
+			IsAlive: true,
+			Latency: 20 * time.Millisecond,
+		},
+	}
+	long := selectable{
+		path: createPath(t, "1-ff00:0:110", "1-ff00:0:112", "1-ff00:0:111"),
+		state: pathhealth.State{
+			IsAlive:  true,
+			Latency:  10 * time.Millisecond,
+			DropRate: 0.5,
+		},
+	}
+	selectables := []pathhealth.Selectable{long, short}
+
+	testCases := map[string]struct {
+		PerfPolicy policies.PerfPolicy
+		Expected   snet.Path
+	}{
+		"no perf policy": {
+			Expected: short.path,
+		},
+		"lowest latency": {
+			PerfPolicy: policies.LowestLatency{},
+			Expected:   long.path,
+		},
+		"lowest loss": {
+			PerfPolicy: policies.LowestLoss{},
+			Expected:   short.path,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			selector := &pathhealth.FilteringPathSelector{
+				PerfPolicy:      tc.PerfPolicy,
+				RevocationStore: &pathhealth.MemoryRevocationStore{},
+			}
+			selection := selector.Select(selectables, nil)
+			assert.Equal(t, []snet.Path{tc.Expected}, selection.Paths)
+		})
+	}
+}
+
+func createPath(t *testing.T, ias ...string) snet.Path {
+	var interfaces []snet.PathInterface
+	for i, ia := range ias {
+		if i > 0 {
+			interfaces = append(interfaces, snet.PathInterface{IA: xtest.MustParseIA(ia), ID: 1})
+		}
+		if i < len(ias)-1 {
+			interfaces = append(interfaces, snet.PathInterface{IA: xtest.MustParseIA(ia), ID: 2})
+		}
+	}
+	return snetpath.Path{Meta: snet.PathMetadata{Interfaces: interfaces}}
+}
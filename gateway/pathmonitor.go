@@ -40,6 +40,7 @@ func (pm *PathMonitor) Register(
 
 	reg := pm.Monitor.Register(remote, &pathhealth.FilteringPathSelector{
 		PathPolicy:      policies.PathPolicy,
+		PerfPolicy:      policies.PerfPolicy,
 		PathCount:       policies.PathCount,
 		RevocationStore: pm.revStore,
 	})
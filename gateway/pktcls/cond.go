@@ -226,6 +226,49 @@ func (c *CondIPv4) UnmarshalJSON(b []byte) error {
 	return err
 }
 
+var _ Cond = (*CondIPv6)(nil)
+
+// CondIPv6 conditions return true if the embedded IPv6 predicate returns true.
+type CondIPv6 struct {
+	Predicate IPv6Predicate
+}
+
+func NewCondIPv6(p IPv6Predicate) *CondIPv6 {
+	return &CondIPv6{Predicate: p}
+}
+
+func (c *CondIPv6) Eval(v gopacket.Layer) bool {
+	if c.Predicate == nil || v == nil {
+		return false
+	}
+	p, ok := v.(*layers.IPv6)
+	if !ok {
+		return false
+	}
+	return c.Predicate.Eval(p)
+}
+
+func (c *CondIPv6) Type() string {
+	return TypeCondIPv6
+}
+
+func (c *CondIPv6) String() string {
+	if c.Predicate == nil {
+		return "<nil>"
+	}
+	return c.Predicate.String()
+}
+
+func (c *CondIPv6) MarshalJSON() ([]byte, error) {
+	return marshalInterface(c.Predicate)
+}
+
+func (c *CondIPv6) UnmarshalJSON(b []byte) error {
+	var err error
+	c.Predicate, err = unmarshalIPv6Predicate(b)
+	return err
+}
+
 var _ Cond = (*CondPorts)(nil)
 
 // CondPorts conditions return true if the embedded port predicate returns true.
@@ -243,19 +286,15 @@ func (c *CondPorts) Eval(v gopacket.Layer) bool {
 	}
 	// Port predicates are independent on particular L3 or L4 protocol.
 	// Here we extract the ports and pass them to the embedded predicate.
-	l3 := v.LayerType()
-	if l3 != layers.LayerTypeIPv4 {
-		return false
-	}
-	ipv4, ok := v.(*layers.IPv4)
-	if !ok {
+	proto, payload, ok := upperLayer(v)
+	if !ok || payload == nil {
 		return false
 	}
 
-	switch ipv4.NextLayerType() {
-	case layers.LayerTypeUDP:
+	switch proto {
+	case layers.IPProtocolUDP:
 		udp := &layers.UDP{}
-		err := udp.DecodeFromBytes(ipv4.LayerPayload(), gopacket.NilDecodeFeedback)
+		err := udp.DecodeFromBytes(payload, gopacket.NilDecodeFeedback)
 		if err != nil {
 			return false
 		}
@@ -263,9 +302,9 @@ func (c *CondPorts) Eval(v gopacket.Layer) bool {
 			Src: uint16(udp.SrcPort),
 			Dst: uint16(udp.DstPort),
 		})
-	case layers.LayerTypeTCP:
+	case layers.IPProtocolTCP:
 		tcp := &layers.TCP{}
-		err := tcp.DecodeFromBytes(ipv4.LayerPayload(), gopacket.NilDecodeFeedback)
+		err := tcp.DecodeFromBytes(payload, gopacket.NilDecodeFeedback)
 		if err != nil {
 			return false
 		}
@@ -299,6 +338,116 @@ func (c *CondPorts) UnmarshalJSON(b []byte) error {
 	return err
 }
 
+var _ Cond = (*CondICMP)(nil)
+
+// CondICMP conditions return true if the packet carries an ICMPv4 or ICMPv6
+// message and the embedded ICMP predicate returns true.
+type CondICMP struct {
+	Predicate ICMPPredicate
+}
+
+func NewCondICMP(p ICMPPredicate) *CondICMP {
+	return &CondICMP{Predicate: p}
+}
+
+func (c *CondICMP) Eval(v gopacket.Layer) bool {
+	if c.Predicate == nil || v == nil {
+		return false
+	}
+	proto, payload, ok := upperLayer(v)
+	if !ok || payload == nil {
+		return false
+	}
+	var icmp ICMP
+	switch {
+	case proto == layers.IPProtocolICMPv4 && v.LayerType() == layers.LayerTypeIPv4:
+		icmp4 := &layers.ICMPv4{}
+		if err := icmp4.DecodeFromBytes(payload, gopacket.NilDecodeFeedback); err != nil {
+			return false
+		}
+		icmp = ICMP{Type: icmp4.TypeCode.Type(), Code: icmp4.TypeCode.Code()}
+	case proto == layers.IPProtocolICMPv6 && v.LayerType() == layers.LayerTypeIPv6:
+		icmp6 := &layers.ICMPv6{}
+		if err := icmp6.DecodeFromBytes(payload, gopacket.NilDecodeFeedback); err != nil {
+			return false
+		}
+		icmp = ICMP{Type: icmp6.TypeCode.Type(), Code: icmp6.TypeCode.Code()}
+	default:
+		return false
+	}
+	return c.Predicate.Eval(&icmp)
+}
+
+func (c *CondICMP) Type() string {
+	return TypeCondICMP
+}
+
+func (c *CondICMP) String() string {
+	if c.Predicate == nil {
+		return "<nil>"
+	}
+	return c.Predicate.String()
+}
+
+func (c *CondICMP) MarshalJSON() ([]byte, error) {
+	return marshalInterface(c.Predicate)
+}
+
+func (c *CondICMP) UnmarshalJSON(b []byte) error {
+	var err error
+	c.Predicate, err = unmarshalICMPPredicate(b)
+	return err
+}
+
+// upperLayer returns the upper-layer protocol of an IPv4 or IPv6 packet and the
+// payload that carries it. IPv6 extension headers are skipped. The payload is
+// nil for fragmented packets, because the upper-layer header is not guaranteed
+// to be contained in them. The last return value is false if v is not an IP
+// packet or if its extension headers are malformed.
+func upperLayer(v gopacket.Layer) (layers.IPProtocol, []byte, bool) {
+	switch p := v.(type) {
+	case *layers.IPv4:
+		if p.Flags&layers.IPv4MoreFragments != 0 || p.FragOffset != 0 {
+			return p.Protocol, nil, true
+		}
+		return p.Protocol, p.LayerPayload(), true
+	case *layers.IPv6:
+		proto, payload := p.NextHeader, p.LayerPayload()
+		if p.HopByHop != nil {
+			// The hop-by-hop options are already stripped from the payload.
+			proto = p.HopByHop.NextHeader
+		}
+		fragmented := false
+		for {
+			switch proto {
+			case layers.IPProtocolIPv6HopByHop, layers.IPProtocolIPv6Routing,
+				layers.IPProtocolIPv6Destination:
+				if len(payload) < 2 {
+					return 0, nil, false
+				}
+				n := (int(payload[1]) + 1) * 8
+				if len(payload) < n {
+					return 0, nil, false
+				}
+				proto, payload = layers.IPProtocol(payload[0]), payload[n:]
+			case layers.IPProtocolIPv6Fragment:
+				if len(payload) < 8 {
+					return 0, nil, false
+				}
+				fragmented = true
+				proto, payload = layers.IPProtocol(payload[0]), payload[8:]
+			default:
+				if fragmented {
+					return proto, nil, true
+				}
+				return proto, payload, true
+			}
+		}
+	default:
+		return 0, nil, false
+	}
+}
+
 const typeCondClass = "CondClass"
 
 // CondClass conditions return true if the embedded traffic class returns true
@@ -169,6 +169,7 @@ func TestPortCond(t *testing.T) {
 		Cond    pktcls.Cond
 		SrcPort uint16
 		DstPort uint16
+		IPv6    bool
 		ExpEval bool
 	}{
 		"Match UDP src port": {
@@ -195,18 +196,160 @@ func TestPortCond(t *testing.T) {
 			DstPort: 200,
 			ExpEval: false,
 		},
+		"Match UDP dst port over IPv6": {
+			Cond: pktcls.NewCondPorts(
+				&pktcls.PortMatchDestination{
+					MinPort: 443,
+					MaxPort: 443,
+				},
+			),
+			DstPort: 443,
+			IPv6:    true,
+			ExpEval: true,
+		},
+		"Do not match UDP src port over IPv6": {
+			Cond: pktcls.NewCondPorts(
+				&pktcls.PortMatchSource{
+					MinPort: 100,
+					MaxPort: 199,
+				},
+			),
+			SrcPort: 99,
+			IPv6:    true,
+			ExpEval: false,
+		},
 	}
 
 	for name, tc := range testCases {
 		name, tc := name, tc
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			pkt := createUDPPacket(tc.SrcPort, tc.DstPort)
+			var pkt gopacket.Layer
+			if tc.IPv6 {
+				udp := &layers.UDP{
+					SrcPort: layers.UDPPort(tc.SrcPort),
+					DstPort: layers.UDPPort(tc.DstPort),
+				}
+				pkt = createIPv6Packet(0, 0, layers.IPProtocolUDP, false, udp)
+			} else {
+				pkt = createUDPPacket(tc.SrcPort, tc.DstPort)
+			}
 			assert.Equal(t, tc.ExpEval, tc.Cond.Eval(pkt))
 		})
 	}
 }
 
+func TestIPv6Cond(t *testing.T) {
+	_, net6, _ := net.ParseCIDR("2001:db8::/32")
+	tcp := &layers.TCP{SrcPort: 1234, DstPort: 443}
+	testCases := []struct {
+		Name    string
+		Cond    pktcls.Cond
+		Packet  gopacket.Layer
+		ExpEval bool
+	}{
+		{
+			Name:    "Match source",
+			Cond:    pktcls.NewCondIPv6(&pktcls.IPv6MatchSource{Net: net6}),
+			Packet:  createIPv6Packet(0, 0, layers.IPProtocolTCP, false, tcp),
+			ExpEval: true,
+		},
+		{
+			Name:    "Do not match destination",
+			Cond:    pktcls.NewCondIPv6(&pktcls.IPv6MatchDestination{Net: net6}),
+			Packet:  createIPv6Packet(0, 0, layers.IPProtocolTCP, false, tcp),
+			ExpEval: false,
+		},
+		{
+			Name:    "Match traffic class",
+			Cond:    pktcls.NewCondIPv6(&pktcls.IPv6MatchTrafficClass{TrafficClass: 0xb8}),
+			Packet:  createIPv6Packet(0xb8, 0, layers.IPProtocolTCP, false, tcp),
+			ExpEval: true,
+		},
+		{
+			Name:    "Match flow label",
+			Cond:    pktcls.NewCondIPv6(&pktcls.IPv6MatchFlowLabel{FlowLabel: 0x12345}),
+			Packet:  createIPv6Packet(0, 0x12345, layers.IPProtocolTCP, false, tcp),
+			ExpEval: true,
+		},
+		{
+			Name:    "Match next header",
+			Cond:    pktcls.NewCondIPv6(&pktcls.IPv6MatchNextHeader{NextHeader: 6}),
+			Packet:  createIPv6Packet(0, 0, layers.IPProtocolTCP, false, tcp),
+			ExpEval: true,
+		},
+		{
+			Name:    "Match next header behind extension header",
+			Cond:    pktcls.NewCondIPv6(&pktcls.IPv6MatchNextHeader{NextHeader: 6}),
+			Packet:  createIPv6Packet(0, 0, layers.IPProtocolTCP, true, tcp),
+			ExpEval: true,
+		},
+		{
+			Name: "Match ports behind extension header",
+			Cond: pktcls.NewCondPorts(
+				&pktcls.PortMatchDestination{MinPort: 443, MaxPort: 443},
+			),
+			Packet:  createIPv6Packet(0, 0, layers.IPProtocolTCP, true, tcp),
+			ExpEval: true,
+		},
+		{
+			Name:    "IPv6 condition on IPv4 packet",
+			Cond:    pktcls.NewCondIPv6(&pktcls.IPv6MatchTrafficClass{TrafficClass: 0}),
+			Packet:  createUDPPacket(0, 0),
+			ExpEval: false,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.ExpEval, tc.Cond.Eval(tc.Packet))
+		})
+	}
+}
+
+func TestICMPCond(t *testing.T) {
+	echo4 := &layers.ICMPv4{
+		TypeCode: layers.CreateICMPv4TypeCode(layers.ICMPv4TypeEchoRequest, 0),
+	}
+	echo6 := &layers.ICMPv6{
+		TypeCode: layers.CreateICMPv6TypeCode(layers.ICMPv6TypeEchoRequest, 0),
+	}
+	testCases := map[string]struct {
+		Cond    pktcls.Cond
+		Packet  gopacket.Layer
+		ExpEval bool
+	}{
+		"Match ICMPv4 echo request": {
+			Cond:    pktcls.NewCondICMP(&pktcls.ICMPMatchType{ICMPType: 8}),
+			Packet:  createICMPv4Packet(echo4),
+			ExpEval: true,
+		},
+		"Match ICMPv6 echo request": {
+			Cond:    pktcls.NewCondICMP(&pktcls.ICMPMatchType{ICMPType: 128}),
+			Packet:  createIPv6Packet(0, 0, layers.IPProtocolICMPv6, false, echo6),
+			ExpEval: true,
+		},
+		"Do not match ICMPv6 with ICMPv4 type": {
+			Cond:    pktcls.NewCondICMP(&pktcls.ICMPMatchType{ICMPType: 8}),
+			Packet:  createIPv6Packet(0, 0, layers.IPProtocolICMPv6, false, echo6),
+			ExpEval: false,
+		},
+		"Do not match UDP": {
+			Cond:    pktcls.NewCondICMP(&pktcls.ICMPMatchType{ICMPType: 0}),
+			Packet:  createUDPPacket(0, 0),
+			ExpEval: false,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.ExpEval, tc.Cond.Eval(tc.Packet))
+		})
+	}
+}
+
 func createUDPPacket(src, dst uint16) gopacket.Layer {
 	ip := &layers.IPv4{
 		Version:  4,
@@ -239,7 +382,82 @@ func createUDPPacket(src, dst uint16) gopacket.Layer {
 	return pkt
 }
 
+func createICMPv4Packet(icmp *layers.ICMPv4) gopacket.Layer {
+	ip := &layers.IPv4{
+		Version:  4,
+		IHL:      5,
+		TTL:      64,
+		SrcIP:    net.IP{192, 168, 14, 3},
+		DstIP:    net.IP{192, 168, 14, 2},
+		Protocol: layers.IPProtocolICMPv4,
+	}
+	input := gopacket.NewSerializeBuffer()
+	options := gopacket.SerializeOptions{
+		FixLengths:       true,
+		ComputeChecksums: true,
+	}
+	if err := gopacket.SerializeLayers(input, options,
+		ip, icmp, gopacket.Payload([]byte("payload"))); err != nil {
+		panic(err)
+	}
+	pkt := &layers.IPv4{}
+	if err := pkt.DecodeFromBytes(input.Bytes(), gopacket.NilDecodeFeedback); err != nil {
+		panic(err)
+	}
+	return pkt
+}
+
+// createIPv6Packet serializes an IPv6 packet carrying the upper layer l. If
+// withExt is set, a destination options extension header is inserted between
+// the IPv6 header and the upper layer.
+func createIPv6Packet(tc uint8, flowLabel uint32, proto layers.IPProtocol, withExt bool,
+	l gopacket.SerializableLayer) gopacket.Layer {
+
+	ip := &layers.IPv6{
+		Version:      6,
+		TrafficClass: tc,
+		FlowLabel:    flowLabel,
+		HopLimit:     64,
+		SrcIP:        net.ParseIP("2001:db8::1"),
+		DstIP:        net.ParseIP("2001:db9::1"),
+		NextHeader:   proto,
+	}
+	serLayers := []gopacket.SerializableLayer{ip}
+	if withExt {
+		ip.NextHeader = layers.IPProtocolIPv6Destination
+		dst := &layers.IPv6Destination{}
+		dst.NextHeader = proto
+		dst.Options = []*layers.IPv6DestinationOption{
+			{OptionType: 1, OptionData: make([]byte, 4)},
+		}
+		serLayers = append(serLayers, dst)
+	}
+	switch v := l.(type) {
+	case *layers.TCP:
+		_ = v.SetNetworkLayerForChecksum(ip)
+	case *layers.UDP:
+		_ = v.SetNetworkLayerForChecksum(ip)
+	case *layers.ICMPv6:
+		_ = v.SetNetworkLayerForChecksum(ip)
+	}
+	serLayers = append(serLayers, l, gopacket.Payload([]byte("payload")))
+	input := gopacket.NewSerializeBuffer()
+	options := gopacket.SerializeOptions{
+		FixLengths:       true,
+		ComputeChecksums: true,
+	}
+	if err := gopacket.SerializeLayers(input, options, serLayers...); err != nil {
+		panic(err)
+	}
+	pkt := &layers.IPv6{}
+	if err := pkt.DecodeFromBytes(input.Bytes(), gopacket.NilDecodeFeedback); err != nil {
+		panic(err)
+	}
+	return pkt
+}
+
 func TestStringer(t *testing.T) {
+	_, net6, _ := net.ParseCIDR("2001:db8::/32")
 	_, net, _ := net.ParseCIDR("12.12.12.0/26")
 	tests := map[string]struct {
 		Cond pktcls.Cond
@@ -266,6 +484,22 @@ func TestStringer(t *testing.T) {
 				},
 			},
 		},
+		"IPv6 ports ICMP": {
+			Str: "any(all(dst=2001:db8::/32,nexthdr=TCP,dstport=443-443),tc=0xb8," +
+				"flowlabel=0x1,icmptype=128)",
+			Cond: pktcls.CondAnyOf{
+				pktcls.CondAllOf{
+					pktcls.NewCondIPv6(&pktcls.IPv6MatchDestination{Net: net6}),
+					pktcls.NewCondIPv6(&pktcls.IPv6MatchNextHeader{NextHeader: 6}),
+					pktcls.NewCondPorts(
+						&pktcls.PortMatchDestination{MinPort: 443, MaxPort: 443},
+					),
+				},
+				pktcls.NewCondIPv6(&pktcls.IPv6MatchTrafficClass{TrafficClass: 0xb8}),
+				pktcls.NewCondIPv6(&pktcls.IPv6MatchFlowLabel{FlowLabel: 0x1}),
+				pktcls.NewCondICMP(&pktcls.ICMPMatchType{ICMPType: 128}),
+			},
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
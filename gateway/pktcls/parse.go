@@ -125,6 +125,63 @@ func (l *classListener) EnterMatchProtocol(ctx *traffic_class.MatchProtocolConte
 	l.pushCond(NewCondIPv4(prot))
 }
 
+func (l *classListener) EnterMatchSrcIPv6(ctx *traffic_class.MatchSrcIPv6Context) {
+	// Push Selector as Predicate on stack and update the number of Conds on the stack
+	var err error
+	msrc := &IPv6MatchSource{}
+	msrc.Net, err = parseIPv6Net(ctx.GetStop().GetText())
+	if err != nil {
+		l.err = serrors.WrapStr("CIDR parsing failed!", err, "cidr", ctx.GetStop().GetText())
+	}
+	l.pushCond(NewCondIPv6(msrc))
+}
+
+func (l *classListener) EnterMatchDstIPv6(ctx *traffic_class.MatchDstIPv6Context) {
+	// Push Selector as Predicate on stack and update the number of Conds on the stack
+	var err error
+	mdst := &IPv6MatchDestination{}
+	mdst.Net, err = parseIPv6Net(ctx.GetStop().GetText())
+	if err != nil {
+		l.err = serrors.WrapStr("CIDR parsing failed!", err, "cidr", ctx.GetStop().GetText())
+	}
+	l.pushCond(NewCondIPv6(mdst))
+}
+
+func (l *classListener) EnterMatchTC(ctx *traffic_class.MatchTCContext) {
+	// Push Selector as Predicate on stack and update the number of Conds on the stack
+	mtc := &IPv6MatchTrafficClass{}
+	tc, err := strconv.ParseUint(ctx.GetStop().GetText(), 16, 8)
+	if err != nil {
+		l.err = serrors.WrapStr("TC parsing failed!", err, "tc", ctx.GetStop().GetText())
+	}
+	mtc.TrafficClass = uint8(tc)
+	l.pushCond(NewCondIPv6(mtc))
+}
+
+func (l *classListener) EnterMatchFlowLabel(ctx *traffic_class.MatchFlowLabelContext) {
+	// Push Selector as Predicate on stack and update the number of Conds on the stack
+	mfl := &IPv6MatchFlowLabel{}
+	fl, err := strconv.ParseUint(ctx.GetStop().GetText(), 16, 20)
+	if err != nil {
+		l.err = serrors.WrapStr("FLOWLABEL parsing failed!", err,
+			"flowlabel", ctx.GetStop().GetText())
+	}
+	mfl.FlowLabel = uint32(fl)
+	l.pushCond(NewCondIPv6(mfl))
+}
+
+func (l *classListener) EnterMatchNextHdr(ctx *traffic_class.MatchNextHdrContext) {
+	// Push Selector as Predicate on stack and update the number of Conds on the stack
+	nh := &IPv6MatchNextHeader{}
+	number, err := protocolNameToNumber(ctx.GetStop().GetText())
+	if err != nil {
+		l.err = serrors.WrapStr("Next header parsing failed!", err,
+			"nexthdr", ctx.GetStop().GetText())
+	}
+	nh.NextHeader = number
+	l.pushCond(NewCondIPv6(nh))
+}
+
 func (l *classListener) EnterMatchSrcPort(ctx *traffic_class.MatchSrcPortContext) {
 	// Push Selector as Predicate on stack and update the number of Conds on the stack
 	src := &PortMatchSource{}
@@ -187,6 +244,18 @@ func (l *classListener) EnterMatchDstPortRange(ctx *traffic_class.MatchDstPortRa
 	l.pushCond(NewCondPorts(dst))
 }
 
+func (l *classListener) EnterMatchICMPType(ctx *traffic_class.MatchICMPTypeContext) {
+	// Push Selector as Predicate on stack and update the number of Conds on the stack
+	mtype := &ICMPMatchType{}
+	t, err := strconv.ParseUint(ctx.GetStop().GetText(), 10, 8)
+	if err != nil {
+		l.err = serrors.WrapStr("ICMPTYPE parsing failed!", err,
+			"icmptype", ctx.GetStop().GetText())
+	}
+	mtype.ICMPType = uint8(t)
+	l.pushCond(NewCondICMP(mtype))
+}
+
 func (l *classListener) EnterCondCls(ctx *traffic_class.CondClsContext) {
 	l.pushCond(CondClass{TrafficClass: ctx.GetStop().GetText()})
 }
@@ -79,6 +79,56 @@ func TestTrafficClassValidation(t *testing.T) {
 			Class: "protocol=FOO",
 			Valid: false,
 		},
+		{
+			Name:  "src IPv6Cond",
+			Class: "src=2001:db8::/32",
+			Valid: true,
+		},
+		{
+			Name:  "dst IPv6Cond",
+			Class: "dst=::/0",
+			Valid: true,
+		},
+		{
+			Name:  "bad dst IPv6Cond",
+			Class: "dst=2001:db8::",
+			Valid: false,
+		},
+		{
+			Name:  "IPv4-mapped dst IPv6Cond",
+			Class: "dst=::ffff:10.0.0.0/104",
+			Valid: false,
+		},
+		{
+			Name:  "tc IPv6Cond",
+			Class: "tc=0xb8",
+			Valid: true,
+		},
+		{
+			Name:  "flowlabel IPv6Cond",
+			Class: "flowlabel=0xfffff",
+			Valid: true,
+		},
+		{
+			Name:  "bad flowlabel IPv6Cond",
+			Class: "flowlabel=0x100000",
+			Valid: false,
+		},
+		{
+			Name:  "nexthdr IPv6Cond",
+			Class: "nexthdr=ICMPv6",
+			Valid: true,
+		},
+		{
+			Name:  "icmptype ICMPCond",
+			Class: "icmptype=128",
+			Valid: true,
+		},
+		{
+			Name:  "bad icmptype ICMPCond",
+			Class: "icmptype=256",
+			Valid: false,
+		},
 		{
 			Name:  "BOOL",
 			Class: "BOOL=true",
@@ -134,6 +184,7 @@ func TestTrafficClassValidation(t *testing.T) {
 }
 
 func TestTrafficClassTree(t *testing.T) {
+	_, net6, _ := net.ParseCIDR("2001:db8::/32")
 	_, net, _ := net.ParseCIDR("12.12.12.0/26")
 	testCases := []struct {
 		Name  string
@@ -257,6 +308,44 @@ func TestTrafficClassTree(t *testing.T) {
 			Class: "protocol=udp",
 			Tree:  pktcls.NewCondIPv4(&pktcls.IPv4MatchProtocol{Protocol: uint8(17)}),
 		},
+		{
+			Name:  "src IPv6Cond",
+			Class: "src=2001:db8::/32",
+			Tree:  pktcls.NewCondIPv6(&pktcls.IPv6MatchSource{Net: net6}),
+		},
+		{
+			Name:  "dst IPv6Cond",
+			Class: "DST=2001:DB8::/32",
+			Tree:  pktcls.NewCondIPv6(&pktcls.IPv6MatchDestination{Net: net6}),
+		},
+		{
+			Name:  "tc IPv6Cond",
+			Class: "tc=0xb8",
+			Tree:  pktcls.NewCondIPv6(&pktcls.IPv6MatchTrafficClass{TrafficClass: 0xb8}),
+		},
+		{
+			Name:  "flowlabel IPv6Cond",
+			Class: "flowlabel=0x12345",
+			Tree:  pktcls.NewCondIPv6(&pktcls.IPv6MatchFlowLabel{FlowLabel: 0x12345}),
+		},
+		{
+			Name:  "nexthdr IPv6Cond",
+			Class: "nexthdr=tcp",
+			Tree:  pktcls.NewCondIPv6(&pktcls.IPv6MatchNextHeader{NextHeader: 6}),
+		},
+		{
+			Name:  "icmptype ICMPCond",
+			Class: "icmptype=8",
+			Tree:  pktcls.NewCondICMP(&pktcls.ICMPMatchType{ICMPType: 8}),
+		},
+		{
+			Name:  "IPv4 and IPv6 dst",
+			Class: "any(dst=12.12.12.0/26,dst=2001:db8::/32)",
+			Tree: pktcls.CondAnyOf{
+				pktcls.NewCondIPv4(&pktcls.IPv4MatchDestination{Net: net}),
+				pktcls.NewCondIPv6(&pktcls.IPv6MatchDestination{Net: net6}),
+			},
+		},
 	}
 
 	for _, tc := range testCases {
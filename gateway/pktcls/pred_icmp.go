@@ -0,0 +1,73 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pktcls
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ICMP represents the type and code of an ICMP message, irrespective of
+// whether it is an ICMPv4 or an ICMPv6 message.
+type ICMP struct {
+	Type uint8
+	Code uint8
+}
+
+// ICMPPredicate describes a single test on ICMP fields.
+type ICMPPredicate interface {
+	// Eval returns true if the ICMP message matched the predicate
+	Eval(*ICMP) bool
+	Typer
+	fmt.Stringer
+}
+
+var _ ICMPPredicate = (*ICMPMatchType)(nil)
+
+// ICMPMatchType checks whether the ICMP type matches. The numbering of ICMPv4
+// and ICMPv6 types differs, e.g., an echo request is type 8 in ICMPv4 and type
+// 128 in ICMPv6.
+type ICMPMatchType struct {
+	ICMPType uint8
+}
+
+func (m *ICMPMatchType) Type() string {
+	return "MatchICMPType"
+}
+
+func (m *ICMPMatchType) Eval(i *ICMP) bool {
+	return m.ICMPType == i.Type
+}
+
+func (m *ICMPMatchType) String() string {
+	return fmt.Sprintf("icmptype=%d", m.ICMPType)
+}
+
+func (m *ICMPMatchType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		jsonContainer{
+			"Type": fmt.Sprintf("%d", m.ICMPType),
+		},
+	)
+}
+
+func (m *ICMPMatchType) UnmarshalJSON(b []byte) error {
+	i, err := unmarshalUintField(b, "MatchICMPType", "Type", 8)
+	if err != nil {
+		return err
+	}
+	m.ICMPType = uint8(i)
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routemgr
+
+import (
+	"context"
+
+	"inet.af/netaddr"
+
+	"github.com/scionproto/scion/gateway/control"
+	"github.com/scionproto/scion/pkg/log"
+)
+
+// RouteAnnouncer announces IP prefixes to another routing backend.
+type RouteAnnouncer interface {
+	// Announce starts announcing the prefix. Duplicates are a no-op.
+	Announce(prefix netaddr.IPPrefix)
+	// Withdraw stops announcing the prefix.
+	Withdraw(prefix netaddr.IPPrefix)
+}
+
+// BGPExporter is a one-way exporter of routes to a BGP speaker. The next hop
+// of the routes is not exported, the speaker announces itself as next hop.
+type BGPExporter struct {
+	// Announcer is the BGP speaker the routes are exported to.
+	Announcer RouteAnnouncer
+}
+
+// Run exports the route updates received from the consumer until the context
+// is canceled or the consumer is closed.
+func (e *BGPExporter) Run(ctx context.Context, consumer control.Consumer) {
+	logger := log.FromCtx(ctx)
+	// Routes for the same prefix can be published with different next hops.
+	// The prefix is withdrawn once the last of them is deleted.
+	refs := make(map[netaddr.IPPrefix]int)
+	for {
+		select {
+		case update, ok := <-consumer.Updates():
+			if !ok {
+				return
+			}
+			prefix, ok := netaddr.FromStdIPNet(update.Prefix)
+			if !ok {
+				logger.Error("Invalid route prefix, not exported to BGP",
+					"prefix", update.Prefix)
+				continue
+			}
+			if update.IsAdd {
+				refs[prefix]++
+				if refs[prefix] == 1 {
+					e.Announcer.Announce(prefix)
+				}
+				continue
+			}
+			if refs[prefix] == 0 {
+				continue
+			}
+			refs[prefix]--
+			if refs[prefix] == 0 {
+				delete(refs, prefix)
+				e.Announcer.Withdraw(prefix)
+			}
+		case <-ctx.Done():
+			consumer.Close()
+			return
+		}
+	}
+}
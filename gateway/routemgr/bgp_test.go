@@ -0,0 +1,95 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routemgr
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"inet.af/netaddr"
+
+	"github.com/scionproto/scion/gateway/control"
+)
+
+type fakeAnnouncer struct {
+	mtx       sync.Mutex
+	announced map[netaddr.IPPrefix]bool
+}
+
+func (a *fakeAnnouncer) Announce(prefix netaddr.IPPrefix) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.announced[prefix] = true
+}
+
+func (a *fakeAnnouncer) Withdraw(prefix netaddr.IPPrefix) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	delete(a.announced, prefix)
+}
+
+func (a *fakeAnnouncer) Announced() []string {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	var result []string
+	for p := range a.announced {
+		result = append(result, p.String())
+	}
+	return result
+}
+
+func TestBGPExporter(t *testing.T) {
+	db := createRouteDB()
+	defer db.Close()
+	pub := db.NewPublisher()
+
+	announcer := &fakeAnnouncer{announced: make(map[netaddr.IPPrefix]bool)}
+	exporter := &BGPExporter{Announcer: announcer}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		exporter.Run(ctx, db.NewConsumer())
+	}()
+	// Stop the exporter before the database is closed, both close the consumer.
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	_, prefix, _ := net.ParseCIDR("192.168.0.0/24")
+	route1 := control.Route{Prefix: prefix, NextHop: net.ParseIP("10.0.0.1")}
+	route2 := control.Route{Prefix: prefix, NextHop: net.ParseIP("10.0.0.2")}
+
+	pub.AddRoute(route1)
+	pub.AddRoute(route2)
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"192.168.0.0/24"}, announcer.Announced())
+	}, time.Second, time.Millisecond)
+
+	// The prefix is still announced as long as one of the routes exists.
+	pub.DeleteRoute(route1)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, []string{"192.168.0.0/24"}, announcer.Announced())
+
+	pub.DeleteRoute(route2)
+	assert.Eventually(t, func() bool {
+		return len(announcer.Announced()) == 0
+	}, time.Second, time.Millisecond)
+}
@@ -51,6 +51,13 @@ func (l *Linux) NewPublisher() control.Publisher {
 	return l.exportedRoutes.NewPublisher()
 }
 
+// NewConsumer creates a consumer that receives the updates of the routes
+// exported to Linux. It can be used to export the same routes to other routing
+// backends.
+func (l *Linux) NewConsumer() control.Consumer {
+	return l.exportedRoutes.NewConsumer()
+}
+
 func (l *Linux) Close() {
 	l.init()
 	close(l.closeChan)
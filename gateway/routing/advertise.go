@@ -41,6 +41,41 @@ func AdvertiseList(pol *Policy, from, to addr.IA) ([]netaddr.IPPrefix, error) {
 	return nets, nil
 }
 
+// FilterAdvertised returns the prefixes that may be advertised for the given
+// policy and ISD-ASes. A prefix may be advertised if it is contained in the
+// network of an advertise rule that matches the ISD-ASes. This is used for
+// prefixes that are learned dynamically, e.g., from a local router, in which
+// case the advertise rules select the prefixes instead of listing them.
+func FilterAdvertised(pol *Policy, from, to addr.IA,
+	prefixes []netaddr.IPPrefix) ([]netaddr.IPPrefix, error) {
+
+	if pol == nil {
+		return []netaddr.IPPrefix{}, nil
+	}
+	var sb netaddr.IPSetBuilder
+	for _, r := range pol.Rules {
+		if r.Action != Advertise || !r.From.Match(from) || !r.To.Match(to) {
+			continue
+		}
+		set, err := r.Network.IPSet()
+		if err != nil {
+			return nil, err
+		}
+		sb.AddSet(set)
+	}
+	allowed, err := sb.IPSet()
+	if err != nil {
+		return nil, err
+	}
+	nets := []netaddr.IPPrefix{}
+	for _, prefix := range prefixes {
+		if allowed.ContainsPrefix(prefix) {
+			nets = append(nets, prefix)
+		}
+	}
+	return nets, nil
+}
+
 // StaticAdvertised returns the list of all prefixes that can be advertised.
 // Used for reporting purposes.
 func StaticAdvertised(pol *Policy) []*net.IPNet {
@@ -58,6 +58,48 @@ func TestAdvertiseList(t *testing.T) {
 	assert.Empty(t, prefixes)
 }
 
+func TestFilterAdvertised(t *testing.T) {
+	from := addr.MustIAFrom(1, 0)
+	to := addr.MustIAFrom(2, 0)
+	imported := xtest.MustParseIPPrefixes(t, "10.0.1.0/24", "10.1.0.0/16", "127.1.0.0/16",
+		"192.168.0.0/24")
+
+	prefixes, err := routing.FilterAdvertised(nil, from, to, imported)
+	assert.NoError(t, err)
+	assert.Empty(t, prefixes)
+
+	policy := routing.Policy{DefaultAction: routing.Accept}
+	prefixes, err = routing.FilterAdvertised(&policy, from, to, imported)
+	assert.NoError(t, err)
+	assert.Empty(t, prefixes)
+
+	policy.Rules = append(policy.Rules, routing.Rule{
+		Action:  routing.Advertise,
+		From:    routing.NewIAMatcher(t, "1-0"),
+		To:      routing.NewIAMatcher(t, "2-0"),
+		Network: routing.NewNetworkMatcher(t, "127.1.0.0/30,10.0.0.0/16"),
+	})
+	policy.Rules = append(policy.Rules, routing.Rule{
+		Action:  routing.Advertise,
+		From:    routing.NewIAMatcher(t, "1-0"),
+		To:      routing.NewIAMatcher(t, "2-0"),
+		Network: routing.NewNetworkMatcher(t, "!10.0.0.0/8"),
+	})
+	policy.Rules = append(policy.Rules, routing.Rule{
+		Action:  routing.Accept,
+		From:    routing.NewIAMatcher(t, "1-0"),
+		To:      routing.NewIAMatcher(t, "2-0"),
+		Network: routing.NewNetworkMatcher(t, "10.1.0.0/16"),
+	})
+	prefixes, err = routing.FilterAdvertised(&policy, from, to, imported)
+	assert.NoError(t, err)
+	assert.Equal(t, xtest.MustParseIPPrefixes(t, "10.0.1.0/24", "127.1.0.0/16",
+		"192.168.0.0/24"), prefixes)
+	prefixes, err = routing.FilterAdvertised(&policy, to, from, imported)
+	assert.NoError(t, err)
+	assert.Empty(t, prefixes)
+}
+
 func TestStaticAdvertiseList(t *testing.T) {
 	policy := routing.Policy{DefaultAction: routing.Reject}
 
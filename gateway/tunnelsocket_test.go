@@ -0,0 +1,145 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/gateway/control"
+	"github.com/scionproto/scion/gateway/dataplane"
+	"github.com/scionproto/scion/gateway/pktcls"
+	"github.com/scionproto/scion/gateway/routemgr"
+	"github.com/scionproto/scion/gateway/xnet"
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/private/xtest"
+)
+
+// TestTunnelSocket wires the tunnel socket of the gateway through the tunnel
+// reader and the route manager, like Gateway.Run does.
+func TestTunnelSocket(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	g := &Gateway{
+		TunnelSocket: &net.UnixAddr{Name: filepath.Join(dir, "gateway.sock"), Net: "unixgram"},
+	}
+	// Keep the socket device to inspect the installed routes.
+	devices := make(chan *xnet.SocketDevice, 1)
+	opener := g.tunnelDeviceOpener(ctx)
+	recordingOpener := control.DeviceOpenerFunc(
+		func(ctx context.Context, ia addr.IA) (control.Device, error) {
+			dev, err := opener.Open(ctx, ia)
+			if err == nil {
+				devices <- dev.(*xnet.SocketDevice)
+			}
+			return dev, err
+		},
+	)
+
+	session := make(sessionChan, 1)
+	rt := dataplane.NewRoutingTable([]*control.RoutingChain{
+		{
+			Prefixes:        []*net.IPNet{xtest.MustParseCIDR(t, "10.1.0.0/16")},
+			TrafficMatchers: []control.TrafficMatcher{{ID: 1, Matcher: pktcls.CondTrue}},
+		},
+	})
+	require.NoError(t, rt.SetSession(1, session))
+	art := &dataplane.AtomicRoutingTable{}
+	art.SetRoutingTable(rt)
+
+	tunnelReader := TunnelReader{
+		DeviceOpener: recordingOpener,
+		Router:       art,
+	}
+	deviceManager := &routemgr.SingleDeviceManager{
+		DeviceOpener: tunnelReader.GetDeviceOpenerWithAsyncReader(ctx),
+	}
+	routePublisherFactory := createRouteManager(ctx, deviceManager, nil)
+	defer routePublisherFactory.(*routemgr.Linux).Close()
+
+	// The ingress side holds a handle to the device while the gateway runs.
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	handle, err := deviceManager.Get(ctx, ia)
+	require.NoError(t, err)
+	defer handle.Close()
+	dev := <-devices
+
+	route := control.Route{Prefix: xtest.MustParseCIDR(t, "10.1.0.0/16"), IA: ia}
+	publisher := routePublisherFactory.NewPublisher()
+	defer publisher.Close()
+	publisher.AddRoute(route)
+	assert.Eventually(t, func() bool {
+		routes := dev.Routes()
+		return len(routes) == 1 && routes[0].String() == route.String()
+	}, time.Second, 10*time.Millisecond)
+
+	// Packets from the local stack are forwarded to the session of the route.
+	peer, err := net.ListenUnixgram("unixgram",
+		&net.UnixAddr{Name: filepath.Join(dir, "stack.sock"), Net: "unixgram"})
+	require.NoError(t, err)
+	defer peer.Close()
+	pkt := newIPv4Packet(t, net.IP{10, 1, 0, 1})
+	_, err = peer.WriteTo(pkt, g.TunnelSocket)
+	require.NoError(t, err)
+	select {
+	case got := <-session:
+		assert.Equal(t, pkt, got)
+	case <-time.After(time.Second):
+		t.Fatal("packet not forwarded to session")
+	}
+
+	// Packets from remote gateways are written back to the local stack.
+	_, err = handle.Write([]byte("incoming"))
+	require.NoError(t, err)
+	require.NoError(t, peer.SetReadDeadline(time.Now().Add(time.Second)))
+	buf := make([]byte, 100)
+	n, _, err := peer.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "incoming", string(buf[:n]))
+}
+
+// sessionChan is a session that passes the written packets to the channel.
+type sessionChan chan []byte
+
+func (s sessionChan) Write(packet gopacket.Packet) {
+	s <- append([]byte(nil), packet.Data()...)
+}
+
+func newIPv4Packet(t *testing.T, dst net.IP) []byte {
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	err := gopacket.SerializeLayers(buf, opts,
+		&layers.IPv4{
+			Version:  4,
+			TTL:      64,
+			Protocol: layers.IPProtocolUDP,
+			SrcIP:    net.IP{192, 168, 0, 1},
+			DstIP:    dst,
+		},
+		gopacket.Payload([]byte("outgoing")),
+	)
+	require.NoError(t, err)
+	return buf.Bytes()
+}
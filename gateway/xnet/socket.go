@@ -0,0 +1,216 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xnet
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/scionproto/scion/gateway/control"
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/log"
+	"github.com/scionproto/scion/pkg/private/serrors"
+)
+
+// SocketOpener returns a control.DeviceOpener that opens devices exchanging IP
+// packets over a datagram socket instead of a Linux tunnel device. Each
+// datagram carries exactly one IP packet. This does not require any
+// privileges, the other end of the socket is typically a userspace TCP/IP
+// stack.
+//
+// The socket is bound to the local address, which must be a *net.UDPAddr or a
+// *net.UnixAddr of network "unixgram". Packets are sent to the remote address.
+// If remote is nil, packets are sent to the address from which the last packet
+// was received. Unix socket peers must thus bind their socket to a path. Since
+// anybody who can reach a UDP socket could otherwise redirect the packets, the
+// remote address can only be omitted for UDP sockets bound to a loopback
+// address. If it is set, UDP datagrams from other addresses are discarded.
+//
+// The routes are not installed anywhere, the device only keeps track of them.
+func SocketOpener(local, remote net.Addr) control.DeviceOpener {
+	f := func(ctx context.Context, _ addr.IA) (control.Device, error) {
+		return OpenSocket(ctx, local, remote)
+	}
+	return control.DeviceOpenerFunc(f)
+}
+
+// OpenSocket creates a new socket-backed device. See SocketOpener for details.
+func OpenSocket(ctx context.Context, local, remote net.Addr) (*SocketDevice, error) {
+	logger := log.FromCtx(ctx)
+	var conn net.PacketConn
+	var path string
+	switch a := local.(type) {
+	case *net.UDPAddr:
+		if remote == nil && !a.IP.IsLoopback() {
+			return nil, serrors.New("remote address required for UDP socket not bound to "+
+				"loopback address", "addr", a)
+		}
+		c, err := net.ListenUDP("udp", a)
+		if err != nil {
+			return nil, serrors.WrapStr("listening on UDP socket", err, "addr", a)
+		}
+		conn = c
+	case *net.UnixAddr:
+		if err := removeStaleSocket(a.Name); err != nil {
+			return nil, err
+		}
+		c, err := net.ListenUnixgram("unixgram", a)
+		if err != nil {
+			return nil, serrors.WrapStr("listening on Unix socket", err, "addr", a)
+		}
+		conn, path = c, a.Name
+	default:
+		return nil, serrors.New("unsupported socket address", "addr", local)
+	}
+	logger.Debug("Successfully opened socket device", "local", conn.LocalAddr(),
+		"remote", remote)
+	return &SocketDevice{
+		conn:        conn,
+		path:        path,
+		remote:      remote,
+		fixedRemote: remote != nil,
+		routes:      make(map[string]control.Route),
+	}, nil
+}
+
+// removeStaleSocket removes the Unix socket of a previous run, binding fails
+// otherwise. Files that are not sockets are left alone.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return serrors.WrapStr("checking stale Unix socket", err, "path", path)
+	}
+	if info.Mode()&fs.ModeSocket == 0 {
+		return serrors.New("refusing to remove file that is not a socket", "path", path,
+			"mode", info.Mode())
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return serrors.WrapStr("removing stale Unix socket", err, "path", path)
+	}
+	return nil
+}
+
+// SocketDevice is a device that exchanges IP packets over a datagram socket.
+type SocketDevice struct {
+	conn net.PacketConn
+	// path is the path of the Unix socket that is removed on close.
+	path string
+
+	mtx         sync.Mutex
+	remote      net.Addr
+	fixedRemote bool
+	routes      map[string]control.Route
+}
+
+// LocalAddr returns the address the socket is bound to.
+func (d *SocketDevice) LocalAddr() net.Addr {
+	return d.conn.LocalAddr()
+}
+
+// Read reads the next IP packet from the socket. UDP datagrams that do not
+// come from the configured remote address are discarded.
+func (d *SocketDevice) Read(b []byte) (int, error) {
+	for {
+		n, from, err := d.conn.ReadFrom(b)
+		if err != nil {
+			return n, err
+		}
+		if d.accept(from) {
+			return n, nil
+		}
+	}
+}
+
+// accept returns whether a datagram from the address is accepted. If the
+// remote address is not fixed, it is learned from the accepted datagrams.
+func (d *SocketDevice) accept(from net.Addr) bool {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if d.fixedRemote {
+		remote, ok := d.remote.(*net.UDPAddr)
+		if !ok {
+			return true
+		}
+		udp, ok := from.(*net.UDPAddr)
+		return ok && udp.IP.Equal(remote.IP) && udp.Port == remote.Port
+	}
+	if from != nil && from.String() != "" {
+		d.remote = from
+	}
+	return true
+}
+
+// Write writes the IP packet to the remote address. If no remote address is
+// known yet, an error is returned.
+func (d *SocketDevice) Write(b []byte) (int, error) {
+	d.mtx.Lock()
+	remote := d.remote
+	d.mtx.Unlock()
+	if remote == nil {
+		return 0, serrors.New("remote address of socket device not known yet")
+	}
+	return d.conn.WriteTo(b, remote)
+}
+
+// Close closes the socket.
+func (d *SocketDevice) Close() error {
+	err := d.conn.Close()
+	if d.path != "" {
+		os.Remove(d.path)
+	}
+	return err
+}
+
+// AddRoute records the route.
+func (d *SocketDevice) AddRoute(ctx context.Context, r *control.Route) error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.routes[r.String()] = *r
+	log.FromCtx(ctx).Debug("Successfully added route", "socket", d.conn.LocalAddr(),
+		"route", r)
+	return nil
+}
+
+// DeleteRoute removes the route.
+func (d *SocketDevice) DeleteRoute(ctx context.Context, r *control.Route) error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if _, ok := d.routes[r.String()]; !ok {
+		return serrors.New("route not found", "route", r)
+	}
+	delete(d.routes, r.String())
+	log.FromCtx(ctx).Debug("Successfully deleted route", "socket", d.conn.LocalAddr(),
+		"route", r)
+	return nil
+}
+
+// Routes returns the routes that are currently added to the device.
+func (d *SocketDevice) Routes() []control.Route {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	routes := make([]control.Route, 0, len(d.routes))
+	for _, r := range d.routes {
+		routes = append(routes, r)
+	}
+	return routes
+}
@@ -0,0 +1,134 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xnet_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/gateway/control"
+	"github.com/scionproto/scion/gateway/xnet"
+	"github.com/scionproto/scion/pkg/private/xtest"
+)
+
+func TestSocketDeviceUDP(t *testing.T) {
+	dev, err := xnet.OpenSocket(context.Background(),
+		&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}, nil)
+	require.NoError(t, err)
+	defer dev.Close()
+
+	// Without a known remote, packets cannot be written.
+	_, err = dev.Write([]byte{1})
+	assert.Error(t, err)
+
+	peer, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer peer.Close()
+	testExchange(t, dev, peer, dev.LocalAddr())
+}
+
+func TestSocketDeviceUnix(t *testing.T) {
+	dir := t.TempDir()
+	local := &net.UnixAddr{Name: filepath.Join(dir, "gateway.sock"), Net: "unixgram"}
+	remote := &net.UnixAddr{Name: filepath.Join(dir, "stack.sock"), Net: "unixgram"}
+
+	peer, err := net.ListenUnixgram("unixgram", remote)
+	require.NoError(t, err)
+	defer peer.Close()
+	dev, err := xnet.OpenSocket(context.Background(), local, remote)
+	require.NoError(t, err)
+	defer dev.Close()
+	testExchange(t, dev, peer, local)
+}
+
+func TestSocketDeviceUDPRemote(t *testing.T) {
+	_, err := xnet.OpenSocket(context.Background(), &net.UDPAddr{IP: net.IPv4zero}, nil)
+	assert.Error(t, err, "remote is required for non-loopback address")
+
+	peer, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer peer.Close()
+	other, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer other.Close()
+
+	dev, err := xnet.OpenSocket(context.Background(),
+		&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}, peer.LocalAddr())
+	require.NoError(t, err)
+	defer dev.Close()
+
+	// Datagrams from other addresses are discarded.
+	_, err = other.WriteTo([]byte("injected"), dev.LocalAddr())
+	require.NoError(t, err)
+	testExchange(t, dev, peer, dev.LocalAddr())
+}
+
+func TestSocketDeviceUnixStale(t *testing.T) {
+	dir := t.TempDir()
+	local := &net.UnixAddr{Name: filepath.Join(dir, "gateway.sock"), Net: "unixgram"}
+
+	// A stale socket is replaced.
+	stale, err := net.ListenUnixgram("unixgram", local)
+	require.NoError(t, err)
+	require.NoError(t, stale.Close())
+	dev, err := xnet.OpenSocket(context.Background(), local, nil)
+	require.NoError(t, err)
+	require.NoError(t, dev.Close())
+
+	// Other files are left alone.
+	require.NoError(t, os.WriteFile(local.Name, []byte("data"), 0o600))
+	_, err = xnet.OpenSocket(context.Background(), local, nil)
+	assert.Error(t, err)
+	content, err := os.ReadFile(local.Name)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(content))
+}
+
+func TestSocketDeviceRoutes(t *testing.T) {
+	dev, err := xnet.OpenSocket(context.Background(),
+		&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}, nil)
+	require.NoError(t, err)
+	defer dev.Close()
+
+	r := &control.Route{Prefix: xtest.MustParseCIDR(t, "10.1.0.0/16")}
+	require.NoError(t, dev.AddRoute(context.Background(), r))
+	assert.Equal(t, []control.Route{*r}, dev.Routes())
+	require.NoError(t, dev.DeleteRoute(context.Background(), r))
+	assert.Empty(t, dev.Routes())
+	assert.Error(t, dev.DeleteRoute(context.Background(), r))
+}
+
+func testExchange(t *testing.T, dev *xnet.SocketDevice, peer net.PacketConn,
+	devAddr net.Addr) {
+
+	_, err := peer.WriteTo([]byte("outgoing"), devAddr)
+	require.NoError(t, err)
+	buf := make([]byte, 100)
+	n, err := dev.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "outgoing", string(buf[:n]))
+
+	_, err = dev.Write([]byte("incoming"))
+	require.NoError(t, err)
+	n, _, err = peer.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "incoming", string(buf[:n]))
+}
@@ -39,6 +39,7 @@ func (e *OpError) Error() string {
 
 var _ net.Conn = (*Conn)(nil)
 var _ net.PacketConn = (*Conn)(nil)
+var _ PathMTUReporter = (*Conn)(nil)
 
 type Conn struct {
 	conn PacketConn
@@ -79,3 +80,24 @@ func (c *Conn) SetDeadline(t time.Time) error {
 func (c *Conn) Close() error {
 	return c.conn.Close()
 }
+
+// PathMTU returns the current MTU estimate of the path to dst. If dst is nil,
+// the remote address of the connection is used. The boolean is false if the
+// networking context does not track path MTUs or no estimate is known.
+func (c *Conn) PathMTU(dst net.Addr) (uint16, bool) {
+	pathMTUs := c.scionNet.PathMTUs
+	if pathMTUs == nil {
+		return 0, false
+	}
+	if dst == nil && c.remote != nil {
+		dst = c.remote
+	}
+	switch a := dst.(type) {
+	case *UDPAddr:
+		return pathMTUs.Lookup(a.IA, a.Path)
+	case *SVCAddr:
+		return pathMTUs.Lookup(a.IA, a.Path)
+	default:
+		return 0, false
+	}
+}
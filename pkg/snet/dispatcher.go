@@ -90,11 +90,15 @@ type SCMPHandler interface {
 
 // DefaultSCMPHandler handles SCMP messages received from the network. If a
 // revocation handler is configured, it is informed of any received interface
-// down messages.
+// down messages. If path MTUs are configured, they are updated with any
+// received packet too big messages.
 type DefaultSCMPHandler struct {
 	// RevocationHandler manages revocations received via SCMP. If nil, the
 	// handler is not called.
 	RevocationHandler RevocationHandler
+	// PathMTUs is updated with the MTU of packet too big messages. If nil,
+	// packet too big messages are ignored.
+	PathMTUs *PathMTUs
 	// SCMPErrors reports the total number of SCMP Errors encountered.
 	SCMPErrors metrics.Counter
 }
@@ -125,6 +129,14 @@ func (h DefaultSCMPHandler) Handle(pkt *Packet) error {
 			RawTimestamp: util.TimeToSecs(time.Now()),
 			RawTTL:       10,
 		})
+	case slayers.SCMPTypePacketTooBig:
+		if h.PathMTUs != nil {
+			if err := h.PathMTUs.HandlePacketTooBig(pkt); err != nil {
+				log.Debug("Ignoring invalid packet too big message", "err", err,
+					"src", pkt.Source)
+			}
+		}
+		return nil
 	default:
 		// Only handle connectivity down for now
 		log.Debug("Ignoring scmp packet", "scmp", typeCode, "src", pkt.Source)
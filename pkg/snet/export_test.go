@@ -15,6 +15,8 @@
 package snet
 
 import (
+	"time"
+
 	"github.com/scionproto/scion/pkg/slayers"
 )
 
@@ -22,3 +24,7 @@ func SCMPParameterProblemWithCode(m SCMPParameterProblem, c slayers.SCMPCode) SC
 	m.code = c
 	return m
 }
+
+func SetPathMTUsNow(t *PathMTUs, now func() time.Time) {
+	t.now = now
+}
@@ -136,7 +136,11 @@ func (c *SCIONPacketConn) WriteTo(pkt *Packet, ov *net.UDPAddr) error {
 	if err := pkt.Serialize(); err != nil {
 		return serrors.WrapStr("serialize SCION packet", err)
 	}
+	return c.writeSerialized(pkt, ov)
+}
 
+// writeSerialized sends the packet that was already serialized into pkt.Bytes.
+func (c *SCIONPacketConn) writeSerialized(pkt *Packet, ov *net.UDPAddr) error {
 	// Send message
 	n, err := c.Conn.WriteTo(pkt.Bytes, ov)
 	if err != nil {
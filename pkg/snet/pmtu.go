@@ -0,0 +1,262 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/google/gopacket"
+
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/slayers"
+	"github.com/scionproto/scion/pkg/slayers/path"
+	"github.com/scionproto/scion/pkg/slayers/path/scion"
+)
+
+// PathMTUReporter is implemented by connections that track the MTU of the
+// paths they send packets on.
+type PathMTUReporter interface {
+	// PathMTU returns the current MTU estimate for packets to dst. The
+	// boolean is false if no estimate is known.
+	PathMTU(dst net.Addr) (uint16, bool)
+}
+
+// PacketTooBigError is returned by write calls if the packet exceeds the known
+// MTU of the path.
+type PacketTooBigError struct {
+	// MTU is the current MTU estimate of the path.
+	MTU uint16
+	// Size is the size of the SCION packet that was not sent.
+	Size int
+}
+
+func (e *PacketTooBigError) Error() string {
+	return fmt.Sprintf("packet too big: size %d exceeds path MTU %d", e.Size, e.MTU)
+}
+
+// Is reports whether target is syscall.EMSGSIZE. The error is thus handled like
+// the error of a socket that refuses to send a packet that exceeds the MTU of
+// the link, e.g., by QUIC path MTU discovery, which drops such probe packets.
+func (e *PacketTooBigError) Is(target error) bool {
+	return target == syscall.EMSGSIZE
+}
+
+// PathMTUs tracks the MTU of paths. The MTU of a path is lowered whenever a
+// SCMP Packet Too Big message is received for a packet that was sent on the
+// path. Paths are identified by their destination ISD-AS and their hop fields,
+// such that the estimate applies to all packets sent on the same path,
+// irrespective of the destination host.
+//
+// If ProbeInterval is set, an estimate is discarded once it has not been
+// lowered for the interval. The next large packet then probes whether the path
+// supports a larger MTU again, in the spirit of packetization layer path MTU
+// discovery. If the path still does not support the size, the router sends
+// another Packet Too Big message.
+//
+// PathMTUs is safe for concurrent use.
+type PathMTUs struct {
+	// ProbeInterval is the interval after which an estimate is discarded. If
+	// zero, estimates are kept forever.
+	ProbeInterval time.Duration
+
+	mtx     sync.Mutex
+	entries map[string]*pathMTUEntry
+	// smallest is the smallest MTU estimate, or 0 if there is none. It is
+	// accessed atomically, such that packets that are not larger can be sent
+	// without looking up the estimate of their path.
+	smallest uint32
+	// now is used to get the current time; tests can override it.
+	now func() time.Time
+}
+
+type pathMTUEntry struct {
+	mtu     uint16
+	updated time.Time
+}
+
+// Lookup returns the MTU estimate for the path p to the destination dst. The
+// boolean is false if no estimate is known.
+func (t *PathMTUs) Lookup(dst addr.IA, p DataplanePath) (uint16, bool) {
+	if p == nil {
+		return 0, false
+	}
+	var s slayers.SCION
+	if err := p.SetPath(&s); err != nil {
+		return 0, false
+	}
+	key, err := pathMTUKey(dst, s.PathType, s.Path)
+	if err != nil {
+		return 0, false
+	}
+	return t.get(key)
+}
+
+// PathMTU returns the MTU of p, considering both the MTU in the metadata of
+// the path and the learned estimate. It returns 0 if neither is known.
+func (t *PathMTUs) PathMTU(p Path) uint16 {
+	var mtu uint16
+	if md := p.Metadata(); md != nil {
+		mtu = md.MTU
+	}
+	if learned, ok := t.Lookup(p.Destination(), p.Dataplane()); ok {
+		if mtu == 0 || learned < mtu {
+			mtu = learned
+		}
+	}
+	return mtu
+}
+
+// Update lowers the MTU estimate of the path on which the quoted packet was
+// sent to mtu. The quote is the payload of a SCMP Packet Too Big message. It
+// must at least contain the SCION header of the offending packet.
+func (t *PathMTUs) Update(quote []byte, mtu uint16) error {
+	if mtu == 0 {
+		return serrors.New("invalid MTU", "mtu", mtu)
+	}
+	var s slayers.SCION
+	if err := s.DecodeFromBytes(quote, gopacket.NilDecodeFeedback); err != nil {
+		return serrors.WrapStr("decoding quoted packet", err)
+	}
+	key, err := pathMTUKey(s.DstIA, s.PathType, s.Path)
+	if err != nil {
+		return err
+	}
+	t.lower(key, mtu)
+	return nil
+}
+
+// exceeds returns the MTU estimate of the path of the serialized packet raw if
+// the packet exceeds it. The boolean is false if the packet does not exceed
+// the estimate, or if no estimate is known.
+func (t *PathMTUs) exceeds(raw []byte) (uint16, bool) {
+	smallest := atomic.LoadUint32(&t.smallest)
+	if smallest == 0 || len(raw) <= int(smallest) {
+		return 0, false
+	}
+	var s slayers.SCION
+	if err := s.DecodeFromBytes(raw, gopacket.NilDecodeFeedback); err != nil {
+		return 0, false
+	}
+	key, err := pathMTUKey(s.DstIA, s.PathType, s.Path)
+	if err != nil {
+		return 0, false
+	}
+	mtu, ok := t.get(key)
+	if !ok || len(raw) <= int(mtu) {
+		return 0, false
+	}
+	return mtu, true
+}
+
+// hasEstimates returns whether any MTU estimate is known.
+func (t *PathMTUs) hasEstimates() bool {
+	return atomic.LoadUint32(&t.smallest) != 0
+}
+
+// HandlePacketTooBig updates the estimate based on the SCMP Packet Too Big
+// message in pkt.
+func (t *PathMTUs) HandlePacketTooBig(pkt *Packet) error {
+	msg, ok := pkt.Payload.(SCMPPacketTooBig)
+	if !ok {
+		return serrors.New("not a packet too big message", "type", fmt.Sprintf("%T",
+			pkt.Payload))
+	}
+	return t.Update(msg.Payload, msg.MTU)
+}
+
+func (t *PathMTUs) get(key string) (uint16, bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	e, ok := t.entries[key]
+	if !ok {
+		return 0, false
+	}
+	if t.ProbeInterval > 0 && t.timeNow().Sub(e.updated) >= t.ProbeInterval {
+		delete(t.entries, key)
+		t.updateSmallest()
+		return 0, false
+	}
+	return e.mtu, true
+}
+
+func (t *PathMTUs) lower(key string, mtu uint16) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.entries == nil {
+		t.entries = make(map[string]*pathMTUEntry)
+	}
+	now := t.timeNow()
+	e, ok := t.entries[key]
+	if !ok || mtu < e.mtu ||
+		(t.ProbeInterval > 0 && now.Sub(e.updated) >= t.ProbeInterval) {
+
+		t.entries[key] = &pathMTUEntry{mtu: mtu, updated: now}
+		t.updateSmallest()
+		return
+	}
+	if mtu == e.mtu {
+		e.updated = now
+	}
+}
+
+// updateSmallest recomputes the smallest estimate. The caller must hold the
+// lock.
+func (t *PathMTUs) updateSmallest() {
+	var smallest uint16
+	for _, e := range t.entries {
+		if smallest == 0 || e.mtu < smallest {
+			smallest = e.mtu
+		}
+	}
+	atomic.StoreUint32(&t.smallest, uint32(smallest))
+}
+
+func (t *PathMTUs) timeNow() time.Time {
+	if t.now == nil {
+		return time.Now()
+	}
+	return t.now()
+}
+
+// pathMTUKey computes the key that identifies a path to dst. For SCION paths,
+// only the hop fields are considered, because routers update the path meta
+// header and the segment identifiers in the info fields while forwarding.
+func pathMTUKey(dst addr.IA, pathType path.Type, p path.Path) (string, error) {
+	if p == nil {
+		return "", serrors.New("no path")
+	}
+	raw := make([]byte, 9+p.Len())
+	binary.BigEndian.PutUint64(raw, uint64(dst))
+	raw[8] = byte(pathType)
+	if err := p.SerializeTo(raw[9:]); err != nil {
+		return "", serrors.WrapStr("serializing path", err)
+	}
+	if pathType != scion.PathType {
+		return string(raw), nil
+	}
+	var base scion.Base
+	if err := base.DecodeFromBytes(raw[9:]); err != nil {
+		return "", serrors.WrapStr("decoding path", err)
+	}
+	hops := raw[9+scion.MetaLen+base.NumINF*path.InfoLen:]
+	return string(raw[:9]) + string(hops), nil
+}
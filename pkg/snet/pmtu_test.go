@@ -0,0 +1,200 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet_test
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/pkg/slayers/path"
+	"github.com/scionproto/scion/pkg/slayers/path/scion"
+	"github.com/scionproto/scion/pkg/snet"
+	"github.com/scionproto/scion/pkg/snet/mock_snet"
+	snetpath "github.com/scionproto/scion/pkg/snet/path"
+)
+
+func pmtuPath(t *testing.T, egress uint16, currHF uint8, segID uint16) snetpath.SCION {
+	p := scion.Decoded{
+		Base: scion.Base{
+			PathMeta: scion.MetaHdr{
+				CurrHF: currHF,
+				SegLen: [3]uint8{2, 0, 0},
+			},
+			NumINF:  1,
+			NumHops: 2,
+		},
+		InfoFields: []path.InfoField{{ConsDir: true, SegID: segID}},
+		HopFields:  []path.HopField{{ConsEgress: egress}, {ConsIngress: 1}},
+	}
+	raw := make([]byte, p.Len())
+	require.NoError(t, p.SerializeTo(raw))
+	return snetpath.SCION{Raw: raw}
+}
+
+// pmtuQuote returns the packet that is quoted in a packet too big message
+// for a packet sent on p.
+func pmtuQuote(t *testing.T, dst addr.IA, p snet.DataplanePath) []byte {
+	pkt := &snet.Packet{
+		PacketInfo: snet.PacketInfo{
+			Destination: snet.SCIONAddress{
+				IA:   dst,
+				Host: addr.HostIPv4(net.ParseIP("127.0.0.2").To4()),
+			},
+			Source: snet.SCIONAddress{
+				IA:   xtest.MustParseIA("1-ff00:0:110"),
+				Host: addr.HostIPv4(net.ParseIP("127.0.0.1").To4()),
+			},
+			Path: p,
+			Payload: snet.UDPPayload{
+				SrcPort: 25,
+				DstPort: 1925,
+				Payload: make([]byte, 1400),
+			},
+		},
+	}
+	require.NoError(t, pkt.Serialize())
+	return pkt.Bytes
+}
+
+func TestPathMTUs(t *testing.T) {
+	dst := xtest.MustParseIA("1-ff00:0:112")
+	sent := pmtuPath(t, 4, 0, 0x1234)
+	// Routers advance the path and update the segment identifier.
+	inTransit := pmtuPath(t, 4, 1, 0x4321)
+	other := pmtuPath(t, 5, 0, 0x1234)
+
+	t.Run("unknown", func(t *testing.T) {
+		var mtus snet.PathMTUs
+		_, ok := mtus.Lookup(dst, sent)
+		assert.False(t, ok)
+	})
+	t.Run("lowered by packet too big", func(t *testing.T) {
+		var mtus snet.PathMTUs
+		require.NoError(t, mtus.Update(pmtuQuote(t, dst, inTransit), 1300))
+		mtu, ok := mtus.Lookup(dst, sent)
+		assert.True(t, ok)
+		assert.Equal(t, uint16(1300), mtu)
+		_, ok = mtus.Lookup(dst, other)
+		assert.False(t, ok)
+		_, ok = mtus.Lookup(xtest.MustParseIA("1-ff00:0:113"), sent)
+		assert.False(t, ok)
+
+		// Larger values do not raise the estimate.
+		require.NoError(t, mtus.Update(pmtuQuote(t, dst, sent), 1400))
+		mtu, _ = mtus.Lookup(dst, sent)
+		assert.Equal(t, uint16(1300), mtu)
+		require.NoError(t, mtus.Update(pmtuQuote(t, dst, sent), 1280))
+		mtu, _ = mtus.Lookup(dst, sent)
+		assert.Equal(t, uint16(1280), mtu)
+	})
+	t.Run("invalid quote", func(t *testing.T) {
+		var mtus snet.PathMTUs
+		assert.Error(t, mtus.Update([]byte{0x01, 0x02}, 1300))
+		assert.Error(t, mtus.Update(pmtuQuote(t, dst, sent), 0))
+	})
+	t.Run("probe interval", func(t *testing.T) {
+		now := time.Now()
+		mtus := &snet.PathMTUs{ProbeInterval: time.Minute}
+		snet.SetPathMTUsNow(mtus, func() time.Time { return now })
+		require.NoError(t, mtus.Update(pmtuQuote(t, dst, sent), 1300))
+		now = now.Add(59 * time.Second)
+		_, ok := mtus.Lookup(dst, sent)
+		assert.True(t, ok)
+		now = now.Add(time.Second)
+		_, ok = mtus.Lookup(dst, sent)
+		assert.False(t, ok)
+	})
+	t.Run("path metadata", func(t *testing.T) {
+		var mtus snet.PathMTUs
+		p := snetpath.Path{
+			Dst:           dst,
+			DataplanePath: sent,
+			Meta:          snet.PathMetadata{MTU: 1472},
+		}
+		assert.Equal(t, uint16(1472), mtus.PathMTU(p))
+		require.NoError(t, mtus.Update(pmtuQuote(t, dst, sent), 1300))
+		assert.Equal(t, uint16(1300), mtus.PathMTU(p))
+	})
+	t.Run("scmp handler", func(t *testing.T) {
+		var mtus snet.PathMTUs
+		h := snet.DefaultSCMPHandler{PathMTUs: &mtus}
+		pkt := &snet.Packet{
+			PacketInfo: snet.PacketInfo{
+				Payload: snet.SCMPPacketTooBig{
+					MTU:     1350,
+					Payload: pmtuQuote(t, dst, inTransit),
+				},
+			},
+		}
+		require.NoError(t, h.Handle(pkt))
+		mtu, ok := mtus.Lookup(dst, sent)
+		assert.True(t, ok)
+		assert.Equal(t, uint16(1350), mtu)
+	})
+}
+
+func TestConnWritePathMTU(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	local := xtest.MustParseIA("1-ff00:0:110")
+	dst := xtest.MustParseIA("1-ff00:0:112")
+	p := pmtuPath(t, 4, 0, 0x1234)
+	mtus := &snet.PathMTUs{}
+	require.NoError(t, mtus.Update(pmtuQuote(t, dst, p), 1300))
+
+	packetConn := mock_snet.NewMockPacketConn(ctrl)
+	disp := mock_snet.NewMockPacketDispatcherService(ctrl)
+	disp.EXPECT().Register(gomock.Any(), local, gomock.Any(), addr.SvcNone).
+		Return(packetConn, uint16(40000), nil)
+	n := &snet.SCIONNetwork{
+		LocalIA:    local,
+		Dispatcher: disp,
+		PathMTUs:   mtus,
+	}
+	remote := &snet.UDPAddr{
+		IA:      dst,
+		Host:    &net.UDPAddr{IP: net.ParseIP("127.0.0.2"), Port: 1925},
+		Path:    p,
+		NextHop: &net.UDPAddr{IP: net.ParseIP("127.0.0.3"), Port: 30041},
+	}
+	conn, err := n.Dial(context.Background(), "udp",
+		&net.UDPAddr{IP: net.ParseIP("127.0.0.1")}, remote, addr.SvcNone)
+	require.NoError(t, err)
+
+	mtu, ok := conn.PathMTU(nil)
+	assert.True(t, ok)
+	assert.Equal(t, uint16(1300), mtu)
+
+	packetConn.EXPECT().WriteTo(gomock.Any(), remote.NextHop).Return(nil)
+	_, err = conn.Write(make([]byte, 1000))
+	assert.NoError(t, err)
+
+	_, err = conn.Write(make([]byte, 1300))
+	var tooBig *snet.PacketTooBigError
+	require.ErrorAs(t, err, &tooBig)
+	assert.Equal(t, uint16(1300), tooBig.MTU)
+	// QUIC drops path MTU probes that fail with EMSGSIZE.
+	assert.ErrorIs(t, err, syscall.EMSGSIZE)
+}
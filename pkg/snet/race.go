@@ -0,0 +1,252 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/scionproto/scion/pkg/private/serrors"
+)
+
+const (
+	// DefaultAttemptDelay is the default delay between starting connection
+	// attempts on consecutive paths.
+	DefaultAttemptDelay = 250 * time.Millisecond
+	// DefaultFallbackDelay is the default delay before the IP fallback attempt
+	// is started.
+	DefaultFallbackDelay = 300 * time.Millisecond
+)
+
+// StreamDialer establishes a connection to a SCION address. The path and the
+// next hop of the destination address determine the path that is used. The
+// squic.ConnDialer implements this interface.
+type StreamDialer interface {
+	Dial(ctx context.Context, dst net.Addr) (net.Conn, error)
+}
+
+// IPDialer establishes a connection over IP. The net.Dialer implements this
+// interface.
+type IPDialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// RaceTarget describes the destination of a RacingDialer.
+type RaceTarget struct {
+	// Remote is the SCION address of the destination. The path of the
+	// address is replaced by each of the Paths.
+	Remote *UDPAddr
+	// Paths are the candidate paths, in order of preference.
+	Paths []Path
+	// Fallback is the legacy IP endpoint (host:port) of the destination. If
+	// empty, no IP fallback is attempted.
+	Fallback string
+	// FallbackNetwork is the network of the IP fallback. If empty, "tcp" is
+	// used.
+	FallbackNetwork string
+}
+
+// RaceResult is the winner of a race.
+type RaceResult struct {
+	// Conn is the established connection.
+	Conn net.Conn
+	// Path is the path that is used by Conn. It is nil if the IP fallback won.
+	Path Path
+	// Fallback is set if the IP fallback won.
+	Fallback bool
+}
+
+// RacingDialer races connection establishment over several SCION paths and,
+// optionally, a legacy IP endpoint in the style of happy eyeballs (RFC 8305).
+// Attempts are started in order of preference, each AttemptDelay after the
+// previous one, or immediately if the previous attempt failed. The first
+// attempt that succeeds wins; all other attempts are canceled and connections
+// that are established nevertheless are closed.
+type RacingDialer struct {
+	// Dialer is used for the SCION attempts.
+	Dialer StreamDialer
+	// FallbackDialer is used for the IP fallback attempt. If nil, a zero
+	// net.Dialer is used.
+	FallbackDialer IPDialer
+	// AttemptDelay is the delay between starting attempts on consecutive
+	// paths. If zero, DefaultAttemptDelay is used.
+	AttemptDelay time.Duration
+	// FallbackDelay is the delay after which the IP fallback attempt is
+	// started, independent of the progress of the SCION attempts. If zero,
+	// DefaultFallbackDelay is used.
+	FallbackDelay time.Duration
+}
+
+type raceAttempt struct {
+	conn net.Conn
+	path Path
+	err  error
+}
+
+// Dial races connection attempts to target and returns the first connection
+// that is established. If all attempts fail, the returned error contains the
+// errors of all attempts.
+func (d *RacingDialer) Dial(ctx context.Context, target RaceTarget) (*RaceResult, error) {
+	if len(target.Paths) == 0 && target.Fallback == "" {
+		return nil, serrors.New("no paths and no fallback")
+	}
+	if len(target.Paths) > 0 && (target.Remote == nil || d.Dialer == nil) {
+		return nil, serrors.New("remote and dialer required for SCION attempts")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	total := len(target.Paths)
+	if target.Fallback != "" {
+		total++
+	}
+	// The channel can hold all results such that attempts never block.
+	results := make(chan raceAttempt, total)
+	running := 0
+
+	next := 0
+	startNext := func() {
+		p := target.Paths[next]
+		next++
+		running++
+		go func() {
+			remote := target.Remote.Copy()
+			remote.Path = p.Dataplane()
+			remote.NextHop = p.UnderlayNextHop()
+			conn, err := d.Dialer.Dial(ctx, remote)
+			results <- raceAttempt{conn: conn, path: p, err: err}
+		}()
+	}
+	startFallback := func() {
+		running++
+		go func() {
+			conn, err := d.fallbackDialer().DialContext(ctx, target.fallbackNetwork(),
+				target.Fallback)
+			results <- raceAttempt{conn: conn, err: err}
+		}()
+	}
+
+	var attemptTimer <-chan time.Time
+	if next < len(target.Paths) {
+		startNext()
+		attemptTimer = time.After(d.attemptDelay())
+	}
+	var fallbackTimer <-chan time.Time
+	if target.Fallback != "" {
+		if running == 0 {
+			startFallback()
+		} else {
+			fallbackTimer = time.After(d.fallbackDelay())
+		}
+	}
+
+	var errs serrors.List
+	for {
+		select {
+		case <-attemptTimer:
+			attemptTimer = nil
+			if next < len(target.Paths) {
+				startNext()
+				attemptTimer = time.After(d.attemptDelay())
+			}
+		case <-fallbackTimer:
+			fallbackTimer = nil
+			startFallback()
+		case r := <-results:
+			running--
+			if r.err == nil {
+				closeLosers(results, running)
+				return &RaceResult{Conn: r.conn, Path: r.path, Fallback: r.path == nil}, nil
+			}
+			if r.path != nil {
+				errs = append(errs, serrors.WrapStr("dialing over path", r.err,
+					"path", fmtRacePath(r.path)))
+			} else {
+				errs = append(errs, serrors.WrapStr("dialing fallback", r.err,
+					"address", target.Fallback))
+			}
+			// Start the next attempt immediately if an attempt failed.
+			if next < len(target.Paths) {
+				startNext()
+				attemptTimer = time.After(d.attemptDelay())
+				continue
+			}
+			if fallbackTimer != nil && running == 0 {
+				fallbackTimer = nil
+				startFallback()
+				continue
+			}
+			if running == 0 && fallbackTimer == nil {
+				return nil, serrors.WrapStr("all attempts failed", errs.ToError())
+			}
+		case <-ctx.Done():
+			closeLosers(results, running)
+			return nil, serrors.WrapStr("dialing canceled", ctx.Err(),
+				"errors", errs.ToError())
+		}
+	}
+}
+
+// closeLosers closes the connections of the n attempts that are still running
+// once they complete.
+func closeLosers(results <-chan raceAttempt, n int) {
+	if n == 0 {
+		return
+	}
+	go func() {
+		for i := 0; i < n; i++ {
+			if r := <-results; r.err == nil {
+				r.conn.Close()
+			}
+		}
+	}()
+}
+
+func (d *RacingDialer) fallbackDialer() IPDialer {
+	if d.FallbackDialer == nil {
+		return &net.Dialer{}
+	}
+	return d.FallbackDialer
+}
+
+func (d *RacingDialer) attemptDelay() time.Duration {
+	if d.AttemptDelay == 0 {
+		return DefaultAttemptDelay
+	}
+	return d.AttemptDelay
+}
+
+func (d *RacingDialer) fallbackDelay() time.Duration {
+	if d.FallbackDelay == 0 {
+		return DefaultFallbackDelay
+	}
+	return d.FallbackDelay
+}
+
+func (t RaceTarget) fallbackNetwork() string {
+	if t.FallbackNetwork == "" {
+		return "tcp"
+	}
+	return t.FallbackNetwork
+}
+
+func fmtRacePath(p Path) string {
+	if md := p.Metadata(); md != nil && len(md.Interfaces) > 0 {
+		return fmt.Sprint(md.Interfaces)
+	}
+	return fmt.Sprintf("%s>%s", p.Source(), p.Destination())
+}
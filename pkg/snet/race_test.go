@@ -0,0 +1,224 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/pkg/snet"
+	snetpath "github.com/scionproto/scion/pkg/snet/path"
+)
+
+// raceConn is a connection that records whether it was closed.
+type raceConn struct {
+	net.Conn
+	closed int32
+}
+
+func (c *raceConn) Close() error {
+	atomic.StoreInt32(&c.closed, 1)
+	return nil
+}
+
+func (c *raceConn) isClosed() bool {
+	return atomic.LoadInt32(&c.closed) == 1
+}
+
+// raceBehavior describes how an attempt behaves: it completes after delay,
+// and fails if err is set.
+type raceBehavior struct {
+	delay time.Duration
+	err   error
+	conn  *raceConn
+}
+
+func (b raceBehavior) dial(ctx context.Context) (net.Conn, error) {
+	select {
+	case <-time.After(b.delay):
+	case <-ctx.Done():
+		if b.conn != nil {
+			// Simulate a connection that completes despite the cancellation.
+			return b.conn, nil
+		}
+		return nil, ctx.Err()
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.conn, nil
+}
+
+// raceDialer dials according to the behavior of the next hop port.
+type raceDialer map[int]raceBehavior
+
+func (d raceDialer) Dial(ctx context.Context, dst net.Addr) (net.Conn, error) {
+	return d[dst.(*snet.UDPAddr).NextHop.Port].dial(ctx)
+}
+
+type raceIPDialer raceBehavior
+
+func (d raceIPDialer) DialContext(ctx context.Context, _, _ string) (net.Conn, error) {
+	return raceBehavior(d).dial(ctx)
+}
+
+func racePath(port int) snet.Path {
+	return snetpath.Path{
+		Src:     xtest.MustParseIA("1-ff00:0:110"),
+		Dst:     xtest.MustParseIA("1-ff00:0:112"),
+		NextHop: &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port},
+	}
+}
+
+func TestRacingDialer(t *testing.T) {
+	remote := &snet.UDPAddr{
+		IA:   xtest.MustParseIA("1-ff00:0:112"),
+		Host: &net.UDPAddr{IP: net.IPv4(127, 0, 0, 2), Port: 443},
+	}
+	paths := []snet.Path{racePath(1), racePath(2), racePath(3)}
+
+	t.Run("no candidates", func(t *testing.T) {
+		d := &snet.RacingDialer{}
+		_, err := d.Dial(context.Background(), snet.RaceTarget{Remote: remote})
+		assert.Error(t, err)
+	})
+	t.Run("first path wins", func(t *testing.T) {
+		c1, c2 := &raceConn{}, &raceConn{}
+		d := &snet.RacingDialer{
+			Dialer: raceDialer{
+				1: {delay: 10 * time.Millisecond, conn: c1},
+				2: {delay: 10 * time.Millisecond, conn: c2},
+			},
+			AttemptDelay: time.Second,
+		}
+		r, err := d.Dial(context.Background(), snet.RaceTarget{
+			Remote: remote,
+			Paths:  paths[:2],
+		})
+		require.NoError(t, err)
+		assert.Equal(t, c1, r.Conn)
+		assert.Equal(t, paths[0], r.Path)
+		assert.False(t, r.Fallback)
+	})
+	t.Run("faster path wins and loser is closed", func(t *testing.T) {
+		slow, fast := &raceConn{}, &raceConn{}
+		d := &snet.RacingDialer{
+			Dialer: raceDialer{
+				1: {delay: time.Second, conn: slow},
+				2: {delay: 0, conn: fast},
+			},
+			AttemptDelay: 10 * time.Millisecond,
+		}
+		r, err := d.Dial(context.Background(), snet.RaceTarget{
+			Remote: remote,
+			Paths:  paths[:2],
+		})
+		require.NoError(t, err)
+		assert.Equal(t, fast, r.Conn)
+		assert.Equal(t, paths[1], r.Path)
+		assert.Eventually(t, slow.isClosed, time.Second, 5*time.Millisecond)
+		assert.False(t, fast.isClosed())
+	})
+	t.Run("failure starts next attempt immediately", func(t *testing.T) {
+		c := &raceConn{}
+		d := &snet.RacingDialer{
+			Dialer: raceDialer{
+				1: {err: serrors.New("unreachable")},
+				2: {conn: c},
+			},
+			AttemptDelay: time.Hour,
+		}
+		r, err := d.Dial(context.Background(), snet.RaceTarget{
+			Remote: remote,
+			Paths:  paths[:2],
+		})
+		require.NoError(t, err)
+		assert.Equal(t, paths[1], r.Path)
+	})
+	t.Run("fallback wins", func(t *testing.T) {
+		c := &raceConn{}
+		d := &snet.RacingDialer{
+			Dialer: raceDialer{
+				1: {delay: time.Second, err: serrors.New("timeout")},
+			},
+			FallbackDialer: raceIPDialer{conn: c},
+			FallbackDelay:  10 * time.Millisecond,
+		}
+		r, err := d.Dial(context.Background(), snet.RaceTarget{
+			Remote:   remote,
+			Paths:    paths[:1],
+			Fallback: "127.0.0.2:443",
+		})
+		require.NoError(t, err)
+		assert.True(t, r.Fallback)
+		assert.Nil(t, r.Path)
+		assert.Equal(t, c, r.Conn)
+	})
+	t.Run("fallback started early if all paths failed", func(t *testing.T) {
+		c := &raceConn{}
+		d := &snet.RacingDialer{
+			Dialer: raceDialer{
+				1: {err: serrors.New("unreachable")},
+			},
+			FallbackDialer: raceIPDialer{conn: c},
+			FallbackDelay:  time.Hour,
+		}
+		r, err := d.Dial(context.Background(), snet.RaceTarget{
+			Remote:   remote,
+			Paths:    paths[:1],
+			Fallback: "127.0.0.2:443",
+		})
+		require.NoError(t, err)
+		assert.True(t, r.Fallback)
+	})
+	t.Run("all fail", func(t *testing.T) {
+		d := &snet.RacingDialer{
+			Dialer: raceDialer{
+				1: {err: serrors.New("unreachable")},
+				2: {err: serrors.New("unreachable")},
+				3: {err: serrors.New("unreachable")},
+			},
+			FallbackDialer: raceIPDialer{err: serrors.New("refused")},
+		}
+		_, err := d.Dial(context.Background(), snet.RaceTarget{
+			Remote:   remote,
+			Paths:    paths,
+			Fallback: "127.0.0.2:443",
+		})
+		assert.Error(t, err)
+	})
+	t.Run("context canceled", func(t *testing.T) {
+		d := &snet.RacingDialer{
+			Dialer: raceDialer{
+				1: {delay: time.Hour},
+			},
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := d.Dial(ctx, snet.RaceTarget{
+			Remote: remote,
+			Paths:  paths[:1],
+		})
+		assert.Error(t, err)
+	})
+}
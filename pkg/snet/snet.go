@@ -30,12 +30,24 @@
 //
 // Multiple networking contexts can share the same SCIOND and/or dispatcher.
 //
+// Instead of registering with the dispatcher, a networking context can open
+// sockets directly on the underlay by using an UnderlayPacketDispatcherService.
+// This requires the local AS to configure an end host port range, within which
+// the border routers deliver packets directly to the SCION/UDP destination
+// port.
+//
 // Write calls never return SCMP errors directly. If a write call caused an
 // SCMP message to be received by the Conn, it can be inspected by calling
 // Read. In this case, the error value is non-nil and can be type asserted to
 // *OpError. Method SCMP() can be called on the error to extract the SCMP
 // header.
 //
+// If the networking context is configured with PathMTUs, the MTU of paths is
+// learned from SCMP Packet Too Big messages, and writes of packets that exceed
+// the known MTU of their path fail with a PacketTooBigError instead of being
+// dropped silently by a router. The current estimate can be queried with
+// Conn.PathMTU.
+//
 // Important: not draining SCMP errors via Read calls can cause the dispatcher
 // to shutdown the socket (see https://github.com/scionproto/scion/pull/1356).
 // To prevent this on a Conn object with only Write calls, run a separate
@@ -69,6 +81,12 @@ type SCIONNetwork struct {
 	// (that implements net.Conn). If unset, the default reply pather is used,
 	// which parses the incoming path as a path.Path and reverses it.
 	ReplyPather ReplyPather
+	// PathMTUs holds the MTU estimates of paths. If set, writes of packets
+	// that exceed the estimate of their path fail with a PacketTooBigError.
+	// The estimates are lowered by the SCMP handler of the Dispatcher, which
+	// should thus be configured with the same PathMTUs. If nil, the path MTU
+	// is not tracked.
+	PathMTUs *PathMTUs
 	// Metrics holds the metrics emitted by the network.
 	Metrics SCIONNetworkMetrics
 }
@@ -177,6 +177,17 @@ func (c *acceptingConn) Write(b []byte) (n int, err error) {
 	return stream.Write(b)
 }
 
+// CloseWrite closes the sending direction of the stream. The peer reads
+// io.EOF once it received all data written before. Reading is still possible.
+func (c *acceptingConn) CloseWrite() error {
+	c.acceptStream()
+	stream, err := c.waitForStream()
+	if err != nil {
+		return err
+	}
+	return stream.Close()
+}
+
 // waitForStream blocks until a stream has been accepted, or failed to accept.
 func (c *acceptingConn) waitForStream() (quic.Stream, error) {
 	<-c.acceptedStream
@@ -338,7 +349,11 @@ func (c *acceptingConn) Close() error {
 	return nil
 }
 
-// ConnDialer dials a net.Conn over a QUIC stream.
+var _ snet.StreamDialer = ConnDialer{}
+
+// ConnDialer dials a net.Conn over a QUIC stream. It can be used as the
+// dialer of a snet.RacingDialer to race connection establishment over several
+// paths.
 type ConnDialer struct {
 	// Conn is the connection to initiate QUIC Sessions on. It can be shared
 	// between clients and servers, because QUIC connection IDs are used to
@@ -370,6 +385,16 @@ func (d ConnDialer) Dial(ctx context.Context, dst net.Addr) (net.Conn, error) {
 		if d.QUICConfig != nil {
 			quicConfig = d.QUICConfig.Clone()
 		}
+		// If the SCION path MTU is known, writes of larger packets fail, so
+		// there is no point in probing for a larger MTU. If the MTU is only
+		// learned later, the failing probes are dropped, because the error of
+		// the write matches EMSGSIZE.
+		if _, ok := d.pathMTU(dst); ok {
+			if quicConfig == nil {
+				quicConfig = &quic.Config{}
+			}
+			quicConfig.DisablePathMTUDiscovery = true
+		}
 
 		var err error
 		session, err = quic.DialContext(ctx, d.Conn, dst, addressStr, tlsConfig, quicConfig)
@@ -397,13 +422,25 @@ func (d ConnDialer) Dial(ctx context.Context, dst net.Addr) (net.Conn, error) {
 		session.CloseWithError(OpenStreamError, "")
 		return nil, serrors.WrapStr("opening stream", err)
 	}
-	return &acceptedConn{
+	conn := &acceptedConn{
 		stream:  stream,
 		session: session,
-	}, nil
+	}
+	if r, ok := d.Conn.(snet.PathMTUReporter); ok {
+		conn.pathMTUs = r
+	}
+	return conn, nil
 
 }
 
+func (d ConnDialer) pathMTU(dst net.Addr) (uint16, bool) {
+	r, ok := d.Conn.(snet.PathMTUReporter)
+	if !ok {
+		return 0, false
+	}
+	return r.PathMTU(dst)
+}
+
 // computeAddressStr returns a parseable version of the SCION address for use
 // with QUIC SNI.
 func computeAddressStr(address net.Addr) string {
@@ -413,10 +450,33 @@ func computeAddressStr(address net.Addr) string {
 	return address.String()
 }
 
+// PathMTUConn is implemented by the connections that are returned by
+// ConnListener.Accept and ConnDialer.Dial. It reports the MTU of the SCION
+// path that is used by the underlying QUIC session.
+type PathMTUConn interface {
+	net.Conn
+	// PathMTU returns the current MTU estimate of the SCION path that is used
+	// by the connection. The boolean is false if no estimate is known.
+	PathMTU() (uint16, bool)
+}
+
+var _ PathMTUConn = (*acceptedConn)(nil)
+
 // acceptedConn is a net.Conn wrapper for a QUIC stream.
 type acceptedConn struct {
 	stream  quic.Stream
 	session quic.Connection
+	// pathMTUs reports the MTU of the SCION path of the session. It is nil if
+	// the underlying connection does not track path MTUs.
+	pathMTUs snet.PathMTUReporter
+}
+
+// PathMTU implements PathMTUConn.
+func (c *acceptedConn) PathMTU() (uint16, bool) {
+	if c.pathMTUs == nil {
+		return 0, false
+	}
+	return c.pathMTUs.PathMTU(c.session.RemoteAddr())
 }
 
 func (c *acceptedConn) Read(b []byte) (int, error) {
@@ -427,6 +487,12 @@ func (c *acceptedConn) Write(b []byte) (int, error) {
 	return c.stream.Write(b)
 }
 
+// CloseWrite closes the sending direction of the stream. The peer reads
+// io.EOF once it received all data written before. Reading is still possible.
+func (c *acceptedConn) CloseWrite() error {
+	return c.stream.Close()
+}
+
 func (c *acceptedConn) SetDeadline(t time.Time) error {
 	return c.stream.SetDeadline(t)
 }
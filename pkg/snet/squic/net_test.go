@@ -19,6 +19,7 @@ package squic_test
 import (
 	"context"
 	"crypto/tls"
+	"io"
 	"net"
 	"os"
 	"sync"
@@ -198,6 +199,35 @@ func TestEstablishConnection(t *testing.T) {
 		err = clientConn.Close()
 		require.NoError(t, err)
 	})
+	t.Run("close write", func(t *testing.T) {
+		srv, srvPacketConn := netListener(t)
+
+		dialer := connDialer(t)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		clientConn, err := dialer.Dial(ctx, srvPacketConn.LocalAddr())
+		require.NoError(t, err)
+		defer clientConn.Close()
+
+		_, err = clientConn.Write([]byte("client hello"))
+		require.NoError(t, err)
+		require.NoError(t, clientConn.(interface{ CloseWrite() error }).CloseWrite())
+
+		srvConn, err := srv.Accept()
+		require.NoError(t, err)
+		defer srvConn.Close()
+		msg, err := io.ReadAll(srvConn)
+		require.NoError(t, err)
+		assert.Equal(t, "client hello", string(msg))
+
+		// The server can still write after the client closed its direction.
+		_, err = srvConn.Write([]byte("server hello"))
+		require.NoError(t, err)
+		require.NoError(t, srvConn.(interface{ CloseWrite() error }).CloseWrite())
+		msg, err = io.ReadAll(clientConn)
+		require.NoError(t, err)
+		assert.Equal(t, "server hello", string(msg))
+	})
 }
 
 func netListener(t *testing.T) (net.Listener, *net.UDPConn) {
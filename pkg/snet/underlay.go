@@ -0,0 +1,123 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"syscall"
+
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/private/serrors"
+)
+
+var _ PacketDispatcherService = (*UnderlayPacketDispatcherService)(nil)
+
+// UnderlayPacketDispatcherService opens UDP sockets directly on the underlay
+// network, bypassing the dispatcher. The border routers of the local AS
+// deliver SCION/UDP packets to the underlay port equal to the SCION/UDP
+// destination port, if that port is within the end host port range of the AS
+// (see the endhost_port_range topology attribute). SCMP messages are delivered
+// to the port given by the identifier (for echo and traceroute replies) or by
+// the L4 source port of the quoted packet (for SCMP errors). Applications that
+// send SCMP echo or traceroute requests on such a socket must thus use the
+// local port as identifier.
+//
+// Registering SVC addresses is not supported; services that need to be
+// reachable via SVC addresses must still use the dispatcher.
+type UnderlayPacketDispatcherService struct {
+	// StartPort and EndPort delimit the inclusive range of ports on which the
+	// border routers deliver packets directly to end hosts. Both must be set.
+	StartPort uint16
+	EndPort   uint16
+	// SCMPHandler is invoked for packets that contain an SCMP L4. If the
+	// handler is nil, errors are returned back to applications every time an
+	// SCMP message is received.
+	SCMPHandler SCMPHandler
+	// Metrics injected into SCIONPacketConn.
+	SCIONPacketConnMetrics SCIONPacketConnMetrics
+}
+
+// Register opens a UDP socket on the registration address. If the port of the
+// registration address is 0, a free port within the configured range is
+// chosen. Otherwise, the port must be within the configured range.
+func (s *UnderlayPacketDispatcherService) Register(ctx context.Context, ia addr.IA,
+	registration *net.UDPAddr, svc addr.HostSVC) (PacketConn, uint16, error) {
+
+	if s.StartPort == 0 || s.StartPort > s.EndPort {
+		return nil, 0, serrors.New("invalid port range",
+			"start", s.StartPort, "end", s.EndPort)
+	}
+	if registration == nil {
+		return nil, 0, serrors.New("nil registration address")
+	}
+	if svc != addr.SvcNone {
+		return nil, 0, serrors.New("SVC registration not supported without dispatcher",
+			"svc", svc)
+	}
+	var conn *net.UDPConn
+	var err error
+	if registration.Port != 0 {
+		if !s.inRange(uint16(registration.Port)) {
+			return nil, 0, serrors.New("port outside of end host port range",
+				"port", registration.Port, "start", s.StartPort, "end", s.EndPort)
+		}
+		if conn, err = net.ListenUDP("udp", registration); err != nil {
+			return nil, 0, serrors.WrapStr("opening underlay socket", err,
+				"addr", registration)
+		}
+	} else if conn, err = s.listenInRange(ctx, registration); err != nil {
+		return nil, 0, err
+	}
+	return &SCIONPacketConn{
+		Conn:        conn,
+		SCMPHandler: s.SCMPHandler,
+		Metrics:     s.SCIONPacketConnMetrics,
+	}, uint16(conn.LocalAddr().(*net.UDPAddr).Port), nil
+}
+
+// listenInRange opens a socket on the first free port in the range, starting
+// the search at a random port.
+func (s *UnderlayPacketDispatcherService) listenInRange(ctx context.Context,
+	registration *net.UDPAddr) (*net.UDPConn, error) {
+
+	numPorts := int(s.EndPort) - int(s.StartPort) + 1
+	offset := rand.Intn(numPorts)
+	for i := 0; i < numPorts; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		candidate := &net.UDPAddr{
+			IP:   registration.IP,
+			Port: int(s.StartPort) + (offset+i)%numPorts,
+			Zone: registration.Zone,
+		}
+		conn, err := net.ListenUDP("udp", candidate)
+		if err == nil {
+			return conn, nil
+		}
+		if !errors.Is(err, syscall.EADDRINUSE) {
+			return nil, serrors.WrapStr("opening underlay socket", err, "addr", candidate)
+		}
+	}
+	return nil, serrors.New("no free port in end host port range",
+		"start", s.StartPort, "end", s.EndPort)
+}
+
+func (s *UnderlayPacketDispatcherService) inRange(port uint16) bool {
+	return port >= s.StartPort && port <= s.EndPort
+}
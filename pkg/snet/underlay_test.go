@@ -0,0 +1,73 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/pkg/snet"
+)
+
+func TestUnderlayPacketDispatcherServiceRegister(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	localhost := net.IPv4(127, 0, 0, 1)
+
+	t.Run("invalid range", func(t *testing.T) {
+		s := &snet.UnderlayPacketDispatcherService{StartPort: 32000, EndPort: 31000}
+		_, _, err := s.Register(context.Background(), ia, &net.UDPAddr{IP: localhost},
+			addr.SvcNone)
+		assert.Error(t, err)
+	})
+	t.Run("svc not supported", func(t *testing.T) {
+		s := &snet.UnderlayPacketDispatcherService{StartPort: 31000, EndPort: 32767}
+		_, _, err := s.Register(context.Background(), ia, &net.UDPAddr{IP: localhost},
+			addr.SvcCS)
+		assert.Error(t, err)
+	})
+	t.Run("port outside of range", func(t *testing.T) {
+		s := &snet.UnderlayPacketDispatcherService{StartPort: 31000, EndPort: 32767}
+		_, _, err := s.Register(context.Background(), ia,
+			&net.UDPAddr{IP: localhost, Port: 40000}, addr.SvcNone)
+		assert.Error(t, err)
+	})
+	t.Run("random port in range", func(t *testing.T) {
+		s := &snet.UnderlayPacketDispatcherService{StartPort: 31000, EndPort: 32767}
+		conn, port, err := s.Register(context.Background(), ia, &net.UDPAddr{IP: localhost},
+			addr.SvcNone)
+		require.NoError(t, err)
+		defer conn.Close()
+		assert.GreaterOrEqual(t, port, uint16(31000))
+		assert.LessOrEqual(t, port, uint16(32767))
+	})
+	t.Run("range exhausted", func(t *testing.T) {
+		s := &snet.UnderlayPacketDispatcherService{StartPort: 31000, EndPort: 32767}
+		conn, port, err := s.Register(context.Background(), ia, &net.UDPAddr{IP: localhost},
+			addr.SvcNone)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		single := &snet.UnderlayPacketDispatcherService{StartPort: port, EndPort: port}
+		_, _, err = single.Register(context.Background(), ia, &net.UDPAddr{IP: localhost},
+			addr.SvcNone)
+		assert.Error(t, err)
+	})
+}
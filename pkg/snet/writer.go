@@ -85,12 +85,40 @@ func (c *scionConnWriter) WriteTo(b []byte, raddr net.Addr) (int, error) {
 
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
-	if err := c.conn.WriteTo(pkt, nextHop); err != nil {
+	if err := c.write(pkt, nextHop); err != nil {
 		return 0, err
 	}
 	return len(b), nil
 }
 
+// serializedWriter is implemented by packet connections that can send packets
+// that are already serialized.
+type serializedWriter interface {
+	writeSerialized(pkt *Packet, ov *net.UDPAddr) error
+}
+
+// write sends pkt. If the path MTUs are tracked and pkt exceeds the MTU
+// estimate of its path, an error is returned instead. The estimate is only
+// looked up for packets that are larger than the smallest known estimate. The
+// packet is only serialized once, unless the underlying connection cannot send
+// serialized packets.
+func (c *scionConnWriter) write(pkt *Packet, nextHop *net.UDPAddr) error {
+	pathMTUs := c.base.scionNet.PathMTUs
+	if pathMTUs == nil || !pathMTUs.hasEstimates() {
+		return c.conn.WriteTo(pkt, nextHop)
+	}
+	if err := pkt.Serialize(); err != nil {
+		return serrors.WrapStr("serialize SCION packet", err)
+	}
+	if mtu, ok := pathMTUs.exceeds(pkt.Bytes); ok {
+		return &PacketTooBigError{MTU: mtu, Size: len(pkt.Bytes)}
+	}
+	if w, ok := c.conn.(serializedWriter); ok {
+		return w.writeSerialized(pkt, nextHop)
+	}
+	return c.conn.WriteTo(pkt, nextHop)
+}
+
 // Write sends b through a connection with fixed remote address. If the remote
 // address for the connection is unknown, Write returns an error.
 func (c *scionConnWriter) Write(b []byte) (int, error) {
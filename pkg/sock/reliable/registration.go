@@ -25,6 +25,7 @@ import (
 type CommandBitField uint8
 
 const (
+	CmdSCMPErrors  CommandBitField = 0x08
 	CmdBindAddress CommandBitField = 0x04
 	CmdEnableSCMP  CommandBitField = 0x02
 	CmdAlwaysOn    CommandBitField = 0x01
@@ -36,6 +37,9 @@ type Registration struct {
 	PublicAddress *net.UDPAddr
 	BindAddress   *net.UDPAddr
 	SVCAddress    addr.HostSVC
+	// SCMPErrors are the SCMP error types for which the application wants to
+	// receive SCMP error notifications. If empty, no notifications are sent.
+	SCMPErrors SCMPErrorTypes
 }
 
 func (r *Registration) SerializeTo(b []byte) (int, error) {
@@ -54,6 +58,10 @@ func (r *Registration) SerializeTo(b []byte) (int, error) {
 		msg.BindData = &bindAddress
 		bindAddress.SetFromUDPAddr(r.BindAddress)
 	}
+	if r.SCMPErrors != 0 {
+		msg.Command |= CmdSCMPErrors
+		msg.SCMPErrors = r.SCMPErrors
+	}
 	if r.SVCAddress != addr.SvcNone {
 		buffer := make([]byte, 2)
 		binary.BigEndian.PutUint16(buffer, uint16(r.SVCAddress))
@@ -86,6 +94,7 @@ func (r *Registration) DecodeFromBytes(b []byte) error {
 			Port: int(msg.BindData.Port),
 		}
 	}
+	r.SCMPErrors = msg.SCMPErrors
 	return nil
 }
 
@@ -97,6 +106,7 @@ type registrationMessage struct {
 	IA         uint64
 	PublicData registrationAddressField
 	BindData   *registrationAddressField
+	SCMPErrors SCMPErrorTypes
 	SVC        []byte
 }
 
@@ -118,6 +128,13 @@ func (m *registrationMessage) SerializeTo(b []byte) (int, error) {
 		}
 		offset += m.BindData.length()
 	}
+	if (m.Command & CmdSCMPErrors) != 0 {
+		if len(b[offset:]) < 1 {
+			return 0, ErrBufferTooSmall
+		}
+		b[offset] = byte(m.SCMPErrors)
+		offset++
+	}
 	copy(b[offset:], m.SVC)
 	offset += len(m.SVC)
 	return offset, nil
@@ -142,6 +159,13 @@ func (l *registrationMessage) DecodeFromBytes(b []byte) error {
 		}
 		offset += l.BindData.length()
 	}
+	if (l.Command & CmdSCMPErrors) != 0 {
+		if len(b[offset:]) < 1 {
+			return ErrIncompleteMessage
+		}
+		l.SCMPErrors = SCMPErrorTypes(b[offset])
+		offset++
+	}
 	switch len(b[offset:]) {
 	case 0:
 		return nil
@@ -22,6 +22,7 @@ import (
 
 	"github.com/scionproto/scion/pkg/addr"
 	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/pkg/slayers"
 )
 
 func TestRegistrationMessageSerializeTo(t *testing.T) {
@@ -104,6 +105,18 @@ func TestRegistrationMessageSerializeTo(t *testing.T) {
 				0, 80, 1, 10, 2, 3, 4,
 				0, 81, 1, 10, 5, 6, 7, 0, 2},
 		},
+		{
+			Name: "public address with SCMP errors and SVC",
+			Registration: &Registration{
+				IA:            xtest.MustParseIA("1-ff00:0:1"),
+				PublicAddress: &net.UDPAddr{IP: net.IP{10, 2, 3, 4}, Port: 80},
+				SVCAddress:    addr.SvcCS,
+				SCMPErrors: NewSCMPErrorTypes(slayers.SCMPTypePacketTooBig,
+					slayers.SCMPTypeExternalInterfaceDown),
+			},
+			ExpectedData: []byte{0x0b, 17, 0, 1, 0xff, 0, 0, 0, 0, 0x01,
+				0, 80, 1, 10, 2, 3, 4, 0x24, 0, 2},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
@@ -172,6 +185,23 @@ func TestRegistrationMessageDecodeFromBytes(t *testing.T) {
 				SVCAddress:    addr.SvcNone,
 			},
 		},
+		{
+			Name: "public address with SCMP errors",
+			Data: []byte{0x0b, 17, 0, 1, 0xff, 0, 0, 0, 0, 0x01,
+				0, 80, 1, 10, 2, 3, 4, 0x04},
+			ExpectedRegistration: Registration{
+				IA:            xtest.MustParseIA("1-ff00:0:1"),
+				PublicAddress: &net.UDPAddr{IP: net.IP{10, 2, 3, 4}, Port: 80},
+				SVCAddress:    addr.SvcNone,
+				SCMPErrors:    NewSCMPErrorTypes(slayers.SCMPTypePacketTooBig),
+			},
+		},
+		{
+			Name: "incomplete SCMP errors",
+			Data: []byte{0x0b, 17, 0, 1, 0xff, 0, 0, 0, 0, 0x01,
+				0, 80, 1, 10, 2, 3, 4},
+			ExpectedError: ErrIncompleteMessage,
+		},
 		{
 			Name: "incomplete bind starting information",
 			Data: []byte{0x07, 17, 0, 1, 0xff, 0, 0, 0, 0, 0x01,
@@ -36,7 +36,8 @@
 // ReliableSocket registration message format:
 //
 //	13-bytes: [Common header with address type NONE]
-//	 1-byte: Command (bit mask with 0x04=Bind address, 0x02=SCMP enable, 0x01 always set)
+//	 1-byte: Command (bit mask with 0x08=SCMP errors, 0x04=Bind address, 0x02=SCMP enable,
+//	         0x01 always set)
 //	 1-byte: L4 Proto (IANA number)
 //	 8-bytes: ISD-AS
 //	 2-bytes: L4 port
@@ -45,8 +46,25 @@
 //	+2-bytes: L4 bind port  \
 //	+1-byte: Address type    ) (optional bind address)
 //	+var-byte: Bind Address /
+//	+1-byte: SCMP error types (optional bit mask, bit i selects SCMP type i)
 //	+2-bytes: SVC (optional SVC type)
 //
+// ReliableSocket SCMP error notification message format:
+//
+//	13-bytes: [Common header with cookie 0xde00ad01be02ef04 and address type NONE]
+//	 2-bytes: SCMP type and code
+//	 1-byte: Flags (0x01=quoted packet truncated)
+//	 8-bytes: ISD-AS of the SCMP error originator
+//	 8-bytes: Destination ISD-AS of the offending packet
+//	 2-bytes: Destination L4 port of the offending packet (0 if unknown)
+//	 1-byte: Destination address type of the offending packet
+//	 var-byte: Destination address of the offending packet
+//	 2-bytes: Next-hop MTU (Packet Too Big)
+//	 2-bytes: Pointer (Parameter Problem)
+//	 8-bytes: ISD-AS (Interface Down)
+//	 8-bytes: Ingress interface (Internal Connectivity Down)
+//	 8-bytes: Egress interface (External and Internal Connectivity Down)
+//
 // To communicate with SCIOND, clients must first connect to SCIOND's UNIX socket. Messages
 // for SCIOND must set the ADDR TYPE field in the common header to NONE. The payload contains
 // the query for SCIOND (e.g., a request for paths to a SCION destination). The reply header
@@ -60,6 +78,13 @@
 // To send messages to remote SCION hosts, hosts fill in the common header
 // with the address type, the address and the layer 4 port of the remote host.
 //
+// Applications that subscribe to SCMP error types during registration receive
+// SCMP error notifications for their flows on the same socket. Notifications
+// use a different cookie than regular messages, and are passed to the
+// notification handler of the connection instead of being returned by reads.
+// The dispatcher also delivers notifications if the packet quoted in the SCMP
+// error is truncated and cannot be attributed to a single registration.
+//
 // Reads and writes to the connection are thread safe.
 package reliable
 
@@ -98,10 +123,35 @@ type Dispatcher interface {
 		svc addr.HostSVC) (net.PacketConn, uint16, error)
 }
 
+// SCMPErrorHandler is invoked for SCMP error notifications received on a
+// connection.
+type SCMPErrorHandler func(n *SCMPErrorNotification)
+
+// SCMPErrorDispatcher is a Dispatcher that additionally supports subscribing
+// to SCMP error notifications.
+type SCMPErrorDispatcher interface {
+	Dispatcher
+	// RegisterSCMPErrors works like Register. In addition, the dispatcher
+	// delivers notifications for SCMP errors of the given types that concern
+	// the registration, or that cannot be attributed to a single registration
+	// because the quoted packet is truncated. Notifications are passed to
+	// handler, which is called from the goroutine reading from the returned
+	// connection. If handler is nil, notifications are discarded.
+	RegisterSCMPErrors(ctx context.Context, ia addr.IA, address *net.UDPAddr,
+		svc addr.HostSVC, types SCMPErrorTypes,
+		handler SCMPErrorHandler) (net.PacketConn, uint16, error)
+}
+
 // NewDispatcher creates a new dispatcher API endpoint on top of a UNIX
 // STREAM reliable socket. If name is empty, the default dispatcher path is
 // chosen.
 func NewDispatcher(name string) Dispatcher {
+	return NewSCMPErrorDispatcher(name)
+}
+
+// NewSCMPErrorDispatcher works like NewDispatcher, but the returned dispatcher
+// additionally supports subscribing to SCMP error notifications.
+func NewSCMPErrorDispatcher(name string) SCMPErrorDispatcher {
 	if name == "" {
 		name = DefaultDispPath
 	}
@@ -115,7 +165,28 @@ type dispatcherService struct {
 func (d *dispatcherService) Register(ctx context.Context, ia addr.IA, public *net.UDPAddr,
 	svc addr.HostSVC) (net.PacketConn, uint16, error) {
 
-	return registerMetricsWrapper(ctx, d.Address, ia, public, svc)
+	return registerMetricsWrapper(ctx, d.Address, &Registration{
+		IA:            ia,
+		PublicAddress: public,
+		SVCAddress:    svc,
+	})
+}
+
+func (d *dispatcherService) RegisterSCMPErrors(ctx context.Context, ia addr.IA,
+	public *net.UDPAddr, svc addr.HostSVC, types SCMPErrorTypes,
+	handler SCMPErrorHandler) (net.PacketConn, uint16, error) {
+
+	conn, port, err := registerMetricsWrapper(ctx, d.Address, &Registration{
+		IA:            ia,
+		PublicAddress: public,
+		SVCAddress:    svc,
+		SCMPErrors:    types,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	conn.SetSCMPErrorHandler(handler)
+	return conn, port, nil
 }
 
 var _ net.Conn = (*Conn)(nil)
@@ -132,6 +203,8 @@ type Conn struct {
 	writeMutex    sync.Mutex
 	writeBuffer   []byte
 	writeStreamer *WriteStreamer
+
+	scmpErrorHandler SCMPErrorHandler
 }
 
 func newConn(c net.Conn) *Conn {
@@ -160,24 +233,17 @@ func Dial(ctx context.Context, address string) (*Conn, error) {
 	return newConn(c), nil
 }
 
-func registerMetricsWrapper(ctx context.Context, dispatcher string, ia addr.IA,
-	public *net.UDPAddr, svc addr.HostSVC) (*Conn, uint16, error) {
+func registerMetricsWrapper(ctx context.Context, dispatcher string,
+	reg *Registration) (*Conn, uint16, error) {
 
-	conn, port, err := register(ctx, dispatcher, ia, public, svc)
-	labels := metrics.RegisterLabels{Result: labelResult(err), SVC: svc.BaseString()}
+	conn, port, err := register(ctx, dispatcher, reg)
+	labels := metrics.RegisterLabels{Result: labelResult(err), SVC: reg.SVCAddress.BaseString()}
 	metrics.M.Registers(labels).Inc()
 	return conn, port, err
 }
 
-func register(ctx context.Context, dispatcher string, ia addr.IA, public *net.UDPAddr,
-	svc addr.HostSVC) (*Conn, uint16, error) {
-
-	reg := &Registration{
-		IA:            ia,
-		PublicAddress: public,
-		SVCAddress:    svc,
-	}
-
+func register(ctx context.Context, dispatcher string, reg *Registration) (*Conn, uint16, error) {
+	public := reg.PublicAddress
 	conn, err := Dial(ctx, dispatcher)
 	if err != nil {
 		return nil, 0, err
@@ -267,11 +333,25 @@ func (conn *Conn) ReadFrom(buf []byte) (int, net.Addr, error) {
 	return n, addr, err
 }
 
+// SetSCMPErrorHandler sets the handler that is invoked for SCMP error
+// notifications received on the connection. Notifications are consumed while
+// reading; they are never returned to the caller of Read or ReadFrom. If
+// handler is nil, notifications are discarded.
+func (conn *Conn) SetSCMPErrorHandler(handler SCMPErrorHandler) {
+	conn.readMutex.Lock()
+	defer conn.readMutex.Unlock()
+	conn.scmpErrorHandler = handler
+}
+
 func (conn *Conn) readFrom(buf []byte) (int, net.Addr, error) {
 	conn.readMutex.Lock()
 	defer conn.readMutex.Unlock()
 
 	n, err := conn.readPacketizer.Read(conn.readBuffer)
+	for err == nil && isNotification(conn.readBuffer[:n]) {
+		conn.handleNotification(conn.readBuffer[:n])
+		n, err = conn.readPacketizer.Read(conn.readBuffer)
+	}
 	if err != nil {
 		return 0, nil, err
 	}
@@ -326,6 +406,31 @@ func (conn *Conn) writeTo(buf []byte, dst net.Addr) (int, error) {
 	return len(buf), nil
 }
 
+func (conn *Conn) handleNotification(b []byte) {
+	if conn.scmpErrorHandler == nil {
+		return
+	}
+	var n SCMPErrorNotification
+	if err := n.DecodeFromBytes(b); err != nil {
+		log.Debug("Ignoring malformed SCMP error notification", "err", err)
+		return
+	}
+	conn.scmpErrorHandler(&n)
+}
+
+// WriteSCMPErrorNotification blocks until it sends the notification as a
+// single framed message through conn.
+func (conn *Conn) WriteSCMPErrorNotification(n *SCMPErrorNotification) error {
+	conn.writeMutex.Lock()
+	defer conn.writeMutex.Unlock()
+
+	l, err := n.SerializeTo(conn.writeBuffer)
+	if err != nil {
+		return err
+	}
+	return conn.writeStreamer.Write(conn.writeBuffer[:l])
+}
+
 // Read blocks until it reads the next framed message payload from conn and stores it in buf.
 // The first return value contains the number of payload bytes read.
 // buf must be large enough to fit the entire message. No addressing data is returned,
@@ -0,0 +1,179 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reliable
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/slayers"
+)
+
+var (
+	notificationCookie = uint64(0xde00ad01be02ef04)
+)
+
+// SCMPErrorTypes is a bit mask of SCMP error types. Bit i is set if SCMP
+// type i is selected. Only SCMP error types (0-7) can be represented.
+type SCMPErrorTypes uint8
+
+// AllSCMPErrorTypes selects all SCMP error types.
+const AllSCMPErrorTypes SCMPErrorTypes = 0xff
+
+// NewSCMPErrorTypes returns the bit mask selecting the given types. Types that
+// are not SCMP error types are ignored.
+func NewSCMPErrorTypes(types ...slayers.SCMPType) SCMPErrorTypes {
+	var t SCMPErrorTypes
+	for _, typ := range types {
+		if typ < 8 {
+			t |= 1 << typ
+		}
+	}
+	return t
+}
+
+// Contains returns whether typ is selected.
+func (t SCMPErrorTypes) Contains(typ slayers.SCMPType) bool {
+	return typ < 8 && t&(1<<typ) != 0
+}
+
+// Types returns the selected SCMP types in ascending order.
+func (t SCMPErrorTypes) Types() []slayers.SCMPType {
+	var types []slayers.SCMPType
+	for typ := slayers.SCMPType(0); typ < 8; typ++ {
+		if t.Contains(typ) {
+			types = append(types, typ)
+		}
+	}
+	return types
+}
+
+// SCMPErrorNotification contains the parsed contents of an SCMP error message
+// that the dispatcher forwards to applications that subscribed to the error
+// type.
+type SCMPErrorNotification struct {
+	// TypeCode is the type and code of the SCMP error.
+	TypeCode slayers.SCMPTypeCode
+	// Source is the ISD-AS of the originator of the SCMP error.
+	Source addr.IA
+	// Truncated is set if the packet quoted in the SCMP error was truncated
+	// such that the destination fields below could not be extracted.
+	Truncated bool
+	// DestinationIA and Destination identify the remote end of the offending
+	// packet as far as it could be parsed from the quote. Destination is nil
+	// if the destination host is unknown or not an IP address.
+	DestinationIA addr.IA
+	Destination   *net.UDPAddr
+	// MTU is set for Packet Too Big errors.
+	MTU uint16
+	// Pointer is set for Parameter Problem errors.
+	Pointer uint16
+	// IA is set for External Interface Down and Internal Connectivity Down
+	// errors.
+	IA addr.IA
+	// Ingress is set for Internal Connectivity Down errors.
+	Ingress uint64
+	// Egress is set for External Interface Down and Internal Connectivity
+	// Down errors.
+	Egress uint64
+}
+
+const scmpErrorNotificationFixedLen = 2 + 1 + 8 + 8 + 2 + 1 + 2 + 2 + 8 + 8 + 8
+
+func (n *SCMPErrorNotification) SerializeTo(b []byte) (int, error) {
+	var dst []byte
+	dstType := addr.HostTypeNone
+	var dstPort uint16
+	if n.Destination != nil {
+		dstPort = uint16(n.Destination.Port)
+		if n.Destination.IP != nil {
+			dst = normalizeIP(n.Destination.IP)
+			dstType = getIPAddressType(n.Destination.IP)
+		}
+	}
+	payloadLen := scmpErrorNotificationFixedLen + len(dst)
+	f := frame{
+		Cookie:      notificationCookie,
+		AddressType: byte(addr.HostTypeNone),
+		Length:      uint32(payloadLen),
+		Payload:     make([]byte, payloadLen),
+	}
+	p := f.Payload
+	n.TypeCode.SerializeTo(p[0:2])
+	if n.Truncated {
+		p[2] = 1
+	}
+	binary.BigEndian.PutUint64(p[3:], uint64(n.Source))
+	binary.BigEndian.PutUint64(p[11:], uint64(n.DestinationIA))
+	binary.BigEndian.PutUint16(p[19:], dstPort)
+	p[21] = byte(dstType)
+	offset := 22 + copy(p[22:], dst)
+	binary.BigEndian.PutUint16(p[offset:], n.MTU)
+	binary.BigEndian.PutUint16(p[offset+2:], n.Pointer)
+	binary.BigEndian.PutUint64(p[offset+4:], uint64(n.IA))
+	binary.BigEndian.PutUint64(p[offset+12:], n.Ingress)
+	binary.BigEndian.PutUint64(p[offset+20:], n.Egress)
+	return f.SerializeTo(b)
+}
+
+func (n *SCMPErrorNotification) DecodeFromBytes(b []byte) error {
+	var f frame
+	if err := f.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	if f.Cookie != notificationCookie {
+		return ErrBadCookie
+	}
+	p := f.Payload
+	if len(p) < scmpErrorNotificationFixedLen {
+		return ErrIncompleteMessage
+	}
+	n.TypeCode = slayers.CreateSCMPTypeCode(slayers.SCMPType(p[0]), slayers.SCMPCode(p[1]))
+	n.Truncated = p[2]&1 != 0
+	n.Source = addr.IA(binary.BigEndian.Uint64(p[3:]))
+	n.DestinationIA = addr.IA(binary.BigEndian.Uint64(p[11:]))
+	dstPort := binary.BigEndian.Uint16(p[19:])
+	dstType := addr.HostAddrType(p[21])
+	if !isValidReliableSockDestination(dstType) {
+		return serrors.WithCtx(ErrBadAddressType, "type", dstType)
+	}
+	dstLen := getAddressLength(dstType)
+	if len(p) != scmpErrorNotificationFixedLen+dstLen {
+		return ErrBadLength
+	}
+	offset := 22
+	n.Destination = nil
+	if dstType != addr.HostTypeNone || dstPort != 0 {
+		n.Destination = &net.UDPAddr{Port: int(dstPort)}
+		if dstLen > 0 {
+			n.Destination.IP = append(net.IP(nil), p[offset:offset+dstLen]...)
+		}
+	}
+	offset += dstLen
+	n.MTU = binary.BigEndian.Uint16(p[offset:])
+	n.Pointer = binary.BigEndian.Uint16(p[offset+2:])
+	n.IA = addr.IA(binary.BigEndian.Uint64(p[offset+4:]))
+	n.Ingress = binary.BigEndian.Uint64(p[offset+12:])
+	n.Egress = binary.BigEndian.Uint64(p[offset+20:])
+	return nil
+}
+
+// isNotification returns whether the framed message in b is an SCMP error
+// notification.
+func isNotification(b []byte) bool {
+	return len(b) >= 8 && binary.BigEndian.Uint64(b) == notificationCookie
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reliable
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/pkg/slayers"
+)
+
+func TestSCMPErrorTypes(t *testing.T) {
+	types := NewSCMPErrorTypes(slayers.SCMPTypePacketTooBig, slayers.SCMPTypeEchoRequest,
+		slayers.SCMPTypeInternalConnectivityDown)
+	assert.Equal(t, SCMPErrorTypes(0x44), types)
+	assert.True(t, types.Contains(slayers.SCMPTypePacketTooBig))
+	assert.False(t, types.Contains(slayers.SCMPTypeExternalInterfaceDown))
+	assert.False(t, types.Contains(slayers.SCMPTypeEchoRequest))
+	assert.Equal(t, []slayers.SCMPType{slayers.SCMPTypePacketTooBig,
+		slayers.SCMPTypeInternalConnectivityDown}, types.Types())
+	assert.Empty(t, SCMPErrorTypes(0).Types())
+}
+
+func TestSCMPErrorNotificationSerialization(t *testing.T) {
+	testCases := map[string]*SCMPErrorNotification{
+		"packet too big": {
+			TypeCode:      slayers.CreateSCMPTypeCode(slayers.SCMPTypePacketTooBig, 0),
+			Source:        xtest.MustParseIA("1-ff00:0:111"),
+			DestinationIA: xtest.MustParseIA("1-ff00:0:112"),
+			Destination:   &net.UDPAddr{IP: net.IP{10, 2, 3, 4}, Port: 80},
+			MTU:           1280,
+		},
+		"internal connectivity down IPv6": {
+			TypeCode:      slayers.CreateSCMPTypeCode(slayers.SCMPTypeInternalConnectivityDown, 0),
+			Source:        xtest.MustParseIA("1-ff00:0:111"),
+			DestinationIA: xtest.MustParseIA("1-ff00:0:112"),
+			Destination:   &net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: 80},
+			IA:            xtest.MustParseIA("1-ff00:0:111"),
+			Ingress:       131,
+			Egress:        141,
+		},
+		"truncated": {
+			TypeCode:  slayers.CreateSCMPTypeCode(slayers.SCMPTypeExternalInterfaceDown, 0),
+			Source:    xtest.MustParseIA("1-ff00:0:111"),
+			Truncated: true,
+			IA:        xtest.MustParseIA("1-ff00:0:111"),
+			Egress:    141,
+		},
+	}
+	for name, n := range testCases {
+		t.Run(name, func(t *testing.T) {
+			b := make([]byte, 1500)
+			l, err := n.SerializeTo(b)
+			require.NoError(t, err)
+			assert.True(t, isNotification(b[:l]))
+
+			var decoded SCMPErrorNotification
+			require.NoError(t, decoded.DecodeFromBytes(b[:l]))
+			assert.Equal(t, n, &decoded)
+
+			var p UnderlayPacket
+			assert.ErrorIs(t, p.DecodeFromBytes(b[:l]), ErrBadCookie)
+		})
+	}
+}
+
+func TestConnSCMPErrorHandler(t *testing.T) {
+	client, server := newConnPair(t)
+	defer client.Close()
+	defer server.Close()
+
+	var received []*SCMPErrorNotification
+	client.SetSCMPErrorHandler(func(n *SCMPErrorNotification) {
+		received = append(received, n)
+	})
+	n := &SCMPErrorNotification{
+		TypeCode: slayers.CreateSCMPTypeCode(slayers.SCMPTypePacketTooBig, 0),
+		Source:   xtest.MustParseIA("1-ff00:0:111"),
+		MTU:      1280,
+	}
+	require.NoError(t, server.WriteSCMPErrorNotification(n))
+	_, err := server.WriteTo([]byte("hello"), nil)
+	require.NoError(t, err)
+
+	buf := make([]byte, 100)
+	l, err := client.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(buf[:l]))
+	require.Len(t, received, 1)
+	assert.Equal(t, n.MTU, received[0].MTU)
+}
+
+func newConnPair(t *testing.T) (*Conn, *Conn) {
+	c1, c2, err := socketPair()
+	require.NoError(t, err)
+	return newConn(c1), newConn(c2)
+}
+
+func socketPair() (*net.UnixConn, *net.UnixConn, error) {
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: "@", Net: "unix"})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer l.Close()
+	c1, err := net.DialUnix("unix", nil, l.Addr().(*net.UnixAddr))
+	if err != nil {
+		return nil, nil, err
+	}
+	c2, err := l.AcceptUnix()
+	if err != nil {
+		c1.Close()
+		return nil, nil, err
+	}
+	return c1, c2, nil
+}
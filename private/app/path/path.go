@@ -79,10 +79,65 @@ func Choose(
 ) (snet.Path, error) {
 
 	o := applyOption(opts)
+	paths, err := candidates(ctx, conn, remote, o)
+	if err != nil {
+		return nil, err
+	}
+	ranked := o.policy != nil && o.policy.Ordering != nil
+	if !ranked {
+		Sort(paths)
+	}
+	if o.interactive {
+		return printAndChoose(paths, remote, o.colorScheme)
+	}
+	if ranked {
+		// The policy ranked the best path first.
+		return paths[0], nil
+	}
+	return paths[rand.Intn(len(paths))], nil
+}
+
+// ChooseAll returns all paths to the remote that satisfy the options, in the
+// order defined by the ordering of the policy option if it is set, and by Sort
+// otherwise. The interactive option is ignored.
+func ChooseAll(
+	ctx context.Context,
+	conn daemon.Connector,
+	remote addr.IA,
+	opts ...Option,
+) ([]snet.Path, error) {
+
+	o := applyOption(opts)
+	paths, err := candidates(ctx, conn, remote, o)
+	if err != nil {
+		return nil, err
+	}
+	if o.policy == nil || o.policy.Ordering == nil {
+		Sort(paths)
+	}
+	return paths, nil
+}
+
+// candidates fetches the paths to the remote and filters them according to
+// the options.
+func candidates(
+	ctx context.Context,
+	conn daemon.Connector,
+	remote addr.IA,
+	o options,
+) ([]snet.Path, error) {
+
 	paths, err := fetchPaths(ctx, conn, remote, o.refresh, o.seq)
 	if err != nil {
 		return nil, serrors.WrapStr("fetching paths", err)
 	}
+	if o.policy != nil {
+		paths = o.policy.Filter(paths)
+		if len(paths) == 0 {
+			return nil, serrors.New("no path available that satisfies the policy",
+				"policy", o.policy.Name)
+		}
+	}
 	if o.epic {
 		// Only use paths that support EPIC and intra-AS (empty) paths.
 		epicPaths := []snet.Path{}
@@ -110,11 +165,7 @@ func Choose(
 			return nil, serrors.New("no healthy paths available")
 		}
 	}
-	if o.interactive {
-		return printAndChoose(paths, remote, o.colorScheme)
-	}
-
-	return paths[rand.Intn(len(paths))], nil
+	return paths, nil
 }
 
 func filterUnhealthy(
@@ -189,8 +240,6 @@ func fetchPaths(
 }
 
 func printAndChoose(paths []snet.Path, remote addr.IA, cs ColorScheme) (snet.Path, error) {
-	Sort(paths)
-
 	sectionHeader := func(intfs int) {
 		cs.Header.Printf("%d Hops:\n", (intfs/2)+1)
 	}
@@ -317,6 +366,7 @@ type options struct {
 	interactive bool
 	refresh     bool
 	seq         string
+	policy      *pathpol.Policy
 	colorScheme ColorScheme
 	probeCfg    *ProbeConfig
 	epic        bool
@@ -350,6 +400,14 @@ func WithSequence(seq string) Option {
 	}
 }
 
+// WithPolicy filters the paths with the path policy. A nil policy does not
+// filter any paths.
+func WithPolicy(policy *pathpol.Policy) Option {
+	return func(o *options) {
+		o.policy = policy
+	}
+}
+
 func WithColorScheme(cs ColorScheme) Option {
 	return func(o *options) {
 		o.colorScheme = cs
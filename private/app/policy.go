@@ -0,0 +1,37 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+const (
+	// PolicyUsage defines the usage message for the policy flag.
+	PolicyUsage = "File with a path policy in JSON or YAML format"
+	// PolicyHelp defines the help message for a path policy file.
+	PolicyHelp = `A path policy file contains either a single policy object, or a list of named
+policies, in which case the first policy is used and the others can be
+referenced with 'extends'. A policy can define an 'acl', a 'sequence',
+'local_isd_ases', 'remote_isd_ases', and weighted 'options'. The announced path
+metadata can be constrained with 'max_latency', 'min_bandwidth' (in Kbit/s),
+'min_mtu', 'exclude_link_types', and a 'geofence' of excluded regions and
+countries. An 'ordering' ranks the paths by a cost that weights the 'hops',
+'latency', 'inverse_bandwidth', and 'expiry' of a path.
+
+Policy Example:
+
+========== ====================================================
+ acl:       ["- 1-ff00:0:133#0", "+"]
+ sequence:  "1-ff00:0:110#0 0*"
+========== ====================================================
+`
+)
@@ -82,12 +82,21 @@ func (a *ACL) evalPath(pm *snet.PathMetadata) ACLAction {
 }
 
 func (a *ACL) evalInterface(iface snet.PathInterface, ingress bool) ACLAction {
-	for _, aclEntry := range a.Entries {
+	if i := a.matchInterface(iface, ingress); i >= 0 {
+		return a.Entries[i].Action
+	}
+	panic("Default ACL action missing")
+}
+
+// matchInterface returns the index of the first entry that matches the
+// interface, or -1 if no entry matches.
+func (a *ACL) matchInterface(iface snet.PathInterface, ingress bool) int {
+	for i, aclEntry := range a.Entries {
 		if aclEntry.Rule == nil || aclEntry.Rule.pathIFMatch(iface, ingress) {
-			return aclEntry.Action
+			return i
 		}
 	}
-	panic("Default ACL action missing")
+	return -1
 }
 
 type ACLEntry struct {
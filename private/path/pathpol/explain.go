@@ -0,0 +1,283 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathpol
+
+import (
+	"fmt"
+
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/private/common"
+	"github.com/scionproto/scion/pkg/snet"
+)
+
+// Attribute identifies the policy attribute that rejected a path.
+type Attribute string
+
+// The policy attributes, in the order in which they are evaluated.
+const (
+	AttributeLocalISDAS  Attribute = "local_isd_ases"
+	AttributeRemoteISDAS Attribute = "remote_isd_ases"
+	AttributeACL         Attribute = "acl"
+	AttributeSequence    Attribute = "sequence"
+	// The metadata constraints.
+	AttributeMaxLatency       Attribute = "max_latency"
+	AttributeMinBandwidth     Attribute = "min_bandwidth"
+	AttributeMinMTU           Attribute = "min_mtu"
+	AttributeExcludeLinkTypes Attribute = "exclude_link_types"
+	AttributeGeofence         Attribute = "geofence"
+	AttributeOptions          Attribute = "options"
+)
+
+// Evaluation explains how a policy evaluated a single path.
+type Evaluation struct {
+	// Path is the evaluated path.
+	Path snet.Path `json:"-"`
+	// Fingerprint is the fingerprint of the evaluated path.
+	Fingerprint string `json:"fingerprint"`
+	// Accepted indicates whether the policy keeps the path.
+	Accepted bool `json:"accepted"`
+	// Rejection explains why the path was rejected. It is nil for accepted
+	// paths.
+	Rejection *Rejection `json:"rejection,omitempty"`
+	// ACLMatches lists the ACL entries that matched the interfaces of the
+	// path, up to the first interface that was denied.
+	ACLMatches []ACLMatch `json:"acl_matches,omitempty"`
+}
+
+// ACLMatch is an ACL entry that matched an interface of a path.
+type ACLMatch struct {
+	// Index is the index of the interface in the path metadata.
+	Index int             `json:"index"`
+	IA    addr.IA         `json:"isd_as"`
+	ID    common.IFIDType `json:"interface"`
+	// Entry is the matching ACL entry, and EntryIndex its index in the ACL.
+	Entry      string `json:"entry"`
+	EntryIndex int    `json:"entry_index"`
+}
+
+// Rejection explains why a policy attribute rejected a path.
+type Rejection struct {
+	Attribute Attribute `json:"attribute"`
+	Reason    string    `json:"reason"`
+	// ACLMatch is the ACL entry that denied the path. It is nil if no ACL
+	// entry matched.
+	ACLMatch *ACLMatch `json:"acl_match,omitempty"`
+	// Sequence is the policy sequence that the path did not match, and
+	// PathSequence is the sequence of the path.
+	Sequence     string `json:"sequence,omitempty"`
+	PathSequence string `json:"path_sequence,omitempty"`
+	// ISDASRule is the remote ISD-AS rule that rejected the path. It is nil if
+	// no rule matched.
+	ISDASRule *ISDASRule `json:"isd_as_rule,omitempty"`
+	// Options explains the rejection of the path by each option that was
+	// considered.
+	Options []OptionRejection `json:"options,omitempty"`
+}
+
+// OptionRejection explains why the policy of an option rejected a path.
+type OptionRejection struct {
+	Weight    int        `json:"weight"`
+	Rejection *Rejection `json:"rejection"`
+}
+
+// Evaluate evaluates the policy and explains for each path whether it is
+// kept. The accepted paths are the ones returned by Filter.
+func (p *Policy) Evaluate(paths []snet.Path) []Evaluation {
+	return p.EvaluateOpt(paths, FilterOptions{})
+}
+
+// EvaluateOpt evaluates the policy with the given options and explains for
+// each path whether it is kept. The accepted paths are the ones returned by
+// FilterOpt.
+func (p *Policy) EvaluateOpt(paths []snet.Path, opts FilterOptions) []Evaluation {
+	evals := make([]Evaluation, 0, len(paths))
+	for _, path := range paths {
+		evals = append(evals, Evaluation{
+			Path:        path,
+			Fingerprint: snet.Fingerprint(path).String(),
+			Accepted:    true,
+		})
+	}
+	p.evaluate(evals, opts)
+	return evals
+}
+
+// evaluate evaluates the policy for the accepted evaluations, and updates
+// them in place.
+func (p *Policy) evaluate(evals []Evaluation, opts FilterOptions) {
+	if p == nil {
+		return
+	}
+	for i := range evals {
+		e := &evals[i]
+		if !e.Accepted {
+			continue
+		}
+		e.Rejection = p.evaluatePath(e, opts)
+		e.Accepted = e.Rejection == nil
+	}
+	if len(p.Options) > 0 {
+		p.evaluateOptions(evals, opts)
+	}
+}
+
+// evaluatePath evaluates all attributes except the options for a single
+// path, and returns the first rejection.
+func (p *Policy) evaluatePath(e *Evaluation, opts FilterOptions) *Rejection {
+	path := e.Path
+	if p.LocalISDAS != nil {
+		switch {
+		case path.Source() == path.Destination():
+			return &Rejection{
+				Attribute: AttributeLocalISDAS,
+				Reason:    "source and destination are in the same ISD-AS",
+			}
+		case !p.LocalISDAS.allows(path.Source()):
+			return &Rejection{
+				Attribute: AttributeLocalISDAS,
+				Reason:    fmt.Sprintf("source %s is not allowed", path.Source()),
+			}
+		}
+	}
+	if p.RemoteISDAS != nil {
+		if len(path.Metadata().Interfaces) == 0 {
+			return &Rejection{
+				Attribute: AttributeRemoteISDAS,
+				Reason:    "path has no interfaces",
+			}
+		}
+		i := p.RemoteISDAS.matchRule(path.Destination())
+		if i < 0 {
+			return &Rejection{
+				Attribute: AttributeRemoteISDAS,
+				Reason:    fmt.Sprintf("no rule matches destination %s", path.Destination()),
+			}
+		}
+		if rule := p.RemoteISDAS.Rules[i]; rule.Reject {
+			return &Rejection{
+				Attribute: AttributeRemoteISDAS,
+				Reason:    fmt.Sprintf("destination %s is rejected", path.Destination()),
+				ISDASRule: &rule,
+			}
+		}
+	}
+	if p.ACL != nil && len(p.ACL.Entries) > 0 {
+		for i, iface := range path.Metadata().Interfaces {
+			entry := p.ACL.matchInterface(iface, i%2 != 0)
+			if entry < 0 {
+				return &Rejection{
+					Attribute: AttributeACL,
+					Reason: fmt.Sprintf("no ACL entry matches interface %s#%d",
+						iface.IA, iface.ID),
+				}
+			}
+			match := ACLMatch{
+				Index:      i,
+				IA:         iface.IA,
+				ID:         iface.ID,
+				Entry:      p.ACL.Entries[entry].String(),
+				EntryIndex: entry,
+			}
+			e.ACLMatches = append(e.ACLMatches, match)
+			if p.ACL.Entries[entry].Action == Deny {
+				return &Rejection{
+					Attribute: AttributeACL,
+					Reason: fmt.Sprintf("interface %s#%d is denied by %q",
+						iface.IA, iface.ID, match.Entry),
+					ACLMatch: &match,
+				}
+			}
+		}
+	}
+	if p.Sequence != nil && p.Sequence.srcstr != "" && !opts.IgnoreSequence {
+		desc, ok, err := p.Sequence.match(path)
+		if err != nil {
+			return &Rejection{
+				Attribute: AttributeSequence,
+				Reason:    fmt.Sprintf("invalid path: %s", err),
+				Sequence:  p.Sequence.String(),
+			}
+		}
+		if !ok {
+			return &Rejection{
+				Attribute:    AttributeSequence,
+				Reason:       "path does not match the sequence",
+				Sequence:     p.Sequence.String(),
+				PathSequence: desc,
+			}
+		}
+	}
+	for _, m := range p.metadataPolicies() {
+		if reason := m.policy.check(path.Metadata()); reason != "" {
+			return &Rejection{Attribute: m.attr, Reason: reason}
+		}
+	}
+	return nil
+}
+
+// evaluateOptions evaluates the options for the accepted evaluations in the
+// same way as evalOptions, and rejects the paths that no selected option
+// accepts.
+func (p *Policy) evaluateOptions(evals []Evaluation, opts FilterOptions) {
+	var candidates []int
+	for i, e := range evals {
+		if e.Accepted {
+			candidates = append(candidates, i)
+		}
+	}
+	selected := make(map[snet.PathFingerprint]struct{})
+	rejections := make([][]OptionRejection, len(candidates))
+	currWeight := p.Options[0].Weight
+	for _, option := range p.Options {
+		if currWeight > option.Weight && len(selected) > 0 {
+			break
+		}
+		currWeight = option.Weight
+		sub := make([]Evaluation, 0, len(candidates))
+		for _, i := range candidates {
+			sub = append(sub, Evaluation{
+				Path:        evals[i].Path,
+				Fingerprint: evals[i].Fingerprint,
+				Accepted:    true,
+			})
+		}
+		var policy *Policy
+		if option.Policy != nil {
+			policy = option.Policy.Policy
+		}
+		policy.evaluate(sub, opts)
+		for j, e := range sub {
+			if e.Accepted {
+				selected[snet.Fingerprint(e.Path)] = struct{}{}
+				continue
+			}
+			rejections[j] = append(rejections[j], OptionRejection{
+				Weight:    option.Weight,
+				Rejection: e.Rejection,
+			})
+		}
+	}
+	for j, i := range candidates {
+		if _, ok := selected[snet.Fingerprint(evals[i].Path)]; ok {
+			continue
+		}
+		evals[i].Accepted = false
+		evals[i].Rejection = &Rejection{
+			Attribute: AttributeOptions,
+			Reason:    "path is rejected by all selected options",
+			Options:   rejections[j],
+		}
+	}
+}
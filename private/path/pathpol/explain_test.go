@@ -0,0 +1,136 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathpol
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/pkg/snet"
+)
+
+func TestEvaluateMatchesFilter(t *testing.T) {
+	tests := map[string]string{
+		"empty":            `{}`,
+		"acl":              `{"acl": ["- 1-ff00:0:120#0", "+"]}`,
+		"sequence":         `{"sequence": "0+ 1-ff00:0:130 0+"}`,
+		"remote":           `{"remote_isd_ases": [{"isd_as": "2-0", "reject": true}, {}]}`,
+		"local":            `{"local_isd_ases": ["1-ff00:0:111"]}`,
+		"acl and sequence": `{"acl": ["- 2-ff00:0:210#0", "+"], "sequence": "0* 1-ff00:0:120 0*"}`,
+		"options": `{"options": [
+			{"weight": 1, "policy": {"acl": ["- 1-ff00:0:120#0", "+"]}},
+			{"weight": 1, "policy": {"acl": ["- 2-ff00:0:210#0", "+"]}},
+			{"policy": {}}
+		]}`,
+		"options without match": `{"options": [
+			{"weight": 2, "policy": {"acl": ["-"]}},
+			{"weight": 1, "policy": {"sequence": "0+ 1-ff00:0:130 0+"}}
+		]}`,
+	}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	pp := NewPathProvider(ctrl)
+	paths := pp.GetPaths(xtest.MustParseIA("1-ff00:0:110"), xtest.MustParseIA("2-ff00:0:220"))
+	require.NotEmpty(t, paths)
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy, err := ParsePolicy([]byte(raw))
+			require.NoError(t, err)
+			evals := policy.Evaluate(paths)
+			require.Len(t, evals, len(paths))
+			var accepted []snet.Path
+			for i, e := range evals {
+				assert.Equal(t, paths[i], e.Path)
+				assert.Equal(t, e.Accepted, e.Rejection == nil)
+				if e.Accepted {
+					accepted = append(accepted, e.Path)
+				}
+			}
+			assert.ElementsMatch(t, policy.Filter(paths), accepted)
+		})
+	}
+}
+
+func TestEvaluateRejection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	pp := NewPathProvider(ctrl)
+	paths := pp.GetPaths(xtest.MustParseIA("1-ff00:0:110"), xtest.MustParseIA("2-ff00:0:220"))
+
+	t.Run("acl", func(t *testing.T) {
+		policy, err := ParsePolicy([]byte(`{"acl": ["- 1-ff00:0:110#0", "+"]}`))
+		require.NoError(t, err)
+		for _, e := range policy.Evaluate(paths) {
+			require.False(t, e.Accepted)
+			assert.Equal(t, AttributeACL, e.Rejection.Attribute)
+			require.NotNil(t, e.Rejection.ACLMatch)
+			assert.Equal(t, 0, e.Rejection.ACLMatch.Index)
+			assert.Equal(t, xtest.MustParseIA("1-ff00:0:110"), e.Rejection.ACLMatch.IA)
+			assert.Equal(t, 0, e.Rejection.ACLMatch.EntryIndex)
+			assert.Equal(t, []ACLMatch{*e.Rejection.ACLMatch}, e.ACLMatches)
+		}
+	})
+	t.Run("sequence", func(t *testing.T) {
+		policy, err := ParsePolicy([]byte(`{"sequence": "1-ff00:0:111 0*"}`))
+		require.NoError(t, err)
+		for _, e := range policy.Evaluate(paths) {
+			require.False(t, e.Accepted)
+			assert.Equal(t, AttributeSequence, e.Rejection.Attribute)
+			assert.Equal(t, "1-ff00:0:111 0*", e.Rejection.Sequence)
+			seq, err := GetSequence(e.Path)
+			require.NoError(t, err)
+			assert.Equal(t, seq, e.Rejection.PathSequence)
+		}
+	})
+	t.Run("options", func(t *testing.T) {
+		policy, err := ParsePolicy([]byte(`{"options": [
+			{"weight": 2, "policy": {"acl": ["- 1-ff00:0:110#0", "+"]}},
+			{"weight": 1, "policy": {"remote_isd_ases": [{"isd_as": "2-0", "reject": true}]}}
+		]}`))
+		require.NoError(t, err)
+		for _, e := range policy.Evaluate(paths) {
+			require.False(t, e.Accepted)
+			assert.Equal(t, AttributeOptions, e.Rejection.Attribute)
+			require.Len(t, e.Rejection.Options, 2)
+			assert.Equal(t, 2, e.Rejection.Options[0].Weight)
+			assert.Equal(t, AttributeACL, e.Rejection.Options[0].Rejection.Attribute)
+			assert.Equal(t, 1, e.Rejection.Options[1].Weight)
+			assert.Equal(t, AttributeRemoteISDAS, e.Rejection.Options[1].Rejection.Attribute)
+			assert.NotNil(t, e.Rejection.Options[1].Rejection.ISDASRule)
+		}
+	})
+	t.Run("json", func(t *testing.T) {
+		policy, err := ParsePolicy([]byte(`{"remote_isd_ases": [{"isd_as": "2-0", "reject": true}]}`))
+		require.NoError(t, err)
+		evals := policy.Evaluate(paths[:1])
+		raw, err := json.Marshal(evals[0])
+		require.NoError(t, err)
+		expected := `{
+			"fingerprint": "` + snet.Fingerprint(paths[0]).String() + `",
+			"accepted": false,
+			"rejection": {
+				"attribute": "remote_isd_ases",
+				"reason": "destination 2-ff00:0:220 is rejected",
+				"isd_as_rule": {"isd_as": "2-0", "reject": true}
+			}
+		}`
+		assert.JSONEq(t, expected, string(raw))
+	})
+}
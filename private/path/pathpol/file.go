@@ -0,0 +1,207 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathpol
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/scionproto/scion/pkg/private/serrors"
+)
+
+// LoadPolicyFromFile loads a policy from a JSON or YAML file. See ParsePolicy
+// for the supported formats.
+func LoadPolicyFromFile(file string) (*Policy, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, serrors.WrapStr("reading policy file", err, "file", file)
+	}
+	p, err := ParsePolicy(raw)
+	if err != nil {
+		return nil, serrors.WithCtx(err, "file", file)
+	}
+	return p, nil
+}
+
+// ParsePolicy parses a policy in JSON or YAML format. The input is either a
+// single policy object, or a list of named policies as described in
+// doc/PathPolicy.md. For a list, the first policy is returned, and all
+// policies in the list can be referenced in the extends attributes. The
+// extended policies are applied and the options are sorted by weight.
+func ParsePolicy(raw []byte) (*Policy, error) {
+	// JSON is parsed as YAML as well. Decoding the normalized JSON afterwards
+	// reuses the JSON unmarshalers of the policy attributes.
+	var generic interface{}
+	if err := yaml.Unmarshal(raw, &generic); err != nil {
+		return nil, serrors.WrapStr("parsing policy", err)
+	}
+	normalized, err := json.Marshal(jsonCompatible(generic))
+	if err != nil {
+		return nil, serrors.WrapStr("normalizing policy", err)
+	}
+	var named []*ExtPolicy
+	var top *ExtPolicy
+	switch generic.(type) {
+	case []interface{}:
+		var entries []map[string]*ExtPolicy
+		if err := decodeStrict(normalized, &entries); err != nil {
+			return nil, serrors.WrapStr("parsing named policies", err)
+		}
+		for i, entry := range entries {
+			if len(entry) != 1 {
+				return nil, serrors.New("list entry must contain exactly one named policy",
+					"index", i, "policies", len(entry))
+			}
+			for name, p := range entry {
+				if p == nil {
+					p = &ExtPolicy{}
+				}
+				if p.Policy == nil {
+					p.Policy = &Policy{}
+				}
+				p.Name = name
+				named = append(named, p)
+			}
+		}
+		if len(named) == 0 {
+			return nil, serrors.New("no policy defined")
+		}
+		top = named[0]
+	case map[interface{}]interface{}:
+		top = &ExtPolicy{}
+		if err := decodeStrict(normalized, top); err != nil {
+			return nil, serrors.WrapStr("parsing policy", err)
+		}
+	default:
+		return nil, serrors.New("policy must be an object or a list of named policies")
+	}
+	if err := checkExtends(named); err != nil {
+		return nil, err
+	}
+	policy, err := PolicyFromExtPolicy(top, named)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveOptions(policy, named, map[*Policy]bool{}); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// checkExtends verifies that the named policies have unique names and that
+// they do not extend each other in a cycle.
+func checkExtends(named []*ExtPolicy) error {
+	byName := make(map[string]*ExtPolicy, len(named))
+	for _, p := range named {
+		if _, ok := byName[p.Name]; ok {
+			return serrors.New("duplicate policy name", "policy", p.Name)
+		}
+		byName[p.Name] = p
+	}
+	// done is false for policies that are currently visited, and true for
+	// policies that are fully checked.
+	done := make(map[string]bool, len(named))
+	var visit func(name string) error
+	visit = func(name string) error {
+		if finished, ok := done[name]; ok {
+			if !finished {
+				return serrors.New("circular policy extension", "policy", name)
+			}
+			return nil
+		}
+		p, ok := byName[name]
+		if !ok {
+			// Missing policies are reported by PolicyFromExtPolicy.
+			return nil
+		}
+		done[name] = false
+		for _, ext := range p.Extends {
+			if err := visit(ext); err != nil {
+				return err
+			}
+		}
+		done[name] = true
+		return nil
+	}
+	for _, p := range named {
+		if err := visit(p.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveOptions applies the extended policies of the options recursively and
+// sorts the options by weight, as done by NewPolicy. The visiting map is used
+// to detect options that include themselves.
+func resolveOptions(p *Policy, named []*ExtPolicy, visiting map[*Policy]bool) error {
+	if finished, ok := visiting[p]; ok {
+		if !finished {
+			return serrors.New("circular policy options", "policy", p.Name)
+		}
+		return nil
+	}
+	visiting[p] = false
+	for i, option := range p.Options {
+		if option.Policy == nil {
+			return serrors.New("option without policy", "policy", p.Name, "index", i)
+		}
+		resolved, err := PolicyFromExtPolicy(option.Policy, named)
+		if err != nil {
+			return err
+		}
+		option.Policy.Policy = resolved
+		if err := resolveOptions(resolved, named, visiting); err != nil {
+			return err
+		}
+	}
+	sort.SliceStable(p.Options, func(i, j int) bool {
+		return p.Options[i].Weight > p.Options[j].Weight
+	})
+	visiting[p] = true
+	return nil
+}
+
+func decodeStrict(raw []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// jsonCompatible converts the generic maps produced by the YAML decoder to
+// maps with string keys, such that the value can be marshaled to JSON.
+func jsonCompatible(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = jsonCompatible(val)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, 0, len(v))
+		for _, val := range v {
+			l = append(l, jsonCompatible(val))
+		}
+		return l
+	default:
+		return v
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathpol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := map[string]struct {
+		Input       string
+		ExpACL      []string
+		ExpSequence string
+		ExpWeights  []int
+		ExpOptACLs  [][]string
+		AssertErr   assert.ErrorAssertionFunc
+	}{
+		"single JSON policy": {
+			Input: `{
+				"acl": ["- 1-ff00:0:133#0", "+"],
+				"sequence": "1-ff00:0:133#0 0*"
+			}`,
+			ExpACL:      []string{"- 1-ff00:0:133#0", "+"},
+			ExpSequence: "1-ff00:0:133#0 0*",
+			AssertErr:   assert.NoError,
+		},
+		"single YAML policy": {
+			Input: `
+acl:
+- "- 1-ff00:0:133#0"
+- "+"
+`,
+			ExpACL:    []string{"- 1-ff00:0:133#0", "+"},
+			AssertErr: assert.NoError,
+		},
+		"named policies with extends": {
+			Input: `
+- extends_example:
+    extends:
+    - sub_pol_1
+    - sub_pol_2
+- sub_pol_1:
+    acl:
+    - "- 1-ff00:0:133#0"
+    - "+"
+- sub_pol_2:
+    sequence: "0+ 1-ff00:0:110#0 1-ff00:0:110#0 0+"
+`,
+			ExpACL:      []string{"- 1-ff00:0:133#0", "+"},
+			ExpSequence: "0+ 1-ff00:0:110#0 1-ff00:0:110#0 0+",
+			AssertErr:   assert.NoError,
+		},
+		"options are resolved and sorted": {
+			Input: `
+- policy_with_options:
+    options:
+    - policy:
+        extends:
+        - option_1
+    - weight: 3
+      policy:
+        acl:
+        - "- 1"
+        - "+"
+- option_1:
+    acl:
+    - "- 1-ff00:0:133#0"
+    - "+"
+`,
+			ExpWeights: []int{3, 0},
+			ExpOptACLs: [][]string{
+				{"- 1-0#0", "+"},
+				{"- 1-ff00:0:133#0", "+"},
+			},
+			AssertErr: assert.NoError,
+		},
+		"unknown attribute": {
+			Input:     `{"acl": ["+"], "unknown": 1}`,
+			AssertErr: assert.Error,
+		},
+		"unknown extended policy": {
+			Input:     `[{"a": {"extends": ["b"]}}]`,
+			AssertErr: assert.Error,
+		},
+		"circular extends": {
+			Input:     `[{"a": {"extends": ["b"]}}, {"b": {"extends": ["a"]}}]`,
+			AssertErr: assert.Error,
+		},
+		"duplicate name": {
+			Input:     `[{"a": {}}, {"a": {}}]`,
+			AssertErr: assert.Error,
+		},
+		"option without policy": {
+			Input:     `{"options": [{"weight": 1}]}`,
+			AssertErr: assert.Error,
+		},
+		"scalar": {
+			Input:     `acl`,
+			AssertErr: assert.Error,
+		},
+	}
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			p, err := ParsePolicy([]byte(tc.Input))
+			tc.AssertErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.ExpACL, aclStrings(p.ACL))
+			var seq string
+			if p.Sequence != nil {
+				seq = p.Sequence.String()
+			}
+			assert.Equal(t, tc.ExpSequence, seq)
+			require.Len(t, p.Options, len(tc.ExpWeights))
+			for i, o := range p.Options {
+				assert.Equal(t, tc.ExpWeights[i], o.Weight)
+				assert.Equal(t, tc.ExpOptACLs[i], aclStrings(o.Policy.ACL))
+			}
+		})
+	}
+}
+
+func aclStrings(acl *ACL) []string {
+	if acl == nil {
+		return nil
+	}
+	var entries []string
+	for _, e := range acl.Entries {
+		entries = append(entries, e.String())
+	}
+	return entries
+}
@@ -33,16 +33,22 @@ func (li *LocalISDAS) Eval(paths []snet.Path) []snet.Path {
 		if path.Source() == path.Destination() {
 			continue
 		}
-		for _, allowedIA := range li.AllowedIAs {
-			if path.Source() == allowedIA {
-				result = append(result, path)
-				break
-			}
+		if li.allows(path.Source()) {
+			result = append(result, path)
 		}
 	}
 	return result
 }
 
+func (li *LocalISDAS) allows(ia addr.IA) bool {
+	for _, allowedIA := range li.AllowedIAs {
+		if ia == allowedIA {
+			return true
+		}
+	}
+	return false
+}
+
 func (li *LocalISDAS) MarshalJSON() ([]byte, error) {
 	return json.Marshal(li.AllowedIAs)
 }
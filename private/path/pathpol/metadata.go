@@ -0,0 +1,266 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathpol
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/private/util"
+	"github.com/scionproto/scion/pkg/snet"
+)
+
+// LatencyBound is a path policy that rejects paths whose total announced
+// latency exceeds Max. Paths with hops that did not announce a latency are
+// rejected, because the bound cannot be verified for them.
+type LatencyBound struct {
+	Max time.Duration
+}
+
+func (lb *LatencyBound) Eval(paths []snet.Path) []snet.Path {
+	return evalMetadata(paths, lb.check)
+}
+
+// check returns the reason why the path metadata violates the bound, or an
+// empty string.
+func (lb *LatencyBound) check(pm *snet.PathMetadata) string {
+	if len(pm.Interfaces) == 0 {
+		return ""
+	}
+	if len(pm.Latency) != len(pm.Interfaces)-1 {
+		return "latency is not announced for all hops"
+	}
+	var total time.Duration
+	for i, l := range pm.Latency {
+		if l < 0 {
+			return fmt.Sprintf("latency is not announced for hop %d", i)
+		}
+		total += l
+	}
+	if total > lb.Max {
+		return fmt.Sprintf("latency %s exceeds %s", total, lb.Max)
+	}
+	return ""
+}
+
+func (lb *LatencyBound) MarshalJSON() ([]byte, error) {
+	return json.Marshal(util.DurWrap{Duration: lb.Max})
+}
+
+func (lb *LatencyBound) UnmarshalJSON(b []byte) error {
+	var d util.DurWrap
+	if err := json.Unmarshal(b, &d); err != nil {
+		return err
+	}
+	lb.Max = d.Duration
+	return nil
+}
+
+// BandwidthBound is a path policy that rejects paths whose bottleneck
+// bandwidth, in Kbit/s, is below Min. Paths with hops that did not announce a
+// bandwidth are rejected, because the bound cannot be verified for them.
+type BandwidthBound struct {
+	Min uint64
+}
+
+func (bb *BandwidthBound) Eval(paths []snet.Path) []snet.Path {
+	return evalMetadata(paths, bb.check)
+}
+
+func (bb *BandwidthBound) check(pm *snet.PathMetadata) string {
+	if len(pm.Interfaces) == 0 {
+		return ""
+	}
+	if len(pm.Bandwidth) != len(pm.Interfaces)-1 {
+		return "bandwidth is not announced for all hops"
+	}
+	for i, bw := range pm.Bandwidth {
+		if bw == 0 {
+			return fmt.Sprintf("bandwidth is not announced for hop %d", i)
+		}
+		if bw < bb.Min {
+			return fmt.Sprintf("bandwidth %d Kbit/s of hop %d is below %d Kbit/s",
+				bw, i, bb.Min)
+		}
+	}
+	return ""
+}
+
+func (bb *BandwidthBound) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bb.Min)
+}
+
+func (bb *BandwidthBound) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &bb.Min)
+}
+
+// MTUBound is a path policy that rejects paths with an MTU below Min.
+type MTUBound struct {
+	Min uint16
+}
+
+func (mb *MTUBound) Eval(paths []snet.Path) []snet.Path {
+	return evalMetadata(paths, mb.check)
+}
+
+func (mb *MTUBound) check(pm *snet.PathMetadata) string {
+	if pm.MTU < mb.Min {
+		return fmt.Sprintf("MTU %d is below %d", pm.MTU, mb.Min)
+	}
+	return ""
+}
+
+func (mb *MTUBound) MarshalJSON() ([]byte, error) {
+	return json.Marshal(mb.Min)
+}
+
+func (mb *MTUBound) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &mb.Min)
+}
+
+// LinkTypeFilter is a path policy that rejects paths that contain an
+// inter-domain link with one of the excluded link types.
+type LinkTypeFilter struct {
+	Excluded []snet.LinkType
+}
+
+func (lf *LinkTypeFilter) Eval(paths []snet.Path) []snet.Path {
+	return evalMetadata(paths, lf.check)
+}
+
+func (lf *LinkTypeFilter) check(pm *snet.PathMetadata) string {
+	for i, lt := range pm.LinkType {
+		for _, excluded := range lf.Excluded {
+			if lt == excluded {
+				return fmt.Sprintf("link %d has excluded link type %s", i, lt)
+			}
+		}
+	}
+	return ""
+}
+
+func (lf *LinkTypeFilter) MarshalJSON() ([]byte, error) {
+	types := make([]string, 0, len(lf.Excluded))
+	for _, lt := range lf.Excluded {
+		types = append(types, lt.String())
+	}
+	return json.Marshal(types)
+}
+
+func (lf *LinkTypeFilter) UnmarshalJSON(b []byte) error {
+	var types []string
+	if err := json.Unmarshal(b, &types); err != nil {
+		return err
+	}
+	lf.Excluded = make([]snet.LinkType, 0, len(types))
+	for _, t := range types {
+		lt, err := parseLinkType(t)
+		if err != nil {
+			return err
+		}
+		lf.Excluded = append(lf.Excluded, lt)
+	}
+	return nil
+}
+
+func parseLinkType(s string) (snet.LinkType, error) {
+	for _, lt := range []snet.LinkType{
+		snet.LinkTypeUnset,
+		snet.LinkTypeDirect,
+		snet.LinkTypeMultihop,
+		snet.LinkTypeOpennet,
+	} {
+		if lt.String() == s {
+			return lt, nil
+		}
+	}
+	return snet.LinkTypeUnset, serrors.New("unknown link type", "link_type", s)
+}
+
+// Geofence is a path policy that rejects paths with a border router that is
+// located in one of the regions or countries. The country of a router is the
+// last comma separated element of its announced address, and is compared
+// case-insensitively. Routers that did not announce a location are only
+// rejected if RejectUnknown is set.
+type Geofence struct {
+	Regions       []GeoRegion `json:"regions,omitempty"`
+	Countries     []string    `json:"countries,omitempty"`
+	RejectUnknown bool        `json:"reject_unknown,omitempty"`
+}
+
+// GeoRegion is an area bounded by latitudes and longitudes, in degrees of the
+// WGS 84 datum.
+type GeoRegion struct {
+	MinLatitude  float32 `json:"min_latitude"`
+	MaxLatitude  float32 `json:"max_latitude"`
+	MinLongitude float32 `json:"min_longitude"`
+	MaxLongitude float32 `json:"max_longitude"`
+}
+
+// Contains returns whether the coordinates are within the region.
+func (r GeoRegion) Contains(geo snet.GeoCoordinates) bool {
+	return geo.Latitude >= r.MinLatitude && geo.Latitude <= r.MaxLatitude &&
+		geo.Longitude >= r.MinLongitude && geo.Longitude <= r.MaxLongitude
+}
+
+func (g *Geofence) Eval(paths []snet.Path) []snet.Path {
+	return evalMetadata(paths, g.check)
+}
+
+func (g *Geofence) check(pm *snet.PathMetadata) string {
+	for i := range pm.Interfaces {
+		var geo snet.GeoCoordinates
+		if i < len(pm.Geo) {
+			geo = pm.Geo[i]
+		}
+		knownCoordinates := geo.Latitude != 0 || geo.Longitude != 0
+		if !knownCoordinates && geo.Address == "" {
+			if g.RejectUnknown {
+				return fmt.Sprintf("location of router %d is not announced", i)
+			}
+			continue
+		}
+		if knownCoordinates {
+			for _, r := range g.Regions {
+				if r.Contains(geo) {
+					return fmt.Sprintf("router %d at %.4f,%.4f is in an excluded region",
+						i, geo.Latitude, geo.Longitude)
+				}
+			}
+		}
+		parts := strings.Split(geo.Address, ",")
+		country := strings.TrimSpace(parts[len(parts)-1])
+		for _, c := range g.Countries {
+			if country != "" && strings.EqualFold(country, c) {
+				return fmt.Sprintf("router %d is in excluded country %s", i, country)
+			}
+		}
+	}
+	return ""
+}
+
+// evalMetadata returns the paths for which check does not return a reason.
+func evalMetadata(paths []snet.Path, check func(*snet.PathMetadata) string) []snet.Path {
+	result := []snet.Path{}
+	for _, path := range paths {
+		if check(path.Metadata()) == "" {
+			result = append(result, path)
+		}
+	}
+	return result
+}
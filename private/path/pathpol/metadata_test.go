@@ -0,0 +1,205 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathpol
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/pkg/snet"
+	snetpath "github.com/scionproto/scion/pkg/snet/path"
+)
+
+func TestMetadataPolicies(t *testing.T) {
+	// The path traverses three ASes: 1-ff00:0:110 -> 1-ff00:0:120 -> 1-ff00:0:130.
+	newPath := func(modify func(*snet.PathMetadata)) snet.Path {
+		meta := snet.PathMetadata{
+			Interfaces: []snet.PathInterface{
+				{IA: xtest.MustParseIA("1-ff00:0:110"), ID: 1},
+				{IA: xtest.MustParseIA("1-ff00:0:120"), ID: 2},
+				{IA: xtest.MustParseIA("1-ff00:0:120"), ID: 3},
+				{IA: xtest.MustParseIA("1-ff00:0:130"), ID: 4},
+			},
+			MTU:       1400,
+			Latency:   []time.Duration{10 * time.Millisecond, time.Millisecond, 20 * time.Millisecond},
+			Bandwidth: []uint64{1000, 5000, 2000},
+			LinkType:  []snet.LinkType{snet.LinkTypeDirect, snet.LinkTypeOpennet},
+			Geo: []snet.GeoCoordinates{
+				{Latitude: 47.37, Longitude: 8.54, Address: "Zurich, Switzerland"},
+				{Latitude: 46.95, Longitude: 7.44, Address: "Bern, Switzerland"},
+				{Latitude: 46.95, Longitude: 7.44, Address: "Bern, Switzerland"},
+				{Latitude: 48.14, Longitude: 11.58, Address: "Munich, Germany"},
+			},
+		}
+		if modify != nil {
+			modify(&meta)
+		}
+		return snetpath.Path{
+			Src:  meta.Interfaces[0].IA,
+			Dst:  meta.Interfaces[len(meta.Interfaces)-1].IA,
+			Meta: meta,
+		}
+	}
+	tests := map[string]struct {
+		Policy   string
+		Path     snet.Path
+		Accepted bool
+	}{
+		"latency within bound": {
+			Policy:   `{"max_latency": "31ms"}`,
+			Path:     newPath(nil),
+			Accepted: true,
+		},
+		"latency exceeds bound": {
+			Policy: `{"max_latency": "30ms"}`,
+			Path:   newPath(nil),
+		},
+		"latency unknown": {
+			Policy: `{"max_latency": "1s"}`,
+			Path: newPath(func(pm *snet.PathMetadata) {
+				pm.Latency[1] = snet.LatencyUnset
+			}),
+		},
+		"bandwidth above bound": {
+			Policy:   `{"min_bandwidth": 1000}`,
+			Path:     newPath(nil),
+			Accepted: true,
+		},
+		"bandwidth below bound": {
+			Policy: `{"min_bandwidth": 1001}`,
+			Path:   newPath(nil),
+		},
+		"bandwidth unknown": {
+			Policy: `{"min_bandwidth": 1}`,
+			Path: newPath(func(pm *snet.PathMetadata) {
+				pm.Bandwidth = nil
+			}),
+		},
+		"mtu above floor": {
+			Policy:   `{"min_mtu": 1400}`,
+			Path:     newPath(nil),
+			Accepted: true,
+		},
+		"mtu below floor": {
+			Policy: `{"min_mtu": 1401}`,
+			Path:   newPath(nil),
+		},
+		"link type not excluded": {
+			Policy:   `{"exclude_link_types": ["multihop"]}`,
+			Path:     newPath(nil),
+			Accepted: true,
+		},
+		"link type excluded": {
+			Policy: `{"exclude_link_types": ["multihop", "opennet"]}`,
+			Path:   newPath(nil),
+		},
+		"outside of region": {
+			Policy: `{"geofence": {"regions": [
+				{"min_latitude": 50, "max_latitude": 60, "min_longitude": 0, "max_longitude": 20}
+			]}}`,
+			Path:     newPath(nil),
+			Accepted: true,
+		},
+		"inside of region": {
+			Policy: `{"geofence": {"regions": [
+				{"min_latitude": 48, "max_latitude": 49, "min_longitude": 11, "max_longitude": 12}
+			]}}`,
+			Path: newPath(nil),
+		},
+		"excluded country": {
+			Policy: `{"geofence": {"countries": ["germany"]}}`,
+			Path:   newPath(nil),
+		},
+		"unknown location allowed": {
+			Policy: `{"geofence": {"countries": ["France"]}}`,
+			Path: newPath(func(pm *snet.PathMetadata) {
+				pm.Geo = pm.Geo[:2]
+			}),
+			Accepted: true,
+		},
+		"unknown location rejected": {
+			Policy: `{"geofence": {"countries": ["France"], "reject_unknown": true}}`,
+			Path: newPath(func(pm *snet.PathMetadata) {
+				pm.Geo = pm.Geo[:2]
+			}),
+		},
+	}
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			policy, err := ParsePolicy([]byte(tc.Policy))
+			require.NoError(t, err)
+			paths := []snet.Path{tc.Path}
+			if tc.Accepted {
+				assert.Equal(t, paths, policy.Filter(paths))
+			} else {
+				assert.Empty(t, policy.Filter(paths))
+			}
+			evals := policy.Evaluate(paths)
+			require.Len(t, evals, 1)
+			assert.Equal(t, tc.Accepted, evals[0].Accepted)
+			if !tc.Accepted {
+				assert.NotEmpty(t, evals[0].Rejection.Reason)
+			}
+		})
+	}
+}
+
+func TestMetadataPoliciesJSON(t *testing.T) {
+	raw := `{
+		"max_latency": "100ms",
+		"min_bandwidth": 1000,
+		"min_mtu": 1280,
+		"exclude_link_types": ["opennet", "unset"],
+		"geofence": {
+			"regions": [
+				{"min_latitude": 1, "max_latitude": 2, "min_longitude": 3, "max_longitude": 4}
+			],
+			"countries": ["Switzerland"],
+			"reject_unknown": true
+		}
+	}`
+	var policy Policy
+	require.NoError(t, json.Unmarshal([]byte(raw), &policy))
+	assert.Equal(t, &LatencyBound{Max: 100 * time.Millisecond}, policy.MaxLatency)
+	assert.Equal(t, &BandwidthBound{Min: 1000}, policy.MinBandwidth)
+	assert.Equal(t, &MTUBound{Min: 1280}, policy.MinMTU)
+	assert.Equal(t, &LinkTypeFilter{
+		Excluded: []snet.LinkType{snet.LinkTypeOpennet, snet.LinkTypeUnset},
+	}, policy.ExcludeLinkTypes)
+	assert.Equal(t, &Geofence{
+		Regions: []GeoRegion{
+			{MinLatitude: 1, MaxLatitude: 2, MinLongitude: 3, MaxLongitude: 4},
+		},
+		Countries:     []string{"Switzerland"},
+		RejectUnknown: true,
+	}, policy.Geofence)
+
+	// Gateway session policies are copied by marshaling to JSON and back.
+	marshaled, err := json.Marshal(&policy)
+	require.NoError(t, err)
+	var copied Policy
+	require.NoError(t, json.Unmarshal(marshaled, &copied))
+	assert.Equal(t, policy, copied)
+
+	err = json.Unmarshal([]byte(`{"exclude_link_types": ["carrier-pigeon"]}`), &policy)
+	assert.Error(t, err)
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathpol
+
+import (
+	"sort"
+	"time"
+
+	"github.com/scionproto/scion/pkg/snet"
+)
+
+// Ordering ranks paths by their cost. The cost of a path is the weighted sum
+// of its properties, and paths with a lower cost are ranked first.
+type Ordering struct {
+	// Hops is the weight of the number of inter-domain links on the path.
+	Hops float64 `json:"hops,omitempty"`
+	// Latency is the weight of the total announced latency in milliseconds.
+	// Hops that did not announce a latency do not contribute.
+	Latency float64 `json:"latency,omitempty"`
+	// InverseBandwidth is the weight of the inverse bottleneck bandwidth,
+	// expressed as the milliseconds it takes to transfer one Mbit. Hops that
+	// did not announce a bandwidth do not contribute.
+	InverseBandwidth float64 `json:"inverse_bandwidth,omitempty"`
+	// Expiry is the weight of the remaining lifetime of the path in hours. The
+	// term is subtracted from the cost, such that paths that expire later are
+	// ranked first.
+	Expiry float64 `json:"expiry,omitempty"`
+}
+
+// Cost returns the cost of the path at the given time.
+func (o *Ordering) Cost(path snet.Path, now time.Time) float64 {
+	pm := path.Metadata()
+	if pm == nil {
+		return 0
+	}
+	var cost float64
+	if o.Hops != 0 {
+		cost += o.Hops * float64(len(pm.Interfaces)/2)
+	}
+	if o.Latency != 0 {
+		var total time.Duration
+		for _, l := range pm.Latency {
+			if l > 0 {
+				total += l
+			}
+		}
+		cost += o.Latency * float64(total) / float64(time.Millisecond)
+	}
+	if o.InverseBandwidth != 0 {
+		var bottleneck uint64
+		for _, bw := range pm.Bandwidth {
+			if bw != 0 && (bottleneck == 0 || bw < bottleneck) {
+				bottleneck = bw
+			}
+		}
+		if bottleneck != 0 {
+			// 1 Mbit takes 1e6 / bottleneck milliseconds at a bandwidth in
+			// Kbit/s.
+			cost += o.InverseBandwidth * 1e6 / float64(bottleneck)
+		}
+	}
+	if o.Expiry != 0 && !pm.Expiry.IsZero() {
+		cost -= o.Expiry * pm.Expiry.Sub(now).Hours()
+	}
+	return cost
+}
+
+// Rank returns the paths sorted by ascending cost. Paths with the same cost
+// keep their relative order. The input slice is not modified.
+func (o *Ordering) Rank(paths []snet.Path) []snet.Path {
+	now := time.Now()
+	type costedPath struct {
+		path snet.Path
+		cost float64
+	}
+	costed := make([]costedPath, 0, len(paths))
+	for _, path := range paths {
+		costed = append(costed, costedPath{path: path, cost: o.Cost(path, now)})
+	}
+	sort.SliceStable(costed, func(i, j int) bool {
+		return costed[i].cost < costed[j].cost
+	})
+	ranked := make([]snet.Path, 0, len(costed))
+	for _, c := range costed {
+		ranked = append(ranked, c.path)
+	}
+	return ranked
+}
@@ -0,0 +1,173 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathpol
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/snet"
+	snetpath "github.com/scionproto/scion/pkg/snet/path"
+)
+
+// newOrderingPath creates a path over the given number of inter-domain links
+// with the given per-hop latency and bandwidth.
+func newOrderingPath(
+	links int,
+	latency time.Duration,
+	bandwidth uint64,
+	expiry time.Time,
+) snet.Path {
+
+	meta := snet.PathMetadata{Expiry: expiry}
+	for i := 0; i <= links; i++ {
+		ia := addr.MustIAFrom(1, addr.AS(0xff00_0000_0110+i))
+		if i > 0 {
+			meta.Interfaces = append(meta.Interfaces, snet.PathInterface{IA: ia, ID: 1})
+			meta.Latency = append(meta.Latency, latency)
+			meta.Bandwidth = append(meta.Bandwidth, bandwidth)
+		}
+		if i < links {
+			meta.Interfaces = append(meta.Interfaces, snet.PathInterface{IA: ia, ID: 2})
+			if i > 0 {
+				meta.Latency = append(meta.Latency, 0)
+				meta.Bandwidth = append(meta.Bandwidth, bandwidth)
+			}
+		}
+	}
+	return snetpath.Path{
+		Src:  meta.Interfaces[0].IA,
+		Dst:  meta.Interfaces[len(meta.Interfaces)-1].IA,
+		Meta: meta,
+	}
+}
+
+func TestOrderingCost(t *testing.T) {
+	now := time.Now()
+	path := newOrderingPath(2, 10*time.Millisecond, 1000, now.Add(2*time.Hour))
+	tests := map[string]struct {
+		Ordering Ordering
+		Cost     float64
+	}{
+		"empty": {},
+		"hops": {
+			Ordering: Ordering{Hops: 2},
+			Cost:     4,
+		},
+		"latency": {
+			Ordering: Ordering{Latency: 0.5},
+			Cost:     10,
+		},
+		"inverse bandwidth": {
+			Ordering: Ordering{InverseBandwidth: 1},
+			Cost:     1000,
+		},
+		"expiry": {
+			Ordering: Ordering{Expiry: 3},
+			Cost:     -6,
+		},
+		"combined": {
+			Ordering: Ordering{Hops: 1, Latency: 1, InverseBandwidth: 0.01, Expiry: 1},
+			Cost:     2 + 20 + 10 - 2,
+		},
+	}
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.InDelta(t, tc.Cost, tc.Ordering.Cost(path, now), 1e-9)
+		})
+	}
+	t.Run("unknown metadata", func(t *testing.T) {
+		path := newOrderingPath(1, snet.LatencyUnset, 0, time.Time{})
+		o := Ordering{Latency: 1, InverseBandwidth: 1, Expiry: 1}
+		assert.Zero(t, o.Cost(path, now))
+	})
+}
+
+func TestOrderingRank(t *testing.T) {
+	expiry := time.Now().Add(time.Hour)
+	long := newOrderingPath(3, time.Millisecond, 1000, expiry)
+	slow := newOrderingPath(1, 50*time.Millisecond, 1000, expiry)
+	fast := newOrderingPath(2, 5*time.Millisecond, 1000, expiry)
+	fastCopy := newOrderingPath(2, 5*time.Millisecond, 1000, expiry)
+	paths := []snet.Path{slow, fast, long, fastCopy}
+
+	o := Ordering{Latency: 1, Hops: 1}
+	ranked := o.Rank(paths)
+	assert.Equal(t, []snet.Path{long, fast, fastCopy, slow}, ranked)
+	assert.Equal(t, []snet.Path{slow, fast, long, fastCopy}, paths, "input must not be modified")
+
+	o = Ordering{Hops: 1}
+	assert.Equal(t, []snet.Path{slow, fast, fastCopy, long}, o.Rank(paths))
+}
+
+func TestPolicyOrdering(t *testing.T) {
+	expiry := time.Now().Add(time.Hour)
+	short := newOrderingPath(1, 30*time.Millisecond, 1000, expiry)
+	long := newOrderingPath(3, time.Millisecond, 1000, expiry)
+	paths := []snet.Path{short, long}
+
+	t.Run("filter ranks paths", func(t *testing.T) {
+		policy, err := ParsePolicy([]byte(`{"ordering": {"latency": 1}}`))
+		require.NoError(t, err)
+		assert.Equal(t, []snet.Path{long, short}, policy.Filter(paths))
+		cost, ok := policy.Cost(long)
+		assert.True(t, ok)
+		assert.InDelta(t, 3, cost, 1e-9)
+	})
+	t.Run("no ordering", func(t *testing.T) {
+		policy, err := ParsePolicy([]byte(`{}`))
+		require.NoError(t, err)
+		assert.Equal(t, paths, policy.Filter(paths))
+		_, ok := policy.Cost(long)
+		assert.False(t, ok)
+	})
+	t.Run("options are ranked", func(t *testing.T) {
+		policy, err := ParsePolicy([]byte(`{
+			"ordering": {"latency": 1},
+			"options": [
+				{"weight": 1, "policy": {"sequence": "0* 1-ff00:0:111 0*"}},
+				{"weight": 1, "policy": {"sequence": "0+ 1-ff00:0:112 0+"}}
+			]
+		}`))
+		require.NoError(t, err)
+		assert.Equal(t, []snet.Path{long, short}, policy.Filter(paths))
+	})
+	t.Run("extends", func(t *testing.T) {
+		policy, err := ParsePolicy([]byte(`[
+			{"policy": {"extends": ["base"]}},
+			{"base": {"ordering": {"hops": 1}}}
+		]`))
+		require.NoError(t, err)
+		assert.Equal(t, &Ordering{Hops: 1}, policy.Ordering)
+		assert.Equal(t, []snet.Path{short, long}, policy.Filter(paths))
+	})
+	t.Run("json", func(t *testing.T) {
+		raw := `{"ordering":{"hops":1,"latency":2,"inverse_bandwidth":3,"expiry":4}}`
+		var policy Policy
+		require.NoError(t, json.Unmarshal([]byte(raw), &policy))
+		assert.Equal(t, &Ordering{Hops: 1, Latency: 2, InverseBandwidth: 3, Expiry: 4},
+			policy.Ordering)
+		marshaled, err := json.Marshal(&policy)
+		require.NoError(t, err)
+		assert.JSONEq(t, raw, string(marshaled))
+	})
+}
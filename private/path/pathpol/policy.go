@@ -14,14 +14,18 @@
 // limitations under the License.
 
 // Package pathpol implements path policies, documentation in doc/PathPolicy.md
-// Currently implemented: ACL, Sequence, Extends and Options.
+// Currently implemented: ACL, Sequence, Extends, Options, and constraints on the
+// announced latency, bandwidth, MTU, link types and router locations.
 //
 // A policy has Filter() method that takes a slice of paths and returns a
-// filtered slice of paths.
+// filtered slice of paths, ranked by cost if the policy defines an ordering.
+// The Evaluate() method explains for each path whether it is kept, and which
+// attribute of the policy rejected it.
 package pathpol
 
 import (
 	"sort"
+	"time"
 
 	"github.com/scionproto/scion/pkg/private/serrors"
 	"github.com/scionproto/scion/pkg/snet"
@@ -51,7 +55,17 @@ type Policy struct {
 	Sequence    *Sequence    `json:"sequence,omitempty"`
 	LocalISDAS  *LocalISDAS  `json:"local_isd_ases,omitempty"`
 	RemoteISDAS *RemoteISDAS `json:"remote_isd_ases,omitempty"`
-	Options     []Option     `json:"options,omitempty"`
+	// MaxLatency, MinBandwidth, MinMTU, ExcludeLinkTypes and Geofence
+	// constrain the announced metadata of the paths.
+	MaxLatency       *LatencyBound   `json:"max_latency,omitempty"`
+	MinBandwidth     *BandwidthBound `json:"min_bandwidth,omitempty"`
+	MinMTU           *MTUBound       `json:"min_mtu,omitempty"`
+	ExcludeLinkTypes *LinkTypeFilter `json:"exclude_link_types,omitempty"`
+	Geofence         *Geofence       `json:"geofence,omitempty"`
+	Options          []Option        `json:"options,omitempty"`
+	// Ordering ranks the paths that are returned by Filter. The orderings of
+	// the option policies are ignored.
+	Ordering *Ordering `json:"ordering,omitempty"`
 }
 
 // NewPolicy creates a Policy and sorts its Options
@@ -85,13 +99,29 @@ func (p *Policy) FilterOpt(paths []snet.Path, opts FilterOptions) []snet.Path {
 	if p.Sequence != nil && !opts.IgnoreSequence {
 		paths = p.Sequence.Eval(paths)
 	}
+	for _, m := range p.metadataPolicies() {
+		paths = m.policy.Eval(paths)
+	}
 	// Filter on sub policies
 	if len(p.Options) > 0 {
 		paths = p.evalOptions(paths, opts)
 	}
+	if p.Ordering != nil {
+		paths = p.Ordering.Rank(paths)
+	}
 	return paths
 }
 
+// Cost returns the cost of the path according to the ordering of the policy.
+// The second return value is false if the policy does not define an
+// ordering.
+func (p *Policy) Cost(path snet.Path) (float64, bool) {
+	if p == nil || p.Ordering == nil {
+		return 0, false
+	}
+	return p.Ordering.Cost(path, time.Now()), true
+}
+
 // PolicyFromExtPolicy creates a Policy from an extending Policy and the extended policies
 func PolicyFromExtPolicy(extPolicy *ExtPolicy, extended []*ExtPolicy) (*Policy, error) {
 	policy := extPolicy.Policy
@@ -145,10 +175,66 @@ func (p *Policy) applyExtended(extends []string, exPolicies []*ExtPolicy) error
 		if p.RemoteISDAS == nil {
 			p.RemoteISDAS = policy.RemoteISDAS
 		}
+		// Replace metadata constraints.
+		if p.MaxLatency == nil {
+			p.MaxLatency = policy.MaxLatency
+		}
+		if p.MinBandwidth == nil {
+			p.MinBandwidth = policy.MinBandwidth
+		}
+		if p.MinMTU == nil {
+			p.MinMTU = policy.MinMTU
+		}
+		if p.ExcludeLinkTypes == nil {
+			p.ExcludeLinkTypes = policy.ExcludeLinkTypes
+		}
+		if p.Geofence == nil {
+			p.Geofence = policy.Geofence
+		}
+		// Replace ordering.
+		if p.Ordering == nil {
+			p.Ordering = policy.Ordering
+		}
 	}
 	return nil
 }
 
+// metadataPolicy is a policy attribute that constrains the path metadata.
+type metadataPolicy interface {
+	Eval([]snet.Path) []snet.Path
+	// check returns the reason why the path metadata violates the
+	// constraint, or an empty string.
+	check(*snet.PathMetadata) string
+}
+
+type metadataAttribute struct {
+	attr   Attribute
+	policy metadataPolicy
+}
+
+// metadataPolicies returns the metadata constraints that are set, in the
+// order in which they are evaluated.
+func (p *Policy) metadataPolicies() []metadataAttribute {
+	var result []metadataAttribute
+	if p.MaxLatency != nil {
+		result = append(result, metadataAttribute{AttributeMaxLatency, p.MaxLatency})
+	}
+	if p.MinBandwidth != nil {
+		result = append(result, metadataAttribute{AttributeMinBandwidth, p.MinBandwidth})
+	}
+	if p.MinMTU != nil {
+		result = append(result, metadataAttribute{AttributeMinMTU, p.MinMTU})
+	}
+	if p.ExcludeLinkTypes != nil {
+		result = append(result,
+			metadataAttribute{AttributeExcludeLinkTypes, p.ExcludeLinkTypes})
+	}
+	if p.Geofence != nil {
+		result = append(result, metadataAttribute{AttributeGeofence, p.Geofence})
+	}
+	return result
+}
+
 // evalOptions evaluates the options of a policy and returns the pathSet that matches the option
 // with the highest weight
 func (p *Policy) evalOptions(paths []snet.Path, opts FilterOptions) []snet.Path {
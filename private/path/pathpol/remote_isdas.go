@@ -40,19 +40,24 @@ func (ri *RemoteISDAS) Eval(paths []snet.Path) []snet.Path {
 		if len(path.Metadata().Interfaces) == 0 {
 			continue
 		}
-		ia := path.Destination()
-		for _, rule := range ri.Rules {
-			if matchISDAS(rule.IA, ia) {
-				if !rule.Reject {
-					result = append(result, path)
-				}
-				break
-			}
+		if i := ri.matchRule(path.Destination()); i >= 0 && !ri.Rules[i].Reject {
+			result = append(result, path)
 		}
 	}
 	return result
 }
 
+// matchRule returns the index of the first rule that matches the ISD-AS, or
+// -1 if no rule matches.
+func (ri *RemoteISDAS) matchRule(ia addr.IA) int {
+	for i, rule := range ri.Rules {
+		if matchISDAS(rule.IA, ia) {
+			return i
+		}
+	}
+	return -1
+}
+
 func matchISDAS(rule addr.IA, ia addr.IA) bool {
 	if rule.ISD() != 0 && rule.ISD() != ia.ISD() {
 		return false
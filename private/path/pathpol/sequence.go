@@ -83,22 +83,31 @@ func (s *Sequence) Eval(paths []snet.Path) []snet.Path {
 	}
 	result := []snet.Path{}
 	for _, path := range paths {
-		desc, err := GetSequence(path)
-		if desc != "" {
-			desc = desc + " "
-		}
+		_, ok, err := s.match(path)
 		if err != nil {
 			log.Error("get sequence from path", "err", err)
 			continue
 		}
-		// Check whether the string matches the sequence regexp.
-		if s.re.MatchString(desc) {
+		if ok {
 			result = append(result, path)
 		}
 	}
 	return result
 }
 
+// match returns the sequence of the path, and whether it matches the sequence.
+func (s *Sequence) match(path snet.Path) (string, bool, error) {
+	desc, err := GetSequence(path)
+	if err != nil {
+		return "", false, err
+	}
+	// Check whether the string matches the sequence regexp.
+	if desc == "" {
+		return desc, s.re.MatchString(desc), nil
+	}
+	return desc, s.re.MatchString(desc + " "), nil
+}
+
 func (s *Sequence) String() string {
 	return s.srcstr
 }
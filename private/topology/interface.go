@@ -37,6 +37,10 @@ type Topology interface {
 	IA() addr.IA
 	// MTU returns the MTU of the local AS.
 	MTU() uint16
+	// PortRange returns the inclusive range of UDP ports on which end hosts
+	// receive SCION packets directly from the border routers. If no range is
+	// configured, both values are zero.
+	PortRange() (uint16, uint16)
 	// Core returns whether the local AS is core.
 	Core() bool
 	// CA returns whether the local AS is a CA.
@@ -151,6 +155,10 @@ func (t *topologyS) MTU() uint16 {
 	return uint16(t.Topology.MTU)
 }
 
+func (t *topologyS) PortRange() (uint16, uint16) {
+	return t.Topology.EndhostStartPort, t.Topology.EndhostEndPort
+}
+
 func (t *topologyS) InterfaceIDs() []common.IFIDType {
 	intfs := make([]common.IFIDType, 0, len(t.Topology.IFInfoMap))
 	for ifid := range t.Topology.IFInfoMap {
@@ -66,6 +66,10 @@ type Topology struct {
 	TimestampHuman string `json:"timestamp_human,omitempty"`
 	IA             string `json:"isd_as"`
 	MTU            int    `json:"mtu"`
+	// EndhostPortRange is the inclusive range of UDP ports, in the format
+	// "<start>-<end>", on which end hosts in the AS receive SCION packets
+	// directly from the border routers, i.e., without the dispatcher.
+	EndhostPortRange string `json:"endhost_port_range,omitempty"`
 	// Attributes are the primary AS attributes as described in
 	// https://github.com/scionproto/scion/blob/master/doc/ControlPlanePKI.md#primary-ases
 	Attributes          []Attribute             `json:"attributes"`
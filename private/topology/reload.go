@@ -122,6 +122,13 @@ func (l *Loader) MTU() uint16 {
 	return l.topo.MTU()
 }
 
+func (l *Loader) PortRange() (uint16, uint16) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	return l.topo.PortRange()
+}
+
 func (l *Loader) Core() bool {
 	l.mtx.Lock()
 	defer l.mtx.Unlock()
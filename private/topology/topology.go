@@ -22,6 +22,8 @@ import (
 	"net"
 	"os"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/scionproto/scion/pkg/addr"
@@ -62,6 +64,12 @@ type (
 		IA         addr.IA
 		Attributes []jsontopo.Attribute
 		MTU        int
+		// EndhostStartPort and EndhostEndPort delimit the inclusive range of
+		// UDP ports on which end hosts receive SCION packets directly from
+		// the border routers. If both are zero, all packets are delivered to
+		// the dispatcher on EndhostPort.
+		EndhostStartPort uint16
+		EndhostEndPort   uint16
 
 		BR        map[string]BRInfo
 		BRNames   []string
@@ -201,9 +209,36 @@ func (t *RWTopology) populateMeta(raw *jsontopo.Topology) error {
 	}
 	t.MTU = raw.MTU
 	t.Attributes = raw.Attributes
+	if raw.EndhostPortRange != "" {
+		if t.EndhostStartPort, t.EndhostEndPort, err = parsePortRange(
+			raw.EndhostPortRange); err != nil {
+
+			return serrors.WrapStr("parsing end host port range", err)
+		}
+	}
 	return nil
 }
 
+// parsePortRange parses a port range in the format "<start>-<end>".
+func parsePortRange(raw string) (uint16, uint16, error) {
+	rawStart, rawEnd, ok := strings.Cut(raw, "-")
+	if !ok {
+		return 0, 0, serrors.New("invalid format, expected <start>-<end>", "range", raw)
+	}
+	start, err := strconv.ParseUint(rawStart, 10, 16)
+	if err != nil {
+		return 0, 0, serrors.WrapStr("parsing start port", err, "range", raw)
+	}
+	end, err := strconv.ParseUint(rawEnd, 10, 16)
+	if err != nil {
+		return 0, 0, serrors.WrapStr("parsing end port", err, "range", raw)
+	}
+	if start == 0 || start > end {
+		return 0, 0, serrors.New("invalid port range", "start", start, "end", end)
+	}
+	return uint16(start), uint16(end), nil
+}
+
 func (t *RWTopology) populateBR(raw *jsontopo.Topology) error {
 	for name, rawBr := range raw.BorderRouters {
 		if rawBr.InternalAddr == "" {
@@ -378,6 +413,9 @@ func (t *RWTopology) Copy() *RWTopology {
 		MTU:        t.MTU,
 		Attributes: append(t.Attributes[:0:0], t.Attributes...),
 
+		EndhostStartPort: t.EndhostStartPort,
+		EndhostEndPort:   t.EndhostEndPort,
+
 		BR:        copyBRMap(t.BR),
 		BRNames:   append(t.BRNames[:0:0], t.BRNames...),
 		IFInfoMap: t.IFInfoMap.copy(),
@@ -35,9 +35,65 @@ func TestMeta(t *testing.T) {
 	assert.Equal(t, time.Unix(168570123, 0), c.Timestamp, "Field 'Timestamp'")
 	assert.Equal(t, addr.MustIAFrom(1, 0xff0000000311), c.IA, "Field 'ISD_AS'")
 	assert.Equal(t, 1472, c.MTU, "Field 'MTU'")
+	assert.Equal(t, uint16(31000), c.EndhostStartPort, "Field 'EndhostStartPort'")
+	assert.Equal(t, uint16(32767), c.EndhostEndPort, "Field 'EndhostEndPort'")
 	assert.Empty(t, c.Attributes, "Field 'Attributes'")
 }
 
+func TestEndhostPortRange(t *testing.T) {
+	testCases := map[string]struct {
+		Raw        string
+		Start, End uint16
+		Assertion  assert.ErrorAssertionFunc
+	}{
+		"unset": {
+			Assertion: assert.NoError,
+		},
+		"valid": {
+			Raw:       "31000-32767",
+			Start:     31000,
+			End:       32767,
+			Assertion: assert.NoError,
+		},
+		"single port": {
+			Raw:       "31000-31000",
+			Start:     31000,
+			End:       31000,
+			Assertion: assert.NoError,
+		},
+		"missing separator": {
+			Raw:       "31000",
+			Assertion: assert.Error,
+		},
+		"inverted": {
+			Raw:       "32767-31000",
+			Assertion: assert.Error,
+		},
+		"zero start": {
+			Raw:       "0-31000",
+			Assertion: assert.Error,
+		},
+		"out of range": {
+			Raw:       "31000-70000",
+			Assertion: assert.Error,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			raw := &jsontopo.Topology{IA: "1-ff00:0:311", EndhostPortRange: tc.Raw}
+			topo, err := RWTopologyFromJSONTopology(raw)
+			tc.Assertion(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.Start, topo.EndhostStartPort)
+			assert.Equal(t, tc.End, topo.EndhostEndPort)
+		})
+	}
+}
+
 func TestActive(t *testing.T) {
 	t.Run("positive TTL", func(t *testing.T) {
 		c := MustLoadTopo(t, "testdata/basic.json")
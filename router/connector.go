@@ -129,6 +129,11 @@ func (c *Connector) AddExternalInterface(localIfID common.IFIDType, link control
 		return c.DataPlane.AddNextHop(intf, link.Remote.Addr)
 	}
 
+	if link.MTU > 0 {
+		if err := c.DataPlane.AddLinkMTU(intf, link.MTU); err != nil {
+			return serrors.WrapStr("adding link MTU", err, "if_id", localIfID)
+		}
+	}
 	connection, err := conn.New(link.Local.Addr, link.Remote.Addr,
 		&conn.Config{ReceiveBufferSize: receiveBufferSize})
 	if err != nil {
@@ -180,6 +185,29 @@ func (c *Connector) SetKey(ia addr.IA, index int, key []byte) error {
 	return c.DataPlane.SetKey(key)
 }
 
+// SetPortRange sets the range of UDP ports on which end hosts receive SCION
+// packets directly.
+func (c *Connector) SetPortRange(ia addr.IA, start, end uint16) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	log.Debug("Setting end host port range", "isd_as", ia, "start", start, "end", end)
+	if !c.ia.Equal(ia) {
+		return serrors.WithCtx(errMultiIA, "current", c.ia, "new", ia)
+	}
+	return c.DataPlane.SetPortRange(start, end)
+}
+
+// SetInternalMTU sets the MTU of the internal network of the AS.
+func (c *Connector) SetInternalMTU(ia addr.IA, mtu int) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	log.Debug("Setting internal MTU", "isd_as", ia, "mtu", mtu)
+	if !c.ia.Equal(ia) {
+		return serrors.WithCtx(errMultiIA, "current", c.ia, "new", ia)
+	}
+	return c.DataPlane.SetInternalMTU(mtu)
+}
+
 func (c *Connector) ListInternalInterfaces() ([]control.InternalInterface, error) {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
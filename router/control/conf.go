@@ -37,6 +37,8 @@ type Dataplane interface {
 	AddSvc(ia addr.IA, svc addr.HostSVC, ip net.IP) error
 	DelSvc(ia addr.IA, svc addr.HostSVC, ip net.IP) error
 	SetKey(ia addr.IA, index int, key []byte) error
+	SetPortRange(ia addr.IA, start, end uint16) error
+	SetInternalMTU(ia addr.IA, mtu int) error
 }
 
 // LinkInfo contains the information about a link between an internal and
@@ -124,6 +126,20 @@ func ConfigDataplane(dp Dataplane, cfg *Config) error {
 			return err
 		}
 	}
+	// Set the port range for direct delivery to end hosts
+	if cfg.Topo != nil {
+		if start, end := cfg.Topo.PortRange(); start != 0 {
+			if err := dp.SetPortRange(cfg.IA, start, end); err != nil {
+				return err
+			}
+		}
+		// Set the MTU of the internal network
+		if mtu := cfg.Topo.MTU(); mtu > 0 {
+			if err := dp.SetInternalMTU(cfg.IA, int(mtu)); err != nil {
+				return err
+			}
+		}
+	}
 	// Add internal interfaces
 	if cfg.BR != nil {
 		if cfg.BR.InternalAddr != nil {
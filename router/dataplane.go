@@ -33,6 +33,7 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/subtle"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"hash"
@@ -197,6 +198,11 @@ type DataPlane struct {
 	macFactory        func() hash.Hash
 	bfdSessions       map[uint16]bfdSession
 	localIA           addr.IA
+	endhostStartPort  uint16
+	endhostEndPort    uint16
+	linkMTUs          map[uint16]int
+	internalMTU       int
+	packetTooBig      scmpRateLimiter
 	mtx               sync.Mutex
 	running           bool
 	Metrics           *Metrics
@@ -218,6 +224,9 @@ var (
 	noBFDSessionFound             = serrors.New("no BFD sessions was found")
 	noBFDSessionConfigured        = serrors.New("no BFD sessions have been configured")
 	errBFDDisabled                = serrors.New("BFD is disabled")
+	invalidPortRange              = serrors.New("invalid end host port range")
+	invalidMTU                    = serrors.New("invalid MTU")
+	packetTooBig                  = serrors.New("packet exceeds MTU of egress link")
 )
 
 type scmpError struct {
@@ -333,6 +342,71 @@ func (d *DataPlane) SetKey(key []byte) (res error) {
 	return nil
 }
 
+// SetPortRange sets the inclusive range of UDP ports on which end hosts in the
+// local AS receive SCION packets directly. Packets destined to a port outside
+// of this range are delivered to the dispatcher on topology.EndhostPort.
+// @ requires  acc(d.Mem(), OutMutexPerm)
+// @ requires  !d.IsRunning()
+// @ preserves d.mtx.LockP()
+// @ preserves d.mtx.LockInv() == MutexInvariant!<d!>
+// @ ensures   acc(d.Mem(), OutMutexPerm)
+// @ ensures   !d.IsRunning()
+// @ ensures   e != nil ==> e.ErrorMem()
+// @ decreases 0 if sync.IgnoreBlockingForTermination()
+func (d *DataPlane) SetPortRange(start, end uint16) (e error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	// @ unfold MutexInvariant!<d!>()
+	// @ assert !d.IsRunning()
+	// @ d.isRunningEq()
+	// @ unfold d.Mem()
+	// @ defer fold MutexInvariant!<d!>()
+	// @ defer fold d.Mem()
+	if d.running {
+		// @ Unreachable()
+		return modifyExisting
+	}
+	if start == 0 || start > end {
+		// @ establishInvalidPortRange()
+		return invalidPortRange
+	}
+	d.endhostStartPort = start
+	d.endhostEndPort = end
+	return nil
+}
+
+// SetInternalMTU sets the MTU of the internal network of the local AS. Packets
+// that are forwarded over the internal network and exceed the MTU are dropped.
+// If no MTU is set, packets are not checked.
+// @ requires  acc(d.Mem(), OutMutexPerm)
+// @ requires  !d.IsRunning()
+// @ preserves d.mtx.LockP()
+// @ preserves d.mtx.LockInv() == MutexInvariant!<d!>
+// @ ensures   acc(d.Mem(), OutMutexPerm)
+// @ ensures   !d.IsRunning()
+// @ ensures   e != nil ==> e.ErrorMem()
+// @ decreases 0 if sync.IgnoreBlockingForTermination()
+func (d *DataPlane) SetInternalMTU(mtu int) (e error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	// @ unfold MutexInvariant!<d!>()
+	// @ assert !d.IsRunning()
+	// @ d.isRunningEq()
+	// @ unfold d.Mem()
+	// @ defer fold MutexInvariant!<d!>()
+	// @ defer fold d.Mem()
+	if d.running {
+		// @ Unreachable()
+		return modifyExisting
+	}
+	if mtu <= 0 {
+		// @ establishInvalidMTU()
+		return invalidMTU
+	}
+	d.internalMTU = mtu
+	return nil
+}
+
 // AddInternalInterface sets the interface the data-plane will use to
 // send/receive traffic in the local AS. This can only be called once; future
 // calls will return an error. This can only be called on a not yet running
@@ -487,6 +561,46 @@ func (d *DataPlane) AddLinkType(ifID uint16, linkTo topology.LinkType) error {
 	return nil
 }
 
+// AddLinkMTU sets the MTU of the link of an external interface. Packets that
+// are forwarded over the link and exceed the MTU are dropped. If no MTU is set,
+// packets are not checked. This can only be called on a not yet running
+// dataplane.
+// @ requires  acc(d.Mem(), OutMutexPerm)
+// @ requires  !d.IsRunning()
+// @ preserves d.mtx.LockP()
+// @ preserves d.mtx.LockInv() == MutexInvariant!<d!>
+// @ ensures   acc(d.Mem(), OutMutexPerm)
+// @ ensures   !d.IsRunning()
+// @ ensures   e != nil ==> e.ErrorMem()
+// @ decreases 0 if sync.IgnoreBlockingForTermination()
+func (d *DataPlane) AddLinkMTU(ifID uint16, mtu int) (e error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	// @ unfold MutexInvariant!<d!>()
+	// @ assert !d.IsRunning()
+	// @ d.isRunningEq()
+	// @ unfold d.Mem()
+	// @ defer fold MutexInvariant!<d!>()
+	// @ defer fold d.Mem()
+	if d.running {
+		// @ Unreachable()
+		return modifyExisting
+	}
+	if mtu <= 0 {
+		// @ establishInvalidMTU()
+		return serrors.WithCtx(invalidMTU, "ifID", ifID)
+	}
+	if _, existsB := d.linkMTUs[ifID]; existsB {
+		// @ establishAlreadySet()
+		return serrors.WithCtx(alreadySet, "ifID", ifID)
+	}
+	if d.linkMTUs == nil {
+		d.linkMTUs = make(map[uint16]int)
+	}
+	d.linkMTUs[ifID] = mtu
+	return nil
+}
+
 // AddExternalInterfaceBFD adds the inter AS connection BFD session.
 // @ trusted
 // @ requires false
@@ -2962,9 +3076,10 @@ func (p *scionPacketProcessor) verifyCurrentMAC( /*@ ghost dp io.DataPlaneSpec,
 // @ 	absIO_val(respr.OutPkt, respr.EgressID).isIO_val_Unsupported
 // @ decreases 0 if sync.IgnoreBlockingForTermination()
 func (p *scionPacketProcessor) resolveInbound( /*@ ghost ubScionL []byte, ghost ubLL []byte, ghost startLL int, ghost endLL int @*/ ) (resaddr *net.UDPAddr, respr processResult, reserr error /*@ , ghost addrAliasesUb bool @*/) {
+	port := p.endhostPort( /*@ ubScionL, ubLL, startLL, endLL @*/ )
 	// (VerifiedSCION) the parameter used to be p.scionLayer,
 	// instead of &p.scionLayer.
-	a, err /*@ , addrAliases @*/ := p.d.resolveLocalDst(&p.scionLayer /*@, ubScionL @*/)
+	a, err /*@ , addrAliases @*/ := p.d.resolveLocalDst(&p.scionLayer, port /*@, ubScionL @*/)
 	// @ establishNoSVCBackend()
 	switch {
 	case errors.Is(err, noSVCBackend):
@@ -3354,6 +3469,139 @@ func (p *scionPacketProcessor) validateEgressUp(
 	return processResult{}, nil
 }
 
+// validateEgressMTU checks that the packet does not exceed the MTU of the link
+// it is sent on. The egress interface 0, as well as the interfaces of sibling
+// routers, denote the internal network of the local AS. Oversized packets are
+// dropped and answered with an SCMP Packet Too Big message, unless the rate
+// limit for such messages is exceeded.
+// @ requires  0 <= startLL && startLL <= endLL && endLL <= len(ub)
+// @ requires  acc(&p.rawPkt, R50) && ub === p.rawPkt
+// @ requires  sl.Bytes(ub, 0, len(ub))
+// @ requires  acc(p.scionLayer.Mem(ub), R2)
+// @ requires  p.scionLayer.ValidPathMetaData(ub)
+// @ requires  acc(&p.buffer, R50) && p.buffer != nil && p.buffer.Mem()
+// @ requires  sl.Bytes(p.buffer.UBuf(), 0, len(p.buffer.UBuf()))
+// @ requires  acc(&p.d, R50) && acc(p.d.Mem(), _)
+// @ requires  acc(&p.ingressID, R21)
+// @ preserves ubLL == nil || ubLL === ub[startLL:endLL]
+// @ preserves acc(&p.lastLayer, R55) && p.lastLayer != nil
+// @ preserves &p.scionLayer !== p.lastLayer ==>
+// @ 	acc(p.lastLayer.Mem(ubLL), R15)
+// @ preserves &p.scionLayer === p.lastLayer ==>
+// @ 	ub === ubLL
+// @ ensures   acc(&p.rawPkt, R50)
+// @ ensures   acc(&p.ingressID, R21)
+// @ ensures   acc(&p.d, R50) && acc(p.d.Mem(), _)
+// @ ensures   acc(p.scionLayer.Mem(ub), R2)
+// @ ensures   sl.Bytes(ub, 0, len(ub))
+// @ ensures   p.d.validResult(respr, false)
+// @ ensures   acc(&p.buffer, R50) && p.buffer != nil && p.buffer.Mem()
+// @ ensures   sl.Bytes(p.buffer.UBuf(), 0, len(p.buffer.UBuf()))
+// @ ensures   respr !== processResult{} ==>
+// @ 	respr.OutPkt === p.buffer.UBuf()
+// @ ensures   reserr != nil ==> reserr.ErrorMem()
+// @ ensures   reserr == nil ==>
+// @ 	respr === processResult{}
+// @ ensures   reserr == nil ==> absPkt(ub) == old(absPkt(ub))
+// @ ensures   reserr == nil ==> old(slayers.IsSupportedPkt(ub)) == slayers.IsSupportedPkt(ub)
+// @ ensures   reserr != nil && respr.OutPkt != nil ==>
+// @ 	absIO_val(respr.OutPkt, respr.EgressID).isIO_val_Unsupported
+// @ decreases 0 if sync.IgnoreBlockingForTermination()
+func (p *scionPacketProcessor) validateEgressMTU(
+	egressID uint16,
+	// @ ghost ub []byte,
+	// @ ghost ubLL []byte,
+	// @ ghost startLL int,
+	// @ ghost endLL int,
+) (respr processResult, reserr error) {
+	// @ p.d.getMTUsMem()
+	// @ p.d.getExternalMem()
+	// @ if p.d.external != nil { unfold acc(accBatchConn(p.d.external), _) }
+	mtu, metricsID := p.d.internalMTU, uint16(0)
+	if _, external := p.d.external[egressID]; external {
+		mtu, metricsID = p.d.linkMTUs[egressID], egressID
+	}
+	if mtu == 0 || len(p.rawPkt) <= mtu {
+		// @ fold p.d.validResult(processResult{}, false)
+		return processResult{}, nil
+	}
+	// @ establishPacketTooBig()
+	cause := serrors.WithCtx(packetTooBig, "mtu", mtu, "length", len(p.rawPkt))
+	if !p.d.reportPacketTooBig(metricsID) {
+		// @ fold p.d.validResult(processResult{}, false)
+		return processResult{}, cause
+	}
+	tmpRes, tmpErr := p.packSCMP(
+		slayers.SCMPTypePacketTooBig,
+		0,
+		&slayers.SCMPPacketTooBig{MTU: uint16(mtu)},
+		cause,
+		/*@ ub, ubLL, startLL, endLL, @*/
+	)
+	// @ ghost if tmpErr != nil && tmpRes.OutPkt != nil {
+	// @ 	AbsUnsupportedPktIsUnsupportedVal(tmpRes.OutPkt, tmpRes.EgressID)
+	// @ }
+	return tmpRes, tmpErr
+}
+
+// reportPacketTooBig counts an oversized packet in the metrics of the link
+// identified by metricsID. It returns whether the rate limit allows to answer
+// the packet with an SCMP Packet Too Big message.
+// (VerifiedSCION) marked as trusted because the forwarding metrics are only
+// known to exist for the interfaces of a well-configured dataplane, which is
+// not established in the packet processor, and because of the rate limiter.
+// @ trusted
+// @ requires acc(d.Mem(), _)
+// @ decreases 0 if sync.IgnoreBlockingForTermination()
+func (d *DataPlane) reportPacketTooBig(metricsID uint16) bool {
+	if c := d.forwardingMetrics[metricsID].OversizedPacketsTotal; c != nil {
+		c.Inc()
+	}
+	return d.packetTooBig.allow()
+}
+
+// packetTooBigRate is the maximum number of SCMP Packet Too Big messages that
+// the router sends per second.
+const packetTooBigRate = 100
+
+// scmpRateLimiter limits the rate at which SCMP messages are generated. It is
+// a token bucket that holds at most one second worth of tokens. The zero value
+// allows packetTooBigRate messages per second.
+type scmpRateLimiter struct {
+	mtx    sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// allow takes a token from the bucket. It returns false if no token is
+// available.
+// (VerifiedSCION) marked as trusted because the bucket is shared by all packet
+// processors, which only hold a wildcard permission to it. Its state is
+// protected by l.mtx, whose lock invariant is not modelled, and it does not
+// affect the memory of the caller.
+// @ trusted
+// @ requires acc(l, _)
+// @ decreases 0 if sync.IgnoreBlockingForTermination()
+func (l *scmpRateLimiter) allow() bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	now := time.Now()
+	if l.last.IsZero() {
+		l.tokens = packetTooBigRate
+	} else {
+		l.tokens += now.Sub(l.last).Seconds() * packetTooBigRate
+		if l.tokens > packetTooBigRate {
+			l.tokens = packetTooBigRate
+		}
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
 // @ requires  0 <= startLL && startLL <= endLL && endLL <= len(ub)
 // @ requires  acc(&p.path, R20)
 // @ requires  sl.Bytes(ub, 0, len(ub))
@@ -3905,6 +4153,10 @@ func (p *scionPacketProcessor) process(
 		// @ p.LocalDstLemma(ub)
 		// @ assert p.ingressID != 0
 		// @ assert len(nextPkt.CurrSeg.Future) == 1
+		if r, err := p.validateEgressMTU(0 /*@, ub, ubLL, startLL, endLL @*/); err != nil {
+			// @ p.scionLayer.DowngradePerm(ub)
+			return r, err /*@, false, absReturnErr(r) @*/
+		}
 		a, r, err /*@, aliasesUb @*/ := p.resolveInbound( /*@ ub, ubLL, startLL, endLL @*/ )
 		if err != nil {
 			// @ p.scionLayer.DowngradePerm(ub)
@@ -3972,6 +4224,10 @@ func (p *scionPacketProcessor) process(
 	// @ assert nextPkt == absPkt(ub)
 	egressID := p.egressInterface( /*@ nextPkt @*/ )
 	// @ assert AbsEgressInterfaceConstraint(nextPkt, path.ifsToIO_ifs(egressID))
+	if r, err := p.validateEgressMTU(egressID /*@, ub, ubLL, startLL, endLL @*/); err != nil {
+		// @ p.scionLayer.DowngradePerm(ub)
+		return r, err /*@, false, absReturnErr(r) @*/
+	}
 	// @ p.d.getExternalMem()
 	// @ if p.d.external != nil { unfold acc(accBatchConn(p.d.external), _) }
 	if c, ok := p.d.external[egressID]; ok {
@@ -4218,7 +4474,7 @@ func (p *scionPacketProcessor) processOHP() (respr processResult, reserr error /
 	}
 	// (VerifiedSCION) the parameter was changed from 's' to '&p.scionLayer' due to the
 	// changes made to 'resolveLocalDst'.
-	a, err /*@ , addrAliases @*/ := p.d.resolveLocalDst(&p.scionLayer /* s */ /*@ , ubScionL @*/)
+	a, err /*@ , addrAliases @*/ := p.d.resolveLocalDst(&p.scionLayer /* s */, topology.EndhostPort /*@ , ubScionL @*/)
 	if err != nil {
 		// @ ghost if addrAliases {
 		// @ 	apply acc(a.Mem(), R15) --* acc(sl.Bytes(ubScionL, 0, len(ubScionL)), R15)
@@ -4246,7 +4502,7 @@ func (p *scionPacketProcessor) processOHP() (respr processResult, reserr error /
 // specs a lot easier and, makes the implementation faster as well by avoiding passing large data-structures
 // by value. We should consider porting merging this in upstream SCION.
 // @ decreases 0 if sync.IgnoreBlockingForTermination()
-func (d *DataPlane) resolveLocalDst(s *slayers.SCION /*@, ghost ub []byte @*/) (resaddr *net.UDPAddr, reserr error /*@ , ghost addrAliasesUb bool @*/) {
+func (d *DataPlane) resolveLocalDst(s *slayers.SCION, port uint16 /*@, ghost ub []byte @*/) (resaddr *net.UDPAddr, reserr error /*@ , ghost addrAliasesUb bool @*/) {
 	// @ ghost start, end := s.ExtractAcc(ub)
 	// @ assert s.RawDstAddr === ub[start:end]
 	// @ sl.SplitRange_Bytes(ub, start, end, R15)
@@ -4274,7 +4530,7 @@ func (d *DataPlane) resolveLocalDst(s *slayers.SCION /*@, ghost ub []byte @*/) (
 		// @ sl.CombineRange_Bytes(ub, start, end, R15)
 		return a, nil /*@ , false @*/
 	case *net.IPAddr:
-		tmp := addEndhostPort(v)
+		tmp := addEndhostPort(v, port)
 		// @ package acc(tmp.Mem(), R15) --* acc(sl.Bytes(ub, 0, len(ub)), R15) {
 		// @ 	apply acc(tmp.Mem(), R15) --* acc(v.Mem(), R15)
 		// @ 	assert acc(dst.Mem(), R15)
@@ -4291,9 +4547,9 @@ func (d *DataPlane) resolveLocalDst(s *slayers.SCION /*@, ghost ub []byte @*/) (
 // @ ensures  res != nil && acc(res.Mem(), R15)
 // @ ensures  acc(res.Mem(), R15) --* acc(dst.Mem(), R15)
 // @ decreases
-func addEndhostPort(dst *net.IPAddr) (res *net.UDPAddr) {
+func addEndhostPort(dst *net.IPAddr, port uint16) (res *net.UDPAddr) {
 	// @ unfold acc(dst.Mem(), R15)
-	tmp := &net.UDPAddr{IP: dst.IP, Port: topology.EndhostPort}
+	tmp := &net.UDPAddr{IP: dst.IP, Port: int(port)}
 	// @ assert forall i int :: { &tmp.IP[i] } 0 <= i && i < len(tmp.IP) ==> acc(&tmp.IP[i], R15)
 	// @ fold acc(sl.Bytes(tmp.IP, 0, len(tmp.IP)), R15)
 	// @ fold acc(tmp.Mem(), R15)
@@ -4310,6 +4566,131 @@ func addEndhostPort(dst *net.IPAddr) (res *net.UDPAddr) {
 	return tmp
 }
 
+// endhostPort returns the underlay UDP port on which the destination end host
+// receives the packet. For SCION/UDP, this is the destination port. For SCMP
+// informational replies, it is the identifier, and for SCMP errors it is the
+// source port (or identifier) of the quoted packet. If the port is not within
+// the end host port range of the local AS, the packet is delivered to the
+// dispatcher on topology.EndhostPort.
+// @ requires  0 <= startLL && startLL <= endLL && endLL <= len(ubScionL)
+// @ preserves acc(&p.d, R50) && acc(p.d.Mem(), _)
+// @ preserves acc(sl.Bytes(ubScionL, 0, len(ubScionL)), R20)
+// @ preserves acc(p.scionLayer.Mem(ubScionL), R20)
+// @ preserves ubLL == nil || ubLL === ubScionL[startLL:endLL]
+// @ preserves acc(&p.lastLayer, R55) && p.lastLayer != nil
+// @ preserves &p.scionLayer !== p.lastLayer ==>
+// @ 	acc(p.lastLayer.Mem(ubLL), R15)
+// @ preserves &p.scionLayer === p.lastLayer ==>
+// @ 	ubScionL === ubLL
+// @ decreases
+func (p *scionPacketProcessor) endhostPort( /*@ ghost ubScionL []byte, ghost ubLL []byte, ghost startLL int, ghost endLL int @*/ ) uint16 {
+	// @ p.d.getEndhostPortRange()
+	start, end := p.d.endhostStartPort, p.d.endhostEndPort
+	if start == 0 {
+		return topology.EndhostPort
+	}
+	pld /*@ , startPld, endPld @*/ := p.lastLayer.LayerPayload( /*@ ubLL @*/ )
+	// @ sl.SplitRange_Bytes(ubScionL, startLL, endLL, R20)
+	// @ ghost if pld == nil {
+	// @ 	sl.NilAcc_Bytes()
+	// @ } else {
+	// @ 	sl.SplitRange_Bytes(ubLL, startPld, endPld, R20)
+	// @ }
+	var port uint16
+	var ok bool
+	switch nextHdr(p.lastLayer /*@ , ubLL @*/) {
+	case slayers.L4UDP:
+		if len(pld) >= 4 {
+			// @ unfold acc(sl.Bytes(pld, 0, len(pld)), R20)
+			// @ assert &pld[2:4][0] == &pld[2] && &pld[2:4][1] == &pld[3]
+			port, ok = binary.BigEndian.Uint16(pld[2:4]), true
+			// @ fold acc(sl.Bytes(pld, 0, len(pld)), R20)
+		}
+	case slayers.L4SCMP:
+		port, ok = scmpEndhostPort(pld)
+	}
+	// @ ghost if pld != nil { sl.CombineRange_Bytes(ubLL, startPld, endPld, R20) }
+	// @ sl.CombineRange_Bytes(ubScionL, startLL, endLL, R20)
+	if !ok || port < start || port > end {
+		return topology.EndhostPort
+	}
+	return port
+}
+
+// scmpEndhostPort extracts the port of the end host an SCMP message is destined
+// to. For echo and traceroute replies, the identifier is used. For SCMP errors,
+// the port is taken from the L4 header of the quoted packet: the source port
+// for SCION/UDP, or the identifier for SCMP echo and traceroute requests. The
+// boolean result is false if the message does not carry enough information.
+// @ preserves acc(sl.Bytes(scmp, 0, len(scmp)), R55)
+// @ decreases
+func scmpEndhostPort(scmp []byte) (uint16, bool) {
+	const scmpHdrLen = 4
+	if len(scmp) < scmpHdrLen {
+		return 0, false
+	}
+	// @ unfold acc(sl.Bytes(scmp, 0, len(scmp)), R55)
+	// @ defer fold acc(sl.Bytes(scmp, 0, len(scmp)), R55)
+	var quoteOffset int
+	switch slayers.SCMPType(scmp[0]) {
+	case slayers.SCMPTypeEchoReply, slayers.SCMPTypeTracerouteReply:
+		if len(scmp) < scmpHdrLen+2 {
+			return 0, false
+		}
+		// @ assert &scmp[scmpHdrLen:scmpHdrLen+2][0] == &scmp[scmpHdrLen]
+		// @ assert &scmp[scmpHdrLen:scmpHdrLen+2][1] == &scmp[scmpHdrLen+1]
+		return binary.BigEndian.Uint16(scmp[scmpHdrLen : scmpHdrLen+2]), true
+	case slayers.SCMPTypeDestinationUnreachable, slayers.SCMPTypePacketTooBig,
+		slayers.SCMPTypeParameterProblem:
+
+		quoteOffset = scmpHdrLen + 4
+	case slayers.SCMPTypeExternalInterfaceDown:
+		quoteOffset = scmpHdrLen + 16
+	case slayers.SCMPTypeInternalConnectivityDown:
+		quoteOffset = scmpHdrLen + 24
+	default:
+		return 0, false
+	}
+	if len(scmp) < quoteOffset+slayers.CmnHdrLen {
+		return 0, false
+	}
+	// The offsets below are relative to the start of the SCMP message, the
+	// quoted packet starts at quoteOffset.
+	l4Type := slayers.L4ProtocolType(scmp[quoteOffset+4])
+	offset := quoteOffset + int(scmp[quoteOffset+5])*slayers.LineLen
+	// Skip the extension headers of the quoted packet.
+	// @ invariant quoteOffset <= offset
+	// @ invariant forall i int :: { &scmp[i] } 0 <= i && i < len(scmp) ==>
+	// @ 	acc(&scmp[i], R55)
+	// @ decreases len(scmp) - offset
+	for (l4Type == slayers.HopByHopClass || l4Type == slayers.End2EndClass) &&
+		offset+2 <= len(scmp) {
+
+		l4Type = slayers.L4ProtocolType(scmp[offset])
+		offset += (int(scmp[offset+1]) + 1) * slayers.LineLen
+	}
+	switch l4Type {
+	case slayers.L4UDP:
+		if len(scmp) < offset+2 {
+			return 0, false
+		}
+		// @ assert &scmp[offset:offset+2][0] == &scmp[offset]
+		// @ assert &scmp[offset:offset+2][1] == &scmp[offset+1]
+		return binary.BigEndian.Uint16(scmp[offset : offset+2]), true
+	case slayers.L4SCMP:
+		if len(scmp) < offset+scmpHdrLen+2 {
+			return 0, false
+		}
+		switch slayers.SCMPType(scmp[offset]) {
+		case slayers.SCMPTypeEchoRequest, slayers.SCMPTypeTracerouteRequest:
+			id := offset + scmpHdrLen
+			// @ assert &scmp[id:id+2][0] == &scmp[id] && &scmp[id:id+2][1] == &scmp[id+1]
+			return binary.BigEndian.Uint16(scmp[id : id+2]), true
+		}
+	}
+	return 0, false
+}
+
 // TODO(matzf) this function is now only used to update the OneHop-path.
 // This should be changed so that the OneHop-path can be updated in-place, like
 // the scion.Raw path.
@@ -4850,11 +5231,12 @@ func nextHdr(layer gopacket.DecodingLayer /*@ , ghost ubuf []byte @*/) slayers.L
 // forwardingMetrics contains the subset of Metrics relevant for forwarding,
 // instantiated with some interface-specific labels.
 type forwardingMetrics struct {
-	InputBytesTotal     prometheus.Counter
-	OutputBytesTotal    prometheus.Counter
-	InputPacketsTotal   prometheus.Counter
-	OutputPacketsTotal  prometheus.Counter
-	DroppedPacketsTotal prometheus.Counter
+	InputBytesTotal       prometheus.Counter
+	OutputBytesTotal      prometheus.Counter
+	InputPacketsTotal     prometheus.Counter
+	OutputPacketsTotal    prometheus.Counter
+	DroppedPacketsTotal   prometheus.Counter
+	OversizedPacketsTotal prometheus.Counter
 }
 
 // @ requires  acc(labels, _)
@@ -4864,17 +5246,19 @@ type forwardingMetrics struct {
 func initForwardingMetrics(metrics *Metrics, labels prometheus.Labels) (res forwardingMetrics) {
 	// @ unfold acc(metrics.Mem(), _)
 	c := forwardingMetrics{
-		InputBytesTotal:     metrics.InputBytesTotal.With(labels),
-		InputPacketsTotal:   metrics.InputPacketsTotal.With(labels),
-		OutputBytesTotal:    metrics.OutputBytesTotal.With(labels),
-		OutputPacketsTotal:  metrics.OutputPacketsTotal.With(labels),
-		DroppedPacketsTotal: metrics.DroppedPacketsTotal.With(labels),
+		InputBytesTotal:       metrics.InputBytesTotal.With(labels),
+		InputPacketsTotal:     metrics.InputPacketsTotal.With(labels),
+		OutputBytesTotal:      metrics.OutputBytesTotal.With(labels),
+		OutputPacketsTotal:    metrics.OutputPacketsTotal.With(labels),
+		DroppedPacketsTotal:   metrics.DroppedPacketsTotal.With(labels),
+		OversizedPacketsTotal: metrics.OversizedPacketsTotal.With(labels),
 	}
 	c.InputBytesTotal.Add(float64(0))
 	c.InputPacketsTotal.Add(float64(0))
 	c.OutputBytesTotal.Add(float64(0))
 	c.OutputPacketsTotal.Add(float64(0))
 	c.DroppedPacketsTotal.Add(float64(0))
+	c.OversizedPacketsTotal.Add(float64(0))
 	// @ fold acc(forwardingMetricsNonInjectiveMem(c), _)
 	return c
 }
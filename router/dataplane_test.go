@@ -104,6 +104,56 @@ func TestDataPlaneSetKey(t *testing.T) {
 	})
 }
 
+func TestDataPlaneSetPortRange(t *testing.T) {
+	t.Run("fails after serve", func(t *testing.T) {
+		d := &router.DataPlane{}
+		d.FakeStart()
+		assert.Error(t, d.SetPortRange(31000, 32767))
+	})
+	t.Run("invalid range is not allowed", func(t *testing.T) {
+		d := &router.DataPlane{}
+		assert.Error(t, d.SetPortRange(0, 32767))
+		assert.Error(t, d.SetPortRange(32767, 31000))
+	})
+	t.Run("set works", func(t *testing.T) {
+		d := &router.DataPlane{}
+		assert.NoError(t, d.SetPortRange(31000, 32767))
+	})
+}
+
+func TestDataPlaneSetInternalMTU(t *testing.T) {
+	t.Run("fails after serve", func(t *testing.T) {
+		d := &router.DataPlane{}
+		d.FakeStart()
+		assert.Error(t, d.SetInternalMTU(1472))
+	})
+	t.Run("invalid MTU is not allowed", func(t *testing.T) {
+		d := &router.DataPlane{}
+		assert.Error(t, d.SetInternalMTU(0))
+	})
+	t.Run("set works", func(t *testing.T) {
+		d := &router.DataPlane{}
+		assert.NoError(t, d.SetInternalMTU(1472))
+	})
+}
+
+func TestDataPlaneAddLinkMTU(t *testing.T) {
+	t.Run("fails after serve", func(t *testing.T) {
+		d := &router.DataPlane{}
+		d.FakeStart()
+		assert.Error(t, d.AddLinkMTU(1, 1472))
+	})
+	t.Run("invalid MTU is not allowed", func(t *testing.T) {
+		d := &router.DataPlane{}
+		assert.Error(t, d.AddLinkMTU(1, 0))
+	})
+	t.Run("double set fails", func(t *testing.T) {
+		d := &router.DataPlane{}
+		assert.NoError(t, d.AddLinkMTU(1, 1472))
+		assert.Error(t, d.AddLinkMTU(1, 1472))
+	})
+}
+
 func TestDataPlaneAddExternalInterface(t *testing.T) {
 	t.Run("fails after serve", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
@@ -1199,6 +1249,263 @@ func TestProcessPkt(t *testing.T) {
 	}
 }
 
+func TestProcessPktEndhostPort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key := []byte("testkey_xxxxxxxx")
+	now := time.Now()
+	dst := &net.IPAddr{IP: net.ParseIP("10.0.100.100").To4()}
+	src := &net.IPAddr{IP: net.ParseIP("10.0.200.200").To4()}
+
+	// quote returns a serialized SCION/UDP packet sent by the end host from
+	// srcPort, as it is quoted in SCMP error messages.
+	quote := func(t *testing.T, srcPort uint16) []byte {
+		spkt, dpath := prepBaseMsg(now)
+		spkt.SrcIA = xtest.MustParseIA("1-ff00:0:110")
+		require.NoError(t, spkt.SetSrcAddr(dst))
+		require.NoError(t, spkt.SetDstAddr(src))
+		dpath.HopFields = []path.HopField{
+			{ConsIngress: 0, ConsEgress: 1},
+			{ConsIngress: 31, ConsEgress: 30},
+			{ConsIngress: 41, ConsEgress: 40},
+		}
+		dpath.Base.PathMeta.CurrHF = 0
+		spkt.Path = dpath
+		buffer := gopacket.NewSerializeBuffer()
+		err := gopacket.SerializeLayers(buffer, gopacket.SerializeOptions{FixLengths: true},
+			spkt, &slayers.UDP{SrcPort: srcPort, DstPort: 40000},
+			gopacket.Payload("actualpayloadbytes"))
+		require.NoError(t, err)
+		return buffer.Bytes()
+	}
+
+	testCases := map[string]struct {
+		portRange    [2]uint16
+		nextHdr      slayers.L4ProtocolType
+		l4           func(t *testing.T) []gopacket.SerializableLayer
+		expectedPort int
+	}{
+		"udp in range": {
+			portRange: [2]uint16{31000, 32767},
+			nextHdr:   slayers.L4UDP,
+			l4: func(*testing.T) []gopacket.SerializableLayer {
+				return []gopacket.SerializableLayer{
+					&slayers.UDP{SrcPort: 40000, DstPort: 31042},
+				}
+			},
+			expectedPort: 31042,
+		},
+		"udp out of range": {
+			portRange: [2]uint16{31000, 32767},
+			nextHdr:   slayers.L4UDP,
+			l4: func(*testing.T) []gopacket.SerializableLayer {
+				return []gopacket.SerializableLayer{
+					&slayers.UDP{SrcPort: 40000, DstPort: 40000},
+				}
+			},
+			expectedPort: topology.EndhostPort,
+		},
+		"udp without range": {
+			nextHdr: slayers.L4UDP,
+			l4: func(*testing.T) []gopacket.SerializableLayer {
+				return []gopacket.SerializableLayer{
+					&slayers.UDP{SrcPort: 40000, DstPort: 31042},
+				}
+			},
+			expectedPort: topology.EndhostPort,
+		},
+		"scmp echo reply": {
+			portRange: [2]uint16{31000, 32767},
+			nextHdr:   slayers.L4SCMP,
+			l4: func(*testing.T) []gopacket.SerializableLayer {
+				return []gopacket.SerializableLayer{
+					&slayers.SCMP{
+						TypeCode: slayers.CreateSCMPTypeCode(slayers.SCMPTypeEchoReply, 0),
+					},
+					&slayers.SCMPEcho{Identifier: 31042, SeqNumber: 1},
+				}
+			},
+			expectedPort: 31042,
+		},
+		"scmp error quoting udp": {
+			portRange: [2]uint16{31000, 32767},
+			nextHdr:   slayers.L4SCMP,
+			l4: func(t *testing.T) []gopacket.SerializableLayer {
+				return []gopacket.SerializableLayer{
+					&slayers.SCMP{
+						TypeCode: slayers.CreateSCMPTypeCode(
+							slayers.SCMPTypeDestinationUnreachable,
+							slayers.SCMPCodeNoRoute),
+					},
+					&slayers.SCMPDestinationUnreachable{},
+					gopacket.Payload(quote(t, 31042)),
+				}
+			},
+			expectedPort: 31042,
+		},
+		"scmp error with truncated quote": {
+			portRange: [2]uint16{31000, 32767},
+			nextHdr:   slayers.L4SCMP,
+			l4: func(t *testing.T) []gopacket.SerializableLayer {
+				return []gopacket.SerializableLayer{
+					&slayers.SCMP{
+						TypeCode: slayers.CreateSCMPTypeCode(
+							slayers.SCMPTypeDestinationUnreachable,
+							slayers.SCMPCodeNoRoute),
+					},
+					&slayers.SCMPDestinationUnreachable{},
+					gopacket.Payload(quote(t, 31042)[:slayers.CmnHdrLen]),
+				}
+			},
+			expectedPort: topology.EndhostPort,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			dp := router.NewDP(nil, nil, mock_router.NewMockBatchConn(ctrl), nil,
+				nil, xtest.MustParseIA("1-ff00:0:110"), nil, key)
+			if tc.portRange[0] != 0 {
+				require.NoError(t, dp.SetPortRange(tc.portRange[0], tc.portRange[1]))
+			}
+
+			spkt, dpath := prepBaseMsg(now)
+			spkt.DstIA = xtest.MustParseIA("1-ff00:0:110")
+			spkt.NextHdr = tc.nextHdr
+			require.NoError(t, spkt.SetDstAddr(dst))
+			require.NoError(t, spkt.SetSrcAddr(src))
+			dpath.HopFields = []path.HopField{
+				{ConsIngress: 41, ConsEgress: 40},
+				{ConsIngress: 31, ConsEgress: 30},
+				{ConsIngress: 01, ConsEgress: 0},
+			}
+			dpath.Base.PathMeta.CurrHF = 2
+			dpath.HopFields[2].Mac = computeMAC(t, key, dpath.InfoFields[0], dpath.HopFields[2])
+			spkt.Path = dpath
+			buffer := gopacket.NewSerializeBuffer()
+			err := gopacket.SerializeLayers(buffer, gopacket.SerializeOptions{FixLengths: true},
+				append([]gopacket.SerializableLayer{spkt}, tc.l4(t)...)...)
+			require.NoError(t, err)
+			msg := &ipv4.Message{Buffers: [][]byte{buffer.Bytes()}}
+
+			result, err := dp.ProcessPkt(1, msg)
+			require.NoError(t, err)
+			assert.Equal(t, &net.UDPAddr{IP: dst.IP, Port: tc.expectedPort}, result.OutAddr)
+		})
+	}
+}
+
+func TestProcessPktPacketTooBig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key := []byte("testkey_xxxxxxxx")
+	now := time.Now()
+
+	// outbound returns a packet from the local AS that leaves on interface 1.
+	outbound := func(t *testing.T) *ipv4.Message {
+		spkt, dpath := prepBaseMsg(now)
+		spkt.SrcIA = xtest.MustParseIA("1-ff00:0:110")
+		require.NoError(t, spkt.SetSrcAddr(&net.IPAddr{IP: net.ParseIP("10.0.200.200").To4()}))
+		require.NoError(t, spkt.SetDstAddr(&net.IPAddr{IP: net.ParseIP("10.0.100.100").To4()}))
+		dpath.HopFields = []path.HopField{
+			{ConsIngress: 0, ConsEgress: 1},
+			{ConsIngress: 31, ConsEgress: 30},
+			{ConsIngress: 41, ConsEgress: 40},
+		}
+		dpath.Base.PathMeta.CurrHF = 0
+		dpath.HopFields[0].Mac = computeMAC(t, key, dpath.InfoFields[0], dpath.HopFields[0])
+		return toMsg(t, spkt, dpath)
+	}
+	// inbound returns a packet for the local AS that arrives on interface 1.
+	inbound := func(t *testing.T) *ipv4.Message {
+		spkt, dpath := prepBaseMsg(now)
+		spkt.DstIA = xtest.MustParseIA("1-ff00:0:110")
+		require.NoError(t, spkt.SetSrcAddr(&net.IPAddr{IP: net.ParseIP("10.0.200.200").To4()}))
+		require.NoError(t, spkt.SetDstAddr(&net.IPAddr{IP: net.ParseIP("10.0.100.100").To4()}))
+		dpath.HopFields = []path.HopField{
+			{ConsIngress: 41, ConsEgress: 40},
+			{ConsIngress: 31, ConsEgress: 30},
+			{ConsIngress: 1, ConsEgress: 0},
+		}
+		dpath.Base.PathMeta.CurrHF = 2
+		dpath.HopFields[2].Mac = computeMAC(t, key, dpath.InfoFields[0], dpath.HopFields[2])
+		return toMsg(t, spkt, dpath)
+	}
+
+	testCases := map[string]struct {
+		msg          func(t *testing.T) *ipv4.Message
+		srcInterface uint16
+		linkMTU      int
+		internalMTU  int
+		expectedMTU  uint16
+	}{
+		"outbound fits": {
+			msg:     outbound,
+			linkMTU: 1472,
+		},
+		"outbound too big": {
+			msg:         outbound,
+			linkMTU:     100,
+			expectedMTU: 100,
+		},
+		"outbound ignores internal MTU": {
+			msg:         outbound,
+			internalMTU: 100,
+		},
+		"inbound fits": {
+			msg:          inbound,
+			srcInterface: 1,
+			internalMTU:  1472,
+		},
+		"inbound too big": {
+			msg:          inbound,
+			srcInterface: 1,
+			linkMTU:      1472,
+			internalMTU:  100,
+			expectedMTU:  100,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			dp := router.NewDP(
+				map[uint16]router.BatchConn{
+					uint16(1): mock_router.NewMockBatchConn(ctrl),
+				},
+				map[uint16]topology.LinkType{
+					1: topology.Child,
+				},
+				nil, nil, nil, xtest.MustParseIA("1-ff00:0:110"), nil, key)
+			// The internal IP is the source address of SCMP messages.
+			require.NoError(t, dp.AddInternalInterface(mock_router.NewMockBatchConn(ctrl),
+				net.ParseIP("10.0.200.100")))
+			if tc.linkMTU != 0 {
+				require.NoError(t, dp.AddLinkMTU(1, tc.linkMTU))
+			}
+			if tc.internalMTU != 0 {
+				require.NoError(t, dp.SetInternalMTU(tc.internalMTU))
+			}
+
+			result, err := dp.ProcessPkt(tc.srcInterface, tc.msg(t))
+			if tc.expectedMTU == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.NotNil(t, result.OutPkt)
+			pkt := gopacket.NewPacket(result.OutPkt, slayers.LayerTypeSCION, gopacket.Default)
+			scmp, ok := pkt.Layer(slayers.LayerTypeSCMP).(*slayers.SCMP)
+			require.True(t, ok)
+			assert.Equal(t, slayers.SCMPTypePacketTooBig, scmp.TypeCode.Type())
+			ptb, ok := pkt.Layer(slayers.LayerTypeSCMPPacketTooBig).(*slayers.SCMPPacketTooBig)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedMTU, ptb.MTU)
+		})
+	}
+}
+
 func toMsg(t *testing.T, spkt *slayers.SCION, dpath path.Path) *ipv4.Message {
 	t.Helper()
 	ret := &ipv4.Message{}
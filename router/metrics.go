@@ -28,6 +28,7 @@ type Metrics struct {
 	InputPacketsTotal         *prometheus.CounterVec
 	OutputPacketsTotal        *prometheus.CounterVec
 	DroppedPacketsTotal       *prometheus.CounterVec
+	OversizedPacketsTotal     *prometheus.CounterVec
 	InterfaceUp               *prometheus.GaugeVec
 	BFDInterfaceStateChanges  *prometheus.CounterVec
 	BFDPacketsSent            *prometheus.CounterVec
@@ -82,6 +83,14 @@ func NewMetrics() (m *Metrics) {
 			},
 			[]string{"interface", "isd_as", "neighbor_isd_as"},
 		),
+		OversizedPacketsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "router_oversized_pkts_total",
+				Help: "Total number of packets dropped by the router because they " +
+					"exceeded the MTU of the egress link.",
+			},
+			[]string{"interface", "isd_as", "neighbor_isd_as"},
+		),
 		InterfaceUp: promauto.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "router_interface_up",
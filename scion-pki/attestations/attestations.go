@@ -0,0 +1,34 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestations
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/scionproto/scion/private/app/command"
+)
+
+func Cmd(pather command.Pather) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "prefix-attestation",
+		Aliases: []string{"attestation"},
+		Short:   "Manage prefix attestations for the SCION IP Gateway.",
+	}
+	joined := command.Join(pather, cmd)
+	cmd.AddCommand(
+		newCreateCmd(joined),
+	)
+	return cmd
+}
@@ -0,0 +1,177 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestations
+
+import (
+	"crypto"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"inet.af/netaddr"
+
+	"github.com/scionproto/scion/gateway/attestation"
+	"github.com/scionproto/scion/gateway/control"
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/scrypto/cppki"
+	"github.com/scionproto/scion/private/app/command"
+	"github.com/scionproto/scion/private/app/flag"
+	"github.com/scionproto/scion/private/trust"
+	"github.com/scionproto/scion/scion-pki/key"
+)
+
+func newCreateCmd(pather command.Pather) *cobra.Command {
+	now := time.Now().UTC()
+	var flags struct {
+		cert     string
+		key      string
+		notAfter flag.Time
+	}
+	flags.notAfter = flag.Time{
+		Current: now,
+		Default: "expiration time of the AS certificate",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "create [flags] <prefix>...",
+		Short: "Create a signed prefix attestation",
+		Example: fmt.Sprintf(
+			`  %[1]s create --cert ISD1-ASff00_0_110.pem --key cp-as.key 192.0.2.0/24
+  %[1]s create --cert ISD1-ASff00_0_110.pem --key cp-as.key --not-after 2d \
+    10.0.0.0/16-24 2001:db8::/32`,
+			pather.CommandPath(),
+		),
+		Long: `'create' creates a prefix attestation that authorizes an ISD-AS to announce
+the given IP prefixes to remote SCION IP Gateways, and prints it in PEM format.
+
+The command takes the following positional arguments:
+
+- <prefix> is an IP prefix in CIDR notation, optionally followed by a hyphen
+  and the maximum length of the announced prefixes that are covered by it.
+  For example, 10.0.0.0/16-24 authorizes the announcement of 10.0.0.0/16 and
+  of all more specific prefixes up to length 24.
+
+The attestation is signed with the AS certificate of the attested ISD-AS. The
+\--cert flag specifies the certificate chain of the AS, and the \--key flag its
+private key. Both flags are required.
+
+The attestation is only valid as long as the AS certificate is valid. By
+default, it expires together with the AS certificate. The \--not-after flag can
+either be a timestamp or a relative time offset from the current time, and it
+must not be after the expiration time of the AS certificate.
+`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prefixes := make([]control.AttestedPrefix, 0, len(args))
+			for _, arg := range args {
+				p, err := parsePrefix(arg)
+				if err != nil {
+					return err
+				}
+				prefixes = append(prefixes, p)
+			}
+			cmd.SilenceUsage = true
+
+			chain, err := cppki.ReadPEMCerts(flags.cert)
+			if err != nil {
+				return serrors.WrapStr("reading certificate chain", err, "file", flags.cert)
+			}
+			if err := cppki.ValidateChain(chain); err != nil {
+				return serrors.WrapStr("validating certificate chain", err)
+			}
+			ia, err := cppki.ExtractIA(chain[0].Subject)
+			if err != nil {
+				return serrors.WrapStr("extracting ISD-AS", err)
+			}
+			priv, err := key.LoadPrivateKey(flags.key)
+			if err != nil {
+				return serrors.WrapStr("loading private key", err)
+			}
+			pub, ok := chain[0].PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+			if !ok || !pub.Equal(priv.Public()) {
+				return serrors.New("private key does not match AS certificate")
+			}
+
+			notAfter := flags.notAfter.Time
+			if notAfter.IsZero() {
+				notAfter = chain[0].NotAfter
+			}
+			signer := trust.Signer{
+				PrivateKey:   priv,
+				IA:           ia,
+				Subject:      chain[0].Subject,
+				Chain:        chain,
+				SubjectKeyID: chain[0].SubjectKeyId,
+				Expiration:   chain[0].NotAfter,
+			}
+			raw, err := attestation.Sign(cmd.Context(), control.PrefixAttestation{
+				IA:       ia,
+				Prefixes: prefixes,
+				NotAfter: notAfter,
+			}, signer)
+			if err != nil {
+				return serrors.WrapStr("signing attestation", err)
+			}
+			fmt.Fprint(cmd.OutOrStdout(), string(attestation.EncodePEM([][]byte{raw})))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.cert, "cert", "",
+		"The path to the certificate chain of the attested AS",
+	)
+	cmd.Flags().StringVar(&flags.key, "key", "",
+		"The path to the private key of the AS certificate",
+	)
+	cmd.Flags().Var(&flags.notAfter, "not-after",
+		`The NotAfter time of the attestation. Can either be a timestamp or an offset.
+
+If the value is a timestamp, it is expected to either be an RFC 3339 formatted
+timestamp or a unix timestamp. If the value is a duration, it is used as the
+offset from the current time.`,
+	)
+	cmd.MarkFlagRequired("cert")
+	cmd.MarkFlagRequired("key")
+
+	return cmd
+}
+
+// parsePrefix parses a prefix with an optional maximum length, e.g.,
+// 10.0.0.0/16-24.
+func parsePrefix(s string) (control.AttestedPrefix, error) {
+	rawPrefix, rawMaxLength, hasMaxLength := strings.Cut(s, "-")
+	prefix, err := netaddr.ParseIPPrefix(rawPrefix)
+	if err != nil {
+		return control.AttestedPrefix{}, serrors.WrapStr("parsing prefix", err, "input", s)
+	}
+	if prefix.Masked() != prefix {
+		return control.AttestedPrefix{}, serrors.New("prefix has host bits set", "input", s)
+	}
+	p := control.AttestedPrefix{Prefix: prefix}
+	if !hasMaxLength {
+		return p, nil
+	}
+	maxLength, err := strconv.ParseUint(rawMaxLength, 10, 8)
+	if err != nil {
+		return control.AttestedPrefix{}, serrors.WrapStr("parsing max length", err, "input", s)
+	}
+	if uint8(maxLength) < prefix.Bits() || uint8(maxLength) > prefix.IP().BitLen() {
+		return control.AttestedPrefix{}, serrors.New("invalid max length", "input", s)
+	}
+	p.MaxLength = uint8(maxLength)
+	return p, nil
+}
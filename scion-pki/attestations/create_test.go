@@ -0,0 +1,147 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestations
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"inet.af/netaddr"
+
+	"github.com/scionproto/scion/gateway/attestation"
+	"github.com/scionproto/scion/gateway/control"
+	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/private/app/command"
+	"github.com/scionproto/scion/private/trust"
+	"github.com/scionproto/scion/scion-pki/testcrypto"
+)
+
+func TestCreateCmd(t *testing.T) {
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	cmd := testcrypto.Cmd(command.StringPather(""))
+	cmd.SetArgs([]string{"-t", "testdata/golden.topo", "-o", dir})
+	cmd.SetOutput(&buf)
+	require.NoError(t, cmd.Execute(), buf.String())
+
+	store := &attestation.TRCStore{}
+	_, err := trust.LoadTRCs(context.Background(), filepath.Join(dir, "trcs"), store)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		Args         []string
+		ErrAssertion assert.ErrorAssertionFunc
+	}{
+		"valid": {
+			Args: []string{
+				"--cert", filepath.Join(dir, "certs/ISD1-ASff00_0_111.pem"),
+				"--key", filepath.Join(dir, "ASff00_0_111/crypto/as/cp-as.key"),
+				"--not-after", "1h",
+				"10.0.0.0/16-24", "2001:db8::/32",
+			},
+			ErrAssertion: assert.NoError,
+		},
+		"key mismatch": {
+			Args: []string{
+				"--cert", filepath.Join(dir, "certs/ISD1-ASff00_0_111.pem"),
+				"--key", filepath.Join(dir, "ASff00_0_110/crypto/as/cp-as.key"),
+				"10.0.0.0/16",
+			},
+			ErrAssertion: assert.Error,
+		},
+		"outlives certificate": {
+			Args: []string{
+				"--cert", filepath.Join(dir, "certs/ISD1-ASff00_0_111.pem"),
+				"--key", filepath.Join(dir, "ASff00_0_111/crypto/as/cp-as.key"),
+				"--not-after", "1000d",
+				"10.0.0.0/16",
+			},
+			ErrAssertion: assert.Error,
+		},
+		"invalid prefix": {
+			Args: []string{
+				"--cert", filepath.Join(dir, "certs/ISD1-ASff00_0_111.pem"),
+				"--key", filepath.Join(dir, "ASff00_0_111/crypto/as/cp-as.key"),
+				"10.0.0.0/16-8",
+			},
+			ErrAssertion: assert.Error,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			cmd := newCreateCmd(command.StringPather("prefix-attestation"))
+			cmd.SetArgs(tc.Args)
+			cmd.SetOut(&out)
+			cmd.SetErr(&bytes.Buffer{})
+			err := cmd.Execute()
+			tc.ErrAssertion(t, err)
+			if err != nil {
+				return
+			}
+			raw, err := attestation.DecodePEM(out.Bytes())
+			require.NoError(t, err)
+			require.Len(t, raw, 1)
+			a, err := attestation.Verifier{TRCs: store}.Verify(context.Background(), raw[0])
+			require.NoError(t, err)
+			assert.Equal(t, xtest.MustParseIA("1-ff00:0:111"), a.IA)
+			assert.Equal(t, []control.AttestedPrefix{
+				{Prefix: netaddr.MustParseIPPrefix("10.0.0.0/16"), MaxLength: 24},
+				{Prefix: netaddr.MustParseIPPrefix("2001:db8::/32")},
+			}, a.Prefixes)
+		})
+	}
+}
+
+func TestParsePrefix(t *testing.T) {
+	testCases := map[string]struct {
+		Input        string
+		Expected     control.AttestedPrefix
+		ErrAssertion assert.ErrorAssertionFunc
+	}{
+		"prefix": {
+			Input:        "192.0.2.0/24",
+			Expected:     control.AttestedPrefix{Prefix: netaddr.MustParseIPPrefix("192.0.2.0/24")},
+			ErrAssertion: assert.NoError,
+		},
+		"max length": {
+			Input: "2001:db8::/32-48",
+			Expected: control.AttestedPrefix{
+				Prefix:    netaddr.MustParseIPPrefix("2001:db8::/32"),
+				MaxLength: 48,
+			},
+			ErrAssertion: assert.NoError,
+		},
+		"host bits":            {Input: "192.0.2.1/24", ErrAssertion: assert.Error},
+		"max length too short": {Input: "192.0.2.0/24-16", ErrAssertion: assert.Error},
+		"max length too long":  {Input: "192.0.2.0/24-33", ErrAssertion: assert.Error},
+		"garbage max length":   {Input: "192.0.2.0/24-x", ErrAssertion: assert.Error},
+		"no prefix":            {Input: "192.0.2.0", ErrAssertion: assert.Error},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			p, err := parsePrefix(tc.Input)
+			tc.ErrAssertion(t, err)
+			assert.Equal(t, tc.Expected, p)
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/scionproto/scion/private/app"
+	"github.com/scionproto/scion/scion-pki/attestations"
 	"github.com/scionproto/scion/scion-pki/certs"
 	"github.com/scionproto/scion/scion-pki/key"
 	"github.com/scionproto/scion/scion-pki/testcrypto"
@@ -55,6 +56,7 @@ func main() {
 		key.Cmd(cmd),
 		certs.Cmd(cmd),
 		trcs.Cmd(cmd),
+		attestations.Cmd(cmd),
 		testcrypto.Cmd(cmd),
 		newGendocs(cmd),
 	)
@@ -0,0 +1,207 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bwtest implements a bandwidth test between a client and a server.
+//
+// The test sends a paced train of datagrams in each tested direction. The
+// receiver of a train measures how many datagrams arrived, how many arrived
+// out of order or duplicated, and the time it took to receive them. From
+// these, the goodput and the loss of the direction are computed.
+//
+// The client and the server exchange control messages over the same datagram
+// connection as the trains. Control messages are retransmitted until they are
+// answered, such that the test tolerates loss of individual messages.
+package bwtest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/pkg/private/serrors"
+)
+
+const (
+	// MinPacketSize is the minimum size of a test datagram.
+	MinPacketSize = dataHdrLen
+	// MaxPacketSize is the maximum size of a test datagram.
+	MaxPacketSize = 65000
+	// MaxPackets is the maximum number of datagrams in a train.
+	MaxPackets = 1 << 24
+)
+
+// Parameters describe the train of datagrams that is sent in one direction.
+type Parameters struct {
+	// PacketSize is the size of the payload of each datagram in bytes.
+	PacketSize int
+	// Packets is the number of datagrams in the train. If zero, no datagrams
+	// are sent in this direction.
+	Packets int
+	// Bandwidth is the rate at which the datagrams are sent in bits per
+	// second.
+	Bandwidth uint64
+}
+
+// ParametersFor returns the parameters of a train of datagrams of the given
+// size that are sent at the given bandwidth for the given duration. At least
+// one datagram is sent.
+func ParametersFor(duration time.Duration, packetSize int, bandwidth uint64) Parameters {
+	p := Parameters{PacketSize: packetSize, Bandwidth: bandwidth}
+	if interval := p.Interval(); interval > 0 {
+		p.Packets = int(duration / interval)
+	}
+	if p.Packets < 1 {
+		p.Packets = 1
+	}
+	if p.Packets > MaxPackets {
+		p.Packets = MaxPackets
+	}
+	return p
+}
+
+// Enabled indicates whether datagrams are sent in the direction.
+func (p Parameters) Enabled() bool {
+	return p.Packets > 0
+}
+
+// Interval returns the time between sending two consecutive datagrams.
+func (p Parameters) Interval() time.Duration {
+	if p.Bandwidth == 0 {
+		return 0
+	}
+	return time.Duration(uint64(p.PacketSize) * 8 * uint64(time.Second) / p.Bandwidth)
+}
+
+// Duration returns the time it takes to send the train.
+func (p Parameters) Duration() time.Duration {
+	return time.Duration(p.Packets) * p.Interval()
+}
+
+// Validate checks that the parameters describe a valid train. Disabled
+// directions are always valid.
+func (p Parameters) Validate() error {
+	if !p.Enabled() {
+		return nil
+	}
+	if p.PacketSize < MinPacketSize || p.PacketSize > MaxPacketSize {
+		return serrors.New("invalid packet size", "size", p.PacketSize,
+			"min", MinPacketSize, "max", MaxPacketSize)
+	}
+	if p.Packets > MaxPackets {
+		return serrors.New("too many packets", "packets", p.Packets, "max", MaxPackets)
+	}
+	if p.Bandwidth == 0 {
+		return serrors.New("bandwidth must be positive")
+	}
+	return nil
+}
+
+// Stats are the statistics of one direction of a test.
+type Stats struct {
+	// Sent is the number of datagrams that were sent.
+	Sent int `json:"sent" yaml:"sent"`
+	// Received is the number of distinct datagrams that were received.
+	Received int `json:"received" yaml:"received"`
+	// Reordered is the number of datagrams that arrived after a datagram
+	// that was sent later.
+	Reordered int `json:"reordered" yaml:"reordered"`
+	// Duplicates is the number of datagrams that were received more than
+	// once.
+	Duplicates int `json:"duplicates" yaml:"duplicates"`
+	// Bytes is the number of payload bytes of the distinct datagrams that
+	// were received.
+	Bytes uint64 `json:"bytes" yaml:"bytes"`
+	// Duration is the time from the arrival of the first datagram to the
+	// arrival of the last datagram, extended by one sending interval to
+	// account for the transmission of the first datagram.
+	Duration time.Duration `json:"-" yaml:"-"`
+}
+
+// Loss returns the percentage of sent datagrams that were not received.
+func (s Stats) Loss() float64 {
+	if s.Sent == 0 || s.Received >= s.Sent {
+		return 0
+	}
+	return float64(s.Sent-s.Received) * 100 / float64(s.Sent)
+}
+
+// Goodput returns the rate at which payload was received in bits per second.
+func (s Stats) Goodput() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	return float64(s.Bytes) * 8 / s.Duration.Seconds()
+}
+
+// receiver records the datagrams of a train.
+type receiver struct {
+	mtx      sync.Mutex
+	params   Parameters
+	seen     []bool
+	highest  int
+	first    time.Time
+	last     time.Time
+	stats    Stats
+	complete chan struct{}
+}
+
+func newReceiver(params Parameters) *receiver {
+	return &receiver{
+		params:   params,
+		seen:     make([]bool, params.Packets),
+		highest:  -1,
+		complete: make(chan struct{}),
+	}
+}
+
+// record records the arrival of the datagram with the sequence number seq and
+// the given size.
+func (r *receiver) record(seq int, size int, now time.Time) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if seq < 0 || seq >= len(r.seen) {
+		return
+	}
+	if r.seen[seq] {
+		r.stats.Duplicates++
+		return
+	}
+	r.seen[seq] = true
+	if r.stats.Received == 0 {
+		r.first = now
+	}
+	r.last = now
+	r.stats.Received++
+	r.stats.Bytes += uint64(size)
+	if seq < r.highest {
+		r.stats.Reordered++
+	} else {
+		r.highest = seq
+	}
+	if r.stats.Received == len(r.seen) {
+		close(r.complete)
+	}
+}
+
+// result returns the statistics of the datagrams that were received so far.
+// The number of sent datagrams is not known to the receiver and must be set
+// by the caller.
+func (r *receiver) result() Stats {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	s := r.stats
+	if s.Received > 0 {
+		s.Duration = r.last.Sub(r.first) + r.params.Interval()
+	}
+	return s
+}
@@ -0,0 +1,165 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bwtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParametersFor(t *testing.T) {
+	testCases := map[string]struct {
+		Duration   time.Duration
+		PacketSize int
+		Bandwidth  uint64
+		Expected   Parameters
+		Interval   time.Duration
+	}{
+		"regular": {
+			Duration:   3 * time.Second,
+			PacketSize: 1000,
+			Bandwidth:  1e6,
+			Expected:   Parameters{PacketSize: 1000, Packets: 375, Bandwidth: 1e6},
+			Interval:   8 * time.Millisecond,
+		},
+		"at least one packet": {
+			Duration:   time.Millisecond,
+			PacketSize: 1000,
+			Bandwidth:  1000,
+			Expected:   Parameters{PacketSize: 1000, Packets: 1, Bandwidth: 1000},
+			Interval:   8 * time.Second,
+		},
+		"capped packets": {
+			Duration:   time.Hour,
+			PacketSize: MinPacketSize,
+			Bandwidth:  1e9,
+			Expected: Parameters{
+				PacketSize: MinPacketSize,
+				Packets:    MaxPackets,
+				Bandwidth:  1e9,
+			},
+			Interval: MinPacketSize * 8 * time.Nanosecond,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			p := ParametersFor(tc.Duration, tc.PacketSize, tc.Bandwidth)
+			assert.Equal(t, tc.Expected, p)
+			assert.Equal(t, tc.Interval, p.Interval())
+			assert.NoError(t, p.Validate())
+		})
+	}
+}
+
+func TestParametersValidate(t *testing.T) {
+	testCases := map[string]struct {
+		Params    Parameters
+		Assertion assert.ErrorAssertionFunc
+	}{
+		"disabled": {
+			Params:    Parameters{},
+			Assertion: assert.NoError,
+		},
+		"valid": {
+			Params:    Parameters{PacketSize: 1000, Packets: 10, Bandwidth: 1e6},
+			Assertion: assert.NoError,
+		},
+		"packet too small": {
+			Params:    Parameters{PacketSize: MinPacketSize - 1, Packets: 10, Bandwidth: 1e6},
+			Assertion: assert.Error,
+		},
+		"packet too large": {
+			Params:    Parameters{PacketSize: MaxPacketSize + 1, Packets: 10, Bandwidth: 1e6},
+			Assertion: assert.Error,
+		},
+		"too many packets": {
+			Params:    Parameters{PacketSize: 1000, Packets: MaxPackets + 1, Bandwidth: 1e6},
+			Assertion: assert.Error,
+		},
+		"zero bandwidth": {
+			Params:    Parameters{PacketSize: 1000, Packets: 10},
+			Assertion: assert.Error,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			tc.Assertion(t, tc.Params.Validate())
+		})
+	}
+}
+
+func TestRequestRoundTrip(t *testing.T) {
+	cs := Parameters{PacketSize: 1000, Packets: 375, Bandwidth: 1e6}
+	sc := Parameters{PacketSize: 1400, Packets: 10, Bandwidth: 5e6}
+	cookie := []byte("0123456789abcdef")
+	m, err := decode(encodeRequest(42, cs, sc, cookie))
+	require.NoError(t, err)
+	assert.Equal(t, message{
+		typ:            typeRequest,
+		id:             42,
+		clientToServer: cs,
+		serverToClient: sc,
+		cookie:         cookie,
+	}, m)
+
+	_, err = decode(encodeRequest(42, cs, sc, cookie)[:hdrLen+2*paramsLen])
+	assert.Error(t, err)
+}
+
+func TestReceiver(t *testing.T) {
+	p := Parameters{PacketSize: 1000, Packets: 4, Bandwidth: 8e6}
+	r := newReceiver(p)
+	start := time.Now()
+
+	r.record(0, 1000, start)
+	r.record(2, 1000, start.Add(2*time.Millisecond))
+	r.record(2, 1000, start.Add(3*time.Millisecond))
+	r.record(1, 1000, start.Add(4*time.Millisecond))
+	// Out of range sequence numbers are ignored.
+	r.record(4, 1000, start.Add(5*time.Millisecond))
+	r.record(-1, 1000, start.Add(5*time.Millisecond))
+
+	select {
+	case <-r.complete:
+		t.Fatal("receiver complete before all datagrams arrived")
+	default:
+	}
+	assert.Equal(t, Stats{
+		Received:   3,
+		Reordered:  1,
+		Duplicates: 1,
+		Bytes:      3000,
+		Duration:   5 * time.Millisecond,
+	}, r.result())
+
+	r.record(3, 1000, start.Add(6*time.Millisecond))
+	select {
+	case <-r.complete:
+	default:
+		t.Fatal("receiver not complete after all datagrams arrived")
+	}
+	s := r.result()
+	s.Sent = 5
+	assert.Equal(t, 4, s.Received)
+	assert.InDelta(t, 20.0, s.Loss(), 0.001)
+	assert.InDelta(t, 4000*8/0.007, s.Goodput(), 0.001)
+}
@@ -0,0 +1,257 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bwtest
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/scionproto/scion/pkg/log"
+	"github.com/scionproto/scion/pkg/private/serrors"
+)
+
+// retransmitInterval is the interval at which unanswered control messages are
+// retransmitted.
+const retransmitInterval = 250 * time.Millisecond
+
+// ClientConfig configures a bandwidth test.
+type ClientConfig struct {
+	// Conn is used to exchange datagrams with the server. It is not closed by
+	// the test, but its read deadline is modified.
+	Conn net.PacketConn
+	// Remote is the address of the server. For SCION connections, it contains
+	// the path that is tested.
+	Remote net.Addr
+	// ClientToServer describes the train that is sent to the server.
+	ClientToServer Parameters
+	// ServerToClient describes the train that is sent by the server. If it
+	// is not enabled, only the client to server direction is tested.
+	ServerToClient Parameters
+	// Timeout is the time that is waited for answers to control messages, and
+	// for datagrams that are still in flight after a train was sent.
+	Timeout time.Duration
+
+	// ErrHandler is invoked for every error that does not cause the test to
+	// abort. Execution time must be small, as it is run synchronously.
+	ErrHandler func(err error)
+}
+
+// Result is the result of a bandwidth test. The stats of directions that were
+// not tested are nil.
+type Result struct {
+	ClientToServer *Stats
+	ServerToClient *Stats
+}
+
+// Run runs the bandwidth test. It blocks until both directions have been
+// tested, or the context is canceled.
+func Run(ctx context.Context, cfg ClientConfig) (Result, error) {
+	if !cfg.ClientToServer.Enabled() && !cfg.ServerToClient.Enabled() {
+		return Result{}, serrors.New("no direction enabled")
+	}
+	if err := cfg.ClientToServer.Validate(); err != nil {
+		return Result{}, serrors.WrapStr("validating client to server parameters", err)
+	}
+	if err := cfg.ServerToClient.Validate(); err != nil {
+		return Result{}, serrors.WrapStr("validating server to client parameters", err)
+	}
+	if cfg.Timeout <= 0 {
+		return Result{}, serrors.New("timeout must be positive")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c := &client{
+		cfg:      cfg,
+		id:       rand.Uint64(),
+		recv:     newReceiver(cfg.ServerToClient),
+		controls: make(chan message, 10),
+	}
+	readDone := make(chan struct{})
+	go func() {
+		defer log.HandlePanic()
+		defer close(readDone)
+		c.read(ctx)
+	}()
+	defer func() {
+		// Stop and unblock the reader.
+		cancel()
+		_ = cfg.Conn.SetReadDeadline(time.Now())
+		<-readDone
+	}()
+
+	// The server only accepts the test once the request echoes the cookie it
+	// sent to the address of the client.
+	req := encodeRequest(c.id, cfg.ClientToServer, cfg.ServerToClient, nil)
+	reply, err := c.exchange(ctx, req, typeCookie)
+	if err != nil {
+		return Result{}, serrors.WrapStr("requesting cookie", err)
+	}
+	req = encodeRequest(c.id, cfg.ClientToServer, cfg.ServerToClient, reply.cookie)
+	if _, err := c.exchange(ctx, req, typeAccept); err != nil {
+		return Result{}, serrors.WrapStr("requesting test", err)
+	}
+	accepted := time.Now()
+
+	var csSent int
+	if cfg.ClientToServer.Enabled() {
+		var err error
+		csSent, err = sendTrain(ctx, cfg.Conn, cfg.Remote, c.id, cfg.ClientToServer)
+		if err != nil {
+			return Result{}, serrors.WrapStr("sending datagrams", err, "sent", csSent)
+		}
+		// Wait for the datagrams that are still in flight.
+		select {
+		case <-time.After(cfg.Timeout):
+		case <-ctx.Done():
+			return Result{}, ctx.Err()
+		}
+	}
+	if cfg.ServerToClient.Enabled() {
+		deadline := accepted.Add(cfg.ServerToClient.Duration() + cfg.Timeout)
+		select {
+		case <-c.recv.complete:
+		case <-time.After(time.Until(deadline)):
+		case <-ctx.Done():
+			return Result{}, ctx.Err()
+		}
+	}
+
+	reply, err = c.exchange(ctx, encodeEmpty(typeResultRequest, c.id), typeResult)
+	if err != nil {
+		return Result{}, serrors.WrapStr("requesting result", err)
+	}
+	var res Result
+	if cfg.ClientToServer.Enabled() {
+		cs := reply.stats
+		cs.Sent = csSent
+		res.ClientToServer = &cs
+	}
+	if cfg.ServerToClient.Enabled() {
+		sc := c.recv.result()
+		sc.Sent = reply.scSent
+		res.ServerToClient = &sc
+	}
+	return res, nil
+}
+
+type client struct {
+	cfg      ClientConfig
+	id       uint64
+	recv     *receiver
+	controls chan message
+}
+
+// exchange sends the control message req until a reply of the wanted type is
+// received.
+func (c *client) exchange(ctx context.Context, req []byte, want msgType) (message, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+	retransmit := time.NewTicker(retransmitInterval)
+	defer retransmit.Stop()
+	for {
+		if _, err := c.cfg.Conn.WriteTo(req, c.cfg.Remote); err != nil {
+			return message{}, serrors.WrapStr("sending control message", err)
+		}
+		for waiting := true; waiting; {
+			select {
+			case m := <-c.controls:
+				switch m.typ {
+				case want:
+					return m, nil
+				case typeReject:
+					return message{}, serrors.New("rejected by server", "reason", m.reason)
+				}
+			case <-retransmit.C:
+				waiting = false
+			case <-ctx.Done():
+				return message{}, serrors.WrapStr("waiting for server", ctx.Err())
+			}
+		}
+	}
+}
+
+// read reads datagrams from the connection until the context is canceled.
+// Data datagrams are recorded by the receiver, control messages are passed
+// to the controls channel.
+func (c *client) read(ctx context.Context) {
+	buf := make([]byte, MaxPacketSize)
+	for {
+		n, _, err := c.cfg.Conn.ReadFrom(buf)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			c.handleErr(serrors.WrapStr("reading datagram", err))
+			continue
+		}
+		m, err := decode(buf[:n])
+		if err != nil {
+			c.handleErr(serrors.WrapStr("decoding datagram", err))
+			continue
+		}
+		if m.id != c.id {
+			continue
+		}
+		if m.typ == typeData {
+			c.recv.record(m.seq, n, time.Now())
+			continue
+		}
+		select {
+		case c.controls <- m:
+		default:
+		}
+	}
+}
+
+func (c *client) handleErr(err error) {
+	if c.cfg.ErrHandler != nil {
+		c.cfg.ErrHandler(err)
+	}
+}
+
+// sendTrain sends the train of datagrams described by p to remote. It returns
+// the number of datagrams that were sent.
+func sendTrain(ctx context.Context, conn net.PacketConn, remote net.Addr, id uint64,
+	p Parameters) (int, error) {
+
+	pkt := make([]byte, p.PacketSize)
+	encodeData(pkt, id)
+	interval := p.Interval()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+	start := time.Now()
+	for seq := 0; seq < p.Packets; seq++ {
+		if wait := time.Until(start.Add(time.Duration(seq) * interval)); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return seq, ctx.Err()
+			}
+		} else if err := ctx.Err(); err != nil {
+			return seq, err
+		}
+		setSeq(pkt, seq)
+		if _, err := conn.WriteTo(pkt, remote); err != nil {
+			return seq, err
+		}
+	}
+	return p.Packets, nil
+}
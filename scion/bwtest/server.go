@@ -0,0 +1,321 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bwtest
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/scionproto/scion/pkg/log"
+	"github.com/scionproto/scion/pkg/private/serrors"
+)
+
+const (
+	// DefaultSessionTimeout is the default time after which idle sessions are
+	// removed.
+	DefaultSessionTimeout = 10 * time.Second
+	// DefaultMaxSessions is the default maximum number of concurrent sessions.
+	DefaultMaxSessions = 16
+	// DefaultMaxBandwidth is the default maximum bandwidth in bits per second
+	// that a client may request for a direction.
+	DefaultMaxBandwidth = 100e6
+	// DefaultMaxDuration is the default maximum time it may take to send the
+	// train of a direction.
+	DefaultMaxDuration = 10 * time.Second
+	// DefaultMaxPackets is the default maximum number of datagrams in the
+	// train of a direction.
+	DefaultMaxPackets = 1 << 20
+)
+
+// Server answers bandwidth tests of clients.
+//
+// Before a test is accepted, the server checks that the client receives
+// datagrams at its source address: it answers the first request with a cookie
+// that the client must echo in the request. Until then, and to any other
+// source, the server never replies with more bytes than it received, such that
+// it cannot be used to reflect and amplify traffic towards spoofed addresses.
+type Server struct {
+	// Conn is used to exchange datagrams with the clients. It is not closed by
+	// the server, but its read deadline is modified.
+	Conn net.PacketConn
+	// MaxBandwidth is the maximum bandwidth in bits per second that a client
+	// may request for a direction. If zero, DefaultMaxBandwidth is used.
+	MaxBandwidth uint64
+	// MaxDuration is the maximum time it may take to send the train of a
+	// direction. If zero, DefaultMaxDuration is used.
+	MaxDuration time.Duration
+	// MaxPackets is the maximum number of datagrams in the train of a
+	// direction. It is capped at the package level MaxPackets. If zero,
+	// DefaultMaxPackets is used.
+	MaxPackets int
+	// MaxSessions is the maximum number of concurrent sessions. If zero,
+	// DefaultMaxSessions is used.
+	MaxSessions int
+	// SessionTimeout is the time after which sessions without activity are
+	// removed. If zero, DefaultSessionTimeout is used.
+	SessionTimeout time.Duration
+}
+
+type session struct {
+	// remote is the validated address of the client.
+	remote   string
+	params   message
+	recv     *receiver
+	scSent   int64
+	lastSeen time.Time
+	cancel   context.CancelFunc
+}
+
+// Serve answers bandwidth tests until the context is canceled.
+func (s *Server) Serve(ctx context.Context) error {
+	cookieKey := make([]byte, sha256.Size)
+	if _, err := rand.Read(cookieKey); err != nil {
+		return serrors.WrapStr("generating cookie key", err)
+	}
+	sessions := make(map[uint64]*session)
+	defer func() {
+		for _, sess := range sessions {
+			sess.cancel()
+		}
+	}()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		defer log.HandlePanic()
+		select {
+		case <-ctx.Done():
+			// Unblock the reader.
+			_ = s.Conn.SetReadDeadline(time.Now())
+		case <-stop:
+		}
+	}()
+
+	buf := make([]byte, MaxPacketSize)
+	lastCleanup := time.Now()
+	for {
+		if err := s.Conn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+			return serrors.WrapStr("setting read deadline", err)
+		}
+		n, remote, err := s.Conn.ReadFrom(buf)
+		if ctx.Err() != nil {
+			return nil
+		}
+		now := time.Now()
+		if now.Sub(lastCleanup) > time.Second {
+			s.cleanup(sessions, now)
+			lastCleanup = now
+		}
+		if err != nil {
+			if !serrors.IsTimeout(err) {
+				log.Debug("Reading datagram", "err", err)
+			}
+			continue
+		}
+		m, err := decode(buf[:n])
+		if err != nil {
+			log.Debug("Decoding datagram", "remote", remote, "err", err)
+			continue
+		}
+		// Only messages from the validated address of the client belong to
+		// the session.
+		sess := sessions[m.id]
+		if sess != nil && sess.remote != remote.String() {
+			sess = nil
+		}
+		if sess != nil {
+			sess.lastSeen = now
+		}
+		switch m.typ {
+		case typeRequest:
+			c := cookie(cookieKey, m.id, remote)
+			if !hmac.Equal(m.cookie, c) {
+				// The cookie reply is smaller than the request.
+				s.reply(remote, encodeCookie(m.id, c))
+				continue
+			}
+			if sess == nil {
+				if sessions[m.id] != nil {
+					s.reply(remote, encodeReject(m.id, "session exists"))
+					continue
+				}
+				if sess, err = s.accept(ctx, sessions, m, remote); err != nil {
+					log.Debug("Rejecting test", "remote", remote, "err", err)
+					s.reply(remote, encodeReject(m.id, err.Error()))
+					continue
+				}
+				sessions[m.id] = sess
+			}
+			s.reply(remote, encodeEmpty(typeAccept, m.id))
+		case typeData:
+			if sess != nil {
+				sess.recv.record(m.seq, n, now)
+			}
+		case typeResultRequest:
+			if sess == nil {
+				// The address is not validated, the reason is truncated to the
+				// size of the request.
+				s.reply(remote, capReply(encodeReject(m.id, "unknown session"), n))
+				continue
+			}
+			stats := sess.recv.result()
+			s.reply(remote, encodeResult(m.id, stats, int(atomic.LoadInt64(&sess.scSent))))
+		}
+	}
+}
+
+// cookie computes the cookie that the client with the address remote must
+// echo in the request for the session id.
+func cookie(key []byte, id uint64, remote net.Addr) []byte {
+	mac := hmac.New(sha256.New, key)
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], id)
+	mac.Write(b[:])
+	mac.Write([]byte(remote.String()))
+	return mac.Sum(nil)[:cookieLen]
+}
+
+// capReply truncates the reply b to n bytes.
+func capReply(b []byte, n int) []byte {
+	if len(b) > n {
+		return b[:n]
+	}
+	return b
+}
+
+// accept creates a session for the test request m and starts sending the
+// server to client train.
+func (s *Server) accept(ctx context.Context, sessions map[uint64]*session, m message,
+	remote net.Addr) (*session, error) {
+
+	if len(sessions) >= s.maxSessions() {
+		return nil, serrors.New("too many sessions")
+	}
+	for _, p := range []Parameters{m.clientToServer, m.serverToClient} {
+		if err := s.checkLimits(p); err != nil {
+			return nil, err
+		}
+	}
+	sessCtx, cancel := context.WithCancel(ctx)
+	sess := &session{
+		remote:   remote.String(),
+		params:   m,
+		recv:     newReceiver(m.clientToServer),
+		lastSeen: time.Now(),
+		cancel:   cancel,
+	}
+	log.Debug("Accepted test", "remote", remote,
+		"client_to_server", m.clientToServer, "server_to_client", m.serverToClient)
+	if m.serverToClient.Enabled() {
+		go func() {
+			defer log.HandlePanic()
+			sent, err := sendTrain(sessCtx, s.Conn, remote, m.id, m.serverToClient)
+			atomic.StoreInt64(&sess.scSent, int64(sent))
+			if err != nil && sessCtx.Err() == nil {
+				log.Debug("Sending datagrams", "remote", remote, "err", err)
+			}
+		}()
+	}
+	return sess, nil
+}
+
+// cleanup removes the sessions that are idle and whose trains are complete.
+func (s *Server) cleanup(sessions map[uint64]*session, now time.Time) {
+	for id, sess := range sessions {
+		// The server to client train does not cause any activity.
+		timeout := sess.params.serverToClient.Duration() + s.sessionTimeout()
+		if now.Sub(sess.lastSeen) < timeout {
+			continue
+		}
+		sess.cancel()
+		delete(sessions, id)
+	}
+}
+
+func (s *Server) reply(remote net.Addr, b []byte) {
+	if _, err := s.Conn.WriteTo(b, remote); err != nil {
+		log.Debug("Sending reply", "remote", remote, "err", err)
+	}
+}
+
+// checkLimits checks that the parameters requested by a client are valid and
+// within the limits of the server. This is checked before any state is
+// allocated for the parameters.
+func (s *Server) checkLimits(p Parameters) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if !p.Enabled() {
+		return nil
+	}
+	if limit := s.maxBandwidth(); p.Bandwidth > limit {
+		return serrors.New("bandwidth exceeds limit", "requested", p.Bandwidth, "max", limit)
+	}
+	if limit := s.maxPackets(); p.Packets > limit {
+		return serrors.New("packets exceed limit", "requested", p.Packets, "max", limit)
+	}
+	// The duration is not computed directly, as it can overflow for the
+	// untrusted parameters.
+	limit := s.maxDuration()
+	if interval := p.Interval(); interval > 0 && time.Duration(p.Packets) > limit/interval {
+		return serrors.New("duration exceeds limit", "packets", p.Packets,
+			"interval", interval, "max", limit)
+	}
+	return nil
+}
+
+func (s *Server) maxBandwidth() uint64 {
+	if s.MaxBandwidth == 0 {
+		return DefaultMaxBandwidth
+	}
+	return s.MaxBandwidth
+}
+
+func (s *Server) maxDuration() time.Duration {
+	if s.MaxDuration == 0 {
+		return DefaultMaxDuration
+	}
+	return s.MaxDuration
+}
+
+func (s *Server) maxPackets() int {
+	switch {
+	case s.MaxPackets == 0:
+		return DefaultMaxPackets
+	case s.MaxPackets > MaxPackets:
+		return MaxPackets
+	default:
+		return s.MaxPackets
+	}
+}
+
+func (s *Server) maxSessions() int {
+	if s.MaxSessions == 0 {
+		return DefaultMaxSessions
+	}
+	return s.MaxSessions
+}
+
+func (s *Server) sessionTimeout() time.Duration {
+	if s.SessionTimeout == 0 {
+		return DefaultSessionTimeout
+	}
+	return s.SessionTimeout
+}
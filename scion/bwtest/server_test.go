@@ -0,0 +1,184 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bwtest
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerCheckLimits(t *testing.T) {
+	testCases := map[string]struct {
+		Server    Server
+		Params    Parameters
+		Assertion assert.ErrorAssertionFunc
+	}{
+		"disabled": {
+			Params:    Parameters{},
+			Assertion: assert.NoError,
+		},
+		"within defaults": {
+			Params:    ParametersFor(3*time.Second, 1000, 10e6),
+			Assertion: assert.NoError,
+		},
+		"invalid": {
+			Params:    Parameters{PacketSize: 1000, Packets: 10},
+			Assertion: assert.Error,
+		},
+		"default bandwidth exceeded": {
+			Params:    ParametersFor(time.Second, 1000, DefaultMaxBandwidth+1),
+			Assertion: assert.Error,
+		},
+		"configured bandwidth exceeded": {
+			Server:    Server{MaxBandwidth: 1e6},
+			Params:    ParametersFor(time.Second, 1000, 2e6),
+			Assertion: assert.Error,
+		},
+		"default duration exceeded": {
+			Params:    ParametersFor(DefaultMaxDuration+time.Second, 1000, 1e6),
+			Assertion: assert.Error,
+		},
+		"configured duration exceeded": {
+			Server:    Server{MaxDuration: time.Second},
+			Params:    ParametersFor(2*time.Second, 1000, 1e6),
+			Assertion: assert.Error,
+		},
+		"long interval": {
+			Params:    Parameters{PacketSize: MaxPacketSize, Packets: 1000, Bandwidth: 1},
+			Assertion: assert.Error,
+		},
+		"default packets exceeded": {
+			Server: Server{MaxDuration: time.Hour},
+			Params: Parameters{
+				PacketSize: MinPacketSize,
+				Packets:    DefaultMaxPackets + 1,
+				Bandwidth:  DefaultMaxBandwidth,
+			},
+			Assertion: assert.Error,
+		},
+		"configured packets exceeded": {
+			Server:    Server{MaxPackets: 10},
+			Params:    Parameters{PacketSize: 1000, Packets: 11, Bandwidth: 1e6},
+			Assertion: assert.Error,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			tc.Assertion(t, tc.Server.checkLimits(tc.Params))
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	serverConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer serverConn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	server := &Server{Conn: serverConn, MaxBandwidth: 10e6}
+	serveDone := make(chan error, 1)
+	go func() { serveDone <- server.Serve(ctx) }()
+
+	run := func(t *testing.T, cs, sc Parameters) (Result, error) {
+		clientConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer clientConn.Close()
+		return Run(ctx, ClientConfig{
+			Conn:           clientConn,
+			Remote:         serverConn.LocalAddr(),
+			ClientToServer: cs,
+			ServerToClient: sc,
+			Timeout:        time.Second,
+		})
+	}
+
+	t.Run("bidirectional", func(t *testing.T) {
+		p := Parameters{PacketSize: 1000, Packets: 20, Bandwidth: 1e6}
+		res, err := run(t, p, p)
+		require.NoError(t, err)
+		for _, s := range []*Stats{res.ClientToServer, res.ServerToClient} {
+			require.NotNil(t, s)
+			assert.Equal(t, 20, s.Sent)
+			assert.LessOrEqual(t, s.Received, s.Sent)
+			assert.Positive(t, s.Received)
+		}
+	})
+	t.Run("rejected", func(t *testing.T) {
+		p := Parameters{PacketSize: 1000, Packets: 20, Bandwidth: 20e6}
+		_, err := run(t, Parameters{}, p)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bandwidth exceeds limit")
+	})
+
+	cancel()
+	assert.NoError(t, <-serveDone)
+}
+
+func TestServerCookie(t *testing.T) {
+	serverConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer serverConn.Close()
+	clientConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer clientConn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	server := &Server{Conn: serverConn}
+	serveDone := make(chan error, 1)
+	go func() { serveDone <- server.Serve(ctx) }()
+
+	exchange := func(t *testing.T, req []byte) message {
+		_, err := clientConn.WriteTo(req, serverConn.LocalAddr())
+		require.NoError(t, err)
+		require.NoError(t, clientConn.SetReadDeadline(time.Now().Add(time.Second)))
+		buf := make([]byte, MaxPacketSize)
+		n, _, err := clientConn.ReadFrom(buf)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, n, len(req), "reply larger than request")
+		m, err := decode(buf[:n])
+		require.NoError(t, err)
+		return m
+	}
+	sc := Parameters{PacketSize: 1000, Packets: 20, Bandwidth: 1e6}
+
+	// Requests without a valid cookie are answered with a cookie only.
+	m := exchange(t, encodeRequest(1, Parameters{}, sc, nil))
+	assert.Equal(t, typeCookie, m.typ)
+	c := m.cookie
+	m = exchange(t, encodeRequest(1, Parameters{}, sc, make([]byte, cookieLen)))
+	assert.Equal(t, typeCookie, m.typ)
+	assert.Equal(t, c, m.cookie)
+	m = exchange(t, encodeEmpty(typeResultRequest, 1))
+	assert.Equal(t, typeReject, m.typ)
+	require.NoError(t, clientConn.SetReadDeadline(time.Now().Add(200*time.Millisecond)))
+	_, _, err = clientConn.ReadFrom(make([]byte, MaxPacketSize))
+	assert.Error(t, err, "datagram sent before the cookie was echoed")
+
+	// The test is accepted once the cookie is echoed.
+	m = exchange(t, encodeRequest(1, Parameters{}, sc, c))
+	assert.Equal(t, typeAccept, m.typ)
+
+	cancel()
+	assert.NoError(t, <-serveDone)
+}
@@ -0,0 +1,206 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bwtest
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/scionproto/scion/pkg/private/serrors"
+)
+
+// All messages start with a header that consists of the message type (1 byte)
+// and the session identifier (8 bytes). The encoding of the remainder depends
+// on the type:
+//
+//	request:        client to server parameters, server to client parameters,
+//	                cookie (16 bytes, zero if the client has none yet)
+//	cookie:         cookie (16 bytes)
+//	accept:         -
+//	reject:         reason (UTF-8 string)
+//	data:           sequence number (4 bytes), padding
+//	result request: -
+//	result:         client to server stats, datagrams sent to the client (4 bytes)
+//
+// Parameters are encoded as packet size (4 bytes), number of packets
+// (4 bytes) and bandwidth (8 bytes). Stats are encoded as received, reordered
+// and duplicates (4 bytes each), bytes (8 bytes) and duration in nanoseconds
+// (8 bytes). All integers are encoded in big endian.
+
+type msgType uint8
+
+const (
+	typeRequest msgType = iota + 1
+	typeAccept
+	typeReject
+	typeData
+	typeResultRequest
+	typeResult
+	typeCookie
+)
+
+const (
+	hdrLen     = 1 + 8
+	paramsLen  = 4 + 4 + 8
+	statsLen   = 4 + 4 + 4 + 8 + 8
+	dataHdrLen = hdrLen + 4
+	cookieLen  = 16
+)
+
+type message struct {
+	typ msgType
+	id  uint64
+	// Set for requests.
+	clientToServer Parameters
+	serverToClient Parameters
+	// Set for requests and cookies.
+	cookie []byte
+	// Set for rejects.
+	reason string
+	// Set for data.
+	seq int
+	// Set for results.
+	stats  Stats
+	scSent int
+}
+
+func encodeHdr(b []byte, typ msgType, id uint64) {
+	b[0] = byte(typ)
+	binary.BigEndian.PutUint64(b[1:], id)
+}
+
+func encodeParams(b []byte, p Parameters) {
+	binary.BigEndian.PutUint32(b, uint32(p.PacketSize))
+	binary.BigEndian.PutUint32(b[4:], uint32(p.Packets))
+	binary.BigEndian.PutUint64(b[8:], p.Bandwidth)
+}
+
+func decodeParams(b []byte) Parameters {
+	return Parameters{
+		PacketSize: int(binary.BigEndian.Uint32(b)),
+		Packets:    int(binary.BigEndian.Uint32(b[4:])),
+		Bandwidth:  binary.BigEndian.Uint64(b[8:]),
+	}
+}
+
+func encodeStats(b []byte, s Stats) {
+	binary.BigEndian.PutUint32(b, uint32(s.Received))
+	binary.BigEndian.PutUint32(b[4:], uint32(s.Reordered))
+	binary.BigEndian.PutUint32(b[8:], uint32(s.Duplicates))
+	binary.BigEndian.PutUint64(b[12:], s.Bytes)
+	binary.BigEndian.PutUint64(b[20:], uint64(s.Duration))
+}
+
+func decodeStats(b []byte) Stats {
+	return Stats{
+		Received:   int(binary.BigEndian.Uint32(b)),
+		Reordered:  int(binary.BigEndian.Uint32(b[4:])),
+		Duplicates: int(binary.BigEndian.Uint32(b[8:])),
+		Bytes:      binary.BigEndian.Uint64(b[12:]),
+		Duration:   time.Duration(binary.BigEndian.Uint64(b[20:])),
+	}
+}
+
+func encodeRequest(id uint64, cs, sc Parameters, cookie []byte) []byte {
+	b := make([]byte, hdrLen+2*paramsLen+cookieLen)
+	encodeHdr(b, typeRequest, id)
+	encodeParams(b[hdrLen:], cs)
+	encodeParams(b[hdrLen+paramsLen:], sc)
+	copy(b[hdrLen+2*paramsLen:], cookie)
+	return b
+}
+
+func encodeCookie(id uint64, cookie []byte) []byte {
+	b := make([]byte, hdrLen+cookieLen)
+	encodeHdr(b, typeCookie, id)
+	copy(b[hdrLen:], cookie)
+	return b
+}
+
+func encodeEmpty(typ msgType, id uint64) []byte {
+	b := make([]byte, hdrLen)
+	encodeHdr(b, typ, id)
+	return b
+}
+
+func encodeReject(id uint64, reason string) []byte {
+	b := make([]byte, hdrLen+len(reason))
+	encodeHdr(b, typeReject, id)
+	copy(b[hdrLen:], reason)
+	return b
+}
+
+func encodeResult(id uint64, cs Stats, scSent int) []byte {
+	b := make([]byte, hdrLen+statsLen+4)
+	encodeHdr(b, typeResult, id)
+	encodeStats(b[hdrLen:], cs)
+	binary.BigEndian.PutUint32(b[hdrLen+statsLen:], uint32(scSent))
+	return b
+}
+
+// encodeData initializes the data datagram b. The sequence number is set
+// with setSeq.
+func encodeData(b []byte, id uint64) {
+	encodeHdr(b, typeData, id)
+}
+
+func setSeq(b []byte, seq int) {
+	binary.BigEndian.PutUint32(b[hdrLen:], uint32(seq))
+}
+
+func decode(b []byte) (message, error) {
+	if len(b) < hdrLen {
+		return message{}, serrors.New("message too short", "length", len(b))
+	}
+	m := message{
+		typ: msgType(b[0]),
+		id:  binary.BigEndian.Uint64(b[1:]),
+	}
+	pld := b[hdrLen:]
+	var minLen int
+	switch m.typ {
+	case typeRequest:
+		minLen = 2*paramsLen + cookieLen
+	case typeCookie:
+		minLen = cookieLen
+	case typeData:
+		minLen = 4
+	case typeResult:
+		minLen = statsLen + 4
+	case typeAccept, typeReject, typeResultRequest:
+	default:
+		return message{}, serrors.New("unknown message type", "type", m.typ)
+	}
+	if len(pld) < minLen {
+		return message{}, serrors.New("message too short", "type", m.typ,
+			"length", len(b))
+	}
+	switch m.typ {
+	case typeRequest:
+		m.clientToServer = decodeParams(pld)
+		m.serverToClient = decodeParams(pld[paramsLen:])
+		m.cookie = append([]byte(nil), pld[2*paramsLen:2*paramsLen+cookieLen]...)
+	case typeCookie:
+		m.cookie = append([]byte(nil), pld[:cookieLen]...)
+	case typeReject:
+		m.reason = string(pld)
+	case typeData:
+		m.seq = int(binary.BigEndian.Uint32(pld))
+	case typeResult:
+		m.stats = decodeStats(pld)
+		m.scSent = int(binary.BigEndian.Uint32(pld[statsLen:]))
+	}
+	return m, nil
+}
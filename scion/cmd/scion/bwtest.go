@@ -0,0 +1,490 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/daemon"
+	"github.com/scionproto/scion/pkg/log"
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/snet"
+	"github.com/scionproto/scion/pkg/snet/addrutil"
+	"github.com/scionproto/scion/pkg/sock/reliable"
+	"github.com/scionproto/scion/private/app"
+	"github.com/scionproto/scion/private/app/flag"
+	"github.com/scionproto/scion/private/app/path"
+	"github.com/scionproto/scion/private/path/pathpol"
+	"github.com/scionproto/scion/scion/bwtest"
+)
+
+// BwtestResult is the result of a bandwidth test.
+type BwtestResult struct {
+	Path           Path         `json:"path" yaml:"path"`
+	ClientToServer *BwtestStats `json:"client_to_server,omitempty" yaml:"client_to_server,omitempty"`
+	ServerToClient *BwtestStats `json:"server_to_client,omitempty" yaml:"server_to_client,omitempty"`
+}
+
+// BwtestStats is the result of one direction of a bandwidth test.
+type BwtestStats struct {
+	PacketSize   int    `json:"packet_size" yaml:"packet_size"`
+	BandwidthBps uint64 `json:"attempted_bandwidth_bps" yaml:"attempted_bandwidth_bps"`
+	bwtest.Stats `yaml:",inline"`
+	Loss         float64        `json:"packet_loss" yaml:"packet_loss"`
+	GoodputBps   float64        `json:"goodput_bps" yaml:"goodput_bps"`
+	Time         durationMillis `json:"time" yaml:"time"`
+}
+
+func newBwtest(pather CommandPather) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "bwtest",
+		Short: "Measure the bandwidth of a SCION path",
+		Long: `'bwtest' measures the bandwidth of a SCION path.
+
+The client sends a paced train of UDP datagrams over the selected path to a
+bandwidth test server. Optionally, the server sends a train back to the client
+at the same time. The receiver of a train reports the goodput, the loss and the
+number of reordered and duplicated datagrams.`,
+	}
+	cmd.AddCommand(
+		newBwtestClient(cmd),
+		newBwtestServer(cmd),
+	)
+	return cmd
+}
+
+func newBwtestClient(pather CommandPather) *cobra.Command {
+	var envFlags flag.SCIONEnvironment
+	var flags struct {
+		bandwidth     string
+		bidirectional bool
+		duration      time.Duration
+		format        string
+		healthyOnly   bool
+		interactive   bool
+		logLevel      string
+		noColor       bool
+		pktSize       uint
+		refresh       bool
+		sequence      string
+		policy        string
+		timeout       time.Duration
+	}
+
+	var cmd = &cobra.Command{
+		Use:   "client [flags] <server>",
+		Short: "Run a bandwidth test against a bandwidth test server",
+		Example: fmt.Sprintf(`  %[1]s client 1-ff00:0:110,10.0.0.1:30100
+  %[1]s client 1-ff00:0:110,10.0.0.1:30100 --bandwidth 50Mbps --duration 10s
+  %[1]s client 1-ff00:0:110,10.0.0.1:30100 --bidirectional --format json`,
+			pather.CommandPath()),
+		Long: fmt.Sprintf(`'client' runs a bandwidth test against a bandwidth test server.
+
+The client sends datagrams of the size given by \--packet-size at the rate given
+by \--bandwidth for the time given by \--duration. The bandwidth is given in bits
+per second, optionally with one of the suffixes kbps, Mbps or Gbps.
+
+When the \--bidirectional option is set, the server sends datagrams with the same
+parameters back to the client at the same time.
+
+If no datagram is received in a tested direction, the command exits with code 1.
+On other errors, it exits with code 2.
+
+%s
+%s`, app.SequenceHelp, app.PolicyHelp),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			remote, err := snet.ParseUDPAddr(args[0])
+			if err != nil {
+				return serrors.WrapStr("parsing remote", err)
+			}
+			if remote.Host.Port == 0 {
+				return serrors.New("server port required")
+			}
+			bandwidth, err := parseBandwidth(flags.bandwidth)
+			if err != nil {
+				return serrors.WrapStr("parsing bandwidth", err)
+			}
+			params := bwtest.ParametersFor(flags.duration, int(flags.pktSize), bandwidth)
+			if err := params.Validate(); err != nil {
+				return err
+			}
+			if err := app.SetupLog(flags.logLevel); err != nil {
+				return serrors.WrapStr("setting up logging", err)
+			}
+			printf, err := getPrintf(flags.format, cmd.OutOrStdout())
+			if err != nil {
+				return serrors.WrapStr("get formatting", err)
+			}
+
+			cmd.SilenceUsage = true
+
+			policy, err := loadPolicy(flags.policy)
+			if err != nil {
+				return err
+			}
+
+			if err := envFlags.LoadExternalVars(); err != nil {
+				return err
+			}
+			daemonAddr := envFlags.Daemon()
+			dispatcher := envFlags.Dispatcher()
+			localIP := envFlags.Local().IPAddr().IP
+			log.Debug("Resolved SCION environment flags",
+				"daemon", daemonAddr,
+				"dispatcher", dispatcher,
+				"local", localIP,
+			)
+
+			ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+			defer cancelF()
+			sd, err := daemon.NewService(daemonAddr).Connect(ctx)
+			if err != nil {
+				return serrors.WrapStr("connecting to SCION Daemon", err)
+			}
+			defer sd.Close()
+
+			info, err := app.QueryASInfo(context.Background(), sd)
+			if err != nil {
+				return err
+			}
+
+			opts := []path.Option{
+				path.WithInteractive(flags.interactive),
+				path.WithRefresh(flags.refresh),
+				path.WithSequence(flags.sequence),
+				path.WithPolicy(policy),
+				path.WithColorScheme(path.DefaultColorScheme(flags.noColor)),
+			}
+			if flags.healthyOnly {
+				opts = append(opts, path.WithProbing(&path.ProbeConfig{
+					LocalIA:    info.IA,
+					LocalIP:    localIP,
+					Dispatcher: dispatcher,
+				}))
+			}
+			path, err := path.Choose(context.Background(), sd, remote.IA, opts...)
+			if err != nil {
+				return err
+			}
+			remote.Path = path.Dataplane()
+			remote.NextHop = path.UnderlayNextHop()
+
+			// Resolve local IP based on underlay next hop
+			if localIP == nil {
+				target := remote.Host.IP
+				if remote.NextHop != nil {
+					target = remote.NextHop.IP
+				}
+				if localIP, err = addrutil.ResolveLocal(target); err != nil {
+					return serrors.WrapStr("resolving local address", err)
+				}
+				printf("Resolved local address:\n  %s\n", localIP)
+			}
+			printf("Using path:\n  %s\n\n", path)
+
+			network := &snet.SCIONNetwork{
+				LocalIA: info.IA,
+				Dispatcher: &snet.DefaultPacketDispatcherService{
+					Dispatcher: reliable.NewDispatcher(dispatcher),
+					SCMPHandler: snet.DefaultSCMPHandler{
+						RevocationHandler: daemon.RevHandler{Connector: sd},
+					},
+				},
+			}
+			conn, err := network.Listen(context.Background(), "udp",
+				&net.UDPAddr{IP: localIP}, addr.SvcNone)
+			if err != nil {
+				return serrors.WrapStr("listening", err)
+			}
+			defer conn.Close()
+
+			cfg := bwtest.ClientConfig{
+				Conn:           conn,
+				Remote:         remote,
+				ClientToServer: params,
+				Timeout:        flags.timeout,
+				ErrHandler: func(err error) {
+					fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
+				},
+			}
+			if flags.bidirectional {
+				cfg.ServerToClient = params
+			}
+			printf("BWTEST %s pld=%dB packets=%d bandwidth=%s duration=%s\n",
+				remote, params.PacketSize, params.Packets, fmtBandwidth(float64(bandwidth)),
+				params.Duration())
+
+			ctx = app.WithSignal(context.Background(), os.Interrupt, syscall.SIGTERM)
+			r, err := bwtest.Run(ctx, cfg)
+			if err != nil {
+				return err
+			}
+
+			seq, err := pathpol.GetSequence(path)
+			if err != nil {
+				return serrors.New("get sequence from used path")
+			}
+			res := BwtestResult{
+				Path: Path{
+					Fingerprint: snet.Fingerprint(path).String(),
+					Hops:        getHops(path),
+					Sequence:    seq,
+					LocalIP:     localIP,
+					NextHop:     path.UnderlayNextHop().String(),
+				},
+				ClientToServer: newBwtestStats(r.ClientToServer, params),
+				ServerToClient: newBwtestStats(r.ServerToClient, params),
+			}
+
+			switch flags.format {
+			case "human":
+				printBwtestStats(printf, "client to server", res.ClientToServer)
+				printBwtestStats(printf, "server to client", res.ServerToClient)
+			case "json":
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				enc.SetEscapeHTML(false)
+				if err := enc.Encode(res); err != nil {
+					return err
+				}
+			case "yaml":
+				enc := yaml.NewEncoder(os.Stdout)
+				if err := enc.Encode(res); err != nil {
+					return err
+				}
+			}
+			for _, d := range []*BwtestStats{res.ClientToServer, res.ServerToClient} {
+				if d != nil && d.Received == 0 {
+					return app.WithExitCode(serrors.New("no datagram received"), 1)
+				}
+			}
+			return nil
+		},
+	}
+
+	envFlags.Register(cmd.Flags())
+	cmd.Flags().StringVar(&flags.bandwidth, "bandwidth", "1Mbps",
+		"bandwidth at which datagrams are sent")
+	cmd.Flags().BoolVar(&flags.bidirectional, "bidirectional", false,
+		"let the server send datagrams to the client at the same time")
+	cmd.Flags().DurationVar(&flags.duration, "duration", 3*time.Second,
+		"time during which datagrams are sent")
+	cmd.Flags().UintVarP(&flags.pktSize, "packet-size", "s", 1000,
+		`number of bytes in the UDP payload of each datagram; the total size of the
+packet is larger due to the SCION header.`,
+	)
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", time.Second,
+		"time to wait for answers of the server and for datagrams in flight")
+	cmd.Flags().BoolVarP(&flags.interactive, "interactive", "i", false, "interactive mode")
+	cmd.Flags().BoolVar(&flags.noColor, "no-color", false, "disable colored output")
+	cmd.Flags().StringVar(&flags.sequence, "sequence", "", app.SequenceUsage)
+	cmd.Flags().StringVar(&flags.policy, "policy", "", app.PolicyUsage)
+	cmd.Flags().BoolVar(&flags.healthyOnly, "healthy-only", false, "only use healthy paths")
+	cmd.Flags().BoolVar(&flags.refresh, "refresh", false, "set refresh flag for path request")
+	cmd.Flags().StringVar(&flags.logLevel, "log.level", "", app.LogLevelUsage)
+	cmd.Flags().StringVar(&flags.format, "format", "human",
+		"Specify the output format (human|json|yaml)")
+	return cmd
+}
+
+func newBwtestServer(pather CommandPather) *cobra.Command {
+	var envFlags flag.SCIONEnvironment
+	var flags struct {
+		logLevel     string
+		maxBandwidth string
+		maxDuration  time.Duration
+		maxPackets   uint
+		port         uint16
+	}
+
+	var cmd = &cobra.Command{
+		Use:   "server [flags]",
+		Short: "Run a bandwidth test server",
+		Example: fmt.Sprintf(`  %[1]s server
+  %[1]s server --port 30100 --max-bandwidth 100Mbps`, pather.CommandPath()),
+		Long: `'server' runs a bandwidth test server that answers the tests of clients
+until it is interrupted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			maxBandwidth, err := parseBandwidth(flags.maxBandwidth)
+			if err != nil {
+				return serrors.WrapStr("parsing maximum bandwidth", err)
+			}
+			if flags.maxDuration <= 0 {
+				return serrors.New("maximum duration must be positive")
+			}
+			if flags.maxPackets == 0 || flags.maxPackets > bwtest.MaxPackets {
+				return serrors.New("invalid maximum number of packets",
+					"max_packets", flags.maxPackets, "limit", bwtest.MaxPackets)
+			}
+			if err := app.SetupLog(flags.logLevel); err != nil {
+				return serrors.WrapStr("setting up logging", err)
+			}
+
+			cmd.SilenceUsage = true
+
+			if err := envFlags.LoadExternalVars(); err != nil {
+				return err
+			}
+			daemonAddr := envFlags.Daemon()
+			dispatcher := envFlags.Dispatcher()
+			localIP := envFlags.Local().IPAddr().IP
+			log.Debug("Resolved SCION environment flags",
+				"daemon", daemonAddr,
+				"dispatcher", dispatcher,
+				"local", localIP,
+			)
+
+			ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+			defer cancelF()
+			sd, err := daemon.NewService(daemonAddr).Connect(ctx)
+			if err != nil {
+				return serrors.WrapStr("connecting to SCION Daemon", err)
+			}
+			defer sd.Close()
+
+			info, err := app.QueryASInfo(context.Background(), sd)
+			if err != nil {
+				return err
+			}
+			if localIP == nil {
+				if localIP, err = addrutil.DefaultLocalIP(context.Background(), sd); err != nil {
+					return serrors.WrapStr("determining local address", err)
+				}
+			}
+
+			network := &snet.SCIONNetwork{
+				LocalIA: info.IA,
+				Dispatcher: &snet.DefaultPacketDispatcherService{
+					Dispatcher: reliable.NewDispatcher(dispatcher),
+					SCMPHandler: snet.DefaultSCMPHandler{
+						RevocationHandler: daemon.RevHandler{Connector: sd},
+					},
+				},
+			}
+			conn, err := network.Listen(context.Background(), "udp",
+				&net.UDPAddr{IP: localIP, Port: int(flags.port)}, addr.SvcNone)
+			if err != nil {
+				return serrors.WrapStr("listening", err)
+			}
+			defer conn.Close()
+			fmt.Fprintf(cmd.OutOrStdout(), "Listening on %s,%s\n", info.IA, conn.LocalAddr())
+
+			server := bwtest.Server{
+				Conn:         conn,
+				MaxBandwidth: maxBandwidth,
+				MaxDuration:  flags.maxDuration,
+				MaxPackets:   int(flags.maxPackets),
+			}
+			ctx = app.WithSignal(context.Background(), os.Interrupt, syscall.SIGTERM)
+			return server.Serve(ctx)
+		},
+	}
+
+	envFlags.Register(cmd.Flags())
+	cmd.Flags().Uint16Var(&flags.port, "port", 30100, "port to listen on")
+	cmd.Flags().StringVar(&flags.maxBandwidth, "max-bandwidth", "100Mbps",
+		"maximum bandwidth that clients may request per direction")
+	cmd.Flags().DurationVar(&flags.maxDuration, "max-duration", bwtest.DefaultMaxDuration,
+		"maximum duration that clients may request per direction")
+	cmd.Flags().UintVar(&flags.maxPackets, "max-packets", bwtest.DefaultMaxPackets,
+		"maximum number of packets that clients may request per direction")
+	cmd.Flags().StringVar(&flags.logLevel, "log.level", "", app.LogLevelUsage)
+	return cmd
+}
+
+func newBwtestStats(s *bwtest.Stats, p bwtest.Parameters) *BwtestStats {
+	if s == nil {
+		return nil
+	}
+	return &BwtestStats{
+		PacketSize:   p.PacketSize,
+		BandwidthBps: p.Bandwidth,
+		Stats:        *s,
+		Loss:         s.Loss(),
+		GoodputBps:   s.Goodput(),
+		Time:         durationMillis(s.Duration),
+	}
+}
+
+func printBwtestStats(printf func(format string, ctx ...interface{}), name string,
+	d *BwtestStats) {
+
+	if d == nil {
+		return
+	}
+	printf("\n--- %s statistics ---\n", name)
+	printf("%d packets transmitted, %d received, %.2f%% packet loss, "+
+		"%d reordered, %d duplicates\n",
+		d.Sent, d.Received, d.Loss, d.Reordered, d.Duplicates)
+	printf("goodput %s (attempted %s), time %v\n",
+		fmtBandwidth(d.GoodputBps), fmtBandwidth(float64(d.BandwidthBps)), d.Time)
+}
+
+var bandwidthUnits = []struct {
+	suffix string
+	factor float64
+}{
+	{"Gbps", 1e9},
+	{"Mbps", 1e6},
+	{"kbps", 1e3},
+	{"bps", 1},
+}
+
+// parseBandwidth parses a bandwidth in bits per second, optionally followed by
+// one of the units bps, kbps, Mbps or Gbps.
+func parseBandwidth(s string) (uint64, error) {
+	factor := 1.0
+	num := s
+	for _, u := range bandwidthUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			num, factor = strings.TrimSuffix(s, u.suffix), u.factor
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil {
+		return 0, serrors.New("invalid bandwidth", "input", s)
+	}
+	bw := v * factor
+	if bw < 1 {
+		return 0, serrors.New("bandwidth must be positive", "input", s)
+	}
+	return uint64(bw), nil
+}
+
+// fmtBandwidth formats a bandwidth in bits per second with the largest unit
+// that keeps the value at or above one.
+func fmtBandwidth(bps float64) string {
+	for _, u := range bandwidthUnits {
+		if bps >= u.factor {
+			return fmt.Sprintf("%.2f %s", bps/u.factor, u.suffix)
+		}
+	}
+	return fmt.Sprintf("%.2f bps", bps)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/scionproto/scion/pkg/private/common"
 	"github.com/scionproto/scion/pkg/private/serrors"
 	"github.com/scionproto/scion/pkg/snet"
+	"github.com/scionproto/scion/private/path/pathpol"
 )
 
 // Path defines the base model for the `ping` and `traceroute` result path
@@ -61,6 +62,15 @@ func getHops(path snet.Path) []Hop {
 	return hops
 }
 
+// loadPolicy loads the path policy from the file. If the file is empty, a nil
+// policy is returned, which does not filter any paths.
+func loadPolicy(file string) (*pathpol.Policy, error) {
+	if file == "" {
+		return nil, nil
+	}
+	return pathpol.LoadPolicyFromFile(file)
+}
+
 // getPrintf returns a printf function for the "human" formatting flag and an empty one for machine
 // readable format flags
 func getPrintf(output string, writer io.Writer) (func(format string, ctx ...interface{}), error) {
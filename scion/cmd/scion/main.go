@@ -49,7 +49,10 @@ func main() {
 	}
 	cmd.AddCommand(
 		command.NewVersion(cmd),
+		newBwtest(cmd),
+		newNetcat(cmd),
 		newPing(cmd),
+		newPolicy(cmd),
 		newShowpaths(cmd),
 		newTraceroute(cmd),
 		newAddress(cmd),
@@ -0,0 +1,437 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/lucas-clemente/quic-go"
+	"github.com/spf13/cobra"
+
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/daemon"
+	"github.com/scionproto/scion/pkg/log"
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/snet"
+	"github.com/scionproto/scion/pkg/snet/addrutil"
+	"github.com/scionproto/scion/pkg/snet/squic"
+	"github.com/scionproto/scion/pkg/sock/reliable"
+	"github.com/scionproto/scion/private/app"
+	"github.com/scionproto/scion/private/app/appnet"
+	"github.com/scionproto/scion/private/app/flag"
+	"github.com/scionproto/scion/private/app/path"
+)
+
+// ncDatagramSize is the maximum number of bytes read from stdin that are sent
+// in a single datagram in UDP mode. It is chosen such that the packets fit
+// into the minimum MTU with most paths.
+const ncDatagramSize = 1024
+
+func newNetcat(pather CommandPather) *cobra.Command {
+	var envFlags flag.SCIONEnvironment
+	var flags struct {
+		healthyOnly bool
+		interactive bool
+		listen      bool
+		logLevel    string
+		noColor     bool
+		quic        bool
+		refresh     bool
+		sequence    string
+		policy      string
+		timeout     time.Duration
+		wait        time.Duration
+	}
+
+	var cmd = &cobra.Command{
+		Use:   "nc [flags] <remote>|<port>",
+		Short: "Read and write data over SCION connections",
+		Example: fmt.Sprintf(`  %[1]s nc --listen 8000
+  %[1]s nc 1-ff00:0:110,10.0.0.1:8000
+  %[1]s nc --listen --quic 8000 > file
+  %[1]s nc --quic 1-ff00:0:110,10.0.0.1:8000 < file`, pather.CommandPath()),
+		Long: fmt.Sprintf(`'nc' reads data from stdin and sends it to a remote SCION host,
+and writes the data received from the remote host to stdout.
+
+By default, the data is sent in UDP datagrams of at most %[1]d bytes. When
+the \--quic option is set, the data is sent over a reliable QUIC stream instead.
+
+When the \--listen option is set, nc listens on the given port for data of remote
+hosts instead of connecting to a remote host. In UDP mode, data read from stdin is
+sent to the host from which the last datagram was received, and nc runs until it is
+interrupted. In QUIC mode, nc accepts a single connection, which is established once
+the connecting side sends data or closes its input, and exits when the connection is
+closed.
+
+When connecting in UDP mode, nc exits after the time given by \--wait once stdin is
+closed. In QUIC mode, nc closes the sending direction of the stream once stdin is
+closed, and exits when the remote host closes the connection.
+
+Status information is written to stderr.
+
+%[2]s
+%[3]s`, ncDatagramSize, app.SequenceHelp, app.PolicyHelp),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var remote *snet.UDPAddr
+			var port uint64
+			var err error
+			if flags.listen {
+				if port, err = strconv.ParseUint(args[0], 10, 16); err != nil {
+					return serrors.WrapStr("parsing port", err)
+				}
+			} else {
+				if remote, err = snet.ParseUDPAddr(args[0]); err != nil {
+					return serrors.WrapStr("parsing remote", err)
+				}
+				if remote.Host.Port == 0 {
+					return serrors.New("remote port required")
+				}
+			}
+			if err := app.SetupLog(flags.logLevel); err != nil {
+				return serrors.WrapStr("setting up logging", err)
+			}
+
+			cmd.SilenceUsage = true
+
+			policy, err := loadPolicy(flags.policy)
+			if err != nil {
+				return err
+			}
+
+			if err := envFlags.LoadExternalVars(); err != nil {
+				return err
+			}
+			daemonAddr := envFlags.Daemon()
+			dispatcher := envFlags.Dispatcher()
+			localIP := envFlags.Local().IPAddr().IP
+			log.Debug("Resolved SCION environment flags",
+				"daemon", daemonAddr,
+				"dispatcher", dispatcher,
+				"local", localIP,
+			)
+
+			ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+			defer cancelF()
+			sd, err := daemon.NewService(daemonAddr).Connect(ctx)
+			if err != nil {
+				return serrors.WrapStr("connecting to SCION Daemon", err)
+			}
+			defer sd.Close()
+
+			info, err := app.QueryASInfo(context.Background(), sd)
+			if err != nil {
+				return err
+			}
+
+			if remote != nil {
+				opts := []path.Option{
+					path.WithInteractive(flags.interactive),
+					path.WithRefresh(flags.refresh),
+					path.WithSequence(flags.sequence),
+					path.WithPolicy(policy),
+					path.WithColorScheme(path.DefaultColorScheme(flags.noColor)),
+				}
+				if flags.healthyOnly {
+					opts = append(opts, path.WithProbing(&path.ProbeConfig{
+						LocalIA:    info.IA,
+						LocalIP:    localIP,
+						Dispatcher: dispatcher,
+					}))
+				}
+				path, err := path.Choose(context.Background(), sd, remote.IA, opts...)
+				if err != nil {
+					return err
+				}
+				remote.Path = path.Dataplane()
+				remote.NextHop = path.UnderlayNextHop()
+				fmt.Fprintf(os.Stderr, "Using path:\n  %s\n", path)
+			}
+			if localIP == nil {
+				if remote != nil {
+					target := remote.Host.IP
+					if remote.NextHop != nil {
+						target = remote.NextHop.IP
+					}
+					localIP, err = addrutil.ResolveLocal(target)
+				} else {
+					localIP, err = addrutil.DefaultLocalIP(context.Background(), sd)
+				}
+				if err != nil {
+					return serrors.WrapStr("resolving local address", err)
+				}
+			}
+
+			network := &snet.SCIONNetwork{
+				LocalIA: info.IA,
+				Dispatcher: &snet.DefaultPacketDispatcherService{
+					Dispatcher: reliable.NewDispatcher(dispatcher),
+					SCMPHandler: snet.DefaultSCMPHandler{
+						RevocationHandler: daemon.RevHandler{Connector: sd},
+					},
+				},
+			}
+			conn, err := network.Listen(context.Background(), "udp",
+				&net.UDPAddr{IP: localIP, Port: int(port)}, addr.SvcNone)
+			if err != nil {
+				return serrors.WrapStr("listening", err)
+			}
+			defer conn.Close()
+			if flags.listen {
+				fmt.Fprintf(os.Stderr, "Listening on %s,%s\n", info.IA, conn.LocalAddr())
+			}
+
+			ctx = app.WithSignal(context.Background(), os.Interrupt, syscall.SIGTERM)
+			switch {
+			case flags.quic && flags.listen:
+				return ncAcceptQUIC(ctx, conn)
+			case flags.quic:
+				return ncDialQUIC(ctx, conn, remote, flags.timeout)
+			case flags.listen:
+				return ncUDP(ctx, conn, nil, flags.wait)
+			default:
+				return ncUDP(ctx, conn, remote, flags.wait)
+			}
+		},
+	}
+
+	envFlags.Register(cmd.Flags())
+	cmd.Flags().BoolVar(&flags.listen, "listen", false,
+		"listen on the given port instead of connecting to a remote host")
+	cmd.Flags().BoolVar(&flags.quic, "quic", false, "send the data over a QUIC stream")
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", 5*time.Second,
+		"timeout for establishing the QUIC connection")
+	cmd.Flags().DurationVar(&flags.wait, "wait", time.Second,
+		"time to wait for datagrams after stdin is closed in UDP mode")
+	cmd.Flags().BoolVarP(&flags.interactive, "interactive", "i", false, "interactive mode")
+	cmd.Flags().BoolVar(&flags.noColor, "no-color", false, "disable colored output")
+	cmd.Flags().StringVar(&flags.sequence, "sequence", "", app.SequenceUsage)
+	cmd.Flags().StringVar(&flags.policy, "policy", "", app.PolicyUsage)
+	cmd.Flags().BoolVar(&flags.healthyOnly, "healthy-only", false, "only use healthy paths")
+	cmd.Flags().BoolVar(&flags.refresh, "refresh", false, "set refresh flag for path request")
+	cmd.Flags().StringVar(&flags.logLevel, "log.level", "", app.LogLevelUsage)
+	return cmd
+}
+
+// ncUDP exchanges datagrams with the remote host. If remote is nil, the
+// datagrams read from stdin are sent to the host from which the last datagram
+// was received, and ncUDP runs until the context is canceled. Otherwise, it
+// returns after the wait time once stdin is closed.
+func ncUDP(ctx context.Context, conn net.PacketConn, remote net.Addr,
+	wait time.Duration) error {
+
+	var mtx sync.Mutex
+	peer := remote
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer log.HandlePanic()
+		buf := make([]byte, 1<<16)
+		var backoff time.Duration
+		for {
+			n, from, err := conn.ReadFrom(buf)
+			if err != nil {
+				select {
+				case <-done:
+					// The connection is closed.
+					return
+				default:
+				}
+				if errors.Is(err, net.ErrClosed) {
+					return
+				}
+				fmt.Fprintf(os.Stderr, "ERROR: reading datagram: %s\n", err)
+				// Back off on consecutive errors, such that a persistent
+				// error does not result in a busy loop.
+				backoff = ncNextBackoff(backoff)
+				select {
+				case <-time.After(backoff):
+				case <-done:
+					return
+				}
+				continue
+			}
+			backoff = 0
+			if remote == nil {
+				mtx.Lock()
+				peer = from
+				mtx.Unlock()
+			}
+			if _, err := os.Stdout.Write(buf[:n]); err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: writing to stdout: %s\n", err)
+			}
+		}
+	}()
+
+	stdinDone := make(chan error, 1)
+	go func() {
+		defer log.HandlePanic()
+		buf := make([]byte, ncDatagramSize)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if n > 0 {
+				mtx.Lock()
+				dst := peer
+				mtx.Unlock()
+				if dst == nil {
+					fmt.Fprintf(os.Stderr, "ERROR: no remote host known, dropping input\n")
+				} else if _, err := conn.WriteTo(buf[:n], dst); err != nil {
+					fmt.Fprintf(os.Stderr, "ERROR: sending datagram: %s\n", err)
+				}
+			}
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				stdinDone <- err
+				return
+			}
+		}
+	}()
+
+	select {
+	case err := <-stdinDone:
+		if err != nil {
+			return serrors.WrapStr("reading stdin", err)
+		}
+	case <-ctx.Done():
+		return nil
+	}
+	if remote == nil {
+		<-ctx.Done()
+		return nil
+	}
+	select {
+	case <-time.After(wait):
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+// ncDialQUIC establishes a QUIC connection to the remote host and exchanges
+// data over a single stream.
+func ncDialQUIC(ctx context.Context, conn net.PacketConn, remote net.Addr,
+	timeout time.Duration) error {
+
+	dialer := squic.ConnDialer{
+		Conn: conn,
+		TLSConfig: &tls.Config{
+			InsecureSkipVerify: true,
+			NextProtos:         []string{"SCION"},
+		},
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	stream, err := dialer.Dial(dialCtx, remote)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	return ncStream(ctx, stream)
+}
+
+// ncAcceptQUIC accepts a single QUIC connection and exchanges data over its
+// stream.
+func ncAcceptQUIC(ctx context.Context, conn net.PacketConn) error {
+	tlsConfig, err := appnet.GenerateTLSConfig()
+	if err != nil {
+		return serrors.WrapStr("generating TLS config", err)
+	}
+	quicListener, err := quic.Listen(conn, tlsConfig, nil)
+	if err != nil {
+		return serrors.WrapStr("listening QUIC/SCION", err)
+	}
+	listener := squic.NewConnListener(quicListener)
+	defer listener.Close()
+	stream, err := listener.AcceptCtx(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return serrors.WrapStr("accepting connection", err)
+	}
+	defer stream.Close()
+	fmt.Fprintf(os.Stderr, "Accepted connection from %s\n", stream.RemoteAddr())
+	return ncStream(ctx, stream)
+}
+
+// ncStream copies stdin to the stream and the stream to stdout. Once stdin is
+// closed, the sending direction of the stream is closed. It returns once both
+// directions are complete, i.e., stdin was sent entirely and the remote host
+// closed the stream, once either direction fails, or once the context is
+// canceled.
+func ncStream(ctx context.Context, stream net.Conn) error {
+	errs := make(chan error, 2)
+	go func() {
+		defer log.HandlePanic()
+		if _, err := io.Copy(os.Stdout, stream); err != nil {
+			errs <- serrors.WrapStr("receiving", err)
+			return
+		}
+		errs <- nil
+	}()
+	go func() {
+		defer log.HandlePanic()
+		if _, err := io.Copy(stream, os.Stdin); err != nil {
+			errs <- serrors.WrapStr("sending", err)
+			return
+		}
+		if cw, ok := stream.(interface{ CloseWrite() error }); ok {
+			if err := cw.CloseWrite(); err != nil {
+				errs <- serrors.WrapStr("closing stream", err)
+				return
+			}
+		}
+		errs <- nil
+	}()
+	for pending := 2; pending > 0; pending-- {
+		select {
+		case err := <-errs:
+			if err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return nil
+}
+
+// ncNextBackoff returns the time to wait after a failed read, given the time
+// that was waited after the previous consecutive failure.
+func ncNextBackoff(prev time.Duration) time.Duration {
+	const (
+		minBackoff = 10 * time.Millisecond
+		maxBackoff = time.Second
+	)
+	switch {
+	case prev < minBackoff:
+		return minBackoff
+	case 2*prev > maxBackoff:
+		return maxBackoff
+	default:
+		return 2 * prev
+	}
+}
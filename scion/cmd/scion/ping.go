@@ -21,6 +21,7 @@ import (
 	"math"
 	"net"
 	"os"
+	"sort"
 	"syscall"
 	"time"
 
@@ -81,12 +82,15 @@ func newPing(pather CommandPather) *cobra.Command {
 		refresh     bool
 		healthyOnly bool
 		sequence    string
+		policy      string
 		size        uint
 		pktSize     uint
 		timeout     time.Duration
 		tracer      string
 		epic        bool
 		format      string
+		allPaths    bool
+		maxPaths    int
 	}
 
 	var cmd = &cobra.Command{
@@ -102,10 +106,17 @@ and reports back the statistics.
 When the \--healthy-only option is set, ping first determines healthy paths through probing and
 chooses amongst them.
 
+When the \--all-paths option is set, ping sends SCMP echo packets on all available paths
+concurrently and reports the statistics per path. The packets on the different paths are
+interleaved, such that each path is probed once per interval. The \--max-paths option limits
+the number of paths that are used, and implies \--all-paths. The payload size is the same on
+all paths.
+
 If no reply packet is received at all, ping will exit with code 1.
 On other errors, ping will exit with code 2.
 
-%s`, app.SequenceHelp),
+%s
+%s`, app.SequenceHelp, app.PolicyHelp),
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			remote, err := snet.ParseUDPAddr(args[0])
@@ -127,6 +138,11 @@ On other errors, ping will exit with code 2.
 
 			cmd.SilenceUsage = true
 
+			policy, err := loadPolicy(flags.policy)
+			if err != nil {
+				return err
+			}
+
 			if err := envFlags.LoadExternalVars(); err != nil {
 				return err
 			}
@@ -162,6 +178,7 @@ On other errors, ping will exit with code 2.
 				path.WithInteractive(flags.interactive),
 				path.WithRefresh(flags.refresh),
 				path.WithSequence(flags.sequence),
+				path.WithPolicy(policy),
 				path.WithColorScheme(path.DefaultColorScheme(flags.noColor)),
 				path.WithEPIC(flags.epic),
 			}
@@ -172,29 +189,35 @@ On other errors, ping will exit with code 2.
 					Dispatcher: dispatcher,
 				}))
 			}
-			path, err := path.Choose(traceCtx, sd, remote.IA, opts...)
-			if err != nil {
-				return err
-			}
-
-			// If the EPIC flag is set, use the EPIC-HP path type
-			if flags.epic {
-				switch s := path.Dataplane().(type) {
-				case snetpath.SCION:
-					epicPath, err := snetpath.NewEPICDataplanePath(s, path.Metadata().EpicAuths)
-					if err != nil {
-						return err
-					}
-					remote.Path = epicPath
-				case snetpath.Empty:
-					remote.Path = s
-				default:
-					return serrors.New("unsupported path type")
+			var paths []snet.Path
+			multi := flags.allPaths || flags.maxPaths > 0
+			if multi {
+				if flags.interactive {
+					return serrors.New("interactive mode is not supported for multiple paths")
+				}
+				if paths, err = path.ChooseAll(traceCtx, sd, remote.IA, opts...); err != nil {
+					return err
+				}
+				if flags.maxPaths > 0 && len(paths) > flags.maxPaths {
+					paths = paths[:flags.maxPaths]
 				}
 			} else {
-				remote.Path = path.Dataplane()
+				path, err := path.Choose(traceCtx, sd, remote.IA, opts...)
+				if err != nil {
+					return err
+				}
+				paths = []snet.Path{path}
+			}
+			remotes := make([]*snet.UDPAddr, 0, len(paths))
+			for _, path := range paths {
+				r := remote.Copy()
+				if r.Path, err = dataplanePath(path, flags.epic); err != nil {
+					return err
+				}
+				r.NextHop = path.UnderlayNextHop()
+				remotes = append(remotes, r)
 			}
-			remote.NextHop = path.UnderlayNextHop()
+			remote = remotes[0]
 
 			// Resolve local IP based on underlay next hop
 			if localIP == nil {
@@ -208,7 +231,15 @@ On other errors, ping will exit with code 2.
 				}
 				printf("Resolved local address:\n  %s\n", localIP)
 			}
-			printf("Using path:\n  %s\n\n", path)
+			if !multi {
+				printf("Using path:\n  %s\n\n", paths[0])
+			} else {
+				printf("Using paths:\n")
+				for i, path := range paths {
+					printf("  [%2d] %s\n", i, path)
+				}
+				printf("\n")
+			}
 			span.SetTag("src.host", localIP)
 			local := &snet.UDPAddr{
 				IA:   info.IA,
@@ -216,10 +247,19 @@ On other errors, ping will exit with code 2.
 			}
 			pldSize := int(flags.size)
 
+			// The payload size is shared by all paths. It is chosen such that
+			// the packets on none of the paths exceed the requested packet size
+			// or the MTU.
 			if cmd.Flags().Changed("packet-size") {
-				overhead, err := ping.Size(local, remote, 0)
-				if err != nil {
-					return err
+				var overhead int
+				for _, r := range remotes {
+					o, err := ping.Size(local, r, 0)
+					if err != nil {
+						return err
+					}
+					if o > overhead {
+						overhead = o
+					}
 				}
 				if overhead > int(flags.pktSize) {
 					return serrors.New(
@@ -229,10 +269,16 @@ On other errors, ping will exit with code 2.
 				pldSize = int(flags.pktSize - uint(overhead))
 			}
 			if flags.maxMTU {
-				mtu := int(path.Metadata().MTU)
-				pldSize, err = calcMaxPldSize(local, remote, mtu)
-				if err != nil {
-					return err
+				pldSize = math.MaxInt
+				for i, r := range remotes {
+					mtu := int(paths[i].Metadata().MTU)
+					max, err := calcMaxPldSize(local, r, mtu)
+					if err != nil {
+						return err
+					}
+					if max < pldSize {
+						pldSize = max
+					}
 				}
 			}
 			pktSize, err := ping.Size(local, remote, pldSize)
@@ -247,6 +293,22 @@ On other errors, ping will exit with code 2.
 			if count == 0 {
 				count = math.MaxUint16
 			}
+			if multi {
+				return runPingMulti(ctx, multiPingConfig{
+					dispatcher: reliable.NewDispatcher(dispatcher),
+					local:      local,
+					remote:     remote,
+					remotes:    remotes,
+					paths:      paths,
+					count:      count,
+					interval:   flags.interval,
+					timeout:    flags.timeout,
+					pldSize:    pldSize,
+					format:     flags.format,
+					printf:     printf,
+				})
+			}
+			path := paths[0]
 
 			seq, err := pathpol.GetSequence(path)
 			if err != nil {
@@ -340,6 +402,7 @@ On other errors, ping will exit with code 2.
 	cmd.Flags().BoolVar(&flags.noColor, "no-color", false, "disable colored output")
 	cmd.Flags().DurationVar(&flags.timeout, "timeout", time.Second, "timeout per packet")
 	cmd.Flags().StringVar(&flags.sequence, "sequence", "", app.SequenceUsage)
+	cmd.Flags().StringVar(&flags.policy, "policy", "", app.PolicyUsage)
 	cmd.Flags().BoolVar(&flags.healthyOnly, "healthy-only", false, "only use healthy paths")
 	cmd.Flags().BoolVar(&flags.refresh, "refresh", false, "set refresh flag for path request")
 	cmd.Flags().DurationVar(&flags.interval, "interval", time.Second, "time between packets")
@@ -363,9 +426,29 @@ SCMP echo header and payload are equal to the MTU of the path. This flag overrid
 	cmd.Flags().BoolVar(&flags.epic, "epic", false, "Enable EPIC for path probing.")
 	cmd.Flags().StringVar(&flags.format, "format", "human",
 		"Specify the output format (human|json|yaml)")
+	cmd.Flags().BoolVar(&flags.allPaths, "all-paths", false,
+		"ping all available paths concurrently and report statistics per path")
+	cmd.Flags().IntVar(&flags.maxPaths, "max-paths", 0,
+		"maximum number of paths to ping concurrently; implies --all-paths")
 	return cmd
 }
 
+// dataplanePath returns the dataplane path that is used to send packets on
+// path. If epic is set, the EPIC-HP path type is used.
+func dataplanePath(path snet.Path, epic bool) (snet.DataplanePath, error) {
+	if !epic {
+		return path.Dataplane(), nil
+	}
+	switch s := path.Dataplane().(type) {
+	case snetpath.SCION:
+		return snetpath.NewEPICDataplanePath(s, path.Metadata().EpicAuths)
+	case snetpath.Empty:
+		return s, nil
+	default:
+		return nil, serrors.New("unsupported path type")
+	}
+}
+
 func calcMaxPldSize(local, remote *snet.UDPAddr, mtu int) (int, error) {
 	overhead, err := ping.Size(local, remote, 0)
 	if err != nil {
@@ -416,3 +499,186 @@ func calculateStats(s ping.Stats, replies []PingUpdate, run time.Duration) Stats
 	stats.MdevRTT = durationMillis(mdevRTT)
 	return stats
 }
+
+// MultiResult is the result of pinging a remote host over multiple paths.
+type MultiResult struct {
+	PayloadSize int          `json:"payload_size" yaml:"payload_size"`
+	Paths       []PathResult `json:"paths" yaml:"paths"`
+}
+
+// PathResult is the result of pinging a remote host over a single path in
+// multi-path mode.
+type PathResult struct {
+	Path            Path         `json:"path" yaml:"path"`
+	ScionPacketSize int          `json:"scion_packet_size" yaml:"scion_packet_size"`
+	Replies         []PingUpdate `json:"replies" yaml:"replies"`
+	Statistics      PathStats    `json:"statistics" yaml:"statistics"`
+}
+
+// PathStats extends the ping statistics with RTT percentiles and jitter.
+type PathStats struct {
+	Stats  `yaml:",inline"`
+	P50RTT durationMillis `json:"p50_rtt" yaml:"p50_rtt"`
+	P90RTT durationMillis `json:"p90_rtt" yaml:"p90_rtt"`
+	P99RTT durationMillis `json:"p99_rtt" yaml:"p99_rtt"`
+	// Jitter is the mean absolute difference between the RTTs of consecutive
+	// replies.
+	Jitter durationMillis `json:"jitter" yaml:"jitter"`
+}
+
+type multiPingConfig struct {
+	dispatcher reliable.Dispatcher
+	local      *snet.UDPAddr
+	remote     *snet.UDPAddr
+	remotes    []*snet.UDPAddr
+	paths      []snet.Path
+	count      uint16
+	interval   time.Duration
+	timeout    time.Duration
+	pldSize    int
+	format     string
+	printf     func(format string, ctx ...interface{})
+}
+
+// runPingMulti pings the remote over all paths in cfg concurrently and reports
+// the statistics per path.
+func runPingMulti(ctx context.Context, cfg multiPingConfig) error {
+	printf := cfg.printf
+	res := MultiResult{
+		PayloadSize: cfg.pldSize,
+		Paths:       make([]PathResult, 0, len(cfg.paths)),
+	}
+	for i, p := range cfg.paths {
+		seq, err := pathpol.GetSequence(p)
+		if err != nil {
+			return serrors.New("get sequence from used path")
+		}
+		pktSize, err := ping.Size(cfg.local, cfg.remotes[i], cfg.pldSize)
+		if err != nil {
+			return err
+		}
+		res.Paths = append(res.Paths, PathResult{
+			Path: Path{
+				Fingerprint: snet.Fingerprint(p).String(),
+				Hops:        getHops(p),
+				Sequence:    seq,
+				LocalIP:     cfg.local.Host.IP,
+				NextHop:     p.UnderlayNextHop().String(),
+			},
+			ScionPacketSize: pktSize,
+		})
+	}
+
+	start := time.Now()
+	stats, err := ping.RunMulti(ctx, ping.MultiConfig{
+		Dispatcher:  cfg.dispatcher,
+		Attempts:    cfg.count,
+		Interval:    cfg.interval,
+		Timeout:     cfg.timeout,
+		Local:       cfg.local,
+		Remotes:     cfg.remotes,
+		PayloadSize: cfg.pldSize,
+		ErrHandler: func(err error) {
+			fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
+		},
+		UpdateHandler: func(update ping.MultiUpdate) {
+			var additional string
+			switch update.State {
+			case ping.AfterTimeout:
+				additional = " state=After timeout"
+			case ping.OutOfOrder:
+				additional = " state=Out of Order"
+			case ping.Duplicate:
+				additional = " state=Duplicate"
+			}
+			r := &res.Paths[update.Path]
+			r.Replies = append(r.Replies, PingUpdate{
+				Size:     update.Size,
+				Source:   update.Source.String(),
+				Sequence: update.Sequence,
+				RTT:      durationMillis(update.RTT),
+				State:    update.State.String(),
+			})
+			printf("%d bytes from %s,%s: path=%d scmp_seq=%d time=%s%s\n",
+				update.Size, update.Source.IA, update.Source.Host, update.Path,
+				update.Sequence, durationMillis(update.RTT), additional)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	run := time.Since(start)
+	var received int
+	for i := range res.Paths {
+		res.Paths[i].Statistics = calculatePathStats(stats[i], res.Paths[i].Replies, run)
+		received += stats[i].Received
+	}
+
+	switch cfg.format {
+	case "human":
+		printf("\n--- %s,%s statistics ---\n", cfg.remote.IA, cfg.remote.Host.IP)
+		printf("%4s %6s %6s %5s %9s %9s %9s %9s %9s %9s %9s\n", "PATH", "SENT", "RECV",
+			"LOSS", "MIN", "AVG", "MAX", "P50", "P90", "P99", "JITTER")
+		for i, r := range res.Paths {
+			s := r.Statistics
+			printf("%4d %6d %6d %4d%% %9.3f %9.3f %9.3f %9.3f %9.3f %9.3f %9.3f\n",
+				i, s.Sent, s.Received, s.Loss, s.MinRTT.Millis(), s.AvgRTT.Millis(),
+				s.MaxRTT.Millis(), s.P50RTT.Millis(), s.P90RTT.Millis(), s.P99RTT.Millis(),
+				s.Jitter.Millis(),
+			)
+		}
+		printf("rtt in ms, time %v\n", durationMillis(run))
+		if received == 0 {
+			return app.WithExitCode(serrors.New("no reply packet received"), 1)
+		}
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(res)
+	case "yaml":
+		enc := yaml.NewEncoder(os.Stdout)
+		return enc.Encode(res)
+	}
+	return nil
+}
+
+// calculatePathStats computes the PathStats from the ping stats and updates of
+// a single path.
+func calculatePathStats(s ping.Stats, replies []PingUpdate, run time.Duration) PathStats {
+	stats := PathStats{Stats: calculateStats(s, replies, run)}
+	if len(replies) == 0 {
+		return stats
+	}
+	var jitter durationMillis
+	rtts := make([]durationMillis, 0, len(replies))
+	for i, r := range replies {
+		rtts = append(rtts, r.RTT)
+		if i == 0 {
+			continue
+		}
+		diff := r.RTT - replies[i-1].RTT
+		if diff < 0 {
+			diff = -diff
+		}
+		jitter += diff
+	}
+	if len(replies) > 1 {
+		stats.Jitter = jitter / durationMillis(len(replies)-1)
+	}
+	sort.Slice(rtts, func(i, j int) bool { return rtts[i] < rtts[j] })
+	stats.P50RTT = percentile(rtts, 50)
+	stats.P90RTT = percentile(rtts, 90)
+	stats.P99RTT = percentile(rtts, 99)
+	return stats
+}
+
+// percentile returns the p-th percentile of the sorted RTTs using the nearest
+// rank method.
+func percentile(sorted []durationMillis, p int) durationMillis {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,262 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/scionproto/scion/pkg/addr"
+	"github.com/scionproto/scion/pkg/daemon"
+	"github.com/scionproto/scion/pkg/log"
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/snet"
+	"github.com/scionproto/scion/private/app"
+	"github.com/scionproto/scion/private/app/command"
+	"github.com/scionproto/scion/private/app/flag"
+	"github.com/scionproto/scion/private/app/path"
+	"github.com/scionproto/scion/private/path/pathpol"
+)
+
+// PolicyCheckResult is the result of checking a path policy against the
+// paths to a destination.
+type PolicyCheckResult struct {
+	Destination addr.IA           `json:"destination" yaml:"destination"`
+	Policy      string            `json:"policy" yaml:"policy"`
+	Paths       []PolicyCheckPath `json:"paths" yaml:"paths"`
+}
+
+// PolicyCheckPath is a path that was checked against the policy.
+type PolicyCheckPath struct {
+	FullPath snet.Path `json:"-" yaml:"-"`
+	Path     `yaml:",inline"`
+	MTU      uint16 `json:"mtu" yaml:"mtu"`
+	Kept     bool   `json:"kept" yaml:"kept"`
+	// Cost is the cost of the path if the policy defines an ordering.
+	Cost *float64 `json:"cost,omitempty" yaml:"cost,omitempty"`
+	// Rejection explains why the policy rejected the path.
+	Rejection *pathpol.Rejection `json:"rejection,omitempty" yaml:"rejection,omitempty"`
+}
+
+func newPolicy(pather CommandPather) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "policy",
+		Short: "Inspect path policies",
+	}
+	joined := command.Join(pather, cmd)
+	cmd.AddCommand(
+		newPolicyCheck(joined),
+	)
+	return cmd
+}
+
+func newPolicyCheck(pather CommandPather) *cobra.Command {
+	var envFlags flag.SCIONEnvironment
+	var flags struct {
+		timeout  time.Duration
+		refresh  bool
+		noColor  bool
+		format   string
+		logLevel string
+	}
+
+	var cmd = &cobra.Command{
+		Use:   "check [flags] <policy-file> <dst-isd-as>",
+		Short: "Check which paths to a SCION AS a path policy keeps",
+		Example: fmt.Sprintf(`  %[1]s check policy.yml 1-ff00:0:110
+  %[1]s check policy.json 1-ff00:0:110 --format json`, pather.CommandPath()),
+		Long: fmt.Sprintf(`'check' evaluates a path policy file against the paths that are currently
+available to the destination ISD-AS, and shows which paths the policy keeps.
+For every rejected path, the policy attribute that rejected it is shown,
+together with the reason, e.g., the ACL entry that denied an interface. The
+attributes are evaluated in the order local_isd_ases, remote_isd_ases, acl,
+sequence, max_latency, min_bandwidth, min_mtu, exclude_link_types, geofence,
+and options, and a path is attributed to the first attribute that rejects it.
+If the policy defines an ordering, the paths are listed by ascending cost.
+
+If the policy rejects all paths, check exits with code 1.
+On other errors, check exits with code 2.
+
+%s`, app.PolicyHelp),
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policy, err := pathpol.LoadPolicyFromFile(args[0])
+			if err != nil {
+				return err
+			}
+			dst, err := addr.ParseIA(args[1])
+			if err != nil {
+				return serrors.WrapStr("invalid destination ISD-AS", err)
+			}
+			if err := app.SetupLog(flags.logLevel); err != nil {
+				return serrors.WrapStr("setting up logging", err)
+			}
+			printf, err := getPrintf(flags.format, cmd.OutOrStdout())
+			if err != nil {
+				return serrors.WrapStr("get formatting", err)
+			}
+
+			cmd.SilenceUsage = true
+
+			if err := envFlags.LoadExternalVars(); err != nil {
+				return err
+			}
+			daemonAddr := envFlags.Daemon()
+			log.Debug("Resolved SCION environment flags", "daemon", daemonAddr)
+
+			ctx, cancelF := context.WithTimeout(context.Background(), flags.timeout)
+			defer cancelF()
+			sd, err := daemon.NewService(daemonAddr).Connect(ctx)
+			if err != nil {
+				return serrors.WrapStr("connecting to SCION Daemon", err)
+			}
+			defer sd.Close()
+			paths, err := sd.Paths(ctx, dst, 0, daemon.PathReqFlags{Refresh: flags.refresh})
+			if err != nil {
+				return serrors.WrapStr("retrieving paths from the SCION Daemon", err)
+			}
+			if policy.Ordering != nil {
+				paths = policy.Ordering.Rank(paths)
+			} else {
+				path.Sort(paths)
+			}
+
+			res, err := checkPolicy(policy, paths)
+			if err != nil {
+				return err
+			}
+			res.Destination = dst
+			res.Policy = args[0]
+
+			var kept int
+			for _, p := range res.Paths {
+				if p.Kept {
+					kept++
+				}
+			}
+			switch flags.format {
+			case "human":
+				printf("Policy %s keeps %d of %d paths to %s\n",
+					res.Policy, kept, len(res.Paths), res.Destination)
+				res.Human(cmd.OutOrStdout(), !flags.noColor)
+			case "json":
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				enc.SetEscapeHTML(false)
+				if err := enc.Encode(res); err != nil {
+					return err
+				}
+			case "yaml":
+				enc := yaml.NewEncoder(os.Stdout)
+				if err := enc.Encode(res); err != nil {
+					return err
+				}
+			default:
+				return serrors.New("output format not supported", "format", flags.format)
+			}
+			if kept == 0 {
+				return app.WithExitCode(serrors.New("policy rejects all paths"), 1)
+			}
+			return nil
+		},
+	}
+
+	envFlags.Register(cmd.Flags())
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", 5*time.Second, "Timeout")
+	cmd.Flags().BoolVarP(&flags.refresh, "refresh", "r", false,
+		"Set refresh flag for SCION Deamon path request")
+	cmd.Flags().BoolVar(&flags.noColor, "no-color", false, "disable colored output")
+	cmd.Flags().StringVar(&flags.format, "format", "human",
+		"Specify the output format (human|json|yaml)")
+	cmd.Flags().StringVar(&flags.logLevel, "log.level", "", app.LogLevelUsage)
+	return cmd
+}
+
+// checkPolicy evaluates the policy and records for each path whether it is
+// kept, or why it was rejected.
+func checkPolicy(policy *pathpol.Policy, paths []snet.Path) (*PolicyCheckResult, error) {
+	res := &PolicyCheckResult{Paths: make([]PolicyCheckPath, 0, len(paths))}
+	for _, e := range policy.Evaluate(paths) {
+		seq, err := pathpol.GetSequence(e.Path)
+		if err != nil {
+			return nil, serrors.New("get sequence from path")
+		}
+		var nextHop string
+		if nh := e.Path.UnderlayNextHop(); nh != nil {
+			nextHop = nh.String()
+		}
+		var cost *float64
+		if c, ok := policy.Cost(e.Path); ok {
+			cost = &c
+		}
+		res.Paths = append(res.Paths, PolicyCheckPath{
+			FullPath: e.Path,
+			Path: Path{
+				Fingerprint: e.Fingerprint,
+				Hops:        getHops(e.Path),
+				Sequence:    seq,
+				NextHop:     nextHop,
+			},
+			MTU:       e.Path.Metadata().MTU,
+			Kept:      e.Accepted,
+			Cost:      cost,
+			Rejection: e.Rejection,
+		})
+	}
+	return res, nil
+}
+
+// Human writes the checked paths in human readable form to the writer.
+func (r PolicyCheckResult) Human(w io.Writer, colored bool) {
+	cs := path.DefaultColorScheme(!colored)
+	for i, p := range r.Paths {
+		status := cs.Good.Sprint("kept")
+		if !p.Kept {
+			status = cs.Bad.Sprint(describeRejection(p.Rejection))
+		}
+		entries := cs.KeyValues(
+			"Hops", cs.Path(p.FullPath),
+			"MTU", fmt.Sprint(p.MTU),
+		)
+		if p.Cost != nil {
+			entries = append(entries, cs.KeyValue("Cost", fmt.Sprintf("%.2f", *p.Cost)))
+		}
+		entries = append(entries, cs.KeyValue("Status", status))
+		fmt.Fprintf(w, "[%2d] %s\n", i, strings.Join(entries, " "))
+	}
+}
+
+// describeRejection describes the rejection in a single line. For options, the
+// rejections of the individual options are included.
+func describeRejection(r *pathpol.Rejection) string {
+	desc := fmt.Sprintf("rejected by %s: %s", r.Attribute, r.Reason)
+	if len(r.Options) == 0 {
+		return desc
+	}
+	options := make([]string, 0, len(r.Options))
+	for _, o := range r.Options {
+		options = append(options,
+			fmt.Sprintf("weight %d %s", o.Weight, describeRejection(o.Rejection)))
+	}
+	return fmt.Sprintf("%s (%s)", desc, strings.Join(options, "; "))
+}
@@ -18,7 +18,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -44,6 +46,10 @@ func newShowpaths(pather CommandPather) *cobra.Command {
 		noColor  bool
 		tracer   string
 		format   string
+		watch    bool
+		interval time.Duration
+		compare  string
+		policy   string
 	}
 
 	var cmd = &cobra.Command{
@@ -64,16 +70,30 @@ By default, the paths are probed. Paths served from the SCION Deamon's might not
 forward traffic successfully (e.g. if a network link went down, or there is a black
 hole on the path). To disable path probing, set the appropriate flag.
 
+When the \--compare option is set, the paths are compared against a result that was
+previously saved with \--format json. The paths that appeared, disappeared, or changed
+their status or metadata are reported. If there are any changes, showpaths exits with
+code 1.
+
+When the \--watch option is set, showpaths refreshes the paths periodically until it
+is interrupted, and reports the changes compared to the previous refresh. With
+\--format json, the changes are written as a stream of JSON lines, one per change.
+If \--compare is set as well, the first refresh is compared against the saved result.
+
 If no alive path is discovered, json output is not enabled, and probing is not
 disabled, showpaths will exit with the code 1.
 On other errors, showpaths will exit with code 2.
 
-%s`, app.SequenceHelp),
+%s
+%s`, app.SequenceHelp, app.PolicyHelp),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			dst, err := addr.ParseIA(args[0])
 			if err != nil {
 				return serrors.WrapStr("invalid destination ISD-AS", err)
 			}
+			if flags.watch && flags.interval <= 0 {
+				return serrors.New("interval must be positive", "interval", flags.interval)
+			}
 			if err := app.SetupLog(flags.logLevel); err != nil {
 				return serrors.WrapStr("setting up logging", err)
 			}
@@ -93,6 +113,10 @@ On other errors, showpaths will exit with code 2.
 
 			cmd.SilenceUsage = true
 
+			if flags.cfg.Policy, err = loadPolicy(flags.policy); err != nil {
+				return err
+			}
+
 			if err := envFlags.LoadExternalVars(); err != nil {
 				return err
 			}
@@ -110,12 +134,40 @@ On other errors, showpaths will exit with code 2.
 			span.SetTag("dst.isd_as", dst)
 			defer span.Finish()
 
+			var baseline *showpaths.Result
+			if flags.compare != "" {
+				if baseline, err = showpaths.LoadResult(flags.compare); err != nil {
+					return err
+				}
+				if baseline.Destination != dst {
+					return serrors.New("destination of saved result does not match",
+						"expected", dst, "actual", baseline.Destination)
+				}
+			}
+			if flags.watch {
+				ctx := app.WithSignal(traceCtx, os.Interrupt, syscall.SIGTERM)
+				return watchPaths(ctx, dst, baseline, watchConfig{
+					cfg:      flags.cfg,
+					timeout:  flags.timeout,
+					interval: flags.interval,
+					extended: flags.extended,
+					colored:  !flags.noColor,
+					format:   flags.format,
+					out:      cmd.OutOrStdout(),
+					errOut:   cmd.ErrOrStderr(),
+				})
+			}
+
 			ctx, cancel := context.WithTimeout(traceCtx, flags.timeout)
 			defer cancel()
 			res, err := showpaths.Run(ctx, dst, flags.cfg)
 			if err != nil {
 				return err
 			}
+			if baseline != nil {
+				return comparePaths(baseline, res, flags.compare, flags.format,
+					!flags.noColor, cmd.OutOrStdout())
+			}
 
 			switch flags.format {
 			case "human":
@@ -149,6 +201,7 @@ On other errors, showpaths will exit with code 2.
 	envFlags.Register(cmd.Flags())
 	cmd.Flags().DurationVar(&flags.timeout, "timeout", 5*time.Second, "Timeout")
 	cmd.Flags().StringVar(&flags.cfg.Sequence, "sequence", "", app.SequenceUsage)
+	cmd.Flags().StringVar(&flags.policy, "policy", "", app.PolicyUsage)
 	cmd.Flags().IntVarP(&flags.cfg.MaxPaths, "maxpaths", "m", 10,
 		"Maximum number of paths that are displayed")
 	cmd.Flags().BoolVarP(&flags.extended, "extended", "e", false,
@@ -165,9 +218,133 @@ On other errors, showpaths will exit with code 2.
 	cmd.Flags().StringVar(&flags.logLevel, "log.level", "", app.LogLevelUsage)
 	cmd.Flags().StringVar(&flags.tracer, "tracing.agent", "", "Tracing agent address")
 	cmd.Flags().BoolVar(&flags.cfg.Epic, "epic", false, "Enable EPIC.")
+	cmd.Flags().BoolVar(&flags.watch, "watch", false,
+		"Refresh the paths periodically and report the changes")
+	cmd.Flags().DurationVar(&flags.interval, "interval", 10*time.Second,
+		"Time between two refreshes in watch mode")
+	cmd.Flags().StringVar(&flags.compare, "compare", "",
+		"Compare the paths against a result previously saved in json format")
 	err := cmd.Flags().MarkDeprecated("json", "json flag is deprecated, use format flag")
 	if err != nil {
 		panic(err)
 	}
 	return cmd
 }
+
+// comparePaths reports the changes between the saved and the current result.
+func comparePaths(saved, res *showpaths.Result, file, format string, colored bool,
+	w io.Writer) error {
+
+	changes := showpaths.Diff(saved, res)
+	switch format {
+	case "human":
+		if len(changes) == 0 {
+			fmt.Fprintf(w, "No changes to paths in %s\n", file)
+			return nil
+		}
+		fmt.Fprintf(w, "Changes to paths in %s\n", file)
+		showpaths.HumanChanges(w, changes, colored)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(changes); err != nil {
+			return err
+		}
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		if err := enc.Encode(changes); err != nil {
+			return err
+		}
+	default:
+		return serrors.New("output format not supported", "format", format)
+	}
+	if len(changes) != 0 {
+		return app.WithExitCode(serrors.New("paths changed"), 1)
+	}
+	return nil
+}
+
+type watchConfig struct {
+	cfg      showpaths.Config
+	timeout  time.Duration
+	interval time.Duration
+	extended bool
+	colored  bool
+	format   string
+	out      io.Writer
+	errOut   io.Writer
+}
+
+// PathChangeEvent is a single entry of the change stream in watch mode.
+type PathChangeEvent struct {
+	Time             time.Time `json:"time" yaml:"time"`
+	Destination      addr.IA   `json:"destination" yaml:"destination"`
+	showpaths.Change `yaml:",inline"`
+}
+
+// watchPaths refreshes the paths periodically and reports the changes until
+// the context is canceled. If prev is nil, the first result is reported in
+// full.
+func watchPaths(ctx context.Context, dst addr.IA, prev *showpaths.Result,
+	cfg watchConfig) error {
+
+	var enc interface{ Encode(interface{}) error }
+	switch cfg.format {
+	case "human":
+	case "json":
+		jsonEnc := json.NewEncoder(cfg.out)
+		jsonEnc.SetEscapeHTML(false)
+		enc = jsonEnc
+	case "yaml":
+		enc = yaml.NewEncoder(cfg.out)
+	default:
+		return serrors.New("output format not supported", "format", cfg.format)
+	}
+	if prev == nil && enc != nil {
+		// Report all paths of the first result as appeared.
+		prev = &showpaths.Result{Destination: dst}
+	}
+
+	ticker := time.NewTicker(cfg.interval)
+	defer ticker.Stop()
+	for {
+		runCtx, cancel := context.WithTimeout(ctx, cfg.timeout)
+		res, err := showpaths.Run(runCtx, dst, cfg.cfg)
+		cancel()
+		now := time.Now()
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			fmt.Fprintf(cfg.errOut, "ERROR: %s\n", err)
+		case enc != nil:
+			for _, c := range showpaths.Diff(prev, res) {
+				event := PathChangeEvent{Time: now, Destination: dst, Change: c}
+				if err := enc.Encode(event); err != nil {
+					return err
+				}
+			}
+			prev = res
+		case prev == nil:
+			fmt.Fprintf(cfg.out, "%s: available paths to %s\n",
+				now.Format(time.RFC3339), res.Destination)
+			if len(res.Paths) != 0 {
+				res.Human(cfg.out, cfg.extended, cfg.colored)
+			}
+			prev = res
+		default:
+			if changes := showpaths.Diff(prev, res); len(changes) != 0 {
+				fmt.Fprintf(cfg.out, "%s: paths to %s changed\n",
+					now.Format(time.RFC3339), res.Destination)
+				showpaths.HumanChanges(cfg.out, changes, cfg.colored)
+			}
+			prev = res
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net"
 	"os"
 	"strings"
@@ -54,6 +55,55 @@ type HopInfo struct {
 	IP             string           `json:"ip" yaml:"ip"`
 	IA             addr.IA          `json:"isd_as" yaml:"isd_as"`
 	RoundTripTimes []durationMillis `json:"round_trip_times" yaml:"round_trip_times"`
+	// Announced contains the metadata announced in the beacons for this hop.
+	Announced HopMetadata `json:"announced" yaml:"announced"`
+}
+
+// HopMetadata is the metadata announced in the beacons for a hop.
+type HopMetadata struct {
+	// Latency is the announced one-way latency from the first interface of the
+	// path to this hop.
+	Latency *durationMillis `json:"latency,omitempty" yaml:"latency,omitempty"`
+	// RTTDeviation is the difference between the minimum measured RTT and the
+	// RTT corresponding to the announced latency. A negative value indicates
+	// that the announced latency is too high.
+	RTTDeviation *durationMillis `json:"rtt_deviation,omitempty" yaml:"rtt_deviation,omitempty"`
+	LinkType     string          `json:"link_type" yaml:"link_type"`
+	Geo          *Geo            `json:"geo,omitempty" yaml:"geo,omitempty"`
+	Note         string          `json:"note,omitempty" yaml:"note,omitempty"`
+}
+
+// Geo is the announced position of a router.
+type Geo struct {
+	Latitude  float32 `json:"latitude" yaml:"latitude"`
+	Longitude float32 `json:"longitude" yaml:"longitude"`
+	Address   string  `json:"address,omitempty" yaml:"address,omitempty"`
+}
+
+// ResultMtr is the result of the traceroute in mtr mode.
+type ResultMtr struct {
+	Path   Path     `json:"path" yaml:"path"`
+	Cycles int      `json:"cycles" yaml:"cycles"`
+	Hops   []MtrHop `json:"hops" yaml:"hops"`
+}
+
+// MtrHop contains the statistics of a hop over all cycles.
+type MtrHop struct {
+	InterfaceID uint16 `json:"interface_id" yaml:"interface_id"`
+	// IP address of the router responding to the traceroute requests.
+	IP        string         `json:"ip" yaml:"ip"`
+	IA        addr.IA        `json:"isd_as" yaml:"isd_as"`
+	Sent      int            `json:"sent" yaml:"sent"`
+	Received  int            `json:"received" yaml:"received"`
+	Loss      int            `json:"packet_loss" yaml:"packet_loss"`
+	Last      durationMillis `json:"last_rtt" yaml:"last_rtt"`
+	Best      durationMillis `json:"best_rtt" yaml:"best_rtt"`
+	Avg       durationMillis `json:"avg_rtt" yaml:"avg_rtt"`
+	Worst     durationMillis `json:"worst_rtt" yaml:"worst_rtt"`
+	StdDev    durationMillis `json:"stddev_rtt" yaml:"stddev_rtt"`
+	Announced HopMetadata    `json:"announced" yaml:"announced"`
+
+	rtts []time.Duration
 }
 
 func newTraceroute(pather CommandPather) *cobra.Command {
@@ -65,10 +115,14 @@ func newTraceroute(pather CommandPather) *cobra.Command {
 		noColor     bool
 		refresh     bool
 		sequence    string
+		policy      string
 		timeout     time.Duration
 		tracer      string
 		epic        bool
 		format      string
+		mtr         bool
+		cycles      int
+		interval    time.Duration
 	}
 
 	var cmd = &cobra.Command{
@@ -79,9 +133,22 @@ func newTraceroute(pather CommandPather) *cobra.Command {
 		Long: fmt.Sprintf(`'traceroute' traces the SCION path to a remote AS using
 SCMP traceroute packets.
 
+Every hop is annotated with the metadata that the ASes on the path announced in
+their beacons, i.e., the link type, the position of the router, the note of the AS,
+and the latency from the first interface of the path. The measured RTT is compared
+with the RTT corresponding to the announced latency; a hop where the measured RTT is
+lower than the announced one is marked with '!', which hints at a misconfigured
+latency announcement.
+
+When the \--mtr option is set, traceroute traces the path repeatedly, once per
+\--interval, and reports the packet loss and RTT statistics per hop, similar to mtr.
+It runs for the number of cycles given by \--cycles, or until it is interrupted if
+\--cycles is not set.
+
 If any packet is dropped, traceroute will exit with code 1.
 On other errors, traceroute will exit with code 2.
-%s`, app.SequenceHelp),
+%s
+%s`, app.SequenceHelp, app.PolicyHelp),
 
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -103,6 +170,11 @@ On other errors, traceroute will exit with code 2.
 			}
 			cmd.SilenceUsage = true
 
+			policy, err := loadPolicy(flags.policy)
+			if err != nil {
+				return err
+			}
+
 			if err := envFlags.LoadExternalVars(); err != nil {
 				return err
 			}
@@ -136,6 +208,7 @@ On other errors, traceroute will exit with code 2.
 				path.WithInteractive(flags.interactive),
 				path.WithRefresh(flags.refresh),
 				path.WithSequence(flags.sequence),
+				path.WithPolicy(policy),
 				path.WithColorScheme(path.DefaultColorScheme(flags.noColor)),
 				path.WithEPIC(flags.epic),
 			)
@@ -181,32 +254,41 @@ On other errors, traceroute will exit with code 2.
 				Host: &net.UDPAddr{IP: localIP},
 			}
 			ctx = app.WithSignal(traceCtx, os.Interrupt, syscall.SIGTERM)
-			var stats traceroute.Stats
-			var updates []traceroute.Update
+			meta := path.Metadata()
 			cfg := traceroute.Config{
 				Dispatcher:   reliable.NewDispatcher(dispatcher),
 				Remote:       remote,
-				MTU:          path.Metadata().MTU,
+				MTU:          meta.MTU,
 				Local:        local,
 				PathEntry:    path,
 				Timeout:      flags.timeout,
 				ProbesPerHop: 3,
 				ErrHandler:   func(err error) { fmt.Fprintf(os.Stderr, "ERROR: %s\n", err) },
-				UpdateHandler: func(u traceroute.Update) {
-					updates = append(updates, u)
-					printf("%d %s %s\n", u.Index, fmtRemote(u.Remote, u.Interface),
-						fmtRTTs(u.RTTs, flags.timeout))
-				},
-				EPIC: flags.epic,
+				EPIC:         flags.epic,
 			}
-			stats, err = traceroute.Run(ctx, cfg)
+			if flags.mtr || cmd.Flags().Changed("cycles") {
+				return runMtr(ctx, cfg, res.Path, flags.cycles, flags.interval, flags.format,
+					printf)
+			}
+			var updates []traceroute.Update
+			cfg.UpdateHandler = func(u traceroute.Update) {
+				updates = append(updates, u)
+				printf("%d %s %s%s\n", u.Index, fmtRemote(u.Remote, u.Interface),
+					fmtRTTs(u.RTTs, flags.timeout),
+					fmtHopMetadata(traceroute.MetadataForHop(meta, u.Index), u.RTTs,
+						flags.timeout))
+			}
+			stats, err := traceroute.Run(ctx, cfg)
 			if err != nil {
 				return err
 			}
 			res.Hops = make([]HopInfo, 0, len(updates))
 			hops := getHops(path)
 			for i, update := range updates {
-				res.Hops = append(res.Hops, getHopInfo(update, hops[i]))
+				info := getHopInfo(update, hops[i])
+				info.Announced = getHopMetadata(traceroute.MetadataForHop(meta, update.Index),
+					update.RTTs, flags.timeout)
+				res.Hops = append(res.Hops, info)
 			}
 
 			switch flags.format {
@@ -233,9 +315,16 @@ On other errors, traceroute will exit with code 2.
 	cmd.Flags().BoolVar(&flags.noColor, "no-color", false, "disable colored output")
 	cmd.Flags().DurationVar(&flags.timeout, "timeout", time.Second, "timeout per packet")
 	cmd.Flags().StringVar(&flags.sequence, "sequence", "", app.SequenceUsage)
+	cmd.Flags().StringVar(&flags.policy, "policy", "", app.PolicyUsage)
 	cmd.Flags().StringVar(&flags.logLevel, "log.level", "", app.LogLevelUsage)
 	cmd.Flags().StringVar(&flags.tracer, "tracing.agent", "", "Tracing agent address")
 	cmd.Flags().BoolVar(&flags.epic, "epic", false, "Enable EPIC.")
+	cmd.Flags().BoolVar(&flags.mtr, "mtr", false,
+		"trace the path repeatedly and report statistics per hop")
+	cmd.Flags().IntVar(&flags.cycles, "cycles", 0,
+		"number of cycles in mtr mode; run until interrupted if zero (implies --mtr)")
+	cmd.Flags().DurationVar(&flags.interval, "interval", time.Second,
+		"time between the start of two cycles in mtr mode")
 	cmd.Flags().StringVar(&flags.format, "format", "human",
 		"Specify the output format (human|json|yaml)")
 	return cmd
@@ -275,3 +364,192 @@ func getHopInfo(u traceroute.Update, hop Hop) HopInfo {
 		RoundTripTimes: RTTs,
 	}
 }
+
+// minRTT returns the minimum of the RTTs that did not time out.
+func minRTT(rtts []time.Duration, timeout time.Duration) (time.Duration, bool) {
+	var min time.Duration
+	found := false
+	for _, rtt := range rtts {
+		if rtt > timeout {
+			continue
+		}
+		if !found || rtt < min {
+			min, found = rtt, true
+		}
+	}
+	return min, found
+}
+
+func getHopMetadata(m traceroute.HopMetadata, rtts []time.Duration,
+	timeout time.Duration) HopMetadata {
+
+	res := HopMetadata{
+		LinkType: m.LinkType.String(),
+		Note:     m.Note,
+	}
+	if m.Latency >= 0 {
+		latency := durationMillis(m.Latency)
+		res.Latency = &latency
+		if min, ok := minRTT(rtts, timeout); ok {
+			deviation := durationMillis(min - m.AnnouncedRTT())
+			res.RTTDeviation = &deviation
+		}
+	}
+	if m.Geo != (snet.GeoCoordinates{}) {
+		res.Geo = &Geo{
+			Latitude:  m.Geo.Latitude,
+			Longitude: m.Geo.Longitude,
+			Address:   m.Geo.Address,
+		}
+	}
+	return res
+}
+
+// fmtHopMetadata formats the announced metadata of a hop for the human output.
+// The result is empty if nothing was announced.
+func fmtHopMetadata(m traceroute.HopMetadata, rtts []time.Duration,
+	timeout time.Duration) string {
+
+	var parts []string
+	if m.Latency >= 0 {
+		latency := fmt.Sprintf("announced=%s", durationMillis(m.AnnouncedRTT()))
+		if min, ok := minRTT(rtts, timeout); ok && min < m.AnnouncedRTT() {
+			latency += "!"
+		}
+		parts = append(parts, latency)
+	}
+	if m.LinkType != snet.LinkTypeUnset {
+		parts = append(parts, fmt.Sprintf("link=%s", m.LinkType))
+	}
+	if m.Geo != (snet.GeoCoordinates{}) {
+		geo := fmt.Sprintf("geo=%g,%g", m.Geo.Latitude, m.Geo.Longitude)
+		if m.Geo.Address != "" {
+			geo += fmt.Sprintf(" (%q)", m.Geo.Address)
+		}
+		parts = append(parts, geo)
+	}
+	if m.Note != "" {
+		parts = append(parts, fmt.Sprintf("note=%q", m.Note))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(parts, " ") + "]"
+}
+
+// runMtr traces the path repeatedly and reports the statistics per hop.
+func runMtr(ctx context.Context, cfg traceroute.Config, path Path, cycles int,
+	interval time.Duration, format string, printf func(string, ...interface{})) error {
+
+	meta := cfg.PathEntry.Metadata()
+	var res ResultMtr
+	res.Path = path
+	cfg.ProbesPerHop = 1
+	cfg.Cycles = cycles
+	if cycles == 0 {
+		cfg.Cycles = -1
+	}
+	cfg.CycleInterval = interval
+	cfg.UpdateHandler = func(u traceroute.Update) {
+		for len(res.Hops) <= u.Index {
+			var hop MtrHop
+			if i := len(res.Hops); i < len(path.Hops) {
+				hop.IA, hop.InterfaceID = path.Hops[i].IA, uint16(path.Hops[i].ID)
+			}
+			res.Hops = append(res.Hops, hop)
+		}
+		hop := &res.Hops[u.Index]
+		if u.Remote != (snet.SCIONAddress{}) {
+			hop.IA = u.Remote.IA
+			hop.IP = u.Remote.Host.IP().String()
+			hop.InterfaceID = uint16(u.Interface)
+		}
+		for _, rtt := range u.RTTs {
+			hop.Sent++
+			if rtt > cfg.Timeout {
+				continue
+			}
+			hop.Received++
+			hop.Last = durationMillis(rtt)
+			hop.rtts = append(hop.rtts, rtt)
+		}
+		res.Cycles = u.Cycle + 1
+		printf("cycle=%d %d %s %s\n", u.Cycle, u.Index, fmtRemote(u.Remote, u.Interface),
+			fmtRTTs(u.RTTs, cfg.Timeout))
+	}
+	stats, err := traceroute.Run(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	for i := range res.Hops {
+		hop := &res.Hops[i]
+		calculateMtrStats(hop)
+		hop.Announced = getHopMetadata(traceroute.MetadataForHop(meta, i), hop.rtts,
+			cfg.Timeout)
+	}
+
+	switch format {
+	case "human":
+		printf("\n--- %s mtr statistics, %d cycles ---\n", cfg.Remote.IA, res.Cycles)
+		printf("%3s %-32s %5s %5s %5s %9s %9s %9s %9s %9s %9s\n", "HOP", "HOST", "SENT",
+			"RECV", "LOSS", "LAST", "AVG", "BEST", "WORST", "STDDEV", "ANNOUNCED")
+		for i, hop := range res.Hops {
+			host := fmt.Sprintf("%s,%s IfID=%d", hop.IA, hop.IP, hop.InterfaceID)
+			announced := "-"
+			if l := hop.Announced.Latency; l != nil {
+				announced = fmt.Sprintf("%.3f", (2 * *l).Millis())
+				if d := hop.Announced.RTTDeviation; d != nil && *d < 0 {
+					announced += "!"
+				}
+			}
+			printf("%3d %-32s %5d %5d %4d%% %9.3f %9.3f %9.3f %9.3f %9.3f %9s\n",
+				i, host, hop.Sent, hop.Received, hop.Loss, hop.Last.Millis(),
+				hop.Avg.Millis(), hop.Best.Millis(), hop.Worst.Millis(), hop.StdDev.Millis(),
+				announced,
+			)
+		}
+		printf("rtt in ms\n")
+		if stats.Sent != stats.Recv {
+			return app.WithExitCode(serrors.New("packets were lost"), 1)
+		}
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(res)
+	case "yaml":
+		enc := yaml.NewEncoder(os.Stdout)
+		return enc.Encode(res)
+	}
+	return nil
+}
+
+// calculateMtrStats computes the loss and RTT statistics of the hop.
+func calculateMtrStats(hop *MtrHop) {
+	if hop.Sent != 0 {
+		hop.Loss = 100 - hop.Received*100/hop.Sent
+	}
+	if len(hop.rtts) == 0 {
+		return
+	}
+	best, worst := hop.rtts[0], hop.rtts[0]
+	var sum time.Duration
+	for _, rtt := range hop.rtts {
+		if rtt < best {
+			best = rtt
+		}
+		if rtt > worst {
+			worst = rtt
+		}
+		sum += rtt
+	}
+	avg := sum / time.Duration(len(hop.rtts))
+	var sd float64
+	for _, rtt := range hop.rtts {
+		sd += math.Pow(float64(rtt-avg), 2)
+	}
+	hop.Best = durationMillis(best)
+	hop.Worst = durationMillis(worst)
+	hop.Avg = durationMillis(avg)
+	hop.StdDev = durationMillis(math.Sqrt(sd / float64(len(hop.rtts))))
+}
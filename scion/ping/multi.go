@@ -0,0 +1,214 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ping
+
+import (
+	"context"
+	"encoding/binary"
+	"math/rand"
+	"time"
+
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/pkg/snet"
+	"github.com/scionproto/scion/pkg/sock/reliable"
+)
+
+// multiPldSize is the minimum payload size of echo requests in multi-path
+// mode. The payload contains the request time (8 bytes) and the index of the
+// path (2 bytes).
+const multiPldSize = 10
+
+// MultiConfig configures a ping run over multiple paths.
+type MultiConfig struct {
+	Dispatcher reliable.Dispatcher
+	Local      *snet.UDPAddr
+	// Remotes contains the address of the remote host once per path. The
+	// addresses only differ in the path and the next hop.
+	Remotes []*snet.UDPAddr
+
+	// Attempts is the number of pings to send per path.
+	Attempts uint16
+	// Interval is the time between sending two pings on the same path. The
+	// pings on the different paths are interleaved evenly within the interval.
+	Interval time.Duration
+	// Timeout is the time until a ping is considered to have timed out.
+	Timeout time.Duration
+	// PayloadSize is the size of the SCMP echo payload.
+	PayloadSize int
+
+	// ErrHandler is invoked for every error that does not cause pinging to
+	// abort. Execution time must be small, as it is run synchronously.
+	ErrHandler func(err error)
+	// Update handler is invoked for every ping reply. Execution time must be
+	// small, as it is run synchronously.
+	UpdateHandler func(MultiUpdate)
+}
+
+// MultiUpdate contains intermediary information about a received echo reply
+// in multi-path mode.
+type MultiUpdate struct {
+	Update
+	// Path is the index of the path in MultiConfig.Remotes.
+	Path int
+}
+
+// RunMulti pings the remote host over multiple paths concurrently. This blocks
+// until the configured number of attempts is sent on every path, or the
+// context is canceled. The returned stats are in the order of the paths in
+// MultiConfig.Remotes.
+func RunMulti(ctx context.Context, cfg MultiConfig) ([]Stats, error) {
+	if len(cfg.Remotes) == 0 {
+		return nil, serrors.New("no remote")
+	}
+	if cfg.Interval < time.Millisecond {
+		return nil, serrors.New("interval below millisecond")
+	}
+	if len(cfg.Remotes) > int(^uint16(0)) {
+		return nil, serrors.New("too many paths", "paths", len(cfg.Remotes))
+	}
+
+	id := rand.Uint64()
+	replies := make(chan reply, 10)
+	conn, local, err := register(ctx, cfg.Dispatcher, cfg.Local, uint16(id), replies)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.PayloadSize < multiPldSize {
+		cfg.PayloadSize = multiPldSize
+	}
+	p := multiPinger{
+		pinger: pinger{
+			attempts: cfg.Attempts,
+			// The pings on the different paths are interleaved, hence the
+			// interval between two consecutive pings is shorter.
+			interval:   cfg.Interval / time.Duration(len(cfg.Remotes)),
+			timeout:    cfg.Timeout,
+			pldSize:    cfg.PayloadSize,
+			pld:        make([]byte, cfg.PayloadSize),
+			id:         id,
+			conn:       conn,
+			local:      local,
+			replies:    replies,
+			errHandler: cfg.ErrHandler,
+		},
+		remotes:       cfg.Remotes,
+		paths:         make([]pathState, len(cfg.Remotes)),
+		updateHandler: cfg.UpdateHandler,
+	}
+	return p.Ping(ctx)
+}
+
+// pathState is the state of pinging a single path.
+type pathState struct {
+	sentSequence     int
+	receivedSequence int
+	stats            Stats
+}
+
+type multiPinger struct {
+	pinger
+	remotes       []*snet.UDPAddr
+	paths         []pathState
+	updateHandler func(MultiUpdate)
+}
+
+func (p *multiPinger) Ping(ctx context.Context) ([]Stats, error) {
+	for i := range p.paths {
+		p.paths[i].sentSequence, p.paths[i].receivedSequence = -1, -1
+	}
+	err := p.run(ctx, int(p.attempts)*len(p.remotes),
+		func(i int) error {
+			path := i % len(p.remotes)
+			if err := p.sendPath(path); err != nil {
+				return serrors.WithCtx(err, "path", path)
+			}
+			return nil
+		},
+		p.receivePath,
+	)
+	return p.stats(), err
+}
+
+func (p *multiPinger) sendPath(path int) error {
+	state := &p.paths[path]
+	sequence := state.sentSequence + 1
+
+	binary.BigEndian.PutUint64(p.pld, uint64(time.Now().UnixNano()))
+	binary.BigEndian.PutUint16(p.pld[8:], uint16(path))
+	remote := p.remotes[path]
+	pkt, err := pack(p.local, remote, snet.SCMPEchoRequest{
+		Identifier: uint16(p.id),
+		SeqNumber:  uint16(sequence),
+		Payload:    p.pld,
+	})
+	if err != nil {
+		return err
+	}
+	if err := p.conn.WriteTo(pkt, nextHop(p.local, remote)); err != nil {
+		return err
+	}
+
+	state.sentSequence = sequence
+	state.stats.Sent++
+	return nil
+}
+
+func (p *multiPinger) receivePath(reply reply) error {
+	if len(reply.Reply.Payload) < multiPldSize {
+		return serrors.New("payload too short", "length", len(reply.Reply.Payload))
+	}
+	path := int(binary.BigEndian.Uint16(reply.Reply.Payload[8:]))
+	if path >= len(p.paths) {
+		return serrors.New("unknown path", "path", path)
+	}
+	state := &p.paths[path]
+	rtt := reply.Received.Sub(time.Unix(0, int64(binary.BigEndian.Uint64(reply.Reply.Payload)))).
+		Round(time.Microsecond)
+	var s State
+	switch {
+	case rtt > p.timeout:
+		s = AfterTimeout
+	case int(reply.Reply.SeqNumber) < state.receivedSequence:
+		s = OutOfOrder
+	case int(reply.Reply.SeqNumber) == state.receivedSequence:
+		s = Duplicate
+	default:
+		s = Success
+		state.receivedSequence = int(reply.Reply.SeqNumber)
+	}
+	state.stats.Received++
+	if p.updateHandler != nil {
+		p.updateHandler(MultiUpdate{
+			Update: Update{
+				RTT:      rtt,
+				Sequence: int(reply.Reply.SeqNumber),
+				Size:     reply.Size,
+				Source:   reply.Source,
+				State:    s,
+			},
+			Path: path,
+		})
+	}
+	return nil
+}
+
+func (p *multiPinger) stats() []Stats {
+	stats := make([]Stats, 0, len(p.paths))
+	for _, state := range p.paths {
+		stats = append(stats, state.stats)
+	}
+	return stats
+}
@@ -104,22 +104,11 @@ func Run(ctx context.Context, cfg Config) (Stats, error) {
 
 	id := rand.Uint64()
 	replies := make(chan reply, 10)
-
-	svc := snet.DefaultPacketDispatcherService{
-		Dispatcher: cfg.Dispatcher,
-		SCMPHandler: scmpHandler{
-			id:      uint16(id),
-			replies: replies,
-		},
-	}
-	conn, port, err := svc.Register(ctx, cfg.Local.IA, cfg.Local.Host, addr.SvcNone)
+	conn, local, err := register(ctx, cfg.Dispatcher, cfg.Local, uint16(id), replies)
 	if err != nil {
 		return Stats{}, err
 	}
 
-	local := cfg.Local.Copy()
-	local.Host.Port = int(port)
-
 	// we need to have at least 8 bytes to store the request time in the
 	// payload.
 	if cfg.PayloadSize < 8 {
@@ -141,6 +130,28 @@ func Run(ctx context.Context, cfg Config) (Stats, error) {
 	return p.Ping(ctx, cfg.Remote)
 }
 
+// register registers a connection for sending echo requests with the
+// identifier id. The replies are passed to the replies channel. The returned
+// address contains the port that was assigned to the connection.
+func register(ctx context.Context, dispatcher reliable.Dispatcher, local *snet.UDPAddr,
+	id uint16, replies chan<- reply) (snet.PacketConn, *snet.UDPAddr, error) {
+
+	svc := snet.DefaultPacketDispatcherService{
+		Dispatcher: dispatcher,
+		SCMPHandler: scmpHandler{
+			id:      id,
+			replies: replies,
+		},
+	}
+	conn, port, err := svc.Register(ctx, local.IA, local.Host, addr.SvcNone)
+	if err != nil {
+		return nil, nil, err
+	}
+	local = local.Copy()
+	local.Host.Port = int(port)
+	return conn, local, nil
+}
+
 type pinger struct {
 	attempts uint16
 	interval time.Duration
@@ -165,13 +176,40 @@ type pinger struct {
 
 func (p *pinger) Ping(ctx context.Context, remote *snet.UDPAddr) (Stats, error) {
 	p.sentSequence, p.receivedSequence = -1, -1
-	send := time.NewTicker(p.interval)
-	defer send.Stop()
+	err := p.run(ctx, int(p.attempts),
+		func(int) error {
+			return p.send(remote)
+		},
+		func(reply reply) error {
+			p.receive(reply)
+			return nil
+		},
+	)
+	return p.stats, err
+}
+
+// run sends total echo requests with the send function, waiting for the
+// interval after each request, and passes the echo replies to the receive
+// function. Errors returned by receive are reported to the error handler. run
+// blocks until total replies were received, the timeout after sending the last
+// request expired, or the context is canceled.
+func (p *pinger) run(ctx context.Context, total int, send func(i int) error,
+	receive func(reply) error) error {
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	errSend := make(chan error, 1)
+	sendDone := make(chan struct{})
+	// The statistics are read by the caller after returning, so the sending
+	// goroutine must have stopped.
+	defer func() {
+		cancel()
+		<-sendDone
+	}()
 
 	go func() {
 		defer log.HandlePanic()
@@ -180,13 +218,14 @@ func (p *pinger) Ping(ctx context.Context, remote *snet.UDPAddr) (Stats, error)
 
 	go func() {
 		defer log.HandlePanic()
-		for i := uint16(0); i < p.attempts; i++ {
-			if err := p.send(remote); err != nil {
+		defer close(sendDone)
+		for i := 0; i < total; i++ {
+			if err := send(i); err != nil {
 				errSend <- serrors.WrapStr("sending", err)
 				return
 			}
 			select {
-			case <-send.C:
+			case <-ticker.C:
 			case <-ctx.Done():
 				return
 			}
@@ -194,12 +233,12 @@ func (p *pinger) Ping(ctx context.Context, remote *snet.UDPAddr) (Stats, error)
 		time.AfterFunc(p.timeout, cancel)
 	}()
 
-	for i := uint16(0); i < p.attempts; i++ {
+	for i := 0; i < total; i++ {
 		select {
 		case <-ctx.Done():
-			return p.stats, nil
+			return nil
 		case err := <-errSend:
-			return p.stats, err
+			return err
 		case reply := <-p.replies:
 			if reply.Error != nil {
 				if p.errHandler != nil {
@@ -207,10 +246,12 @@ func (p *pinger) Ping(ctx context.Context, remote *snet.UDPAddr) (Stats, error)
 				}
 				continue
 			}
-			p.receive(reply)
+			if err := receive(reply); err != nil && p.errHandler != nil {
+				p.errHandler(err)
+			}
 		}
 	}
-	return p.stats, nil
+	return nil
 }
 
 func (p *pinger) send(remote *snet.UDPAddr) error {
@@ -225,16 +266,7 @@ func (p *pinger) send(remote *snet.UDPAddr) error {
 	if err != nil {
 		return err
 	}
-	nextHop := remote.NextHop
-	if nextHop == nil && p.local.IA.Equal(remote.IA) {
-		nextHop = &net.UDPAddr{
-			IP:   remote.Host.IP,
-			Port: underlay.EndhostPort,
-			Zone: remote.Host.Zone,
-		}
-
-	}
-	if err := p.conn.WriteTo(pkt, nextHop); err != nil {
+	if err := p.conn.WriteTo(pkt, nextHop(p.local, remote)); err != nil {
 		return err
 	}
 
@@ -243,6 +275,18 @@ func (p *pinger) send(remote *snet.UDPAddr) error {
 	return nil
 }
 
+// nextHop returns the underlay address to which packets for remote are sent.
+func nextHop(local, remote *snet.UDPAddr) *net.UDPAddr {
+	if remote.NextHop == nil && local.IA.Equal(remote.IA) {
+		return &net.UDPAddr{
+			IP:   remote.Host.IP,
+			Port: underlay.EndhostPort,
+			Zone: remote.Host.Zone,
+		}
+	}
+	return remote.NextHop
+}
+
 func (p *pinger) receive(reply reply) {
 	rtt := reply.Received.Sub(time.Unix(0, int64(binary.BigEndian.Uint64(reply.Reply.Payload)))).
 		Round(time.Microsecond)
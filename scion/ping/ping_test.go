@@ -0,0 +1,166 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ping
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/pkg/snet"
+	"github.com/scionproto/scion/pkg/snet/path"
+)
+
+func TestPing(t *testing.T) {
+	replies := make(chan reply, 10)
+	var updates []Update
+	p := pinger{
+		attempts:      3,
+		interval:      time.Millisecond,
+		timeout:       100 * time.Millisecond,
+		pld:           make([]byte, 8),
+		id:            42,
+		conn:          &echoConn{replies: replies},
+		local:         testAddr(t, "127.0.0.1"),
+		replies:       replies,
+		updateHandler: func(u Update) { updates = append(updates, u) },
+	}
+	stats, err := p.Ping(context.Background(), testAddr(t, "127.0.0.2"))
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Sent: 3, Received: 3}, stats)
+	require.Len(t, updates, 3)
+	for i, u := range updates {
+		assert.Equal(t, i, u.Sequence)
+		assert.Equal(t, Success, u.State)
+	}
+}
+
+func TestMultiPing(t *testing.T) {
+	replies := make(chan reply, 10)
+	var updates []MultiUpdate
+	var errs []error
+	p := multiPinger{
+		pinger: pinger{
+			attempts:   3,
+			interval:   time.Millisecond,
+			timeout:    100 * time.Millisecond,
+			pld:        make([]byte, multiPldSize),
+			id:         42,
+			local:      testAddr(t, "127.0.0.1"),
+			replies:    replies,
+			errHandler: func(err error) { errs = append(errs, err) },
+		},
+		remotes: []*snet.UDPAddr{
+			testAddr(t, "127.0.0.2"),
+			testAddr(t, "127.0.0.2"),
+			testAddr(t, "127.0.0.2"),
+		},
+		paths:         make([]pathState, 3),
+		updateHandler: func(u MultiUpdate) { updates = append(updates, u) },
+	}
+	p.conn = &echoConn{
+		replies: replies,
+		// Drop all pings on the second path, and answer the pings on the
+		// third path twice.
+		drop:      map[int]bool{1: true},
+		duplicate: map[int]bool{2: true},
+	}
+	stats, err := p.Ping(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Stats{
+		{Sent: 3, Received: 3},
+		{Sent: 3, Received: 0},
+		{Sent: 3, Received: 6},
+	}, stats)
+	assert.Empty(t, errs)
+
+	states := make(map[int][]State)
+	for _, u := range updates {
+		states[u.Path] = append(states[u.Path], u.State)
+	}
+	assert.Equal(t, map[int][]State{
+		0: {Success, Success, Success},
+		2: {Success, Duplicate, Success, Duplicate, Success, Duplicate},
+	}, states)
+}
+
+func TestMultiPingUnknownPath(t *testing.T) {
+	p := multiPinger{paths: make([]pathState, 1)}
+	pld := make([]byte, multiPldSize)
+	binary.BigEndian.PutUint16(pld[8:], 1)
+	err := p.receivePath(reply{Reply: snet.SCMPEchoReply{Payload: pld}})
+	assert.Error(t, err)
+
+	err = p.receivePath(reply{Reply: snet.SCMPEchoReply{Payload: pld[:8]}})
+	assert.Error(t, err)
+}
+
+func testAddr(t *testing.T, ip string) *snet.UDPAddr {
+	return &snet.UDPAddr{
+		IA:   xtest.MustParseIA("1-ff00:0:110"),
+		Host: &net.UDPAddr{IP: net.ParseIP(ip)},
+		Path: path.Empty{},
+	}
+}
+
+// echoConn answers every echo request with an echo reply on the replies
+// channel. In multi-path mode, the requests of the paths in drop are not
+// answered, and the requests of the paths in duplicate are answered twice.
+type echoConn struct {
+	replies   chan<- reply
+	drop      map[int]bool
+	duplicate map[int]bool
+}
+
+func (c *echoConn) WriteTo(pkt *snet.Packet, _ *net.UDPAddr) error {
+	req := pkt.Payload.(snet.SCMPEchoRequest)
+	n := 1
+	if len(req.Payload) >= multiPldSize {
+		path := int(binary.BigEndian.Uint16(req.Payload[8:]))
+		switch {
+		case c.drop[path]:
+			return nil
+		case c.duplicate[path]:
+			n = 2
+		}
+	}
+	for i := 0; i < n; i++ {
+		c.replies <- reply{
+			Received: time.Now(),
+			Reply: snet.SCMPEchoReply{
+				Identifier: req.Identifier,
+				SeqNumber:  req.SeqNumber,
+				Payload:    append([]byte(nil), req.Payload...),
+			},
+		}
+	}
+	return nil
+}
+
+func (c *echoConn) ReadFrom(*snet.Packet, *net.UDPAddr) error {
+	time.Sleep(time.Millisecond)
+	return nil
+}
+
+func (c *echoConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *echoConn) SetWriteDeadline(time.Time) error { return nil }
+func (c *echoConn) SetDeadline(time.Time) error      { return nil }
+func (c *echoConn) Close() error                     { return nil }
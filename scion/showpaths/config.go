@@ -16,6 +16,8 @@ package showpaths
 
 import (
 	"net"
+
+	"github.com/scionproto/scion/private/path/pathpol"
 )
 
 // DefaultMaxPaths is the maximum number of paths that are displayed by default.
@@ -38,6 +40,9 @@ type Config struct {
 	// Sequence is a string of space separated Hop Predicates that is used for
 	// filtering.
 	Sequence string
+	// Policy is a path policy that is used for filtering. If it is nil, the
+	// paths are not filtered by a policy.
+	Policy *pathpol.Policy
 	// Dispatcher is the path to the dispatcher socket. Leaving this empty uses
 	// the default dispatcher socket value.
 	Dispatcher string
@@ -0,0 +1,152 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package showpaths
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/scionproto/scion/pkg/private/serrors"
+	"github.com/scionproto/scion/private/app/path"
+	"github.com/scionproto/scion/private/app/path/pathprobe"
+)
+
+// ChangeType is the type of a path change.
+type ChangeType string
+
+const (
+	// ChangeAppeared indicates that the path was added.
+	ChangeAppeared ChangeType = "appeared"
+	// ChangeDisappeared indicates that the path was removed.
+	ChangeDisappeared ChangeType = "disappeared"
+	// ChangeStatus indicates that the probed status of the path changed.
+	ChangeStatus ChangeType = "status_changed"
+	// ChangeMetadata indicates that the MTU or the next hop of the path
+	// changed.
+	ChangeMetadata ChangeType = "metadata_changed"
+)
+
+// Change describes how a path differs between two results.
+type Change struct {
+	Type        ChangeType `json:"type" yaml:"type"`
+	Fingerprint string     `json:"fingerprint" yaml:"fingerprint"`
+	Sequence    string     `json:"sequence" yaml:"sequence"`
+	Hops        []Hop      `json:"hops" yaml:"hops"`
+	// Status is the status of the path in the new result, or in the old
+	// result if the path disappeared.
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
+	// OldStatus is the status of the path in the old result, if the status
+	// changed.
+	OldStatus string `json:"old_status,omitempty" yaml:"old_status,omitempty"`
+	// Details describes the changed metadata.
+	Details []string `json:"details,omitempty" yaml:"details,omitempty"`
+}
+
+// Diff returns the changes of the paths from the old to the new result. Paths
+// are identified by their fingerprint. The changes of the paths in the new
+// result come first, in the order of the new result, followed by the paths
+// that disappeared, in the order of the old result.
+func Diff(old, new *Result) []Change {
+	oldPaths := make(map[string]Path, len(old.Paths))
+	for _, p := range old.Paths {
+		oldPaths[p.Fingerprint] = p
+	}
+	newPaths := make(map[string]struct{}, len(new.Paths))
+	var changes []Change
+	for _, p := range new.Paths {
+		newPaths[p.Fingerprint] = struct{}{}
+		prev, ok := oldPaths[p.Fingerprint]
+		if !ok {
+			changes = append(changes, newChange(ChangeAppeared, p))
+			continue
+		}
+		if !strings.EqualFold(prev.Status, p.Status) {
+			c := newChange(ChangeStatus, p)
+			c.OldStatus = prev.Status
+			changes = append(changes, c)
+		}
+		var details []string
+		if prev.MTU != p.MTU {
+			details = append(details, fmt.Sprintf("mtu %d -> %d", prev.MTU, p.MTU))
+		}
+		if prev.NextHop != p.NextHop {
+			details = append(details,
+				fmt.Sprintf("next_hop %s -> %s", prev.NextHop, p.NextHop))
+		}
+		if len(details) > 0 {
+			c := newChange(ChangeMetadata, p)
+			c.Details = details
+			changes = append(changes, c)
+		}
+	}
+	for _, p := range old.Paths {
+		if _, ok := newPaths[p.Fingerprint]; !ok {
+			changes = append(changes, newChange(ChangeDisappeared, p))
+		}
+	}
+	return changes
+}
+
+func newChange(t ChangeType, p Path) Change {
+	return Change{
+		Type:        t,
+		Fingerprint: p.Fingerprint,
+		Sequence:    p.Sequence,
+		Hops:        p.Hops,
+		Status:      p.Status,
+	}
+}
+
+// HumanChanges writes the changes in human readable form to the writer.
+func HumanChanges(w io.Writer, changes []Change, colored bool) {
+	cs := path.DefaultColorScheme(!colored)
+	for _, c := range changes {
+		var line string
+		switch c.Type {
+		case ChangeAppeared:
+			line = cs.Good.Sprintf("+ %s %s", c.Fingerprint, c.Sequence)
+		case ChangeDisappeared:
+			line = cs.Bad.Sprintf("- %s %s", c.Fingerprint, c.Sequence)
+		case ChangeStatus:
+			statusColor := cs.Bad
+			if strings.EqualFold(c.Status, string(pathprobe.StatusAlive)) {
+				statusColor = cs.Good
+			}
+			line = fmt.Sprintf("~ %s %s %s", c.Fingerprint, c.Sequence,
+				cs.KeyValue("Status", fmt.Sprintf("%s -> %s", c.OldStatus,
+					statusColor.Sprint(c.Status))))
+		case ChangeMetadata:
+			line = fmt.Sprintf("~ %s %s %s", c.Fingerprint, c.Sequence,
+				strings.Join(c.Details, ", "))
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// LoadResult loads a result that was previously written in JSON format.
+func LoadResult(file string) (*Result, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, serrors.WrapStr("reading result", err, "file", file)
+	}
+	var res Result
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return nil, serrors.WrapStr("parsing result", err, "file", file)
+	}
+	return &res, nil
+}
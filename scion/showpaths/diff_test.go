@@ -0,0 +1,131 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package showpaths_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/scion/showpaths"
+)
+
+func TestDiff(t *testing.T) {
+	path := func(fingerprint, status string, mtu uint16, nextHop string) showpaths.Path {
+		return showpaths.Path{
+			Fingerprint: fingerprint,
+			Sequence:    "seq-" + fingerprint,
+			Status:      status,
+			MTU:         mtu,
+			NextHop:     nextHop,
+		}
+	}
+	change := func(t showpaths.ChangeType, p showpaths.Path) showpaths.Change {
+		return showpaths.Change{
+			Type:        t,
+			Fingerprint: p.Fingerprint,
+			Sequence:    p.Sequence,
+			Status:      p.Status,
+		}
+	}
+
+	testCases := map[string]struct {
+		Old      []showpaths.Path
+		New      []showpaths.Path
+		Expected []showpaths.Change
+	}{
+		"no changes": {
+			Old: []showpaths.Path{path("a", "alive", 1472, "10.0.0.1:30041")},
+			New: []showpaths.Path{path("a", "Alive", 1472, "10.0.0.1:30041")},
+		},
+		"appeared": {
+			New: []showpaths.Path{path("a", "alive", 1472, "10.0.0.1:30041")},
+			Expected: []showpaths.Change{
+				change(showpaths.ChangeAppeared, path("a", "alive", 1472, "10.0.0.1:30041")),
+			},
+		},
+		"disappeared": {
+			Old: []showpaths.Path{path("a", "alive", 1472, "10.0.0.1:30041")},
+			Expected: []showpaths.Change{
+				change(showpaths.ChangeDisappeared, path("a", "alive", 1472, "10.0.0.1:30041")),
+			},
+		},
+		"status changed": {
+			Old: []showpaths.Path{path("a", "alive", 1472, "10.0.0.1:30041")},
+			New: []showpaths.Path{path("a", "timeout", 1472, "10.0.0.1:30041")},
+			Expected: []showpaths.Change{func() showpaths.Change {
+				c := change(showpaths.ChangeStatus, path("a", "timeout", 1472, "10.0.0.1:30041"))
+				c.OldStatus = "alive"
+				return c
+			}()},
+		},
+		"metadata changed": {
+			Old: []showpaths.Path{path("a", "alive", 1472, "10.0.0.1:30041")},
+			New: []showpaths.Path{path("a", "alive", 1280, "10.0.0.2:30041")},
+			Expected: []showpaths.Change{func() showpaths.Change {
+				c := change(showpaths.ChangeMetadata, path("a", "alive", 1280, "10.0.0.2:30041"))
+				c.Details = []string{
+					"mtu 1472 -> 1280",
+					"next_hop 10.0.0.1:30041 -> 10.0.0.2:30041",
+				}
+				return c
+			}()},
+		},
+		"order of changes": {
+			Old: []showpaths.Path{
+				path("a", "alive", 1472, "10.0.0.1:30041"),
+				path("b", "alive", 1472, "10.0.0.1:30041"),
+				path("c", "alive", 1472, "10.0.0.1:30041"),
+			},
+			New: []showpaths.Path{
+				path("d", "alive", 1472, "10.0.0.1:30041"),
+				path("b", "alive", 1472, "10.0.0.1:30041"),
+			},
+			Expected: []showpaths.Change{
+				change(showpaths.ChangeAppeared, path("d", "alive", 1472, "10.0.0.1:30041")),
+				change(showpaths.ChangeDisappeared, path("a", "alive", 1472, "10.0.0.1:30041")),
+				change(showpaths.ChangeDisappeared, path("c", "alive", 1472, "10.0.0.1:30041")),
+			},
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			changes := showpaths.Diff(
+				&showpaths.Result{Paths: tc.Old},
+				&showpaths.Result{Paths: tc.New},
+			)
+			assert.Equal(t, tc.Expected, changes)
+		})
+	}
+}
+
+func TestHumanChanges(t *testing.T) {
+	changes := []showpaths.Change{
+		{Type: showpaths.ChangeAppeared, Fingerprint: "a", Sequence: "seq-a"},
+		{Type: showpaths.ChangeDisappeared, Fingerprint: "b", Sequence: "seq-b"},
+		{
+			Type:        showpaths.ChangeMetadata,
+			Fingerprint: "c",
+			Sequence:    "seq-c",
+			Details:     []string{"mtu 1472 -> 1280"},
+		},
+	}
+	var buf bytes.Buffer
+	showpaths.HumanChanges(&buf, changes, false)
+	assert.Equal(t, "+ a seq-a\n- b seq-b\n~ c seq-c mtu 1472 -> 1280\n", buf.String())
+}
@@ -333,6 +333,7 @@ func Run(ctx context.Context, dst addr.IA, cfg Config) (*Result, error) {
 	if err != nil {
 		return nil, err
 	}
+	paths = cfg.Policy.Filter(paths)
 	if cfg.MaxPaths != 0 && len(paths) > cfg.MaxPaths {
 		paths = paths[:cfg.MaxPaths]
 	}
@@ -363,7 +364,9 @@ func Run(ctx context.Context, dst addr.IA, cfg Config) (*Result, error) {
 			return nil, serrors.WrapStr("getting statuses", err)
 		}
 	}
-	path.Sort(paths)
+	if cfg.Policy == nil || cfg.Policy.Ordering == nil {
+		path.Sort(paths)
+	}
 	res := &Result{
 		LocalIA:     localIA,
 		Destination: dst,
@@ -0,0 +1,84 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceroute
+
+import (
+	"time"
+
+	"github.com/scionproto/scion/pkg/snet"
+)
+
+// HopMetadata contains the metadata that the ASes on the path announced in
+// their beacons for a single hop, i.e., for the interface with the same index
+// as the hop.
+type HopMetadata struct {
+	// Latency is the announced one-way latency from the first interface of the
+	// path to the interface of the hop. It is snet.LatencyUnset if any AS on
+	// the way did not announce the latency. The latency between the local
+	// host and the first interface is not announced, hence this is a lower
+	// bound of the actual latency.
+	Latency time.Duration
+	// LinkType is the announced type of the inter-domain link the interface
+	// is attached to.
+	LinkType snet.LinkType
+	// Geo is the announced position of the router of the interface. It is the
+	// zero value if it was not announced.
+	Geo snet.GeoCoordinates
+	// Note is the note of the AS of the interface.
+	Note string
+}
+
+// AnnouncedRTT returns the round trip time corresponding to the announced
+// latency, or snet.LatencyUnset if the latency is not known.
+func (m HopMetadata) AnnouncedRTT() time.Duration {
+	if m.Latency < 0 {
+		return snet.LatencyUnset
+	}
+	return 2 * m.Latency
+}
+
+// MetadataForHop extracts the announced metadata for the hop with the given
+// index from the path metadata. The index corresponds to Update.Index.
+// Metadata that is not available is left unset.
+func MetadataForHop(meta *snet.PathMetadata, index int) HopMetadata {
+	m := HopMetadata{Latency: snet.LatencyUnset}
+	if meta == nil || index < 0 || index >= len(meta.Interfaces) {
+		return m
+	}
+	if index <= len(meta.Latency) {
+		var latency time.Duration
+		for _, l := range meta.Latency[:index] {
+			if l < 0 {
+				latency = snet.LatencyUnset
+				break
+			}
+			latency += l
+		}
+		m.Latency = latency
+	}
+	// Entry i describes the link between interfaces 2*i and 2*i+1.
+	if i := index / 2; i < len(meta.LinkType) {
+		m.LinkType = meta.LinkType[i]
+	}
+	if index < len(meta.Geo) {
+		m.Geo = meta.Geo[index]
+	}
+	// The first AS has a single interface on the path, all other ASes have
+	// two consecutive ones.
+	if i := (index + 1) / 2; i < len(meta.Notes) {
+		m.Note = meta.Notes[i]
+	}
+	return m
+}
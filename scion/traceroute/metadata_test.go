@@ -0,0 +1,150 @@
+// Copyright 2023 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceroute_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/pkg/private/xtest"
+	"github.com/scionproto/scion/pkg/snet"
+	"github.com/scionproto/scion/scion/traceroute"
+)
+
+func TestMetadataForHop(t *testing.T) {
+	ia110 := xtest.MustParseIA("1-ff00:0:110")
+	ia111 := xtest.MustParseIA("1-ff00:0:111")
+	ia112 := xtest.MustParseIA("1-ff00:0:112")
+	geo := func(i int) snet.GeoCoordinates {
+		return snet.GeoCoordinates{Latitude: float32(i), Longitude: float32(i)}
+	}
+	// The path traverses 1-ff00:0:110 (interface 0), 1-ff00:0:111
+	// (interfaces 1 and 2) and 1-ff00:0:112 (interface 3).
+	meta := &snet.PathMetadata{
+		Interfaces: []snet.PathInterface{
+			{IA: ia110, ID: 1},
+			{IA: ia111, ID: 2},
+			{IA: ia111, ID: 3},
+			{IA: ia112, ID: 4},
+		},
+		Latency:  []time.Duration{10 * time.Millisecond, time.Millisecond, 20 * time.Millisecond},
+		LinkType: []snet.LinkType{snet.LinkTypeDirect, snet.LinkTypeOpennet},
+		Geo:      []snet.GeoCoordinates{geo(0), geo(1), geo(2), geo(3)},
+		Notes:    []string{"110", "111", "112"},
+	}
+
+	testCases := map[string]struct {
+		Meta     *snet.PathMetadata
+		Index    int
+		Expected traceroute.HopMetadata
+	}{
+		"first interface": {
+			Meta:  meta,
+			Index: 0,
+			Expected: traceroute.HopMetadata{
+				Latency:  0,
+				LinkType: snet.LinkTypeDirect,
+				Geo:      geo(0),
+				Note:     "110",
+			},
+		},
+		"ingress interface": {
+			Meta:  meta,
+			Index: 1,
+			Expected: traceroute.HopMetadata{
+				Latency:  10 * time.Millisecond,
+				LinkType: snet.LinkTypeDirect,
+				Geo:      geo(1),
+				Note:     "111",
+			},
+		},
+		"egress interface": {
+			Meta:  meta,
+			Index: 2,
+			Expected: traceroute.HopMetadata{
+				Latency:  11 * time.Millisecond,
+				LinkType: snet.LinkTypeOpennet,
+				Geo:      geo(2),
+				Note:     "111",
+			},
+		},
+		"last interface": {
+			Meta:  meta,
+			Index: 3,
+			Expected: traceroute.HopMetadata{
+				Latency:  31 * time.Millisecond,
+				LinkType: snet.LinkTypeOpennet,
+				Geo:      geo(3),
+				Note:     "112",
+			},
+		},
+		"unset latency on the way": {
+			Meta: &snet.PathMetadata{
+				Interfaces: meta.Interfaces,
+				Latency: []time.Duration{
+					10 * time.Millisecond, snet.LatencyUnset, 20 * time.Millisecond,
+				},
+			},
+			Index:    3,
+			Expected: traceroute.HopMetadata{Latency: snet.LatencyUnset},
+		},
+		"unset latency after the hop": {
+			Meta: &snet.PathMetadata{
+				Interfaces: meta.Interfaces,
+				Latency: []time.Duration{
+					10 * time.Millisecond, snet.LatencyUnset, 20 * time.Millisecond,
+				},
+			},
+			Index:    1,
+			Expected: traceroute.HopMetadata{Latency: 10 * time.Millisecond},
+		},
+		"missing metadata": {
+			Meta:     &snet.PathMetadata{Interfaces: meta.Interfaces},
+			Index:    2,
+			Expected: traceroute.HopMetadata{Latency: snet.LatencyUnset},
+		},
+		"nil metadata": {
+			Meta:     nil,
+			Index:    0,
+			Expected: traceroute.HopMetadata{Latency: snet.LatencyUnset},
+		},
+		"index out of range": {
+			Meta:     meta,
+			Index:    4,
+			Expected: traceroute.HopMetadata{Latency: snet.LatencyUnset},
+		},
+		"negative index": {
+			Meta:     meta,
+			Index:    -1,
+			Expected: traceroute.HopMetadata{Latency: snet.LatencyUnset},
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.Expected, traceroute.MetadataForHop(tc.Meta, tc.Index))
+		})
+	}
+}
+
+func TestHopMetadataAnnouncedRTT(t *testing.T) {
+	m := traceroute.HopMetadata{Latency: 10 * time.Millisecond}
+	assert.Equal(t, 20*time.Millisecond, m.AnnouncedRTT())
+	m = traceroute.HopMetadata{Latency: snet.LatencyUnset}
+	assert.Equal(t, snet.LatencyUnset, m.AnnouncedRTT())
+}
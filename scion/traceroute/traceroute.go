@@ -33,6 +33,8 @@ import (
 
 // Update contains the information for a single hop.
 type Update struct {
+	// Cycle is the index of the cycle in which the hop was probed.
+	Cycle int
 	// Index indicates the hop index in the path.
 	Index int
 	// Remote is the remote router.
@@ -67,6 +69,13 @@ type Config struct {
 
 	// ProbesPerHop indicates how many probes should be done per hop.
 	ProbesPerHop int
+	// Cycles indicates how many times the path is traced. If zero, the path
+	// is traced once. If negative, the path is traced until the context is
+	// canceled.
+	Cycles int
+	// CycleInterval is the minimum time between the start of two consecutive
+	// cycles.
+	CycleInterval time.Duration
 	// ErrHandler is invoked for every error that does not cause tracerouting to
 	// abort. Execution time must be small, as it is run synchronously.
 	ErrHandler func(error)
@@ -77,6 +86,8 @@ type Config struct {
 
 type tracerouter struct {
 	probesPerHop  int
+	cycles        int
+	cycleInterval time.Duration
 	timeout       time.Duration
 	conn          snet.PacketConn
 	local         *snet.UDPAddr
@@ -89,6 +100,7 @@ type tracerouter struct {
 	path  snet.Path
 	epic  bool
 	id    uint16
+	cycle int
 	index int
 
 	stats Stats
@@ -113,6 +125,8 @@ func Run(ctx context.Context, cfg Config) (Stats, error) {
 	local.Host.Port = int(port)
 	t := tracerouter{
 		probesPerHop:  cfg.ProbesPerHop,
+		cycles:        cfg.Cycles,
+		cycleInterval: cfg.CycleInterval,
 		timeout:       cfg.Timeout,
 		conn:          conn,
 		local:         local,
@@ -134,10 +148,6 @@ func (t *tracerouter) Traceroute(ctx context.Context) (Stats, error) {
 			"type", common.TypeOf(t.path.Dataplane()))
 	}
 
-	var idxPath scion.Decoded
-	if err := idxPath.DecodeFromBytes(scionPath.Raw); err != nil {
-		return t.stats, serrors.WrapStr("decoding path", err)
-	}
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -145,6 +155,33 @@ func (t *tracerouter) Traceroute(ctx context.Context) (Stats, error) {
 		defer log.HandlePanic()
 		t.drain(ctx)
 	}()
+	cycles := t.cycles
+	if cycles == 0 {
+		cycles = 1
+	}
+	next := time.NewTimer(0)
+	defer next.Stop()
+	for t.cycle = 0; cycles < 0 || t.cycle < cycles; t.cycle++ {
+		select {
+		case <-next.C:
+		case <-ctx.Done():
+			return t.stats, nil
+		}
+		next.Reset(t.cycleInterval)
+		if err := t.traceOnce(ctx, scionPath); err != nil {
+			return t.stats, err
+		}
+	}
+	return t.stats, nil
+}
+
+// traceOnce probes all hops of the path once.
+func (t *tracerouter) traceOnce(ctx context.Context, scionPath path.SCION) error {
+	var idxPath scion.Decoded
+	if err := idxPath.DecodeFromBytes(scionPath.Raw); err != nil {
+		return serrors.WrapStr("decoding path", err)
+	}
+	t.index = 0
 	prevXover := false
 	for i := 0; i < len(idxPath.HopFields); i++ {
 		hf := idxPath.PathMeta.CurrHF
@@ -156,7 +193,7 @@ func (t *tracerouter) Traceroute(ctx context.Context) (Stats, error) {
 		if i != 0 && !prevXover {
 			u, err := t.probeHop(ctx, hf, !info.ConsDir)
 			if err != nil {
-				return t.stats, serrors.WrapStr("probing hop", err, "hop_index", i)
+				return serrors.WrapStr("probing hop", err, "hop_index", i)
 			}
 			if t.updateHandler != nil && !u.empty() {
 				t.updateHandler(u)
@@ -170,7 +207,7 @@ func (t *tracerouter) Traceroute(ctx context.Context) (Stats, error) {
 		if i < len(idxPath.HopFields)-1 && !xover {
 			u, err := t.probeHop(ctx, hf, info.ConsDir)
 			if err != nil {
-				return t.stats, serrors.WrapStr("probing hop", err, "hop_index", i)
+				return serrors.WrapStr("probing hop", err, "hop_index", i)
 			}
 			if t.updateHandler != nil && !u.empty() {
 				t.updateHandler(u)
@@ -178,12 +215,12 @@ func (t *tracerouter) Traceroute(ctx context.Context) (Stats, error) {
 		}
 		if i < len(idxPath.HopFields)-1 {
 			if err := idxPath.IncPath(); err != nil {
-				return t.stats, serrors.WrapStr("incrementing path", err)
+				return serrors.WrapStr("incrementing path", err)
 			}
 		}
 		prevXover = xover
 	}
-	return t.stats, nil
+	return nil
 }
 
 func (t *tracerouter) probeHop(ctx context.Context, hfIdx uint8, egress bool) (Update, error) {
@@ -219,6 +256,7 @@ func (t *tracerouter) probeHop(ctx context.Context, hfIdx uint8, egress bool) (U
 	}
 
 	u := Update{
+		Cycle: t.cycle,
 		Index: t.index,
 		RTTs:  make([]time.Duration, 0, t.probesPerHop),
 	}
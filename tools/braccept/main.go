@@ -127,6 +127,8 @@ func realMain() int {
 		cases.OutgoingOneHop(artifactsDir, hfMAC),
 		cases.SVC(artifactsDir, hfMAC),
 		cases.JumboPacket(artifactsDir, hfMAC),
+		cases.SCMPPacketTooBig(artifactsDir, hfMAC),
+		cases.SCMPPacketTooBigInternal(artifactsDir, hfMAC),
 	}
 
 	if *bfd {